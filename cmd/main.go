@@ -3,9 +3,9 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 	"time"
 
@@ -20,31 +20,29 @@ import (
 	"go.orx.me/apps/hyper-sync/internal/media"
 	"go.orx.me/apps/hyper-sync/internal/post"
 	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
 	"go.orx.me/apps/hyper-sync/internal/wire"
 	"go.orx.me/apps/hyper-sync/internal/worker"
 )
 
-// shutdownCtx is cancelled on SIGINT/SIGTERM; every background worker loop
-// derives from it. Butterfly core exposes no shutdown hook (TeardownFunc is
+// workers owns every background worker loop; its context is cancelled on
+// SIGINT/SIGTERM. Butterfly core exposes no shutdown hook (TeardownFunc is
 // never invoked), so the process owns its own signal handling. Set in main
 // before app.Run invokes the Init funcs.
-var shutdownCtx context.Context
-
-// workerWG tracks running worker loops so main can drain them on shutdown.
-var workerWG sync.WaitGroup
+var workers *worker.Group
 
 // drainTimeout bounds the shutdown wait for in-flight worker iterations. The
 // publish worker caps per-post work at 2 minutes; this leaves headroom.
 const drainTimeout = 3 * time.Minute
 
+// mongoDisconnectTimeout bounds closing the Mongo connection pool after the
+// workers have drained.
+const mongoDisconnectTimeout = 10 * time.Second
+
 // startWorker runs fn immediately and then on every interval tick until
-// shutdown, registering the loop with workerWG so main can drain it.
+// shutdown, registering the loop with workers so main can drain it.
 func startWorker(interval time.Duration, fn func(context.Context)) {
-	workerWG.Add(1)
-	go func() {
-		defer workerWG.Done()
-		worker.RunLoop(shutdownCtx, interval, fn)
-	}()
+	workers.Loop(interval, fn)
 }
 
 func NewApp() *app.App {
@@ -58,34 +56,26 @@ func NewApp() *app.App {
 			InitJob,
 			InitPublishWorker,
 			InitTokenRefresh,
+			InitSchedules,
+			InitSyncRecordCleanup,
 		},
 	})
 	return appCore
 }
 
 func main() {
+	// 一次性子命令，如 hypersync threads exchange
+	if len(os.Args) > 1 && os.Args[1] == "threads" {
+		os.Exit(runThreadsCommand(os.Args[2:]))
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	shutdownCtx = ctx
+	workers = worker.NewGroup(ctx)
 
 	go func() {
 		<-ctx.Done()
 		stop() // restore default handling so a second signal kills immediately
-
-		logger := log.FromContext(context.Background())
-		logger.Info("Shutdown signal received, draining background workers")
-
-		done := make(chan struct{})
-		go func() {
-			workerWG.Wait()
-			close(done)
-		}()
-
-		select {
-		case <-done:
-			logger.Info("Background workers stopped")
-		case <-time.After(drainTimeout):
-			logger.Warn("Timed out waiting for background workers, exiting anyway")
-		}
+		shutdown()
 		os.Exit(0)
 	}()
 
@@ -93,6 +83,26 @@ func main() {
 	app.Run()
 }
 
+// shutdown stops the background workers, waits up to drainTimeout for
+// in-flight iterations (e.g. a running Sync) and then closes the Mongo
+// connection they were using.
+func shutdown() {
+	logger := log.FromContext(context.Background())
+	logger.Info("Shutdown signal received, draining background workers")
+
+	if err := workers.Shutdown(drainTimeout); err != nil {
+		logger.Warn("Timed out waiting for background workers, exiting anyway")
+	} else {
+		logger.Info("Background workers stopped")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mongoDisconnectTimeout)
+	defer cancel()
+	if err := dao.NewMongoClient().Disconnect(ctx); err != nil {
+		logger.Error("Failed to disconnect from MongoDB", "error", err)
+	}
+}
+
 func InitAuth() error {
 	logger := log.FromContext(context.Background())
 
@@ -112,11 +122,26 @@ func InitAuth() error {
 	if err := userStore.EnsureIndexes(ctx); err != nil {
 		logger.Error("Failed to ensure user indexes", "error", err)
 	}
+	if err := auth.NewMongoRefreshTokenStore(mongoClient, "hypersync").EnsureIndexes(ctx); err != nil {
+		logger.Error("Failed to ensure refresh token indexes", "error", err)
+	}
+	if err := auth.NewMongoRevokedTokenStore(mongoClient, "hypersync").EnsureIndexes(ctx); err != nil {
+		logger.Error("Failed to ensure revoked token indexes", "error", err)
+	}
+	if err := auth.NewMongoPasswordResetStore(mongoClient, "hypersync").EnsureIndexes(ctx); err != nil {
+		logger.Error("Failed to ensure password reset token indexes", "error", err)
+	}
 
 	if err := auth.SeedUser(ctx, userStore, authConf.Username, authConf.Password); err != nil {
 		logger.Error("Failed to seed user", "error", err)
 		return err
 	}
+	if authConf.Email != "" {
+		if err := userStore.SetEmail(ctx, authConf.Username, authConf.Email); err != nil {
+			logger.Error("Failed to set user email", "error", err)
+			return err
+		}
+	}
 
 	return nil
 }
@@ -124,8 +149,10 @@ func InitAuth() error {
 func InitIndexes() error {
 	logger := log.FromContext(context.Background())
 	mongoDAO := wire.NewMongoDAO()
+	// 去重依赖 (social, social_id) 唯一索引，建不出来就不能安全同步
 	if err := mongoDAO.EnsureIndexes(context.Background()); err != nil {
 		logger.Error("Failed to ensure database indexes", "error", err)
+		return err
 	}
 	return nil
 }
@@ -142,7 +169,7 @@ func InitJob() error {
 		if mainSocial == "" {
 			mainSocial = name
 		}
-		if err := runJob(mainSocial, social.SyncTo); err != nil {
+		if err := runJob(mainSocial, social.SyncTo, syncInterval(social)); err != nil {
 			logger.Error("Failed to start sync job", "main_social", mainSocial, "error", err)
 			return err
 		}
@@ -165,18 +192,85 @@ func InitTokenRefresh() error {
 	// 启动 token 刷新定时任务
 	// 每10分钟检查一次 token 状态
 	// StartTokenRefreshScheduler 自带 ticker 循环并在 ctx.Done() 时退出
-	workerWG.Add(1)
-	go func() {
-		defer workerWG.Done()
+	workers.Go(func(ctx context.Context) {
 		interval := time.Minute * 10
 		logger.Info("Starting token refresh scheduler", "interval", interval)
-		schedulerService.StartTokenRefreshScheduler(shutdownCtx, interval)
-	}()
+		schedulerService.StartTokenRefreshScheduler(ctx, interval)
+	})
 
 	logger.Info("Token refresh scheduler initialized successfully")
 	return nil
 }
 
+// InitSchedules 加载 scheduler.schedule_patterns，按 cron 表达式触发同步。
+// cron 表达式不合法时启动失败。
+func InitSchedules() error {
+	logger := log.FromContext(context.Background())
+
+	if conf.Conf.Scheduler == nil || len(conf.Conf.Scheduler.SchedulePatterns) == 0 {
+		return nil
+	}
+
+	schedulerService, err := wire.GetSchedulerService()
+	if err != nil {
+		logger.Error("Failed to create scheduler service", "error", err)
+		return err
+	}
+	if err := schedulerService.LoadSchedules(conf.Conf.Scheduler.SchedulePatterns); err != nil {
+		logger.Error("Invalid sync schedule", "error", err)
+		return err
+	}
+
+	workers.Go(schedulerService.RunSchedules)
+
+	logger.Info("Sync schedules initialized", "count", len(schedulerService.GetSchedulerStatus().Schedules))
+	return nil
+}
+
+// InitSyncRecordCleanup 按 sync.record_retention 每小时清理一次已完成的旧 sync_records。
+// sync.record_cleanup 为 archive 时只标记 archived，不删除，便于审计。
+func InitSyncRecordCleanup() error {
+	logger := log.FromContext(context.Background())
+
+	syncConf := conf.Conf.Sync
+	if syncConf == nil || syncConf.RecordRetention <= 0 {
+		return nil
+	}
+
+	mode := syncConf.RecordCleanup
+	if mode == "" {
+		mode = dao.SyncRecordCleanupDelete
+	}
+	if mode != dao.SyncRecordCleanupDelete && mode != dao.SyncRecordCleanupArchive {
+		return fmt.Errorf("sync.record_cleanup must be %q or %q, got %q",
+			dao.SyncRecordCleanupDelete, dao.SyncRecordCleanupArchive, mode)
+	}
+
+	mongoDAO := wire.NewMongoDAO()
+	retention := syncConf.RecordRetention
+	startWorker(time.Hour, func(ctx context.Context) {
+		var (
+			n   int64
+			err error
+		)
+		if mode == dao.SyncRecordCleanupArchive {
+			n, err = mongoDAO.ArchiveOldSyncRecords(ctx, retention)
+		} else {
+			n, err = mongoDAO.CleanupOldSyncRecords(ctx, retention)
+		}
+		if err != nil {
+			logger.Error("Sync record cleanup failed", "mode", mode, "error", err)
+			return
+		}
+		if n > 0 {
+			logger.Info("Cleaned up old sync records", "mode", mode, "count", n)
+		}
+	})
+
+	logger.Info("Sync record cleanup started", "mode", mode, "retention", retention)
+	return nil
+}
+
 func InitPublishWorker() error {
 	logger := log.FromContext(context.Background())
 
@@ -216,33 +310,62 @@ func InitPublishWorker() error {
 	return nil
 }
 
-func runJob(mainSocial string, socials []string) error {
+// syncInterval returns how often a source is polled: its own sync_interval,
+// else sync.interval, else 30s.
+func syncInterval(platform *social.PlatformConfig) time.Duration {
+	if platform != nil && platform.SyncInterval > 0 {
+		return platform.SyncInterval
+	}
+	if conf.Conf.Sync != nil && conf.Conf.Sync.Interval > 0 {
+		return conf.Conf.Sync.Interval
+	}
+	return 30 * time.Second
+}
+
+func runJob(mainSocial string, socials []string, interval time.Duration) error {
 	logger := log.FromContext(context.Background())
-	logger.Info("Running job", "main_social", mainSocial, "socials", socials)
 
-	socialService, err := wire.GetSocialService()
-	if err != nil {
-		return err
-	}
-	mongoClient := dao.NewMongoClient()
-	postDao := dao.NewPostDao(mongoClient)
-	locker := dao.NewLocker(dao.NewRedisClient())
-	syncService, err := service.NewSyncService(postDao, socialService, locker, mainSocial, socials)
+	// 与配置 API 共用同一实例，运行时修改的设置在下一轮同步生效
+	syncService, err := wire.GetSyncService(mainSocial, socials)
 	if err != nil {
 		return err
 	}
 
-	interval := 30 * time.Second
-	if conf.Conf.Sync != nil && conf.Conf.Sync.Interval > 0 {
-		interval = conf.Conf.Sync.Interval
+	// 随机错开首次同步，避免启动时所有源同时请求各平台 API
+	delay := worker.Jitter(interval)
+	logger.Info("Running job", "main_social", mainSocial, "socials", socials,
+		"interval", interval, "initial_delay", delay)
+
+	// runSync 运行一轮并记录汇总，供定时循环与 cron 调度共用
+	runSync := func(ctx context.Context) error {
+		result, err := syncService.Sync(ctx)
+		if err != nil {
+			return err
+		}
+		if result.TotalChecked > 0 {
+			log.FromContext(ctx).Info("Sync finished",
+				"main_social", mainSocial,
+				"checked", result.TotalChecked,
+				"synced", result.Synced,
+				"skipped", result.Skipped,
+				"failed", result.Failed)
+		}
+		return nil
 	}
 
-	startWorker(interval, func(ctx context.Context) {
-		if err := syncService.Sync(ctx); err != nil {
+	workers.LoopAfter(delay, interval, func(ctx context.Context) {
+		if err := runSync(ctx); err != nil {
 			logger.Error("Sync failed",
 				"error", err)
 		}
 	})
 
+	// scheduler.schedule_patterns 可以在固定时间额外触发这个源的同步
+	schedulerService, err := wire.GetSchedulerService()
+	if err != nil {
+		return err
+	}
+	schedulerService.RegisterSyncJob(mainSocial, runSync)
+
 	return nil
 }
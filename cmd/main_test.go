@@ -0,0 +1,23 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestSyncInterval(t *testing.T) {
+	prev := conf.Conf.Sync
+	t.Cleanup(func() { conf.Conf.Sync = prev })
+
+	conf.Conf.Sync = nil
+	assert.Equal(t, 30*time.Second, syncInterval(&social.PlatformConfig{}), "default")
+
+	conf.Conf.Sync = &conf.SyncConfig{Interval: time.Minute}
+	assert.Equal(t, time.Minute, syncInterval(&social.PlatformConfig{}), "sync.interval")
+	assert.Equal(t, 5*time.Minute, syncInterval(&social.PlatformConfig{SyncInterval: 5 * time.Minute}), "per-social sync_interval wins")
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"butterfly.orx.me/core"
+	"butterfly.orx.me/core/app"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+	"go.orx.me/apps/hyper-sync/internal/wire"
+)
+
+const threadsUsage = "usage: hypersync threads exchange --name <platform> --token <short-lived token>"
+
+// threadsExchangeArgs are the flags of `hypersync threads exchange`.
+type threadsExchangeArgs struct {
+	name  string
+	token string
+}
+
+// parseThreadsCommand parses the arguments after `hypersync threads`.
+func parseThreadsCommand(args []string, output io.Writer) (*threadsExchangeArgs, error) {
+	if len(args) == 0 || args[0] != "exchange" {
+		return nil, errors.New(threadsUsage)
+	}
+
+	fs := flag.NewFlagSet("threads exchange", flag.ContinueOnError)
+	fs.SetOutput(output)
+	parsed := &threadsExchangeArgs{}
+	fs.StringVar(&parsed.name, "name", "threads", "name of the Threads platform in socials")
+	fs.StringVar(&parsed.token, "token", "", "short-lived access token from the Threads OAuth flow")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	if parsed.token == "" {
+		return nil, errors.New("--token is required\n" + threadsUsage)
+	}
+	return parsed, nil
+}
+
+// runThreadsCommand exchanges a Threads short-lived token for a long-lived
+// one and stores it, the CLI counterpart of
+// POST /api/platforms/:name/threads/exchange. It returns the exit code.
+func runThreadsCommand(args []string) int {
+	parsed, err := parseThreadsCommand(args, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	runCommand(func(ctx context.Context) error {
+		schedulerService, err := wire.GetSchedulerService()
+		if err != nil {
+			return err
+		}
+		expiresAt, err := schedulerService.ExchangeThreadsToken(ctx, parsed.name, parsed.token)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Stored long-lived token for %s, expires at %s\n", parsed.name, expiresAt.Format(time.RFC3339))
+		return nil
+	})
+	return 0
+}
+
+// runCommand runs fn with the configuration and stores loaded the same way
+// as the server, then exits instead of serving. Butterfly only loads them
+// inside App.Run, so fn runs as the app's only init func and the process
+// exits with its result.
+func runCommand(fn func(ctx context.Context) error) {
+	// 子命令的参数已解析完，不要交给框架
+	os.Args = os.Args[:1]
+
+	appCore := core.New(&app.Config{
+		Config:  conf.Conf,
+		Service: "hypersync",
+		InitFunc: []func() error{
+			func() error {
+				if err := fn(context.Background()); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+				os.Exit(0)
+				return nil
+			},
+		},
+	})
+	appCore.Run()
+}
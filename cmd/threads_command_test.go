@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseThreadsCommand(t *testing.T) {
+	parsed, err := parseThreadsCommand([]string{"exchange", "--name", "threads-main", "--token", "short"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "threads-main", parsed.name)
+	assert.Equal(t, "short", parsed.token)
+
+	parsed, err = parseThreadsCommand([]string{"exchange", "-token=short"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "threads", parsed.name, "defaults to the platform named threads")
+
+	_, err = parseThreadsCommand([]string{"exchange", "--name", "threads"}, io.Discard)
+	assert.ErrorContains(t, err, "--token is required")
+
+	_, err = parseThreadsCommand(nil, io.Discard)
+	assert.ErrorContains(t, err, "usage")
+
+	_, err = parseThreadsCommand([]string{"refresh"}, io.Discard)
+	assert.ErrorContains(t, err, "usage")
+
+	_, err = parseThreadsCommand([]string{"exchange", "--unknown"}, io.Discard)
+	assert.Error(t, err)
+}
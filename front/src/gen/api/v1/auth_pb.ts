@@ -10,7 +10,7 @@ import type { Message } from "@bufbuild/protobuf";
  * Describes the file api/v1/auth.proto.
  */
 export const file_api_v1_auth: GenFile = /*@__PURE__*/
-  fileDesc("ChFhcGkvdjEvYXV0aC5wcm90bxIGYXBpLnYxIjIKDExvZ2luUmVxdWVzdBIQCgh1c2VybmFtZRgBIAEoCRIQCghwYXNzd29yZBgCIAEoCSIyCg1Mb2dpblJlc3BvbnNlEg0KBXRva2VuGAEgASgJEhIKCmV4cGlyZXNfYXQYAiABKAMiRwoVQ2hhbmdlUGFzc3dvcmRSZXF1ZXN0EhgKEGN1cnJlbnRfcGFzc3dvcmQYASABKAkSFAoMbmV3X3Bhc3N3b3JkGAIgASgJIhgKFkNoYW5nZVBhc3N3b3JkUmVzcG9uc2UymAEKC0F1dGhTZXJ2aWNlEjYKBUxvZ2luEhQuYXBpLnYxLkxvZ2luUmVxdWVzdBoVLmFwaS52MS5Mb2dpblJlc3BvbnNlIgASUQoOQ2hhbmdlUGFzc3dvcmQSHS5hcGkudjEuQ2hhbmdlUGFzc3dvcmRSZXF1ZXN0Gh4uYXBpLnYxLkNoYW5nZVBhc3N3b3JkUmVzcG9uc2UiAEIsWipnby5vcngubWUvYXBwcy9oeXBlci1zeW5jL3BrZy9wcm90by9hcGkvdjFiBnByb3RvMw");
+  fileDesc("ChFhcGkvdjEvYXV0aC5wcm90bxIGYXBpLnYxIjIKDExvZ2luUmVxdWVzdBIQCgh1c2VybmFtZRgBIAEoCRIQCghwYXNzd29yZBgCIAEoCSJlCg1Mb2dpblJlc3BvbnNlEg0KBXRva2VuGAEgASgJEhIKCmV4cGlyZXNfYXQYAiABKAMSFQoNcmVmcmVzaF90b2tlbhgDIAEoCRIaChJyZWZyZXNoX2V4cGlyZXNfYXQYBCABKAMiRwoVQ2hhbmdlUGFzc3dvcmRSZXF1ZXN0EhgKEGN1cnJlbnRfcGFzc3dvcmQYASABKAkSFAoMbmV3X3Bhc3N3b3JkGAIgASgJIhgKFkNoYW5nZVBhc3N3b3JkUmVzcG9uc2UiLAoTUmVmcmVzaFRva2VuUmVxdWVzdBIVCg1yZWZyZXNoX3Rva2VuGAEgASgJImwKFFJlZnJlc2hUb2tlblJlc3BvbnNlEg0KBXRva2VuGAEgASgJEhIKCmV4cGlyZXNfYXQYAiABKAMSFQoNcmVmcmVzaF90b2tlbhgDIAEoCRIaChJyZWZyZXNoX2V4cGlyZXNfYXQYBCABKAMiJgoNTG9nb3V0UmVxdWVzdBIVCg1yZWZyZXNoX3Rva2VuGAEgASgJIhAKDkxvZ291dFJlc3BvbnNlIiYKFkxvZ2luV2l0aEdpdGh1YlJlcXVlc3QSDAoEY29kZRgBIAEoCSIsChtSZXF1ZXN0UGFzc3dvcmRSZXNldFJlcXVlc3QSDQoFZW1haWwYASABKAkiHgocUmVxdWVzdFBhc3N3b3JkUmVzZXRSZXNwb25zZSJCChtDb25maXJtUGFzc3dvcmRSZXNldFJlcXVlc3QSDQoFdG9rZW4YASABKAkSFAoMbmV3X3Bhc3N3b3JkGAIgASgJIh4KHENvbmZpcm1QYXNzd29yZFJlc2V0UmVzcG9uc2UytgQKC0F1dGhTZXJ2aWNlEjYKBUxvZ2luEhQuYXBpLnYxLkxvZ2luUmVxdWVzdBoVLmFwaS52MS5Mb2dpblJlc3BvbnNlIgASUQoOQ2hhbmdlUGFzc3dvcmQSHS5hcGkudjEuQ2hhbmdlUGFzc3dvcmRSZXF1ZXN0Gh4uYXBpLnYxLkNoYW5nZVBhc3N3b3JkUmVzcG9uc2UiABJLCgxSZWZyZXNoVG9rZW4SGy5hcGkudjEuUmVmcmVzaFRva2VuUmVxdWVzdBocLmFwaS52MS5SZWZyZXNoVG9rZW5SZXNwb25zZSIAEjkKBkxvZ291dBIVLmFwaS52MS5Mb2dvdXRSZXF1ZXN0GhYuYXBpLnYxLkxvZ291dFJlc3BvbnNlIgASSgoPTG9naW5XaXRoR2l0aHViEh4uYXBpLnYxLkxvZ2luV2l0aEdpdGh1YlJlcXVlc3QaFS5hcGkudjEuTG9naW5SZXNwb25zZSIAEmMKFFJlcXVlc3RQYXNzd29yZFJlc2V0EiMuYXBpLnYxLlJlcXVlc3RQYXNzd29yZFJlc2V0UmVxdWVzdBokLmFwaS52MS5SZXF1ZXN0UGFzc3dvcmRSZXNldFJlc3BvbnNlIgASYwoUQ29uZmlybVBhc3N3b3JkUmVzZXQSIy5hcGkudjEuQ29uZmlybVBhc3N3b3JkUmVzZXRSZXF1ZXN0GiQuYXBpLnYxLkNvbmZpcm1QYXNzd29yZFJlc2V0UmVzcG9uc2UiAEIsWipnby5vcngubWUvYXBwcy9oeXBlci1zeW5jL3BrZy9wcm90by9hcGkvdjFiBnByb3RvMw");
 
 /**
  * @generated from message api.v1.LoginRequest
@@ -47,6 +47,16 @@ export type LoginResponse = Message<"api.v1.LoginResponse"> & {
    * @generated from field: int64 expires_at = 2;
    */
   expiresAt: bigint;
+
+  /**
+   * @generated from field: string refresh_token = 3;
+   */
+  refreshToken: string;
+
+  /**
+   * @generated from field: int64 refresh_expires_at = 4;
+   */
+  refreshExpiresAt: bigint;
 };
 
 /**
@@ -91,6 +101,167 @@ export type ChangePasswordResponse = Message<"api.v1.ChangePasswordResponse"> &
 export const ChangePasswordResponseSchema: GenMessage<ChangePasswordResponse> = /*@__PURE__*/
   messageDesc(file_api_v1_auth, 3);
 
+/**
+ * @generated from message api.v1.RefreshTokenRequest
+ */
+export type RefreshTokenRequest = Message<"api.v1.RefreshTokenRequest"> & {
+  /**
+   * @generated from field: string refresh_token = 1;
+   */
+  refreshToken: string;
+};
+
+/**
+ * Describes the message api.v1.RefreshTokenRequest.
+ * Use `create(RefreshTokenRequestSchema)` to create a new message.
+ */
+export const RefreshTokenRequestSchema: GenMessage<RefreshTokenRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 4);
+
+/**
+ * @generated from message api.v1.RefreshTokenResponse
+ */
+export type RefreshTokenResponse = Message<"api.v1.RefreshTokenResponse"> & {
+  /**
+   * @generated from field: string token = 1;
+   */
+  token: string;
+
+  /**
+   * @generated from field: int64 expires_at = 2;
+   */
+  expiresAt: bigint;
+
+  /**
+   * @generated from field: string refresh_token = 3;
+   */
+  refreshToken: string;
+
+  /**
+   * @generated from field: int64 refresh_expires_at = 4;
+   */
+  refreshExpiresAt: bigint;
+};
+
+/**
+ * Describes the message api.v1.RefreshTokenResponse.
+ * Use `create(RefreshTokenResponseSchema)` to create a new message.
+ */
+export const RefreshTokenResponseSchema: GenMessage<RefreshTokenResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 5);
+
+/**
+ * @generated from message api.v1.LogoutRequest
+ */
+export type LogoutRequest = Message<"api.v1.LogoutRequest"> & {
+  /**
+   * @generated from field: string refresh_token = 1;
+   */
+  refreshToken: string;
+};
+
+/**
+ * Describes the message api.v1.LogoutRequest.
+ * Use `create(LogoutRequestSchema)` to create a new message.
+ */
+export const LogoutRequestSchema: GenMessage<LogoutRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 6);
+
+/**
+ * @generated from message api.v1.LogoutResponse
+ */
+export type LogoutResponse = Message<"api.v1.LogoutResponse"> & {
+};
+
+/**
+ * Describes the message api.v1.LogoutResponse.
+ * Use `create(LogoutResponseSchema)` to create a new message.
+ */
+export const LogoutResponseSchema: GenMessage<LogoutResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 7);
+
+/**
+ * @generated from message api.v1.LoginWithGithubRequest
+ */
+export type LoginWithGithubRequest = Message<"api.v1.LoginWithGithubRequest"> & {
+  /**
+   * @generated from field: string code = 1;
+   */
+  code: string;
+};
+
+/**
+ * Describes the message api.v1.LoginWithGithubRequest.
+ * Use `create(LoginWithGithubRequestSchema)` to create a new message.
+ */
+export const LoginWithGithubRequestSchema: GenMessage<LoginWithGithubRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 8);
+
+/**
+ * @generated from message api.v1.RequestPasswordResetRequest
+ */
+export type RequestPasswordResetRequest = Message<"api.v1.RequestPasswordResetRequest"> & {
+  /**
+   * @generated from field: string email = 1;
+   */
+  email: string;
+};
+
+/**
+ * Describes the message api.v1.RequestPasswordResetRequest.
+ * Use `create(RequestPasswordResetRequestSchema)` to create a new message.
+ */
+export const RequestPasswordResetRequestSchema: GenMessage<RequestPasswordResetRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 9);
+
+/**
+ * @generated from message api.v1.RequestPasswordResetResponse
+ */
+export type RequestPasswordResetResponse = Message<"api.v1.RequestPasswordResetResponse"> & {
+};
+
+/**
+ * Describes the message api.v1.RequestPasswordResetResponse.
+ * Use `create(RequestPasswordResetResponseSchema)` to create a new message.
+ */
+export const RequestPasswordResetResponseSchema: GenMessage<RequestPasswordResetResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 10);
+
+/**
+ * @generated from message api.v1.ConfirmPasswordResetRequest
+ */
+export type ConfirmPasswordResetRequest = Message<"api.v1.ConfirmPasswordResetRequest"> & {
+  /**
+   * @generated from field: string token = 1;
+   */
+  token: string;
+
+  /**
+   * @generated from field: string new_password = 2;
+   */
+  newPassword: string;
+};
+
+/**
+ * Describes the message api.v1.ConfirmPasswordResetRequest.
+ * Use `create(ConfirmPasswordResetRequestSchema)` to create a new message.
+ */
+export const ConfirmPasswordResetRequestSchema: GenMessage<ConfirmPasswordResetRequest> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 11);
+
+/**
+ * @generated from message api.v1.ConfirmPasswordResetResponse
+ */
+export type ConfirmPasswordResetResponse = Message<"api.v1.ConfirmPasswordResetResponse"> & {
+};
+
+/**
+ * Describes the message api.v1.ConfirmPasswordResetResponse.
+ * Use `create(ConfirmPasswordResetResponseSchema)` to create a new message.
+ */
+export const ConfirmPasswordResetResponseSchema: GenMessage<ConfirmPasswordResetResponse> = /*@__PURE__*/
+  messageDesc(file_api_v1_auth, 12);
+
 /**
  * @generated from service api.v1.AuthService
  */
@@ -111,6 +282,46 @@ export const AuthService: GenService<{
     input: typeof ChangePasswordRequestSchema;
     output: typeof ChangePasswordResponseSchema;
   },
+  /**
+   * @generated from rpc api.v1.AuthService.RefreshToken
+   */
+  refreshToken: {
+    methodKind: "unary";
+    input: typeof RefreshTokenRequestSchema;
+    output: typeof RefreshTokenResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.AuthService.Logout
+   */
+  logout: {
+    methodKind: "unary";
+    input: typeof LogoutRequestSchema;
+    output: typeof LogoutResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.AuthService.LoginWithGithub
+   */
+  loginWithGithub: {
+    methodKind: "unary";
+    input: typeof LoginWithGithubRequestSchema;
+    output: typeof LoginResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.AuthService.RequestPasswordReset
+   */
+  requestPasswordReset: {
+    methodKind: "unary";
+    input: typeof RequestPasswordResetRequestSchema;
+    output: typeof RequestPasswordResetResponseSchema;
+  },
+  /**
+   * @generated from rpc api.v1.AuthService.ConfirmPasswordReset
+   */
+  confirmPasswordReset: {
+    methodKind: "unary";
+    input: typeof ConfirmPasswordResetRequestSchema;
+    output: typeof ConfirmPasswordResetResponseSchema;
+  },
 }> = /*@__PURE__*/
   serviceDesc(file_api_v1_auth, 0);
 
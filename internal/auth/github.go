@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGithubTokenURL = "https://github.com/login/oauth/access_token"
+	defaultGithubAPIURL   = "https://api.github.com"
+)
+
+// ErrGithubCodeRejected is returned by Exchange when GitHub refuses the
+// authorization code (expired, already used, or issued to another app).
+var ErrGithubCodeRejected = errors.New("github rejected the authorization code")
+
+// GithubIdentity is what a completed GitHub OAuth flow tells us about the user.
+type GithubIdentity struct {
+	ID    string
+	Login string
+	// Email is the user's primary address, and only set when GitHub reports
+	// it as verified.
+	Email string
+}
+
+// GithubOAuth exchanges OAuth authorization codes for GitHub identities.
+type GithubOAuth struct {
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	httpClient   *http.Client
+
+	// TokenURL and APIURL default to github.com; tests point them at a fake.
+	TokenURL string
+	APIURL   string
+}
+
+func NewGithubOAuth(clientID, clientSecret, redirectURL string) *GithubOAuth {
+	return &GithubOAuth{
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		TokenURL:     defaultGithubTokenURL,
+		APIURL:       defaultGithubAPIURL,
+	}
+}
+
+// Exchange trades code for an access token and looks up the user it belongs
+// to. The access token is only used for these lookups and is not kept.
+func (g *GithubOAuth) Exchange(ctx context.Context, code string) (*GithubIdentity, error) {
+	accessToken, err := g.exchangeCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+	}
+	if err := g.getJSON(ctx, accessToken, "/user", &user); err != nil {
+		return nil, fmt.Errorf("get github user: %w", err)
+	}
+	if user.ID == 0 {
+		return nil, errors.New("github user response has no id")
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := g.getJSON(ctx, accessToken, "/user/emails", &emails); err != nil {
+		return nil, fmt.Errorf("get github user emails: %w", err)
+	}
+
+	identity := &GithubIdentity{
+		ID:    strconv.FormatInt(user.ID, 10),
+		Login: user.Login,
+	}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			identity.Email = NormalizeEmail(e.Email)
+			break
+		}
+	}
+	return identity, nil
+}
+
+func (g *GithubOAuth) exchangeCode(ctx context.Context, code string) (string, error) {
+	form := url.Values{
+		"client_id":     {g.clientID},
+		"client_secret": {g.clientSecret},
+		"code":          {code},
+	}
+	if g.redirectURL != "" {
+		form.Set("redirect_uri", g.redirectURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("exchange github code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("exchange github code: unexpected status %d", resp.StatusCode)
+	}
+
+	// GitHub reports a bad code as 200 with an "error" field.
+	var result struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode github token response: %w", err)
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("%w: %s", ErrGithubCodeRejected, result.Error)
+	}
+	if result.AccessToken == "" {
+		return "", errors.New("github token response has no access_token")
+	}
+	return result.AccessToken, nil
+}
+
+func (g *GithubOAuth) getJSON(ctx context.Context, accessToken, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(g.APIURL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"connectrpc.com/connect"
 	"github.com/gin-gonic/gin"
@@ -13,13 +14,43 @@ import (
 
 type usernameKey struct{}
 
+type accessTokenKey struct{}
+
 func UsernameFromContext(ctx context.Context) string {
 	v, _ := ctx.Value(usernameKey{}).(string)
 	return v
 }
 
+// AccessToken identifies the JWT a request was authenticated with.
+type AccessToken struct {
+	// ID is the jti claim; empty for tokens minted before it was added.
+	ID        string
+	ExpiresAt time.Time
+}
+
+// AccessTokenFromContext returns the token the interceptor or middleware
+// accepted for this request.
+func AccessTokenFromContext(ctx context.Context) (AccessToken, bool) {
+	v, ok := ctx.Value(accessTokenKey{}).(AccessToken)
+	return v, ok
+}
+
+func withAccessToken(ctx context.Context, username string, token AccessToken) context.Context {
+	ctx = context.WithValue(ctx, usernameKey{}, username)
+	return context.WithValue(ctx, accessTokenKey{}, token)
+}
+
 var publicProcedures = map[string]bool{
 	"/api.v1.AuthService/Login": true,
+	// The caller's access token has typically expired; the refresh token in
+	// the request body is the credential.
+	"/api.v1.AuthService/RefreshToken": true,
+	// The OAuth authorization code in the request body is the credential.
+	"/api.v1.AuthService/LoginWithGithub": true,
+	// Password reset is for users who cannot log in; the emailed token is
+	// the credential for the confirm step.
+	"/api.v1.AuthService/RequestPasswordReset": true,
+	"/api.v1.AuthService/ConfirmPasswordReset": true,
 }
 
 // TokenVersionClaim names the JWT claim carrying the user's TokenVersion at
@@ -39,15 +70,16 @@ var errStoreUnavailable = errors.New("user store unavailable")
 // plain-HTTP middleware so both enforce identical rules. The token's version
 // claim must match the user's current TokenVersion, so a password change
 // invalidates every previously issued token; tokens minted before versioning
-// existed carry an implicit version 0.
-func ValidateBearer(ctx context.Context, jwtSecret, authHeader string, store UserStore) (string, error) {
+// existed carry an implicit version 0. When revoked is non-nil, tokens whose
+// jti was logged out are rejected as well.
+func ValidateBearer(ctx context.Context, jwtSecret, authHeader string, store UserStore, revoked RevokedTokenStore) (string, AccessToken, error) {
 	if authHeader == "" {
-		return "", errInvalidToken
+		return "", AccessToken{}, errInvalidToken
 	}
 
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 	if tokenString == authHeader {
-		return "", errInvalidToken
+		return "", AccessToken{}, errInvalidToken
 	}
 
 	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
@@ -57,41 +89,57 @@ func ValidateBearer(ctx context.Context, jwtSecret, authHeader string, store Use
 		return []byte(jwtSecret), nil
 	}, jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}), jwt.WithExpirationRequired())
 	if err != nil || !token.Valid {
-		return "", errInvalidToken
+		return "", AccessToken{}, errInvalidToken
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", errInvalidToken
+		return "", AccessToken{}, errInvalidToken
 	}
 
 	username, _ := claims["sub"].(string)
+	jti, _ := claims["jti"].(string)
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return "", AccessToken{}, errInvalidToken
+	}
+	accessToken := AccessToken{ID: jti, ExpiresAt: exp.Time}
+
+	if revoked != nil && jti != "" {
+		isRevoked, err := revoked.IsTokenRevoked(ctx, jti)
+		if err != nil {
+			return "", AccessToken{}, errStoreUnavailable
+		}
+		if isRevoked {
+			return "", AccessToken{}, errInvalidToken
+		}
+	}
 
 	user, err := store.GetByUsername(ctx, username)
 	if err != nil {
 		// A missing user is a genuine auth failure; any other store error is
 		// an outage we must not report as revocation.
 		if errors.Is(err, ErrUserNotFound) {
-			return "", errInvalidToken
+			return "", AccessToken{}, errInvalidToken
 		}
-		return "", errStoreUnavailable
+		return "", AccessToken{}, errStoreUnavailable
 	}
 	ver, _ := claims[TokenVersionClaim].(float64) // JSON numbers decode as float64; absent → 0
 	if int64(ver) != user.TokenVersion {
-		return "", errInvalidToken
+		return "", AccessToken{}, errInvalidToken
 	}
 
-	return username, nil
+	return username, accessToken, nil
 }
 
-func NewAuthInterceptor(jwtSecret string, store UserStore) connect.UnaryInterceptorFunc {
+func NewAuthInterceptor(jwtSecret string, store UserStore, revoked RevokedTokenStore) connect.UnaryInterceptorFunc {
 	return connect.UnaryInterceptorFunc(func(next connect.UnaryFunc) connect.UnaryFunc {
 		return connect.UnaryFunc(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
 			if publicProcedures[req.Spec().Procedure] {
 				return next(ctx, req)
 			}
 
-			username, err := ValidateBearer(ctx, jwtSecret, req.Header().Get("Authorization"), store)
+			username, token, err := ValidateBearer(ctx, jwtSecret, req.Header().Get("Authorization"), store, revoked)
 			if err != nil {
 				if errors.Is(err, errStoreUnavailable) {
 					return nil, connect.NewError(connect.CodeUnavailable, err)
@@ -99,17 +147,16 @@ func NewAuthInterceptor(jwtSecret string, store UserStore) connect.UnaryIntercep
 				return nil, connect.NewError(connect.CodeUnauthenticated, err)
 			}
 
-			ctx = context.WithValue(ctx, usernameKey{}, username)
-			return next(ctx, req)
+			return next(withAccessToken(ctx, username, token), req)
 		})
 	})
 }
 
 // GinMiddleware protects plain HTTP routes (media upload, token management)
 // with the same JWT the Connect services use.
-func GinMiddleware(jwtSecret string, store UserStore) gin.HandlerFunc {
+func GinMiddleware(jwtSecret string, store UserStore, revoked RevokedTokenStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		username, err := ValidateBearer(c.Request.Context(), jwtSecret, c.GetHeader("Authorization"), store)
+		username, token, err := ValidateBearer(c.Request.Context(), jwtSecret, c.GetHeader("Authorization"), store, revoked)
 		if err != nil {
 			if errors.Is(err, errStoreUnavailable) {
 				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "service unavailable"})
@@ -118,7 +165,7 @@ func GinMiddleware(jwtSecret string, store UserStore) gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthenticated"})
 			return
 		}
-		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), usernameKey{}, username))
+		c.Request = c.Request.WithContext(withAccessToken(c.Request.Context(), username, token))
 		c.Next()
 	}
 }
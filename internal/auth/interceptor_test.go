@@ -8,11 +8,15 @@ import (
 	"testing"
 	"time"
 
+	"connectrpc.com/connect"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	v1 "go.orx.me/apps/hyper-sync/pkg/proto/api/v1"
+	"go.orx.me/apps/hyper-sync/pkg/proto/api/v1/v1connect"
+
 	"go.orx.me/apps/hyper-sync/internal/auth"
 )
 
@@ -33,6 +37,9 @@ func (s *fakeStore) GetByUsername(context.Context, string) (*auth.User, error) {
 }
 func (s *fakeStore) Create(context.Context, *auth.User) error             { return nil }
 func (s *fakeStore) UpdatePassword(context.Context, string, string) error { return nil }
+func (s *fakeStore) GetByEmail(context.Context, string) (*auth.User, error) {
+	return nil, auth.ErrUserNotFound
+}
 
 func mintToken(t *testing.T, version int64) string {
 	t.Helper()
@@ -47,10 +54,15 @@ func mintToken(t *testing.T, version int64) string {
 }
 
 func ginStatusFor(t *testing.T, store auth.UserStore, authHeader string) int {
+	t.Helper()
+	return ginStatusWithRevoked(t, store, nil, authHeader)
+}
+
+func ginStatusWithRevoked(t *testing.T, store auth.UserStore, revoked auth.RevokedTokenStore, authHeader string) int {
 	t.Helper()
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	r.GET("/protected", auth.GinMiddleware(testSecret, store), func(c *gin.Context) {
+	r.GET("/protected", auth.GinMiddleware(testSecret, store, revoked), func(c *gin.Context) {
 		c.Status(http.StatusOK)
 	})
 
@@ -90,3 +102,85 @@ func TestGinMiddleware_MissingUser_ReturnsUnauthorized(t *testing.T) {
 	code := ginStatusFor(t, store, "Bearer "+mintToken(t, 0))
 	assert.Equal(t, http.StatusUnauthorized, code)
 }
+
+func TestGinMiddleware_RevokedTokenID_Rejected(t *testing.T) {
+	mint := func(jti string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "admin",
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"jti": jti,
+		})
+		signed, err := tok.SignedString([]byte(testSecret))
+		require.NoError(t, err)
+		return signed
+	}
+
+	store := &fakeStore{user: &auth.User{Username: "admin"}}
+	revoked := auth.NewMemoryRevokedTokenStore()
+	require.NoError(t, revoked.RevokeToken(context.Background(), "logged-out", time.Now().Add(time.Hour)))
+
+	assert.Equal(t, http.StatusUnauthorized, ginStatusWithRevoked(t, store, revoked, "Bearer "+mint("logged-out")))
+	assert.Equal(t, http.StatusOK, ginStatusWithRevoked(t, store, revoked, "Bearer "+mint("still-valid")))
+}
+
+// recordingAuthHandler records the identity the interceptor put in the
+// context of each call that reached it.
+type recordingAuthHandler struct {
+	v1connect.UnimplementedAuthServiceHandler
+	reached  bool
+	username string
+	token    auth.AccessToken
+}
+
+func (h *recordingAuthHandler) Login(ctx context.Context, _ *connect.Request[v1.LoginRequest]) (*connect.Response[v1.LoginResponse], error) {
+	h.reached = true
+	return connect.NewResponse(&v1.LoginResponse{}), nil
+}
+
+func (h *recordingAuthHandler) ChangePassword(ctx context.Context, _ *connect.Request[v1.ChangePasswordRequest]) (*connect.Response[v1.ChangePasswordResponse], error) {
+	h.reached = true
+	h.username = auth.UsernameFromContext(ctx)
+	h.token, _ = auth.AccessTokenFromContext(ctx)
+	return connect.NewResponse(&v1.ChangePasswordResponse{}), nil
+}
+
+func newInterceptedClient(t *testing.T, store auth.UserStore) (v1connect.AuthServiceClient, *recordingAuthHandler) {
+	t.Helper()
+	h := &recordingAuthHandler{}
+	mux := http.NewServeMux()
+	path, handler := v1connect.NewAuthServiceHandler(h, connect.WithInterceptors(auth.NewAuthInterceptor(testSecret, store, nil)))
+	mux.Handle(path, handler)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return v1connect.NewAuthServiceClient(server.Client(), server.URL), h
+}
+
+func TestAuthInterceptor_ValidToken_PopulatesContext(t *testing.T) {
+	client, h := newInterceptedClient(t, &fakeStore{user: &auth.User{Username: "admin"}})
+
+	req := connect.NewRequest(&v1.ChangePasswordRequest{})
+	req.Header().Set("Authorization", "Bearer "+mintToken(t, 0))
+	_, err := client.ChangePassword(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "admin", h.username)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), h.token.ExpiresAt, time.Minute)
+}
+
+func TestAuthInterceptor_MissingToken_ReturnsUnauthenticated(t *testing.T) {
+	client, h := newInterceptedClient(t, &fakeStore{user: &auth.User{Username: "admin"}})
+
+	_, err := client.ChangePassword(context.Background(), connect.NewRequest(&v1.ChangePasswordRequest{}))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+	assert.False(t, h.reached, "handler must not run without a token")
+}
+
+func TestAuthInterceptor_PublicProcedure_SkipsAuth(t *testing.T) {
+	// A store outage would fail any token check, proving none is made.
+	client, h := newInterceptedClient(t, &fakeStore{err: errors.New("mongo: connection refused")})
+
+	_, err := client.Login(context.Background(), connect.NewRequest(&v1.LoginRequest{}))
+	require.NoError(t, err)
+	assert.True(t, h.reached)
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const passwordResetTokensCollection = "password_reset_tokens"
+
+type MongoPasswordResetStore struct {
+	client   *mongo.Client
+	database string
+}
+
+func NewMongoPasswordResetStore(client *mongo.Client, database string) *MongoPasswordResetStore {
+	return &MongoPasswordResetStore{
+		client:   client,
+		database: database,
+	}
+}
+
+func (s *MongoPasswordResetStore) collection() *mongo.Collection {
+	return s.client.Database(s.database).Collection(passwordResetTokensCollection)
+}
+
+func (s *MongoPasswordResetStore) Create(ctx context.Context, token *PasswordResetToken) error {
+	createdAt := token.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	doc := passwordResetTokenDocument{
+		ID:           bson.NewObjectID(),
+		TokenHash:    token.TokenHash,
+		Username:     token.Username,
+		TokenVersion: token.TokenVersion,
+		ExpiresAt:    token.ExpiresAt,
+		CreatedAt:    createdAt,
+		Used:         token.Used,
+	}
+	_, err := s.collection().InsertOne(ctx, doc)
+	return err
+}
+
+func (s *MongoPasswordResetStore) GetByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error) {
+	var doc passwordResetTokenDocument
+	err := s.collection().FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrPasswordResetTokenNotFound
+		}
+		return nil, err
+	}
+	return &PasswordResetToken{
+		TokenHash:    doc.TokenHash,
+		Username:     doc.Username,
+		TokenVersion: doc.TokenVersion,
+		ExpiresAt:    doc.ExpiresAt,
+		CreatedAt:    doc.CreatedAt,
+		Used:         doc.Used,
+	}, nil
+}
+
+func (s *MongoPasswordResetStore) MarkUsed(ctx context.Context, tokenHash string) error {
+	// Conditional on used=false, so of two concurrent redemptions only one
+	// matches.
+	result, err := s.collection().UpdateOne(
+		ctx,
+		bson.M{"token_hash": tokenHash, "used": false},
+		bson.M{"$set": bson.M{"used": true, "used_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrPasswordResetTokenUsed
+	}
+	return nil
+}
+
+func (s *MongoPasswordResetStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+type passwordResetTokenDocument struct {
+	ID           bson.ObjectID `bson:"_id,omitempty"`
+	TokenHash    string        `bson:"token_hash"`
+	Username     string        `bson:"username"`
+	TokenVersion int64         `bson:"token_version"`
+	ExpiresAt    time.Time     `bson:"expires_at"`
+	CreatedAt    time.Time     `bson:"created_at"`
+	Used         bool          `bson:"used"`
+}
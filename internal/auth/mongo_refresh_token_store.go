@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const refreshTokensCollection = "refresh_tokens"
+
+type MongoRefreshTokenStore struct {
+	client   *mongo.Client
+	database string
+}
+
+func NewMongoRefreshTokenStore(client *mongo.Client, database string) *MongoRefreshTokenStore {
+	return &MongoRefreshTokenStore{
+		client:   client,
+		database: database,
+	}
+}
+
+func (s *MongoRefreshTokenStore) collection() *mongo.Collection {
+	return s.client.Database(s.database).Collection(refreshTokensCollection)
+}
+
+func (s *MongoRefreshTokenStore) Create(ctx context.Context, token *RefreshToken) error {
+	createdAt := token.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	doc := refreshTokenDocument{
+		ID:           bson.NewObjectID(),
+		TokenHash:    token.TokenHash,
+		Username:     token.Username,
+		FamilyID:     token.FamilyID,
+		TokenVersion: token.TokenVersion,
+		ExpiresAt:    token.ExpiresAt,
+		CreatedAt:    createdAt,
+		Used:         token.Used,
+		Revoked:      token.Revoked,
+	}
+	_, err := s.collection().InsertOne(ctx, doc)
+	return err
+}
+
+func (s *MongoRefreshTokenStore) GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	var doc refreshTokenDocument
+	err := s.collection().FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&doc)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return &RefreshToken{
+		TokenHash:    doc.TokenHash,
+		Username:     doc.Username,
+		FamilyID:     doc.FamilyID,
+		TokenVersion: doc.TokenVersion,
+		ExpiresAt:    doc.ExpiresAt,
+		CreatedAt:    doc.CreatedAt,
+		Used:         doc.Used,
+		Revoked:      doc.Revoked,
+	}, nil
+}
+
+func (s *MongoRefreshTokenStore) MarkUsed(ctx context.Context, tokenHash string) error {
+	// The used/revoked conditions make this a compare-and-set: of two
+	// concurrent refreshes with the same token only one matches.
+	result, err := s.collection().UpdateOne(
+		ctx,
+		bson.M{"token_hash": tokenHash, "used": false, "revoked": false},
+		bson.M{"$set": bson.M{"used": true, "used_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrRefreshTokenUsed
+	}
+	return nil
+}
+
+func (s *MongoRefreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := s.collection().UpdateMany(
+		ctx,
+		bson.M{"family_id": familyID},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	return err
+}
+
+func (s *MongoRefreshTokenStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "family_id", Value: 1}},
+		},
+		{
+			// Expired tokens can no longer be redeemed; let MongoDB drop them.
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
+
+type refreshTokenDocument struct {
+	ID           bson.ObjectID `bson:"_id,omitempty"`
+	TokenHash    string        `bson:"token_hash"`
+	Username     string        `bson:"username"`
+	FamilyID     string        `bson:"family_id"`
+	TokenVersion int64         `bson:"token_version"`
+	ExpiresAt    time.Time     `bson:"expires_at"`
+	CreatedAt    time.Time     `bson:"created_at"`
+	Used         bool          `bson:"used"`
+	Revoked      bool          `bson:"revoked"`
+}
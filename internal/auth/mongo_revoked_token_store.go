@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const revokedTokensCollection = "revoked_tokens"
+
+type MongoRevokedTokenStore struct {
+	client   *mongo.Client
+	database string
+}
+
+func NewMongoRevokedTokenStore(client *mongo.Client, database string) *MongoRevokedTokenStore {
+	return &MongoRevokedTokenStore{
+		client:   client,
+		database: database,
+	}
+}
+
+func (s *MongoRevokedTokenStore) collection() *mongo.Collection {
+	return s.client.Database(s.database).Collection(revokedTokensCollection)
+}
+
+// RevokeToken upserts so logging out twice with the same token is harmless.
+func (s *MongoRevokedTokenStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := s.collection().UpdateOne(
+		ctx,
+		bson.M{"jti": jti},
+		bson.M{
+			"$set":         bson.M{"expires_at": expiresAt},
+			"$setOnInsert": bson.M{"revoked_at": time.Now()},
+		},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
+func (s *MongoRevokedTokenStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	// The TTL monitor runs about once a minute, so also compare expires_at
+	// rather than relying on expired entries being gone.
+	n, err := s.collection().CountDocuments(ctx, bson.M{
+		"jti":        jti,
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (s *MongoRevokedTokenStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "jti", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		},
+	})
+	return err
+}
@@ -27,8 +27,27 @@ func (s *MongoUserStore) collection() *mongo.Collection {
 }
 
 func (s *MongoUserStore) GetByUsername(ctx context.Context, username string) (*User, error) {
+	return s.findOne(ctx, bson.M{"username": username})
+}
+
+func (s *MongoUserStore) GetByEmail(ctx context.Context, email string) (*User, error) {
+	email = NormalizeEmail(email)
+	if email == "" {
+		return nil, ErrUserNotFound
+	}
+	return s.findOne(ctx, bson.M{"email": email})
+}
+
+func (s *MongoUserStore) GetByGithubID(ctx context.Context, githubID string) (*User, error) {
+	if githubID == "" {
+		return nil, ErrUserNotFound
+	}
+	return s.findOne(ctx, bson.M{"github_id": githubID})
+}
+
+func (s *MongoUserStore) findOne(ctx context.Context, filter bson.M) (*User, error) {
 	var doc userDocument
-	err := s.collection().FindOne(ctx, bson.M{"username": username}).Decode(&doc)
+	err := s.collection().FindOne(ctx, filter).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, ErrUserNotFound
@@ -40,6 +59,8 @@ func (s *MongoUserStore) GetByUsername(ctx context.Context, username string) (*U
 		Username:     doc.Username,
 		PasswordHash: doc.PasswordHash,
 		TokenVersion: doc.TokenVersion,
+		Email:        doc.Email,
+		GithubID:     doc.GithubID,
 	}, nil
 }
 
@@ -48,11 +69,32 @@ func (s *MongoUserStore) Create(ctx context.Context, user *User) error {
 		ID:           bson.NewObjectID(),
 		Username:     user.Username,
 		PasswordHash: user.PasswordHash,
+		Email:        NormalizeEmail(user.Email),
+		GithubID:     user.GithubID,
 	}
 	_, err := s.collection().InsertOne(ctx, doc)
 	return err
 }
 
+func (s *MongoUserStore) LinkGithub(ctx context.Context, username string, githubID string) error {
+	return s.set(ctx, username, bson.M{"github_id": githubID})
+}
+
+func (s *MongoUserStore) SetEmail(ctx context.Context, username string, email string) error {
+	return s.set(ctx, username, bson.M{"email": NormalizeEmail(email)})
+}
+
+func (s *MongoUserStore) set(ctx context.Context, username string, fields bson.M) error {
+	result, err := s.collection().UpdateOne(ctx, bson.M{"username": username}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
 func (s *MongoUserStore) UpdatePassword(ctx context.Context, username string, newHash string) error {
 	result, err := s.collection().UpdateOne(
 		ctx,
@@ -72,9 +114,19 @@ func (s *MongoUserStore) UpdatePassword(ctx context.Context, username string, ne
 }
 
 func (s *MongoUserStore) EnsureIndexes(ctx context.Context) error {
-	_, err := s.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys:    bson.D{{Key: "username", Value: 1}},
-		Options: options.Index().SetUnique(true),
+	_, err := s.collection().Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "username", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "email", Value: 1}},
+		},
+		{
+			// Sparse: only users that signed in with GitHub carry the field.
+			Keys:    bson.D{{Key: "github_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetSparse(true),
+		},
 	})
 	return err
 }
@@ -85,5 +137,7 @@ type userDocument struct {
 	PasswordHash string        `bson:"password_hash"`
 	// Existing documents without this field decode to 0, matching tokens
 	// issued before versioning was introduced.
-	TokenVersion int64 `bson:"token_version"`
+	TokenVersion int64  `bson:"token_version"`
+	Email        string `bson:"email,omitempty"`
+	GithubID     string `bson:"github_id,omitempty"`
 }
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPasswordResetTokenNotFound = errors.New("password reset token not found")
+	// ErrPasswordResetTokenUsed is returned by MarkUsed when the token was
+	// already redeemed.
+	ErrPasswordResetTokenUsed = errors.New("password reset token already used")
+)
+
+// PasswordResetToken is the stored form of a reset link token. As with
+// refresh tokens only the hash is persisted.
+type PasswordResetToken struct {
+	TokenHash string
+	Username  string
+	// TokenVersion mirrors User.TokenVersion at issue time: any password
+	// change, including a completed reset, invalidates outstanding links.
+	TokenVersion int64
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	Used         bool
+}
+
+type PasswordResetStore interface {
+	Create(ctx context.Context, token *PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	// MarkUsed flags an unused token as used in a single operation, returning
+	// ErrPasswordResetTokenUsed otherwise, so a link can be redeemed once.
+	MarkUsed(ctx context.Context, tokenHash string) error
+}
+
+// NewPasswordResetToken returns a random token for the reset link and the
+// hash to store.
+func NewPasswordResetToken() (token, tokenHash string, err error) {
+	return newOpaqueToken()
+}
+
+// HashPasswordResetToken returns the lookup key stored for token.
+func HashPasswordResetToken(token string) string {
+	return hashOpaqueToken(token)
+}
+
+type MemoryPasswordResetStore struct {
+	mu     sync.Mutex
+	tokens map[string]*PasswordResetToken
+}
+
+func NewMemoryPasswordResetStore() *MemoryPasswordResetStore {
+	return &MemoryPasswordResetStore{
+		tokens: make(map[string]*PasswordResetToken),
+	}
+}
+
+func (s *MemoryPasswordResetStore) Create(ctx context.Context, token *PasswordResetToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := *token
+	s.tokens[token.TokenHash] = &t
+	return nil
+}
+
+func (s *MemoryPasswordResetStore) GetByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[tokenHash]
+	if !ok {
+		return nil, ErrPasswordResetTokenNotFound
+	}
+	out := *t
+	return &out, nil
+}
+
+func (s *MemoryPasswordResetStore) MarkUsed(ctx context.Context, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[tokenHash]
+	if !ok {
+		return ErrPasswordResetTokenNotFound
+	}
+	if t.Used {
+		return ErrPasswordResetTokenUsed
+	}
+	t.Used = true
+	return nil
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	// ErrRefreshTokenUsed is returned by MarkUsed when the token was already
+	// rotated or revoked, i.e. it is being replayed.
+	ErrRefreshTokenUsed = errors.New("refresh token already used")
+)
+
+// RefreshToken is the stored form of an opaque refresh token. Only the hash
+// is persisted, so a database leak does not hand out usable sessions.
+type RefreshToken struct {
+	TokenHash string
+	Username  string
+	// FamilyID is shared by every token rotated from the same login; reuse of
+	// any member revokes the whole family.
+	FamilyID string
+	// TokenVersion mirrors User.TokenVersion at issue time, so a password
+	// change also invalidates outstanding refresh tokens.
+	TokenVersion int64
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	Used         bool
+	Revoked      bool
+}
+
+type RefreshTokenStore interface {
+	Create(ctx context.Context, token *RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	// MarkUsed flags an unused, unrevoked token as used in a single
+	// operation, returning ErrRefreshTokenUsed otherwise, so two concurrent
+	// refreshes with the same token cannot both succeed.
+	MarkUsed(ctx context.Context, tokenHash string) error
+	RevokeFamily(ctx context.Context, familyID string) error
+}
+
+// NewRefreshToken returns a random opaque token for the client and the hash
+// to store.
+func NewRefreshToken() (token, tokenHash string, err error) {
+	return newOpaqueToken()
+}
+
+// HashRefreshToken returns the lookup key stored for token.
+func HashRefreshToken(token string) string {
+	return hashOpaqueToken(token)
+}
+
+func newOpaqueToken() (token, tokenHash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, hashOpaqueToken(token), nil
+}
+
+// hashOpaqueToken hashes a token from newOpaqueToken. The tokens are
+// high-entropy random values, so a plain SHA-256 is sufficient.
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewTokenFamilyID returns a random identifier for a new login's token chain.
+func NewTokenFamilyID() (string, error) {
+	return randomHex(16)
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type MemoryRefreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]*RefreshToken
+}
+
+func NewMemoryRefreshTokenStore() *MemoryRefreshTokenStore {
+	return &MemoryRefreshTokenStore{
+		tokens: make(map[string]*RefreshToken),
+	}
+}
+
+func (s *MemoryRefreshTokenStore) Create(ctx context.Context, token *RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := *token
+	s.tokens[token.TokenHash] = &t
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[tokenHash]
+	if !ok {
+		return nil, ErrRefreshTokenNotFound
+	}
+	out := *t
+	return &out, nil
+}
+
+func (s *MemoryRefreshTokenStore) MarkUsed(ctx context.Context, tokenHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[tokenHash]
+	if !ok {
+		return ErrRefreshTokenNotFound
+	}
+	if t.Used || t.Revoked {
+		return ErrRefreshTokenUsed
+	}
+	t.Used = true
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range s.tokens {
+		if t.FamilyID == familyID {
+			t.Revoked = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RevokedTokenStore records access tokens (by their jti claim) that were
+// logged out before they expired. An entry only needs to outlive the token
+// it revokes; after that the JWT's own exp claim rejects it.
+type RevokedTokenStore interface {
+	RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// NewTokenID returns a random jti for a new access token.
+func NewTokenID() (string, error) {
+	return randomHex(16)
+}
+
+type MemoryRevokedTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]time.Time
+}
+
+func NewMemoryRevokedTokenStore() *MemoryRevokedTokenStore {
+	return &MemoryRevokedTokenStore{
+		tokens: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryRevokedTokenStore) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[jti] = expiresAt
+	return nil
+}
+
+func (s *MemoryRevokedTokenStore) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	expiresAt, ok := s.tokens[jti]
+	return ok && time.Now().Before(expiresAt), nil
+}
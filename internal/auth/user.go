@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
 )
 
@@ -15,6 +16,11 @@ type User struct {
 	// TokenVersion is embedded in JWT claims at login; a password change
 	// bumps it, invalidating every previously issued token.
 	TokenVersion int64
+	// Email is stored lower-cased; OAuth logins with a matching verified
+	// address are linked to this user.
+	Email string
+	// GithubID is the numeric GitHub user id once the account is linked.
+	GithubID string
 }
 
 type UserStore interface {
@@ -23,6 +29,21 @@ type UserStore interface {
 	// UpdatePassword sets the new hash and bumps TokenVersion in the same
 	// operation so outstanding tokens are invalidated with the change.
 	UpdatePassword(ctx context.Context, username string, newHash string) error
+	GetByEmail(ctx context.Context, email string) (*User, error)
+}
+
+// OAuthUserStore adds the lookups needed to resolve an OAuth identity to a
+// local user.
+type OAuthUserStore interface {
+	UserStore
+	GetByGithubID(ctx context.Context, githubID string) (*User, error)
+	LinkGithub(ctx context.Context, username string, githubID string) error
+	SetEmail(ctx context.Context, username string, email string) error
+}
+
+// NormalizeEmail is the form emails are stored and compared in.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
 
 type MemoryUserStore struct {
@@ -64,3 +85,48 @@ func (s *MemoryUserStore) UpdatePassword(ctx context.Context, username string, n
 	u.TokenVersion++
 	return nil
 }
+
+func (s *MemoryUserStore) GetByEmail(ctx context.Context, email string) (*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	email = NormalizeEmail(email)
+	for _, u := range s.users {
+		if email != "" && u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (s *MemoryUserStore) GetByGithubID(ctx context.Context, githubID string) (*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, u := range s.users {
+		if githubID != "" && u.GithubID == githubID {
+			return u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (s *MemoryUserStore) LinkGithub(ctx context.Context, username string, githubID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[username]
+	if !ok {
+		return ErrUserNotFound
+	}
+	u.GithubID = githubID
+	return nil
+}
+
+func (s *MemoryUserStore) SetEmail(ctx context.Context, username string, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, ok := s.users[username]
+	if !ok {
+		return ErrUserNotFound
+	}
+	u.Email = NormalizeEmail(email)
+	return nil
+}
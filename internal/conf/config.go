@@ -19,12 +19,50 @@ type Config struct {
 	Webhook   *WebhookConfig
 	Auth      *AuthConfig
 	Storage   *StorageConfig
+	Locker    *LockerConfig
+	// Tokens 平台 access token 的存储设置
+	Tokens *TokensConfig
+	// HTTP 出站请求的超时、代理与 TLS 配置
+	HTTP *social.HTTPClientConfig
+}
+
+// TokensConfig controls how platform access tokens are stored.
+type TokensConfig struct {
+	// EncryptionKey encrypts access tokens at rest (AES-256-GCM, key derived
+	// with SHA-256). Empty stores them as plaintext; existing plaintext tokens
+	// are still read and get encrypted the next time they are saved.
+	EncryptionKey string `yaml:"encryption_key"`
+}
+
+// LockerConfig selects how sync and token refresh runs are kept from
+// overlapping.
+type LockerConfig struct {
+	// Type is "redis" (default), shared by every instance, or "memory" for a
+	// single instance without Redis.
+	Type string
 }
 
 type AuthConfig struct {
-	Username  string
-	Password  string
+	Username string
+	Password string
+	// Email of the seeded user; OAuth logins with this verified address are
+	// linked to it.
+	Email     string
 	JWTSecret string `yaml:"jwt_secret"`
+	Github    *GithubAuthConfig
+	// PasswordResetURL is the front-end page reset links point to; the token
+	// is appended as ?token=.
+	PasswordResetURL string `yaml:"password_reset_url"`
+}
+
+// GithubAuthConfig enables "Sign in with GitHub".
+type GithubAuthConfig struct {
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	RedirectURL  string `yaml:"redirect_url"`
+	// AllowedLogins may create a new account on first sign-in; other GitHub
+	// users must match an existing user's email.
+	AllowedLogins []string `yaml:"allowed_logins"`
 }
 
 type StorageConfig struct {
@@ -62,6 +100,44 @@ type SyncConfig struct {
 	TargetPlatforms []string
 	SkipPrivate     bool
 	SkipOlder       time.Duration
+	// CrossPostConcurrency limits how many target platforms a post is
+	// cross-posted to in parallel (default 3).
+	CrossPostConcurrency int
+	// PostRetryAttempts and PostRetryBaseDelay control in-cycle retries of
+	// transient cross-post failures (429/502/503/504, timeouts).
+	PostRetryAttempts  int
+	PostRetryBaseDelay time.Duration
+	// ResyncOnEdit pushes edits of already-synced source posts to targets
+	// that support updating.
+	ResyncOnEdit bool
+	// Filters restricts which source posts are cross-posted.
+	Filters *SyncFilters
+	// MaxMediaBytes caps media downloaded by URL before cross-posting
+	// (default 25MB).
+	MaxMediaBytes int64
+	// MediaCacheBytes, if set, keeps up to this many bytes of media fetched
+	// by URL in memory and revalidates them with ETag / Last-Modified on
+	// the next fetch instead of downloading them again.
+	MediaCacheBytes int64
+	// CircuitBreakerThreshold consecutive failures within
+	// CircuitBreakerWindow stop cross-posting to a platform for
+	// CircuitBreakerCooldown (defaults 5, 10m, 5m).
+	CircuitBreakerThreshold int
+	CircuitBreakerWindow    time.Duration
+	CircuitBreakerCooldown  time.Duration
+	// RequireAltText logs a warning for every synced attachment without
+	// alt text. Posts are still cross-posted.
+	RequireAltText bool
+	// MutePatterns skips source posts matching any pattern: "/regex/",
+	// "@handle" (a mention of that account) or a case-insensitive
+	// substring.
+	MutePatterns []string
+	// RecordRetention, if set, prunes synced and skipped sync_records older
+	// than this once an hour. RecordCleanup selects "delete" (default) or
+	// "archive", which only flags the records so they stay available for
+	// auditing.
+	RecordRetention time.Duration
+	RecordCleanup   string
 }
 
 // SchedulerConfig contains scheduler configuration
@@ -73,14 +149,18 @@ type SchedulerConfig struct {
 	RetryDelay         time.Duration
 	QueueSize          int
 	TaskTimeout        time.Duration
-	SchedulePatterns   []SchedulePattern
+	SchedulePatterns   []SchedulePattern `yaml:"schedule_patterns"`
 }
 
 // SchedulePattern defines a custom sync schedule
 type SchedulePattern struct {
-	Name      string
-	CronExpr  string
-	Enabled   bool
+	Name string
+	// CronExpr is a five-field cron expression ("*/5 * * * *") or a
+	// shorthand such as "@hourly"; see worker.ParseCron.
+	CronExpr string `yaml:"cron"`
+	Enabled  bool
+	// Platforms lists the source platforms to sync; empty means every
+	// platform with sync_to.
 	Platforms []string
 	Filters   *SyncFilters
 }
@@ -91,8 +171,31 @@ type SyncFilters struct {
 	SkipPrivate  bool
 	SkipOlder    time.Duration
 	MaxMemos     int
+	// IncludeTags, if set, only syncs posts carrying at least one of these
+	// hashtags; ExcludeTags skips posts carrying any of them. Tags match
+	// case-insensitively, with or without the leading '#'.
+	IncludeTags []string
+	ExcludeTags []string
+	// ContentMatch, if set, is a regular expression the post content must
+	// match.
+	ContentMatch string
 }
 
+// SignatureScheme selects how an incoming webhook request proves it knows
+// WebhookConfig.Secret.
+type SignatureScheme string
+
+const (
+	// SignatureHMACSHA256 is the default: hex HMAC-SHA256 of the body, as sent
+	// by HyperSync itself and by GitHub (X-Hub-Signature-256).
+	SignatureHMACSHA256 SignatureScheme = "hmac-sha256"
+	// SignatureHMACSHA1 is GitHub's legacy X-Hub-Signature.
+	SignatureHMACSHA1 SignatureScheme = "hmac-sha1"
+	// SignatureSharedSecret compares the header with the secret itself, as
+	// GitLab does with X-Gitlab-Token.
+	SignatureSharedSecret SignatureScheme = "shared-secret"
+)
+
 // WebhookConfig contains webhook configuration
 type WebhookConfig struct {
 	Enabled        bool
@@ -100,6 +203,21 @@ type WebhookConfig struct {
 	AllowedSources []string
 	TrustedIPs     []string
 	Timeout        time.Duration
+	// SignatureScheme defaults to hmac-sha256. SignatureHeader and
+	// SignaturePrefix override the scheme's default header and value prefix
+	// for sources that sign differently.
+	SignatureScheme SignatureScheme `yaml:"signature_scheme"`
+	SignatureHeader string          `yaml:"signature_header"`
+	SignaturePrefix string          `yaml:"signature_prefix"`
+	// OutgoingURLs receive a signed JSON summary after every sync run.
+	OutgoingURLs []string `yaml:"outgoing_urls"`
+	// DebounceWindow collapses Memos webhook events for a source into one
+	// sync run once no new event has arrived for this long. Defaults to 10s;
+	// a negative value syncs on every event.
+	DebounceWindow time.Duration `yaml:"debounce_window"`
+	// MaxBodyBytes caps inbound webhook bodies; larger requests are
+	// rejected. Defaults to 1MB.
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
 }
 
 func (c *Config) Print() {}
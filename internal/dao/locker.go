@@ -1,15 +1,169 @@
 package dao
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
 	bredis "butterfly.orx.me/core/store/redis"
 	"github.com/bsm/redislock"
 	"github.com/redis/go-redis/v9"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+)
+
+// Locker backends, see locker.type
+const (
+	LockerRedis  = "redis"
+	LockerMemory = "memory"
+)
+
+var (
+	// ErrLockNotObtained is returned by Locker.Obtain when another holder has the key.
+	ErrLockNotObtained = errors.New("lock not obtained")
+	// ErrLockNotHeld is returned by Lock.Refresh and Lock.Release after the lock expired.
+	ErrLockNotHeld = errors.New("lock not held")
 )
 
-func NewLocker(client *redis.Client) *redislock.Client {
-	return redislock.New(client)
+// Locker hands out locks that expire after a TTL unless refreshed, so a
+// crashed holder never blocks others for good.
+type Locker interface {
+	Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error)
+}
+
+// Lock is a lock obtained from a Locker.
+type Lock interface {
+	// Refresh extends the lock to ttl from now
+	Refresh(ctx context.Context, ttl time.Duration) error
+	// Release gives the lock up
+	Release(ctx context.Context) error
+}
+
+var (
+	memoryLockerOnce sync.Once
+	memoryLocker     *MemoryLocker
+)
+
+// NewLocker returns the locker selected by locker.type: Redis (default) for
+// deployments with several instances, or a process-wide in-memory locker for
+// a single instance without Redis.
+func NewLocker() Locker {
+	if conf.Conf.Locker != nil && conf.Conf.Locker.Type == LockerMemory {
+		memoryLockerOnce.Do(func() {
+			memoryLocker = NewMemoryLocker()
+		})
+		return memoryLocker
+	}
+	return NewRedisLocker(NewRedisClient())
 }
 
 func NewRedisClient() *redis.Client {
 	return bredis.GetClient("locker")
 }
+
+// RedisLocker is a Locker shared by every instance using the same Redis.
+type RedisLocker struct {
+	client *redislock.Client
+}
+
+// NewRedisLocker creates a Redis-backed locker
+func NewRedisLocker(client *redis.Client) *RedisLocker {
+	return &RedisLocker{client: redislock.New(client)}
+}
+
+// Obtain tries once to take key, without retrying.
+func (l *RedisLocker) Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	lock, err := l.client.Obtain(ctx, key, ttl, nil)
+	if err != nil {
+		if errors.Is(err, redislock.ErrNotObtained) {
+			return nil, fmt.Errorf("obtain %s: %w", key, ErrLockNotObtained)
+		}
+		return nil, fmt.Errorf("obtain %s: %w", key, err)
+	}
+	return &redisLock{lock: lock}, nil
+}
+
+type redisLock struct {
+	lock *redislock.Lock
+}
+
+func (l *redisLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	if err := l.lock.Refresh(ctx, ttl, nil); err != nil {
+		if errors.Is(err, redislock.ErrNotObtained) {
+			return ErrLockNotHeld
+		}
+		return err
+	}
+	return nil
+}
+
+func (l *redisLock) Release(ctx context.Context) error {
+	if err := l.lock.Release(ctx); err != nil {
+		if errors.Is(err, redislock.ErrLockNotHeld) {
+			return ErrLockNotHeld
+		}
+		return err
+	}
+	return nil
+}
+
+// MemoryLocker is a Locker for a single process. Locks are only exclusive
+// within the process, so it must not be used with several instances.
+type MemoryLocker struct {
+	mu    sync.Mutex
+	locks map[string]*memoryLock
+}
+
+// NewMemoryLocker creates an empty in-memory locker
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{locks: make(map[string]*memoryLock)}
+}
+
+// Obtain takes key unless it is held and not yet expired.
+func (l *MemoryLocker) Obtain(_ context.Context, key string, ttl time.Duration) (Lock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if held, ok := l.locks[key]; ok && time.Now().Before(held.expiresAt) {
+		return nil, fmt.Errorf("obtain %s: %w", key, ErrLockNotObtained)
+	}
+	lock := &memoryLock{locker: l, key: key, expiresAt: time.Now().Add(ttl)}
+	l.locks[key] = lock
+	return lock, nil
+}
+
+type memoryLock struct {
+	locker    *MemoryLocker
+	key       string
+	expiresAt time.Time // guarded by locker.mu
+}
+
+// held reports whether l is still the live holder of its key; locker.mu
+// must be held.
+func (l *memoryLock) held() bool {
+	return l.locker.locks[l.key] == l && time.Now().Before(l.expiresAt)
+}
+
+func (l *memoryLock) Refresh(_ context.Context, ttl time.Duration) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+
+	if !l.held() {
+		return ErrLockNotHeld
+	}
+	l.expiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+func (l *memoryLock) Release(_ context.Context) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+
+	if !l.held() {
+		return ErrLockNotHeld
+	}
+	delete(l.locker.locks, l.key)
+	return nil
+}
@@ -0,0 +1,84 @@
+package dao
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLocker_MutualExclusion(t *testing.T) {
+	locker := NewMemoryLocker()
+	ctx := context.Background()
+
+	lock, err := locker.Obtain(ctx, "sync_service:memos", time.Minute)
+	require.NoError(t, err)
+
+	_, err = locker.Obtain(ctx, "sync_service:memos", time.Minute)
+	assert.ErrorIs(t, err, ErrLockNotObtained)
+
+	other, err := locker.Obtain(ctx, "sync_service:rss", time.Minute)
+	require.NoError(t, err, "keys are locked independently")
+	require.NoError(t, other.Release(ctx))
+
+	require.NoError(t, lock.Release(ctx))
+	_, err = locker.Obtain(ctx, "sync_service:memos", time.Minute)
+	assert.NoError(t, err, "a released key can be obtained again")
+}
+
+func TestMemoryLocker_ConcurrentObtain(t *testing.T) {
+	locker := NewMemoryLocker()
+
+	var (
+		wg       sync.WaitGroup
+		obtained atomic.Int32
+	)
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := locker.Obtain(context.Background(), "token_refresh", time.Minute); err == nil {
+				obtained.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), obtained.Load())
+}
+
+func TestMemoryLocker_TTLExpiry(t *testing.T) {
+	locker := NewMemoryLocker()
+	ctx := context.Background()
+
+	stale, err := locker.Obtain(ctx, "key", 20*time.Millisecond)
+	require.NoError(t, err)
+	time.Sleep(40 * time.Millisecond)
+
+	fresh, err := locker.Obtain(ctx, "key", time.Minute)
+	require.NoError(t, err, "an expired lock no longer blocks others")
+
+	assert.ErrorIs(t, stale.Refresh(ctx, time.Minute), ErrLockNotHeld)
+	assert.ErrorIs(t, stale.Release(ctx), ErrLockNotHeld, "releasing an expired lock must not free the new holder's key")
+
+	_, err = locker.Obtain(ctx, "key", time.Minute)
+	assert.ErrorIs(t, err, ErrLockNotObtained)
+	require.NoError(t, fresh.Release(ctx))
+}
+
+func TestMemoryLocker_RefreshExtendsTTL(t *testing.T) {
+	locker := NewMemoryLocker()
+	ctx := context.Background()
+
+	lock, err := locker.Obtain(ctx, "key", 30*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, lock.Refresh(ctx, time.Minute))
+	time.Sleep(50 * time.Millisecond)
+
+	_, err = locker.Obtain(ctx, "key", time.Minute)
+	assert.ErrorIs(t, err, ErrLockNotObtained)
+}
@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrMediaNotFound is returned when a media id does not exist in GridFS.
+var ErrMediaNotFound = errors.New("media not found")
+
+// MediaDAO stores media bytes that have no URL of their own, such as
+// locally uploaded files or media downloaded for caching.
+type MediaDAO interface {
+	// StoreMedia saves data and returns its id
+	StoreMedia(ctx context.Context, filename, contentType string, data []byte) (string, error)
+
+	// GetMedia returns the data and content type stored under id
+	GetMedia(ctx context.Context, id string) ([]byte, string, error)
+
+	// DeleteMedia removes the media stored under id
+	DeleteMedia(ctx context.Context, id string) error
+}
+
+// Ensure MongoDAO implements MediaDAO interface
+var _ MediaDAO = (*MongoDAO)(nil)
+
+// mediaMetadata is the GridFS file metadata written by StoreMedia.
+type mediaMetadata struct {
+	ContentType string `bson:"content_type,omitempty"`
+}
+
+func (d *MongoDAO) mediaBucket() *mongo.GridFSBucket {
+	return d.Client.Database(d.Database).GridFSBucket(options.GridFSBucket().SetName(postMediaBucket))
+}
+
+// StoreMedia writes data to the post media GridFS bucket.
+func (d *MongoDAO) StoreMedia(ctx context.Context, filename, contentType string, data []byte) (string, error) {
+	if filename == "" {
+		filename = "media"
+	}
+	opts := options.GridFSUpload().SetMetadata(mediaMetadata{ContentType: contentType})
+	id, err := d.mediaBucket().UploadFromStream(ctx, filename, bytes.NewReader(data), opts)
+	if err != nil {
+		return "", fmt.Errorf("store media: %w", err)
+	}
+	return id.Hex(), nil
+}
+
+// GetMedia reads a file written by StoreMedia. The content type is "" for
+// files stored without one.
+func (d *MongoDAO) GetMedia(ctx context.Context, id string) ([]byte, string, error) {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, "", fmt.Errorf("get media %s: %w", id, ErrMediaNotFound)
+	}
+
+	stream, err := d.mediaBucket().OpenDownloadStream(ctx, objectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrFileNotFound) {
+			return nil, "", fmt.Errorf("get media %s: %w", id, ErrMediaNotFound)
+		}
+		return nil, "", fmt.Errorf("get media %s: %w", id, err)
+	}
+	defer stream.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(stream); err != nil {
+		return nil, "", fmt.Errorf("get media %s: %w", id, err)
+	}
+
+	var meta mediaMetadata
+	if raw := stream.GetFile().Metadata; len(raw) > 0 {
+		_ = bson.Unmarshal(raw, &meta)
+	}
+	return buf.Bytes(), meta.ContentType, nil
+}
+
+// DeleteMedia removes a file written by StoreMedia.
+func (d *MongoDAO) DeleteMedia(ctx context.Context, id string) error {
+	objectID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("delete media %s: %w", id, ErrMediaNotFound)
+	}
+	if err := d.mediaBucket().Delete(ctx, objectID); err != nil {
+		if errors.Is(err, mongo.ErrFileNotFound) {
+			return fmt.Errorf("delete media %s: %w", id, ErrMediaNotFound)
+		}
+		return fmt.Errorf("delete media %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package dao
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestMongoDAO_MediaRoundTrip(t *testing.T) {
+	postDao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mediaDao := postDao.(*MongoDAO)
+
+	id, err := mediaDao.StoreMedia(ctx, "cat.png", "image/png", []byte("png-bytes"))
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+
+	data, contentType, err := mediaDao.GetMedia(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("png-bytes"), data)
+	assert.Equal(t, "image/png", contentType)
+
+	require.NoError(t, mediaDao.DeleteMedia(ctx, id))
+
+	_, _, err = mediaDao.GetMedia(ctx, id)
+	assert.ErrorIs(t, err, ErrMediaNotFound)
+	assert.ErrorIs(t, mediaDao.DeleteMedia(ctx, id), ErrMediaNotFound)
+}
+
+func TestMongoDAO_GetMedia_InvalidID(t *testing.T) {
+	postDao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	_, _, err := postDao.(*MongoDAO).GetMedia(context.Background(), "not-an-id")
+	assert.ErrorIs(t, err, ErrMediaNotFound)
+}
+
+func TestMongoDAO_DeletePost_RemovesMedia(t *testing.T) {
+	postDao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	post := createTestPost()
+	post.Media = []social.Media{*social.NewMedia([]byte("image-bytes"))}
+
+	id, err := postDao.CreatePost(ctx, FromSocialPost(post))
+	require.NoError(t, err)
+	stored, err := postDao.GetPostByID(ctx, id)
+	require.NoError(t, err)
+	blobID := stored.Media[0].BlobID
+	require.NotEmpty(t, blobID)
+
+	require.NoError(t, postDao.DeletePost(ctx, id))
+
+	_, _, err = postDao.(*MongoDAO).GetMedia(ctx, blobID)
+	assert.ErrorIs(t, err, ErrMediaNotFound)
+}
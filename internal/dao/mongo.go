@@ -34,3 +34,11 @@ func NewSocialConfigDao(client *mongo.Client) SocialConfigDao {
 func NewSyncCursorDao(client *mongo.Client) social.SyncCursorDao {
 	return NewMongoDAO(client)
 }
+
+func NewSyncSettingsDao(client *mongo.Client) SyncSettingsDao {
+	return NewMongoDAO(client)
+}
+
+func NewSyncStateDao(client *mongo.Client) SyncStateDao {
+	return NewMongoDAO(client)
+}
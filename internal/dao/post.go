@@ -2,8 +2,14 @@ package dao
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"butterfly.orx.me/core/log"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -13,6 +19,8 @@ import (
 
 const (
 	postsCollection = "posts"
+	// postMediaBucket is the GridFS bucket holding data-only post media.
+	postMediaBucket = "post_media"
 )
 
 // PostDao defines the interface for post data access operations
@@ -26,12 +34,24 @@ type PostDao interface {
 	// GetBySocialAndSocialID retrieves a post by social platform and social ID
 	GetBySocialAndSocialID(ctx context.Context, social, socialID string) (*PostModel, error)
 
+	// GetPostByContentHash retrieves the latest post from a social platform
+	// with the content hash, created between from and to
+	GetPostByContentHash(ctx context.Context, social, contentHash string, from, to time.Time) (*PostModel, error)
+
+	// GetByCrossPostPlatformID retrieves the post that was cross-posted to
+	// platform and got platformID there
+	GetByCrossPostPlatformID(ctx context.Context, platform, platformID string) (*PostModel, error)
+
 	// ListPosts retrieves posts with optional filtering
 	ListPosts(ctx context.Context, filter map[string]interface{}, limit int64, skip int64) ([]*PostModel, error)
 
 	// CreatePost creates a new post and returns its ID
 	CreatePost(ctx context.Context, post *PostModel) (string, error)
 
+	// CreatePostIfNotExists creates post unless one with the same social and
+	// social ID exists, in which case it returns ErrPostExists
+	CreatePostIfNotExists(ctx context.Context, post *PostModel) (string, error)
+
 	// UpdatePost updates an existing post
 	UpdatePost(ctx context.Context, post *PostModel) error
 
@@ -45,18 +65,69 @@ type PostDao interface {
 // Ensure MongoDAO implements PostDao interface
 var _ PostDao = (*MongoDAO)(nil)
 
-// EnsureIndexes 创建 posts 集合所需的索引。
-// (social, social_id) 唯一索引用于保证同一来源帖子去重，防止并发/重试导致重复记录。
+// ErrPostExists is returned by CreatePostIfNotExists when the post is already
+// stored, e.g. inserted by a concurrent or interrupted sync.
+var ErrPostExists = errors.New("post already exists")
+
+// legacyContentHashIndex 是早期按 (source_platform, content_hash) 去重的唯一索引，
+// 会把内容相同的不同帖子合并成一条，EnsureIndexes 启动时将其删除。
+const legacyContentHashIndex = "uniq_source_platform_content_hash"
+
+// EnsureIndexes 创建 posts 与 sync_records 集合所需的索引，启动时调用。
+// (social, social_id) 唯一索引用于保证同一来源帖子去重，防止并发/重试导致重复记录；
+// (social, content_hash) 为普通索引，仅用于在时间窗口内按内容查找换了 ID 的帖子；
+// (source_platform, original_id) 和 created_at 分别服务 GetPostByOriginalID 与列表排序。
+// 索引已存在时 CreateMany 不做任何事，可重复调用。
 func (d *MongoDAO) EnsureIndexes(ctx context.Context) error {
 	collection := d.Client.Database(d.Database).Collection(postsCollection)
-	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys: bson.D{
-			{Key: "social", Value: 1},
-			{Key: "social_id", Value: 1},
+	names, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "social", Value: 1},
+				{Key: "social_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true).SetName("uniq_social_social_id"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "social", Value: 1},
+				{Key: "content_hash", Value: 1},
+			},
+			Options: options.Index().SetName("social_content_hash"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "source_platform", Value: 1},
+				{Key: "original_id", Value: 1},
+			},
+			Options: options.Index().SetName("source_platform_original_id"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("created_at_desc"),
 		},
-		Options: options.Index().SetUnique(true).SetName("uniq_social_social_id"),
 	})
-	return err
+	if err != nil {
+		return fmt.Errorf("failed to create %s indexes: %w", postsCollection, err)
+	}
+	log.FromContext(ctx).Info("Ensured indexes", "collection", postsCollection, "indexes", names)
+
+	// 内容相同的不同帖子（如重复发的「早安」）是合法的，旧版本建过的内容哈希唯一索引需要删除
+	if err := collection.Indexes().DropOne(ctx, legacyContentHashIndex); err != nil && !isIndexNotFound(err) {
+		return fmt.Errorf("failed to drop %s index %s: %w", postsCollection, legacyContentHashIndex, err)
+	}
+
+	return d.ensureSyncRecordIndexes(ctx)
+}
+
+// isIndexNotFound 判断 DropOne 的错误是否只是索引（或集合）不存在
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		// 26: NamespaceNotFound, 27: IndexNotFound
+		return cmdErr.HasErrorCode(26) || cmdErr.HasErrorCode(27)
+	}
+	return false
 }
 
 // PostModel represents a post in the database
@@ -68,10 +139,16 @@ type PostModel struct {
 	Visibility     string        `bson:"visibility"`
 	SourcePlatform string        `bson:"source_platform"`
 	OriginalID     string        `bson:"original_id"`
+	// SourceURL 源平台上原帖的公开链接，供 footer、模板和 webhook 使用
+	SourceURL string `bson:"source_url,omitempty"`
 	// Store media references instead of full data
-	MediaIDs  []string  `bson:"media_ids,omitempty"`
-	CreatedAt time.Time `bson:"created_at"`
-	UpdatedAt time.Time `bson:"updated_at"`
+	MediaIDs []string `bson:"media_ids,omitempty"`
+	// Media 保存帖子附件；URL 媒体直接记录地址，仅有数据的媒体存入 GridFS 并记录 BlobID
+	Media []PostMedia `bson:"media,omitempty"`
+	// ContentHash 归一化内容与媒体的 SHA-256，用于识别编辑过的帖子和换了 ID 的帖子
+	ContentHash string    `bson:"content_hash,omitempty"`
+	CreatedAt   time.Time `bson:"created_at"`
+	UpdatedAt   time.Time `bson:"updated_at"`
 	// Cross-posting status for each platform
 	CrossPostStatus map[string]CrossPostStatus `bson:"cross_post_status,omitempty"`
 }
@@ -84,17 +161,67 @@ type CrossPostStatus struct {
 	CrossPosted bool       `bson:"cross_posted"`
 	PostedAt    *time.Time `bson:"posted_at,omitempty"`
 	RetryCount  int        `bson:"retry_count,omitempty"` // 失败重试次数，用于限制无限重试
+	DryRun      bool       `bson:"dry_run,omitempty"`     // dry-run 模拟的成功，并未真正发帖
+	SkipReason  string     `bson:"skip_reason,omitempty"` // 非空表示平台跳过了该帖子（如可见性不受支持），并未发帖，同步不会重试
+}
+
+// PostMedia is a media attachment stored with a post. Exactly one of URL or
+// BlobID locates the content.
+type PostMedia struct {
+	URL         string `bson:"url,omitempty"`
+	Description string `bson:"description,omitempty"`
+	// BlobID is the GridFS file id of media that had no URL (e.g. uploaded bytes).
+	BlobID string `bson:"blob_id,omitempty"`
+
+	// data holds bytes not yet written to GridFS.
+	data []byte
+	// load reads BlobID from GridFS; set on posts read from the database so
+	// the bytes are only fetched when the media is actually used.
+	load func() ([]byte, error)
+}
+
+// ContentHash returns the hex SHA-256 of a post's normalized content and
+// media. Line endings and surrounding whitespace are normalized so cosmetic
+// differences between platforms don't count as edits. Posts with neither
+// content nor media hash to "", so they never collide on the unique
+// content-hash index.
+func ContentHash(post *social.Post) string {
+	content := strings.ReplaceAll(post.Content, "\r\n", "\n")
+	if strings.TrimSpace(content) == "" && len(post.Media) == 0 {
+		return ""
+	}
+
+	h := sha256.New()
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	h.Write([]byte(strings.Join(lines, "\n")))
+
+	for i := range post.Media {
+		h.Write([]byte{0})
+		if url := post.Media[i].GetURL(); url != "" {
+			h.Write([]byte(url))
+		} else if data, err := post.Media[i].GetData(); err == nil {
+			sum := sha256.Sum256(data)
+			h.Write(sum[:])
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 // FromSocialPost converts a social.Post to a PostModel
 func FromSocialPost(post *social.Post) *PostModel {
 	now := time.Now()
 	return &PostModel{
-		Content:        post.Content,
-		Visibility:     post.Visibility.String(), // Convert enum to string
-		SourcePlatform: post.SourcePlatform,
-		OriginalID:     post.OriginalID,
-		// Media will be stored separately
+		Content:         post.Content,
+		Visibility:      post.Visibility.String(), // Convert enum to string
+		SourcePlatform:  post.SourcePlatform,
+		OriginalID:      post.OriginalID,
+		SourceURL:       post.SourceURL,
+		Media:           fromSocialMedia(post.Media),
+		ContentHash:     ContentHash(post),
 		CreatedAt:       now,
 		UpdatedAt:       now,
 		CrossPostStatus: make(map[string]CrossPostStatus),
@@ -116,10 +243,57 @@ func (p *PostModel) ToSocialPost() *social.Post {
 		Visibility:     visibility,
 		SourcePlatform: p.SourcePlatform,
 		OriginalID:     p.OriginalID,
-		// Media will need to be loaded separately
+		SourceURL:      p.SourceURL,
+		Media:          p.toSocialMedia(),
 	}
 }
 
+func fromSocialMedia(media []social.Media) []PostMedia {
+	if len(media) == 0 {
+		return nil
+	}
+	result := make([]PostMedia, 0, len(media))
+	for i := range media {
+		m := PostMedia{
+			URL:         media[i].GetURL(),
+			Description: media[i].Description,
+		}
+		if m.URL == "" {
+			// Without a URL GetData just returns the in-memory bytes.
+			data, err := media[i].GetData()
+			if err != nil || len(data) == 0 {
+				continue
+			}
+			m.data = data
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+// toSocialMedia rebuilds post media. Blob media load their bytes from GridFS
+// on first use; blob media of a model that was not read from the database
+// are skipped.
+func (p *PostModel) toSocialMedia() []social.Media {
+	var result []social.Media
+	for _, m := range p.Media {
+		var media *social.Media
+		switch {
+		case m.URL != "":
+			media = social.NewMediaFromURL(m.URL)
+		case m.data != nil:
+			media = social.NewMedia(m.data)
+		case m.load != nil:
+			media = social.NewMediaFromLoader(m.load)
+		default:
+			continue
+		}
+		media.Description = m.Description
+		result = append(result, *media)
+	}
+	return result
+}
+
 // GetPostByID retrieves a post by its ID
 func (d *MongoDAO) GetPostByID(ctx context.Context, id string) (*PostModel, error) {
 	// Convert string ID to ObjectID
@@ -141,6 +315,7 @@ func (d *MongoDAO) GetPostByID(ctx context.Context, id string) (*PostModel, erro
 		return nil, err
 	}
 
+	d.attachMediaLoaders(ctx, post)
 	return post, nil
 }
 
@@ -162,6 +337,7 @@ func (d *MongoDAO) GetPostByOriginalID(ctx context.Context, platform, originalID
 		return nil, err
 	}
 
+	d.attachMediaLoaders(ctx, post)
 	return post, nil
 }
 
@@ -183,6 +359,56 @@ func (d *MongoDAO) GetBySocialAndSocialID(ctx context.Context, social, socialID
 		return nil, err
 	}
 
+	d.attachMediaLoaders(ctx, post)
+	return post, nil
+}
+
+// GetPostByContentHash retrieves the most recent post from a social platform
+// with the given content hash created between from and to. Different posts
+// may share a content hash, so callers bound the lookup to a time window.
+func (d *MongoDAO) GetPostByContentHash(ctx context.Context, social, contentHash string, from, to time.Time) (*PostModel, error) {
+	// Get the posts collection
+	collection := d.Client.Database(d.Database).Collection(postsCollection)
+
+	// Find the post; identical content outside the window is a different post
+	post := &PostModel{}
+	opts := options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	err := collection.FindOne(ctx, bson.M{
+		"social":       social,
+		"content_hash": contentHash,
+		"created_at":   bson.M{"$gte": from, "$lte": to},
+	}, opts).Decode(post)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+
+	d.attachMediaLoaders(ctx, post)
+	return post, nil
+}
+
+// GetByCrossPostPlatformID retrieves the post whose cross-post to platform
+// produced platformID. Used to recognize our own cross-posts when the target
+// is also a sync source.
+func (d *MongoDAO) GetByCrossPostPlatformID(ctx context.Context, platform, platformID string) (*PostModel, error) {
+	// Get the posts collection
+	collection := d.Client.Database(d.Database).Collection(postsCollection)
+
+	// Find the post
+	post := &PostModel{}
+	err := collection.FindOne(ctx, bson.M{
+		"cross_post_status." + platform + ".platform_id": platformID,
+	}).Decode(post)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+
+	d.attachMediaLoaders(ctx, post)
 	return post, nil
 }
 
@@ -220,6 +446,9 @@ func (d *MongoDAO) ListPosts(ctx context.Context, filter map[string]interface{},
 	if err := cursor.All(ctx, &posts); err != nil {
 		return nil, err
 	}
+	for _, post := range posts {
+		d.attachMediaLoaders(ctx, post)
+	}
 
 	return posts, nil
 }
@@ -236,6 +465,10 @@ func (d *MongoDAO) CreatePost(ctx context.Context, post *PostModel) (string, err
 	}
 	post.UpdatedAt = now
 
+	if err := d.storeMediaBlobs(ctx, post); err != nil {
+		return "", err
+	}
+
 	// Insert the post
 	result, err := collection.InsertOne(ctx, post)
 	if err != nil {
@@ -246,6 +479,48 @@ func (d *MongoDAO) CreatePost(ctx context.Context, post *PostModel) (string, err
 	return result.InsertedID.(bson.ObjectID).Hex(), nil
 }
 
+// CreatePostIfNotExists inserts post with an upsert keyed on
+// (social, social_id). When the post is already stored, or a concurrent
+// insert wins the race on the unique index, it returns ErrPostExists.
+func (d *MongoDAO) CreatePostIfNotExists(ctx context.Context, post *PostModel) (string, error) {
+	collection := d.Client.Database(d.Database).Collection(postsCollection)
+
+	now := time.Now()
+	if post.CreatedAt.IsZero() {
+		post.CreatedAt = now
+	}
+	post.UpdatedAt = now
+
+	filter := bson.M{"social": post.Social, "social_id": post.SocialID}
+
+	var stored []int
+	for i, m := range post.Media {
+		if m.BlobID == "" {
+			stored = append(stored, i)
+		}
+	}
+	if err := d.storeMediaBlobs(ctx, post); err != nil {
+		return "", err
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$setOnInsert": post}, options.UpdateOne().SetUpsert(true))
+	if err == nil && result.UpsertedID != nil {
+		return result.UpsertedID.(bson.ObjectID).Hex(), nil
+	}
+
+	// 未插入：清理刚写入 GridFS 的媒体
+	for _, i := range stored {
+		if id := post.Media[i].BlobID; id != "" {
+			_ = d.DeleteMedia(ctx, id)
+			post.Media[i].BlobID = ""
+		}
+	}
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return "", err
+	}
+	return "", ErrPostExists
+}
+
 // UpdatePost updates an existing post
 func (d *MongoDAO) UpdatePost(ctx context.Context, post *PostModel) error {
 	// Get the posts collection
@@ -254,6 +529,10 @@ func (d *MongoDAO) UpdatePost(ctx context.Context, post *PostModel) error {
 	// Update timestamp
 	post.UpdatedAt = time.Now()
 
+	if err := d.storeMediaBlobs(ctx, post); err != nil {
+		return err
+	}
+
 	// Update the post
 	_, err := collection.ReplaceOne(ctx, bson.M{"_id": post.ID}, post)
 	return err
@@ -270,9 +549,24 @@ func (d *MongoDAO) DeletePost(ctx context.Context, id string) error {
 	// Get the posts collection
 	collection := d.Client.Database(d.Database).Collection(postsCollection)
 
-	// Delete the post
-	_, err = collection.DeleteOne(ctx, bson.M{"_id": objectID})
-	return err
+	// Delete the post, then the GridFS media only it referenced
+	var post PostModel
+	err = collection.FindOneAndDelete(ctx, bson.M{"_id": objectID}).Decode(&post)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+	for _, m := range post.Media {
+		if m.BlobID == "" {
+			continue
+		}
+		if err := d.DeleteMedia(ctx, m.BlobID); err != nil && !errors.Is(err, ErrMediaNotFound) {
+			return err
+		}
+	}
+	return nil
 }
 
 // UpdateCrossPostStatus updates the cross-post status for a platform
@@ -299,3 +593,38 @@ func (d *MongoDAO) UpdateCrossPostStatus(ctx context.Context, postID, platform s
 	)
 	return err
 }
+
+// storeMediaBlobs moves the bytes of data-only media into GridFS so the post
+// document only carries a reference.
+func (d *MongoDAO) storeMediaBlobs(ctx context.Context, post *PostModel) error {
+	for i := range post.Media {
+		m := &post.Media[i]
+		if m.URL != "" || m.BlobID != "" || m.data == nil {
+			continue
+		}
+		id, err := d.StoreMedia(ctx, "media", "", m.data)
+		if err != nil {
+			return err
+		}
+		m.BlobID = id
+	}
+	return nil
+}
+
+// attachMediaLoaders lets ToSocialPost fetch GridFS-backed media lazily. The
+// loaders outlive the request that read the post, so they keep ctx's values
+// but not its cancellation.
+func (d *MongoDAO) attachMediaLoaders(ctx context.Context, post *PostModel) {
+	ctx = context.WithoutCancel(ctx)
+	for i := range post.Media {
+		m := &post.Media[i]
+		if m.BlobID == "" || m.data != nil {
+			continue
+		}
+		id := m.BlobID
+		m.load = func() ([]byte, error) {
+			data, _, err := d.GetMedia(ctx, id)
+			return data, err
+		}
+	}
+}
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -45,6 +46,7 @@ func createTestPost() *social.Post {
 		Visibility:     social.VisibilityLevelPublic,
 		SourcePlatform: "test_platform",
 		OriginalID:     "test_original_id",
+		SourceURL:      "https://memos.example.com/m/test_original_id",
 	}
 }
 
@@ -56,6 +58,7 @@ func TestFromSocialPost(t *testing.T) {
 	assert.Equal(t, post.Visibility.String(), model.Visibility)
 	assert.Equal(t, post.SourcePlatform, model.SourcePlatform)
 	assert.Equal(t, post.OriginalID, model.OriginalID)
+	assert.Equal(t, post.SourceURL, model.SourceURL)
 	assert.NotZero(t, model.CreatedAt)
 	assert.NotZero(t, model.UpdatedAt)
 	assert.NotNil(t, model.CrossPostStatus)
@@ -73,6 +76,154 @@ func TestPostModel_ToSocialPost(t *testing.T) {
 	assert.Equal(t, model.Visibility, convertedPost.Visibility.String())
 	assert.Equal(t, model.SourcePlatform, convertedPost.SourcePlatform)
 	assert.Equal(t, model.OriginalID, convertedPost.OriginalID)
+	assert.Equal(t, model.SourceURL, convertedPost.SourceURL)
+}
+
+func TestPostModel_VisibilityRoundTrip(t *testing.T) {
+	levels := []social.VisibilityLevel{
+		social.VisibilityLevelPublic,
+		social.VisibilityLevelUnlisted,
+		social.VisibilityLevelPrivate,
+		social.VisibilityLevelDirect,
+	}
+
+	for _, level := range levels {
+		t.Run(level.String(), func(t *testing.T) {
+			model := FromSocialPost(&social.Post{Content: "test", Visibility: level})
+			assert.Equal(t, level.String(), model.Visibility)
+			assert.Equal(t, level, model.ToSocialPost().Visibility)
+		})
+	}
+}
+
+func TestPostModel_ToSocialPost_InvalidVisibilityDefaultsToPublic(t *testing.T) {
+	model := &PostModel{Content: "test", Visibility: "not-a-level"}
+	assert.Equal(t, social.VisibilityLevelPublic, model.ToSocialPost().Visibility)
+}
+
+func TestPostModel_MediaRoundTrip_URL(t *testing.T) {
+	post := createTestPost()
+	media := social.NewMediaFromURL("https://cdn.example.com/a.jpg")
+	media.Description = "a cat"
+	post.Media = []social.Media{*media}
+
+	model := FromSocialPost(post)
+	require.Len(t, model.Media, 1)
+	assert.Equal(t, "https://cdn.example.com/a.jpg", model.Media[0].URL)
+	assert.Empty(t, model.Media[0].BlobID)
+
+	converted := model.ToSocialPost()
+	require.Len(t, converted.Media, 1)
+	assert.Equal(t, "https://cdn.example.com/a.jpg", converted.Media[0].GetURL())
+	assert.Equal(t, "a cat", converted.Media[0].Description)
+}
+
+func TestPostModel_MediaRoundTrip_Data(t *testing.T) {
+	post := createTestPost()
+	post.Media = []social.Media{*social.NewMedia([]byte("image-bytes"))}
+
+	model := FromSocialPost(post)
+	require.Len(t, model.Media, 1)
+	assert.Empty(t, model.Media[0].URL)
+
+	converted := model.ToSocialPost()
+	require.Len(t, converted.Media, 1)
+	data, err := converted.Media[0].GetData()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("image-bytes"), data)
+}
+
+func TestMongoDAO_MediaBlobRoundTrip(t *testing.T) {
+	postDao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	post := createTestPost()
+	post.Media = []social.Media{
+		*social.NewMediaFromURL("https://cdn.example.com/a.jpg"),
+		*social.NewMedia([]byte("image-bytes")),
+	}
+
+	id, err := postDao.CreatePost(ctx, FromSocialPost(post))
+	require.NoError(t, err)
+
+	stored, err := postDao.GetPostByID(ctx, id)
+	require.NoError(t, err)
+	require.Len(t, stored.Media, 2)
+	assert.NotEmpty(t, stored.Media[1].BlobID, "data-only media should be stored in GridFS")
+	assert.Nil(t, stored.Media[1].data, "blob media should not be read until used")
+
+	converted := stored.ToSocialPost()
+	require.Len(t, converted.Media, 2)
+	assert.Equal(t, "https://cdn.example.com/a.jpg", converted.Media[0].GetURL())
+	data, err := converted.Media[1].GetData()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("image-bytes"), data)
+}
+
+func TestContentHash(t *testing.T) {
+	base := ContentHash(&social.Post{Content: "hello\nworld"})
+
+	assert.Equal(t, base, ContentHash(&social.Post{Content: "  hello  \r\nworld\n"}), "whitespace and line endings are normalized")
+	assert.NotEqual(t, base, ContentHash(&social.Post{Content: "hello\nworld!"}))
+	assert.NotEqual(t, base, ContentHash(&social.Post{
+		Content: "hello\nworld",
+		Media:   []social.Media{*social.NewMediaFromURL("https://cdn.example.com/a.jpg")},
+	}), "media is part of the hash")
+	assert.Equal(t, base, FromSocialPost(&social.Post{Content: "hello\nworld"}).ContentHash)
+	assert.Empty(t, ContentHash(&social.Post{Content: " \n"}), "empty posts have no hash")
+}
+
+func TestMongoDAO_GetPostByContentHash(t *testing.T) {
+	postDao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	createdAt := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+	model := FromSocialPost(createTestPost())
+	model.Social = "memos"
+	model.SocialID = "1"
+	model.CreatedAt = createdAt
+	_, err := postDao.CreatePost(ctx, model)
+	require.NoError(t, err)
+
+	from, to := createdAt.Add(-time.Minute), createdAt.Add(time.Minute)
+	found, err := postDao.GetPostByContentHash(ctx, "memos", model.ContentHash, from, to)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "1", found.SocialID)
+
+	notFound, err := postDao.GetPostByContentHash(ctx, "mastodon", model.ContentHash, from, to)
+	require.NoError(t, err)
+	assert.Nil(t, notFound)
+
+	outsideWindow, err := postDao.GetPostByContentHash(ctx, "memos", model.ContentHash, time.Now().Add(-time.Minute), time.Now())
+	require.NoError(t, err)
+	assert.Nil(t, outsideWindow, "same content posted earlier is a different post")
+}
+
+func TestMongoDAO_GetByCrossPostPlatformID(t *testing.T) {
+	postDao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	model := FromSocialPost(createTestPost())
+	model.Social = "memos-a"
+	model.SocialID = "1"
+	id, err := postDao.CreatePost(ctx, model)
+	require.NoError(t, err)
+	require.NoError(t, postDao.UpdateCrossPostStatus(ctx, id, "memos-b", CrossPostStatus{
+		Success: true, CrossPosted: true, PlatformID: "memos/xyz",
+	}))
+
+	found, err := postDao.GetByCrossPostPlatformID(ctx, "memos-b", "memos/xyz")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, "1", found.SocialID)
+
+	notFound, err := postDao.GetByCrossPostPlatformID(ctx, "mastodon", "memos/xyz")
+	require.NoError(t, err)
+	assert.Nil(t, notFound)
 }
 
 func TestMongoDAO_CreateAndGetPost(t *testing.T) {
@@ -220,3 +371,115 @@ func TestMongoDAO_UpdateCrossPostStatus(t *testing.T) {
 	assert.True(t, updatedPost.CrossPostStatus[platform].Success)
 	assert.Equal(t, "twitter_post_id", updatedPost.CrossPostStatus[platform].PlatformID)
 }
+
+// indexUniqueness maps each index name of collection to whether it is unique.
+func indexUniqueness(t *testing.T, d *MongoDAO, collection string) map[string]bool {
+	t.Helper()
+	specs, err := d.Client.Database(d.Database).Collection(collection).Indexes().ListSpecifications(context.Background())
+	require.NoError(t, err)
+
+	result := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		result[spec.Name] = spec.Unique != nil && *spec.Unique
+	}
+	return result
+}
+
+func TestMongoDAO_EnsureIndexes(t *testing.T) {
+	postDao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mongoDao := postDao.(*MongoDAO)
+	ctx := context.Background()
+
+	// An older deployment created a unique content-hash index; it must be dropped.
+	_, err := mongoDao.Client.Database(mongoDao.Database).Collection(postsCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "source_platform", Value: 1}, {Key: "content_hash", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName(legacyContentHashIndex),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mongoDao.EnsureIndexes(ctx))
+	// Idempotent: a second call at the next startup must not fail.
+	require.NoError(t, mongoDao.EnsureIndexes(ctx))
+
+	posts := indexUniqueness(t, mongoDao, postsCollection)
+	assert.Equal(t, true, posts["uniq_social_social_id"])
+	assert.Contains(t, posts, "social_content_hash")
+	assert.Equal(t, false, posts["social_content_hash"], "identical content from different posts is allowed")
+	assert.NotContains(t, posts, legacyContentHashIndex)
+	assert.Contains(t, posts, "source_platform_original_id")
+	assert.Contains(t, posts, "created_at_desc")
+
+	records := indexUniqueness(t, mongoDao, syncRecordsCollection)
+	assert.Equal(t, true, records["uniq_source_platform_source_id"])
+	assert.Contains(t, records, "status_created_at")
+	assert.Contains(t, records, "archived_created_at")
+}
+
+func TestMongoDAO_CreatePostIfNotExists_Concurrent(t *testing.T) {
+	postDao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, postDao.(*MongoDAO).EnsureIndexes(ctx))
+
+	var (
+		wg   sync.WaitGroup
+		ids  [2]string
+		errs [2]error
+	)
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			post := FromSocialPost(createTestPost())
+			post.Social = "memos"
+			post.SocialID = "memo-1"
+			ids[i], errs[i] = postDao.CreatePostIfNotExists(ctx, post)
+		}()
+	}
+	wg.Wait()
+
+	var created int
+	for i := range 2 {
+		if errs[i] == nil {
+			created++
+			assert.NotEmpty(t, ids[i])
+		} else {
+			assert.ErrorIs(t, errs[i], ErrPostExists)
+		}
+	}
+	assert.Equal(t, 1, created, "exactly one insert must win")
+
+	posts, err := postDao.ListPosts(ctx, nil, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, posts, 1)
+}
+
+func TestMongoDAO_CreatePostIfNotExists_SameContentDifferentPosts(t *testing.T) {
+	postDao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, postDao.(*MongoDAO).EnsureIndexes(ctx))
+
+	// Two memos with the same text are distinct posts
+	for _, socialID := range []string{"a", "b"} {
+		post := FromSocialPost(&social.Post{SourcePlatform: "memos", Content: "good morning"})
+		post.Social = "memos"
+		post.SocialID = socialID
+		_, err := postDao.CreatePostIfNotExists(ctx, post)
+		require.NoError(t, err, "posts dedupe by social ID, not by content")
+	}
+
+	post := FromSocialPost(&social.Post{SourcePlatform: "memos", Content: "edited"})
+	post.Social = "memos"
+	post.SocialID = "a"
+	_, err := postDao.CreatePostIfNotExists(ctx, post)
+	assert.ErrorIs(t, err, ErrPostExists)
+
+	posts, err := postDao.ListPosts(ctx, nil, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, posts, 2)
+}
@@ -6,6 +6,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 	"go.orx.me/apps/hyper-sync/internal/social"
 )
 
@@ -77,44 +78,24 @@ func (d *MongoDAO) GetConfigByPlatform(ctx context.Context, platform string) (*S
 func (d *MongoDAO) UpdatePlatformToken(ctx context.Context, platform, accessToken string, expiresAt *time.Time) error {
 	collection := d.Client.Database(d.Database).Collection(socialConfigCollection)
 
-	// 先尝试获取现有记录
-	existingConfig, err := d.GetConfigByPlatform(ctx, platform)
-	if err != nil {
-		return err
-	}
-
 	now := time.Now()
+	set := bson.M{
+		"config.access_token": accessToken,
+		"updated_at":          now,
+	}
+	if expiresAt != nil {
+		set["config.expires_at"] = *expiresAt
+	}
 
-	if existingConfig != nil {
-		// 记录存在，执行更新
-		filter := bson.M{"platform": platform}
-		update := bson.M{
-			"$set": bson.M{
-				"config.access_token": accessToken,
-				"updated_at":          now,
-			},
-		}
-
-		if expiresAt != nil {
-			update["$set"].(bson.M)["config.expires_at"] = *expiresAt
-		}
-
-		_, err := collection.UpdateOne(ctx, filter, update)
-		return err
-	} else {
-		// 记录不存在，创建新记录
-		newConfig := &SocialConfigModel{
-			Platform: platform,
-			UserID:   0, // 默认为0，可以根据需要修改
-			Config: SocialConfig{
-				AccessToken: accessToken,
-				ExpiresAt:   expiresAt,
-			},
-			CreatedAt: now,
-			UpdatedAt: now,
-		}
-
-		_, err := collection.InsertOne(ctx, newConfig)
-		return err
+	// upsert：记录不存在时创建，一次写入避免并发刷新时重复插入
+	filter := bson.M{"platform": platform}
+	update := bson.M{
+		"$set": set,
+		"$setOnInsert": bson.M{
+			"user_id":    int64(0), // 默认为0，可以根据需要修改
+			"created_at": now,
+		},
 	}
+	_, err := collection.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	return err
 }
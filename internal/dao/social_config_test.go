@@ -7,6 +7,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockSocialConfigDao for testing
@@ -110,3 +111,47 @@ func TestSocialConfig_Fields(t *testing.T) {
 		assert.Nil(t, config.ExpiresAt)
 	})
 }
+
+func TestMongoDAO_UpdatePlatformToken_RoundTrip(t *testing.T) {
+	dao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mongoDao := dao.(*MongoDAO)
+	ctx := context.Background()
+
+	expiresAt := time.Now().Add(60 * 24 * time.Hour).Truncate(time.Millisecond).UTC()
+	require.NoError(t, mongoDao.UpdatePlatformToken(ctx, "threads", "token-1", &expiresAt))
+
+	config, err := mongoDao.GetConfigByPlatform(ctx, "threads")
+	require.NoError(t, err)
+	require.NotNil(t, config)
+	assert.Equal(t, "token-1", config.Config.AccessToken)
+	assert.True(t, expiresAt.Equal(*config.Config.ExpiresAt))
+	assert.False(t, config.CreatedAt.IsZero())
+}
+
+func TestMongoDAO_UpdatePlatformToken_Overwrite(t *testing.T) {
+	dao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mongoDao := dao.(*MongoDAO)
+	ctx := context.Background()
+
+	first := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+	second := time.Now().Add(60 * 24 * time.Hour).Truncate(time.Millisecond)
+	require.NoError(t, mongoDao.UpdatePlatformToken(ctx, "threads", "old", &first))
+	created, err := mongoDao.GetConfigByPlatform(ctx, "threads")
+	require.NoError(t, err)
+
+	require.NoError(t, mongoDao.UpdatePlatformToken(ctx, "threads", "new", &second))
+	config, err := mongoDao.GetConfigByPlatform(ctx, "threads")
+	require.NoError(t, err)
+	assert.Equal(t, "new", config.Config.AccessToken)
+	assert.True(t, second.Equal(*config.Config.ExpiresAt))
+	assert.Equal(t, created.ID, config.ID, "the record is updated in place")
+	assert.True(t, created.CreatedAt.Equal(config.CreatedAt))
+
+	count, err := mongoDao.Client.Database(mongoDao.Database).Collection(socialConfigCollection).CountDocuments(ctx, map[string]string{"platform": "threads"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"butterfly.orx.me/core/log"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -33,6 +34,9 @@ type SyncRecordModel struct {
 	// Metadata about the original content
 	ContentHash    string `bson:"content_hash,omitempty"`    // Hash of content to detect changes
 	ContentPreview string `bson:"content_preview,omitempty"` // First 100 chars for preview
+	// Archived records are kept for auditing but hidden from listings and
+	// status counts by default
+	Archived bool `bson:"archived,omitempty"`
 }
 
 // SyncTargetStatus tracks sync status for each target platform
@@ -52,6 +56,60 @@ const (
 	SyncStatusSkipped = "skipped"
 )
 
+// Sync record cleanup modes, see sync.record_cleanup
+const (
+	SyncRecordCleanupDelete  = "delete"
+	SyncRecordCleanupArchive = "archive"
+)
+
+// ensureSyncRecordIndexes creates the sync_records indexes: (source_platform,
+// source_id) unique for GetSyncRecordBySource, (status, created_at) for
+// status listings and (archived, created_at) for archive-aware listings.
+func (d *MongoDAO) ensureSyncRecordIndexes(ctx context.Context) error {
+	collection := d.Client.Database(d.Database).Collection(syncRecordsCollection)
+	names, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "source_platform", Value: 1},
+				{Key: "source_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true).SetName("uniq_source_platform_source_id"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+			Options: options.Index().SetName("status_created_at"),
+		},
+		{
+			Keys: bson.D{
+				{Key: "archived", Value: 1},
+				{Key: "created_at", Value: 1},
+			},
+			Options: options.Index().SetName("archived_created_at"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s indexes: %w", syncRecordsCollection, err)
+	}
+	log.FromContext(ctx).Info("Ensured indexes", "collection", syncRecordsCollection, "indexes", names)
+	return nil
+}
+
+// withArchivedFilter returns filter restricted to unarchived records unless
+// includeArchived is set or filter already matches on archived itself.
+func withArchivedFilter(filter bson.M, includeArchived bool) bson.M {
+	result := bson.M{}
+	for k, v := range filter {
+		result[k] = v
+	}
+	if _, ok := result["archived"]; !ok && !includeArchived {
+		result["archived"] = bson.M{"$ne": true}
+	}
+	return result
+}
+
 // GetSyncRecord retrieves a sync record by ID
 func (d *MongoDAO) GetSyncRecord(ctx context.Context, id string) (*SyncRecordModel, error) {
 	objectID, err := bson.ObjectIDFromHex(id)
@@ -92,8 +150,9 @@ func (d *MongoDAO) GetSyncRecordBySource(ctx context.Context, sourcePlatform, so
 	return record, nil
 }
 
-// ListSyncRecords retrieves sync records with optional filtering
-func (d *MongoDAO) ListSyncRecords(ctx context.Context, filter bson.M, limit int64, skip int64) ([]*SyncRecordModel, error) {
+// ListSyncRecords retrieves sync records with optional filtering. Archived
+// records are only returned when includeArchived is set.
+func (d *MongoDAO) ListSyncRecords(ctx context.Context, filter bson.M, limit int64, skip int64, includeArchived bool) ([]*SyncRecordModel, error) {
 	collection := d.Client.Database(d.Database).Collection(syncRecordsCollection)
 
 	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
@@ -104,11 +163,7 @@ func (d *MongoDAO) ListSyncRecords(ctx context.Context, filter bson.M, limit int
 		opts.SetSkip(skip)
 	}
 
-	if filter == nil {
-		filter = bson.M{}
-	}
-
-	cursor, err := collection.Find(ctx, filter, opts)
+	cursor, err := collection.Find(ctx, withArchivedFilter(filter, includeArchived), opts)
 	if err != nil {
 		return nil, err
 	}
@@ -122,6 +177,54 @@ func (d *MongoDAO) ListSyncRecords(ctx context.Context, filter bson.M, limit int
 	return records, nil
 }
 
+// CountSyncRecords counts sync records matching filter, e.g. the total for
+// a paged ListSyncRecords query.
+func (d *MongoDAO) CountSyncRecords(ctx context.Context, filter bson.M, includeArchived bool) (int64, error) {
+	collection := d.Client.Database(d.Database).Collection(syncRecordsCollection)
+
+	count, err := collection.CountDocuments(ctx, withArchivedFilter(filter, includeArchived))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count sync records: %w", err)
+	}
+	return count, nil
+}
+
+// AggregateStatusCounts returns the number of sync records per status,
+// computed in the database with a $group stage. Statuses with no records
+// are absent from the map. Archived records are only counted when
+// includeArchived is set.
+func (d *MongoDAO) AggregateStatusCounts(ctx context.Context, includeArchived bool) (map[string]int64, error) {
+	collection := d.Client.Database(d.Database).Collection(syncRecordsCollection)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: withArchivedFilter(nil, includeArchived)}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$status"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate sync record statuses: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode sync record status counts: %w", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
 // CreateSyncRecord creates a new sync record
 func (d *MongoDAO) CreateSyncRecord(ctx context.Context, record *SyncRecordModel) (string, error) {
 	collection := d.Client.Database(d.Database).Collection(syncRecordsCollection)
@@ -272,20 +375,44 @@ func (d *MongoDAO) DeleteSyncRecord(ctx context.Context, id string) error {
 	return err
 }
 
+// oldSyncRecordsFilter matches finished sync records older than olderThan.
+func oldSyncRecordsFilter(olderThan time.Duration) bson.M {
+	return bson.M{
+		"created_at": bson.M{"$lt": time.Now().Add(-olderThan)},
+		"status":     bson.M{"$in": []string{SyncStatusSynced, SyncStatusSkipped}},
+	}
+}
+
 // CleanupOldSyncRecords removes sync records older than the specified duration
 func (d *MongoDAO) CleanupOldSyncRecords(ctx context.Context, olderThan time.Duration) (int64, error) {
 	collection := d.Client.Database(d.Database).Collection(syncRecordsCollection)
 
-	cutoff := time.Now().Add(-olderThan)
-	filter := bson.M{
-		"created_at": bson.M{"$lt": cutoff},
-		"status":     bson.M{"$in": []string{SyncStatusSynced, SyncStatusSkipped}},
-	}
-
-	result, err := collection.DeleteMany(ctx, filter)
+	result, err := collection.DeleteMany(ctx, oldSyncRecordsFilter(olderThan))
 	if err != nil {
 		return 0, err
 	}
 
 	return result.DeletedCount, nil
 }
+
+// ArchiveOldSyncRecords marks the records CleanupOldSyncRecords would delete
+// as archived instead, keeping them for auditing. It returns the number of
+// records newly archived.
+func (d *MongoDAO) ArchiveOldSyncRecords(ctx context.Context, olderThan time.Duration) (int64, error) {
+	collection := d.Client.Database(d.Database).Collection(syncRecordsCollection)
+
+	filter := withArchivedFilter(oldSyncRecordsFilter(olderThan), false)
+	update := bson.M{
+		"$set": bson.M{
+			"archived":   true,
+			"updated_at": time.Now(),
+		},
+	}
+
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive sync records: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,149 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMongoDAO_SyncRecordCounts(t *testing.T) {
+	dao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mongoDao := dao.(*MongoDAO)
+	ctx := context.Background()
+
+	statuses := []string{
+		SyncStatusPending, SyncStatusPending,
+		SyncStatusSynced, SyncStatusSynced, SyncStatusSynced,
+		SyncStatusFailed,
+	}
+	for i, status := range statuses {
+		_, err := mongoDao.CreateSyncRecord(ctx, &SyncRecordModel{
+			SourcePlatform: "memos",
+			SourceID:       string(rune('a' + i)),
+			Status:         status,
+		})
+		require.NoError(t, err)
+	}
+
+	counts, err := mongoDao.AggregateStatusCounts(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		SyncStatusPending: 2,
+		SyncStatusSynced:  3,
+		SyncStatusFailed:  1,
+	}, counts)
+
+	total, err := mongoDao.CountSyncRecords(ctx, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(statuses)), total)
+
+	synced, err := mongoDao.CountSyncRecords(ctx, bson.M{"status": SyncStatusSynced}, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), synced)
+
+	// The total is independent of page size.
+	page, err := mongoDao.ListSyncRecords(ctx, nil, 2, 0, false)
+	require.NoError(t, err)
+	assert.Len(t, page, 2)
+}
+
+func TestMongoDAO_AggregateStatusCounts_Empty(t *testing.T) {
+	dao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	counts, err := dao.(*MongoDAO).AggregateStatusCounts(context.Background(), false)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
+
+// createAgedSyncRecord inserts a record and backdates its created_at.
+func createAgedSyncRecord(t *testing.T, d *MongoDAO, sourceID, status string, age time.Duration) string {
+	t.Helper()
+	ctx := context.Background()
+
+	id, err := d.CreateSyncRecord(ctx, &SyncRecordModel{
+		SourcePlatform: "memos",
+		SourceID:       sourceID,
+		Status:         status,
+	})
+	require.NoError(t, err)
+
+	record, err := d.GetSyncRecord(ctx, id)
+	require.NoError(t, err)
+	record.CreatedAt = time.Now().Add(-age)
+	require.NoError(t, d.UpdateSyncRecord(ctx, record))
+	return id
+}
+
+func TestMongoDAO_ArchiveOldSyncRecords(t *testing.T) {
+	dao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mongoDao := dao.(*MongoDAO)
+	ctx := context.Background()
+
+	oldSynced := createAgedSyncRecord(t, mongoDao, "a", SyncStatusSynced, 48*time.Hour)
+	oldFailed := createAgedSyncRecord(t, mongoDao, "b", SyncStatusFailed, 48*time.Hour)
+	newSynced := createAgedSyncRecord(t, mongoDao, "c", SyncStatusSynced, time.Minute)
+
+	archived, err := mongoDao.ArchiveOldSyncRecords(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), archived)
+
+	// Archiving again touches nothing new.
+	archived, err = mongoDao.ArchiveOldSyncRecords(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+
+	for id, want := range map[string]bool{oldSynced: true, oldFailed: false, newSynced: false} {
+		record, err := mongoDao.GetSyncRecord(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, record, "archiving must not delete records")
+		assert.Equal(t, want, record.Archived, id)
+	}
+}
+
+func TestMongoDAO_SyncRecords_ExcludeArchived(t *testing.T) {
+	dao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mongoDao := dao.(*MongoDAO)
+	ctx := context.Background()
+
+	createAgedSyncRecord(t, mongoDao, "a", SyncStatusSynced, 48*time.Hour)
+	createAgedSyncRecord(t, mongoDao, "b", SyncStatusSynced, time.Minute)
+	createAgedSyncRecord(t, mongoDao, "c", SyncStatusFailed, time.Minute)
+	_, err := mongoDao.ArchiveOldSyncRecords(ctx, 24*time.Hour)
+	require.NoError(t, err)
+
+	records, err := mongoDao.ListSyncRecords(ctx, nil, 0, 0, false)
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+	for _, record := range records {
+		assert.False(t, record.Archived)
+	}
+
+	records, err = mongoDao.ListSyncRecords(ctx, bson.M{"status": SyncStatusSynced}, 0, 0, true)
+	require.NoError(t, err)
+	assert.Len(t, records, 2)
+
+	total, err := mongoDao.CountSyncRecords(ctx, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	total, err = mongoDao.CountSyncRecords(ctx, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+
+	counts, err := mongoDao.AggregateStatusCounts(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{SyncStatusSynced: 1, SyncStatusFailed: 1}, counts)
+	counts, err = mongoDao.AggregateStatusCounts(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{SyncStatusSynced: 2, SyncStatusFailed: 1}, counts)
+}
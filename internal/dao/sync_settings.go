@@ -0,0 +1,65 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// configCollection holds settings changed at runtime through the API, one
+// document per source platform. They override the config file on restart.
+const configCollection = "config"
+
+// SyncSettingsModel is the runtime-updatable subset of a source's sync
+// configuration.
+type SyncSettingsModel struct {
+	Source          string        `bson:"source"`
+	TargetPlatforms []string      `bson:"target_platforms"`
+	SkipPrivate     bool          `bson:"skip_private"`
+	SkipOlder       time.Duration `bson:"skip_older"`
+	FetchLimit      int           `bson:"fetch_limit"`
+	UpdatedAt       time.Time     `bson:"updated_at"`
+}
+
+// SyncSettingsDao persists runtime sync settings
+type SyncSettingsDao interface {
+	// GetSyncSettings returns nil when source has no saved settings
+	GetSyncSettings(ctx context.Context, source string) (*SyncSettingsModel, error)
+	SaveSyncSettings(ctx context.Context, settings *SyncSettingsModel) error
+}
+
+// Ensure MongoDAO implements SyncSettingsDao
+var _ SyncSettingsDao = (*MongoDAO)(nil)
+
+// GetSyncSettings loads the saved settings of source.
+func (d *MongoDAO) GetSyncSettings(ctx context.Context, source string) (*SyncSettingsModel, error) {
+	coll := d.Client.Database(d.Database).Collection(configCollection)
+
+	var settings SyncSettingsModel
+	err := coll.FindOne(ctx, bson.M{"source": source}).Decode(&settings)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get sync settings %s: %w", source, err)
+	}
+	return &settings, nil
+}
+
+// SaveSyncSettings replaces the saved settings of settings.Source.
+func (d *MongoDAO) SaveSyncSettings(ctx context.Context, settings *SyncSettingsModel) error {
+	coll := d.Client.Database(d.Database).Collection(configCollection)
+
+	settings.UpdatedAt = time.Now()
+	filter := bson.M{"source": settings.Source}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := coll.ReplaceOne(ctx, filter, settings, opts); err != nil {
+		return fmt.Errorf("save sync settings %s: %w", settings.Source, err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMongoDAO_SyncSettings_SaveAndGet(t *testing.T) {
+	dao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mongoDao := dao.(*MongoDAO)
+	ctx := context.Background()
+
+	settings, err := mongoDao.GetSyncSettings(ctx, "memos")
+	require.NoError(t, err)
+	assert.Nil(t, settings, "a source without saved settings returns nil")
+
+	require.NoError(t, mongoDao.SaveSyncSettings(ctx, &SyncSettingsModel{
+		Source: "memos", TargetPlatforms: []string{"mastodon"}, SkipOlder: time.Hour, FetchLimit: 20,
+	}))
+	require.NoError(t, mongoDao.SaveSyncSettings(ctx, &SyncSettingsModel{
+		Source: "memos", TargetPlatforms: []string{"bluesky"}, SkipPrivate: true, SkipOlder: 2 * time.Hour, FetchLimit: 50,
+	}))
+
+	settings, err = mongoDao.GetSyncSettings(ctx, "memos")
+	require.NoError(t, err)
+	require.NotNil(t, settings)
+	assert.Equal(t, []string{"bluesky"}, settings.TargetPlatforms, "the second save replaces the first")
+	assert.True(t, settings.SkipPrivate)
+	assert.Equal(t, 2*time.Hour, settings.SkipOlder)
+	assert.Equal(t, 50, settings.FetchLimit)
+	assert.False(t, settings.UpdatedAt.IsZero())
+}
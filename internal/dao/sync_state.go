@@ -0,0 +1,64 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// syncStateCollection holds each source's sync watermark, one document per
+// source platform.
+const syncStateCollection = "sync_state"
+
+// SyncStateModel is the newest post of a source that a sync has fully
+// processed. The next sync lists only posts newer than it.
+type SyncStateModel struct {
+	Source    string    `bson:"source"`
+	SinceID   string    `bson:"since_id"`
+	SinceTime time.Time `bson:"since_time"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// SyncStateDao persists sync watermarks
+type SyncStateDao interface {
+	// GetSyncState returns nil when source has no watermark yet
+	GetSyncState(ctx context.Context, source string) (*SyncStateModel, error)
+	SaveSyncState(ctx context.Context, state *SyncStateModel) error
+}
+
+// Ensure MongoDAO implements SyncStateDao
+var _ SyncStateDao = (*MongoDAO)(nil)
+
+// GetSyncState loads the watermark of source.
+func (d *MongoDAO) GetSyncState(ctx context.Context, source string) (*SyncStateModel, error) {
+	coll := d.Client.Database(d.Database).Collection(syncStateCollection)
+
+	var state SyncStateModel
+	err := coll.FindOne(ctx, bson.M{"source": source}).Decode(&state)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get sync state %s: %w", source, err)
+	}
+	return &state, nil
+}
+
+// SaveSyncState sets the watermark of state.Source in a single upsert, so
+// the ID and time are never seen half-updated.
+func (d *MongoDAO) SaveSyncState(ctx context.Context, state *SyncStateModel) error {
+	coll := d.Client.Database(d.Database).Collection(syncStateCollection)
+
+	state.UpdatedAt = time.Now()
+	filter := bson.M{"source": state.Source}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := coll.ReplaceOne(ctx, filter, state, opts); err != nil {
+		return fmt.Errorf("save sync state %s: %w", state.Source, err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMongoDAO_SyncState_SaveAndGet(t *testing.T) {
+	dao, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	mongoDao := dao.(*MongoDAO)
+	ctx := context.Background()
+
+	state, err := mongoDao.GetSyncState(ctx, "memos")
+	require.NoError(t, err)
+	assert.Nil(t, state, "a source that never synced has no watermark")
+
+	first := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, mongoDao.SaveSyncState(ctx, &SyncStateModel{Source: "memos", SinceID: "memos/1", SinceTime: first}))
+	require.NoError(t, mongoDao.SaveSyncState(ctx, &SyncStateModel{Source: "memos", SinceID: "memos/2", SinceTime: first.Add(time.Minute)}))
+	require.NoError(t, mongoDao.SaveSyncState(ctx, &SyncStateModel{Source: "rss", SinceID: "item", SinceTime: first}))
+
+	state, err = mongoDao.GetSyncState(ctx, "memos")
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, "memos/2", state.SinceID, "the second save replaces the first")
+	assert.True(t, state.SinceTime.Equal(first.Add(time.Minute)))
+	assert.False(t, state.UpdatedAt.IsZero())
+}
@@ -2,22 +2,36 @@ package dao
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"go.orx.me/apps/hyper-sync/internal/conf"
 	"go.orx.me/apps/hyper-sync/internal/social"
 )
 
 // ThreadsConfigAdapter 适配器，将 SocialConfigDao 适配为 social.TokenManager
-// 简化版本，只处理 access token
+// 简化版本，只处理 access token；token 按平台名存在 social_configs 集合中
 type ThreadsConfigAdapter struct {
 	socialDao SocialConfigDao
+	// cipher 为 nil 时 token 以明文保存
+	cipher *tokenCipher
 }
 
 // NewThreadsConfigAdapter 创建新的 Threads 配置适配器
-func NewThreadsConfigAdapter(socialDao SocialConfigDao) *ThreadsConfigAdapter {
+// 配置了 tokens.encryption_key 时，access token 加密后再写入数据库
+func NewThreadsConfigAdapter(socialDao SocialConfigDao) (*ThreadsConfigAdapter, error) {
+	var key string
+	if conf.Conf.Tokens != nil {
+		key = conf.Conf.Tokens.EncryptionKey
+	}
+	cipher, err := newTokenCipher(key)
+	if err != nil {
+		return nil, err
+	}
 	return &ThreadsConfigAdapter{
 		socialDao: socialDao,
-	}
+		cipher:    cipher,
+	}, nil
 }
 
 // GetAccessToken 获取指定平台的 access token
@@ -44,13 +58,16 @@ func (a *ThreadsConfigAdapter) GetTokenInfo(ctx context.Context, platform string
 	}
 
 	// 直接从 SocialConfig 结构体中获取 token 信息
-	accessToken := configModel.Config.AccessToken
 	expiresAt := configModel.Config.ExpiresAt
-
-	if accessToken == "" {
+	if configModel.Config.AccessToken == "" {
 		return nil, nil // No token found
 	}
 
+	accessToken, err := a.cipher.decrypt(configModel.Config.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read access token for %s: %w", platform, err)
+	}
+
 	return &social.TokenInfo{
 		AccessToken: accessToken,
 		ExpiresAt:   expiresAt,
@@ -59,8 +76,11 @@ func (a *ThreadsConfigAdapter) GetTokenInfo(ctx context.Context, platform string
 
 // SaveAccessToken 保存指定平台的 access token
 func (a *ThreadsConfigAdapter) SaveAccessToken(ctx context.Context, platform, accessToken string, expiresAt *time.Time) error {
-	// 直接使用 SocialConfigDao 的 UpdatePlatformToken 方法
-	return a.socialDao.UpdatePlatformToken(ctx, platform, accessToken, expiresAt)
+	stored, err := a.cipher.encrypt(accessToken)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt access token for %s: %w", platform, err)
+	}
+	return a.socialDao.UpdatePlatformToken(ctx, platform, stored, expiresAt)
 }
 
 // Ensure ThreadsConfigAdapter implements social.TokenManager
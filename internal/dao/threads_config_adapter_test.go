@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySocialConfigDao keeps SocialConfigModels in a map, like the
+// social_configs collection keyed by platform.
+type memorySocialConfigDao struct {
+	configs map[string]*SocialConfigModel
+}
+
+func (m *memorySocialConfigDao) GetConfigByPlatform(_ context.Context, platform string) (*SocialConfigModel, error) {
+	return m.configs[platform], nil
+}
+
+func (m *memorySocialConfigDao) UpdatePlatformToken(_ context.Context, platform, accessToken string, expiresAt *time.Time) error {
+	config := m.configs[platform]
+	if config == nil {
+		config = &SocialConfigModel{Platform: platform}
+		m.configs[platform] = config
+	}
+	config.Config.AccessToken = accessToken
+	if expiresAt != nil {
+		config.Config.ExpiresAt = expiresAt
+	}
+	return nil
+}
+
+func newTestAdapter(t *testing.T, key string) (*ThreadsConfigAdapter, *memorySocialConfigDao) {
+	t.Helper()
+	store := &memorySocialConfigDao{configs: map[string]*SocialConfigModel{}}
+	cipher, err := newTokenCipher(key)
+	require.NoError(t, err)
+	return &ThreadsConfigAdapter{socialDao: store, cipher: cipher}, store
+}
+
+func TestThreadsConfigAdapter_SaveThenGet(t *testing.T) {
+	for _, key := range []string{"", "correct horse battery staple"} {
+		t.Run("key="+key, func(t *testing.T) {
+			adapter, _ := newTestAdapter(t, key)
+			ctx := context.Background()
+
+			info, err := adapter.GetTokenInfo(ctx, "threads")
+			require.NoError(t, err)
+			assert.Nil(t, info, "no token saved yet")
+
+			expiresAt := time.Now().Add(60 * 24 * time.Hour).Truncate(time.Millisecond)
+			require.NoError(t, adapter.SaveAccessToken(ctx, "threads", "token-1", &expiresAt))
+
+			info, err = adapter.GetTokenInfo(ctx, "threads")
+			require.NoError(t, err)
+			require.NotNil(t, info)
+			assert.Equal(t, "token-1", info.AccessToken)
+			assert.True(t, expiresAt.Equal(*info.ExpiresAt))
+
+			token, err := adapter.GetAccessToken(ctx, "threads")
+			require.NoError(t, err)
+			assert.Equal(t, "token-1", token)
+		})
+	}
+}
+
+func TestThreadsConfigAdapter_OverwriteOnSave(t *testing.T) {
+	adapter, store := newTestAdapter(t, "secret")
+	ctx := context.Background()
+
+	first := time.Now().Add(time.Hour)
+	second := time.Now().Add(60 * 24 * time.Hour)
+	require.NoError(t, adapter.SaveAccessToken(ctx, "threads", "old", &first))
+	require.NoError(t, adapter.SaveAccessToken(ctx, "threads", "new", &second))
+	require.NoError(t, adapter.SaveAccessToken(ctx, "threads-2", "other", nil))
+
+	info, err := adapter.GetTokenInfo(ctx, "threads")
+	require.NoError(t, err)
+	assert.Equal(t, "new", info.AccessToken)
+	assert.Equal(t, second, *info.ExpiresAt)
+	assert.Len(t, store.configs, 2, "one record per platform")
+}
+
+func TestThreadsConfigAdapter_EncryptsAtRest(t *testing.T) {
+	adapter, store := newTestAdapter(t, "secret")
+	ctx := context.Background()
+
+	require.NoError(t, adapter.SaveAccessToken(ctx, "threads", "plain-token", nil))
+	stored := store.configs["threads"].Config.AccessToken
+	assert.True(t, strings.HasPrefix(stored, encryptedTokenPrefix))
+	assert.NotContains(t, stored, "plain-token")
+
+	// 同一 token 每次加密结果不同（随机 nonce）
+	require.NoError(t, adapter.SaveAccessToken(ctx, "threads", "plain-token", nil))
+	assert.NotEqual(t, stored, store.configs["threads"].Config.AccessToken)
+
+	// 换了 key 或没有 key 时读取失败，而不是把密文当 token 用
+	wrongKey, _ := newTestAdapter(t, "other")
+	wrongKey.socialDao = store
+	_, err := wrongKey.GetTokenInfo(ctx, "threads")
+	assert.Error(t, err)
+
+	noKey, _ := newTestAdapter(t, "")
+	noKey.socialDao = store
+	_, err = noKey.GetTokenInfo(ctx, "threads")
+	assert.ErrorIs(t, err, errTokenKeyMissing)
+}
+
+func TestThreadsConfigAdapter_ReadsPlaintextAfterEnablingKey(t *testing.T) {
+	adapter, store := newTestAdapter(t, "secret")
+	store.configs["threads"] = &SocialConfigModel{Platform: "threads", Config: SocialConfig{AccessToken: "legacy"}}
+
+	token, err := adapter.GetAccessToken(context.Background(), "threads")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", token)
+}
@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedTokenPrefix marks a token stored by tokenCipher; values without it
+// were saved before encryption was enabled and are read as plaintext.
+const encryptedTokenPrefix = "enc:v1:"
+
+// errTokenKeyMissing is returned when an encrypted token is read without
+// tokens.encryption_key configured.
+var errTokenKeyMissing = errors.New("token is encrypted but tokens.encryption_key is not set")
+
+// tokenCipher encrypts access tokens at rest with AES-256-GCM. The key is
+// derived from the configured passphrase with SHA-256, so any length works.
+// A nil tokenCipher stores tokens as plaintext.
+type tokenCipher struct {
+	aead cipher.AEAD
+}
+
+func newTokenCipher(passphrase string) (*tokenCipher, error) {
+	if passphrase == "" {
+		return nil, nil
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token cipher: %w", err)
+	}
+	return &tokenCipher{aead: aead}, nil
+}
+
+// encrypt returns token sealed under a random nonce, or token unchanged for
+// a nil cipher.
+func (c *tokenCipher) encrypt(token string) (string, error) {
+	if c == nil || token == "" {
+		return token, nil
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(token), nil)
+	return encryptedTokenPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt reverses encrypt. Plaintext values pass through, so tokens saved
+// before encryption was enabled keep working until they are next saved.
+func (c *tokenCipher) decrypt(value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedTokenPrefix)
+	if !ok {
+		return value, nil
+	}
+	if c == nil {
+		return "", errTokenKeyMissing
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode encrypted token: %w", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", errors.New("encrypted token is too short")
+	}
+	plain, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt token, wrong tokens.encryption_key?: %w", err)
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"butterfly.orx.me/core/log"
+	"github.com/gin-gonic/gin"
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/service"
+)
+
+// ConfigHandler exposes the running configuration with secrets redacted
+type ConfigHandler struct {
+	socialService  *service.SocialService
+	sources        map[string][]string // source platform -> sync targets
+	getSyncService SyncServiceFactory  // must return the live SyncService of a source
+	settingsDao    dao.SyncSettingsDao
+}
+
+// NewConfigHandler creates a new config handler
+func NewConfigHandler(socialService *service.SocialService, sources map[string][]string, getSyncService SyncServiceFactory, settingsDao dao.SyncSettingsDao) *ConfigHandler {
+	return &ConfigHandler{
+		socialService:  socialService,
+		sources:        sources,
+		getSyncService: getSyncService,
+		settingsDao:    settingsDao,
+	}
+}
+
+// ConfigSnapshot is the redaction-safe configuration returned by GetConfig
+type ConfigSnapshot struct {
+	Platforms []service.PlatformConfigSnapshot `json:"platforms"`
+	Sync      []service.SyncConfigSnapshot     `json:"sync"`
+}
+
+// ConfigResponse represents the response for the config endpoint
+type ConfigResponse struct {
+	Success bool            `json:"success"`
+	Data    *ConfigSnapshot `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// GetConfig returns the configured platforms and the effective sync settings
+// of every source, with tokens, passwords and keys redacted
+// GET /api/config
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+
+	sources := make([]string, 0, len(h.sources))
+	for source := range h.sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	snapshot := &ConfigSnapshot{
+		Platforms: h.socialService.GetConfigSnapshot(),
+		Sync:      make([]service.SyncConfigSnapshot, 0, len(sources)),
+	}
+	for _, source := range sources {
+		syncService, err := h.getSyncService(source, h.sources[source])
+		if err != nil {
+			logger.Error("Failed to get sync service", "source", source, "error", err)
+			c.JSON(http.StatusInternalServerError, ConfigResponse{
+				Success: false,
+				Error:   err.Error(),
+			})
+			return
+		}
+		snapshot.Sync = append(snapshot.Sync, syncService.GetConfigSnapshot())
+	}
+
+	c.JSON(http.StatusOK, ConfigResponse{
+		Success: true,
+		Data:    snapshot,
+	})
+}
+
+// UpdateConfigRequest changes the runtime settings of one source. Omitted
+// fields keep their current value.
+type UpdateConfigRequest struct {
+	Source          string   `json:"source"`
+	TargetPlatforms []string `json:"target_platforms"`
+	SkipPrivate     *bool    `json:"skip_private"`
+	// SkipOlder is a Go duration such as "2h"; "0" disables the age limit.
+	SkipOlder  *string `json:"skip_older"`
+	FetchLimit *int    `json:"fetch_limit"`
+}
+
+// UpdateConfigResponse represents the response for a config update
+type UpdateConfigResponse struct {
+	Success bool                        `json:"success"`
+	Data    *service.SyncConfigSnapshot `json:"data,omitempty"`
+	Error   string                      `json:"error,omitempty"`
+}
+
+// UpdateConfig saves new runtime settings for a source and applies them from
+// its next sync on, returning the effective sync config
+// PUT /api/config
+func (h *ConfigHandler) UpdateConfig(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+
+	var req UpdateConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, UpdateConfigResponse{
+			Success: false,
+			Error:   "invalid request body: " + err.Error(),
+		})
+		return
+	}
+	targets, ok := h.sources[req.Source]
+	if !ok {
+		c.JSON(http.StatusNotFound, UpdateConfigResponse{
+			Success: false,
+			Error:   "no sync configured for source " + req.Source,
+		})
+		return
+	}
+
+	update := service.SyncSettingsUpdate{
+		TargetPlatforms: req.TargetPlatforms,
+		SkipPrivate:     req.SkipPrivate,
+		FetchLimit:      req.FetchLimit,
+	}
+	if req.SkipOlder != nil {
+		skipOlder, err := time.ParseDuration(*req.SkipOlder)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, UpdateConfigResponse{
+				Success: false,
+				Error:   "invalid skip_older: " + err.Error(),
+			})
+			return
+		}
+		update.SkipOlder = &skipOlder
+	}
+
+	syncService, err := h.getSyncService(req.Source, targets)
+	if err != nil {
+		logger.Error("Failed to get sync service", "source", req.Source, "error", err)
+		c.JSON(http.StatusInternalServerError, UpdateConfigResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if _, err := syncService.UpdateSettings(c.Request.Context(), h.settingsDao, update); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidSyncSettings) {
+			status = http.StatusBadRequest
+		} else {
+			logger.Error("Failed to update sync settings", "source", req.Source, "error", err)
+		}
+		c.JSON(status, UpdateConfigResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	snapshot := syncService.GetConfigSnapshot()
+	c.JSON(http.StatusOK, UpdateConfigResponse{
+		Success: true,
+		Data:    &snapshot,
+	})
+}
@@ -0,0 +1,116 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/handler"
+	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// memorySettingsDao keeps saved sync settings in memory.
+type memorySettingsDao struct {
+	saved map[string]*dao.SyncSettingsModel
+}
+
+func (d *memorySettingsDao) GetSyncSettings(_ context.Context, source string) (*dao.SyncSettingsModel, error) {
+	return d.saved[source], nil
+}
+
+func (d *memorySettingsDao) SaveSyncSettings(_ context.Context, settings *dao.SyncSettingsModel) error {
+	if d.saved == nil {
+		d.saved = make(map[string]*dao.SyncSettingsModel)
+	}
+	d.saved[settings.Source] = settings
+	return nil
+}
+
+func TestConfigHandler_GetConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	socialService := service.NewSocialServiceFromPlatforms([]*social.SocialPlatform{
+		{Name: "memos", Client: &fakeClient{name: "memos"}, Config: &social.PlatformConfig{
+			Type: "memos", Enabled: true, SyncTo: []string{"threads"},
+			Memos: &social.MemosConfig{Endpoint: "https://memos.example.com", Token: "memos-secret-token"},
+		}},
+		{Name: "threads", Client: &fakeClient{name: "threads"}, Config: &social.PlatformConfig{
+			Type: "threads", Enabled: true,
+			Threads: &social.ThreadsConfig{ClientSecret: "threads-client-secret", AccessToken: "threads-access-token"},
+		}},
+	})
+	sources := map[string][]string{"memos": {"threads"}}
+	newSyncService := func(mainSocial string, socials []string) (*service.SyncService, error) {
+		return service.NewSyncService(nil, socialService, nil, mainSocial, socials)
+	}
+	h := handler.NewConfigHandler(socialService, sources, newSyncService, &memorySettingsDao{})
+
+	r := gin.New()
+	r.GET("/api/config", h.GetConfig)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/config", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	for _, secret := range []string{"memos-secret-token", "threads-client-secret", "threads-access-token"} {
+		assert.NotContains(t, w.Body.String(), secret)
+	}
+
+	var resp handler.ConfigResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	require.NotNil(t, resp.Data)
+	require.Len(t, resp.Data.Platforms, 2)
+	assert.Equal(t, "https://memos.example.com", resp.Data.Platforms[0].Settings["endpoint"])
+	require.Len(t, resp.Data.Sync, 1)
+	assert.Equal(t, "memos", resp.Data.Sync[0].Source)
+	assert.Equal(t, []string{"threads"}, resp.Data.Sync[0].TargetPlatforms, "the configured targets, not placeholders")
+}
+
+func TestConfigHandler_UpdateConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	socialService := service.NewSocialServiceFromPlatforms([]*social.SocialPlatform{
+		{Name: "memos", Client: &fakeClient{name: "memos"}, Config: &social.PlatformConfig{Type: "memos"}},
+		{Name: "mastodon", Client: &fakeClient{name: "mastodon"}, Config: &social.PlatformConfig{Type: "mastodon"}},
+		{Name: "bluesky", Client: &fakeClient{name: "bluesky"}, Config: &social.PlatformConfig{Type: "bluesky"}},
+	})
+	syncService, err := service.NewSyncService(nil, socialService, nil, "memos", []string{"mastodon"})
+	require.NoError(t, err)
+	settingsDao := &memorySettingsDao{}
+	h := handler.NewConfigHandler(socialService, map[string][]string{"memos": {"mastodon"}},
+		func(string, []string) (*service.SyncService, error) { return syncService, nil }, settingsDao)
+
+	r := gin.New()
+	r.PUT("/api/config", h.UpdateConfig)
+	put := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/config", strings.NewReader(body)))
+		return w
+	}
+
+	w := put(`{"source":"memos","target_platforms":["mastodon","bluesky"],"skip_older":"2h","fetch_limit":20}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp handler.UpdateConfigResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data)
+	assert.Equal(t, []string{"mastodon", "bluesky"}, resp.Data.TargetPlatforms)
+	assert.Equal(t, "2h0m0s", resp.Data.SkipOlderThan)
+	assert.Equal(t, 20, resp.Data.FetchLimit)
+	require.Contains(t, settingsDao.saved, "memos")
+	assert.Equal(t, 20, settingsDao.saved["memos"].FetchLimit)
+
+	assert.Equal(t, http.StatusBadRequest, put(`{"source":"memos","target_platforms":["myspace"]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, put(`{"source":"memos","skip_older":"-1h"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, put(`{"source":"memos","skip_older":"soon"}`).Code)
+	assert.Equal(t, http.StatusNotFound, put(`{"source":"bluesky","fetch_limit":5}`).Code)
+	assert.Equal(t, []string{"mastodon", "bluesky"}, syncService.Settings().TargetPlatforms, "rejected updates change nothing")
+}
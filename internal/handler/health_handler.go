@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"butterfly.orx.me/core/log"
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds each dependency check of a readiness probe
+const readinessTimeout = 2 * time.Second
+
+// Pinger checks that a downstream dependency is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingerFunc adapts a function to the Pinger interface
+type PingerFunc func(ctx context.Context) error
+
+// Ping calls f(ctx)
+func (f PingerFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthHandler serves liveness and readiness probes
+type HealthHandler struct {
+	checks map[string]Pinger
+}
+
+// NewHealthHandler creates a health handler; checks maps a dependency name,
+// e.g. "mongo", to how it is pinged
+func NewHealthHandler(checks map[string]Pinger) *HealthHandler {
+	return &HealthHandler{
+		checks: checks,
+	}
+}
+
+// HealthResponse is the body of both probes. Checks holds "ok" or the error
+// of each dependency and is only set by the readiness probe.
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Liveness reports that the process is up
+// GET /healthz
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+// Readiness pings every dependency and returns 503 when any is unavailable
+// GET /readyz
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		checks = make(map[string]string, len(h.checks))
+		ready  = true
+	)
+	for name, pinger := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := pinger.Ping(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.Warn("Readiness check failed", "dependency", name, "error", err)
+				checks[name] = err.Error()
+				ready = false
+				return
+			}
+			checks[name] = "ok"
+		}()
+	}
+	wg.Wait()
+
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, HealthResponse{Status: "unavailable", Checks: checks})
+		return
+	}
+	c.JSON(http.StatusOK, HealthResponse{Status: "ok", Checks: checks})
+}
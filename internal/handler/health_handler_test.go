@@ -0,0 +1,67 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/handler"
+)
+
+func healthy(context.Context) error { return nil }
+
+func probe(t *testing.T, checks map[string]handler.Pinger, path string) (*httptest.ResponseRecorder, handler.HealthResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	h := handler.NewHealthHandler(checks)
+	r := gin.New()
+	r.GET("/healthz", h.Liveness)
+	r.GET("/readyz", h.Readiness)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var resp handler.HealthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w, resp
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	w, resp := probe(t, map[string]handler.Pinger{
+		"mongo": handler.PingerFunc(healthy),
+		"redis": handler.PingerFunc(healthy),
+	}, "/readyz")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", resp.Status)
+	assert.Equal(t, map[string]string{"mongo": "ok", "redis": "ok"}, resp.Checks)
+}
+
+func TestHealthHandler_MongoUnreachable(t *testing.T) {
+	unreachable := handler.PingerFunc(func(ctx context.Context) error {
+		return errors.New("server selection timeout")
+	})
+	checks := map[string]handler.Pinger{
+		"mongo": unreachable,
+		"redis": handler.PingerFunc(healthy),
+	}
+
+	w, resp := probe(t, checks, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "unavailable", resp.Status)
+	assert.Equal(t, "server selection timeout", resp.Checks["mongo"])
+	assert.Equal(t, "ok", resp.Checks["redis"])
+
+	// Liveness does not depend on downstream services.
+	w, resp = probe(t, checks, "/healthz")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", resp.Status)
+	assert.Empty(t, resp.Checks)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// PlatformHandler exposes the configured platforms and their capabilities
+type PlatformHandler struct {
+	schedulerService *service.SchedulerService
+	configs          map[string]*social.PlatformConfig
+}
+
+// NewPlatformHandler creates a new platform handler
+func NewPlatformHandler(schedulerService *service.SchedulerService, configs map[string]*social.PlatformConfig) *PlatformHandler {
+	return &PlatformHandler{
+		schedulerService: schedulerService,
+		configs:          configs,
+	}
+}
+
+// PlatformsResponse represents the response for the platform list
+type PlatformsResponse struct {
+	Success bool                   `json:"success"`
+	Data    []service.PlatformInfo `json:"data"`
+}
+
+// GetPlatforms lists every configured platform with its type, supported
+// visibility levels and capabilities, plus token status for Threads
+// GET /api/platforms
+func (h *PlatformHandler) GetPlatforms(c *gin.Context) {
+	c.JSON(http.StatusOK, PlatformsResponse{
+		Success: true,
+		Data:    h.schedulerService.ListPlatforms(c.Request.Context(), h.configs),
+	})
+}
@@ -0,0 +1,94 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/handler"
+	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+type fakeClient struct{ name string }
+
+func (f *fakeClient) Post(context.Context, *social.Post) (interface{}, error) { return nil, nil }
+func (f *fakeClient) ListPosts(context.Context, int) ([]*social.Post, error)  { return nil, nil }
+func (f *fakeClient) Name() string                                            { return f.name }
+
+// fakeDeleterClient also implements social.SocialDeleter.
+type fakeDeleterClient struct{ fakeClient }
+
+func (f *fakeDeleterClient) Delete(context.Context, string) error { return nil }
+
+type fakeTokenManager struct{ expiresAt time.Time }
+
+func (f *fakeTokenManager) GetAccessToken(context.Context, string) (string, error) {
+	return "token", nil
+}
+
+func (f *fakeTokenManager) GetTokenInfo(context.Context, string) (*social.TokenInfo, error) {
+	return &social.TokenInfo{AccessToken: "token", ExpiresAt: &f.expiresAt}, nil
+}
+
+func (f *fakeTokenManager) SaveAccessToken(context.Context, string, string, *time.Time) error {
+	return nil
+}
+
+func TestPlatformHandler_GetPlatforms(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	configs := map[string]*social.PlatformConfig{
+		"bluesky": {Type: "bluesky", Enabled: true},
+		"threads": {Name: "threads", Type: "threads", Enabled: true},
+		"memos":   {Type: "memos", Enabled: false},
+	}
+	socialService := service.NewSocialServiceFromPlatforms([]*social.SocialPlatform{
+		{Name: "bluesky", Client: &fakeDeleterClient{fakeClient{name: "bluesky"}}, Config: configs["bluesky"]},
+		{Name: "threads", Client: &fakeClient{name: "threads"}, Config: configs["threads"]},
+	})
+	tokenManager := &fakeTokenManager{expiresAt: time.Now().Add(30 * 24 * time.Hour)}
+	h := handler.NewPlatformHandler(service.NewSchedulerService(socialService, nil, tokenManager), configs)
+
+	r := gin.New()
+	r.GET("/api/platforms", h.GetPlatforms)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/platforms", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp handler.PlatformsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	require.Len(t, resp.Data, 3)
+
+	bluesky, memos, threads := resp.Data[0], resp.Data[1], resp.Data[2]
+
+	assert.Equal(t, "bluesky", bluesky.Name)
+	assert.Equal(t, "bluesky", bluesky.Type)
+	assert.True(t, bluesky.Enabled)
+	assert.Equal(t, []string{"public", "private"}, bluesky.Visibility)
+	assert.True(t, bluesky.SupportsMedia)
+	assert.True(t, bluesky.SupportsListPosts)
+	assert.True(t, bluesky.SupportsDelete)
+	assert.Nil(t, bluesky.TokenStatus)
+
+	assert.Equal(t, "memos", memos.Name, "the map key names a platform without a name")
+	assert.False(t, memos.Enabled)
+	assert.False(t, memos.SupportsMedia)
+	assert.True(t, memos.SupportsListPosts)
+	assert.False(t, memos.SupportsDelete, "a disabled platform has no client")
+
+	assert.Equal(t, "threads", threads.Name)
+	assert.False(t, threads.SupportsListPosts)
+	assert.False(t, threads.SupportsDelete)
+	require.NotNil(t, threads.TokenStatus)
+	assert.True(t, threads.TokenStatus.HasToken)
+	assert.False(t, threads.TokenStatus.IsExpiringSoon)
+}
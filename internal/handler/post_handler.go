@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.orx.me/apps/hyper-sync/internal/service"
+)
+
+// PostHandler handles manual cross-posting
+type PostHandler struct {
+	postService *service.PostService
+}
+
+// NewPostHandler creates a new post handler
+func NewPostHandler(postService *service.PostService) *PostHandler {
+	return &PostHandler{
+		postService: postService,
+	}
+}
+
+// CreatePostRequest represents the body of a manual cross-post
+type CreatePostRequest struct {
+	Content    string   `json:"content"`
+	Visibility string   `json:"visibility"`
+	MediaURLs  []string `json:"media_urls"`
+	Platforms  []string `json:"platforms"`
+}
+
+// CreatePostResponse represents the response for a manual cross-post.
+// Success is true only when every platform succeeded.
+type CreatePostResponse struct {
+	Success bool                               `json:"success"`
+	PostID  string                             `json:"post_id,omitempty"`
+	Results map[string]service.CrossPostResult `json:"results,omitempty"`
+	Error   string                             `json:"error,omitempty"`
+}
+
+// CreatePost posts one piece of content to the given platforms immediately
+// and returns each platform's result
+// POST /api/post
+func (h *PostHandler) CreatePost(c *gin.Context) {
+	var req CreatePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, CreatePostResponse{
+			Success: false,
+			Error:   "invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	created, results, err := h.postService.CrossPostNow(c.Request.Context(), service.CrossPostRequest{
+		Content:    req.Content,
+		Visibility: req.Visibility,
+		MediaURLs:  req.MediaURLs,
+		Platforms:  req.Platforms,
+	})
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidCrossPost) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, CreatePostResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	success := true
+	for _, result := range results {
+		success = success && result.Success
+	}
+	c.JSON(http.StatusOK, CreatePostResponse{
+		Success: success,
+		PostID:  created.ID,
+		Results: results,
+	})
+}
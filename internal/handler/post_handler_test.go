@@ -0,0 +1,118 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/handler"
+	"go.orx.me/apps/hyper-sync/internal/post"
+	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// postingClient records posts and fails them all when err is set.
+type postingClient struct {
+	fakeClient
+	err    error
+	posted []*social.Post
+}
+
+func (p *postingClient) Post(_ context.Context, post *social.Post) (interface{}, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.posted = append(p.posted, post)
+	return map[string]string{"id": p.name + "-1"}, nil
+}
+
+func newPostRouter(t *testing.T, store post.Store, platforms ...*social.SocialPlatform) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	postService := service.NewPostService(store,
+		service.WithSocialService(service.NewSocialServiceFromPlatforms(platforms)))
+	r := gin.New()
+	r.POST("/api/post", handler.NewPostHandler(postService).CreatePost)
+	return r
+}
+
+func doPost(t *testing.T, r *gin.Engine, body any) (*httptest.ResponseRecorder, handler.CreatePostResponse) {
+	t.Helper()
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/post", bytes.NewReader(data)))
+
+	var resp handler.CreatePostResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w, resp
+}
+
+func TestPostHandler_CreatePost_PartialFailure(t *testing.T) {
+	mastodon := &postingClient{fakeClient: fakeClient{name: "mastodon"}}
+	bluesky := &postingClient{fakeClient: fakeClient{name: "bluesky"}, err: errors.New("rate limited")}
+	store := post.NewMemoryStore()
+	r := newPostRouter(t, store,
+		&social.SocialPlatform{Name: "mastodon", Client: mastodon, Config: &social.PlatformConfig{Type: "mastodon"}},
+		&social.SocialPlatform{Name: "bluesky", Client: bluesky, Config: &social.PlatformConfig{Type: "bluesky"}},
+	)
+
+	w, resp := doPost(t, r, handler.CreatePostRequest{
+		Content:   "hello",
+		MediaURLs: []string{"https://cdn.example.com/a.png"},
+		Platforms: []string{"mastodon", "bluesky"},
+	})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, resp.Success, "one platform failed")
+	assert.Equal(t, map[string]service.CrossPostResult{
+		"mastodon": {Success: true, PlatformID: "mastodon-1"},
+		"bluesky":  {Error: "rate limited"},
+	}, resp.Results)
+
+	require.Len(t, mastodon.posted, 1)
+	assert.Equal(t, "hello", mastodon.posted[0].Content)
+	require.Len(t, mastodon.posted[0].Media, 1)
+	assert.Equal(t, "https://cdn.example.com/a.png", mastodon.posted[0].Media[0].GetURL())
+
+	stored, err := store.GetByID(context.Background(), resp.PostID)
+	require.NoError(t, err)
+	assert.Equal(t, "published", stored.Status)
+	assert.False(t, stored.SyncPending, "the publish worker must not post it again")
+	assert.Equal(t, "mastodon-1", stored.CrossPostStatus["mastodon"].PlatformID)
+	assert.Equal(t, "rate limited", stored.CrossPostStatus["bluesky"].Error)
+}
+
+func TestPostHandler_CreatePost_UnsupportedVisibility(t *testing.T) {
+	bluesky := &postingClient{fakeClient: fakeClient{name: "bluesky"}}
+	r := newPostRouter(t, post.NewMemoryStore(),
+		&social.SocialPlatform{Name: "bluesky", Client: bluesky, Config: &social.PlatformConfig{Type: "bluesky"}},
+	)
+
+	w, resp := doPost(t, r, handler.CreatePostRequest{
+		Content:    "hello",
+		Visibility: "unlisted",
+		Platforms:  []string{"bluesky"},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "bluesky")
+	assert.Empty(t, bluesky.posted)
+}
+
+func TestPostHandler_CreatePost_UnknownPlatform(t *testing.T) {
+	r := newPostRouter(t, post.NewMemoryStore())
+
+	w, _ := doPost(t, r, handler.CreatePostRequest{Content: "hello", Platforms: []string{"myspace"}})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+
+	"butterfly.orx.me/core/log"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.orx.me/apps/hyper-sync/internal/dao"
+)
+
+// PostRetryHandler re-attempts the failed targets of one synced post
+type PostRetryHandler struct {
+	postDao        dao.PostDao
+	sources        map[string][]string // source platform -> sync targets
+	getSyncService SyncServiceFactory  // must return the live SyncService of a source
+}
+
+// NewPostRetryHandler creates a new post retry handler
+func NewPostRetryHandler(postDao dao.PostDao, sources map[string][]string, getSyncService SyncServiceFactory) *PostRetryHandler {
+	return &PostRetryHandler{
+		postDao:        postDao,
+		sources:        sources,
+		getSyncService: getSyncService,
+	}
+}
+
+// RetryPostRequest represents the optional body of a post retry
+type RetryPostRequest struct {
+	// Platforms limits the retry to these targets; empty retries every failed one.
+	Platforms []string `json:"platforms"`
+}
+
+// RetryPostResponse represents the response for a post retry.
+// Success is true only when every retried target succeeded.
+type RetryPostResponse struct {
+	Success bool                      `json:"success"`
+	Message string                    `json:"message,omitempty"`
+	Results map[string]PlatformStatus `json:"results,omitempty"`
+	Error   string                    `json:"error,omitempty"`
+}
+
+// RetryPost re-posts a synced post to the targets it has not reached yet
+// POST /api/posts/:id/retry
+func (h *PostRetryHandler) RetryPost(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+	id := c.Param("id")
+
+	var req RetryPostRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, RetryPostResponse{
+				Success: false,
+				Error:   "invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	if _, err := bson.ObjectIDFromHex(id); err != nil {
+		c.JSON(http.StatusBadRequest, RetryPostResponse{
+			Success: false,
+			Error:   "invalid post id: " + id,
+		})
+		return
+	}
+
+	post, err := h.postDao.GetPostByID(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("Failed to get post", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, RetryPostResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if post == nil {
+		c.JSON(http.StatusNotFound, RetryPostResponse{
+			Success: false,
+			Error:   "post not found: " + id,
+		})
+		return
+	}
+
+	// 只允许重试该帖子来源的同步目标或已有跨发记录的平台，避免误发到无关平台
+	targets := h.sources[post.Social]
+	for _, platform := range req.Platforms {
+		if _, ok := post.CrossPostStatus[platform]; ok || slices.Contains(targets, platform) {
+			continue
+		}
+		c.JSON(http.StatusBadRequest, RetryPostResponse{
+			Success: false,
+			Error:   "platform " + platform + " is not a sync target of " + post.Social,
+		})
+		return
+	}
+
+	// 用正在运行的服务，才能看到暂停的目标
+	syncService, err := h.getSyncService(post.Social, targets)
+	if err != nil {
+		logger.Error("Failed to get sync service", "source", post.Social, "error", err)
+		c.JSON(http.StatusInternalServerError, RetryPostResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	statuses, err := syncService.RetryPost(c.Request.Context(), post, req.Platforms)
+	if err != nil {
+		logger.Error("Failed to retry post", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, RetryPostResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if len(statuses) == 0 {
+		c.JSON(http.StatusOK, RetryPostResponse{
+			Success: true,
+			Message: "No failed targets to retry",
+		})
+		return
+	}
+
+	success := true
+	results := make(map[string]PlatformStatus, len(statuses))
+	for platform, status := range statuses {
+		// 按可见性设置跳过的目标不算失败
+		success = success && (status.Success || status.SkipReason != "")
+		results[platform] = PlatformStatus{
+			Success:    status.Success,
+			PlatformID: status.PlatformID,
+			PostedAt:   status.PostedAt,
+			Error:      status.Error,
+			SkipReason: status.SkipReason,
+		}
+	}
+	logger.Info("Retried post", "id", id, "platforms", len(results), "success", success)
+	c.JSON(http.StatusOK, RetryPostResponse{
+		Success: success,
+		Results: results,
+	})
+}
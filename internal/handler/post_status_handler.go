@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"butterfly.orx.me/core/log"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.orx.me/apps/hyper-sync/internal/dao"
+)
+
+// PostStatusHandler reports where a synced post has been cross-posted
+type PostStatusHandler struct {
+	postDao dao.PostDao
+}
+
+// NewPostStatusHandler creates a new post status handler
+func NewPostStatusHandler(postDao dao.PostDao) *PostStatusHandler {
+	return &PostStatusHandler{
+		postDao: postDao,
+	}
+}
+
+// PlatformStatus is the cross-post outcome on one target platform
+type PlatformStatus struct {
+	Success    bool       `json:"success"`
+	PlatformID string     `json:"platform_id,omitempty"`
+	PostedAt   *time.Time `json:"posted_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	// SkipReason is set when the platform's visibility settings skipped the post
+	SkipReason string `json:"skip_reason,omitempty"`
+}
+
+// PostStatus is a synced post's source and per-platform cross-post status
+type PostStatus struct {
+	ID              string                    `json:"id"`
+	Social          string                    `json:"social"`
+	SocialID        string                    `json:"social_id"`
+	SourcePlatform  string                    `json:"source_platform,omitempty"`
+	OriginalID      string                    `json:"original_id,omitempty"`
+	CreatedAt       time.Time                 `json:"created_at"`
+	CrossPostStatus map[string]PlatformStatus `json:"cross_post_status"`
+}
+
+// PostStatusResponse represents the response for a post status query
+type PostStatusResponse struct {
+	Success bool        `json:"success"`
+	Data    *PostStatus `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// GetPostStatus returns the cross-post status of a synced post
+// GET /api/posts/:id/status
+func (h *PostStatusHandler) GetPostStatus(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+	id := c.Param("id")
+
+	if _, err := bson.ObjectIDFromHex(id); err != nil {
+		c.JSON(http.StatusBadRequest, PostStatusResponse{
+			Success: false,
+			Error:   "invalid post id: " + id,
+		})
+		return
+	}
+
+	post, err := h.postDao.GetPostByID(c.Request.Context(), id)
+	if err != nil {
+		logger.Error("Failed to get post", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, PostStatusResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	// GetPostByID 找不到时返回 nil, nil
+	if post == nil {
+		c.JSON(http.StatusNotFound, PostStatusResponse{
+			Success: false,
+			Error:   "post not found: " + id,
+		})
+		return
+	}
+
+	statuses := make(map[string]PlatformStatus, len(post.CrossPostStatus))
+	for platform, status := range post.CrossPostStatus {
+		statuses[platform] = PlatformStatus{
+			Success:    status.Success,
+			PlatformID: status.PlatformID,
+			PostedAt:   status.PostedAt,
+			Error:      status.Error,
+			SkipReason: status.SkipReason,
+		}
+	}
+
+	c.JSON(http.StatusOK, PostStatusResponse{
+		Success: true,
+		Data: &PostStatus{
+			ID:              post.ID.Hex(),
+			Social:          post.Social,
+			SocialID:        post.SocialID,
+			SourcePlatform:  post.SourcePlatform,
+			OriginalID:      post.OriginalID,
+			CreatedAt:       post.CreatedAt,
+			CrossPostStatus: statuses,
+		},
+	})
+}
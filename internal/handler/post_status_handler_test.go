@@ -0,0 +1,97 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/handler"
+)
+
+// fakePostDao serves GetPostByID from a map; other methods are unused.
+type fakePostDao struct {
+	dao.PostDao
+	posts map[string]*dao.PostModel
+}
+
+func (f *fakePostDao) GetPostByID(_ context.Context, id string) (*dao.PostModel, error) {
+	return f.posts[id], nil
+}
+
+func getPostStatus(t *testing.T, postDao dao.PostDao, id string) (*httptest.ResponseRecorder, handler.PostStatusResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/posts/:id/status", handler.NewPostStatusHandler(postDao).GetPostStatus)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/posts/"+id+"/status", nil))
+
+	var resp handler.PostStatusResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w, resp
+}
+
+func TestPostStatusHandler_Found(t *testing.T) {
+	id := bson.NewObjectID()
+	postedAt := time.Date(2026, 7, 3, 12, 0, 0, 0, time.UTC)
+	postDao := &fakePostDao{posts: map[string]*dao.PostModel{
+		id.Hex(): {
+			ID:             id,
+			Social:         "memos",
+			SocialID:       "memos/42",
+			SourcePlatform: "memos",
+			OriginalID:     "memos/42",
+			CrossPostStatus: map[string]dao.CrossPostStatus{
+				"bluesky":  {Success: true, CrossPosted: true, PlatformID: "3kabc", PostedAt: &postedAt},
+				"mastodon": {Success: false, Error: "rate limited", RetryCount: 2},
+			},
+		},
+	}}
+
+	w, resp := getPostStatus(t, postDao, id.Hex())
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, resp.Success)
+	require.NotNil(t, resp.Data)
+	assert.Equal(t, id.Hex(), resp.Data.ID)
+	assert.Equal(t, "memos", resp.Data.SourcePlatform)
+	assert.Equal(t, "memos/42", resp.Data.OriginalID)
+	require.Len(t, resp.Data.CrossPostStatus, 2)
+
+	bluesky := resp.Data.CrossPostStatus["bluesky"]
+	assert.True(t, bluesky.Success)
+	assert.Equal(t, "3kabc", bluesky.PlatformID)
+	require.NotNil(t, bluesky.PostedAt)
+	assert.True(t, postedAt.Equal(*bluesky.PostedAt))
+
+	mastodon := resp.Data.CrossPostStatus["mastodon"]
+	assert.False(t, mastodon.Success)
+	assert.Equal(t, "rate limited", mastodon.Error)
+	assert.Nil(t, mastodon.PostedAt)
+}
+
+func TestPostStatusHandler_NotFound(t *testing.T) {
+	w, resp := getPostStatus(t, &fakePostDao{}, bson.NewObjectID().Hex())
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.False(t, resp.Success)
+	assert.Nil(t, resp.Data)
+}
+
+func TestPostStatusHandler_BadID(t *testing.T) {
+	w, resp := getPostStatus(t, &fakePostDao{}, "not-an-object-id")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "not-an-object-id")
+}
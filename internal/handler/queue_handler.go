@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+
+	"butterfly.orx.me/core/log"
+	"github.com/gin-gonic/gin"
+	"go.orx.me/apps/hyper-sync/internal/service"
+)
+
+// QueueHandler handles pending sync queue endpoints
+type QueueHandler struct {
+	schedulerService *service.SchedulerService
+}
+
+// NewQueueHandler creates a new queue handler
+func NewQueueHandler(schedulerService *service.SchedulerService) *QueueHandler {
+	return &QueueHandler{
+		schedulerService: schedulerService,
+	}
+}
+
+// ClearTaskQueueResponse represents the response for clearing the queue
+type ClearTaskQueueResponse struct {
+	Success bool   `json:"success"`
+	Cleared int    `json:"cleared"`
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ClearTaskQueue discards posts buffered by source platforms that have not
+// been synced yet
+// DELETE /api/sync/queue
+func (h *QueueHandler) ClearTaskQueue(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+
+	cleared, err := h.schedulerService.ClearQueue(c.Request.Context())
+	if err != nil {
+		logger.Error("Failed to clear task queue", "error", err)
+		c.JSON(http.StatusInternalServerError, ClearTaskQueueResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Cleared task queue", "cleared", cleared)
+	c.JSON(http.StatusOK, ClearTaskQueueResponse{
+		Success: true,
+		Cleared: cleared,
+		Message: "Pending posts discarded",
+	})
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.orx.me/apps/hyper-sync/internal/service"
+)
+
+// ScheduleHandler exposes the cron sync schedules
+type ScheduleHandler struct {
+	schedulerService *service.SchedulerService
+}
+
+// NewScheduleHandler creates a new schedule handler
+func NewScheduleHandler(schedulerService *service.SchedulerService) *ScheduleHandler {
+	return &ScheduleHandler{
+		schedulerService: schedulerService,
+	}
+}
+
+// SchedulerStatusResponse represents the response for the schedule status
+type SchedulerStatusResponse struct {
+	Success bool                     `json:"success"`
+	Data    *service.SchedulerStatus `json:"data"`
+}
+
+// GetSchedulerStatus lists the loaded schedule patterns with their next and
+// last run times
+// GET /api/sync/schedules
+func (h *ScheduleHandler) GetSchedulerStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, SchedulerStatusResponse{
+		Success: true,
+		Data:    h.schedulerService.GetSchedulerStatus(),
+	})
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"butterfly.orx.me/core/log"
+	"github.com/gin-gonic/gin"
+	"go.orx.me/apps/hyper-sync/internal/service"
+)
+
+// SyncServiceFactory returns the SyncService for a source platform and its
+// sync targets.
+type SyncServiceFactory func(mainSocial string, socials []string) (*service.SyncService, error)
+
+// SyncHandler handles on-demand sync endpoints
+type SyncHandler struct {
+	sources        map[string][]string // source platform -> sync targets
+	getSyncService SyncServiceFactory  // must return the live SyncService of a source
+}
+
+// NewSyncHandler creates a new sync handler
+func NewSyncHandler(sources map[string][]string, getSyncService SyncServiceFactory) *SyncHandler {
+	return &SyncHandler{
+		sources:        sources,
+		getSyncService: getSyncService,
+	}
+}
+
+// TriggerSyncRequest represents the body of a sync trigger
+type TriggerSyncRequest struct {
+	// Source limits the sync to one source platform; empty syncs them all.
+	Source string `json:"source"`
+	// DryRun logs what would be posted without posting anything.
+	DryRun bool `json:"dry_run"`
+}
+
+// TriggerSyncResponse represents the response for a sync trigger
+type TriggerSyncResponse struct {
+	Success bool     `json:"success"`
+	Message string   `json:"message,omitempty"`
+	Sources []string `json:"sources,omitempty"`
+	DryRun  bool     `json:"dry_run"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// TriggerSync runs one sync cycle immediately
+// POST /api/sync/trigger
+func (h *SyncHandler) TriggerSync(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+
+	var req TriggerSyncRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, TriggerSyncResponse{
+				Success: false,
+				Error:   "invalid request body: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	sources, ok := h.selectSources(req.Source)
+	if !ok {
+		c.JSON(http.StatusNotFound, TriggerSyncResponse{
+			Success: false,
+			DryRun:  req.DryRun,
+			Error:   "no sync configured for source " + req.Source,
+		})
+		return
+	}
+
+	logger.Info("Manually triggering sync", "sources", sources, "dry_run", req.DryRun)
+
+	for _, source := range sources {
+		syncService, err := h.getSyncService(source, h.sources[source])
+		if err != nil {
+			logger.Error("Failed to get sync service", "source", source, "error", err)
+			c.JSON(http.StatusInternalServerError, TriggerSyncResponse{
+				Success: false,
+				DryRun:  req.DryRun,
+				Error:   err.Error(),
+			})
+			return
+		}
+		// 与定时同步共用同一个服务（暂停的目标、游标、熔断器），dry run 只作用于本次调用
+		if _, err := syncService.SyncWithOptions(c.Request.Context(), service.SyncOptions{DryRun: req.DryRun}); err != nil {
+			logger.Error("Triggered sync failed", "source", source, "error", err)
+			c.JSON(http.StatusInternalServerError, TriggerSyncResponse{
+				Success: false,
+				DryRun:  req.DryRun,
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, TriggerSyncResponse{
+		Success: true,
+		Message: "Sync completed",
+		Sources: sources,
+		DryRun:  req.DryRun,
+	})
+}
+
+// selectSources returns source alone, or every configured source in name
+// order when source is empty. ok is false for an unknown source.
+func (h *SyncHandler) selectSources(source string) (sources []string, ok bool) {
+	if source != "" {
+		if _, ok := h.sources[source]; !ok {
+			return nil, false
+		}
+		return []string{source}, true
+	}
+	for source := range h.sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources, true
+}
+
+// syncStreamEvent is one Server-Sent Event of StreamSync.
+type syncStreamEvent struct {
+	name string
+	data interface{}
+}
+
+// StreamSync runs one sync cycle like TriggerSync, streaming its progress as
+// Server-Sent Events: a service.SyncProgress per processing, posted, skipped
+// and failed step and a summary per source, then a final done event with a
+// TriggerSyncResponse.
+// GET /api/sync/stream?source=memos&dry_run=true
+func (h *SyncHandler) StreamSync(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+
+	source := c.Query("source")
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, TriggerSyncResponse{
+				Success: false,
+				Error:   "invalid dry_run: " + v,
+			})
+			return
+		}
+	}
+
+	sources, ok := h.selectSources(source)
+	if !ok {
+		c.JSON(http.StatusNotFound, TriggerSyncResponse{
+			Success: false,
+			DryRun:  dryRun,
+			Error:   "no sync configured for source " + source,
+		})
+		return
+	}
+
+	logger.Info("Manually triggering streamed sync", "sources", sources, "dry_run", dryRun)
+
+	// 跨发在多个 goroutine 中并发回调进度，统一经 channel 交给当前 goroutine 写出
+	ctx := c.Request.Context()
+	events := make(chan syncStreamEvent, 16)
+	send := func(event syncStreamEvent) {
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		defer close(events)
+		resp := h.runStreamedSync(ctx, sources, dryRun, func(progress service.SyncProgress) {
+			send(syncStreamEvent{name: progress.Type, data: progress})
+		})
+		send(syncStreamEvent{name: "done", data: resp})
+	}()
+
+	// Content-Type 由 c.SSEvent 设置
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	for event := range events {
+		c.SSEvent(event.name, event.data)
+		c.Writer.Flush()
+	}
+}
+
+// runStreamedSync syncs sources one after another, reporting progress, and
+// returns the response for the final done event.
+func (h *SyncHandler) runStreamedSync(ctx context.Context, sources []string, dryRun bool, progress service.SyncProgressFunc) TriggerSyncResponse {
+	logger := log.FromContext(ctx)
+
+	for _, source := range sources {
+		syncService, err := h.getSyncService(source, h.sources[source])
+		if err != nil {
+			logger.Error("Failed to get sync service", "source", source, "error", err)
+			return TriggerSyncResponse{Success: false, DryRun: dryRun, Error: err.Error()}
+		}
+
+		opts := service.SyncOptions{DryRun: dryRun, Progress: progress}
+		if _, err := syncService.SyncWithOptions(ctx, opts); err != nil {
+			logger.Error("Streamed sync failed", "source", source, "error", err)
+			return TriggerSyncResponse{Success: false, DryRun: dryRun, Error: err.Error()}
+		}
+	}
+
+	return TriggerSyncResponse{
+		Success: true,
+		Message: "Sync completed",
+		Sources: sources,
+		DryRun:  dryRun,
+	}
+}
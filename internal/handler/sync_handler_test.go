@@ -0,0 +1,251 @@
+package handler_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/handler"
+	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// listingClient is a source platform serving a fixed list of posts.
+type listingClient struct {
+	fakeClient
+	posts []*social.Post
+}
+
+func (c *listingClient) ListPosts(context.Context, int) ([]*social.Post, error) { return c.posts, nil }
+
+// syncPostDao stores posts in memory, covering what a sync of new posts
+// uses; the embedded nil dao.PostDao panics on anything else.
+type syncPostDao struct {
+	dao.PostDao
+
+	mu    sync.Mutex
+	posts map[string]*dao.PostModel
+}
+
+func (d *syncPostDao) GetBySocialAndSocialID(_ context.Context, socialName, socialID string) (*dao.PostModel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range d.posts {
+		if p.Social == socialName && p.SocialID == socialID {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *syncPostDao) GetPostByContentHash(context.Context, string, string, time.Time, time.Time) (*dao.PostModel, error) {
+	return nil, nil
+}
+
+func (d *syncPostDao) GetByCrossPostPlatformID(context.Context, string, string) (*dao.PostModel, error) {
+	return nil, nil
+}
+
+func (d *syncPostDao) CreatePostIfNotExists(_ context.Context, p *dao.PostModel) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p.ID = bson.NewObjectID()
+	d.posts[p.ID.Hex()] = p
+	return p.ID.Hex(), nil
+}
+
+func (d *syncPostDao) UpdateCrossPostStatus(_ context.Context, postID, platform string, status dao.CrossPostStatus) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.posts[postID].CrossPostStatus[platform] = status
+	return nil
+}
+
+type sseEvent struct {
+	name string
+	data string
+}
+
+// readSSE splits a text/event-stream body into events.
+func readSSE(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if current.name != "" {
+				events = append(events, current)
+			}
+			current = sseEvent{}
+		case strings.HasPrefix(line, "event:"):
+			current.name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			current.data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestSyncHandler_StreamSync(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Now()
+	source := &listingClient{fakeClient: fakeClient{name: "memos"}, posts: []*social.Post{
+		{ID: "1", Content: "first", CreatedAt: now},
+		{ID: "2", Content: "second", CreatedAt: now},
+		{ID: "3", Content: "third", CreatedAt: now},
+	}}
+	socialService := service.NewSocialServiceFromPlatforms([]*social.SocialPlatform{
+		{Name: "memos", Client: source, Config: &social.PlatformConfig{}},
+		// a bare fakeClient returns no post, which the sync records as skipped
+		{Name: "mastodon", Client: &postingClient{fakeClient: fakeClient{name: "mastodon"}}, Config: &social.PlatformConfig{}},
+	})
+	postDao := &syncPostDao{posts: make(map[string]*dao.PostModel)}
+	locker := dao.NewMemoryLocker()
+	newSyncService := func(mainSocial string, socials []string) (*service.SyncService, error) {
+		return service.NewSyncService(postDao, socialService, locker, mainSocial, socials)
+	}
+	h := handler.NewSyncHandler(map[string][]string{"memos": {"mastodon"}}, newSyncService)
+
+	r := gin.New()
+	r.GET("/api/sync/stream", h.StreamSync)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/sync/stream", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	mediaType, _, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "text/event-stream", mediaType)
+
+	events := readSSE(t, w.Body.String())
+	require.NotEmpty(t, events)
+
+	var names []string
+	posted := map[string]string{}
+	for _, event := range events {
+		names = append(names, event.name)
+		if event.name == service.SyncProgressPosted {
+			var progress service.SyncProgress
+			require.NoError(t, json.Unmarshal([]byte(event.data), &progress))
+			assert.Equal(t, "memos", progress.Source)
+			posted[progress.PostID] = progress.Platform
+		}
+	}
+	assert.Equal(t, []string{
+		"processing", "posted",
+		"processing", "posted",
+		"processing", "posted",
+		"summary", "done",
+	}, names)
+	assert.Equal(t, map[string]string{"1": "mastodon", "2": "mastodon", "3": "mastodon"}, posted)
+
+	var summary service.SyncProgress
+	require.NoError(t, json.Unmarshal([]byte(events[len(events)-2].data), &summary))
+	require.NotNil(t, summary.Summary)
+	assert.Equal(t, 3, summary.Summary.PostsFetched)
+	assert.Equal(t, 3, summary.Summary.PostsSynced)
+
+	var done handler.TriggerSyncResponse
+	require.NoError(t, json.Unmarshal([]byte(events[len(events)-1].data), &done))
+	assert.True(t, done.Success)
+	assert.Equal(t, []string{"memos"}, done.Sources)
+}
+
+func TestSyncHandler_StreamSync_UnknownSource(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := handler.NewSyncHandler(map[string][]string{"memos": {"mastodon"}}, nil)
+
+	r := gin.New()
+	r.GET("/api/sync/stream", h.StreamSync)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/sync/stream?source=bluesky", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/sync/stream?dry_run=maybe", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// newSharedSyncHandler returns a handler that runs every sync on svc, the way
+// the routes use the running instance from wire.GetSyncService.
+func newSharedSyncHandler(t *testing.T, mastodon *postingClient) (*handler.SyncHandler, *service.SyncService) {
+	t.Helper()
+	now := time.Now()
+	source := &listingClient{fakeClient: fakeClient{name: "memos"}, posts: []*social.Post{
+		{ID: "1", Content: "first", CreatedAt: now},
+	}}
+	socialService := service.NewSocialServiceFromPlatforms([]*social.SocialPlatform{
+		{Name: "memos", Client: source, Config: &social.PlatformConfig{}},
+		{Name: "mastodon", Client: mastodon, Config: &social.PlatformConfig{}},
+	})
+	postDao := &syncPostDao{posts: make(map[string]*dao.PostModel)}
+	svc, err := service.NewSyncService(postDao, socialService, dao.NewMemoryLocker(), "memos", []string{"mastodon"})
+	require.NoError(t, err)
+	getSyncService := func(string, []string) (*service.SyncService, error) { return svc, nil }
+	return handler.NewSyncHandler(map[string][]string{"memos": {"mastodon"}}, getSyncService), svc
+}
+
+func TestSyncHandler_StreamSync_SkipsPausedTarget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mastodon := &postingClient{fakeClient: fakeClient{name: "mastodon"}}
+	h, svc := newSharedSyncHandler(t, mastodon)
+	svc.PauseTarget("mastodon")
+
+	r := gin.New()
+	r.GET("/api/sync/stream", h.StreamSync)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/sync/stream", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var reasons []string
+	for _, event := range readSSE(t, w.Body.String()) {
+		if event.name == service.SyncProgressSkipped {
+			var progress service.SyncProgress
+			require.NoError(t, json.Unmarshal([]byte(event.data), &progress))
+			reasons = append(reasons, progress.Reason)
+		}
+	}
+	assert.Equal(t, []string{"target_paused"}, reasons)
+	assert.Empty(t, mastodon.posted)
+}
+
+func TestSyncHandler_TriggerSync_DryRunIsPerCall(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mastodon := &postingClient{fakeClient: fakeClient{name: "mastodon"}}
+	h, svc := newSharedSyncHandler(t, mastodon)
+
+	r := gin.New()
+	r.POST("/api/sync/trigger", h.TriggerSync)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/sync/trigger", strings.NewReader(`{"dry_run":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp handler.TriggerSyncResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.DryRun)
+	assert.Empty(t, mastodon.posted)
+	// 共享的服务不能被单次调用切成 dry run
+	assert.False(t, svc.DryRun)
+}
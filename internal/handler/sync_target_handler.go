@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+
+	"butterfly.orx.me/core/log"
+	"github.com/gin-gonic/gin"
+	"go.orx.me/apps/hyper-sync/internal/service"
+)
+
+// SyncTargetHandler pauses and resumes cross-posting to a target platform
+type SyncTargetHandler struct {
+	sources        map[string][]string // source platform -> sync targets
+	getSyncService SyncServiceFactory  // must return the live SyncService of a source
+}
+
+// NewSyncTargetHandler creates a new sync target handler
+func NewSyncTargetHandler(sources map[string][]string, getSyncService SyncServiceFactory) *SyncTargetHandler {
+	return &SyncTargetHandler{
+		sources:        sources,
+		getSyncService: getSyncService,
+	}
+}
+
+// SyncTargetResponse represents the response for a pause or resume
+type SyncTargetResponse struct {
+	Success  bool   `json:"success"`
+	Platform string `json:"platform,omitempty"`
+	Paused   bool   `json:"paused"`
+	// Sources are the source platforms currently syncing to Platform.
+	Sources []string `json:"sources,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// PauseTarget stops cross-posting to a platform from every source until it
+// is resumed or the service restarts
+// POST /api/sync/targets/:platform/pause
+func (h *SyncTargetHandler) PauseTarget(c *gin.Context) {
+	h.setPaused(c, true)
+}
+
+// ResumeTarget re-enables cross-posting to a paused platform
+// POST /api/sync/targets/:platform/resume
+func (h *SyncTargetHandler) ResumeTarget(c *gin.Context) {
+	h.setPaused(c, false)
+}
+
+func (h *SyncTargetHandler) setPaused(c *gin.Context, paused bool) {
+	logger := log.FromContext(c.Request.Context())
+	platform := c.Param("platform")
+
+	sources := make([]string, 0, len(h.sources))
+	for source := range h.sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var syncServices []*service.SyncService
+	var syncing []string
+	for _, source := range sources {
+		syncService, err := h.getSyncService(source, h.sources[source])
+		if err != nil {
+			logger.Error("Failed to get sync service", "source", source, "error", err)
+			c.JSON(http.StatusInternalServerError, SyncTargetResponse{
+				Success:  false,
+				Platform: platform,
+				Error:    err.Error(),
+			})
+			return
+		}
+		syncServices = append(syncServices, syncService)
+		if slices.Contains(syncService.Settings().TargetPlatforms, platform) {
+			syncing = append(syncing, source)
+		}
+	}
+	if len(syncing) == 0 {
+		c.JSON(http.StatusNotFound, SyncTargetResponse{
+			Success:  false,
+			Platform: platform,
+			Error:    "no source syncs to platform " + platform,
+		})
+		return
+	}
+
+	// 所有源都设置暂停状态，这样之后通过 PUT /api/config 加入该目标的源也会遵守
+	for _, syncService := range syncServices {
+		if paused {
+			syncService.PauseTarget(platform)
+		} else {
+			syncService.ResumeTarget(platform)
+		}
+	}
+
+	logger.Info("Sync target paused state changed", "platform", platform, "paused", paused, "sources", syncing)
+	c.JSON(http.StatusOK, SyncTargetResponse{
+		Success:  true,
+		Platform: platform,
+		Paused:   paused,
+		Sources:  syncing,
+	})
+}
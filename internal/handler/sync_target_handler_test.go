@@ -0,0 +1,62 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/handler"
+	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestSyncTargetHandler_PauseResume(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	socialService := service.NewSocialServiceFromPlatforms([]*social.SocialPlatform{
+		{Name: "memos", Client: &fakeClient{name: "memos"}, Config: &social.PlatformConfig{}},
+		{Name: "threads", Client: &fakeClient{name: "threads"}, Config: &social.PlatformConfig{}},
+	})
+	sources := map[string][]string{"memos": {"threads"}}
+	live := make(map[string]*service.SyncService)
+	getSyncService := func(mainSocial string, socials []string) (*service.SyncService, error) {
+		if s, ok := live[mainSocial]; ok {
+			return s, nil
+		}
+		s, err := service.NewSyncService(nil, socialService, nil, mainSocial, socials)
+		live[mainSocial] = s
+		return s, err
+	}
+	h := handler.NewSyncTargetHandler(sources, getSyncService)
+
+	r := gin.New()
+	r.POST("/api/sync/targets/:platform/pause", h.PauseTarget)
+	r.POST("/api/sync/targets/:platform/resume", h.ResumeTarget)
+	post := func(path string) (*httptest.ResponseRecorder, handler.SyncTargetResponse) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		var resp handler.SyncTargetResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return w, resp
+	}
+
+	w, resp := post("/api/sync/targets/threads/pause")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.True(t, resp.Paused)
+	assert.Equal(t, []string{"memos"}, resp.Sources)
+	assert.True(t, live["memos"].IsTargetPaused("threads"))
+
+	w, resp = post("/api/sync/targets/threads/resume")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.False(t, resp.Paused)
+	assert.False(t, live["memos"].IsTargetPaused("threads"))
+
+	w, _ = post("/api/sync/targets/bluesky/pause")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.False(t, live["memos"].IsTargetPaused("bluesky"), "an unknown target is not paused")
+}
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"butterfly.orx.me/core/log"
 	"github.com/gin-gonic/gin"
 	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
 )
 
 // TokenHandler handles token management endpoints
@@ -113,3 +117,118 @@ func (h *TokenHandler) RefreshAllTokens(c *gin.Context) {
 		Message: "All tokens refresh check completed",
 	})
 }
+
+// ExchangeTokenRequest is the body of the Threads token exchange
+type ExchangeTokenRequest struct {
+	ShortLivedToken string `json:"short_lived_token"`
+}
+
+// ExchangeTokenResponse represents the response for a token exchange
+type ExchangeTokenResponse struct {
+	Success   bool       `json:"success"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// ExchangeThreadsToken exchanges a Threads short-lived token for a
+// long-lived one and stores it, for first-time setup
+// POST /api/platforms/:name/threads/exchange
+func (h *TokenHandler) ExchangeThreadsToken(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+	platform := c.Param("name")
+
+	var req ExchangeTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.ShortLivedToken == "" {
+		c.JSON(http.StatusBadRequest, ExchangeTokenResponse{
+			Success: false,
+			Error:   "short_lived_token is required",
+		})
+		return
+	}
+
+	logger.Info("Exchanging Threads token", "platform", platform)
+
+	expiresAt, err := h.schedulerService.ExchangeThreadsToken(c.Request.Context(), platform, req.ShortLivedToken)
+	if err != nil {
+		logger.Error("Failed to exchange token", "platform", platform, "error", err)
+		c.JSON(tokenErrorStatus(err), ExchangeTokenResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ExchangeTokenResponse{
+		Success:   true,
+		ExpiresAt: &expiresAt,
+	})
+}
+
+// tokenErrorStatus maps token operation errors to HTTP status codes.
+func tokenErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrPlatformNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrTokenUnsupported):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// GetPlatformToken returns the token status of a platform. Platforms whose
+// tokens are not managed answer 400.
+// GET /api/platforms/:name/token
+func (h *TokenHandler) GetPlatformToken(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+	platform := c.Param("name")
+
+	status, err := h.schedulerService.GetTokenStatus(c.Request.Context(), platform)
+	if err != nil {
+		logger.Error("Failed to get token status", "platform", platform, "error", err)
+		c.JSON(tokenErrorStatus(err), TokenStatusResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if status.PlatformType != social.PlatformThreads.String() {
+		c.JSON(http.StatusBadRequest, TokenStatusResponse{
+			Success: false,
+			Error:   fmt.Sprintf("unsupported: %s (platform type %s)", service.ErrTokenUnsupported, status.PlatformType),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenStatusResponse{
+		Success: true,
+		Data:    status,
+	})
+}
+
+// RefreshPlatformToken refreshes the token of a platform now instead of
+// waiting for the scheduler.
+// POST /api/platforms/:name/token/refresh
+func (h *TokenHandler) RefreshPlatformToken(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context())
+	platform := c.Param("name")
+
+	logger.Info("Manually refreshing token", "platform", platform)
+
+	if err := h.schedulerService.RefreshThreadsTokenManually(c.Request.Context(), platform); err != nil {
+		logger.Error("Failed to refresh token", "platform", platform, "error", err)
+		errMsg := err.Error()
+		if errors.Is(err, service.ErrTokenUnsupported) {
+			errMsg = "unsupported: " + errMsg
+		}
+		c.JSON(tokenErrorStatus(err), RefreshTokenResponse{
+			Success: false,
+			Error:   errMsg,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, RefreshTokenResponse{
+		Success: true,
+		Message: "Token refreshed successfully",
+	})
+}
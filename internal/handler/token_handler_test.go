@@ -0,0 +1,202 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/handler"
+	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// savingTokenManager keeps the last saved token.
+type savingTokenManager struct {
+	token     string
+	expiresAt *time.Time
+}
+
+func (m *savingTokenManager) GetAccessToken(context.Context, string) (string, error) {
+	return m.token, nil
+}
+
+func (m *savingTokenManager) GetTokenInfo(context.Context, string) (*social.TokenInfo, error) {
+	return &social.TokenInfo{AccessToken: m.token, ExpiresAt: m.expiresAt}, nil
+}
+
+func (m *savingTokenManager) SaveAccessToken(_ context.Context, _ string, token string, expiresAt *time.Time) error {
+	m.token, m.expiresAt = token, expiresAt
+	return nil
+}
+
+// redirectTransport sends every request to target, standing in for
+// graph.threads.net.
+type redirectTransport struct{ target *url.URL }
+
+func (t redirectTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme, r.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func newExchangeRouter(t *testing.T, graph http.HandlerFunc) (*gin.Engine, *savingTokenManager) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	server := httptest.NewServer(graph)
+	t.Cleanup(server.Close)
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	tokens := &savingTokenManager{}
+	httpClients := social.NewHTTPClientFactoryWithTransport(time.Second, redirectTransport{target: target})
+	threads, err := social.NewThreadsClientWithDao("threads", "client-id", "client-secret", "initial", 42, tokens, httpClients)
+	require.NoError(t, err)
+
+	socialService := service.NewSocialServiceFromPlatforms([]*social.SocialPlatform{
+		{Name: "threads", Client: threads, Config: &social.PlatformConfig{Type: "threads"}},
+		{Name: "bluesky", Client: &fakeClient{name: "bluesky"}, Config: &social.PlatformConfig{Type: "bluesky"}},
+	})
+	h := handler.NewTokenHandler(service.NewSchedulerService(socialService, nil, tokens))
+
+	r := gin.New()
+	r.POST("/api/platforms/:name/threads/exchange", h.ExchangeThreadsToken)
+	r.GET("/api/platforms/:name/token", h.GetPlatformToken)
+	r.POST("/api/platforms/:name/token/refresh", h.RefreshPlatformToken)
+	return r, tokens
+}
+
+func postExchange(r *gin.Engine, name, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/platforms/"+name+"/threads/exchange", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestTokenHandler_ExchangeThreadsToken(t *testing.T) {
+	var query url.Values
+	r, tokens := newExchangeRouter(t, func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1.0/access_token", req.URL.Path)
+		query = req.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"long-lived","token_type":"bearer","expires_in":5184000}`))
+	})
+
+	w := postExchange(r, "threads", `{"short_lived_token":"short"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	assert.Equal(t, "th_exchange_token", query.Get("grant_type"))
+	assert.Equal(t, "client-secret", query.Get("client_secret"))
+	assert.Equal(t, "short", query.Get("access_token"))
+
+	var resp handler.ExchangeTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	require.NotNil(t, resp.ExpiresAt)
+	assert.WithinDuration(t, time.Now().Add(60*24*time.Hour), *resp.ExpiresAt, time.Minute)
+
+	// 新 token 通过 TokenManager 保存
+	assert.Equal(t, "long-lived", tokens.token)
+	require.NotNil(t, tokens.expiresAt)
+	assert.WithinDuration(t, *resp.ExpiresAt, *tokens.expiresAt, time.Second)
+}
+
+func TestTokenHandler_ExchangeThreadsToken_Errors(t *testing.T) {
+	r, tokens := newExchangeRouter(t, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"Invalid OAuth access token"}}`))
+	})
+
+	w := postExchange(r, "threads", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "short_lived_token is required")
+
+	w = postExchange(r, "threads", `{"short_lived_token":"expired"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid OAuth access token")
+	assert.Equal(t, "initial", tokens.token, "a failed exchange keeps the stored token")
+
+	w = postExchange(r, "bluesky", `{"short_lived_token":"short"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "not a threads platform")
+
+	w = postExchange(r, "unknown", `{"short_lived_token":"short"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func serve(r *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestTokenHandler_GetPlatformToken(t *testing.T) {
+	r, tokens := newExchangeRouter(t, func(w http.ResponseWriter, req *http.Request) {
+		t.Errorf("unexpected Graph API call %s", req.URL.Path)
+	})
+	expiresAt := time.Now().Add(3 * 24 * time.Hour)
+	tokens.expiresAt = &expiresAt
+
+	w := serve(r, http.MethodGet, "/api/platforms/threads/token")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Success bool `json:"success"`
+		Data    struct {
+			HasToken        bool       `json:"has_token"`
+			ExpiresAt       *time.Time `json:"expires_at"`
+			TimeUntilExpiry *int64     `json:"time_until_expiry"`
+			IsExpiringSoon  bool       `json:"is_expiring_soon"`
+			Message         string     `json:"message"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	assert.True(t, resp.Data.HasToken)
+	assert.True(t, resp.Data.IsExpiringSoon, "3 days left is within the 7 day refresh window")
+	require.NotNil(t, resp.Data.ExpiresAt)
+	assert.WithinDuration(t, expiresAt, *resp.Data.ExpiresAt, time.Second)
+	require.NotNil(t, resp.Data.TimeUntilExpiry)
+	assert.InDelta(t, float64(3*24*time.Hour), float64(*resp.Data.TimeUntilExpiry), float64(time.Minute))
+	assert.NotEmpty(t, resp.Data.Message)
+}
+
+func TestTokenHandler_PlatformTokenUnsupported(t *testing.T) {
+	r, _ := newExchangeRouter(t, func(w http.ResponseWriter, req *http.Request) {
+		t.Errorf("unexpected Graph API call %s", req.URL.Path)
+	})
+
+	w := serve(r, http.MethodGet, "/api/platforms/bluesky/token")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unsupported")
+
+	w = serve(r, http.MethodPost, "/api/platforms/bluesky/token/refresh")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unsupported")
+
+	w = serve(r, http.MethodGet, "/api/platforms/unknown/token")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTokenHandler_RefreshPlatformToken(t *testing.T) {
+	r, tokens := newExchangeRouter(t, func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/v1.0/refresh_access_token", req.URL.Path)
+		assert.Equal(t, "th_refresh_token", req.URL.Query().Get("grant_type"))
+		assert.Equal(t, "initial", req.URL.Query().Get("access_token"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"refreshed","token_type":"bearer","expires_in":5184000}`))
+	})
+
+	w := serve(r, http.MethodPost, "/api/platforms/threads/token/refresh")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "refreshed", tokens.token)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"butterfly.orx.me/core/log"
+	"github.com/gin-gonic/gin"
+	"go.orx.me/apps/hyper-sync/internal/service"
+)
+
+// WebhookHandler handles incoming webhooks that trigger a sync
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// WebhookResponse represents the response to an incoming webhook
+type WebhookResponse struct {
+	Success bool                   `json:"success"`
+	Data    *service.WebhookResult `json:"data,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// MemosWebhook handles a Memos webhook
+// POST /api/webhooks/memos
+func (h *WebhookHandler) MemosWebhook(c *gin.Context) {
+	h.handle(c, h.webhookService.HandleMemosWebhook)
+}
+
+// GenericWebhook handles a webhook naming the source to sync
+// POST /api/webhooks/generic
+func (h *WebhookHandler) GenericWebhook(c *gin.Context) {
+	h.handle(c, h.webhookService.HandleGenericWebhook)
+}
+
+// TelegramWebhook handles an update Telegram delivers to a bot's webhook
+// POST /api/webhooks/telegram
+func (h *WebhookHandler) TelegramWebhook(c *gin.Context) {
+	h.handle(c, h.webhookService.HandleTelegramWebhook)
+}
+
+func (h *WebhookHandler) handle(c *gin.Context, fn func(context.Context, *http.Request) (*service.WebhookResult, error)) {
+	result, err := fn(c.Request.Context(), c.Request)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrWebhookUnauthorized):
+			status = http.StatusUnauthorized
+		case errors.Is(err, service.ErrWebhookDisabled):
+			status = http.StatusForbidden
+		case errors.Is(err, service.ErrInvalidWebhookPayload):
+			status = http.StatusBadRequest
+		default:
+			log.FromContext(c.Request.Context()).Error("Webhook handling failed", "path", c.FullPath(), "error", err)
+		}
+		c.JSON(status, WebhookResponse{
+			Success: false,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookResponse{
+		Success: true,
+		Data:    result,
+	})
+}
@@ -0,0 +1,184 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/handler"
+	"go.orx.me/apps/hyper-sync/internal/service"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+// fakeMemosClient also implements social.PostGetter and records the memos
+// fetched. The memos it returns are too old to be cross-posted.
+type fakeMemosClient struct {
+	fakeClient
+	gets []string
+}
+
+func (f *fakeMemosClient) GetPost(_ context.Context, id string) (*social.Post, error) {
+	f.gets = append(f.gets, id)
+	return &social.Post{ID: id}, nil
+}
+
+// newWebhookRouter mounts the webhook routes and records which sources
+// they synced.
+func newWebhookRouter(cfg *conf.WebhookConfig) (*gin.Engine, *[]string) {
+	r, synced, _ := newMemosWebhookRouter(cfg)
+	return r, synced
+}
+
+// newMemosWebhookRouter is newWebhookRouter that also returns the memos
+// client, whose endpoint is https://memos.example.com.
+func newMemosWebhookRouter(cfg *conf.WebhookConfig) (*gin.Engine, *[]string, *fakeMemosClient) {
+	gin.SetMode(gin.TestMode)
+
+	configs := map[string]*social.PlatformConfig{
+		"memos":    {Type: "memos", SyncTo: []string{"mastodon"}, Memos: &social.MemosConfig{Endpoint: "https://memos.example.com/"}},
+		"rss":      {Type: "rss", SyncTo: []string{"mastodon"}},
+		"mastodon": {Type: "mastodon"},
+	}
+	memos := &fakeMemosClient{fakeClient: fakeClient{name: "memos"}}
+	socialService := service.NewSocialServiceFromPlatforms([]*social.SocialPlatform{
+		{Name: "memos", Client: memos, Config: configs["memos"]},
+		{Name: "rss", Client: &fakeClient{name: "rss"}, Config: configs["rss"]},
+		{Name: "mastodon", Client: &fakeClient{name: "mastodon"}, Config: configs["mastodon"]},
+	})
+	var synced []string
+	newSyncService := func(mainSocial string, socials []string) (*service.SyncService, error) {
+		synced = append(synced, mainSocial)
+		return service.NewSyncService(nil, socialService, dao.NewMemoryLocker(), mainSocial, socials)
+	}
+	h := handler.NewWebhookHandler(service.NewWebhookService(cfg, configs, newSyncService))
+
+	r := gin.New()
+	r.POST("/api/webhooks/memos", h.MemosWebhook)
+	r.POST("/api/webhooks/generic", h.GenericWebhook)
+	return r, &synced, memos
+}
+
+func postWebhook(r *gin.Engine, path, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(service.WebhookSignatureHeader, signature)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestWebhookHandler_MemosValidSignature(t *testing.T) {
+	// 关闭去抖，同步在请求内完成
+	r, synced, memos := newMemosWebhookRouter(&conf.WebhookConfig{Enabled: true, Secret: testWebhookSecret, DebounceWindow: -1})
+	body := `{"url":"https://memos.example.com","activityType":"memos.memo.created","creator":"users/1","memo":{"name":"memos/abc"}}`
+
+	w := postWebhook(r, "/api/webhooks/memos", body, service.SignWebhookPayload(testWebhookSecret, []byte(body)))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp handler.WebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	require.NotNil(t, resp.Data)
+	assert.True(t, resp.Data.Accepted)
+	assert.Equal(t, "memos.memo.created", resp.Data.Event)
+	assert.Equal(t, []string{"memos"}, resp.Data.Sources, "only memos sources are synced")
+	assert.Equal(t, []string{"memos"}, *synced)
+	assert.Equal(t, "memos/abc", resp.Data.MemoID)
+	assert.Equal(t, []string{"memos/abc"}, memos.gets, "only the memo in the payload is fetched")
+}
+
+func TestWebhookHandler_MemosWithoutMemoSyncsBatch(t *testing.T) {
+	// 关闭去抖，同步在请求内完成
+	r, synced, memos := newMemosWebhookRouter(&conf.WebhookConfig{Enabled: true, Secret: testWebhookSecret, DebounceWindow: -1})
+	body := `{"activityType":"memos.memo.updated"}`
+
+	w := postWebhook(r, "/api/webhooks/memos", body, service.SignWebhookPayload(testWebhookSecret, []byte(body)))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp handler.WebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data)
+	assert.Empty(t, resp.Data.MemoID)
+	assert.Equal(t, []string{"memos"}, *synced)
+	assert.Empty(t, memos.gets, "without a memo the source is listed")
+}
+
+func TestWebhookHandler_MemosDebounced(t *testing.T) {
+	r, synced := newWebhookRouter(&conf.WebhookConfig{Enabled: true, Secret: testWebhookSecret, DebounceWindow: time.Hour})
+	body := `{"activityType":"memos.memo.updated","memo":{"name":"memos/abc"}}`
+
+	w := postWebhook(r, "/api/webhooks/memos", body, service.SignWebhookPayload(testWebhookSecret, []byte(body)))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp handler.WebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data)
+	assert.True(t, resp.Data.Accepted)
+	assert.Equal(t, "sync scheduled", resp.Data.Message)
+	assert.Equal(t, []string{"memos"}, resp.Data.Sources)
+	assert.Empty(t, *synced, "the sync waits for the debounce window")
+}
+
+func TestWebhookHandler_MemosIgnoredEvent(t *testing.T) {
+	r, synced := newWebhookRouter(&conf.WebhookConfig{Enabled: true, Secret: testWebhookSecret})
+	body := `{"activityType":"memos.memo.deleted","memo":{"name":"memos/abc"}}`
+
+	w := postWebhook(r, "/api/webhooks/memos", body, service.SignWebhookPayload(testWebhookSecret, []byte(body)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp handler.WebhookResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Data)
+	assert.False(t, resp.Data.Accepted)
+	assert.Empty(t, *synced)
+}
+
+func TestWebhookHandler_InvalidSignature(t *testing.T) {
+	r, synced := newWebhookRouter(&conf.WebhookConfig{Enabled: true, Secret: testWebhookSecret})
+	body := `{"source":"rss"}`
+
+	w := postWebhook(r, "/api/webhooks/generic", body, service.SignWebhookPayload("wrong-secret", []byte(body)))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = postWebhook(r, "/api/webhooks/memos", `{"activityType":"memos.memo.created"}`, "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "an unsigned request is rejected")
+
+	assert.Empty(t, *synced)
+}
+
+func TestWebhookHandler_Disabled(t *testing.T) {
+	r, synced := newWebhookRouter(&conf.WebhookConfig{Enabled: false, Secret: testWebhookSecret})
+	body := `{"source":"rss"}`
+
+	w := postWebhook(r, "/api/webhooks/generic", body, service.SignWebhookPayload(testWebhookSecret, []byte(body)))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, *synced)
+}
+
+func TestWebhookHandler_Generic(t *testing.T) {
+	r, synced := newWebhookRouter(&conf.WebhookConfig{Enabled: true, Secret: testWebhookSecret})
+	sign := func(body string) string { return service.SignWebhookPayload(testWebhookSecret, []byte(body)) }
+
+	body := `{"source":"rss","event":"feed.updated"}`
+	w := postWebhook(r, "/api/webhooks/generic", body, sign(body))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{"rss"}, *synced)
+
+	body = `{"source":"mastodon"}`
+	w = postWebhook(r, "/api/webhooks/generic", body, sign(body))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "mastodon has no sync_to")
+}
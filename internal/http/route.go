@@ -1,6 +1,8 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 
 	"connectrpc.com/connect"
@@ -26,20 +28,33 @@ func Router(r *gin.Engine) {
 		})
 	})
 
+	// Liveness and readiness probes; /readyz pings MongoDB and the Redis lock client
+	checks := map[string]handler.Pinger{
+		"mongo": handler.PingerFunc(pingMongo),
+	}
+	if conf.Conf.Locker == nil || conf.Conf.Locker.Type != dao.LockerMemory {
+		checks["redis"] = handler.PingerFunc(pingRedis)
+	}
+	health := handler.NewHealthHandler(checks)
+	r.GET("/healthz", health.Liveness)
+	r.GET("/readyz", health.Readiness)
+
 	jwtSecret := requireJWTSecret()
 
 	// The user store backs both credential checks and per-request token
 	// version validation (invalidating tokens issued before a password change).
 	userStore := auth.NewMongoUserStore(dao.NewMongoClient(), "hypersync")
+	// Logged-out access tokens are rejected until they expire.
+	revokedStore := auth.NewMongoRevokedTokenStore(dao.NewMongoClient(), "hypersync")
 
 	// ConnectRPC services
-	mountConnectRPC(r, jwtSecret, userStore)
+	mountConnectRPC(r, jwtSecret, userStore, revokedStore)
 
 	// API routes
 	api := r.Group("/api")
 	{
 		// Token management routes mutate platform state — same JWT as the RPCs.
-		tokenRoutes := api.Group("/token", auth.GinMiddleware(jwtSecret, userStore))
+		tokenRoutes := api.Group("/token", auth.GinMiddleware(jwtSecret, userStore, revokedStore))
 		{
 		schedulerService, err := wire.GetSchedulerService()
 		if err != nil {
@@ -52,7 +67,105 @@ func Router(r *gin.Engine) {
 			tokenRoutes.POST("/refresh/:platform", tokenHandler.RefreshToken)
 			tokenRoutes.POST("/refresh-all", tokenHandler.RefreshAllTokens)
 		}
+
+		// On-demand sync (optionally dry-run) — same JWT as the RPCs.
+		syncRoutes := api.Group("/sync", auth.GinMiddleware(jwtSecret, userStore, revokedStore))
+		{
+			syncHandler := handler.NewSyncHandler(syncSources(), wire.GetSyncService)
+
+			syncRoutes.POST("/trigger", syncHandler.TriggerSync)
+			// Same sync, with progress streamed as Server-Sent Events
+			syncRoutes.GET("/stream", syncHandler.StreamSync)
+
+			schedulerService, err := wire.GetSchedulerService()
+			if err != nil {
+				panic(err)
+			}
+			queueHandler := handler.NewQueueHandler(schedulerService)
+
+			syncRoutes.DELETE("/queue", queueHandler.ClearTaskQueue)
+
+			scheduleHandler := handler.NewScheduleHandler(schedulerService)
+
+			syncRoutes.GET("/schedules", scheduleHandler.GetSchedulerStatus)
+
+			// Pause/resume cross-posting to a target on the live sync services
+			syncTargetHandler := handler.NewSyncTargetHandler(syncSources(), wire.GetSyncService)
+
+			syncRoutes.POST("/targets/:platform/pause", syncTargetHandler.PauseTarget)
+			syncRoutes.POST("/targets/:platform/resume", syncTargetHandler.ResumeTarget)
+		}
+
+		// Configured platforms and their capabilities, for the config UI
+		schedulerService, err := wire.GetSchedulerService()
+		if err != nil {
+			panic(err)
+		}
+		platformHandler := handler.NewPlatformHandler(schedulerService, conf.Conf.Socials)
+		api.GET("/platforms", auth.GinMiddleware(jwtSecret, userStore, revokedStore), platformHandler.GetPlatforms)
+		platformTokenHandler := handler.NewTokenHandler(schedulerService)
+		api.GET("/platforms/:name/token", auth.GinMiddleware(jwtSecret, userStore, revokedStore), platformTokenHandler.GetPlatformToken)
+		api.POST("/platforms/:name/token/refresh", auth.GinMiddleware(jwtSecret, userStore, revokedStore), platformTokenHandler.RefreshPlatformToken)
+		// First-time Threads setup: swap a short-lived token for a long-lived one
+		api.POST("/platforms/:name/threads/exchange", auth.GinMiddleware(jwtSecret, userStore, revokedStore), platformTokenHandler.ExchangeThreadsToken)
+
+		// Effective configuration with secrets redacted
+		socialService, err := wire.GetSocialService()
+		if err != nil {
+			panic(err)
+		}
+		configHandler := handler.NewConfigHandler(socialService, syncSources(), wire.GetSyncService, dao.NewSyncSettingsDao(dao.NewMongoClient()))
+		api.GET("/config", auth.GinMiddleware(jwtSecret, userStore, revokedStore), configHandler.GetConfig)
+		api.PUT("/config", auth.GinMiddleware(jwtSecret, userStore, revokedStore), configHandler.UpdateConfig)
+
+		// Where a synced post has been cross-posted
+		postDao := dao.NewPostDao(dao.NewMongoClient())
+		postStatusHandler := handler.NewPostStatusHandler(postDao)
+		api.GET("/posts/:id/status", auth.GinMiddleware(jwtSecret, userStore, revokedStore), postStatusHandler.GetPostStatus)
+
+		// Re-attempt the failed targets of one synced post
+		postRetryHandler := handler.NewPostRetryHandler(postDao, syncSources(), wire.GetSyncService)
+		api.POST("/posts/:id/retry", auth.GinMiddleware(jwtSecret, userStore, revokedStore), postRetryHandler.RetryPost)
+
+		// Incoming webhooks trigger a sync; they are authenticated by their
+		// signature (webhook.secret), not by JWT.
+		webhookService := service.NewWebhookService(conf.Conf.Webhook, conf.Conf.Socials, wire.GetSyncService)
+		webhookService.SetSocialClients(wire.GetSocialServiceClients)
+		webhookHandler := handler.NewWebhookHandler(webhookService)
+		api.POST("/webhooks/memos", webhookHandler.MemosWebhook)
+		api.POST("/webhooks/generic", webhookHandler.GenericWebhook)
+		// Telegram 无法签名，按 secret_token 请求头校验
+		api.POST("/webhooks/telegram", webhookHandler.TelegramWebhook)
+	}
+}
+
+func pingMongo(ctx context.Context) error {
+	return dao.NewMongoClient().Ping(ctx, nil)
+}
+
+func pingRedis(ctx context.Context) error {
+	client := dao.NewRedisClient()
+	if client == nil {
+		return errors.New("redis locker client is not configured")
+	}
+	return client.Ping(ctx).Err()
+}
+
+// syncSources maps every configured source platform to its sync_to targets,
+// mirroring the scheduled jobs started by InitJob.
+func syncSources() map[string][]string {
+	sources := make(map[string][]string)
+	for name, social := range conf.Conf.Socials {
+		if len(social.SyncTo) == 0 {
+			continue
+		}
+		mainSocial := social.Name
+		if mainSocial == "" {
+			mainSocial = name
+		}
+		sources[mainSocial] = social.SyncTo
 	}
+	return sources
 }
 
 // requireJWTSecret fails startup when no usable JWT secret is configured. A
@@ -69,11 +182,21 @@ func requireJWTSecret() string {
 	return authConf.JWTSecret
 }
 
-func mountConnectRPC(r *gin.Engine, jwtSecret string, userStore *auth.MongoUserStore) {
+func mountConnectRPC(r *gin.Engine, jwtSecret string, userStore *auth.MongoUserStore, revokedStore *auth.MongoRevokedTokenStore) {
 	mongoClient := dao.NewMongoClient()
-	interceptor := auth.NewAuthInterceptor(jwtSecret, userStore)
+	interceptor := auth.NewAuthInterceptor(jwtSecret, userStore, revokedStore)
 
-	authService := service.NewAuthService(userStore, jwtSecret)
+	refreshStore := auth.NewMongoRefreshTokenStore(mongoClient, "hypersync")
+	var authOpts []service.AuthServiceOption
+	if gh := conf.Conf.Auth.Github; gh != nil && gh.ClientID != "" {
+		github := auth.NewGithubOAuth(gh.ClientID, gh.ClientSecret, gh.RedirectURL)
+		authOpts = append(authOpts, service.WithGithubLogin(github, userStore, gh.AllowedLogins))
+	}
+	authOpts = append(authOpts, service.WithPasswordReset(
+		auth.NewMongoPasswordResetStore(mongoClient, "hypersync"),
+		service.LogPasswordResetNotifier{ResetURL: conf.Conf.Auth.PasswordResetURL},
+	))
+	authService := service.NewAuthService(userStore, refreshStore, revokedStore, jwtSecret, authOpts...)
 	authPath, authHandler := v1connect.NewAuthServiceHandler(authService, connect.WithInterceptors(interceptor))
 	r.Any(authPath+"*path", gin.WrapH(authHandler))
 
@@ -86,10 +209,14 @@ func mountConnectRPC(r *gin.Engine, jwtSecret string, userStore *auth.MongoUserS
 	} else {
 		slog.Error("social service unavailable, cascade platform delete disabled", "error", err)
 	}
+	if socialService, err := wire.GetSocialService(); err == nil {
+		postOpts = append(postOpts, service.WithSocialService(socialService))
+	}
 
 	postService := service.NewPostService(postStore, postOpts...)
 	postPath, postHandler := v1connect.NewPostServiceHandler(postService, connect.WithInterceptors(interceptor))
 	r.Any(postPath+"*path", gin.WrapH(postHandler))
+	r.POST("/api/post", auth.GinMiddleware(jwtSecret, userStore, revokedStore), handler.NewPostHandler(postService).CreatePost)
 
 	// Media service
 	mediaStore := media.NewMongoStore(mongoClient, "hypersync")
@@ -112,5 +239,5 @@ func mountConnectRPC(r *gin.Engine, jwtSecret string, userStore *auth.MongoUserS
 	mediaService := service.NewMediaService(mediaStore, objectStorage, cdnDomain)
 	mediaPath, mediaHandler := v1connect.NewMediaServiceHandler(mediaService, connect.WithInterceptors(interceptor))
 	r.Any(mediaPath+"*path", gin.WrapH(mediaHandler))
-	r.POST("/api/media/upload", auth.GinMiddleware(jwtSecret, userStore), gin.WrapF(mediaService.HandleUpload))
+	r.POST("/api/media/upload", auth.GinMiddleware(jwtSecret, userStore, revokedStore), gin.WrapF(mediaService.HandleUpload))
 }
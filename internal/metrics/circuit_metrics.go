@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	CircuitBreakerState = mustInt64Gauge(
+		"hyper_sync_circuit_breaker_state",
+		"Circuit breaker state per target platform: 0 closed, 1 open, 2 half-open",
+	)
+	CircuitBreakerTransitionsTotal = mustInt64Counter(
+		"hyper_sync_circuit_breaker_transitions_total",
+		"Total number of circuit breaker state changes by new state",
+	)
+)
+
+// CircuitMetrics provides convenience methods for a target platform's
+// circuit breaker.
+type CircuitMetrics struct {
+	targetPlatform attribute.KeyValue
+}
+
+func NewCircuitMetrics(targetPlatform string) *CircuitMetrics {
+	return &CircuitMetrics{
+		targetPlatform: attribute.String(AttrTargetPlatform, targetPlatform),
+	}
+}
+
+// RecordTransition records the breaker entering state; value is the gauge
+// encoding of state (0 closed, 1 open, 2 half-open).
+func (m *CircuitMetrics) RecordTransition(state string, value int64) {
+	CircuitBreakerState.Record(context.Background(), value,
+		metric.WithAttributes(m.targetPlatform))
+	CircuitBreakerTransitionsTotal.Add(context.Background(), 1,
+		metric.WithAttributes(m.targetPlatform, attribute.String(AttrStatus, state)))
+}
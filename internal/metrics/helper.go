@@ -54,6 +54,12 @@ func (m *SyncMetrics) IncPostsProcessed(status string) {
 		metric.WithAttributes(m.mainSocial, attribute.String(AttrStatus, status)))
 }
 
+// IncPostsMuted counts a post skipped by a mute pattern. It is recorded in
+// addition to IncPostsProcessed(StatusSkippedMuted).
+func (m *SyncMetrics) IncPostsMuted() {
+	PostsMutedTotal.Add(context.Background(), 1, metric.WithAttributes(m.mainSocial))
+}
+
 func (m *SyncMetrics) IncCrossPosts(targetPlatform, status string) {
 	CrossPostsTotal.Add(context.Background(), 1,
 		metric.WithAttributes(m.mainSocial,
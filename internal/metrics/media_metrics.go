@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// AttrResult is the outcome of a media operation.
+const AttrResult = "result"
+
+var (
+	MediaDownloadedBytesTotal = mustInt64Counter(
+		"hypersync_media_downloaded_bytes_total",
+		"Total bytes of media downloaded from URLs",
+	)
+	MediaResizeDuration = mustFloat64Histogram(
+		"hypersync_media_resize_duration_seconds",
+		"Duration of image resize operations",
+		"s",
+	)
+	MediaResizedTotal = mustInt64Counter(
+		"hypersync_media_resized_total",
+		"Total number of image resize operations by result",
+	)
+)
+
+// RecordMediaDownload counts n bytes of media fetched from a URL for
+// platform.
+func RecordMediaDownload(platform string, n int) {
+	MediaDownloadedBytesTotal.Add(context.Background(), int64(n),
+		metric.WithAttributes(attribute.String(AttrPlatform, platform)))
+}
+
+// RecordMediaResize records one resize of an image for platform; result is
+// StatusSuccess or StatusError.
+func RecordMediaResize(platform, result string, duration time.Duration) {
+	MediaResizedTotal.Add(context.Background(), 1,
+		metric.WithAttributes(
+			attribute.String(AttrPlatform, platform),
+			attribute.String(AttrResult, result),
+		))
+	MediaResizeDuration.Record(context.Background(), duration.Seconds())
+}
@@ -32,6 +32,14 @@ func mustInt64UpDownCounter(name, desc string) metric.Int64UpDownCounter {
 	return c
 }
 
+func mustFloat64Gauge(name, desc, unit string) metric.Float64Gauge {
+	g, err := meter.Float64Gauge(name, metric.WithDescription(desc), metric.WithUnit(unit))
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
 func mustInt64Gauge(name, desc string) metric.Int64Gauge {
 	g, err := meter.Int64Gauge(name, metric.WithDescription(desc))
 	if err != nil {
@@ -74,21 +82,35 @@ var (
 		"hyper_sync_retries_total",
 		"Total number of retry attempts",
 	)
+	PostsMutedTotal = mustInt64Counter(
+		"hyper_sync_posts_muted_total",
+		"Total number of source posts skipped by sync.mute_patterns",
+	)
 )
 
 const (
-	StatusProcessed     = "processed"
-	StatusSkippedOld    = "skipped_old"
-	StatusSkippedDirect = "skipped_direct"
-	StatusExists        = "exists"
-	StatusSuccess       = "success"
-	StatusError         = "error"
+	StatusProcessed         = "processed"
+	StatusSkippedOld        = "skipped_old"
+	StatusSkippedDirect     = "skipped_direct"
+	StatusSkippedPrivate    = "skipped_private"
+	StatusSkippedFiltered   = "skipped_filtered"
+	StatusSkippedMirror     = "skipped_mirror"
+	StatusSkippedMuted      = "skipped_muted"
+	StatusSkippedPaused     = "skipped_paused"
+	StatusSkippedVisibility = "skipped_visibility"
+	StatusSkippedNoPost     = "skipped_no_post"
+	StatusExists            = "exists"
+	StatusSuccess           = "success"
+	StatusError             = "error"
+	StatusDryRun            = "dry_run"
+	StatusUpdated           = "updated"
 
 	OperationFetchPosts     = "fetch_posts"
 	OperationSyncToPlatform = "sync_to_platform"
 	OperationTotal          = "total"
 	OperationGetPost        = "get_post"
 	OperationCreatePost     = "create_post"
+	OperationUpdatePost     = "update_post"
 	OperationUpdateStatus   = "update_status"
 
 	ErrorTypePlatform = "platform_error"
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const AttrPlatform = "platform"
+
+var (
+	TokenExpiresIn = mustFloat64Gauge(
+		"hypersync_token_expires_in_seconds",
+		"Seconds until the platform's access token expires; negative once expired, +Inf if it never expires",
+		"s",
+	)
+	TokenRefreshTotal = mustInt64Counter(
+		"hypersync_token_refresh_total",
+		"Total number of token refresh checks by result",
+	)
+)
+
+// TokenMetrics provides convenience methods for token lifetime metrics.
+type TokenMetrics struct {
+	platform attribute.KeyValue
+}
+
+func NewTokenMetrics(platform string) *TokenMetrics {
+	return &TokenMetrics{
+		platform: attribute.String(AttrPlatform, platform),
+	}
+}
+
+// SetExpiresIn records the time left on the token; pass a negative duration
+// for a token that has already expired.
+func (m *TokenMetrics) SetExpiresIn(d time.Duration) {
+	TokenExpiresIn.Record(context.Background(), d.Seconds(),
+		metric.WithAttributes(m.platform))
+}
+
+// SetNoExpiry records +Inf, so "expires within N" alerts never fire for
+// tokens without an expiry.
+func (m *TokenMetrics) SetNoExpiry() {
+	TokenExpiresIn.Record(context.Background(), math.Inf(1),
+		metric.WithAttributes(m.platform))
+}
+
+func (m *TokenMetrics) IncRefresh(status string) {
+	TokenRefreshTotal.Add(context.Background(), 1,
+		metric.WithAttributes(m.platform, attribute.String(AttrStatus, status)))
+}
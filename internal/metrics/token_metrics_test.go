@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTokenMetrics(t *testing.T) {
+	// The package's instruments come from the global meter, which forwards to
+	// whichever provider is installed, even after the instruments exist.
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	NewTokenMetrics("threads").SetExpiresIn(36 * time.Hour)
+	NewTokenMetrics("bluesky").SetExpiresIn(-5 * time.Minute)
+	NewTokenMetrics("mastodon").SetNoExpiry()
+
+	threads := NewTokenMetrics("threads")
+	threads.IncRefresh(StatusSuccess)
+	threads.IncRefresh(StatusSuccess)
+	threads.IncRefresh(StatusError)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	gauge, ok := findMetric(rm, "hypersync_token_expires_in_seconds").(metricdata.Gauge[float64])
+	require.True(t, ok, "expires_in should be a float64 gauge")
+	expiresIn := make(map[string]float64)
+	for _, dp := range gauge.DataPoints {
+		expiresIn[attrValue(dp.Attributes, AttrPlatform)] = dp.Value
+	}
+	assert.Equal(t, (36 * time.Hour).Seconds(), expiresIn["threads"])
+	assert.Equal(t, -300.0, expiresIn["bluesky"])
+	assert.True(t, math.IsInf(expiresIn["mastodon"], 1))
+
+	counter, ok := findMetric(rm, "hypersync_token_refresh_total").(metricdata.Sum[int64])
+	require.True(t, ok, "refresh_total should be an int64 sum")
+	refreshes := make(map[string]int64)
+	for _, dp := range counter.DataPoints {
+		refreshes[attrValue(dp.Attributes, AttrPlatform)+"/"+attrValue(dp.Attributes, AttrStatus)] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"threads/success": 2, "threads/error": 1}, refreshes)
+}
+
+func findMetric(rm metricdata.ResourceMetrics, name string) metricdata.Aggregation {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	return nil
+}
+
+func attrValue(set attribute.Set, key string) string {
+	v, _ := set.Value(attribute.Key(key))
+	return v.AsString()
+}
@@ -3,8 +3,12 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"butterfly.orx.me/core/log"
 	"connectrpc.com/connect"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
@@ -13,16 +17,59 @@ import (
 	v1 "go.orx.me/apps/hyper-sync/pkg/proto/api/v1"
 )
 
+const (
+	accessTokenTTL   = 24 * time.Hour
+	refreshTokenTTL  = 30 * 24 * time.Hour
+	passwordResetTTL = time.Hour
+)
+
+type AuthServiceOption func(*AuthService)
+
+// WithGithubLogin enables LoginWithGithub. GitHub users are matched to local
+// users by linked GitHub id, then by verified email; a new account is only
+// created for logins listed in allowedLogins.
+func WithGithubLogin(github *auth.GithubOAuth, users auth.OAuthUserStore, allowedLogins []string) AuthServiceOption {
+	return func(s *AuthService) {
+		s.github = github
+		s.oauthUsers = users
+		s.allowedGithubLogins = allowedLogins
+	}
+}
+
+// WithPasswordReset enables RequestPasswordReset/ConfirmPasswordReset.
+// notifier delivers the reset token to the user.
+func WithPasswordReset(resets auth.PasswordResetStore, notifier PasswordResetNotifier) AuthServiceOption {
+	return func(s *AuthService) {
+		s.resetStore = resets
+		s.resetNotifier = notifier
+	}
+}
+
 type AuthService struct {
-	userStore auth.UserStore
-	jwtSecret string
+	userStore    auth.UserStore
+	refreshStore auth.RefreshTokenStore
+	revokedStore auth.RevokedTokenStore
+	jwtSecret    string
+
+	github              *auth.GithubOAuth
+	oauthUsers          auth.OAuthUserStore
+	allowedGithubLogins []string
+
+	resetStore    auth.PasswordResetStore
+	resetNotifier PasswordResetNotifier
 }
 
-func NewAuthService(userStore auth.UserStore, jwtSecret string) *AuthService {
-	return &AuthService{
-		userStore: userStore,
-		jwtSecret: jwtSecret,
+func NewAuthService(userStore auth.UserStore, refreshStore auth.RefreshTokenStore, revokedStore auth.RevokedTokenStore, jwtSecret string, opts ...AuthServiceOption) *AuthService {
+	s := &AuthService{
+		userStore:    userStore,
+		refreshStore: refreshStore,
+		revokedStore: revokedStore,
+		jwtSecret:    jwtSecret,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // dummyHash keeps the not-found path doing a bcrypt comparison so response
@@ -40,22 +87,425 @@ func (s *AuthService) Login(ctx context.Context, req *connect.Request[v1.LoginRe
 		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
 	}
 
-	expiresAt := time.Now().Add(24 * time.Hour)
+	familyID, err := auth.NewTokenFamilyID()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, nil)
+	}
+
+	tokens, err := s.issueTokens(ctx, user, familyID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, nil)
+	}
+
+	return connect.NewResponse(&v1.LoginResponse{
+		Token:            tokens.accessToken,
+		ExpiresAt:        tokens.accessExpiresAt.Unix(),
+		RefreshToken:     tokens.refreshToken,
+		RefreshExpiresAt: tokens.refreshExpiresAt.Unix(),
+	}), nil
+}
+
+// LoginWithGithub completes a GitHub OAuth sign-in: the front end sends the
+// authorization code from GitHub's redirect and receives the same tokens as
+// Login.
+func (s *AuthService) LoginWithGithub(ctx context.Context, req *connect.Request[v1.LoginWithGithubRequest]) (*connect.Response[v1.LoginResponse], error) {
+	logger := log.FromContext(ctx)
+
+	if s.github == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, errors.New("github login is not configured"))
+	}
+	if req.Msg.Code == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("code is required"))
+	}
+
+	identity, err := s.github.Exchange(ctx, req.Msg.Code)
+	if err != nil {
+		if errors.Is(err, auth.ErrGithubCodeRejected) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+		}
+		logger.Error("github oauth exchange failed", "error", err)
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+
+	user, err := s.findOrCreateOAuthUser(ctx, oauthIdentity{
+		provider: "github",
+		id:       identity.ID,
+		login:    identity.Login,
+		email:    identity.Email,
+	})
+	if err != nil {
+		if errors.Is(err, errOAuthUserNotAllowed) {
+			logger.Warn("github login rejected: no matching user", "github_login", identity.Login)
+			return nil, connect.NewError(connect.CodePermissionDenied, err)
+		}
+		logger.Error("failed to resolve github user", "github_login", identity.Login, "error", err)
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+
+	familyID, err := auth.NewTokenFamilyID()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, nil)
+	}
+
+	tokens, err := s.issueTokens(ctx, user, familyID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, nil)
+	}
+
+	logger.Info("user logged in with github", "username", user.Username, "github_login", identity.Login)
+
+	return connect.NewResponse(&v1.LoginResponse{
+		Token:            tokens.accessToken,
+		ExpiresAt:        tokens.accessExpiresAt.Unix(),
+		RefreshToken:     tokens.refreshToken,
+		RefreshExpiresAt: tokens.refreshExpiresAt.Unix(),
+	}), nil
+}
+
+var errOAuthUserNotAllowed = errors.New("no user is linked to this account and it is not allowed to sign up")
+
+// oauthIdentity is a provider-neutral view of an external account.
+type oauthIdentity struct {
+	provider string
+	id       string
+	login    string
+	// email must already be verified by the provider.
+	email string
+}
+
+// findOrCreateOAuthUser resolves an external identity to a local user:
+// an already linked user wins; otherwise a user with the same verified email
+// is linked; otherwise a new user is created if the login is allowlisted.
+func (s *AuthService) findOrCreateOAuthUser(ctx context.Context, identity oauthIdentity) (*auth.User, error) {
+	logger := log.FromContext(ctx)
+
+	user, err := s.oauthUsers.GetByGithubID(ctx, identity.id)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, auth.ErrUserNotFound) {
+		return nil, fmt.Errorf("get user by %s id: %w", identity.provider, err)
+	}
+
+	if identity.email != "" {
+		user, err = s.oauthUsers.GetByEmail(ctx, identity.email)
+		if err == nil {
+			if err := s.oauthUsers.LinkGithub(ctx, user.Username, identity.id); err != nil {
+				return nil, fmt.Errorf("link %s account: %w", identity.provider, err)
+			}
+			user.GithubID = identity.id
+			logger.Info("linked oauth account by email",
+				"provider", identity.provider,
+				"username", user.Username,
+				"login", identity.login)
+			return user, nil
+		}
+		if !errors.Is(err, auth.ErrUserNotFound) {
+			return nil, fmt.Errorf("get user by email: %w", err)
+		}
+	}
+
+	if !slices.ContainsFunc(s.allowedGithubLogins, func(l string) bool { return strings.EqualFold(l, identity.login) }) {
+		return nil, errOAuthUserNotAllowed
+	}
+
+	// The provider prefix keeps OAuth-created usernames from colliding with
+	// password users. PasswordHash stays empty, so password login never
+	// matches.
+	user = &auth.User{
+		Username: identity.provider + ":" + identity.login,
+		Email:    identity.email,
+		GithubID: identity.id,
+	}
+	if err := s.oauthUsers.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+	logger.Info("created user from oauth login",
+		"provider", identity.provider,
+		"username", user.Username)
+	return user, nil
+}
+
+// RequestPasswordReset issues a single-use reset token for the user with the
+// given email and hands it to the notifier. The response is the same whether
+// or not the email belongs to a user, so the RPC cannot be used to probe for
+// accounts.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, req *connect.Request[v1.RequestPasswordResetRequest]) (*connect.Response[v1.RequestPasswordResetResponse], error) {
+	logger := log.FromContext(ctx)
+
+	if s.resetStore == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, errors.New("password reset is not configured"))
+	}
+	if strings.TrimSpace(req.Msg.Email) == "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("email is required"))
+	}
+
+	user, err := s.userStore.GetByEmail(ctx, req.Msg.Email)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			logger.Info("password reset requested for unknown email")
+			return connect.NewResponse(&v1.RequestPasswordResetResponse{}), nil
+		}
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+
+	token, tokenHash, err := auth.NewPasswordResetToken()
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, nil)
+	}
+	now := time.Now()
+	expiresAt := now.Add(passwordResetTTL)
+	if err := s.resetStore.Create(ctx, &auth.PasswordResetToken{
+		TokenHash:    tokenHash,
+		Username:     user.Username,
+		TokenVersion: user.TokenVersion,
+		ExpiresAt:    expiresAt,
+		CreatedAt:    now,
+	}); err != nil {
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+
+	// A delivery failure is logged, not returned: an error here would only
+	// happen for existing users and so would reveal the account.
+	if err := s.resetNotifier.SendPasswordReset(ctx, user, token, expiresAt); err != nil {
+		logger.Error("failed to send password reset", "username", user.Username, "error", err)
+	}
+
+	return connect.NewResponse(&v1.RequestPasswordResetResponse{}), nil
+}
+
+// ConfirmPasswordReset redeems a reset token and sets the new password. The
+// password change bumps TokenVersion, which ends every existing session and
+// invalidates any other outstanding reset token.
+func (s *AuthService) ConfirmPasswordReset(ctx context.Context, req *connect.Request[v1.ConfirmPasswordResetRequest]) (*connect.Response[v1.ConfirmPasswordResetResponse], error) {
+	logger := log.FromContext(ctx)
+
+	if s.resetStore == nil {
+		return nil, connect.NewError(connect.CodeUnimplemented, errors.New("password reset is not configured"))
+	}
+	// Checked before the token is consumed, so a rejected password does not
+	// burn the link.
+	if len(req.Msg.NewPassword) < 8 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("new password must be at least 8 characters"))
+	}
+	if req.Msg.Token == "" {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+	tokenHash := auth.HashPasswordResetToken(req.Msg.Token)
+
+	stored, err := s.resetStore.GetByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, auth.ErrPasswordResetTokenNotFound) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+		}
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+	if stored.Used || time.Now().After(stored.ExpiresAt) {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	user, err := s.userStore.GetByUsername(ctx, stored.Username)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+		}
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+	if stored.TokenVersion != user.TokenVersion {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	if err := s.resetStore.MarkUsed(ctx, tokenHash); err != nil {
+		if errors.Is(err, auth.ErrPasswordResetTokenUsed) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+		}
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+
+	newHash, err := bcrypt.GenerateFromPassword([]byte(req.Msg.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, nil)
+	}
+	if err := s.userStore.UpdatePassword(ctx, user.Username, string(newHash)); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, nil)
+	}
+
+	logger.Info("password reset completed", "username", user.Username)
+
+	return connect.NewResponse(&v1.ConfirmPasswordResetResponse{}), nil
+}
+
+// RefreshToken exchanges a refresh token for a new access token. Refresh
+// tokens are single use: each call invalidates the presented token and returns
+// its successor. Presenting a token that was already rotated means it leaked,
+// so the whole family from that login is revoked and the user must log in
+// again.
+func (s *AuthService) RefreshToken(ctx context.Context, req *connect.Request[v1.RefreshTokenRequest]) (*connect.Response[v1.RefreshTokenResponse], error) {
+	logger := log.FromContext(ctx)
+
+	if req.Msg.RefreshToken == "" {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+	tokenHash := auth.HashRefreshToken(req.Msg.RefreshToken)
+
+	stored, err := s.refreshStore.GetByHash(ctx, tokenHash)
+	if err != nil {
+		if errors.Is(err, auth.ErrRefreshTokenNotFound) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+		}
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+
+	if stored.Used || stored.Revoked {
+		s.revokeFamily(ctx, stored, "reused")
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	user, err := s.userStore.GetByUsername(ctx, stored.Username)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+		}
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+
+	// A password change bumps TokenVersion; sessions from before it end here.
+	if stored.TokenVersion != user.TokenVersion {
+		s.revokeFamily(ctx, stored, "password_changed")
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+
+	if err := s.refreshStore.MarkUsed(ctx, tokenHash); err != nil {
+		if errors.Is(err, auth.ErrRefreshTokenUsed) {
+			// Lost a race with another refresh of the same token.
+			s.revokeFamily(ctx, stored, "reused")
+			return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+		}
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+
+	tokens, err := s.issueTokens(ctx, user, stored.FamilyID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, nil)
+	}
+
+	logger.Info("refresh token rotated", "username", user.Username, "family_id", stored.FamilyID)
+
+	return connect.NewResponse(&v1.RefreshTokenResponse{
+		Token:            tokens.accessToken,
+		ExpiresAt:        tokens.accessExpiresAt.Unix(),
+		RefreshToken:     tokens.refreshToken,
+		RefreshExpiresAt: tokens.refreshExpiresAt.Unix(),
+	}), nil
+}
+
+// Logout revokes the access token the request was made with until it would
+// have expired anyway. If the client also sends its refresh token, that
+// token's family is revoked so the session cannot be silently renewed.
+func (s *AuthService) Logout(ctx context.Context, req *connect.Request[v1.LogoutRequest]) (*connect.Response[v1.LogoutResponse], error) {
+	logger := log.FromContext(ctx)
+
+	username := auth.UsernameFromContext(ctx)
+	token, ok := auth.AccessTokenFromContext(ctx)
+	if username == "" || !ok {
+		return nil, connect.NewError(connect.CodeUnauthenticated, nil)
+	}
+	if token.ID == "" {
+		return nil, connect.NewError(connect.CodeFailedPrecondition,
+			errors.New("token predates revocation support; log in again to get a revocable token"))
+	}
+
+	if err := s.revokedStore.RevokeToken(ctx, token.ID, token.ExpiresAt); err != nil {
+		logger.Error("failed to revoke access token", "username", username, "error", err)
+		return nil, connect.NewError(connect.CodeUnavailable, nil)
+	}
+
+	if req.Msg.RefreshToken != "" {
+		stored, err := s.refreshStore.GetByHash(ctx, auth.HashRefreshToken(req.Msg.RefreshToken))
+		switch {
+		case err == nil && stored.Username == username:
+			s.revokeFamily(ctx, stored, "logout")
+		case err != nil && !errors.Is(err, auth.ErrRefreshTokenNotFound):
+			logger.Error("failed to look up refresh token on logout", "username", username, "error", err)
+		}
+	}
+
+	logger.Info("user logged out", "username", username)
+
+	return connect.NewResponse(&v1.LogoutResponse{}), nil
+}
+
+type issuedTokens struct {
+	accessToken      string
+	accessExpiresAt  time.Time
+	refreshToken     string
+	refreshExpiresAt time.Time
+}
+
+// issueTokens signs an access token for user and stores a new refresh token
+// in the given family.
+func (s *AuthService) issueTokens(ctx context.Context, user *auth.User, familyID string) (*issuedTokens, error) {
+	tokenID, err := auth.NewTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	accessExpiresAt := now.Add(accessTokenTTL)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub":                  user.Username,
-		"exp":                  expiresAt.Unix(),
+		"exp":                  accessExpiresAt.Unix(),
+		"jti":                  tokenID,
 		auth.TokenVersionClaim: user.TokenVersion,
 	})
 
 	tokenString, err := token.SignedString([]byte(s.jwtSecret))
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, nil)
+		return nil, err
 	}
 
-	return connect.NewResponse(&v1.LoginResponse{
-		Token:     tokenString,
-		ExpiresAt: expiresAt.Unix(),
-	}), nil
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshExpiresAt := now.Add(refreshTokenTTL)
+	if err := s.refreshStore.Create(ctx, &auth.RefreshToken{
+		TokenHash:    refreshHash,
+		Username:     user.Username,
+		FamilyID:     familyID,
+		TokenVersion: user.TokenVersion,
+		ExpiresAt:    refreshExpiresAt,
+		CreatedAt:    now,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &issuedTokens{
+		accessToken:      tokenString,
+		accessExpiresAt:  accessExpiresAt,
+		refreshToken:     refreshToken,
+		refreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// revokeFamily revokes every refresh token descended from the same login as
+// stored. Failure is logged rather than returned: the caller is already
+// rejecting the request.
+func (s *AuthService) revokeFamily(ctx context.Context, stored *auth.RefreshToken, reason string) {
+	logger := log.FromContext(ctx)
+	logger.Warn("revoking refresh token family",
+		"username", stored.Username,
+		"family_id", stored.FamilyID,
+		"reason", reason)
+	if err := s.refreshStore.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		logger.Error("failed to revoke refresh token family",
+			"family_id", stored.FamilyID,
+			"error", err)
+	}
 }
 
 func (s *AuthService) ChangePassword(ctx context.Context, req *connect.Request[v1.ChangePasswordRequest]) (*connect.Response[v1.ChangePasswordResponse], error) {
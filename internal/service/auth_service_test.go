@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -34,7 +35,7 @@ func setupAuthTest(t *testing.T, users ...*auth.User) (v1connect.AuthServiceClie
 		require.NoError(t, err)
 	}
 
-	svc := service.NewAuthService(store, testJWTSecret)
+	svc := service.NewAuthService(store, auth.NewMemoryRefreshTokenStore(), auth.NewMemoryRevokedTokenStore(), testJWTSecret)
 
 	mux := http.NewServeMux()
 	path, handler := v1connect.NewAuthServiceHandler(svc)
@@ -120,8 +121,9 @@ func setupProtectedAuthTest(t *testing.T, users ...*auth.User) (v1connect.AuthSe
 func newProtectedClient(t *testing.T, store auth.UserStore) (v1connect.AuthServiceClient, func()) {
 	t.Helper()
 
-	svc := service.NewAuthService(store, testJWTSecret)
-	interceptor := auth.NewAuthInterceptor(testJWTSecret, store)
+	revokedStore := auth.NewMemoryRevokedTokenStore()
+	svc := service.NewAuthService(store, auth.NewMemoryRefreshTokenStore(), revokedStore, testJWTSecret)
+	interceptor := auth.NewAuthInterceptor(testJWTSecret, store, revokedStore)
 
 	mux := http.NewServeMux()
 	path, handler := v1connect.NewAuthServiceHandler(svc, connect.WithInterceptors(interceptor))
@@ -142,6 +144,9 @@ func (outageStore) GetByUsername(context.Context, string) (*auth.User, error) {
 }
 func (outageStore) Create(context.Context, *auth.User) error             { return nil }
 func (outageStore) UpdatePassword(context.Context, string, string) error { return nil }
+func (outageStore) GetByEmail(context.Context, string) (*auth.User, error) {
+	return nil, errors.New("mongo: connection refused")
+}
 
 func TestProtectedEndpoint_NoToken_ReturnsUnauthenticated(t *testing.T) {
 	user := &auth.User{
@@ -376,3 +381,498 @@ func TestProtectedEndpoint_StoreOutage_ReturnsUnavailableNotUnauthenticated(t *t
 	require.Error(t, err)
 	assert.Equal(t, connect.CodeUnavailable, connect.CodeOf(err))
 }
+
+// setupRefreshTest mounts the AuthService behind the real interceptor and
+// exposes the refresh token store so tests can plant tokens directly.
+func setupRefreshTest(t *testing.T, users ...*auth.User) (v1connect.AuthServiceClient, *auth.MemoryRefreshTokenStore, func()) {
+	t.Helper()
+
+	store := auth.NewMemoryUserStore()
+	for _, u := range users {
+		require.NoError(t, store.Create(context.Background(), u))
+	}
+	refreshStore := auth.NewMemoryRefreshTokenStore()
+
+	revokedStore := auth.NewMemoryRevokedTokenStore()
+	svc := service.NewAuthService(store, refreshStore, revokedStore, testJWTSecret)
+	interceptor := auth.NewAuthInterceptor(testJWTSecret, store, revokedStore)
+
+	mux := http.NewServeMux()
+	path, handler := v1connect.NewAuthServiceHandler(svc, connect.WithInterceptors(interceptor))
+	mux.Handle(path, handler)
+
+	server := httptest.NewServer(mux)
+	client := v1connect.NewAuthServiceClient(server.Client(), server.URL)
+
+	return client, refreshStore, server.Close
+}
+
+func refresh(client v1connect.AuthServiceClient, token string) (*connect.Response[v1.RefreshTokenResponse], error) {
+	return client.RefreshToken(context.Background(), connect.NewRequest(&v1.RefreshTokenRequest{
+		RefreshToken: token,
+	}))
+}
+
+func TestRefreshToken_RotatesAndIssuesWorkingAccessToken(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "password")}
+	client, _, cleanup := setupRefreshTest(t, user)
+	defer cleanup()
+
+	loginResp, err := client.Login(context.Background(), connect.NewRequest(&v1.LoginRequest{
+		Username: "admin",
+		Password: "password",
+	}))
+	require.NoError(t, err)
+	require.NotEmpty(t, loginResp.Msg.RefreshToken)
+	assert.Greater(t, loginResp.Msg.RefreshExpiresAt, loginResp.Msg.ExpiresAt)
+
+	// No Authorization header: the refresh token alone must be enough.
+	resp, err := refresh(client, loginResp.Msg.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Msg.Token)
+	assert.NotEmpty(t, resp.Msg.RefreshToken)
+	assert.NotEqual(t, loginResp.Msg.RefreshToken, resp.Msg.RefreshToken, "refresh token must rotate")
+
+	req := connect.NewRequest(&v1.ChangePasswordRequest{
+		CurrentPassword: "password",
+		NewPassword:     "new-password-123",
+	})
+	req.Header().Set("Authorization", "Bearer "+resp.Msg.Token)
+	_, err = client.ChangePassword(context.Background(), req)
+	require.NoError(t, err, "refreshed access token must be accepted")
+
+	// The password change ends every session, including refresh tokens.
+	_, err = refresh(client, resp.Msg.RefreshToken)
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func TestRefreshToken_Expired_ReturnsUnauthenticated(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "password")}
+	client, refreshStore, cleanup := setupRefreshTest(t, user)
+	defer cleanup()
+
+	require.NoError(t, refreshStore.Create(context.Background(), &auth.RefreshToken{
+		TokenHash: auth.HashRefreshToken("expired-token"),
+		Username:  "admin",
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}))
+
+	_, err := refresh(client, "expired-token")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	_, err = refresh(client, "never-issued")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func TestRefreshToken_Reuse_RevokesFamily(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "password")}
+	client, _, cleanup := setupRefreshTest(t, user)
+	defer cleanup()
+
+	loginResp, err := client.Login(context.Background(), connect.NewRequest(&v1.LoginRequest{
+		Username: "admin",
+		Password: "password",
+	}))
+	require.NoError(t, err)
+	first := loginResp.Msg.RefreshToken
+
+	rotated, err := refresh(client, first)
+	require.NoError(t, err)
+
+	// Replaying the rotated-away token is treated as theft.
+	_, err = refresh(client, first)
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	// ...which revokes its successor too.
+	_, err = refresh(client, rotated.Msg.RefreshToken)
+	require.Error(t, err, "reuse must revoke the whole token family")
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	// A fresh login starts a new, unaffected family.
+	relogin, err := client.Login(context.Background(), connect.NewRequest(&v1.LoginRequest{
+		Username: "admin",
+		Password: "password",
+	}))
+	require.NoError(t, err)
+	_, err = refresh(client, relogin.Msg.RefreshToken)
+	require.NoError(t, err)
+}
+
+func login(t *testing.T, client v1connect.AuthServiceClient, username, password string) *v1.LoginResponse {
+	t.Helper()
+	resp, err := client.Login(context.Background(), connect.NewRequest(&v1.LoginRequest{
+		Username: username,
+		Password: password,
+	}))
+	require.NoError(t, err)
+	return resp.Msg
+}
+
+func logout(client v1connect.AuthServiceClient, accessToken, refreshToken string) error {
+	req := connect.NewRequest(&v1.LogoutRequest{RefreshToken: refreshToken})
+	req.Header().Set("Authorization", "Bearer "+accessToken)
+	_, err := client.Logout(context.Background(), req)
+	return err
+}
+
+// callProtected calls ChangePassword with a too-short new password: a token
+// that gets past the interceptor fails with InvalidArgument and nothing
+// changes, so the call can be repeated.
+func callProtected(client v1connect.AuthServiceClient, accessToken, password string) error {
+	req := connect.NewRequest(&v1.ChangePasswordRequest{
+		CurrentPassword: password,
+		NewPassword:     "short",
+	})
+	req.Header().Set("Authorization", "Bearer "+accessToken)
+	_, err := client.ChangePassword(context.Background(), req)
+	return err
+}
+
+func TestLogout_RevokesOnlyThatAccessToken(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "password")}
+	client, cleanup := setupProtectedAuthTest(t, user)
+	defer cleanup()
+
+	loggedOut := login(t, client, "admin", "password")
+	other := login(t, client, "admin", "password")
+
+	require.NoError(t, logout(client, loggedOut.Token, ""))
+
+	err := callProtected(client, loggedOut.Token, "password")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err), "logged-out token must be rejected")
+
+	err = callProtected(client, other.Token, "password")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err), "a token from another login must still pass")
+
+	// Logging out again with the revoked token is itself rejected.
+	err = logout(client, loggedOut.Token, "")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func TestLogout_RevokesRefreshToken(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "password")}
+	client, _, cleanup := setupRefreshTest(t, user)
+	defer cleanup()
+
+	session := login(t, client, "admin", "password")
+	require.NoError(t, logout(client, session.Token, session.RefreshToken))
+
+	_, err := refresh(client, session.RefreshToken)
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func TestLogout_RequiresAuthentication(t *testing.T) {
+	client, cleanup := setupProtectedAuthTest(t)
+	defer cleanup()
+
+	_, err := client.Logout(context.Background(), connect.NewRequest(&v1.LogoutRequest{}))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+// fakeGithub serves the three GitHub endpoints used by the OAuth flow. Only
+// the code "valid-code" is accepted.
+type fakeGithub struct {
+	id            int64
+	login         string
+	email         string
+	emailVerified bool
+}
+
+func (f fakeGithub) serve(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		if r.PostForm.Get("code") != "valid-code" {
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "gh-access-token"})
+	})
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer gh-access-token", r.Header.Get("Authorization"))
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": f.id, "login": f.login})
+	})
+	mux.HandleFunc("GET /user/emails", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]map[string]any{
+			{"email": "noreply@example.com", "primary": false, "verified": true},
+			{"email": f.email, "primary": true, "verified": f.emailVerified},
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func setupGithubLoginTest(t *testing.T, gh fakeGithub, allowedLogins []string, users ...*auth.User) (v1connect.AuthServiceClient, *auth.MemoryUserStore, func()) {
+	t.Helper()
+
+	store := auth.NewMemoryUserStore()
+	for _, u := range users {
+		require.NoError(t, store.Create(context.Background(), u))
+	}
+
+	githubServer := gh.serve(t)
+	github := auth.NewGithubOAuth("client-id", "client-secret", "")
+	github.TokenURL = githubServer.URL + "/login/oauth/access_token"
+	github.APIURL = githubServer.URL
+
+	revokedStore := auth.NewMemoryRevokedTokenStore()
+	svc := service.NewAuthService(store, auth.NewMemoryRefreshTokenStore(), revokedStore, testJWTSecret,
+		service.WithGithubLogin(github, store, allowedLogins))
+	interceptor := auth.NewAuthInterceptor(testJWTSecret, store, revokedStore)
+
+	mux := http.NewServeMux()
+	path, handler := v1connect.NewAuthServiceHandler(svc, connect.WithInterceptors(interceptor))
+	mux.Handle(path, handler)
+
+	server := httptest.NewServer(mux)
+	client := v1connect.NewAuthServiceClient(server.Client(), server.URL)
+
+	return client, store, func() {
+		server.Close()
+		githubServer.Close()
+	}
+}
+
+func tokenSubject(t *testing.T, token string) string {
+	t.Helper()
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(testJWTSecret), nil
+	})
+	require.NoError(t, err)
+	sub, _ := claims.GetSubject()
+	return sub
+}
+
+func loginWithGithub(client v1connect.AuthServiceClient, code string) (*connect.Response[v1.LoginResponse], error) {
+	return client.LoginWithGithub(context.Background(), connect.NewRequest(&v1.LoginWithGithubRequest{
+		Code: code,
+	}))
+}
+
+func TestLoginWithGithub_AllowedLogin_CreatesUser(t *testing.T) {
+	gh := fakeGithub{id: 583231, login: "octocat", email: "Octo@Example.com", emailVerified: true}
+	client, store, cleanup := setupGithubLoginTest(t, gh, []string{"OctoCat"})
+	defer cleanup()
+
+	resp, err := loginWithGithub(client, "valid-code")
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Msg.Token)
+	assert.NotEmpty(t, resp.Msg.RefreshToken)
+
+	user, err := store.GetByGithubID(context.Background(), "583231")
+	require.NoError(t, err)
+	assert.Equal(t, "github:octocat", user.Username)
+	assert.Equal(t, "octo@example.com", user.Email)
+
+	// The issued token authenticates as the new user.
+	assert.Equal(t, "github:octocat", tokenSubject(t, resp.Msg.Token))
+	require.NoError(t, logout(client, resp.Msg.Token, ""))
+
+	// Signing in again reuses the linked user instead of creating another.
+	again, err := loginWithGithub(client, "valid-code")
+	require.NoError(t, err)
+	assert.Equal(t, "github:octocat", tokenSubject(t, again.Msg.Token))
+}
+
+func TestLoginWithGithub_LinksExistingUserByVerifiedEmail(t *testing.T) {
+	admin := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "password"), Email: "octo@example.com"}
+	gh := fakeGithub{id: 583231, login: "octocat", email: "octo@example.com", emailVerified: true}
+	client, store, cleanup := setupGithubLoginTest(t, gh, nil, admin)
+	defer cleanup()
+
+	resp, err := loginWithGithub(client, "valid-code")
+	require.NoError(t, err)
+
+	user, err := store.GetByUsername(context.Background(), "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "583231", user.GithubID)
+
+	assert.Equal(t, "admin", tokenSubject(t, resp.Msg.Token))
+
+	_, err = store.GetByUsername(context.Background(), "github:octocat")
+	assert.ErrorIs(t, err, auth.ErrUserNotFound, "linking must not create a second user")
+}
+
+func TestLoginWithGithub_UnverifiedEmailNotLinked(t *testing.T) {
+	admin := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "password"), Email: "octo@example.com"}
+	gh := fakeGithub{id: 583231, login: "octocat", email: "octo@example.com", emailVerified: false}
+	client, store, cleanup := setupGithubLoginTest(t, gh, nil, admin)
+	defer cleanup()
+
+	_, err := loginWithGithub(client, "valid-code")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+
+	user, err := store.GetByUsername(context.Background(), "admin")
+	require.NoError(t, err)
+	assert.Empty(t, user.GithubID)
+}
+
+func TestLoginWithGithub_RejectedCode_ReturnsUnauthenticated(t *testing.T) {
+	gh := fakeGithub{id: 583231, login: "octocat", email: "octo@example.com", emailVerified: true}
+	client, _, cleanup := setupGithubLoginTest(t, gh, []string{"octocat"})
+	defer cleanup()
+
+	_, err := loginWithGithub(client, "stale-code")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func TestLoginWithGithub_NotConfigured_ReturnsUnimplemented(t *testing.T) {
+	client, cleanup := setupAuthTest(t)
+	defer cleanup()
+
+	_, err := loginWithGithub(client, "valid-code")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnimplemented, connect.CodeOf(err))
+}
+
+// capturingNotifier records the reset tokens it was asked to deliver.
+type capturingNotifier struct {
+	tokens []string
+}
+
+func (n *capturingNotifier) SendPasswordReset(_ context.Context, _ *auth.User, token string, _ time.Time) error {
+	n.tokens = append(n.tokens, token)
+	return nil
+}
+
+func setupPasswordResetTest(t *testing.T, users ...*auth.User) (v1connect.AuthServiceClient, *auth.MemoryPasswordResetStore, *capturingNotifier, func()) {
+	t.Helper()
+
+	store := auth.NewMemoryUserStore()
+	for _, u := range users {
+		require.NoError(t, store.Create(context.Background(), u))
+	}
+	resets := auth.NewMemoryPasswordResetStore()
+	notifier := &capturingNotifier{}
+
+	revokedStore := auth.NewMemoryRevokedTokenStore()
+	svc := service.NewAuthService(store, auth.NewMemoryRefreshTokenStore(), revokedStore, testJWTSecret,
+		service.WithPasswordReset(resets, notifier))
+	interceptor := auth.NewAuthInterceptor(testJWTSecret, store, revokedStore)
+
+	mux := http.NewServeMux()
+	path, handler := v1connect.NewAuthServiceHandler(svc, connect.WithInterceptors(interceptor))
+	mux.Handle(path, handler)
+
+	server := httptest.NewServer(mux)
+	client := v1connect.NewAuthServiceClient(server.Client(), server.URL)
+
+	return client, resets, notifier, server.Close
+}
+
+func requestReset(t *testing.T, client v1connect.AuthServiceClient, email string) {
+	t.Helper()
+	_, err := client.RequestPasswordReset(context.Background(), connect.NewRequest(&v1.RequestPasswordResetRequest{
+		Email: email,
+	}))
+	require.NoError(t, err)
+}
+
+func confirmReset(client v1connect.AuthServiceClient, token, newPassword string) error {
+	_, err := client.ConfirmPasswordReset(context.Background(), connect.NewRequest(&v1.ConfirmPasswordResetRequest{
+		Token:       token,
+		NewPassword: newPassword,
+	}))
+	return err
+}
+
+func TestPasswordReset_ValidToken_SetsNewPassword(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "old-password"), Email: "admin@example.com"}
+	client, _, notifier, cleanup := setupPasswordResetTest(t, user)
+	defer cleanup()
+
+	session := login(t, client, "admin", "old-password")
+
+	requestReset(t, client, "Admin@Example.com")
+	require.Len(t, notifier.tokens, 1)
+
+	require.NoError(t, confirmReset(client, notifier.tokens[0], "new-password"))
+
+	login(t, client, "admin", "new-password")
+	_, err := client.Login(context.Background(), connect.NewRequest(&v1.LoginRequest{
+		Username: "admin",
+		Password: "old-password",
+	}))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	// The reset is a password change: sessions from before it are gone.
+	err = callProtected(client, session.Token, "new-password")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+}
+
+func TestPasswordReset_UnknownEmail_LooksLikeSuccess(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "password"), Email: "admin@example.com"}
+	client, _, notifier, cleanup := setupPasswordResetTest(t, user)
+	defer cleanup()
+
+	requestReset(t, client, "nobody@example.com")
+	assert.Empty(t, notifier.tokens)
+}
+
+func TestPasswordReset_ExpiredToken_ReturnsUnauthenticated(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "old-password"), Email: "admin@example.com"}
+	client, resets, _, cleanup := setupPasswordResetTest(t, user)
+	defer cleanup()
+
+	token, tokenHash, err := auth.NewPasswordResetToken()
+	require.NoError(t, err)
+	require.NoError(t, resets.Create(context.Background(), &auth.PasswordResetToken{
+		TokenHash: tokenHash,
+		Username:  "admin",
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}))
+
+	err = confirmReset(client, token, "new-password")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	login(t, client, "admin", "old-password")
+}
+
+func TestPasswordReset_UsedToken_ReturnsUnauthenticated(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "old-password"), Email: "admin@example.com"}
+	client, _, notifier, cleanup := setupPasswordResetTest(t, user)
+	defer cleanup()
+
+	requestReset(t, client, "admin@example.com")
+	require.Len(t, notifier.tokens, 1)
+
+	require.NoError(t, confirmReset(client, notifier.tokens[0], "new-password"))
+
+	err := confirmReset(client, notifier.tokens[0], "another-password")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+
+	login(t, client, "admin", "new-password")
+}
+
+func TestPasswordReset_ShortPassword_KeepsTokenUsable(t *testing.T) {
+	user := &auth.User{Username: "admin", PasswordHash: hashPassword(t, "old-password"), Email: "admin@example.com"}
+	client, _, notifier, cleanup := setupPasswordResetTest(t, user)
+	defer cleanup()
+
+	requestReset(t, client, "admin@example.com")
+	require.Len(t, notifier.tokens, 1)
+
+	err := confirmReset(client, notifier.tokens[0], "short")
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	require.NoError(t, confirmReset(client, notifier.tokens[0], "new-password"))
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.orx.me/apps/hyper-sync/internal/metrics"
+)
+
+// ErrCircuitOpen is returned by circuitBreaker.Allow while a target platform
+// is failing and should not be posted to.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Defaults for the per-platform circuit breaker, used when the
+// sync.circuit_breaker_* options are not set.
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerWindow    = 10 * time.Minute
+	defaultBreakerCooldown  = 5 * time.Minute
+)
+
+type circuitState int64
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitOpen:
+		return "open"
+	case circuitHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker stops cross-posting to a platform that keeps failing.
+// threshold consecutive failures within window open it; after cooldown one
+// probe is let through (half-open), whose success closes the breaker and
+// whose failure opens it again.
+type circuitBreaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+	metrics   *metrics.CircuitMetrics
+
+	mu           sync.Mutex
+	state        circuitState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	// probeAt is when the half-open probe was let through. A probe that
+	// never reports back (e.g. the post failed before reaching the
+	// platform) expires after cooldown, so the breaker cannot get stuck.
+	probeAt time.Time
+}
+
+func newCircuitBreaker(platform string, threshold int, window, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		window:    window,
+		cooldown:  cooldown,
+		metrics:   metrics.NewCircuitMetrics(platform),
+	}
+}
+
+// Allow reports whether a post may be sent at now, returning ErrCircuitOpen
+// when it may not.
+func (b *circuitBreaker) Allow(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.transition(circuitHalfOpen)
+		b.probeAt = now
+		return nil
+	case circuitHalfOpen:
+		if now.Sub(b.probeAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.probeAt = now
+		return nil
+	}
+	return nil
+}
+
+// Record reports the outcome of a post sent at now.
+func (b *circuitBreaker) Record(now time.Time, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		if b.state != circuitClosed {
+			b.transition(circuitClosed)
+		}
+		return
+	}
+
+	if b.state == circuitHalfOpen {
+		b.open(now)
+		return
+	}
+	// 连续失败只在窗口内累计，间隔太久的失败重新计数
+	if b.failures == 0 || now.Sub(b.firstFailure) > b.window {
+		b.failures = 0
+		b.firstFailure = now
+	}
+	b.failures++
+	if b.state == circuitClosed && b.failures >= b.threshold {
+		b.open(now)
+	}
+}
+
+// State returns the breaker's current state.
+func (b *circuitBreaker) State() circuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) open(now time.Time) {
+	b.openedAt = now
+	b.failures = 0
+	b.transition(circuitOpen)
+}
+
+func (b *circuitBreaker) transition(state circuitState) {
+	b.state = state
+	b.metrics.RecordTransition(state.String(), int64(state))
+}
+
+// platformBreakers holds one circuitBreaker per target platform, shared by
+// every SyncService using the same SocialService. Zero settings fall back to
+// the defaults.
+type platformBreakers struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+
+	mu         sync.Mutex
+	byPlatform map[string]*circuitBreaker
+}
+
+// breaker returns the circuit breaker for platform, creating it on first use.
+func (s *SocialService) breaker(platform string) *circuitBreaker {
+	s.breakers.mu.Lock()
+	defer s.breakers.mu.Unlock()
+	if b, ok := s.breakers.byPlatform[platform]; ok {
+		return b
+	}
+	if s.breakers.byPlatform == nil {
+		s.breakers.byPlatform = make(map[string]*circuitBreaker)
+	}
+
+	threshold, window, cooldown := s.breakers.threshold, s.breakers.window, s.breakers.cooldown
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+	if window <= 0 {
+		window = defaultBreakerWindow
+	}
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+	b := newCircuitBreaker(platform, threshold, window, cooldown)
+	s.breakers.byPlatform[platform] = b
+	return b
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b := newCircuitBreaker("bluesky", 3, time.Minute, 5*time.Minute)
+	now := time.Now()
+	failure := errors.New("bluesky is down")
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, b.Allow(now))
+		b.Record(now, failure)
+	}
+	assert.Equal(t, circuitClosed, b.State())
+
+	b.Record(now, failure)
+	assert.Equal(t, circuitOpen, b.State())
+	assert.ErrorIs(t, b.Allow(now.Add(time.Minute)), ErrCircuitOpen)
+}
+
+func TestCircuitBreaker_FailuresOutsideWindowDoNotAccumulate(t *testing.T) {
+	b := newCircuitBreaker("bluesky", 2, time.Minute, 5*time.Minute)
+	now := time.Now()
+	failure := errors.New("bluesky is down")
+
+	b.Record(now, failure)
+	b.Record(now.Add(2*time.Minute), failure)
+	assert.Equal(t, circuitClosed, b.State())
+
+	b.Record(now.Add(2*time.Minute+time.Second), failure)
+	assert.Equal(t, circuitOpen, b.State())
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	b := newCircuitBreaker("bluesky", 2, time.Minute, 5*time.Minute)
+	now := time.Now()
+	failure := errors.New("bluesky is down")
+
+	b.Record(now, failure)
+	b.Record(now, nil)
+	b.Record(now, failure)
+	assert.Equal(t, circuitClosed, b.State())
+}
+
+func TestCircuitBreaker_HalfOpenProbe(t *testing.T) {
+	b := newCircuitBreaker("bluesky", 1, time.Minute, 5*time.Minute)
+	now := time.Now()
+	b.Record(now, errors.New("bluesky is down"))
+	assert.Equal(t, circuitOpen, b.State())
+
+	// 冷却结束后只放行一个探测请求
+	probeAt := now.Add(5 * time.Minute)
+	assert.NoError(t, b.Allow(probeAt))
+	assert.Equal(t, circuitHalfOpen, b.State())
+	assert.ErrorIs(t, b.Allow(probeAt), ErrCircuitOpen)
+
+	// 探测失败重新打开
+	b.Record(probeAt, errors.New("still down"))
+	assert.Equal(t, circuitOpen, b.State())
+	assert.ErrorIs(t, b.Allow(probeAt.Add(time.Minute)), ErrCircuitOpen)
+
+	// 下一次探测成功则关闭
+	probeAt = probeAt.Add(5 * time.Minute)
+	assert.NoError(t, b.Allow(probeAt))
+	b.Record(probeAt, nil)
+	assert.Equal(t, circuitClosed, b.State())
+	assert.NoError(t, b.Allow(probeAt))
+}
+
+func TestCircuitBreaker_LostProbeExpires(t *testing.T) {
+	b := newCircuitBreaker("bluesky", 1, time.Minute, 5*time.Minute)
+	now := time.Now()
+	b.Record(now, errors.New("bluesky is down"))
+
+	probeAt := now.Add(5 * time.Minute)
+	assert.NoError(t, b.Allow(probeAt))
+	// 探测没有回报结果（例如模板转换失败），冷却后再放行一次
+	assert.ErrorIs(t, b.Allow(probeAt.Add(time.Minute)), ErrCircuitOpen)
+	assert.NoError(t, b.Allow(probeAt.Add(5*time.Minute)))
+}
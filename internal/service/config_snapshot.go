@@ -0,0 +1,236 @@
+package service
+
+import (
+	"sort"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// 配置快照用于 /api/config 展示当前生效的配置。令牌、密码、私钥等敏感字段
+// 一律逐个显式脱敏：已配置时显示 redactedValue，未配置时为空，新增敏感字段
+// 必须在 platformSettings 中同样处理。
+
+// redactedValue replaces a configured secret in a config snapshot.
+const redactedValue = "[REDACTED]"
+
+// redact hides a secret while still showing whether it is set.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// PlatformConfigSnapshot is the redaction-safe configuration of a platform.
+type PlatformConfigSnapshot struct {
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	Enabled           bool     `json:"enabled"`
+	SyncEnabled       bool     `json:"sync_enabled"`
+	SyncTo            []string `json:"sync_to,omitempty"`
+	SyncFromPlatforms []string `json:"sync_from_platforms,omitempty"`
+	SyncDelay         string   `json:"sync_delay,omitempty"`
+	SyncInterval      string   `json:"sync_interval,omitempty"`
+	FetchLimit        int      `json:"fetch_limit,omitempty"`
+	Template          string   `json:"template,omitempty"`
+	Normalize         []string `json:"normalize,omitempty"`
+	Footer            string   `json:"footer,omitempty"`
+	MaxChars          int      `json:"max_chars,omitempty"`
+	Overflow          string   `json:"overflow,omitempty"`
+	// LinkShortener is the shorten endpoint; its token is never included.
+	LinkShortener string                 `json:"link_shortener,omitempty"`
+	PostRateLimit *PostRateLimitSnapshot `json:"post_rate_limit,omitempty"`
+	// VisibilityMap is keyed by visibility name, e.g. {"unlisted": "skip"}.
+	VisibilityMap         map[string]string `json:"visibility_map,omitempty"`
+	UnsupportedVisibility string            `json:"unsupported_visibility,omitempty"`
+	// Settings holds the platform-specific block with secrets redacted.
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// PostRateLimitSnapshot is a platform's post_rate_limit.
+type PostRateLimitSnapshot struct {
+	Every string `json:"every"`
+	Burst int    `json:"burst"`
+	Mode  string `json:"mode"`
+}
+
+// SyncConfigSnapshot is the configuration a SyncService runs with.
+type SyncConfigSnapshot struct {
+	Source               string   `json:"source"`
+	TargetPlatforms      []string `json:"target_platforms"`
+	FetchLimit           int      `json:"fetch_limit"`
+	SkipPrivate          bool     `json:"skip_private"`
+	SkipOlderThan        string   `json:"skip_older_than"`
+	MaxRetries           int      `json:"max_retries"`
+	CrossPostConcurrency int      `json:"cross_post_concurrency"`
+	PostRetryAttempts    int      `json:"post_retry_attempts"`
+	PostRetryDelay       string   `json:"post_retry_delay"`
+	ResyncOnEdit         bool     `json:"resync_on_edit"`
+	RequireAltText       bool     `json:"require_alt_text"`
+	DryRun               bool     `json:"dry_run"`
+	// PausedTargets are targets paused at runtime with PauseTarget.
+	PausedTargets []string `json:"paused_targets,omitempty"`
+	// WebhookConfigured only reports whether outgoing webhooks are set; the
+	// URLs may embed tokens.
+	WebhookConfigured bool `json:"webhook_configured"`
+}
+
+// GetConfigSnapshot returns the redaction-safe configuration of every
+// platform, sorted by name.
+func (s *SocialService) GetConfigSnapshot() []PlatformConfigSnapshot {
+	snapshots := make([]PlatformConfigSnapshot, 0, len(s.platforms))
+	for name, platform := range s.platforms {
+		snapshot := PlatformConfigSnapshot{Name: name}
+		if config := platform.Config; config != nil {
+			snapshot.Type = config.Type
+			snapshot.Enabled = config.Enabled
+			snapshot.SyncEnabled = config.SyncEnabled
+			snapshot.SyncTo = config.SyncTo
+			snapshot.SyncFromPlatforms = config.SyncFromPlatforms
+			snapshot.FetchLimit = config.FetchLimit
+			snapshot.Normalize = config.Normalize
+			if config.LinkShortener != nil {
+				snapshot.LinkShortener = config.LinkShortener.Endpoint
+			}
+			snapshot.Footer = config.Footer
+			snapshot.MaxChars = config.MaxChars
+			snapshot.Overflow = config.Overflow
+			snapshot.UnsupportedVisibility = config.UnsupportedVisibilityPolicy
+			if limit := config.PostRateLimit; limit != nil {
+				mode := limit.Mode
+				if mode == "" {
+					mode = social.PostRateLimitWait
+				}
+				snapshot.PostRateLimit = &PostRateLimitSnapshot{Every: limit.Every.String(), Burst: max(limit.Burst, 1), Mode: mode}
+			}
+			if config.SyncDelay > 0 {
+				snapshot.SyncDelay = config.SyncDelay.String()
+			}
+			if config.SyncInterval > 0 {
+				snapshot.SyncInterval = config.SyncInterval.String()
+			}
+			if config.Template != nil {
+				snapshot.Template = config.Template.Content
+			}
+			if len(config.VisibilityMap) > 0 {
+				snapshot.VisibilityMap = make(map[string]string, len(config.VisibilityMap))
+				for level, value := range config.VisibilityMap {
+					snapshot.VisibilityMap[level.String()] = value
+				}
+			}
+			snapshot.Settings = platformSettings(config)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots
+}
+
+// platformSettings flattens the platform-specific config block, keyed by
+// its yaml field names, redacting every secret.
+func platformSettings(config *social.PlatformConfig) map[string]interface{} {
+	switch {
+	case config.Memos != nil:
+		return map[string]interface{}{
+			"endpoint": config.Memos.Endpoint,
+			"token":    redact(config.Memos.Token),
+		}
+	case config.Mastodon != nil:
+		return map[string]interface{}{
+			"instance":        config.Mastodon.Instance,
+			"token":           redact(config.Mastodon.Token),
+			"include_reblogs": config.Mastodon.IncludeReblogs,
+			"include_replies": config.Mastodon.IncludeReplies,
+		}
+	case config.Bluesky != nil:
+		settings := map[string]interface{}{
+			"host":              config.Bluesky.Host,
+			"handle":            config.Bluesky.Handle,
+			"password":          redact(config.Bluesky.Password),
+			"disable_link_card": config.Bluesky.DisableLinkCard,
+			"gif_mode":          config.Bluesky.GIFMode,
+			"video_mode":        config.Bluesky.VideoMode,
+		}
+		if config.Bluesky.StripMetadata != nil {
+			settings["strip_metadata"] = *config.Bluesky.StripMetadata
+		}
+		return settings
+	case config.Threads != nil:
+		settings := map[string]interface{}{
+			"client_id":     config.Threads.ClientID,
+			"client_secret": redact(config.Threads.ClientSecret),
+			"access_token":  redact(config.Threads.AccessToken),
+			"user_id":       config.Threads.UserID,
+		}
+		if config.Threads.ExpiresAt != nil {
+			settings["expires_at"] = *config.Threads.ExpiresAt
+		}
+		return settings
+	case config.Telegram != nil:
+		return map[string]interface{}{
+			"bot_token":           redact(config.Telegram.BotToken),
+			"channel_id":          config.Telegram.ChannelID,
+			"parse_mode":          config.Telegram.ParseMode,
+			"alt_text_in_caption": config.Telegram.AltTextInCaption,
+			"webhook_url":         config.Telegram.WebhookURL,
+			"webhook_secret":      redact(config.Telegram.WebhookSecret),
+		}
+	case config.Nostr != nil:
+		return map[string]interface{}{
+			"private_key": redact(config.Nostr.PrivateKey),
+			"relays":      config.Nostr.Relays,
+		}
+	case config.Discord != nil:
+		// webhook 地址本身包含令牌
+		return map[string]interface{}{
+			"webhook_url": redact(config.Discord.WebhookURL),
+			"username":    config.Discord.Username,
+			"avatar_url":  config.Discord.AvatarURL,
+		}
+	case config.Matrix != nil:
+		return map[string]interface{}{
+			"homeserver":   config.Matrix.Homeserver,
+			"access_token": redact(config.Matrix.AccessToken),
+			"room_id":      config.Matrix.RoomID,
+		}
+	case config.WordPress != nil:
+		return map[string]interface{}{
+			"site_url":     config.WordPress.SiteURL,
+			"username":     config.WordPress.Username,
+			"app_password": redact(config.WordPress.AppPassword),
+		}
+	case config.Microblog != nil:
+		return map[string]interface{}{
+			"endpoint": config.Microblog.Endpoint,
+			"token":    redact(config.Microblog.Token),
+		}
+	case config.RSS != nil:
+		return map[string]interface{}{
+			"feed_url": config.RSS.FeedURL,
+		}
+	}
+	return nil
+}
+
+// GetConfigSnapshot returns the configuration this SyncService runs with,
+// including runtime settings that take effect on the next sync.
+func (s *SyncService) GetConfigSnapshot() SyncConfigSnapshot {
+	settings := s.Settings()
+	return SyncConfigSnapshot{
+		Source:               s.mainSocial,
+		TargetPlatforms:      settings.TargetPlatforms,
+		FetchLimit:           settings.FetchLimit,
+		SkipPrivate:          settings.SkipPrivate,
+		SkipOlderThan:        settings.SkipOlder.String(),
+		MaxRetries:           maxRetriesFromConfig(),
+		CrossPostConcurrency: crossPostConcurrencyFromConfig(),
+		PostRetryAttempts:    s.postAttempts,
+		PostRetryDelay:       s.postRetryDelay.String(),
+		ResyncOnEdit:         s.resyncOnEdit,
+		RequireAltText:       s.requireAltText,
+		DryRun:               s.DryRun,
+		PausedTargets:        s.PausedTargets(),
+		WebhookConfigured:    s.webhook != nil,
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestSocialService_GetConfigSnapshotRedactsSecrets(t *testing.T) {
+	secrets := []string{"memos-token", "bsky-password", "threads-secret", "threads-token", "bot-token", "nsec1secret", "https://discord.com/api/webhooks/1/abc"}
+	socialService := NewSocialServiceFromPlatforms([]*social.SocialPlatform{
+		{Name: "memos", Config: &social.PlatformConfig{
+			Type: "memos", Enabled: true, SyncTo: []string{"bluesky", "threads"}, SyncInterval: time.Minute,
+			Memos: &social.MemosConfig{Endpoint: "https://memos.example.com", Token: secrets[0]},
+		}},
+		{Name: "bluesky", Config: &social.PlatformConfig{
+			Type: "bluesky", Bluesky: &social.BlueskyConfig{Handle: "me.bsky.social", Password: secrets[1]},
+		}},
+		{Name: "threads", Config: &social.PlatformConfig{
+			Type: "threads", Threads: &social.ThreadsConfig{ClientID: "client", ClientSecret: secrets[2], AccessToken: secrets[3]},
+		}},
+		{Name: "telegram", Config: &social.PlatformConfig{
+			Type: "telegram", Telegram: &social.TelegramConfig{BotToken: secrets[4], ChannelID: "@channel"},
+		}},
+		{Name: "nostr", Config: &social.PlatformConfig{
+			Type: "nostr", Nostr: &social.NostrConfig{PrivateKey: secrets[5]},
+		}},
+		{Name: "discord", Config: &social.PlatformConfig{
+			Type: "discord", Discord: &social.DiscordConfig{WebhookURL: secrets[6]},
+		}},
+	})
+
+	snapshots := socialService.GetConfigSnapshot()
+	body, err := json.Marshal(snapshots)
+	require.NoError(t, err)
+	for _, secret := range secrets {
+		assert.NotContains(t, string(body), secret)
+	}
+
+	require.Len(t, snapshots, 6)
+	memos := snapshots[2]
+	require.Equal(t, "memos", memos.Name)
+	assert.Equal(t, []string{"bluesky", "threads"}, memos.SyncTo)
+	assert.Equal(t, "1m0s", memos.SyncInterval)
+	assert.Equal(t, "https://memos.example.com", memos.Settings["endpoint"])
+	assert.Equal(t, redactedValue, memos.Settings["token"], "a configured secret shows as set")
+	assert.Equal(t, "me.bsky.social", snapshots[0].Settings["handle"])
+}
+
+func TestSyncService_GetConfigSnapshot(t *testing.T) {
+	s := newTestSyncService(newMemoryPostDao(), &fakeSyncClient{name: "memos"},
+		&fakeSyncClient{name: "mastodon"}, &fakeSyncClient{name: "threads"})
+	s.FetchLimit = 40
+
+	snapshot := s.GetConfigSnapshot()
+
+	assert.Equal(t, "memos", snapshot.Source)
+	assert.Equal(t, []string{"mastodon", "threads"}, snapshot.TargetPlatforms)
+	assert.Equal(t, 40, snapshot.FetchLimit)
+	assert.Equal(t, defaultMaxRetries, snapshot.MaxRetries)
+	assert.Equal(t, defaultSkipOlderThan.String(), snapshot.SkipOlderThan)
+	assert.False(t, snapshot.WebhookConfigured)
+}
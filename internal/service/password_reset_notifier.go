@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"butterfly.orx.me/core/log"
+
+	"go.orx.me/apps/hyper-sync/internal/auth"
+)
+
+// PasswordResetNotifier delivers a password reset token to its user.
+type PasswordResetNotifier interface {
+	SendPasswordReset(ctx context.Context, user *auth.User, token string, expiresAt time.Time) error
+}
+
+// LogPasswordResetNotifier writes the reset link to the server log. HyperSync
+// has no mail delivery; on a self-hosted instance the operator reads the link
+// from there.
+type LogPasswordResetNotifier struct {
+	// ResetURL is the front-end page that accepts the token as ?token=. When
+	// empty only the token is logged.
+	ResetURL string
+}
+
+func (n LogPasswordResetNotifier) SendPasswordReset(ctx context.Context, user *auth.User, token string, expiresAt time.Time) error {
+	logger := log.FromContext(ctx)
+
+	if n.ResetURL == "" {
+		logger.Info("password reset requested",
+			"username", user.Username,
+			"token", token,
+			"expires_at", expiresAt)
+		return nil
+	}
+
+	link, err := url.Parse(n.ResetURL)
+	if err != nil {
+		return err
+	}
+	q := link.Query()
+	q.Set("token", token)
+	link.RawQuery = q.Encode()
+
+	logger.Info("password reset requested",
+		"username", user.Username,
+		"link", link.String(),
+		"expires_at", expiresAt)
+	return nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"butterfly.orx.me/core/log"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// PlatformInfo describes a configured platform and what it supports.
+type PlatformInfo struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	Enabled    bool     `json:"enabled"`
+	Visibility []string `json:"visibility"`
+	// SupportsMedia/SupportsListPosts come from the platform type;
+	// SupportsDelete from whether the running client can delete, so it is
+	// false for disabled platforms.
+	SupportsMedia     bool         `json:"supports_media"`
+	SupportsListPosts bool         `json:"supports_list_posts"`
+	SupportsDelete    bool         `json:"supports_delete"`
+	TokenStatus       *TokenStatus `json:"token_status,omitempty"`
+}
+
+// NewSocialServiceFromPlatforms builds a SocialService around already
+// initialized platforms.
+func NewSocialServiceFromPlatforms(platforms []*social.SocialPlatform) *SocialService {
+	platformMap := make(map[string]*social.SocialPlatform, len(platforms))
+	for _, platform := range platforms {
+		platformMap[platform.Name] = platform
+	}
+	return &SocialService{platforms: platformMap}
+}
+
+// ListPlatforms 列出所有已配置平台（含未启用的）及其能力，按名称排序。
+// Threads 平台附带 token 状态；查询失败只记录日志
+func (s *SchedulerService) ListPlatforms(ctx context.Context, configs map[string]*social.PlatformConfig) []PlatformInfo {
+	logger := log.FromContext(ctx)
+
+	platforms := make([]PlatformInfo, 0, len(configs))
+	for key, config := range configs {
+		name := config.Name
+		if name == "" {
+			name = key
+		}
+		platformType := social.ParsePlatform(config.Type)
+		capabilities := social.SupportedCapabilities[platformType]
+
+		info := PlatformInfo{
+			Name:              name,
+			Type:              config.Type,
+			Enabled:           config.Enabled,
+			Visibility:        make([]string, 0, len(social.SupportedVisibilityLevels[platformType])),
+			SupportsMedia:     capabilities.Media,
+			SupportsListPosts: capabilities.ListPosts,
+		}
+		for _, level := range social.SupportedVisibilityLevels[platformType] {
+			info.Visibility = append(info.Visibility, level.String())
+		}
+
+		if platform, err := s.socialService.GetPlatform(name); err == nil {
+			_, info.SupportsDelete = platform.Client.(social.SocialDeleter)
+
+			if platformType == social.PlatformThreads {
+				status, err := s.GetTokenStatus(ctx, name)
+				if err != nil {
+					logger.Warn("Failed to get token status", "platform", name, "error", err)
+				} else {
+					info.TokenStatus = status
+				}
+			}
+		}
+
+		platforms = append(platforms, info)
+	}
+
+	sort.Slice(platforms, func(i, j int) bool { return platforms[i].Name < platforms[j].Name })
+	return platforms
+}
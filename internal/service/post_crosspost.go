@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.orx.me/apps/hyper-sync/internal/post"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// ErrInvalidCrossPost is returned by CrossPostNow when the request cannot be
+// posted as given, e.g. a visibility a target does not support.
+var ErrInvalidCrossPost = errors.New("invalid cross-post request")
+
+// WithSocialService enables CrossPostNow.
+func WithSocialService(svc *SocialService) PostServiceOption {
+	return func(s *PostService) {
+		s.social = svc
+	}
+}
+
+// CrossPostRequest is one piece of content to post to platforms right away.
+type CrossPostRequest struct {
+	Content    string
+	Visibility string
+	MediaURLs  []string
+	Platforms  []string
+}
+
+// CrossPostResult is the outcome of posting to one platform.
+type CrossPostResult struct {
+	Success    bool   `json:"success"`
+	PlatformID string `json:"platform_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CrossPostNow stores req as a published post and posts it to every platform
+// in req.Platforms concurrently, recording each platform's status on the
+// post. Unlike PublishPost it does not wait for the publish worker, and the
+// post is never queued for it: failures are returned to the caller rather
+// than retried. Validation failures wrap ErrInvalidCrossPost.
+func (s *PostService) CrossPostNow(ctx context.Context, req CrossPostRequest) (*post.Post, map[string]CrossPostResult, error) {
+	if s.social == nil {
+		return nil, nil, errors.New("social platforms not configured")
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = "public"
+	}
+	if err := validatePostFields("published", visibility); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCrossPost, err)
+	}
+	if len(req.Platforms) == 0 {
+		return nil, nil, fmt.Errorf("%w: no platforms given", ErrInvalidCrossPost)
+	}
+	if req.Content == "" && len(req.MediaURLs) == 0 {
+		return nil, nil, fmt.Errorf("%w: content or media is required", ErrInvalidCrossPost)
+	}
+
+	level := toVisibilityLevel(visibility)
+	platforms := make([]*social.SocialPlatform, 0, len(req.Platforms))
+	for _, name := range req.Platforms {
+		platform, err := s.social.GetPlatform(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCrossPost, err)
+		}
+		if err := platform.Config.ValidateVisibilityLevel(level); err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrInvalidCrossPost, name, err)
+		}
+		platforms = append(platforms, platform)
+	}
+
+	created, err := s.store.Create(ctx, &post.Post{
+		Content:     req.Content,
+		Visibility:  visibility,
+		Status:      "published",
+		SyncTargets: req.Platforms,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("create post: %w", err)
+	}
+
+	media := make([]social.Media, 0, len(req.MediaURLs))
+	for _, url := range req.MediaURLs {
+		media = append(media, *social.NewMediaFromURL(url))
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]CrossPostResult, len(platforms))
+	)
+	for _, platform := range platforms {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each platform gets its own Post: clients cache media data and
+			// content types on it.
+			socialPost := &social.Post{
+				Content:    req.Content,
+				Visibility: level,
+				Media:      append([]social.Media(nil), media...),
+			}
+			result := postToPlatform(ctx, platform, socialPost)
+
+			now := time.Now()
+			status := post.CrossPostStatus{
+				Success:    result.Success,
+				Error:      result.Error,
+				PlatformID: result.PlatformID,
+				PostedAt:   &now,
+			}
+			if err := s.store.UpdateSyncStatus(ctx, created.ID, platform.Name, status); err != nil {
+				slog.Error("failed to persist sync status", "post_id", created.ID, "platform", platform.Name, "error", err)
+			}
+
+			mu.Lock()
+			results[platform.Name] = result
+			if created.CrossPostStatus == nil {
+				created.CrossPostStatus = make(map[string]post.CrossPostStatus)
+			}
+			created.CrossPostStatus[platform.Name] = status
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return created, results, nil
+}
+
+func postToPlatform(ctx context.Context, platform *social.SocialPlatform, p *social.Post) CrossPostResult {
+	p, err := platform.Transform(p)
+	if err != nil {
+		return CrossPostResult{Error: err.Error()}
+	}
+	resp, err := platform.Client.Post(ctx, p)
+	if partial, ok := social.AsPartialPostError(err); ok {
+		// 已发出的部分不能重发，按已发布返回
+		slog.Warn("manual cross-post only partially published", "platform", platform.Name, "error", err)
+		resp, err = partial.Result, nil
+	}
+	if err != nil {
+		slog.Error("manual cross-post failed", "platform", platform.Name, "error", err)
+		return CrossPostResult{Error: err.Error()}
+	}
+	platformID := social.ExtractPlatformID(resp)
+	slog.Info("manually cross-posted", "platform", platform.Name, "platform_id", platformID)
+	return CrossPostResult{Success: true, PlatformID: platformID}
+}
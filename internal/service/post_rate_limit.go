@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// platformPostLimiters holds the post_rate_limit token bucket of each target
+// platform, shared by every SyncService using the same SocialService so
+// several sources posting to one platform are paced together.
+type platformPostLimiters struct {
+	mu         sync.Mutex
+	byPlatform map[string]*postLimiter
+}
+
+type postLimiter struct {
+	*rate.Limiter
+	// skip leaves posts without a token for the next sync cycle instead of
+	// waiting for one.
+	skip bool
+}
+
+// postLimiter returns platform's post rate limiter, creating it on first
+// use, or nil when the platform has no post_rate_limit.
+func (s *SocialService) postLimiter(platform string) *postLimiter {
+	s.postLimiters.mu.Lock()
+	defer s.postLimiters.mu.Unlock()
+	if l, ok := s.postLimiters.byPlatform[platform]; ok {
+		return l
+	}
+	if s.postLimiters.byPlatform == nil {
+		s.postLimiters.byPlatform = make(map[string]*postLimiter)
+	}
+
+	var l *postLimiter
+	if p, ok := s.platforms[platform]; ok && p.Config != nil && p.Config.PostRateLimit != nil {
+		l = &postLimiter{Limiter: p.Config.PostRateLimit.NewLimiter(), skip: p.Config.PostRateLimit.Skips()}
+	}
+	s.postLimiters.byPlatform[platform] = l
+	return l
+}
+
+// allowPost is consulted before a cross-post to platform is started. With a
+// skip mode post_rate_limit it takes a token, reporting false when none is
+// left; otherwise it always reports true.
+func (s *SocialService) allowPost(platform string) bool {
+	l := s.postLimiter(platform)
+	if l == nil || !l.skip {
+		return true
+	}
+	return l.Allow()
+}
+
+// waitPost blocks until a wait mode post_rate_limit lets a post to platform
+// through, returning an error if ctx ends first (or its deadline comes
+// before the next token). Platforms without a limit, or in skip mode, return
+// at once.
+func (s *SocialService) waitPost(ctx context.Context, platform string) error {
+	l := s.postLimiter(platform)
+	if l == nil || l.skip {
+		return nil
+	}
+	return l.Wait(ctx)
+}
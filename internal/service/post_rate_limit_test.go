@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// pacedClient records when each post reaches the platform.
+type pacedClient struct {
+	*fakeSyncClient
+
+	mu       sync.Mutex
+	postedAt []time.Time
+}
+
+func (c *pacedClient) Post(ctx context.Context, p *social.Post) (interface{}, error) {
+	c.mu.Lock()
+	c.postedAt = append(c.postedAt, time.Now())
+	c.mu.Unlock()
+	return c.fakeSyncClient.Post(ctx, p)
+}
+
+// queuedPosts returns n posts created at the same time. Their content
+// differs so the content-hash dedup does not merge them.
+func queuedPosts(n int) []*social.Post {
+	now := time.Now()
+	posts := make([]*social.Post, 0, n)
+	for i := 0; i < n; i++ {
+		posts = append(posts, &social.Post{ID: strconv.Itoa(i + 1), Content: "hello " + strconv.Itoa(i+1), CreatedAt: now})
+	}
+	return posts
+}
+
+func TestSyncService_PostRateLimitPacesPosts(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: queuedPosts(4)}
+	target := &pacedClient{fakeSyncClient: &fakeSyncClient{name: "mastodon"}}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	const every = 50 * time.Millisecond
+	s.socialService.platforms["mastodon"].Config.PostRateLimit = &social.PostRateLimitConfig{Every: every, Burst: 2}
+
+	require.NoError(t, s.doSync(context.Background()))
+	require.Len(t, target.postedIDs(), 4)
+
+	// The burst lets the first two through together; the rest wait for a token each
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	assert.Less(t, target.postedAt[1].Sub(target.postedAt[0]), every)
+	for i := 2; i < len(target.postedAt); i++ {
+		assert.GreaterOrEqual(t, target.postedAt[i].Sub(target.postedAt[i-1]), every-5*time.Millisecond,
+			"post %d must wait for the next token", i+1)
+	}
+}
+
+func TestSyncService_PostRateLimitSkipMode(t *testing.T) {
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: queuedPosts(3)}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	s.socialService.platforms["mastodon"].Config.PostRateLimit = &social.PostRateLimitConfig{
+		Every: time.Hour,
+		Mode:  social.PostRateLimitSkip,
+	}
+
+	start := time.Now()
+	require.NoError(t, s.doSync(context.Background()))
+	assert.Less(t, time.Since(start), time.Second, "skip mode must not wait for a token")
+	assert.Len(t, target.postedIDs(), 1, "only one token is available this cycle")
+	assert.True(t, s.cursor.IsZero(), "posts left for the next cycle must be listed again")
+}
+
+// failingTransformer fails to transform the post with the given ID.
+type failingTransformer struct{ id string }
+
+func (t failingTransformer) Transform(p *social.Post) (*social.Post, error) {
+	if p.ID == t.id {
+		return nil, assert.AnError
+	}
+	return p, nil
+}
+
+func TestSyncService_PostRateLimitSkipModeOnlySpendsOnPosts(t *testing.T) {
+	now := time.Now()
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "broken", CreatedAt: now.Add(-time.Minute)},
+		{ID: "2", Content: "hello", CreatedAt: now},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	platform := s.socialService.platforms["mastodon"]
+	platform.Transformer = failingTransformer{id: "1"}
+	platform.Config.PostRateLimit = &social.PostRateLimitConfig{
+		Every: time.Hour,
+		Mode:  social.PostRateLimitSkip,
+	}
+
+	require.NoError(t, s.doSync(context.Background()))
+	// 转换失败的帖子不消耗令牌，唯一的令牌留给第二条
+	assert.Equal(t, []string{"2"}, target.postedIDs())
+}
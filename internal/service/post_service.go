@@ -30,6 +30,8 @@ func WithPlatformDeleter(d PlatformDeleter) PostServiceOption {
 type PostService struct {
 	store   post.Store
 	deleter PlatformDeleter
+	// social posts immediately for CrossPostNow; nil disables it
+	social *SocialService
 }
 
 func NewPostService(store post.Store, opts ...PostServiceOption) *PostService {
@@ -84,13 +86,68 @@ func (s *PostService) GetPost(ctx context.Context, req *connect.Request[v1.GetPo
 	}), nil
 }
 
-func (s *PostService) ListPosts(ctx context.Context, req *connect.Request[v1.ListPostsRequest]) (*connect.Response[v1.ListPostsResponse], error) {
+// Page sizes for ListPostsPaginated.
+const (
+	defaultPostPageSize = 20
+	maxPostPageSize     = 100
+)
+
+// errInvalidPage is returned by ListPostsPaginated for pages below 1.
+var errInvalidPage = errors.New("page must be at least 1")
+
+// PostPage is one page of managed posts and the number of posts matching the
+// filter across all pages.
+type PostPage struct {
+	Posts    []*post.Post
+	Total    int64
+	Page     int
+	PageSize int
+}
+
+// HasMore reports whether pages after this one hold more posts.
+func (p *PostPage) HasMore() bool {
+	return int64(p.Page)*int64(p.PageSize) < p.Total
+}
+
+// ListPostsPaginated lists posts with the given status ("" for all). page is
+// 1-based; pageSize defaults to 20 and is capped at 100.
+func (s *PostService) ListPostsPaginated(ctx context.Context, status string, page, pageSize int) (*PostPage, error) {
+	if page < 1 {
+		return nil, errInvalidPage
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPostPageSize
+	}
+	pageSize = min(pageSize, maxPostPageSize)
+
 	result, err := s.store.List(ctx, post.ListOptions{
-		PageSize: int(req.Msg.PageSize),
-		Page:     int(req.Msg.Page),
-		Status:   req.Msg.Status,
+		PageSize: pageSize,
+		Page:     page,
+		Status:   status,
 	})
 	if err != nil {
+		return nil, fmt.Errorf("list posts: %w", err)
+	}
+
+	return &PostPage{
+		Posts:    result.Posts,
+		Total:    int64(result.Total),
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *PostService) ListPosts(ctx context.Context, req *connect.Request[v1.ListPostsRequest]) (*connect.Response[v1.ListPostsResponse], error) {
+	// page 未设置时为 0，按第一页处理
+	page := int(req.Msg.Page)
+	if page == 0 {
+		page = 1
+	}
+	result, err := s.ListPostsPaginated(ctx, req.Msg.Status, page, int(req.Msg.PageSize))
+	if err != nil {
+		if errors.Is(err, errInvalidPage) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
 		return nil, connect.NewError(connect.CodeInternal, err)
 	}
 
@@ -699,3 +699,70 @@ func (m *mockPlatformDeleter) DeleteFromPlatform(ctx context.Context, platform,
 	m.calls = append(m.calls, deleteCall{platform: platform, platformID: platformID})
 	return m.results[platform]
 }
+
+func TestListPostsPaginated_CountsAndSkips(t *testing.T) {
+	store := post.NewMemoryStore()
+	svc := service.NewPostService(store)
+	ctx := context.Background()
+	for i := 0; i < 25; i++ {
+		_, err := store.Create(ctx, &post.Post{Content: fmt.Sprintf("post %d", i), Status: "draft"})
+		require.NoError(t, err)
+	}
+
+	seen := make(map[string]bool)
+	for page, want := range map[int]int{1: 10, 2: 10, 3: 5} {
+		result, err := svc.ListPostsPaginated(ctx, "", page, 10)
+		require.NoError(t, err)
+		assert.Equal(t, int64(25), result.Total)
+		assert.Len(t, result.Posts, want, "page %d", page)
+		assert.Equal(t, page < 3, result.HasMore(), "page %d", page)
+		for _, p := range result.Posts {
+			assert.False(t, seen[p.ID], "post %s returned on two pages", p.ID)
+			seen[p.ID] = true
+		}
+	}
+	assert.Len(t, seen, 25)
+
+	result, err := svc.ListPostsPaginated(ctx, "", 4, 10)
+	require.NoError(t, err)
+	assert.Empty(t, result.Posts)
+	assert.Equal(t, int64(25), result.Total)
+}
+
+func TestListPostsPaginated_PageSize(t *testing.T) {
+	store := post.NewMemoryStore()
+	svc := service.NewPostService(store)
+	ctx := context.Background()
+	for i := 0; i < 120; i++ {
+		_, err := store.Create(ctx, &post.Post{Content: "post", Status: "draft"})
+		require.NoError(t, err)
+	}
+
+	result, err := svc.ListPostsPaginated(ctx, "", 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 20, result.PageSize, "page size defaults to 20")
+	assert.Len(t, result.Posts, 20)
+
+	result, err = svc.ListPostsPaginated(ctx, "", 1, 500)
+	require.NoError(t, err)
+	assert.Equal(t, 100, result.PageSize, "page size is capped at 100")
+	assert.Len(t, result.Posts, 100)
+}
+
+func TestListPostsPaginated_RejectsPageBelowOne(t *testing.T) {
+	svc := service.NewPostService(post.NewMemoryStore())
+
+	_, err := svc.ListPostsPaginated(context.Background(), "", 0, 10)
+	assert.Error(t, err)
+
+	client, cleanup := setupPostTest(t)
+	defer cleanup()
+
+	_, err = client.ListPosts(context.Background(), connect.NewRequest(&v1.ListPostsRequest{Page: -1}))
+	require.Error(t, err)
+	assert.Equal(t, connect.CodeInvalidArgument, connect.CodeOf(err))
+
+	// An unset page still means the first page.
+	_, err = client.ListPosts(context.Background(), connect.NewRequest(&v1.ListPostsRequest{}))
+	assert.NoError(t, err)
+}
@@ -130,6 +130,12 @@ func (w *PublishWorker) syncPost(ctx context.Context, p *post.Post) {
 		result, err := client.Post(ctx, socialPost)
 		now := time.Now()
 
+		// 只发出了一部分：重试会重复发布，按已发布记录
+		if partial, ok := social.AsPartialPostError(err); ok {
+			slog.Warn("post only partially published to platform", "platform", target, "post_id", p.ID, "error", err)
+			result, err = partial.Result, nil
+		}
+
 		if err != nil {
 			status.Error = err.Error()
 			status.RetryCount++
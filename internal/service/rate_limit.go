@@ -0,0 +1,50 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultRateLimitCooldown is how long a platform is skipped after a 429
+// that did not say how long to wait.
+const defaultRateLimitCooldown = time.Minute
+
+// maxInlineRetryAfter is the longest Retry-After a cross-post waits out
+// in-cycle; longer waits are left to the platform's cooldown.
+const maxInlineRetryAfter = 30 * time.Second
+
+// platformCooldowns tracks platforms that rate-limited us and until when they
+// should not be posted to.
+type platformCooldowns struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// SetCooldown skips platform until the given time. An earlier time never
+// shortens an existing cooldown.
+func (s *SocialService) SetCooldown(platform string, until time.Time) {
+	s.cooldowns.mu.Lock()
+	defer s.cooldowns.mu.Unlock()
+	if s.cooldowns.until == nil {
+		s.cooldowns.until = make(map[string]time.Time)
+	}
+	if until.After(s.cooldowns.until[platform]) {
+		s.cooldowns.until[platform] = until
+	}
+}
+
+// CooldownUntil reports whether platform is in a rate-limit cooldown at now
+// and when it ends.
+func (s *SocialService) CooldownUntil(platform string, now time.Time) (time.Time, bool) {
+	s.cooldowns.mu.Lock()
+	defer s.cooldowns.mu.Unlock()
+	until, ok := s.cooldowns.until[platform]
+	if !ok {
+		return time.Time{}, false
+	}
+	if !now.Before(until) {
+		delete(s.cooldowns.until, platform)
+		return time.Time{}, false
+	}
+	return until, true
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// IsRetryable reports whether err looks transient — a rate limit, a gateway
+// or availability error, or a network timeout — and is worth retrying
+// immediately rather than waiting for the next sync cycle.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	// 已经发出了一部分，重试会重复发布
+	if _, partial := social.AsPartialPostError(err); partial {
+		return false
+	}
+	if platformErr, ok := social.AsPlatformError(err); ok && platformErr.Retryable {
+		return true
+	}
+	// RateLimitError 不一定包着 StatusError
+	if _, limited := social.RateLimitRetryAfter(err); limited {
+		return true
+	}
+
+	switch social.HTTPStatusCode(err) {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// retryWithBackoff calls fn up to attempts times, retrying only errors that
+// IsRetryable accepts. The wait doubles after each failure starting from
+// baseDelay, with up to 50% jitter so parallel targets don't retry in
+// lockstep. A rate limit's Retry-After stretches the wait; one longer than
+// maxInlineRetryAfter is returned at once. It returns fn's last error, or
+// ctx's error if ctx ends first.
+func retryWithBackoff(ctx context.Context, attempts int, baseDelay time.Duration, fn func() error) error {
+	return retryWithBackoffIf(ctx, attempts, baseDelay, IsRetryable, fn)
+}
+
+// retryWithBackoffIf is retryWithBackoff with a caller-chosen retryable check.
+func retryWithBackoffIf(ctx context.Context, attempts int, baseDelay time.Duration, retryable func(error) bool, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	delay := baseDelay
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || attempt >= attempts || !retryable(err) {
+			return err
+		}
+
+		wait := delay
+		if delay > 0 {
+			wait += rand.N(delay/2 + 1)
+		}
+		// 平台明确要求的等待时间优先；太长则不在本轮等待，交给冷却期
+		if retryAfter, ok := social.RateLimitRetryAfter(err); ok {
+			if retryAfter > maxInlineRetryAfter {
+				return err
+			}
+			wait = max(wait, retryAfter)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
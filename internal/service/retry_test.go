@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limited", &social.StatusError{Op: "post", StatusCode: http.StatusTooManyRequests}, true},
+		{"rate limit error", &social.RateLimitError{RetryAfter: time.Second}, true},
+		{"wrapped bad gateway", fmt.Errorf("publish: %w", &social.StatusError{Op: "post", StatusCode: http.StatusBadGateway}), true},
+		{"unavailable", &social.StatusError{Op: "post", StatusCode: http.StatusServiceUnavailable}, true},
+		{"bad request", &social.StatusError{Op: "post", StatusCode: http.StatusBadRequest}, false},
+		{"timeout", fmt.Errorf("send: %w", context.DeadlineExceeded), true},
+		{"canceled", context.Canceled, false},
+		{"plain error", errors.New("content too long"), false},
+		{"partial post", &social.PartialPostError{Platform: "telegram", Err: &social.StatusError{Op: "post", StatusCode: http.StatusServiceUnavailable}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetryWithBackoff_SucceedsAfterTransientFailures(t *testing.T) {
+	calls := 0
+	err := retryWithBackoff(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		if calls < 3 {
+			return &social.StatusError{Op: "post", StatusCode: http.StatusServiceUnavailable}
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryWithBackoff_PermanentErrorNotRetried(t *testing.T) {
+	permanent := &social.StatusError{Op: "post", StatusCode: http.StatusUnauthorized}
+	calls := 0
+	err := retryWithBackoff(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		return permanent
+	})
+
+	assert.ErrorIs(t, err, permanent)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryWithBackoff_GivesUpAfterAttempts(t *testing.T) {
+	calls := 0
+	err := retryWithBackoff(context.Background(), 2, time.Millisecond, func() error {
+		calls++
+		return &social.StatusError{Op: "post", StatusCode: http.StatusTooManyRequests}
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryWithBackoff_WaitsForRetryAfter(t *testing.T) {
+	calls := 0
+	start := time.Now()
+	err := retryWithBackoff(context.Background(), 2, time.Millisecond, func() error {
+		calls++
+		if calls == 1 {
+			return &social.RateLimitError{RetryAfter: 50 * time.Millisecond}
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestRetryWithBackoff_LongRetryAfterNotWaited(t *testing.T) {
+	calls := 0
+	err := retryWithBackoff(context.Background(), 3, time.Millisecond, func() error {
+		calls++
+		return &social.RateLimitError{
+			RetryAfter: time.Hour,
+			Err:        &social.StatusError{Op: "post", StatusCode: http.StatusTooManyRequests},
+		}
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls, "a long Retry-After is left to the platform cooldown")
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"butterfly.orx.me/core/log"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+	"go.orx.me/apps/hyper-sync/internal/worker"
+)
+
+// cronSchedule is a loaded scheduler.schedule_patterns entry.
+type cronSchedule struct {
+	pattern  conf.SchedulePattern
+	schedule *worker.CronSchedule
+	lastRun  time.Time
+}
+
+// SchedulerStatus 描述已加载的 cron 调度
+type SchedulerStatus struct {
+	Schedules []ScheduleStatus `json:"schedules"`
+}
+
+// ScheduleStatus is one schedule pattern and when it fires next.
+type ScheduleStatus struct {
+	Name      string     `json:"name"`
+	Cron      string     `json:"cron"`
+	Platforms []string   `json:"platforms"`
+	NextRun   *time.Time `json:"next_run,omitempty"`
+	LastRun   *time.Time `json:"last_run,omitempty"`
+}
+
+// RegisterSyncJob makes a source platform's sync available to schedule
+// patterns. run normally wraps SyncService.Sync, whose Redis lock keeps a cron
+// run and the interval loop from syncing the same source at once.
+func (s *SchedulerService) RegisterSyncJob(source string, run func(context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.syncJobs == nil {
+		s.syncJobs = make(map[string]func(context.Context) error)
+	}
+	s.syncJobs[source] = run
+}
+
+// LoadSchedules parses the enabled patterns, replacing any loaded before. An
+// invalid cron expression fails the whole load so a typo is caught at
+// startup.
+func (s *SchedulerService) LoadSchedules(patterns []conf.SchedulePattern) error {
+	schedules := make([]*cronSchedule, 0, len(patterns))
+	for i, p := range patterns {
+		if !p.Enabled {
+			continue
+		}
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		schedule, err := worker.ParseCron(p.CronExpr)
+		if err != nil {
+			return fmt.Errorf("schedule pattern %s: %w", name, err)
+		}
+		p.Name = name
+		schedules = append(schedules, &cronSchedule{pattern: p, schedule: schedule})
+	}
+
+	s.mu.Lock()
+	s.schedules = schedules
+	s.mu.Unlock()
+	return nil
+}
+
+// RunSchedules fires every loaded schedule until ctx is cancelled, then
+// waits for in-flight runs to return.
+func (s *SchedulerService) RunSchedules(ctx context.Context) {
+	logger := log.FromContext(ctx)
+
+	s.mu.Lock()
+	schedules := s.schedules
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, cs := range schedules {
+		logger.Info("Starting sync schedule",
+			"schedule", cs.pattern.Name,
+			"cron", cs.pattern.CronExpr,
+			"next_run", cs.schedule.Next(time.Now()))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.RunCron(ctx, cs.schedule, func(ctx context.Context) {
+				s.runSchedule(ctx, cs)
+			})
+		}()
+	}
+	wg.Wait()
+	logger.Info("Sync schedules stopped")
+}
+
+// runSchedule syncs each platform the pattern names, one after another.
+func (s *SchedulerService) runSchedule(ctx context.Context, cs *cronSchedule) {
+	logger := log.FromContext(ctx).With("schedule", cs.pattern.Name)
+
+	s.mu.Lock()
+	cs.lastRun = time.Now()
+	jobs := make(map[string]func(context.Context) error, len(s.syncJobs))
+	if len(cs.pattern.Platforms) == 0 {
+		for source, run := range s.syncJobs {
+			jobs[source] = run
+		}
+	} else {
+		for _, source := range cs.pattern.Platforms {
+			if run, ok := s.syncJobs[source]; ok {
+				jobs[source] = run
+			} else {
+				logger.Warn("Scheduled platform has no sync job", "platform", source)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	sources := make([]string, 0, len(jobs))
+	for source := range jobs {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		logger.Info("Running scheduled sync", "platform", source)
+		if err := jobs[source](ctx); err != nil {
+			logger.Error("Scheduled sync failed", "platform", source, "error", err)
+		}
+	}
+}
+
+// GetSchedulerStatus 返回每个 cron 调度的下次/上次运行时间
+func (s *SchedulerService) GetSchedulerStatus() *SchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	status := &SchedulerStatus{Schedules: make([]ScheduleStatus, 0, len(s.schedules))}
+	for _, cs := range s.schedules {
+		entry := ScheduleStatus{
+			Name:      cs.pattern.Name,
+			Cron:      cs.pattern.CronExpr,
+			Platforms: cs.pattern.Platforms,
+		}
+		if next := cs.schedule.Next(now); !next.IsZero() {
+			entry.NextRun = &next
+		}
+		if !cs.lastRun.IsZero() {
+			lastRun := cs.lastRun
+			entry.LastRun = &lastRun
+		}
+		status.Schedules = append(status.Schedules, entry)
+	}
+	return status
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+)
+
+func TestSchedulerService_LoadSchedules_NextRun(t *testing.T) {
+	s := &SchedulerService{}
+	require.NoError(t, s.LoadSchedules([]conf.SchedulePattern{
+		{Name: "every-5m", CronExpr: "*/5 * * * *", Enabled: true, Platforms: []string{"memos"}},
+		{Name: "disabled", CronExpr: "not a cron", Enabled: false},
+	}))
+
+	before := time.Now()
+	status := s.GetSchedulerStatus()
+	require.Len(t, status.Schedules, 1, "disabled patterns are not loaded")
+
+	entry := status.Schedules[0]
+	assert.Equal(t, "every-5m", entry.Name)
+	assert.Equal(t, "*/5 * * * *", entry.Cron)
+	assert.Nil(t, entry.LastRun)
+	require.NotNil(t, entry.NextRun)
+	assert.Zero(t, entry.NextRun.Minute()%5)
+	assert.Zero(t, entry.NextRun.Second())
+	assert.True(t, entry.NextRun.After(before))
+	assert.LessOrEqual(t, entry.NextRun.Sub(before), 5*time.Minute)
+}
+
+func TestSchedulerService_LoadSchedules_InvalidCron(t *testing.T) {
+	s := &SchedulerService{}
+	err := s.LoadSchedules([]conf.SchedulePattern{
+		{Name: "nightly", CronExpr: "0 25 * * *", Enabled: true},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "schedule pattern nightly")
+	assert.Contains(t, err.Error(), `invalid cron expression "0 25 * * *"`)
+}
+
+func TestSchedulerService_RunSchedule_SyncsListedPlatforms(t *testing.T) {
+	s := &SchedulerService{}
+	var ran []string
+	for _, source := range []string{"memos", "telegram", "rss"} {
+		s.RegisterSyncJob(source, func(context.Context) error {
+			ran = append(ran, source)
+			return nil
+		})
+	}
+
+	require.NoError(t, s.LoadSchedules([]conf.SchedulePattern{
+		{Name: "some", CronExpr: "@hourly", Enabled: true, Platforms: []string{"telegram", "memos", "unknown"}},
+		{Name: "all", CronExpr: "@daily", Enabled: true},
+	}))
+
+	s.runSchedule(context.Background(), s.schedules[0])
+	assert.Equal(t, []string{"memos", "telegram"}, ran)
+
+	ran = nil
+	s.runSchedule(context.Background(), s.schedules[1])
+	assert.Equal(t, []string{"memos", "rss", "telegram"}, ran, "no platforms means every registered source")
+
+	status := s.GetSchedulerStatus()
+	require.NotNil(t, status.Schedules[0].LastRun)
+	require.NotNil(t, status.Schedules[1].LastRun)
+}
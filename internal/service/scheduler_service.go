@@ -2,23 +2,35 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"butterfly.orx.me/core/log"
-	"github.com/bsm/redislock"
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/metrics"
 	"go.orx.me/apps/hyper-sync/internal/social"
 )
 
+// ErrTokenUnsupported is returned for token operations on a platform whose
+// tokens HyperSync does not manage; currently only Threads is supported.
+var ErrTokenUnsupported = errors.New("token management not supported for this platform type")
+
 // SchedulerService handles scheduled tasks like token refresh
 type SchedulerService struct {
 	socialService *SocialService
-	locker        *redislock.Client
+	locker        dao.Locker
 	tokenManager  social.TokenManager
+
+	// cron 调度：源平台 → 同步函数，以及已加载的 schedule_patterns
+	mu        sync.Mutex
+	syncJobs  map[string]func(context.Context) error
+	schedules []*cronSchedule
 }
 
 // NewSchedulerService creates a new scheduler service
-func NewSchedulerService(socialService *SocialService, locker *redislock.Client, tokenManager social.TokenManager) *SchedulerService {
+func NewSchedulerService(socialService *SocialService, locker dao.Locker, tokenManager social.TokenManager) *SchedulerService {
 	return &SchedulerService{
 		socialService: socialService,
 		locker:        locker,
@@ -38,6 +50,8 @@ func (s *SchedulerService) StartTokenRefreshScheduler(ctx context.Context, inter
 
 	// 立即执行一次检查
 	s.RefreshAllTokens(ctx)
+	// 有平台刷新失败但 token 仍可用时，retry 在下一个 tick 之前再检查一次
+	retry := s.tokenRetryTimer(interval)
 
 	for {
 		select {
@@ -46,8 +60,58 @@ func (s *SchedulerService) StartTokenRefreshScheduler(ctx context.Context, inter
 			return
 		case <-ticker.C:
 			s.RefreshAllTokens(ctx)
+		case <-retry:
+			logger.Info("Retrying failed token refresh")
+			s.RefreshAllTokens(ctx)
+		}
+		retry = s.tokenRetryTimer(interval)
+	}
+}
+
+// tokenRetryTimer fires when the soonest pending refresh retry (see
+// social.TokenRefreshRetrier) is due, or returns nil when none is due
+// before the next regular tick.
+func (s *SchedulerService) tokenRetryTimer(interval time.Duration) <-chan time.Time {
+	retryIn, ok := s.nextTokenRetry()
+	if !ok || retryIn >= interval {
+		return nil
+	}
+	return time.After(retryIn)
+}
+
+// nextTokenRetry returns the soonest refresh retry requested by any platform.
+func (s *SchedulerService) nextTokenRetry() (time.Duration, bool) {
+	var soonest time.Duration
+	found := false
+	for _, platform := range s.socialService.GetAllPlatforms() {
+		retrier, ok := platform.Client.(social.TokenRefreshRetrier)
+		if !ok {
+			continue
+		}
+		if retryIn, ok := retrier.RefreshRetryIn(); ok && (!found || retryIn < soonest) {
+			soonest, found = retryIn, true
 		}
 	}
+	return soonest, found
+}
+
+// ClearQueue 丢弃所有缓冲型源平台（如 Telegram）中尚未同步的待处理帖子，
+// 返回丢弃的数量。后台拉取协程与正在进行的同步不受影响。
+func (s *SchedulerService) ClearQueue(ctx context.Context) (int, error) {
+	logger := log.FromContext(ctx)
+
+	total := 0
+	for platformName, platform := range s.socialService.GetAllPlatforms() {
+		clearer, ok := platform.Client.(social.QueueClearer)
+		if !ok {
+			continue
+		}
+		n := clearer.ClearQueue()
+		total += n
+		logger.Info("Cleared pending posts", "platform", platformName, "count", n)
+	}
+
+	return total, nil
 }
 
 // RefreshAllTokens 检查并刷新所有平台的 token
@@ -55,7 +119,7 @@ func (s *SchedulerService) RefreshAllTokens(ctx context.Context) {
 	logger := log.FromContext(ctx).With("method", "RefreshAllTokens")
 
 	// 使用分布式锁确保同一时间只有一个实例在执行 token 刷新
-	lock, err := s.locker.Obtain(ctx, "token_refresh", 5*time.Minute, nil)
+	lock, err := s.locker.Obtain(ctx, "token_refresh", 5*time.Minute)
 	if err != nil {
 		logger.Warn("Failed to obtain token refresh lock, skipping", "error", err)
 		return
@@ -64,10 +128,15 @@ func (s *SchedulerService) RefreshAllTokens(ctx context.Context) {
 	defer lock.Release(context.WithoutCancel(ctx))
 
 	logger.Info("Starting token refresh for all platforms")
+	s.refreshAllTokens(ctx)
+	logger.Info("Completed token refresh check for all platforms")
+}
 
-	platforms := s.socialService.GetAllPlatforms()
+// refreshAllTokens is RefreshAllTokens without the distributed lock.
+func (s *SchedulerService) refreshAllTokens(ctx context.Context) {
+	logger := log.FromContext(ctx).With("method", "RefreshAllTokens")
 
-	for platformName, platform := range platforms {
+	for platformName, platform := range s.socialService.GetAllPlatforms() {
 		logger.Info("Checking token for platform",
 			"platform", platformName)
 
@@ -78,8 +147,6 @@ func (s *SchedulerService) RefreshAllTokens(ctx context.Context) {
 				"error", err)
 		}
 	}
-
-	logger.Info("Completed token refresh check for all platforms")
 }
 
 // RefreshPlatformToken 刷新指定平台的 token
@@ -87,58 +154,67 @@ func (s *SchedulerService) RefreshPlatformToken(ctx context.Context, platformNam
 	logger := log.FromContext(ctx).With("method", "RefreshPlatformToken")
 	ctx = log.WithLogger(ctx, logger)
 
-	// 目前只处理 Threads 平台
-	if platform.Config.Type != social.PlatformThreads.String() {
-		logger.Info("Skipping non-threads platform", "platform", platformName, "type", platform.Config.Type)
+	// 只处理实现了 TokenRefresher 的平台（Threads、Bluesky、Mastodon）
+	refresher, ok := platform.Client.(social.TokenRefresher)
+	if !ok {
+		logger.Info("Skipping platform without token refresh", "platform", platformName, "type", platform.Config.Type)
 		return nil
 	}
 
-	// 类型断言获取 ThreadsClient
-	threadsClient, ok := platform.Client.(*social.ThreadsClient)
-	if !ok {
-		logger.Error("Platform client is not a ThreadsClient", "platform", platformName)
-		return fmt.Errorf("platform %s client is not a ThreadsClient", platformName)
-	}
+	logger.Info("Checking token for platform", "platform", platformName, "type", platform.Config.Type)
 
-	logger.Info("Checking token for Threads platform", "platform", platformName)
+	tokenMetrics := metrics.NewTokenMetrics(platformName)
 
 	// 检查并刷新 token（如果需要）
-	err := threadsClient.EnsureValidToken(ctx)
+	err := refresher.EnsureValidToken(ctx)
+	// 刷新失败时 token 也可能仍然有效（或已过期），照常上报剩余时间
+	s.recordTokenExpiry(ctx, platformName, platform.Client, tokenMetrics)
 	if err != nil {
+		tokenMetrics.IncRefresh(metrics.StatusError)
 		logger.Error("Failed to ensure valid token",
 			"platform", platformName,
 			"error", err)
 		return fmt.Errorf("failed to ensure valid token for platform %s: %w", platformName, err)
 	}
+	tokenMetrics.IncRefresh(metrics.StatusSuccess)
 
 	logger.Info("Token check completed successfully", "platform", platformName)
 	return nil
 }
 
+// recordTokenExpiry 更新实现了 social.TokenExpiryReporter 的平台的 token 剩余时间指标
+func (s *SchedulerService) recordTokenExpiry(ctx context.Context, platformName string, client social.SocialClient, tokenMetrics *metrics.TokenMetrics) {
+	reporter, ok := client.(social.TokenExpiryReporter)
+	if !ok {
+		return
+	}
+	expiresAt, ok, err := reporter.TokenExpiresAt(ctx)
+	if err != nil {
+		log.FromContext(ctx).Warn("Failed to read token expiry", "platform", platformName, "error", err)
+		return
+	}
+	if !ok {
+		tokenMetrics.SetNoExpiry()
+		return
+	}
+	tokenMetrics.SetExpiresIn(time.Until(expiresAt))
+}
+
 // RefreshThreadsTokenManually 手动刷新 Threads token（用于测试或紧急情况）
 func (s *SchedulerService) RefreshThreadsTokenManually(ctx context.Context, platformName string) error {
 	logger := log.FromContext(ctx).With("method", "RefreshThreadsTokenManually")
 	ctx = log.WithLogger(ctx, logger)
 
-	platform, err := s.socialService.GetPlatform(platformName)
-	if err != nil {
-		return fmt.Errorf("platform not found: %w", err)
-	}
-
-	if platform.Config.Type != social.PlatformThreads.String() {
-		return fmt.Errorf("platform %s is not a threads platform", platformName)
-	}
-
 	logger.Info("Getting platform", "platform", platformName)
-	threadsClient, ok := platform.Client.(*social.ThreadsClient)
-	if !ok {
-		return fmt.Errorf("platform %s client is not a ThreadsClient", platformName)
+	threadsClient, err := s.threadsClient(platformName)
+	if err != nil {
+		return err
 	}
 
 	logger.Info("Manually refreshing token for Threads platform", "platform", platformName)
 
 	// 强制刷新 token
-	tokenResp, err := threadsClient.RefreshLongLivedToken()
+	tokenResp, err := threadsClient.RefreshLongLivedToken(ctx)
 	if err != nil {
 		logger.Error("Failed to refresh token manually",
 			"platform", platformName,
@@ -162,6 +238,61 @@ func (s *SchedulerService) RefreshThreadsTokenManually(ctx context.Context, plat
 	return nil
 }
 
+// ExchangeThreadsToken 用短期 token 换取 Threads 长期 token 并通过 TokenManager 保存，
+// 返回新 token 的过期时间。用于首次配置，之后由定时任务刷新
+func (s *SchedulerService) ExchangeThreadsToken(ctx context.Context, platformName, shortLivedToken string) (time.Time, error) {
+	logger := log.FromContext(ctx).With("method", "ExchangeThreadsToken")
+	ctx = log.WithLogger(ctx, logger)
+
+	threadsClient, err := s.threadsClient(platformName)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	logger.Info("Exchanging short-lived token for Threads platform", "platform", platformName)
+
+	tokenResp, err := threadsClient.ExchangeForLongLivedToken(ctx, shortLivedToken)
+	if err != nil {
+		logger.Error("Failed to exchange token",
+			"platform", platformName,
+			"error", err)
+		return time.Time{}, fmt.Errorf("failed to exchange token for platform %s: %w", platformName, err)
+	}
+
+	err = threadsClient.SaveTokenToDao(ctx, tokenResp)
+	if err != nil {
+		logger.Error("Failed to save exchanged token",
+			"platform", platformName,
+			"error", err)
+		return time.Time{}, fmt.Errorf("failed to save exchanged token for platform %s: %w", platformName, err)
+	}
+
+	expiresAt := tokenResp.GetTokenExpirationTime()
+	logger.Info("Token exchanged successfully",
+		"platform", platformName,
+		"expires_at", expiresAt.Format(time.RFC3339))
+
+	return expiresAt, nil
+}
+
+// threadsClient returns the ThreadsClient of the named platform.
+func (s *SchedulerService) threadsClient(platformName string) (*social.ThreadsClient, error) {
+	platform, err := s.socialService.GetPlatform(platformName)
+	if err != nil {
+		return nil, fmt.Errorf("platform not found: %w", err)
+	}
+
+	if platform.Config.Type != social.PlatformThreads.String() {
+		return nil, fmt.Errorf("platform %s is not a threads platform: %w", platformName, ErrTokenUnsupported)
+	}
+
+	threadsClient, ok := platform.Client.(*social.ThreadsClient)
+	if !ok {
+		return nil, fmt.Errorf("platform %s client is not a ThreadsClient", platformName)
+	}
+	return threadsClient, nil
+}
+
 // GetTokenStatus 获取指定平台的 token 状态信息
 func (s *SchedulerService) GetTokenStatus(ctx context.Context, platformName string) (*TokenStatus, error) {
 	platform, err := s.socialService.GetPlatform(platformName)
@@ -198,15 +329,21 @@ func (s *SchedulerService) GetTokenStatus(ctx context.Context, platformName stri
 	status.HasToken = true
 	status.ExpiresAt = tokenInfo.ExpiresAt
 
+	tokenMetrics := metrics.NewTokenMetrics(platformName)
 	if tokenInfo.ExpiresAt == nil {
+		tokenMetrics.SetNoExpiry()
 		status.Message = "Token found but no expiration time"
 		status.IsExpiringSoon = false
 	} else {
 		timeUntilExpiry := time.Until(*tokenInfo.ExpiresAt)
+		tokenMetrics.SetExpiresIn(timeUntilExpiry)
 		status.TimeUntilExpiry = &timeUntilExpiry
 
-		// 检查是否在 7 天内过期
-		const refreshThreshold = 7 * 24 * time.Hour
+		// 检查是否在刷新阈值（默认 7 天）内过期
+		refreshThreshold := social.DefaultTokenRefreshThreshold
+		if thresholder, ok := platform.Client.(social.TokenRefreshThresholder); ok {
+			refreshThreshold = thresholder.RefreshThreshold()
+		}
 		status.IsExpiringSoon = timeUntilExpiry <= refreshThreshold
 
 		if timeUntilExpiry <= 0 {
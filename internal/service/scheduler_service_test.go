@@ -2,12 +2,15 @@ package service
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	"github.com/bsm/redislock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.orx.me/apps/hyper-sync/internal/dao"
 	"go.orx.me/apps/hyper-sync/internal/social"
 )
 
@@ -34,33 +37,10 @@ func (m *MockTokenManager) SaveAccessToken(ctx context.Context, platform, access
 	return args.Error(0)
 }
 
-// MockRedisClient 模拟 Redis 客户端
-type MockRedisClient struct {
-	mock.Mock
-}
-
-// MockRedisLock 模拟 Redis 锁
-type MockRedisLock struct {
-	mock.Mock
-}
-
-func (m *MockRedisLock) Release(ctx context.Context) error {
-	args := m.Called(ctx)
-	return args.Error(0)
-}
-
-func (m *MockRedisClient) Obtain(ctx context.Context, key string, ttl time.Duration, opt *redislock.Options) (*redislock.Lock, error) {
-	args := m.Called(ctx, key, ttl, opt)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*redislock.Lock), args.Error(1)
-}
-
 func TestSchedulerService_GetTokenStatus(t *testing.T) {
 	newSchedulerService := func() (*SchedulerService, *MockTokenManager) {
 		mockTokenManager := &MockTokenManager{}
-		mockRedisClient := &redislock.Client{}
+		locker := dao.NewMemoryLocker()
 
 		// 创建模拟的 SocialService
 		socialService := &SocialService{
@@ -80,7 +60,7 @@ func TestSchedulerService_GetTokenStatus(t *testing.T) {
 			},
 		}
 
-		return NewSchedulerService(socialService, mockRedisClient, mockTokenManager), mockTokenManager
+		return NewSchedulerService(socialService, locker, mockTokenManager), mockTokenManager
 	}
 
 	t.Run("should return token status for threads platform", func(t *testing.T) {
@@ -189,3 +169,168 @@ func TestSchedulerService_GetTokenStatus(t *testing.T) {
 		mockTokenManager.AssertExpectations(t)
 	})
 }
+
+// queueClient is a buffer-based source whose pending posts can be cleared.
+type queueClient struct {
+	name string
+
+	mu      sync.Mutex
+	pending int
+}
+
+func (q *queueClient) enqueue() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending++
+}
+
+func (q *queueClient) ClearQueue() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := q.pending
+	q.pending = 0
+	return n
+}
+
+func (q *queueClient) Post(_ context.Context, _ *social.Post) (interface{}, error) { return nil, nil }
+
+func (q *queueClient) ListPosts(_ context.Context, _ int) ([]*social.Post, error) { return nil, nil }
+
+func (q *queueClient) Name() string { return q.name }
+
+func TestSchedulerService_ClearQueue(t *testing.T) {
+	tg := &queueClient{name: "telegram"}
+	other := &queueClient{name: "telegram-2"}
+	for i := 0; i < 5; i++ {
+		tg.enqueue()
+	}
+	other.enqueue()
+
+	s := NewSchedulerService(&SocialService{platforms: map[string]*social.SocialPlatform{
+		"telegram":   {Name: "telegram", Client: tg},
+		"telegram-2": {Name: "telegram-2", Client: other},
+		"mastodon":   {Name: "mastodon", Client: &fakeSyncClient{name: "mastodon"}},
+	}}, nil, nil)
+
+	cleared, err := s.ClearQueue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 6, cleared)
+	assert.Zero(t, tg.pending)
+
+	// Enqueueing concurrently with a clear never loses or double-counts a task.
+	var wg sync.WaitGroup
+	var total atomic.Int64
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); tg.enqueue() }()
+		go func() {
+			defer wg.Done()
+			n, _ := s.ClearQueue(context.Background())
+			total.Add(int64(n))
+		}()
+	}
+	wg.Wait()
+	n, _ := s.ClearQueue(context.Background())
+	total.Add(int64(n))
+	assert.Equal(t, int64(100), total.Load())
+}
+
+// refresherClient is a SocialClient that also implements social.TokenRefresher.
+type refresherClient struct {
+	name  string
+	calls atomic.Int32
+	err   error
+}
+
+func (r *refresherClient) EnsureValidToken(_ context.Context) error {
+	r.calls.Add(1)
+	return r.err
+}
+
+func (r *refresherClient) Post(_ context.Context, _ *social.Post) (interface{}, error) {
+	return nil, nil
+}
+
+func (r *refresherClient) ListPosts(_ context.Context, _ int) ([]*social.Post, error) {
+	return nil, nil
+}
+
+func (r *refresherClient) Name() string { return r.name }
+
+func TestSchedulerService_RefreshAllTokens_CallsTokenRefreshers(t *testing.T) {
+	bluesky := &refresherClient{name: "bluesky"}
+	mastodon := &refresherClient{name: "mastodon", err: errors.New("token revoked")}
+
+	s := NewSchedulerService(&SocialService{platforms: map[string]*social.SocialPlatform{
+		"bluesky":  {Name: "bluesky", Config: &social.PlatformConfig{Type: "bluesky"}, Client: bluesky},
+		"mastodon": {Name: "mastodon", Config: &social.PlatformConfig{Type: "mastodon"}, Client: mastodon},
+		"memos":    {Name: "memos", Config: &social.PlatformConfig{Type: "memos"}, Client: &fakeSyncClient{name: "memos"}},
+	}}, nil, nil)
+
+	// One platform failing does not stop the others.
+	s.refreshAllTokens(context.Background())
+
+	assert.EqualValues(t, 1, bluesky.calls.Load())
+	assert.EqualValues(t, 1, mastodon.calls.Load())
+}
+
+// retrierClient is a refresherClient whose last refresh asked for a retry.
+type retrierClient struct {
+	refresherClient
+	retryIn time.Duration
+}
+
+func (r *retrierClient) RefreshRetryIn() (time.Duration, bool) {
+	return r.retryIn, r.retryIn > 0
+}
+
+func TestSchedulerService_TokenRetryTimer(t *testing.T) {
+	threads := &retrierClient{refresherClient: refresherClient{name: "threads"}}
+	s := NewSchedulerService(&SocialService{platforms: map[string]*social.SocialPlatform{
+		"threads": {Name: "threads", Client: threads},
+		"bluesky": {Name: "bluesky", Client: &refresherClient{name: "bluesky"}},
+	}}, nil, nil)
+
+	assert.Nil(t, s.tokenRetryTimer(time.Minute), "no retry pending")
+
+	threads.retryIn = 5 * time.Minute
+	assert.Nil(t, s.tokenRetryTimer(time.Minute), "the regular tick comes first")
+
+	threads.retryIn = 10 * time.Millisecond
+	retry := s.tokenRetryTimer(time.Minute)
+	if assert.NotNil(t, retry) {
+		select {
+		case <-retry:
+		case <-time.After(time.Second):
+			t.Fatal("retry timer did not fire")
+		}
+	}
+}
+
+func TestSchedulerService_RefreshPlatformToken(t *testing.T) {
+	s := NewSchedulerService(&SocialService{}, nil, nil)
+	ctx := context.Background()
+
+	t.Run("calls EnsureValidToken", func(t *testing.T) {
+		client := &refresherClient{name: "bluesky"}
+		platform := &social.SocialPlatform{Name: "bluesky", Config: &social.PlatformConfig{Type: "bluesky"}, Client: client}
+
+		assert.NoError(t, s.RefreshPlatformToken(ctx, "bluesky", platform))
+		assert.EqualValues(t, 1, client.calls.Load())
+	})
+
+	t.Run("wraps refresh errors", func(t *testing.T) {
+		client := &refresherClient{name: "bluesky", err: errors.New("session expired")}
+		platform := &social.SocialPlatform{Name: "bluesky", Config: &social.PlatformConfig{Type: "bluesky"}, Client: client}
+
+		err := s.RefreshPlatformToken(ctx, "bluesky", platform)
+		assert.ErrorIs(t, err, client.err)
+		assert.Contains(t, err.Error(), "platform bluesky")
+	})
+
+	t.Run("skips clients without token refresh", func(t *testing.T) {
+		platform := &social.SocialPlatform{Name: "memos", Config: &social.PlatformConfig{Type: "memos"}, Client: &fakeSyncClient{name: "memos"}}
+
+		assert.NoError(t, s.RefreshPlatformToken(ctx, "memos", platform))
+	})
+}
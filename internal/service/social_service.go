@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"go.orx.me/apps/hyper-sync/internal/conf"
@@ -12,6 +13,12 @@ import (
 // SocialService handles interactions with social platforms
 type SocialService struct {
 	platforms map[string]*social.SocialPlatform
+	// cooldowns 记录被限流的平台，冷却结束前 doSync 跳过它们
+	cooldowns platformCooldowns
+	// breakers 为每个目标平台维护熔断器，连续失败后 doSync 暂停向其发帖
+	breakers platformBreakers
+	// postLimiters 为配置了 post_rate_limit 的目标平台维护令牌桶，控制发帖节奏
+	postLimiters platformPostLimiters
 }
 
 // NewSocialService creates a new social service
@@ -21,8 +28,25 @@ func NewSocialService(tokenManager social.TokenManager, cursorDao social.SyncCur
 	if conf.Conf.Storage != nil && conf.Conf.Storage.S3 != nil {
 		cdnDomain = conf.Conf.Storage.S3.CDNDomain
 	}
+	if conf.Conf.Sync != nil && conf.Conf.Sync.MaxMediaBytes > 0 {
+		social.MaxMediaBytes = conf.Conf.Sync.MaxMediaBytes
+	}
+	if conf.Conf.Sync != nil && conf.Conf.Sync.MediaCacheBytes > 0 {
+		social.MediaCache = social.NewMediaLRUCache(conf.Conf.Sync.MediaCacheBytes)
+	}
+	httpClients, err := social.NewHTTPClientFactory(conf.Conf.HTTP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure http client: %w", err)
+	}
+	social.MediaHTTPClient = httpClients.Client()
+	if httpConf := conf.Conf.HTTP; httpConf != nil {
+		if httpConf.MaxResponseBytes > 0 {
+			social.MaxResponseBytes = httpConf.MaxResponseBytes
+		}
+		social.StrictJSON = httpConf.StrictJSON
+	}
 	// Initialize platforms with the configuration
-	platforms, err := social.InitSocialPlatforms(config, tokenManager, cursorDao, objectStorage, cdnDomain)
+	platforms, err := social.InitSocialPlatforms(config, tokenManager, cursorDao, objectStorage, cdnDomain, httpClients)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize social platforms: %w", err)
 	}
@@ -33,20 +57,41 @@ func NewSocialService(tokenManager social.TokenManager, cursorDao social.SyncCur
 		platformMap[platform.Name] = platform
 	}
 
-	return &SocialService{
+	s := &SocialService{
 		platforms: platformMap,
-	}, nil
+	}
+	if syncConf := conf.Conf.Sync; syncConf != nil {
+		s.breakers.threshold = syncConf.CircuitBreakerThreshold
+		s.breakers.window = syncConf.CircuitBreakerWindow
+		s.breakers.cooldown = syncConf.CircuitBreakerCooldown
+	}
+	return s, nil
 }
 
+// ErrPlatformNotFound is returned by GetPlatform for a name that is not configured.
+var ErrPlatformNotFound = errors.New("platform not found")
+
 // GetPlatform gets a platform by name
 func (s *SocialService) GetPlatform(name string) (*social.SocialPlatform, error) {
 	platform, ok := s.platforms[name]
 	if !ok {
-		return nil, fmt.Errorf("platform not found: %s", name)
+		return nil, fmt.Errorf("%w: %s", ErrPlatformNotFound, name)
 	}
 	return platform, nil
 }
 
+// NormalizeContent applies targetPlatform's normalize rules to content, such
+// as stripping Memos tags before posting to Mastodon, and shortens its links
+// when the platform has a link shortener. Unknown platforms get content
+// back unchanged.
+func (s *SocialService) NormalizeContent(ctx context.Context, content, targetPlatform string) string {
+	platform, ok := s.platforms[targetPlatform]
+	if !ok {
+		return content
+	}
+	return platform.NormalizeContent(ctx, content)
+}
+
 // GetAllPlatforms returns all configured platforms
 func (s *SocialService) GetAllPlatforms() map[string]*social.SocialPlatform {
 	return s.platforms
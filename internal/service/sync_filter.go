@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// hashtagPattern matches #tag where the # starts a word. Tags may contain
+// letters, digits, '_', '-' and '/' (Memos uses '/' for nested tags), so
+// "#work/notes" is one tag while markdown headings ("# Title") and
+// fragments inside URLs ("page#anchor") are not tags.
+var hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&/#])#([\p{L}\p{N}_][\p{L}\p{N}_/\-]*)`)
+
+// SyncFilters decides which source posts are cross-posted. A post passes if
+// it has at least one IncludeTags tag (when set), none of the ExcludeTags
+// tags, and matches ContentMatch (when set). Tags compare case-insensitively
+// and with or without the leading '#'.
+type SyncFilters struct {
+	IncludeTags  []string
+	ExcludeTags  []string
+	ContentMatch *regexp.Regexp
+}
+
+// NewSyncFilters builds filters from configuration. It returns nil when no
+// filter is configured, so every post passes.
+func NewSyncFilters(cfg *conf.SyncFilters) (*SyncFilters, error) {
+	if cfg == nil || (len(cfg.IncludeTags) == 0 && len(cfg.ExcludeTags) == 0 && cfg.ContentMatch == "") {
+		return nil, nil
+	}
+
+	f := &SyncFilters{
+		IncludeTags: normalizeTags(cfg.IncludeTags),
+		ExcludeTags: normalizeTags(cfg.ExcludeTags),
+	}
+	if cfg.ContentMatch != "" {
+		re, err := regexp.Compile(cfg.ContentMatch)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sync filter content_match %q: %w", cfg.ContentMatch, err)
+		}
+		f.ContentMatch = re
+	}
+	return f, nil
+}
+
+// Match reports whether post should be synced. When it should not, reason
+// says which filter rejected it.
+func (f *SyncFilters) Match(post *social.Post) (ok bool, reason string) {
+	if f == nil {
+		return true, ""
+	}
+
+	tags := extractHashtags(post.Content)
+	has := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		has[tag] = true
+	}
+
+	for _, tag := range f.ExcludeTags {
+		if has[tag] {
+			return false, "excluded tag #" + tag
+		}
+	}
+
+	if len(f.IncludeTags) > 0 {
+		included := false
+		for _, tag := range f.IncludeTags {
+			if has[tag] {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false, "missing included tag"
+		}
+	}
+
+	if f.ContentMatch != nil && !f.ContentMatch.MatchString(post.Content) {
+		return false, "content does not match " + f.ContentMatch.String()
+	}
+
+	return true, ""
+}
+
+// extractHashtags returns the distinct, normalized hashtags in content in
+// order of first appearance.
+func extractHashtags(content string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, m := range hashtagPattern.FindAllStringSubmatch(content, -1) {
+		tag := normalizeTag(m[1])
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(tag), "#/"))
+}
+
+func normalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = normalizeTag(tag); tag != "" {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	assert.Equal(t, []string{"public", "work/notes"}, extractHashtags("hi #Public #work/notes. #public"))
+	assert.Empty(t, extractHashtags("# Heading\nsee https://example.com/page#anchor"))
+	assert.Equal(t, []string{"中文"}, extractHashtags("标签 #中文"))
+}
+
+func TestSyncFilters_Match(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *conf.SyncFilters
+		content string
+		want    bool
+	}{
+		{"no filters", nil, "anything", true},
+
+		{"include matches", &conf.SyncFilters{IncludeTags: []string{"#public"}}, "hello #public", true},
+		{"include is case-insensitive", &conf.SyncFilters{IncludeTags: []string{"Public"}}, "hello #PUBLIC", true},
+		{"include missing", &conf.SyncFilters{IncludeTags: []string{"public"}}, "hello #private", false},
+		{"include any of several", &conf.SyncFilters{IncludeTags: []string{"public", "blog"}}, "#blog post", true},
+
+		{"exclude matches", &conf.SyncFilters{ExcludeTags: []string{"draft"}}, "wip #draft", false},
+		{"exclude absent", &conf.SyncFilters{ExcludeTags: []string{"draft"}}, "done #public", true},
+		{"exclude only, untagged", &conf.SyncFilters{ExcludeTags: []string{"draft"}}, "no tags", true},
+
+		{"combined pass", &conf.SyncFilters{IncludeTags: []string{"public"}, ExcludeTags: []string{"draft"}}, "#public post", true},
+		{"combined exclude wins", &conf.SyncFilters{IncludeTags: []string{"public"}, ExcludeTags: []string{"draft"}}, "#public #draft", false},
+		{"combined missing include", &conf.SyncFilters{IncludeTags: []string{"public"}, ExcludeTags: []string{"draft"}}, "plain", false},
+
+		{"content match", &conf.SyncFilters{ContentMatch: `(?i)golang`}, "I like Golang", true},
+		{"content mismatch", &conf.SyncFilters{ContentMatch: `(?i)golang`}, "I like rust", false},
+		{"content and tag", &conf.SyncFilters{IncludeTags: []string{"public"}, ContentMatch: `^\S`}, " #public", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewSyncFilters(tt.cfg)
+			require.NoError(t, err)
+
+			ok, reason := f.Match(&social.Post{Content: tt.content})
+			assert.Equal(t, tt.want, ok)
+			if !ok {
+				assert.NotEmpty(t, reason)
+			}
+		})
+	}
+}
+
+func TestNewSyncFilters_InvalidRegex(t *testing.T) {
+	_, err := NewSyncFilters(&conf.SyncFilters{ContentMatch: "("})
+	assert.Error(t, err)
+}
+
+func TestSyncService_Filters(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "public", Content: "hello #public", CreatedAt: time.Now()},
+		{ID: "untagged", Content: "hello", CreatedAt: time.Now()},
+		{ID: "draft", Content: "hello #public #draft", CreatedAt: time.Now()},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, target)
+
+	filters, err := NewSyncFilters(&conf.SyncFilters{IncludeTags: []string{"public"}, ExcludeTags: []string{"draft"}})
+	require.NoError(t, err)
+	s.Filters = filters
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, []string{"public"}, target.postedIDs())
+	model, err := postDao.GetBySocialAndSocialID(context.Background(), "memos", "untagged")
+	require.NoError(t, err)
+	assert.Nil(t, model, "filtered posts are not recorded")
+}
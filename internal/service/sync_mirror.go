@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"butterfly.orx.me/core/log"
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// 多源同步：多个平台各自配置 sync_to 互相镜像（如两个 Memos 实例互为目标），
+// 每个源仍由独立的 SyncService 拉取。源 A 跨发到 B 的帖子会在 B 的下一轮同步中
+// 被当作新帖拉到，若不识别就会再发回 A 并重复发到其他目标，形成循环。
+
+// mirrorMatchWindow is how long after a peer source's post a post with the
+// same content is still taken to be its mirror. It covers cross-posts delayed
+// by sync intervals, retries and platform cooldowns.
+const mirrorMatchWindow = 24 * time.Hour
+
+// mirrorOrigin returns the stored post that post, listed from the main
+// social, was mirrored from, or nil when post originated here. A post is a
+// mirror when it is the platform ID some stored post got when cross-posted
+// to the main social, or when a peer source (one whose sync_to includes the
+// main social) already stored the same content, which also covers the
+// window before the peer's cross-post status is written.
+func (s *SyncService) mirrorOrigin(ctx context.Context, post *social.Post, contentHash string) *dao.PostModel {
+	logger := log.FromContext(ctx)
+
+	ids := []string{post.ID}
+	if post.OriginalID != "" && post.OriginalID != post.ID {
+		ids = append(ids, post.OriginalID)
+	}
+	for _, id := range ids {
+		origin, err := s.postDao.GetByCrossPostPlatformID(ctx, s.mainSocial, id)
+		if err != nil {
+			logger.Warn("Error looking up cross-post origin", "error", err, "post_id", post.ID)
+			continue
+		}
+		if origin != nil && origin.Social != s.mainSocial {
+			return origin
+		}
+	}
+
+	if strings.TrimSpace(post.Content) == "" && len(post.Media) == 0 {
+		return nil
+	}
+	// 镜像在源帖之后发出，只在其前 mirrorMatchWindow 内找源帖，更早的相同内容是另一条帖子
+	createdAt := s.postCreatedAt(post)
+	for _, peer := range s.peerSources() {
+		origin, err := s.postDao.GetPostByContentHash(ctx, peer, contentHash,
+			createdAt.Add(-mirrorMatchWindow), createdAt.Add(contentHashMatchWindow))
+		if err != nil {
+			logger.Warn("Error looking up post by content hash", "error", err, "post_id", post.ID, "social", peer)
+			continue
+		}
+		if origin != nil {
+			return origin
+		}
+	}
+	return nil
+}
+
+// peerSources returns the targets that also sync into the main social.
+func (s *SyncService) peerSources() []string {
+	var peers []string
+	for _, target := range s.socials {
+		platform, err := s.socialService.GetPlatform(target)
+		if err != nil || platform.Config == nil {
+			continue
+		}
+		if slices.Contains(platform.Config.SyncTo, s.mainSocial) {
+			peers = append(peers, target)
+		}
+	}
+	return peers
+}
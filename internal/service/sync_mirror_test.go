@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// newMirrorPair wires two sources that sync into each other and into a
+// shared third target, both backed by the same database, as InitJob does
+// for two platforms whose sync_to lists each other.
+func newMirrorPair(a, b, shared *fakeSyncClient) (sa, sb *SyncService) {
+	postDao := newMemoryPostDao()
+	sa = newTestSyncService(postDao, a, b, shared)
+	sb = newTestSyncService(postDao, b, a, shared)
+	for _, s := range []*SyncService{sa, sb} {
+		s.socialService.platforms[a.name].Config.SyncTo = []string{b.name, shared.name}
+		s.socialService.platforms[b.name].Config.SyncTo = []string{a.name, shared.name}
+	}
+	return sa, sb
+}
+
+func TestSyncService_MirroredPostIsNotSyncedBack(t *testing.T) {
+	a := &fakeSyncClient{name: "memos-a", posts: []*social.Post{{ID: "1", Content: "hello", CreatedAt: time.Now()}}}
+	b := &fakeSyncClient{name: "memos-b"}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	sa, sb := newMirrorPair(a, b, mastodon)
+
+	require.NoError(t, sa.doSync(context.Background()))
+	require.Equal(t, []string{"1"}, b.postedIDs())
+
+	// The copy on B now shows up when listing B, with the ID B gave it and
+	// content rewritten by B's template.
+	b.posts = []*social.Post{{ID: "memos-b-1", Content: "hello\n\nvia memos-a", CreatedAt: time.Now()}}
+	require.NoError(t, sb.doSync(context.Background()))
+	require.NoError(t, sa.doSync(context.Background()))
+
+	assert.Empty(t, a.postedIDs(), "the mirror must not be posted back to its origin")
+	assert.Equal(t, []string{"1"}, mastodon.postedIDs(), "the shared target gets the post once")
+}
+
+func TestSyncService_MirroredContentBeforeStatusIsRecorded(t *testing.T) {
+	a := &fakeSyncClient{name: "memos-a", posts: []*social.Post{{ID: "1", Content: "hello", CreatedAt: time.Now()}}}
+	b := &fakeSyncClient{name: "memos-b"}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	sa, sb := newMirrorPair(a, b, mastodon)
+	sa.socials = []string{"mastodon"} // A stored the post but has not reached B yet
+
+	require.NoError(t, sa.doSync(context.Background()))
+
+	b.posts = []*social.Post{{ID: "memos/xyz", Content: "hello", CreatedAt: time.Now()}}
+	require.NoError(t, sb.doSync(context.Background()))
+
+	assert.Empty(t, a.postedIDs())
+	assert.Equal(t, []string{"1"}, mastodon.postedIDs(), "same content from a peer source is not posted twice")
+}
+
+func TestSyncService_SameContentFromNonPeerIsSynced(t *testing.T) {
+	postDao := newMemoryPostDao()
+	a := &fakeSyncClient{name: "memos-a", posts: []*social.Post{{ID: "1", Content: "hello", CreatedAt: time.Now()}}}
+	b := &fakeSyncClient{name: "memos-b", posts: []*social.Post{{ID: "2", Content: "hello", CreatedAt: time.Now()}}}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	sa := newTestSyncService(postDao, a, mastodon)
+	sb := newTestSyncService(postDao, b, a, mastodon) // A does not sync into B
+
+	require.NoError(t, sa.doSync(context.Background()))
+	require.NoError(t, sb.doSync(context.Background()))
+
+	assert.Equal(t, []string{"2"}, a.postedIDs())
+	assert.Equal(t, []string{"1", "2"}, mastodon.postedIDs())
+}
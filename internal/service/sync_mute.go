@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// MuteFilter skips source posts that mention a muted keyword or account.
+// Each pattern is one of:
+//
+//   - "/expr/": a regular expression matched against the content
+//   - "@handle": a mention of that account, e.g. "@alice" also matches
+//     "@alice@example.social" but not "@alicex"
+//   - anything else: a case-insensitive substring
+type MuteFilter struct {
+	patterns []mutePattern
+}
+
+type mutePattern struct {
+	raw       string
+	substring string
+	re        *regexp.Regexp
+}
+
+// NewMuteFilter compiles sync.mute_patterns. It returns nil when no pattern
+// is configured, so nothing is muted.
+func NewMuteFilter(patterns []string) (*MuteFilter, error) {
+	f := &MuteFilter{}
+	for _, raw := range patterns {
+		p := strings.TrimSpace(raw)
+		switch {
+		case p == "":
+			continue
+		case len(p) > 2 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/"):
+			re, err := regexp.Compile(p[1 : len(p)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid sync mute pattern %q: %w", raw, err)
+			}
+			f.patterns = append(f.patterns, mutePattern{raw: p, re: re})
+		case len(p) > 1 && strings.HasPrefix(p, "@"):
+			re := regexp.MustCompile(`(?i)(?:^|[^\w@])` + regexp.QuoteMeta(p) + `(?:$|[^\w])`)
+			f.patterns = append(f.patterns, mutePattern{raw: p, re: re})
+		default:
+			f.patterns = append(f.patterns, mutePattern{raw: p, substring: strings.ToLower(p)})
+		}
+	}
+	if len(f.patterns) == 0 {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// Muted reports whether post matches a mute pattern, and which one.
+func (f *MuteFilter) Muted(post *social.Post) (muted bool, pattern string) {
+	if f == nil {
+		return false, ""
+	}
+	lower := strings.ToLower(post.Content)
+	for _, p := range f.patterns {
+		if p.re != nil && p.re.MatchString(post.Content) {
+			return true, p.raw
+		}
+		if p.re == nil && strings.Contains(lower, p.substring) {
+			return true, p.raw
+		}
+	}
+	return false, ""
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestMuteFilter_Muted(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		content  string
+		want     string
+	}{
+		{"no patterns", nil, "anything", ""},
+
+		{"substring", []string{"DRAFT"}, "wip: draft of a post", "DRAFT"},
+		{"substring absent", []string{"draft"}, "finished post", ""},
+
+		{"handle", []string{"@alice"}, "thanks @alice!", "@alice"},
+		{"handle is case-insensitive", []string{"@alice"}, "cc @Alice", "@alice"},
+		{"handle with instance", []string{"@alice"}, "hi @alice@example.social", "@alice"},
+		{"longer handle", []string{"@alice"}, "hi @alicex", ""},
+		{"email is not a mention", []string{"@example.com"}, "mail bob@example.com", ""},
+
+		{"regex", []string{`/(?i)^re:/`}, "Re: your question", `/(?i)^re:/`},
+		{"regex mismatch", []string{`/(?i)^re:/`}, "about re: nothing", ""},
+
+		{"first match wins", []string{"secret", "@bob"}, "@bob knows the secret", "secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewMuteFilter(tt.patterns)
+			require.NoError(t, err)
+
+			muted, pattern := f.Muted(&social.Post{Content: tt.content})
+			assert.Equal(t, tt.want != "", muted)
+			assert.Equal(t, tt.want, pattern)
+		})
+	}
+}
+
+func TestNewMuteFilter(t *testing.T) {
+	f, err := NewMuteFilter([]string{"", "  "})
+	require.NoError(t, err)
+	assert.Nil(t, f, "blank patterns mute nothing")
+
+	_, err = NewMuteFilter([]string{"/(/"})
+	assert.Error(t, err)
+}
+
+func TestSyncService_MutedPostsAreSkipped(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "keyword", Content: "note to self #private", CreatedAt: time.Now()},
+		{ID: "mention", Content: "replying to @bob", CreatedAt: time.Now()},
+		{ID: "regex", Content: "TODO: finish this", CreatedAt: time.Now()},
+		{ID: "public", Content: "hello world", CreatedAt: time.Now()},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, target)
+
+	mutes, err := NewMuteFilter([]string{"#private", "@bob", "/^TODO:/"})
+	require.NoError(t, err)
+	s.Mutes = mutes
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, []string{"public"}, target.postedIDs())
+	model, err := postDao.GetBySocialAndSocialID(context.Background(), "memos", "mention")
+	require.NoError(t, err)
+	assert.Nil(t, model, "muted posts are not recorded")
+}
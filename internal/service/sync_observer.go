@@ -0,0 +1,142 @@
+package service
+
+import "context"
+
+// Outcomes reported in PostResult.Status.
+const (
+	// PostStatusSynced 至少一个目标平台收到了帖子，且没有目标失败
+	PostStatusSynced = "synced"
+	// PostStatusSkipped 帖子被跳过，或所有目标都无需跨发（已同步、暂停、熔断等）
+	PostStatusSkipped = "skipped"
+	// PostStatusFailed 帖子入库失败，或至少一个目标跨发失败，之后的同步会重试
+	PostStatusFailed = "failed"
+)
+
+// PostResult is how one source post was handled in a sync run.
+type PostResult struct {
+	Source string
+	PostID string
+	Status string
+	// Reason explains a skip of the whole post, e.g. post_too_old.
+	Reason string
+	// Err is set when the post could not be stored.
+	Err error
+}
+
+// SyncCrossPostResult is the outcome of cross-posting one post to one
+// target in a sync run.
+type SyncCrossPostResult struct {
+	Source   string
+	PostID   string
+	Platform string
+	Success  bool
+	DryRun   bool
+	// Skipped is set when the target deliberately did not publish the post
+	// (Success is false); Reason says why, e.g. visibility_skipped.
+	Skipped bool
+	Reason  string
+}
+
+// SyncResult counts how the posts of one sync run were handled, by their
+// PostResult status.
+type SyncResult struct {
+	// TotalChecked counts posts fetched from the source.
+	TotalChecked int
+	Synced       int
+	Skipped      int
+	Failed       int
+	// PerPlatform counts cross-post outcomes by target platform.
+	PerPlatform map[string]*PlatformSyncResult
+	// Err is set when the run aborted, e.g. the source could not be read;
+	// Sync returns it as well.
+	Err error
+}
+
+// count adds a post with status to r.
+func (r *SyncResult) count(status string) {
+	switch status {
+	case PostStatusSynced:
+		r.Synced++
+	case PostStatusSkipped:
+		r.Skipped++
+	case PostStatusFailed:
+		r.Failed++
+	}
+}
+
+// SyncObserver is notified as a sync run progresses. For each post,
+// OnCrossPostResult fires once per target that was tried, then
+// OnPostProcessed; OnSyncComplete ends the run. Targets of one post are
+// cross-posted in parallel, so OnCrossPostResult must be safe for
+// concurrent use.
+type SyncObserver interface {
+	OnPostProcessed(ctx context.Context, result PostResult)
+	OnCrossPostResult(ctx context.Context, result SyncCrossPostResult)
+	OnSyncComplete(ctx context.Context, result SyncResult)
+}
+
+// AddObserver registers observer for later sync runs. A nil observer is
+// ignored.
+func (s *SyncService) AddObserver(observer SyncObserver) {
+	if observer == nil {
+		return
+	}
+	s.observersMu.Lock()
+	defer s.observersMu.Unlock()
+	s.observers = append(s.observers, observer)
+}
+
+// notifyObservers calls fn for every registered observer.
+func (s *SyncService) notifyObservers(fn func(SyncObserver)) {
+	s.observersMu.RLock()
+	observers := s.observers
+	s.observersMu.RUnlock()
+	for _, observer := range observers {
+		fn(observer)
+	}
+}
+
+// postProcessed counts post in result and tells the observers.
+func (s *SyncService) postProcessed(ctx context.Context, result *SyncResult, post PostResult) {
+	result.count(post.Status)
+	s.notifyObservers(func(o SyncObserver) {
+		o.OnPostProcessed(ctx, post)
+	})
+}
+
+// postSkipped reports that the whole post postID was skipped for reason.
+func (s *SyncService) postSkipped(ctx context.Context, result *SyncResult, postID, reason string) {
+	s.reportSkipped(ctx, postID, "", reason)
+	s.postProcessed(ctx, result, PostResult{Source: s.mainSocial, PostID: postID, Status: PostStatusSkipped, Reason: reason})
+}
+
+// postFailed reports that postID could not be processed because of err.
+func (s *SyncService) postFailed(ctx context.Context, result *SyncResult, postID string, err error) {
+	s.reportProgress(ctx, SyncProgress{Type: SyncProgressFailed, PostID: postID, Error: err.Error()})
+	s.postProcessed(ctx, result, PostResult{Source: s.mainSocial, PostID: postID, Status: PostStatusFailed, Err: err})
+}
+
+// crossPostDone reports the outcome of cross-posting postID to platform.
+// reason explains a skipped outcome.
+func (s *SyncService) crossPostDone(ctx context.Context, postID, platform string, outcome crossPostOutcome, reason string) {
+	progress := SyncProgress{Type: SyncProgressPosted, PostID: postID, Platform: platform}
+	switch outcome {
+	case crossPostFailed:
+		progress.Type = SyncProgressFailed
+	case crossPostSkipped:
+		progress.Type = SyncProgressSkipped
+		progress.Reason = reason
+	}
+	s.reportProgress(ctx, progress)
+	s.notifyObservers(func(o SyncObserver) {
+		o.OnCrossPostResult(ctx, SyncCrossPostResult{
+			Source:   s.mainSocial,
+			PostID:   postID,
+			Platform: platform,
+			Success:  outcome == crossPostPosted,
+			DryRun:   s.dryRun(ctx),
+			Skipped:  outcome == crossPostSkipped,
+			Reason:   reason,
+		})
+	})
+}
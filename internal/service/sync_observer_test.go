@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// recordingObserver records every callback as a string, in call order.
+type recordingObserver struct {
+	mu     sync.Mutex
+	calls  []string
+	result SyncResult
+}
+
+func (o *recordingObserver) record(call string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls = append(o.calls, call)
+}
+
+func (o *recordingObserver) OnPostProcessed(_ context.Context, result PostResult) {
+	o.record(fmt.Sprintf("post %s %s", result.PostID, result.Status))
+}
+
+func (o *recordingObserver) OnCrossPostResult(_ context.Context, result SyncCrossPostResult) {
+	o.record(fmt.Sprintf("cross_post %s %s %t", result.PostID, result.Platform, result.Success))
+}
+
+func (o *recordingObserver) OnSyncComplete(_ context.Context, result SyncResult) {
+	o.mu.Lock()
+	o.result = result
+	o.mu.Unlock()
+	o.record("complete")
+}
+
+func TestSyncService_NotifiesObservers(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "first", CreatedAt: time.Now()},
+		{ID: "2", Content: "second", CreatedAt: time.Now()},
+	}}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky"}
+	s := newTestSyncService(newMemoryPostDao(), source, mastodon, bluesky)
+	observer := &recordingObserver{}
+	s.AddObserver(observer)
+	s.AddObserver(nil)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	calls := observer.calls
+	require.Len(t, calls, 7)
+	// 同一帖子的目标并发跨发，顺序不定，但都在该帖子的 OnPostProcessed 之前
+	assert.ElementsMatch(t, []string{"cross_post 1 mastodon true", "cross_post 1 bluesky true"}, calls[0:2])
+	assert.Equal(t, "post 1 synced", calls[2])
+	assert.ElementsMatch(t, []string{"cross_post 2 mastodon true", "cross_post 2 bluesky true"}, calls[3:5])
+	assert.Equal(t, "post 2 synced", calls[5])
+	assert.Equal(t, "complete", calls[6])
+
+	require.NoError(t, observer.result.Err)
+	assert.Equal(t, 2, observer.result.Synced)
+	assert.Equal(t, 2, observer.result.PerPlatform["bluesky"].Success)
+
+	// 第二轮两个帖子都已同步，只回调跳过与结束
+	observer.calls = nil
+	require.NoError(t, s.doSync(context.Background()))
+	assert.Equal(t, []string{"post 1 skipped", "post 2 skipped", "complete"}, observer.calls)
+}
+
+func TestSyncService_NoObservers(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "first", CreatedAt: time.Now()},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+
+	require.NoError(t, s.doSync(context.Background()))
+	assert.Equal(t, []string{"1"}, target.postedIDs())
+}
@@ -0,0 +1,32 @@
+package service
+
+import "context"
+
+// SyncOptions are settings for a single sync call on a shared SyncService,
+// e.g. a manual dry run or a streamed sync. They apply to that call only
+// and never leak into the scheduled rounds running on the same service.
+type SyncOptions struct {
+	// DryRun makes the call a dry run even when SyncService.DryRun is off.
+	DryRun bool
+	// Progress receives the call's progress events, in addition to
+	// SyncService.Progress.
+	Progress SyncProgressFunc
+}
+
+type syncOptionsKey struct{}
+
+// SyncWithOptions is Sync with per-call options.
+func (s *SyncService) SyncWithOptions(ctx context.Context, opts SyncOptions) (SyncResult, error) {
+	return s.Sync(context.WithValue(ctx, syncOptionsKey{}, opts))
+}
+
+// syncOptions returns the per-call options ctx carries, if any.
+func syncOptions(ctx context.Context) SyncOptions {
+	opts, _ := ctx.Value(syncOptionsKey{}).(SyncOptions)
+	return opts
+}
+
+// dryRun reports whether the sync call of ctx must not post anything.
+func (s *SyncService) dryRun(ctx context.Context) bool {
+	return s.DryRun || syncOptions(ctx).DryRun
+}
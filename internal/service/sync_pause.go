@@ -0,0 +1,44 @@
+package service
+
+import "sort"
+
+// 暂停目标：临时停止向某个目标平台跨发（例如排查令牌问题时），无需修改配置或
+// 重启。暂停状态只保存在内存中，重启后恢复跨发。暂停期间的帖子不会推进游标，
+// 恢复后在 skip_older 窗口内的帖子会补发。
+
+// PauseTarget stops cross-posting to platform from the next post on. It is
+// safe to call while a sync is running.
+func (s *SyncService) PauseTarget(platform string) {
+	s.pausedMu.Lock()
+	defer s.pausedMu.Unlock()
+	if s.paused == nil {
+		s.paused = make(map[string]bool)
+	}
+	s.paused[platform] = true
+}
+
+// ResumeTarget re-enables cross-posting to a platform paused by PauseTarget.
+func (s *SyncService) ResumeTarget(platform string) {
+	s.pausedMu.Lock()
+	defer s.pausedMu.Unlock()
+	delete(s.paused, platform)
+}
+
+// IsTargetPaused reports whether cross-posting to platform is paused.
+func (s *SyncService) IsTargetPaused(platform string) bool {
+	s.pausedMu.RLock()
+	defer s.pausedMu.RUnlock()
+	return s.paused[platform]
+}
+
+// PausedTargets returns the paused platforms, sorted by name.
+func (s *SyncService) PausedTargets() []string {
+	s.pausedMu.RLock()
+	defer s.pausedMu.RUnlock()
+	platforms := make([]string, 0, len(s.paused))
+	for platform := range s.paused {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
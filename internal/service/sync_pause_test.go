@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestSyncService_PausedTargetSkipped(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}
+	threads := &fakeSyncClient{name: "threads"}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, threads, mastodon)
+	ctx := context.Background()
+
+	s.PauseTarget("threads")
+	assert.True(t, s.IsTargetPaused("threads"))
+	assert.Equal(t, []string{"threads"}, s.PausedTargets())
+
+	require.NoError(t, s.doSync(ctx))
+	assert.Empty(t, threads.postedIDs(), "a paused target is skipped")
+	assert.Equal(t, []string{"1"}, mastodon.postedIDs())
+
+	s.ResumeTarget("threads")
+	assert.False(t, s.IsTargetPaused("threads"))
+	assert.Empty(t, s.PausedTargets())
+
+	require.NoError(t, s.doSync(ctx))
+	assert.Equal(t, []string{"1"}, threads.postedIDs(), "the post is cross-posted once resumed")
+	assert.Equal(t, []string{"1"}, mastodon.postedIDs())
+}
+
+func TestSyncService_PauseHoldsCursor(t *testing.T) {
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}}
+	s := newTestSyncService(newMemoryPostDao(), source, &fakeSyncClient{name: "threads"})
+	s.PauseTarget("threads")
+
+	require.NoError(t, s.doSync(context.Background()))
+	assert.True(t, s.cursor.IsZero(), "posts held back by a pause must be listed again")
+}
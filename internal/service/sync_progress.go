@@ -0,0 +1,59 @@
+package service
+
+import "context"
+
+// Progress event types reported through SyncService.Progress and
+// SyncOptions.Progress.
+const (
+	// SyncProgressProcessing 开始处理一条源帖子
+	SyncProgressProcessing = "processing"
+	// SyncProgressPosted 帖子已跨发到 Platform（dry run 时表示本应跨发）
+	SyncProgressPosted = "posted"
+	// SyncProgressSkipped 帖子被跳过；Platform 为空时整条帖子都跳过
+	SyncProgressSkipped = "skipped"
+	// SyncProgressFailed 帖子处理或跨发到 Platform 失败
+	SyncProgressFailed = "failed"
+	// SyncProgressSummary 一轮同步结束，Summary 为本轮统计
+	SyncProgressSummary = "summary"
+)
+
+// SyncProgress is one step of a sync run, e.g. for streaming a manual sync
+// to the caller.
+type SyncProgress struct {
+	Type   string `json:"type"`
+	Source string `json:"source"`
+	PostID string `json:"post_id,omitempty"`
+	// Platform is the target platform of posted/failed/skipped events about
+	// a single cross-post.
+	Platform string `json:"platform,omitempty"`
+	// Reason explains a skip, e.g. post_too_old or target_paused.
+	Reason  string       `json:"reason,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Summary *SyncSummary `json:"summary,omitempty"`
+}
+
+// SyncProgressFunc receives progress events. Cross-posts to different
+// targets run in parallel, so it must be safe for concurrent use.
+type SyncProgressFunc func(SyncProgress)
+
+// reportProgress passes event to s.Progress and to the Progress of the
+// call's SyncOptions, if set.
+func (s *SyncService) reportProgress(ctx context.Context, event SyncProgress) {
+	progress := syncOptions(ctx).Progress
+	if s.Progress == nil && progress == nil {
+		return
+	}
+	event.Source = s.mainSocial
+	if s.Progress != nil {
+		s.Progress(event)
+	}
+	if progress != nil {
+		progress(event)
+	}
+}
+
+// reportSkipped reports that postID was skipped for reason, on platform only
+// when platform is set.
+func (s *SyncService) reportSkipped(ctx context.Context, postID, platform, reason string) {
+	s.reportProgress(ctx, SyncProgress{Type: SyncProgressSkipped, PostID: postID, Platform: platform, Reason: reason})
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// progressRecorder collects progress events from concurrent cross-posts.
+type progressRecorder struct {
+	mu     sync.Mutex
+	events []SyncProgress
+}
+
+func (r *progressRecorder) record(event SyncProgress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// byType returns "post_id/platform" of the events of type typ.
+func (r *progressRecorder) byType(typ string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var got []string
+	for _, event := range r.events {
+		if event.Type == typ {
+			got = append(got, event.PostID+"/"+event.Platform)
+		}
+	}
+	return got
+}
+
+func TestSyncService_ReportsProgress(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "first", CreatedAt: time.Now()},
+		{ID: "2", Content: "second", CreatedAt: time.Now()},
+		{ID: "old", Content: "old", CreatedAt: time.Now().Add(-48 * time.Hour)},
+	}}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky", postErr: errors.New("boom")}
+	s := newTestSyncService(newMemoryPostDao(), source, mastodon, bluesky)
+	recorder := &progressRecorder{}
+	s.Progress = recorder.record
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, []string{"1/", "2/", "old/"}, recorder.byType(SyncProgressProcessing))
+	assert.ElementsMatch(t, []string{"1/mastodon", "2/mastodon"}, recorder.byType(SyncProgressPosted))
+	assert.ElementsMatch(t, []string{"1/bluesky", "2/bluesky"}, recorder.byType(SyncProgressFailed))
+	assert.Equal(t, []string{"old/"}, recorder.byType(SyncProgressSkipped))
+
+	last := recorder.events[len(recorder.events)-1]
+	require.Equal(t, SyncProgressSummary, last.Type)
+	assert.Equal(t, "memos", last.Source)
+	require.NotNil(t, last.Summary)
+	assert.Equal(t, 3, last.Summary.PostsFetched)
+	assert.Equal(t, 2, last.Summary.PostsSynced)
+	assert.Equal(t, 2, last.Summary.Platforms["mastodon"].Success)
+	assert.Equal(t, 2, last.Summary.Platforms["bluesky"].Failed)
+	assert.False(t, last.Summary.FinishedAt.IsZero())
+	for _, event := range recorder.events {
+		assert.Equal(t, "memos", event.Source)
+	}
+
+	// 已同步的目标在下一轮报告为 skipped
+	recorder = &progressRecorder{}
+	s.Progress = recorder.record
+	require.NoError(t, s.doSync(context.Background()))
+	assert.Contains(t, recorder.byType(SyncProgressSkipped), "1/mastodon")
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"butterfly.orx.me/core/log"
+
+	"go.orx.me/apps/hyper-sync/internal/dao"
+)
+
+// RetryPost re-attempts the targets a stored post has not been delivered to
+// and returns its cross-post status for every retried target. With platforms
+// empty every failed target in the post's CrossPostStatus is retried;
+// otherwise only the listed platforms are, including ones never attempted.
+// Targets that already succeeded are never posted again, and targets paused
+// with PauseTarget are left alone and reported with SkipReason
+// "target_paused", as are targets whose skip mode post_rate_limit has no
+// token left, with "target_post_rate_limited". Unlike doSync the max_retries limit does not apply: a
+// manual retry is always attempted.
+func (s *SyncService) RetryPost(ctx context.Context, postModel *dao.PostModel, platforms []string) (map[string]dao.CrossPostStatus, error) {
+	logger := log.FromContext(ctx)
+	postID := postModel.ID.Hex()
+
+	targets := platforms
+	if len(targets) == 0 {
+		for platform := range postModel.CrossPostStatus {
+			targets = append(targets, platform)
+		}
+		sort.Strings(targets)
+	}
+
+	post := postModel.ToSocialPost()
+	// crossPost 日志与回复串查找都使用源平台 ID
+	post.ID = postModel.SocialID
+
+	var retried []string
+	// 未尝试的目标及原因，结果里沿用已保存的状态
+	notTried := make(map[string]string)
+	for _, target := range targets {
+		status := postModel.CrossPostStatus[target]
+		if status.Success && status.CrossPosted {
+			logger.Info("Post already synced successfully, not retrying",
+				"post_id", postID, "target_platform", target)
+			continue
+		}
+		if s.IsTargetPaused(target) {
+			logger.Info("Target platform is paused, not retrying",
+				"post_id", postID, "target_platform", target)
+			notTried[target] = "target_paused"
+			continue
+		}
+		logger.Info("Retrying cross-post", "post_id", postID, "target_platform", target, "retry_count", status.RetryCount)
+		if outcome, reason := s.crossPost(ctx, post, postID, target, status.RetryCount); outcome == crossPostDeferred {
+			notTried[target] = reason
+			continue
+		}
+		retried = append(retried, target)
+	}
+
+	results := make(map[string]dao.CrossPostStatus, len(retried)+len(notTried))
+	for target, reason := range notTried {
+		status := postModel.CrossPostStatus[target]
+		status.SkipReason = reason
+		results[target] = status
+	}
+	if len(retried) == 0 {
+		return results, nil
+	}
+
+	updated, err := s.postDao.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return nil, fmt.Errorf("post %s deleted during retry", postID)
+	}
+	for _, target := range retried {
+		results[target] = updated.CrossPostStatus[target]
+	}
+	return results, nil
+}
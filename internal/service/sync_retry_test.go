@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/dao"
+)
+
+func TestSyncService_RetryPostRetriesFailedTarget(t *testing.T) {
+	source := &fakeSyncClient{name: "memos"}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, mastodon, bluesky)
+
+	id, err := postDao.CreatePost(context.Background(), &dao.PostModel{
+		Social:   "memos",
+		SocialID: "1",
+		Content:  "hello",
+		CrossPostStatus: map[string]dao.CrossPostStatus{
+			"mastodon": {Success: true, CrossPosted: true, PlatformID: "m-1"},
+			"bluesky":  {Success: false, Error: "rate limited", RetryCount: 3},
+		},
+	})
+	require.NoError(t, err)
+	postModel, err := postDao.GetPostByID(context.Background(), id)
+	require.NoError(t, err)
+
+	results, err := s.RetryPost(context.Background(), postModel, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, mastodon.postedIDs())
+	assert.Equal(t, []string{"1"}, bluesky.postedIDs())
+	require.Len(t, results, 1)
+	assert.True(t, results["bluesky"].Success)
+	assert.Equal(t, "bluesky-1", results["bluesky"].PlatformID)
+	assert.Equal(t, "m-1", postModel.CrossPostStatus["mastodon"].PlatformID)
+}
+
+func TestSyncService_RetryPostOnlyRequestedPlatforms(t *testing.T) {
+	source := &fakeSyncClient{name: "memos"}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky", postErr: assert.AnError}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, mastodon, bluesky)
+
+	id, err := postDao.CreatePost(context.Background(), &dao.PostModel{
+		Social:   "memos",
+		SocialID: "1",
+		Content:  "hello",
+		CrossPostStatus: map[string]dao.CrossPostStatus{
+			"mastodon": {Success: false, Error: "timeout", RetryCount: 1},
+			"bluesky":  {Success: false, Error: "rate limited", RetryCount: 1},
+		},
+	})
+	require.NoError(t, err)
+	postModel, err := postDao.GetPostByID(context.Background(), id)
+	require.NoError(t, err)
+
+	results, err := s.RetryPost(context.Background(), postModel, []string{"bluesky"})
+	require.NoError(t, err)
+
+	assert.Empty(t, mastodon.postedIDs())
+	require.Len(t, results, 1)
+	assert.False(t, results["bluesky"].Success)
+	assert.Equal(t, 2, results["bluesky"].RetryCount)
+}
+
+func TestSyncService_RetryPostWithoutFailuresIsNoop(t *testing.T) {
+	source := &fakeSyncClient{name: "memos"}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, mastodon)
+
+	id, err := postDao.CreatePost(context.Background(), &dao.PostModel{
+		Social:   "memos",
+		SocialID: "1",
+		CrossPostStatus: map[string]dao.CrossPostStatus{
+			"mastodon": {Success: true, CrossPosted: true, PlatformID: "m-1"},
+		},
+	})
+	require.NoError(t, err)
+	postModel, err := postDao.GetPostByID(context.Background(), id)
+	require.NoError(t, err)
+
+	results, err := s.RetryPost(context.Background(), postModel, []string{"mastodon"})
+	require.NoError(t, err)
+
+	assert.Empty(t, results)
+	assert.Empty(t, mastodon.postedIDs())
+}
+
+func TestSyncService_RetryPostSkipsPausedTarget(t *testing.T) {
+	source := &fakeSyncClient{name: "memos"}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, mastodon, bluesky)
+	s.PauseTarget("bluesky")
+
+	id, err := postDao.CreatePost(context.Background(), &dao.PostModel{
+		Social:   "memos",
+		SocialID: "1",
+		Content:  "hello",
+		CrossPostStatus: map[string]dao.CrossPostStatus{
+			"mastodon": {Success: false, Error: "timeout", RetryCount: 1},
+			"bluesky":  {Success: false, Error: "rate limited", RetryCount: 1},
+		},
+	})
+	require.NoError(t, err)
+	postModel, err := postDao.GetPostByID(context.Background(), id)
+	require.NoError(t, err)
+
+	results, err := s.RetryPost(context.Background(), postModel, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"1"}, mastodon.postedIDs())
+	assert.Empty(t, bluesky.postedIDs())
+	require.Len(t, results, 2)
+	assert.True(t, results["mastodon"].Success)
+	assert.False(t, results["bluesky"].Success)
+	assert.Equal(t, "target_paused", results["bluesky"].SkipReason)
+	assert.Equal(t, 1, results["bluesky"].RetryCount)
+}
@@ -5,20 +5,23 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"butterfly.orx.me/core/log"
-	"github.com/bsm/redislock"
 	"go.opentelemetry.io/otel/trace"
 	"go.orx.me/apps/hyper-sync/internal/conf"
 	"go.orx.me/apps/hyper-sync/internal/dao"
 	"go.orx.me/apps/hyper-sync/internal/metrics"
 	"go.orx.me/apps/hyper-sync/internal/social"
 	"go.orx.me/apps/hyper-sync/internal/telemetry"
+	"golang.org/x/sync/errgroup"
 )
 
 type SyncService struct {
-	locker *redislock.Client
+	locker dao.Locker
 
 	socialService *SocialService
 	postDao       dao.PostDao
@@ -27,29 +30,214 @@ type SyncService struct {
 
 	mainSocial string
 	socials    []string
+
+	// FetchLimit 每轮从源平台拉取的帖子数上限。来自平台的 fetch_limit，
+	// 未配置时使用 sync.batch_size，再未配置则为 defaultFetchLimit。
+	FetchLimit int
+
+	// cursor 记录上一轮已完整处理的最新帖子，源平台实现 social.SinceLister 时
+	// 下一轮只拉取比它新的帖子。仅在 doSync 中读写，doSync 由锁串行化。
+	cursor social.ListCursor
+	// stateDao 持久化 cursor（sync_state 集合），nil 表示只保存在内存中；
+	// cursorLoaded 表示已读取过保存的水位
+	stateDao     dao.SyncStateDao
+	cursorLoaded bool
+
+	// SkipOlderThan 超过该时长的帖子不再同步，0 表示不限制。
+	// 来自 sync.skip_older（默认 1h，负数表示不限制）。
+	SkipOlderThan time.Duration
+
+	// skipPrivate 为 true 时跳过仅自己可见的帖子。来自 sync.skip_private，可在运行时修改
+	skipPrivate bool
+
+	// settingsMu 保护 pendingSettings：ApplySettings 挂起的新设置，下一轮同步开始时生效
+	settingsMu      sync.Mutex
+	pendingSettings *SyncSettings
+
+	// paused 为 PauseTarget 暂停的目标平台，由 pausedMu 保护
+	pausedMu sync.RWMutex
+	paused   map[string]bool
+
+	// DryRun 为 true 时完整执行拉取/去重/日志/指标流程，但不会真正发帖，
+	// 跨发状态记录为 dry_run 而非 CrossPosted。
+	DryRun bool
+
+	// Progress 不为 nil 时接收每条帖子、每个目标平台的处理进度，
+	// 如 GET /api/sync/stream 实时推送手动同步的进度
+	Progress SyncProgressFunc
+
+	// observers 由 AddObserver 注册，在每条帖子、每次跨发与每轮同步结束时回调
+	observersMu sync.RWMutex
+	observers   []SyncObserver
+
+	// Filters 按标签/正则筛选需要跨发的帖子，nil 表示全部同步。
+	// 来自 sync.filters（include_tags / exclude_tags / content_match）。
+	Filters *SyncFilters
+
+	// Mutes 跳过提到屏蔽关键词或账号的帖子，nil 表示不屏蔽。来自 sync.mute_patterns。
+	Mutes *MuteFilter
+
+	// resyncOnEdit 为 true 时，源帖子内容变化后会调用目标平台的 Update 同步修改
+	resyncOnEdit bool
+
+	// requireAltText 为 true 时，对缺少 alt text 的媒体记录警告
+	requireAltText bool
+
+	// postAttempts/postRetryDelay 控制单次跨发遇到临时错误时的重试
+	postAttempts   int
+	postRetryDelay time.Duration
+
+	// webhook 在每轮同步结束后推送 SyncSummary，nil 表示未配置 webhook.outgoing_urls
+	webhook *OutgoingWebhook
+
+	now func() time.Time
 }
 
-func NewSyncService(dao dao.PostDao, socialService *SocialService, locker *redislock.Client,
+// defaultSkipOlderThan is used when sync.skip_older is not configured.
+const defaultSkipOlderThan = time.Hour
+
+// Defaults for retrying transient cross-post failures within a sync cycle.
+const (
+	defaultPostAttempts   = 3
+	defaultPostRetryDelay = time.Second
+)
+
+// defaultMaxRetries is used when sync.max_retries is not configured.
+const defaultMaxRetries = 3
+
+// defaultFetchLimit is how many posts are listed from the main social per
+// sync when neither the platform's fetch_limit nor sync.batch_size is set.
+const defaultFetchLimit = 100
+
+// contentHashMatchWindow bounds how far apart in creation time a post and a
+// stored post with the same content hash may be for the stored one to be
+// treated as the same post under a new ID.
+const contentHashMatchWindow = 10 * time.Minute
+
+// defaultCrossPostConcurrency bounds how many target platforms a single post
+// is cross-posted to at once when sync.cross_post_concurrency is not set.
+const defaultCrossPostConcurrency = 3
+
+func NewSyncService(dao dao.PostDao, socialService *SocialService, locker dao.Locker,
 	mainSocial string, socials []string) (*SyncService, error) {
 
-	return &SyncService{
-		locker:        locker,
-		mainSocial:    mainSocial,
-		socialService: socialService,
-		postDao:       dao,
-		socials:       socials,
-		metrics:       metrics.NewSyncMetrics(mainSocial),
-		tracer:        telemetry.NewSyncTracer(mainSocial),
-	}, nil
+	s := &SyncService{
+		locker:         locker,
+		mainSocial:     mainSocial,
+		socialService:  socialService,
+		postDao:        dao,
+		socials:        socials,
+		metrics:        metrics.NewSyncMetrics(mainSocial),
+		tracer:         telemetry.NewSyncTracer(mainSocial),
+		FetchLimit:     fetchLimitFromConfig(socialService, mainSocial),
+		SkipOlderThan:  skipOlderThanFromConfig(),
+		postAttempts:   defaultPostAttempts,
+		postRetryDelay: defaultPostRetryDelay,
+		now:            time.Now,
+	}
+	if conf.Conf.Sync != nil {
+		filters, err := NewSyncFilters(conf.Conf.Sync.Filters)
+		if err != nil {
+			return nil, err
+		}
+		s.Filters = filters
+		mutes, err := NewMuteFilter(conf.Conf.Sync.MutePatterns)
+		if err != nil {
+			return nil, err
+		}
+		s.Mutes = mutes
+		s.resyncOnEdit = conf.Conf.Sync.ResyncOnEdit
+		s.requireAltText = conf.Conf.Sync.RequireAltText
+		s.skipPrivate = conf.Conf.Sync.SkipPrivate
+		if conf.Conf.Sync.PostRetryAttempts > 0 {
+			s.postAttempts = conf.Conf.Sync.PostRetryAttempts
+		}
+		if conf.Conf.Sync.PostRetryBaseDelay > 0 {
+			s.postRetryDelay = conf.Conf.Sync.PostRetryBaseDelay
+		}
+	}
+	if wh := conf.Conf.Webhook; wh != nil && len(wh.OutgoingURLs) > 0 {
+		s.webhook = NewOutgoingWebhook(wh.OutgoingURLs, wh.Secret, wh.Timeout)
+	}
+	return s, nil
 }
 
-func (s *SyncService) Sync(ctx context.Context) error {
+// fetchLimitFromConfig resolves the main social's fetch_limit, falling back
+// to sync.batch_size and then defaultFetchLimit.
+func fetchLimitFromConfig(socialService *SocialService, mainSocial string) int {
+	if platform, err := socialService.GetPlatform(mainSocial); err == nil && platform.Config != nil && platform.Config.FetchLimit > 0 {
+		return platform.Config.FetchLimit
+	}
+	if conf.Conf.Sync != nil && conf.Conf.Sync.BatchSize > 0 {
+		return conf.Conf.Sync.BatchSize
+	}
+	return defaultFetchLimit
+}
+
+// maxRetriesFromConfig resolves sync.max_retries, the number of failed
+// cross-posts after which a target is given up for a post (default 3).
+func maxRetriesFromConfig() int {
+	if conf.Conf.Sync != nil && conf.Conf.Sync.MaxRetries > 0 {
+		return conf.Conf.Sync.MaxRetries
+	}
+	return defaultMaxRetries
+}
+
+// crossPostConcurrencyFromConfig resolves sync.cross_post_concurrency.
+func crossPostConcurrencyFromConfig() int {
+	if conf.Conf.Sync != nil && conf.Conf.Sync.CrossPostConcurrency > 0 {
+		return conf.Conf.Sync.CrossPostConcurrency
+	}
+	return defaultCrossPostConcurrency
+}
+
+// skipOlderThanFromConfig resolves sync.skip_older: unset (0) falls back to
+// defaultSkipOlderThan, a negative value disables the age limit.
+func skipOlderThanFromConfig() time.Duration {
+	if conf.Conf.Sync == nil || conf.Conf.Sync.SkipOlder == 0 {
+		return defaultSkipOlderThan
+	}
+	if conf.Conf.Sync.SkipOlder < 0 {
+		return 0
+	}
+	return conf.Conf.Sync.SkipOlder
+}
+
+// Sync runs one sync round of the source platform and returns how its posts
+// were handled. The result is empty when another instance holds the lock.
+func (s *SyncService) Sync(ctx context.Context) (SyncResult, error) {
+	var result SyncResult
+	err := s.runLocked(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.syncPosts(ctx, "")
+		return err
+	})
+	return result, err
+}
+
+// SyncSingleMemo syncs only the post memoID of the source platform, e.g. the
+// memo named in a Memos webhook. The post goes through the same dedup, filter
+// and cross-post steps as in Sync, but the listing cursor is left untouched.
+// The source client must implement social.PostGetter.
+func (s *SyncService) SyncSingleMemo(ctx context.Context, memoID string) error {
+	if memoID == "" {
+		return errors.New("memo id is required")
+	}
+	return s.runLocked(ctx, func(ctx context.Context) error {
+		_, err := s.syncPosts(ctx, memoID)
+		return err
+	})
+}
+
+// runLocked runs run while holding the sync lock of the source platform,
+// with tracing and metrics. It skips run when the lock is held elsewhere.
+func (s *SyncService) runLocked(ctx context.Context, run func(ctx context.Context) error) error {
 	logger := log.FromContext(ctx)
 	lockKey := fmt.Sprintf("sync_service:%s", s.mainSocial)
 	const lockTTL = 2 * time.Minute
-	lock, err := s.locker.Obtain(ctx, lockKey, lockTTL, nil)
+	lock, err := s.locker.Obtain(ctx, lockKey, lockTTL)
 	if err != nil {
-		if errors.Is(err, redislock.ErrNotObtained) {
+		if errors.Is(err, dao.ErrLockNotObtained) {
 			logger.Info("Lock held by another instance, skip sync", "lock_key", lockKey)
 		} else {
 			logger.Error("Failed to obtain lock, skip sync", "lock_key", lockKey, "error", err)
@@ -73,7 +261,7 @@ func (s *SyncService) Sync(ctx context.Context) error {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				if err := lock.Refresh(ctx, lockTTL, nil); err != nil {
+				if err := lock.Refresh(ctx, lockTTL); err != nil {
 					logger.Warn("Failed to refresh sync lock", "lock_key", lockKey, "error", err)
 				}
 			}
@@ -86,7 +274,7 @@ func (s *SyncService) Sync(ctx context.Context) error {
 
 	return s.metrics.ActiveOperationsContext(ctx, func(ctx context.Context) error {
 		return s.metrics.TimedOperationWithContext(ctx, metrics.OperationTotal, func(ctx context.Context) error {
-			err := s.doSync(ctx)
+			err := run(ctx)
 			if err != nil {
 				s.tracer.SetSpanError(span, err, "sync_operation_failed", map[string]interface{}{
 					"main_social": s.mainSocial,
@@ -101,26 +289,77 @@ func (s *SyncService) Sync(ctx context.Context) error {
 	})
 }
 
+// doSync runs one sync round without the lock.
 func (s *SyncService) doSync(ctx context.Context) error {
+	_, err := s.syncPosts(ctx, "")
+	return err
+}
+
+// syncPosts runs one sync round: over the listed posts, or over the single
+// post postID when it is set.
+func (s *SyncService) syncPosts(ctx context.Context, postID string) (result SyncResult, err error) {
 	logger := log.FromContext(ctx)
 
+	s.applyPendingSettings()
+
+	summary := newSyncSummary(s.mainSocial, s.dryRun(ctx), s.now())
+	defer func() {
+		result.PerPlatform = summary.platformResults()
+		result.Err = err
+		s.notifySyncComplete(ctx, summary, result)
+	}()
+
 	mainSocial, err := s.socialService.GetPlatform(s.mainSocial)
 	if err != nil {
 		s.metrics.IncErrors("", metrics.ErrorTypePlatform)
-		return err
+		return result, err
 	}
 
-	batchSize := 100
-	if conf.Conf.Sync != nil && conf.Conf.Sync.BatchSize > 0 {
-		batchSize = conf.Conf.Sync.BatchSize
+	fetchLimit := s.FetchLimit
+	if fetchLimit <= 0 {
+		fetchLimit = defaultFetchLimit
+	}
+
+	// 开启 resync_on_edit 时需要重新拉取旧帖以发现修改，不使用游标；
+	// 单帖同步不代表列表已处理完，也不推进游标
+	sinceLister, useCursor := mainSocial.Client.(social.SinceLister)
+	useCursor = useCursor && !s.resyncOnEdit && postID == ""
+	if useCursor {
+		s.loadCursor(ctx)
+	}
+
+	var postGetter social.PostGetter
+	if postID != "" {
+		var ok bool
+		if postGetter, ok = mainSocial.Client.(social.PostGetter); !ok {
+			return result, fmt.Errorf("platform %s cannot fetch a single post", s.mainSocial)
+		}
 	}
 
+	logger.Info("Starting sync",
+		"main_social", s.mainSocial,
+		"post_id", postID,
+		"fetch_limit", fetchLimit,
+		"since_id", s.cursor.SinceID,
+		"since_time", s.cursor.SinceTime,
+		"skip_older_than", s.SkipOlderThan.String(),
+		"dry_run", s.dryRun(ctx))
+
 	// Fetch posts with tracing
-	ctx, fetchSpan := s.tracer.StartFetchPosts(ctx, batchSize)
+	ctx, fetchSpan := s.tracer.StartFetchPosts(ctx, fetchLimit)
 	var posts []*social.Post
 	err = s.metrics.TimedOperationWithContext(ctx, metrics.OperationFetchPosts, func(ctx context.Context) error {
 		var fetchErr error
-		posts, fetchErr = mainSocial.Client.ListPosts(ctx, batchSize)
+		if postGetter != nil {
+			var post *social.Post
+			if post, fetchErr = postGetter.GetPost(ctx, postID); post != nil {
+				posts = []*social.Post{post}
+			}
+		} else if useCursor && !s.cursor.IsZero() {
+			posts, fetchErr = sinceLister.ListPostsSince(ctx, fetchLimit, s.cursor)
+		} else {
+			posts, fetchErr = mainSocial.Client.ListPosts(ctx, fetchLimit)
+		}
 		return fetchErr
 	})
 
@@ -130,7 +369,7 @@ func (s *SyncService) doSync(ctx context.Context) error {
 		})
 		fetchSpan.End()
 		s.metrics.IncErrors("", metrics.ErrorTypePlatform)
-		return err
+		return result, err
 	}
 
 	s.tracer.SetSpanSuccess(fetchSpan, map[string]interface{}{
@@ -139,8 +378,24 @@ func (s *SyncService) doSync(ctx context.Context) error {
 	})
 	fetchSpan.End()
 
+	// 本轮所有帖子都处理完毕（没有延迟、出错或待重试的目标）才推进游标，
+	// 否则下一轮会跳过仍需处理的帖子
+	newest := newestPost(posts)
+	settled := true
+	defer func() {
+		if useCursor && settled && !s.dryRun(ctx) && newest != nil {
+			s.cursor = social.ListCursor{SinceID: newest.ID, SinceTime: newest.CreatedAt}
+			s.saveCursor(ctx)
+		}
+	}()
+
+	// 串中的回复排在父帖之后，父帖的平台 ID 先入库，回复才能挂到它下面
+	posts = orderRepliesAfterParents(posts)
+
 	// Track posts in queue
 	s.metrics.SetPostsInQueue(len(posts))
+	summary.PostsFetched = len(posts)
+	result.TotalChecked = len(posts)
 
 	// Add event to main span
 	if span := trace.SpanFromContext(ctx); span.IsRecording() {
@@ -150,21 +405,18 @@ func (s *SyncService) doSync(ctx context.Context) error {
 		})
 	}
 
-	skipOlder := time.Hour
-	if conf.Conf.Sync != nil && conf.Conf.Sync.SkipOlder > 0 {
-		skipOlder = conf.Conf.Sync.SkipOlder
-	}
-
-	maxRetries := 3
-	if conf.Conf.Sync != nil && conf.Conf.Sync.MaxRetries > 0 {
-		maxRetries = conf.Conf.Sync.MaxRetries
-	}
+	maxRetries := maxRetriesFromConfig()
+	concurrency := crossPostConcurrencyFromConfig()
 
 	// Collect posts that are too recent so we can requeue them for
 	// buffer-based clients (e.g. Telegram) where ListPosts is destructive.
 	var delayedPosts []*social.Post
 	if requeuer, ok := mainSocial.Client.(social.PostRequeuer); ok {
 		defer func() {
+			// A dry run must not consume buffered posts; hand all of them back.
+			if s.dryRun(ctx) {
+				delayedPosts = posts
+			}
 			if len(delayedPosts) > 0 {
 				requeuer.Requeue(delayedPosts)
 				logger.Info("requeued delayed posts", "count", len(delayedPosts))
@@ -179,13 +431,16 @@ func (s *SyncService) doSync(ctx context.Context) error {
 		ctx, postSpan := s.tracer.StartProcessPost(ctx, post.ID, contentPreview)
 
 		logger.Info("Processing post", "post_id", post.ID, "content", contentPreview)
+		s.reportProgress(ctx, SyncProgress{Type: SyncProgressProcessing, PostID: post.ID})
 
 		// Skip old posts
-		if post.CreatedAt.Before(time.Now().Add(-skipOlder)) {
+		now := s.now()
+		if s.SkipOlderThan > 0 && post.CreatedAt.Before(now.Add(-s.SkipOlderThan)) {
 			logger.Debug("Post is too old, skipping", "post_id", post.ID, "created_at", post.CreatedAt)
 			s.metrics.IncPostsProcessed(metrics.StatusSkippedOld)
+			s.postSkipped(ctx, &result, post.ID, "post_too_old")
 			s.tracer.SetSpanSkipped(postSpan, "post_too_old", map[string]interface{}{
-				"post_age_hours": time.Since(post.CreatedAt).Hours(),
+				"post_age_hours": now.Sub(post.CreatedAt).Hours(),
 				"created_at":     post.CreatedAt.Format(time.RFC3339),
 			})
 			postSpan.End()
@@ -196,21 +451,56 @@ func (s *SyncService) doSync(ctx context.Context) error {
 		if post.Visibility == social.VisibilityLevelDirect {
 			logger.Info("Post is direct, skipping", "post_id", post.ID)
 			s.metrics.IncPostsProcessed(metrics.StatusSkippedDirect)
+			s.postSkipped(ctx, &result, post.ID, "post_direct")
 			s.tracer.SetSpanSkipped(postSpan, "post_direct", nil)
 			postSpan.End()
 			continue
 		}
 
-		if mainSocial.Config.SyncDelay > 0 && time.Since(post.CreatedAt) < mainSocial.Config.SyncDelay {
+		if s.skipPrivate && post.Visibility == social.VisibilityLevelPrivate {
+			logger.Info("Post is private, skipping", "post_id", post.ID)
+			s.metrics.IncPostsProcessed(metrics.StatusSkippedPrivate)
+			s.postSkipped(ctx, &result, post.ID, "post_private")
+			s.tracer.SetSpanSkipped(postSpan, "post_private", nil)
+			postSpan.End()
+			continue
+		}
+
+		if ok, reason := s.Filters.Match(post); !ok {
+			logger.Info("Post filtered out, skipping", "post_id", post.ID, "reason", reason)
+			s.metrics.IncPostsProcessed(metrics.StatusSkippedFiltered)
+			s.postSkipped(ctx, &result, post.ID, "post_filtered")
+			s.tracer.SetSpanSkipped(postSpan, "post_filtered", map[string]interface{}{
+				"filter_reason": reason,
+			})
+			postSpan.End()
+			continue
+		}
+
+		if muted, pattern := s.Mutes.Muted(post); muted {
+			logger.Info("Post muted, skipping", "post_id", post.ID, "pattern", pattern)
+			s.metrics.IncPostsProcessed(metrics.StatusSkippedMuted)
+			s.metrics.IncPostsMuted()
+			s.postSkipped(ctx, &result, post.ID, "post_muted")
+			s.tracer.SetSpanSkipped(postSpan, "post_muted", map[string]interface{}{
+				"mute_pattern": pattern,
+			})
+			postSpan.End()
+			continue
+		}
+
+		if mainSocial.Config.SyncDelay > 0 && now.Sub(post.CreatedAt) < mainSocial.Config.SyncDelay {
 			logger.Info("Post too recent, delaying sync",
-				"post_id", post.ID, "age", time.Since(post.CreatedAt), "sync_delay", mainSocial.Config.SyncDelay)
+				"post_id", post.ID, "age", now.Sub(post.CreatedAt), "sync_delay", mainSocial.Config.SyncDelay)
 			s.metrics.IncPostsProcessed(metrics.StatusSkippedOld)
+			s.postSkipped(ctx, &result, post.ID, "post_too_recent")
 			s.tracer.SetSpanSkipped(postSpan, "post_too_recent", map[string]interface{}{
-				"post_age_seconds": time.Since(post.CreatedAt).Seconds(),
+				"post_age_seconds": now.Sub(post.CreatedAt).Seconds(),
 				"sync_delay":       mainSocial.Config.SyncDelay.String(),
 			})
 			postSpan.End()
 			delayedPosts = append(delayedPosts, post)
+			settled = false
 			continue
 		}
 
@@ -225,14 +515,49 @@ func (s *SyncService) doSync(ctx context.Context) error {
 			dbSpan.End()
 			s.tracer.SetSpanError(postSpan, err, "post_processing_failed", nil)
 			postSpan.End()
+			s.postFailed(ctx, &result, post.ID, err)
+			settled = false
 			continue
 		}
 		s.metrics.IncDatabaseOps(metrics.OperationGetPost, metrics.StatusSuccess)
+
+		// 部分平台会更换帖子 ID，按内容哈希兜底匹配已有记录，避免重复发帖；
+		// 只匹配创建时间相近的帖子，之后再发的相同内容是新帖
+		contentHash := dao.ContentHash(post)
+		if postModel == nil && (strings.TrimSpace(post.Content) != "" || len(post.Media) > 0) {
+			createdAt := s.postCreatedAt(post)
+			postModel, err = s.postDao.GetPostByContentHash(ctx, s.mainSocial, contentHash,
+				createdAt.Add(-contentHashMatchWindow), createdAt.Add(contentHashMatchWindow))
+			if err != nil {
+				logger.Warn("Error looking up post by content hash", "error", err, "post_id", post.ID)
+				s.metrics.IncDatabaseOps(metrics.OperationGetPost, metrics.StatusError)
+				postModel = nil
+			} else if postModel != nil {
+				logger.Info("Post matched an existing post by content hash",
+					"post_id", post.ID, "db_id", postModel.ID.Hex(), "stored_social_id", postModel.SocialID)
+			}
+		}
+
 		s.tracer.SetSpanSuccess(dbSpan, map[string]interface{}{
 			"post_exists": postModel != nil,
 		})
 		dbSpan.End()
 
+		// 多源同步时，本帖可能是其他源跨发过来的镜像，再发出去会形成循环
+		if postModel == nil {
+			if origin := s.mirrorOrigin(ctx, post, contentHash); origin != nil {
+				logger.Info("Post mirrors a post from another source, skipping",
+					"post_id", post.ID, "origin_social", origin.Social, "origin_id", origin.SocialID)
+				s.metrics.IncPostsProcessed(metrics.StatusSkippedMirror)
+				s.postSkipped(ctx, &result, post.ID, "post_mirrored")
+				s.tracer.SetSpanSkipped(postSpan, "post_mirrored", map[string]interface{}{
+					"origin_social": origin.Social,
+				})
+				postSpan.End()
+				continue
+			}
+		}
+
 		var postID string
 		if postModel != nil {
 			logger.Info("Post already exists in database", "post_id", post.ID, "db_id", postModel.ID.Hex())
@@ -242,6 +567,10 @@ func (s *SyncService) doSync(ctx context.Context) error {
 			s.tracer.AddEvent(postSpan, "post_exists", map[string]interface{}{
 				"db_id": postModel.ID.Hex(),
 			})
+
+			if postModel.ContentHash != contentHash {
+				s.refreshChangedPost(ctx, post, postModel, contentHash)
+			}
 		} else {
 			// Create new post model and save to database
 			logger.Info("Creating new post in database", "post_id", post.ID)
@@ -253,11 +582,24 @@ func (s *SyncService) doSync(ctx context.Context) error {
 			postModel.SocialID = post.ID
 			postModel.SourcePlatform = s.mainSocial
 			postModel.OriginalID = post.ID
+			postModel.SourceURL = s.withSourceURL(post).SourceURL
 			postModel.CreatedAt = post.CreatedAt
 			postModel.UpdatedAt = time.Now()
 			postModel.CrossPostStatus = make(map[string]dao.CrossPostStatus)
 
-			postID, err = s.postDao.CreatePost(ctx, postModel)
+			postID, err = s.postDao.CreatePostIfNotExists(ctx, postModel)
+			if errors.Is(err, dao.ErrPostExists) {
+				// 另一个实例或重启前中断的一轮已经入库，交给下一轮按已存在处理
+				logger.Info("Post was stored concurrently, skipping", "post_id", post.ID)
+				s.metrics.IncDatabaseOps(metrics.OperationCreatePost, metrics.StatusSuccess)
+				s.metrics.IncPostsProcessed(metrics.StatusExists)
+				s.tracer.SetSpanSkipped(createSpan, "post_exists", nil)
+				createSpan.End()
+				s.tracer.SetSpanSkipped(postSpan, "post_exists", nil)
+				postSpan.End()
+				s.postSkipped(ctx, &result, post.ID, "post_exists")
+				continue
+			}
 			if err != nil {
 				logger.Error("Error creating post in database", "error", err, "post_id", post.ID)
 				s.metrics.IncDatabaseOps(metrics.OperationCreatePost, metrics.StatusError)
@@ -266,6 +608,8 @@ func (s *SyncService) doSync(ctx context.Context) error {
 				createSpan.End()
 				s.tracer.SetSpanError(postSpan, err, "post_processing_failed", nil)
 				postSpan.End()
+				s.postFailed(ctx, &result, post.ID, err)
+				settled = false
 				continue
 			}
 			s.metrics.IncDatabaseOps(metrics.OperationCreatePost, metrics.StatusSuccess)
@@ -282,11 +626,38 @@ func (s *SyncService) doSync(ctx context.Context) error {
 			})
 		}
 
+		if s.requireAltText {
+			for _, i := range missingAltText(post) {
+				logger.Warn("Media lacks alt text", "post_id", post.ID, "media_index", i)
+			}
+		}
+
 		logger.Info("start to sync to other platforms",
 			"platforms", s.socials)
 
-		// Sync to other platforms
+		// Sync to other platforms. 各目标平台并发跨发（并发数受 cross_post_concurrency 限制），
+		// 一个平台变慢不会拖住其他平台。
+		g, gctx := errgroup.WithContext(ctx)
+		g.SetLimit(concurrency)
+		var postSynced, postFailed, postDeferred atomic.Bool
 		for _, targetSocial := range s.socials {
+			if until, cooling := s.socialService.CooldownUntil(targetSocial, s.now()); cooling {
+				logger.Info("Target platform is rate limited, skipping",
+					"post_id", post.ID, "target_platform", targetSocial, "cooldown_until", until)
+				s.reportSkipped(ctx, post.ID, targetSocial, "target_rate_limited")
+				settled = false
+				continue
+			}
+
+			if s.IsTargetPaused(targetSocial) {
+				logger.Info("Target platform is paused, skipping",
+					"post_id", post.ID, "target_platform", targetSocial)
+				s.metrics.IncCrossPosts(targetSocial, metrics.StatusSkippedPaused)
+				s.reportSkipped(ctx, post.ID, targetSocial, "target_paused")
+				settled = false
+				continue
+			}
+
 			// Check existing cross-post status
 			retryCount := 0
 			if postModel.CrossPostStatus != nil {
@@ -294,122 +665,469 @@ func (s *SyncService) doSync(ctx context.Context) error {
 					if status.Success && status.CrossPosted {
 						logger.Info("Post already synced successfully",
 							"post_id", post.ID, "target_platform", targetSocial)
+						s.reportSkipped(ctx, post.ID, targetSocial, "already_synced")
+						continue
+					}
+					// 跳过过的目标不再尝试，可用 RetryPost 手动重试
+					if status.SkipReason != "" {
+						s.reportSkipped(ctx, post.ID, targetSocial, skipReasonPrevious)
 						continue
 					}
 					// 失败重试已达上限，放弃以避免无限重试
 					if status.RetryCount >= maxRetries {
 						logger.Warn("Post cross-post retries exhausted, giving up",
 							"post_id", post.ID, "target_platform", targetSocial, "retry_count", status.RetryCount)
+						s.reportSkipped(ctx, post.ID, targetSocial, "retries_exhausted")
 						continue
 					}
 					retryCount = status.RetryCount
 				}
 			}
 
-			logger.Info("Syncing post to platform", "post_id", post.ID, "target_platform", targetSocial)
-
-			// Start cross-post span
-			ctx, crossPostSpan := s.tracer.StartCrossPost(ctx, post.ID, targetSocial)
-
-			// Get target platform
-			targetPlatform, err := s.socialService.GetPlatform(targetSocial)
-			if err != nil {
-				logger.Error("Error getting target platform", "error", err, "platform", targetSocial)
-				s.metrics.IncErrors(targetSocial, metrics.ErrorTypePlatform)
-				s.metrics.IncCrossPosts(targetSocial, metrics.StatusError)
-
-				s.tracer.SetSpanError(crossPostSpan, err, "platform_get_error", map[string]interface{}{
-					"target_platform": targetSocial,
-				})
+			// 目标平台按 visibility_map / unsupported_visibility 不接收该可见性，记为跳过而不是失败
+			if reason, skipped := s.visibilitySkipped(post, targetSocial); skipped {
+				logger.Info("Target platform skips this visibility, skipping",
+					"post_id", post.ID, "target_platform", targetSocial, "visibility", post.Visibility.String(), "reason", reason)
+				s.metrics.IncCrossPosts(targetSocial, metrics.StatusSkippedVisibility)
+				s.recordCrossPostSkipped(ctx, postID, targetSocial, reason)
+				s.reportSkipped(ctx, post.ID, targetSocial, skipReasonVisibility)
+				continue
+			}
 
-				// Update cross-post status with error
-				status := dao.CrossPostStatus{
-					Success:     false,
-					Error:       err.Error(),
-					CrossPosted: false,
-					RetryCount:  retryCount + 1,
-				}
-				if updateErr := s.postDao.UpdateCrossPostStatus(ctx, postID, targetSocial, status); updateErr != nil {
-					logger.Error("Error updating cross-post status", "error", updateErr, "post_id", postID, "platform", targetSocial)
-					s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusError)
-				} else {
-					s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusSuccess)
-				}
-				crossPostSpan.End()
+			// 熔断器打开时不再尝试，也不消耗该帖子的重试次数
+			if err := s.socialService.breaker(targetSocial).Allow(s.now()); err != nil {
+				logger.Info("Target platform circuit open, skipping",
+					"post_id", post.ID, "target_platform", targetSocial, "error", err)
+				s.reportSkipped(ctx, post.ID, targetSocial, "target_circuit_open")
+				settled = false
 				continue
 			}
 
-			// Post to target platform with timing
-			var response interface{}
-			err = s.metrics.TimedOperationWithContext(ctx, metrics.OperationSyncToPlatform, func(ctx context.Context) error {
-				var postErr error
-				response, postErr = targetPlatform.Client.Post(ctx, post)
-				return postErr
+			g.Go(func() error {
+				outcome, reason := s.crossPost(gctx, post, postID, targetSocial, retryCount)
+				if outcome == crossPostDeferred {
+					postDeferred.Store(true)
+					s.reportSkipped(ctx, post.ID, targetSocial, reason)
+					return nil
+				}
+				summary.recordCrossPost(targetSocial, outcome)
+				switch outcome {
+				case crossPostPosted:
+					postSynced.Store(true)
+				case crossPostFailed:
+					postFailed.Store(true)
+				}
+				s.crossPostDone(ctx, post.ID, targetSocial, outcome, reason)
+				return nil
 			})
+		}
+		_ = g.Wait()
+		if postFailed.Load() || postDeferred.Load() {
+			settled = false
+		}
+		if postSynced.Load() {
+			summary.PostsSynced++
+		}
+		postStatus := PostStatusSkipped
+		switch {
+		case postFailed.Load():
+			postStatus = PostStatusFailed
+		case postSynced.Load():
+			postStatus = PostStatusSynced
+		}
+		s.postProcessed(ctx, &result, PostResult{Source: s.mainSocial, PostID: post.ID, Status: postStatus})
 
-			now := time.Now()
+		// Mark post processing as complete
+		s.tracer.SetSpanSuccess(postSpan, map[string]interface{}{
+			"post_id":          post.ID,
+			"platforms_synced": len(s.socials),
+		})
+		postSpan.End()
+	}
 
-			if err != nil {
-				logger.Error("Error posting to platform", "error", err, "post_id", post.ID, "target_platform", targetSocial)
-				s.metrics.IncErrors(targetSocial, metrics.ErrorTypePlatform)
-				s.metrics.IncCrossPosts(targetSocial, metrics.StatusError)
+	return result, nil
+}
 
-				s.tracer.SetSpanError(crossPostSpan, err, "cross_post_failed", map[string]interface{}{
-					"target_platform": targetSocial,
-				})
+// newestPost returns the post with the latest CreatedAt, or nil for an
+// empty list.
+func newestPost(posts []*social.Post) *social.Post {
+	var newest *social.Post
+	for _, post := range posts {
+		if newest == nil || post.CreatedAt.After(newest.CreatedAt) {
+			newest = post
+		}
+	}
+	return newest
+}
 
-				// Update cross-post status with error
-				status := dao.CrossPostStatus{
-					Success:     false,
-					Error:       err.Error(),
-					CrossPosted: false,
-					PostedAt:    &now,
-					RetryCount:  retryCount + 1,
-				}
-				if updateErr := s.postDao.UpdateCrossPostStatus(ctx, postID, targetSocial, status); updateErr != nil {
-					logger.Error("Error updating cross-post status", "error", updateErr, "post_id", postID, "platform", targetSocial)
-					s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusError)
-				} else {
-					s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusSuccess)
-				}
-			} else {
-				logger.Info("Successfully posted to platform", "post_id", post.ID, "target_platform", targetSocial, "response", response)
-				s.metrics.IncCrossPosts(targetSocial, metrics.StatusSuccess)
+// refreshChangedPost stores the new content of a post whose hash no longer
+// matches the stored one. A post stored before hashes existed just gets its
+// hash backfilled; a real edit is counted as an update and, when
+// resyncOnEdit is enabled, pushed to every target that supports editing.
+func (s *SyncService) refreshChangedPost(ctx context.Context, post *social.Post, postModel *dao.PostModel, contentHash string) {
+	logger := log.FromContext(ctx)
+	edited := postModel.ContentHash != ""
 
-				// Extract platform ID from response if available
-				platformID := extractPlatformID(response)
+	updated := dao.FromSocialPost(post)
+	postModel.Content = updated.Content
+	postModel.Visibility = updated.Visibility
+	postModel.Media = updated.Media
+	postModel.ContentHash = contentHash
 
-				s.tracer.SetSpanSuccess(crossPostSpan, map[string]interface{}{
-					"target_platform": targetSocial,
-					"platform_id":     platformID,
-				})
+	if err := s.postDao.UpdatePost(ctx, postModel); err != nil {
+		logger.Error("Error updating post in database", "error", err, "post_id", post.ID)
+		s.metrics.IncDatabaseOps(metrics.OperationUpdatePost, metrics.StatusError)
+		s.metrics.IncErrors("", metrics.ErrorTypeDatabase)
+		return
+	}
+	s.metrics.IncDatabaseOps(metrics.OperationUpdatePost, metrics.StatusSuccess)
 
-				// Update cross-post status with success
-				status := dao.CrossPostStatus{
-					Success:     true,
-					PlatformID:  platformID,
-					CrossPosted: true,
-					PostedAt:    &now,
-				}
-				if updateErr := s.postDao.UpdateCrossPostStatus(ctx, postID, targetSocial, status); updateErr != nil {
-					logger.Error("Error updating cross-post status", "error", updateErr, "post_id", postID, "platform", targetSocial)
-					s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusError)
-				} else {
-					s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusSuccess)
-				}
+	if !edited {
+		return
+	}
+
+	logger.Info("Post content changed since last sync", "post_id", post.ID, "db_id", postModel.ID.Hex(), "resync", s.resyncOnEdit)
+	s.metrics.IncPostsProcessed(metrics.StatusUpdated)
+	if !s.resyncOnEdit || s.dryRun(ctx) {
+		return
+	}
+
+	for _, targetSocial := range s.socials {
+		status, ok := postModel.CrossPostStatus[targetSocial]
+		if !ok || !status.Success || !status.CrossPosted || status.PlatformID == "" {
+			continue
+		}
+		targetPlatform, err := s.socialService.GetPlatform(targetSocial)
+		if err != nil {
+			continue
+		}
+		updater, ok := targetPlatform.Client.(social.SocialUpdater)
+		if !ok {
+			logger.Info("Target platform does not support editing, skipping re-sync",
+				"post_id", post.ID, "target_platform", targetSocial)
+			continue
+		}
+		out, err := targetPlatform.Transform(s.withSourceURL(post))
+		if err != nil {
+			logger.Error("Error transforming edited post content", "error", err, "post_id", post.ID, "target_platform", targetSocial)
+			s.metrics.IncErrors(targetSocial, metrics.ErrorTypeGeneral)
+			continue
+		}
+		if err := updater.Update(ctx, status.PlatformID, out); err != nil {
+			logger.Error("Error re-syncing edited post", "error", err, "post_id", post.ID, "target_platform", targetSocial)
+			s.metrics.IncErrors(targetSocial, metrics.ErrorTypePlatform)
+			continue
+		}
+		logger.Info("Re-synced edited post", "post_id", post.ID, "target_platform", targetSocial, "platform_id", status.PlatformID)
+	}
+}
+
+// notifySyncComplete reports the run's summary as the last progress event,
+// tells the observers and hands it to the outgoing webhooks in the background, so a slow
+// receiver never holds the sync lock.
+func (s *SyncService) notifySyncComplete(ctx context.Context, summary *SyncSummary, result SyncResult) {
+	summary.FinishedAt = s.now()
+	if result.Err != nil {
+		summary.Error = result.Err.Error()
+	}
+	s.reportProgress(ctx, SyncProgress{Type: SyncProgressSummary, Summary: summary})
+	s.notifyObservers(func(o SyncObserver) {
+		o.OnSyncComplete(ctx, result)
+	})
+
+	if s.webhook == nil {
+		return
+	}
+	go func() {
+		_ = s.webhook.Notify(context.WithoutCancel(ctx), summary)
+	}()
+}
+
+// crossPostOutcome is how cross-posting one post to one target ended.
+type crossPostOutcome int
+
+const (
+	crossPostFailed crossPostOutcome = iota
+	// crossPostPosted includes dry runs
+	crossPostPosted
+	// crossPostSkipped means the post was deliberately not published, see
+	// social.PostSkipped; it is neither a success nor a failure
+	crossPostSkipped
+	// crossPostDeferred means a skip mode post_rate_limit had no token left:
+	// nothing was attempted or recorded, the post is left for the next cycle
+	crossPostDeferred
+)
+
+// crossPost publishes post to targetSocial and records the outcome in the
+// post's CrossPostStatus. Failures are recorded rather than returned, so one
+// target never cancels the others. A skipped outcome comes with the reason
+// code reported to progress listeners and observers, as does a deferred one.
+func (s *SyncService) crossPost(ctx context.Context, post *social.Post, postID, targetSocial string, retryCount int) (crossPostOutcome, string) {
+	logger := log.FromContext(ctx)
+
+	logger.Info("Syncing post to platform", "post_id", post.ID, "target_platform", targetSocial)
+
+	// Start cross-post span
+	ctx, crossPostSpan := s.tracer.StartCrossPost(ctx, post.ID, targetSocial)
+	defer crossPostSpan.End()
+
+	// Get target platform
+	targetPlatform, err := s.socialService.GetPlatform(targetSocial)
+	if err != nil {
+		logger.Error("Error getting target platform", "error", err, "platform", targetSocial)
+		s.metrics.IncErrors(targetSocial, metrics.ErrorTypePlatform)
+		s.metrics.IncCrossPosts(targetSocial, metrics.StatusError)
+
+		s.tracer.SetSpanError(crossPostSpan, err, "platform_get_error", map[string]interface{}{
+			"target_platform": targetSocial,
+		})
+
+		// Update cross-post status with error
+		status := dao.CrossPostStatus{
+			Success:     false,
+			Error:       err.Error(),
+			CrossPosted: false,
+			RetryCount:  retryCount + 1,
+		}
+		if updateErr := s.postDao.UpdateCrossPostStatus(ctx, postID, targetSocial, status); updateErr != nil {
+			logger.Error("Error updating cross-post status", "error", updateErr, "post_id", postID, "platform", targetSocial)
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusError)
+		} else {
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusSuccess)
+		}
+		return crossPostFailed, ""
+	}
+
+	// Clean up source-specific syntax, then apply the target's content
+	// template, footer and visibility settings
+	post, err = targetPlatform.Transform(s.withSourceURL(s.normalizeContent(ctx, post, targetSocial)))
+	if reason, skipped := social.PostSkipped(post, err); skipped {
+		return crossPostSkipped, s.crossPostSkipped(ctx, crossPostSpan, postID, targetSocial, reason, err)
+	}
+	if err != nil {
+		logger.Error("Error transforming post content", "error", err, "post_id", postID, "platform", targetSocial)
+		s.metrics.IncErrors(targetSocial, metrics.ErrorTypeGeneral)
+		s.metrics.IncCrossPosts(targetSocial, metrics.StatusError)
+
+		s.tracer.SetSpanError(crossPostSpan, err, "content_transform_error", map[string]interface{}{
+			"target_platform": targetSocial,
+		})
+
+		status := dao.CrossPostStatus{
+			Success:     false,
+			Error:       err.Error(),
+			CrossPosted: false,
+			RetryCount:  retryCount + 1,
+		}
+		if updateErr := s.postDao.UpdateCrossPostStatus(ctx, postID, targetSocial, status); updateErr != nil {
+			logger.Error("Error updating cross-post status", "error", updateErr, "post_id", postID, "platform", targetSocial)
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusError)
+		} else {
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusSuccess)
+		}
+		return crossPostFailed, ""
+	}
+
+	post = s.withReplyTarget(ctx, post, targetSocial)
+
+	if s.dryRun(ctx) {
+		logger.Info("Dry run: would post to platform",
+			"post_id", post.ID,
+			"target_platform", targetSocial,
+			"client", targetPlatform.Client.Name(),
+			"content", post.Content,
+			"media_count", len(post.Media),
+			"visibility", post.Visibility.String())
+		s.metrics.IncCrossPosts(targetSocial, metrics.StatusDryRun)
+		s.tracer.SetSpanSuccess(crossPostSpan, map[string]interface{}{
+			"target_platform": targetSocial,
+			"dry_run":         true,
+		})
+
+		now := time.Now()
+		status := dao.CrossPostStatus{
+			Success:    true,
+			DryRun:     true,
+			PostedAt:   &now,
+			RetryCount: retryCount,
+		}
+		if updateErr := s.postDao.UpdateCrossPostStatus(ctx, postID, targetSocial, status); updateErr != nil {
+			logger.Error("Error updating cross-post status", "error", updateErr, "post_id", postID, "platform", targetSocial)
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusError)
+		} else {
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusSuccess)
+		}
+		return crossPostPosted, ""
+	}
+
+	// post_rate_limit 为 skip 模式且令牌用完时，帖子留到下一轮再发。
+	// 放在跳过与转换检查之后，不发的帖子不消耗令牌
+	if !s.socialService.allowPost(targetSocial) {
+		logger.Info("Target platform post rate exceeded, skipping until next cycle",
+			"post_id", post.ID, "target_platform", targetSocial)
+		s.tracer.SetSpanSkipped(crossPostSpan, "target_post_rate_limited", map[string]interface{}{
+			"target_platform": targetSocial,
+		})
+		return crossPostDeferred, "target_post_rate_limited"
+	}
+
+	// Post to target platform with timing
+	var response interface{}
+	err = s.metrics.TimedOperationWithContext(ctx, metrics.OperationSyncToPlatform, func(ctx context.Context) error {
+		attempt := 0
+		return retryWithBackoff(ctx, s.postAttempts, s.postRetryDelay, func() error {
+			attempt++
+			// post_rate_limit 为 wait 模式时等到有令牌再发
+			if err := s.socialService.waitPost(ctx, targetSocial); err != nil {
+				return err
+			}
+			var postErr error
+			response, postErr = targetPlatform.Client.Post(ctx, post)
+			if postErr != nil && attempt < s.postAttempts && IsRetryable(postErr) {
+				logger.Warn("Transient error posting to platform, retrying",
+					"error", postErr, "post_id", post.ID, "target_platform", targetSocial, "attempt", attempt)
+			}
+			return postErr
+		})
+	})
+
+	now := time.Now()
+
+	// 只发出了一部分：再发会重复已发出的部分，按已发布处理
+	if partial, ok := social.AsPartialPostError(err); ok {
+		logger.Warn("Post only partially published to platform, not retrying",
+			"error", err, "post_id", post.ID, "target_platform", targetSocial)
+		response, err = partial.Result, nil
+	}
+
+	// 客户端不支持该可见性或没有发帖：重试也不会成功，记为跳过而不是成功；也不计入熔断
+	if reason, skipped := social.PostSkipped(response, err); skipped {
+		return crossPostSkipped, s.crossPostSkipped(ctx, crossPostSpan, postID, targetSocial, reason, err)
+	}
+
+	// 限流由冷却期处理，不计入熔断
+	if _, limited := social.RateLimitRetryAfter(err); !limited {
+		s.socialService.breaker(targetSocial).Record(s.now(), err)
+	}
+
+	if err != nil {
+		logger.Error("Error posting to platform", "error", err, "post_id", post.ID, "target_platform", targetSocial)
+		s.metrics.IncErrors(targetSocial, metrics.ErrorTypePlatform)
+		s.metrics.IncCrossPosts(targetSocial, metrics.StatusError)
+
+		s.tracer.SetSpanError(crossPostSpan, err, "cross_post_failed", map[string]interface{}{
+			"target_platform": targetSocial,
+		})
+
+		nextRetryCount := retryCount + 1
+		if retryAfter, limited := social.RateLimitRetryAfter(err); limited {
+			if retryAfter <= 0 {
+				retryAfter = defaultRateLimitCooldown
 			}
-			crossPostSpan.End()
+			until := s.now().Add(retryAfter)
+			s.socialService.SetCooldown(targetSocial, until)
+			logger.Warn("Target platform rate limited, cooling down",
+				"target_platform", targetSocial, "retry_after", retryAfter, "cooldown_until", until)
+			// 限流与帖子本身无关，不计入重试次数
+			nextRetryCount = retryCount
 		}
 
-		// Mark post processing as complete
-		s.tracer.SetSpanSuccess(postSpan, map[string]interface{}{
-			"post_id":          post.ID,
-			"platforms_synced": len(s.socials),
+		// Update cross-post status with error
+		status := dao.CrossPostStatus{
+			Success:     false,
+			Error:       err.Error(),
+			CrossPosted: false,
+			PostedAt:    &now,
+			RetryCount:  nextRetryCount,
+		}
+		if updateErr := s.postDao.UpdateCrossPostStatus(ctx, postID, targetSocial, status); updateErr != nil {
+			logger.Error("Error updating cross-post status", "error", updateErr, "post_id", postID, "platform", targetSocial)
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusError)
+		} else {
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusSuccess)
+		}
+	} else {
+		logger.Info("Successfully posted to platform", "post_id", post.ID, "target_platform", targetSocial, "response", response)
+		s.metrics.IncCrossPosts(targetSocial, metrics.StatusSuccess)
+
+		// Extract platform ID from response if available
+		platformID := extractPlatformID(response)
+
+		s.tracer.SetSpanSuccess(crossPostSpan, map[string]interface{}{
+			"target_platform": targetSocial,
+			"platform_id":     platformID,
 		})
-		postSpan.End()
+
+		// Update cross-post status with success
+		status := dao.CrossPostStatus{
+			Success:     true,
+			PlatformID:  platformID,
+			CrossPosted: true,
+			PostedAt:    &now,
+		}
+		if updateErr := s.postDao.UpdateCrossPostStatus(ctx, postID, targetSocial, status); updateErr != nil {
+			logger.Error("Error updating cross-post status", "error", updateErr, "post_id", postID, "platform", targetSocial)
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusError)
+		} else {
+			s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusSuccess)
+		}
+	}
+	if err != nil {
+		return crossPostFailed, ""
+	}
+	return crossPostPosted, ""
+}
+
+// normalizeContent returns post with its content normalized for
+// targetSocial, copying it when that changes anything.
+func (s *SyncService) normalizeContent(ctx context.Context, post *social.Post, targetSocial string) *social.Post {
+	content := s.socialService.NormalizeContent(ctx, post.Content, targetSocial)
+	if content == post.Content {
+		return post
 	}
+	out := *post
+	out.Content = content
+	return &out
+}
 
-	return nil
+// postCreatedAt returns when post was created on its source, or now when the
+// source did not report it.
+func (s *SyncService) postCreatedAt(post *social.Post) time.Time {
+	if post.CreatedAt.IsZero() {
+		return s.now()
+	}
+	return post.CreatedAt
+}
+
+// withSourceURL returns post with SourceURL resolved from its source
+// platform when it has none (sources that do not set it while listing, or
+// posts stored before source URLs were recorded).
+func (s *SyncService) withSourceURL(post *social.Post) *social.Post {
+	if post.SourceURL != "" {
+		return post
+	}
+	source, err := s.socialService.GetPlatform(post.SourcePlatform)
+	if err != nil {
+		return post
+	}
+	resolver, ok := source.Client.(social.SourceURLResolver)
+	if !ok {
+		return post
+	}
+	out := *post
+	out.SourceURL = resolver.SourceURL(post)
+	return &out
+}
+
+// missingAltText returns the indexes of post's media without alt text.
+func missingAltText(post *social.Post) []int {
+	var missing []int
+	for i := range post.Media {
+		if strings.TrimSpace(post.Media[i].Description) == "" {
+			missing = append(missing, i)
+		}
+	}
+	return missing
 }
 
 // preview returns a rune-safe content preview.
@@ -427,7 +1145,8 @@ func extractPlatformID(response interface{}) string {
 		return ""
 	}
 
-	if respMap, ok := response.(map[string]interface{}); ok {
+	switch respMap := response.(type) {
+	case map[string]interface{}:
 		for _, key := range []string{"id", "uri", "rkey", "cid"} {
 			if value, exists := respMap[key]; exists {
 				if id := stringifyPlatformID(value); id != "" {
@@ -435,6 +1154,8 @@ func extractPlatformID(response interface{}) string {
 				}
 			}
 		}
+	case map[string]string: // Telegram、Nostr、Discord、Matrix、WordPress、Micro.blog、Memos
+		return respMap["id"]
 	}
 
 	value := reflect.Indirect(reflect.ValueOf(response))
@@ -460,7 +1181,10 @@ func stringifyPlatformID(value interface{}) string {
 		return v
 	case fmt.Stringer:
 		return v.String()
-	default:
-		return ""
 	}
+	// 命名的字符串类型，如 mastodon.ID
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.String {
+		return rv.String()
+	}
+	return ""
 }
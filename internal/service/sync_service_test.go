@@ -0,0 +1,825 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/metrics"
+	"go.orx.me/apps/hyper-sync/internal/social"
+	"go.orx.me/apps/hyper-sync/internal/telemetry"
+)
+
+// fakeSyncClient serves a fixed list of posts and records what is posted to it.
+type fakeSyncClient struct {
+	name  string
+	posts []*social.Post
+	delay time.Duration
+	// postErr, if set, fails every Post.
+	postErr error
+	// noPost makes Post return neither a result nor an error.
+	noPost bool
+
+	mu      sync.Mutex
+	calls   int // Post calls, failed ones included
+	posted  []*social.Post
+	updated []string // platform IDs passed to Update
+}
+
+func (f *fakeSyncClient) Update(_ context.Context, platformID string, _ *social.Post) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.updated = append(f.updated, platformID)
+	return nil
+}
+
+func (f *fakeSyncClient) updatedIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.updated...)
+}
+
+func (f *fakeSyncClient) Post(_ context.Context, p *social.Post) (interface{}, error) {
+	time.Sleep(f.delay)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.postErr != nil {
+		return nil, f.postErr
+	}
+	f.posted = append(f.posted, p)
+	if f.noPost {
+		return nil, nil
+	}
+	return map[string]interface{}{"id": f.name + "-" + p.ID}, nil
+}
+
+func (f *fakeSyncClient) ListPosts(_ context.Context, _ int) ([]*social.Post, error) {
+	return f.posts, nil
+}
+
+func (f *fakeSyncClient) Name() string { return f.name }
+
+func (f *fakeSyncClient) postedIDs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ids := make([]string, 0, len(f.posted))
+	for _, p := range f.posted {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+// memoryPostDao is an in-memory dao.PostDao covering what SyncService uses.
+type memoryPostDao struct {
+	mu    sync.Mutex
+	posts map[string]*dao.PostModel
+}
+
+func newMemoryPostDao() *memoryPostDao {
+	return &memoryPostDao{posts: make(map[string]*dao.PostModel)}
+}
+
+func (d *memoryPostDao) GetPostByID(_ context.Context, id string) (*dao.PostModel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.posts[id], nil
+}
+
+func (d *memoryPostDao) GetPostByOriginalID(_ context.Context, platform, originalID string) (*dao.PostModel, error) {
+	return nil, nil
+}
+
+func (d *memoryPostDao) GetBySocialAndSocialID(_ context.Context, socialName, socialID string) (*dao.PostModel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range d.posts {
+		if p.Social == socialName && p.SocialID == socialID {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *memoryPostDao) GetPostByContentHash(_ context.Context, socialName, contentHash string, from, to time.Time) (*dao.PostModel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range d.posts {
+		if p.Social == socialName && p.ContentHash == contentHash && !p.CreatedAt.Before(from) && !p.CreatedAt.After(to) {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *memoryPostDao) GetByCrossPostPlatformID(_ context.Context, platform, platformID string) (*dao.PostModel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range d.posts {
+		if status, ok := p.CrossPostStatus[platform]; ok && status.PlatformID == platformID {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (d *memoryPostDao) ListPosts(_ context.Context, _ map[string]interface{}, _ int64, _ int64) ([]*dao.PostModel, error) {
+	return nil, nil
+}
+
+func (d *memoryPostDao) CreatePost(_ context.Context, p *dao.PostModel) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p.ID = bson.NewObjectID()
+	d.posts[p.ID.Hex()] = p
+	return p.ID.Hex(), nil
+}
+
+func (d *memoryPostDao) CreatePostIfNotExists(ctx context.Context, p *dao.PostModel) (string, error) {
+	d.mu.Lock()
+	for _, existing := range d.posts {
+		if existing.Social == p.Social && existing.SocialID == p.SocialID {
+			d.mu.Unlock()
+			return "", dao.ErrPostExists
+		}
+	}
+	d.mu.Unlock()
+	return d.CreatePost(ctx, p)
+}
+
+func (d *memoryPostDao) UpdatePost(_ context.Context, p *dao.PostModel) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.posts[p.ID.Hex()] = p
+	return nil
+}
+
+func (d *memoryPostDao) DeletePost(_ context.Context, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.posts, id)
+	return nil
+}
+
+func (d *memoryPostDao) UpdateCrossPostStatus(_ context.Context, postID, platform string, status dao.CrossPostStatus) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if p, ok := d.posts[postID]; ok {
+		p.CrossPostStatus[platform] = status
+	}
+	return nil
+}
+
+func newTestSyncService(postDao dao.PostDao, source social.SocialClient, targets ...social.SocialClient) *SyncService {
+	platforms := map[string]*social.SocialPlatform{
+		source.Name(): {Name: source.Name(), Client: source, Config: &social.PlatformConfig{}},
+	}
+	var names []string
+	for _, target := range targets {
+		platforms[target.Name()] = &social.SocialPlatform{Name: target.Name(), Client: target, Config: &social.PlatformConfig{}}
+		names = append(names, target.Name())
+	}
+
+	return &SyncService{
+		socialService: &SocialService{platforms: platforms},
+		postDao:       postDao,
+		metrics:       metrics.NewSyncMetrics(source.Name()),
+		tracer:        telemetry.NewSyncTracer(source.Name()),
+		mainSocial:    source.Name(),
+		socials:       names,
+		SkipOlderThan: defaultSkipOlderThan,
+		postAttempts:  1,
+		now:           time.Now,
+	}
+}
+
+func TestSyncService_SkipsWhileLockHeld(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	locker := dao.NewMemoryLocker()
+	s.locker = locker
+
+	ctx := context.Background()
+	held, err := locker.Obtain(ctx, "sync_service:memos", time.Minute)
+	require.NoError(t, err)
+
+	result, err := s.Sync(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, target.postedIDs(), "a sync must not run while another holds the lock")
+	assert.Zero(t, result.TotalChecked)
+
+	require.NoError(t, held.Release(ctx))
+	result, err = s.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, target.postedIDs())
+	assert.Equal(t, 1, result.Synced)
+}
+
+func TestSyncService_SyncReturnsResult(t *testing.T) {
+	existing := &social.Post{ID: "existing", Content: "already synced", CreatedAt: time.Now()}
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{existing}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	s.locker = dao.NewMemoryLocker()
+	s.skipPrivate = true
+
+	ctx := context.Background()
+	_, err := s.Sync(ctx)
+	require.NoError(t, err)
+
+	source.posts = []*social.Post{
+		existing,
+		{ID: "new", Content: "new post", CreatedAt: time.Now()},
+		{ID: "private", Content: "followers only", CreatedAt: time.Now(), Visibility: social.VisibilityLevelPrivate},
+	}
+	result, err := s.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.TotalChecked)
+	assert.Equal(t, 1, result.Synced, "new")
+	assert.Equal(t, 2, result.Skipped, "existing and private")
+	assert.Equal(t, 0, result.Failed)
+	assert.NoError(t, result.Err)
+	require.Contains(t, result.PerPlatform, "mastodon")
+	assert.Equal(t, PlatformSyncResult{Success: 1}, *result.PerPlatform["mastodon"])
+	assert.Equal(t, []string{"existing", "new"}, target.postedIDs())
+}
+
+func TestSyncService_SkipOlderThan(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	posts := []*social.Post{
+		{ID: "fresh", Content: "fresh", CreatedAt: now.Add(-10 * time.Minute)},
+		{ID: "two-hours", Content: "two hours", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "two-days", Content: "two days", CreatedAt: now.Add(-48 * time.Hour)},
+	}
+
+	tests := []struct {
+		name          string
+		skipOlderThan time.Duration
+		want          []string
+	}{
+		{"default one hour", time.Hour, []string{"fresh"}},
+		{"wider window", 24 * time.Hour, []string{"fresh", "two-hours"}},
+		{"no limit", 0, []string{"fresh", "two-hours", "two-days"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &fakeSyncClient{name: "memos", posts: posts}
+			target := &fakeSyncClient{name: "mastodon"}
+			s := newTestSyncService(newMemoryPostDao(), source, target)
+			s.SkipOlderThan = tt.skipOlderThan
+			s.now = func() time.Time { return now }
+
+			require.NoError(t, s.doSync(context.Background()))
+			assert.Equal(t, tt.want, target.postedIDs())
+		})
+	}
+}
+
+func TestSyncService_CrossPostsTargetsInParallel(t *testing.T) {
+	const delay = 200 * time.Millisecond
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}
+	targets := []social.SocialClient{
+		&fakeSyncClient{name: "mastodon", delay: delay},
+		&fakeSyncClient{name: "bluesky", delay: delay},
+		&fakeSyncClient{name: "threads", delay: delay},
+	}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, targets...)
+
+	start := time.Now()
+	require.NoError(t, s.doSync(context.Background()))
+	elapsed := time.Since(start)
+
+	assert.Less(t, elapsed, 2*delay, "targets should be posted to concurrently, not one after another")
+	for _, target := range targets {
+		assert.Equal(t, []string{"1"}, target.(*fakeSyncClient).postedIDs())
+	}
+
+	model, err := postDao.GetBySocialAndSocialID(context.Background(), "memos", "1")
+	require.NoError(t, err)
+	require.Len(t, model.CrossPostStatus, 3)
+	for name, status := range model.CrossPostStatus {
+		assert.True(t, status.Success, name)
+		assert.Equal(t, name+"-1", status.PlatformID)
+	}
+}
+
+func TestSyncService_SkipsAlreadySyncedTargets(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, mastodon, bluesky)
+
+	_, err := postDao.CreatePost(context.Background(), &dao.PostModel{
+		Social:   "memos",
+		SocialID: "1",
+		CrossPostStatus: map[string]dao.CrossPostStatus{
+			"mastodon": {Success: true, CrossPosted: true, PlatformID: "m-1"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Empty(t, mastodon.postedIDs())
+	assert.Equal(t, []string{"1"}, bluesky.postedIDs())
+}
+
+func TestSyncService_DryRunDoesNotPost(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, target)
+	s.DryRun = true
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Empty(t, target.postedIDs(), "dry run must not reach the target client")
+
+	model, err := postDao.GetBySocialAndSocialID(context.Background(), "memos", "1")
+	require.NoError(t, err)
+	status := model.CrossPostStatus["mastodon"]
+	assert.True(t, status.DryRun)
+	assert.True(t, status.Success)
+	assert.False(t, status.CrossPosted, "a dry run must not count as posted so a real sync still posts")
+
+	// A real sync afterwards still posts.
+	s.DryRun = false
+	require.NoError(t, s.doSync(context.Background()))
+	assert.Equal(t, []string{"1"}, target.postedIDs())
+}
+
+func TestSyncService_UnchangedContentIsNotResynced(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	s.resyncOnEdit = true
+
+	require.NoError(t, s.doSync(context.Background()))
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, []string{"1"}, target.postedIDs())
+	assert.Empty(t, target.updatedIDs())
+}
+
+func TestSyncService_EditedContentIsResynced(t *testing.T) {
+	post := &social.Post{ID: "1", Content: "hello", CreatedAt: time.Now()}
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{post}}
+	target := &fakeSyncClient{name: "mastodon"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, target)
+	s.resyncOnEdit = true
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	post.Content = "hello, edited"
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, []string{"1"}, target.postedIDs(), "an edit must not be posted as a new post")
+	assert.Equal(t, []string{"mastodon-1"}, target.updatedIDs())
+
+	model, err := postDao.GetBySocialAndSocialID(context.Background(), "memos", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "hello, edited", model.Content)
+	assert.Equal(t, dao.ContentHash(post), model.ContentHash)
+}
+
+func TestSyncService_ChangedIDMatchedByContentHash(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "old-id", Content: "same text", CreatedAt: time.Now()},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	source.posts = []*social.Post{{ID: "new-id", Content: "same text", CreatedAt: time.Now()}}
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, []string{"old-id"}, target.postedIDs())
+}
+
+func TestSyncService_RepeatedContentIsANewPost(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "good morning", CreatedAt: time.Now().Add(-24 * time.Hour)},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	// 第一条已经超过默认的 SkipOlderThan
+	s.SkipOlderThan = 0
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	source.posts = []*social.Post{{ID: "2", Content: "good morning", CreatedAt: time.Now()}}
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, []string{"1", "2"}, target.postedIDs(), "a new memo with an older memo's text must be synced")
+}
+
+// racingPostDao misses every lookup, as when another instance inserts the
+// post between doSync's lookup and its insert.
+type racingPostDao struct {
+	*memoryPostDao
+}
+
+func (d racingPostDao) GetBySocialAndSocialID(context.Context, string, string) (*dao.PostModel, error) {
+	return nil, nil
+}
+
+func (d racingPostDao) GetPostByContentHash(context.Context, string, string, time.Time, time.Time) (*dao.PostModel, error) {
+	return nil, nil
+}
+
+func TestSyncService_ConcurrentlyStoredPostIsSkipped(t *testing.T) {
+	post := &social.Post{ID: "1", Content: "hello", CreatedAt: time.Now()}
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{post}}
+	target := &fakeSyncClient{name: "mastodon"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(racingPostDao{postDao}, source, target)
+
+	stored := dao.FromSocialPost(post)
+	stored.Social, stored.SocialID, stored.SourcePlatform = "memos", "1", "memos"
+	_, err := postDao.CreatePost(context.Background(), stored)
+	require.NoError(t, err)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Empty(t, target.postedIDs(), "the instance that lost the insert must not cross-post")
+	assert.Len(t, postDao.posts, 1)
+}
+
+func TestSyncService_AppliesTargetTemplate(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", SourceURL: "https://memos.example.com/m/1", CreatedAt: time.Now()},
+	}}
+	telegram := &fakeSyncClient{name: "telegram"}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, telegram, mastodon)
+
+	tr, err := social.NewTemplateTransformer("telegram", "{{.Content}}\n\n🔗 {{.SourceURL}}")
+	require.NoError(t, err)
+	s.socialService.platforms["telegram"].Transformer = tr
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	require.Len(t, telegram.posted, 1)
+	assert.Equal(t, "hello\n\n🔗 https://memos.example.com/m/1", telegram.posted[0].Content)
+	require.Len(t, mastodon.posted, 1)
+	assert.Equal(t, "hello", mastodon.posted[0].Content, "platforms without a template get the original content")
+	assert.Equal(t, "hello", source.posts[0].Content, "the source post must not be modified")
+}
+
+func TestSyncService_NormalizesContentPerTarget(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "Plans #life\n- [ ] pack\n- [x] book tickets", CreatedAt: time.Now()},
+	}}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky"}
+	s := newTestSyncService(newMemoryPostDao(), source, mastodon, bluesky)
+	s.socialService.platforms["mastodon"].Config.Normalize = []string{social.NormalizeStripHashtags, social.NormalizeTaskLists}
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	require.Len(t, mastodon.posted, 1)
+	assert.Equal(t, "Plans\n- pack\n- ✓ book tickets", mastodon.posted[0].Content)
+	require.Len(t, bluesky.posted, 1)
+	assert.Equal(t, source.posts[0].Content, bluesky.posted[0].Content, "targets without rules get the original content")
+	assert.Equal(t, "Plans #life\n- [ ] pack\n- [x] book tickets", source.posts[0].Content, "the source post must not be modified")
+}
+
+// resolvingSyncClient is a source that can build permalinks from OriginalID.
+type resolvingSyncClient struct {
+	*fakeSyncClient
+}
+
+func (c resolvingSyncClient) SourceURL(post *social.Post) string {
+	return "https://memos.example.com/m/" + post.OriginalID
+}
+
+func TestSyncService_FooterResolvesSourceURL(t *testing.T) {
+	source := resolvingSyncClient{&fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", SourcePlatform: "memos", OriginalID: "abc", CreatedAt: time.Now()},
+	}}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+
+	tr, err := social.NewFooterTransformer("mastodon", "mastodon", "via my blog ({{.SourceURL}})", nil)
+	require.NoError(t, err)
+	s.socialService.platforms["mastodon"].Transformer = tr
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	require.Len(t, target.posted, 1)
+	assert.Equal(t, "hello\n\nvia my blog (https://memos.example.com/m/abc)", target.posted[0].Content)
+}
+
+func TestSyncService_RepliesThreadOnTarget(t *testing.T) {
+	now := time.Now()
+	// Newest first, as sources list them: the reply precedes its parent.
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "memos/2", Content: "second", InReplyTo: "memos/1", CreatedAt: now},
+		{ID: "memos/1", Content: "first", CreatedAt: now.Add(-time.Minute)},
+	}}
+	target := &fakeSyncClient{name: "bluesky"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	require.Equal(t, []string{"memos/1", "memos/2"}, target.postedIDs(), "the parent should be posted first")
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	assert.Empty(t, target.posted[0].InReplyTo)
+	assert.Equal(t, "bluesky-memos/1", target.posted[1].InReplyTo, "the reply should reference the parent's ID on the target")
+	assert.Equal(t, "memos/1", source.posts[0].InReplyTo, "the source post must not be modified")
+}
+
+func TestSyncService_ReplyToUnsyncedParentPostsStandalone(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "memos/2", Content: "second", InReplyTo: "memos/unknown", CreatedAt: time.Now()},
+	}}
+	target := &fakeSyncClient{name: "bluesky"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	require.Len(t, target.posted, 1)
+	assert.Empty(t, target.posted[0].InReplyTo, "a source ID must never reach the target")
+}
+
+func TestSyncService_RateLimitedTargetCoolsDown(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky", postErr: &social.RateLimitError{
+		RetryAfter: 10 * time.Minute,
+		Err:        &social.StatusError{Op: "post", StatusCode: http.StatusTooManyRequests},
+	}}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, mastodon, bluesky)
+	s.SkipOlderThan = 0
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	until, cooling := s.socialService.CooldownUntil("bluesky", now)
+	require.True(t, cooling)
+	assert.Equal(t, now.Add(10*time.Minute), until)
+	model, err := postDao.GetBySocialAndSocialID(context.Background(), "memos", "1")
+	require.NoError(t, err)
+	assert.False(t, model.CrossPostStatus["bluesky"].Success)
+	assert.Zero(t, model.CrossPostStatus["bluesky"].RetryCount, "rate limits do not use up retries")
+
+	// 冷却期内即使平台已恢复也不会再发
+	bluesky.postErr = nil
+	require.NoError(t, s.doSync(context.Background()))
+	assert.Empty(t, bluesky.postedIDs())
+
+	now = now.Add(11 * time.Minute)
+	require.NoError(t, s.doSync(context.Background()))
+	assert.Equal(t, []string{"1"}, bluesky.postedIDs())
+	assert.Equal(t, []string{"1"}, mastodon.postedIDs())
+}
+
+func TestSyncService_CircuitBreakerSkipsFailingTarget(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "one", CreatedAt: time.Now()},
+		{ID: "2", Content: "two", CreatedAt: time.Now()},
+		{ID: "3", Content: "three", CreatedAt: time.Now()},
+	}}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky", postErr: errors.New("bluesky is down")}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, mastodon, bluesky)
+	s.SkipOlderThan = 0
+	s.socialService.breakers.threshold = 2
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, circuitOpen, s.socialService.breaker("bluesky").State())
+	assert.Equal(t, []string{"1", "2", "3"}, mastodon.postedIDs())
+	third, err := postDao.GetBySocialAndSocialID(context.Background(), "memos", "3")
+	require.NoError(t, err)
+	_, attempted := third.CrossPostStatus["bluesky"]
+	assert.False(t, attempted, "the open breaker short-circuits the third post")
+
+	// 冷却后探测成功，熔断器关闭，剩余帖子照常补发
+	bluesky.postErr = nil
+	now = now.Add(defaultBreakerCooldown)
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, circuitClosed, s.socialService.breaker("bluesky").State())
+	assert.Equal(t, []string{"1", "2", "3"}, bluesky.postedIDs())
+}
+
+func TestSyncService_PartialPostIsRecordedAsPosted(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "album", CreatedAt: time.Now()},
+	}}
+	telegram := &fakeSyncClient{name: "telegram", postErr: &social.PartialPostError{
+		Platform: "telegram",
+		Result:   map[string]string{"id": "42"},
+		Err:      &social.StatusError{Op: "telegram send media group", StatusCode: http.StatusServiceUnavailable},
+	}}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, telegram)
+	s.SkipOlderThan = 0
+
+	require.NoError(t, s.doSync(context.Background()))
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, 1, telegram.calls, "a partly published post must not be sent again")
+	stored, err := postDao.GetBySocialAndSocialID(context.Background(), "memos", "1")
+	require.NoError(t, err)
+	status := stored.CrossPostStatus["telegram"]
+	assert.True(t, status.Success)
+	assert.Equal(t, "42", status.PlatformID)
+}
+
+func TestExtractPlatformID(t *testing.T) {
+	assert.Equal(t, "42", extractPlatformID(map[string]string{"id": "42"}))
+	assert.Equal(t, "at://did:plc:x/app.bsky.feed.post/k",
+		extractPlatformID(map[string]interface{}{"uri": "at://did:plc:x/app.bsky.feed.post/k", "rkey": "k"}))
+	assert.Equal(t, "7", extractPlatformID(&struct{ ID string }{ID: "7"}))
+	type namedID string
+	assert.Equal(t, "8", extractPlatformID(&struct{ ID namedID }{ID: "8"}), "e.g. mastodon.Status.ID")
+	assert.Empty(t, extractPlatformID(nil))
+}
+
+func TestMissingAltText(t *testing.T) {
+	described := social.NewMediaFromURL("https://example.com/a.png")
+	described.Description = "a cat"
+	blank := social.NewMediaFromURL("https://example.com/b.png")
+	blank.Description = "  "
+	post := &social.Post{Media: []social.Media{*described, *blank, *social.NewMediaFromURL("https://example.com/c.png")}}
+
+	assert.Equal(t, []int{1, 2}, missingAltText(post))
+	assert.Empty(t, missingAltText(&social.Post{Content: "text only"}))
+}
+
+// sinceSyncClient is a fakeSyncClient that supports social.SinceLister and
+// records the limit and cursor of every listing.
+type sinceSyncClient struct {
+	*fakeSyncClient
+	limits  []int
+	cursors []social.ListCursor
+}
+
+func (f *sinceSyncClient) ListPosts(ctx context.Context, limit int) ([]*social.Post, error) {
+	return f.ListPostsSince(ctx, limit, social.ListCursor{})
+}
+
+func (f *sinceSyncClient) ListPostsSince(_ context.Context, limit int, since social.ListCursor) ([]*social.Post, error) {
+	f.limits = append(f.limits, limit)
+	f.cursors = append(f.cursors, since)
+	var posts []*social.Post
+	for _, p := range f.posts {
+		if !p.CreatedAt.Before(since.SinceTime) {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+func TestSyncService_ForwardsFetchLimit(t *testing.T) {
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos"}}
+	s := newTestSyncService(newMemoryPostDao(), source, &fakeSyncClient{name: "mastodon"})
+
+	require.NoError(t, s.doSync(context.Background()))
+	s.FetchLimit = 25
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Equal(t, []int{defaultFetchLimit, 25}, source.limits)
+}
+
+func TestFetchLimitFromConfig(t *testing.T) {
+	prev := conf.Conf.Sync
+	t.Cleanup(func() { conf.Conf.Sync = prev })
+
+	conf.Conf.Sync = &conf.SyncConfig{BatchSize: 50}
+	socialService := &SocialService{platforms: map[string]*social.SocialPlatform{
+		"memos":    {Name: "memos", Config: &social.PlatformConfig{FetchLimit: 10}},
+		"mastodon": {Name: "mastodon", Config: &social.PlatformConfig{}},
+	}}
+
+	assert.Equal(t, 10, fetchLimitFromConfig(socialService, "memos"))
+	assert.Equal(t, 50, fetchLimitFromConfig(socialService, "mastodon"))
+
+	conf.Conf.Sync = nil
+	assert.Equal(t, defaultFetchLimit, fetchLimitFromConfig(socialService, "mastodon"))
+}
+
+func TestSyncService_CursorNarrowsListing(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "first", CreatedAt: start},
+		{ID: "2", Content: "second", CreatedAt: start.Add(time.Second)},
+	}}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	ctx := context.Background()
+
+	require.NoError(t, s.doSync(ctx))
+	source.posts = append(source.posts, &social.Post{ID: "3", Content: "third", CreatedAt: start.Add(2 * time.Second)})
+	require.NoError(t, s.doSync(ctx))
+
+	require.Len(t, source.cursors, 2)
+	assert.True(t, source.cursors[0].IsZero(), "the first sync lists from the newest post")
+	assert.Equal(t, "2", source.cursors[1].SinceID)
+	assert.True(t, source.cursors[1].SinceTime.Equal(start.Add(time.Second)))
+	assert.Equal(t, []string{"1", "2", "3"}, target.postedIDs())
+}
+
+func TestSyncService_CursorHeldWhileCrossPostFails(t *testing.T) {
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}}
+	target := &fakeSyncClient{name: "mastodon", postErr: errors.New("boom")}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+
+	require.NoError(t, s.doSync(context.Background()))
+	require.NoError(t, s.doSync(context.Background()))
+
+	require.Len(t, source.cursors, 2)
+	assert.True(t, source.cursors[1].IsZero(), "a post awaiting retry must be listed again")
+}
+
+// getterSyncClient is a fakeSyncClient that supports social.PostGetter and
+// fails any listing, so a test sees whether the sync listed the source.
+type getterSyncClient struct {
+	*fakeSyncClient
+	gets []string
+}
+
+func (f *getterSyncClient) ListPosts(_ context.Context, _ int) ([]*social.Post, error) {
+	return nil, errors.New("unexpected listing")
+}
+
+func (f *getterSyncClient) GetPost(_ context.Context, id string) (*social.Post, error) {
+	f.gets = append(f.gets, id)
+	for _, p := range f.posts {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func TestSyncService_SyncSingleMemo(t *testing.T) {
+	source := &getterSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "memos/1", Content: "first", CreatedAt: time.Now()},
+		{ID: "memos/2", Content: "second", CreatedAt: time.Now()},
+	}}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	s.locker = dao.NewMemoryLocker()
+	ctx := context.Background()
+
+	require.NoError(t, s.SyncSingleMemo(ctx, "memos/2"))
+	require.NoError(t, s.SyncSingleMemo(ctx, "memos/2"))
+
+	assert.Equal(t, []string{"memos/2", "memos/2"}, source.gets)
+	assert.Equal(t, []string{"memos/2"}, target.postedIDs(), "the memo is cross-posted once and the rest left alone")
+	assert.True(t, s.cursor.IsZero(), "a single memo does not advance the cursor")
+
+	assert.Error(t, s.SyncSingleMemo(ctx, "memos/3"))
+}
+
+func TestSyncService_SyncSingleMemoRequiresPostGetter(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "memos/1", Content: "first", CreatedAt: time.Now()},
+	}}
+	target := &fakeSyncClient{name: "mastodon"}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	s.locker = dao.NewMemoryLocker()
+
+	assert.Error(t, s.SyncSingleMemo(context.Background(), "memos/1"))
+	assert.Empty(t, target.postedIDs())
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"butterfly.orx.me/core/log"
+
+	"go.orx.me/apps/hyper-sync/internal/dao"
+)
+
+// 运行时设置：目标平台、是否跳过私密帖、旧帖窗口与拉取上限可通过 API 修改，
+// 保存到 config 集合，无需重启。新设置先挂起，在下一轮 doSync 开始时生效，
+// 因此不会改变正在进行的一轮。
+
+// ErrInvalidSyncSettings is returned by UpdateSettings when the update
+// cannot be applied, e.g. an unknown target platform.
+var ErrInvalidSyncSettings = errors.New("invalid sync settings")
+
+// SyncSettings is the runtime-updatable subset of a SyncService's
+// configuration. SkipOlder 0 disables the age limit.
+type SyncSettings struct {
+	TargetPlatforms []string
+	SkipPrivate     bool
+	SkipOlder       time.Duration
+	FetchLimit      int
+}
+
+// SyncSettingsUpdate changes the fields that are set and keeps the rest.
+type SyncSettingsUpdate struct {
+	TargetPlatforms []string
+	SkipPrivate     *bool
+	SkipOlder       *time.Duration
+	FetchLimit      *int
+}
+
+// Settings returns the settings the next sync runs with.
+func (s *SyncService) Settings() SyncSettings {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	if s.pendingSettings != nil {
+		return *s.pendingSettings
+	}
+	fetchLimit := s.FetchLimit
+	if fetchLimit <= 0 {
+		fetchLimit = defaultFetchLimit
+	}
+	return SyncSettings{
+		TargetPlatforms: append([]string{}, s.socials...),
+		SkipPrivate:     s.skipPrivate,
+		SkipOlder:       s.SkipOlderThan,
+		FetchLimit:      fetchLimit,
+	}
+}
+
+// ApplySettings replaces the runtime settings from the next sync on. It is
+// safe to call while a sync is running.
+func (s *SyncService) ApplySettings(settings SyncSettings) {
+	settings.TargetPlatforms = append([]string{}, settings.TargetPlatforms...)
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	s.pendingSettings = &settings
+}
+
+// applyPendingSettings installs settings queued by ApplySettings. Only
+// syncPosts calls it, and syncs of one source never overlap, so the fields it
+// writes are otherwise only read by the sync itself.
+func (s *SyncService) applyPendingSettings() {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+	if s.pendingSettings == nil {
+		return
+	}
+	s.socials = s.pendingSettings.TargetPlatforms
+	s.skipPrivate = s.pendingSettings.SkipPrivate
+	s.SkipOlderThan = s.pendingSettings.SkipOlder
+	s.FetchLimit = s.pendingSettings.FetchLimit
+	s.pendingSettings = nil
+}
+
+// ValidateSettings checks that every target is a configured platform other
+// than the source, and that the window and limit are in range.
+func (s *SyncService) ValidateSettings(settings SyncSettings) error {
+	for i, target := range settings.TargetPlatforms {
+		if target == s.mainSocial {
+			return fmt.Errorf("%w: %s cannot sync to itself", ErrInvalidSyncSettings, target)
+		}
+		if _, err := s.socialService.GetPlatform(target); err != nil {
+			return fmt.Errorf("%w: unknown platform %q", ErrInvalidSyncSettings, target)
+		}
+		if slices.Contains(settings.TargetPlatforms[:i], target) {
+			return fmt.Errorf("%w: duplicate platform %q", ErrInvalidSyncSettings, target)
+		}
+	}
+	if settings.SkipOlder < 0 {
+		return fmt.Errorf("%w: skip_older must not be negative", ErrInvalidSyncSettings)
+	}
+	if settings.FetchLimit <= 0 {
+		return fmt.Errorf("%w: fetch_limit must be positive", ErrInvalidSyncSettings)
+	}
+	return nil
+}
+
+// UpdateSettings merges update into the current settings, validates and
+// saves the result, and applies it from the next sync on. Validation
+// failures wrap ErrInvalidSyncSettings.
+func (s *SyncService) UpdateSettings(ctx context.Context, store dao.SyncSettingsDao, update SyncSettingsUpdate) (SyncSettings, error) {
+	settings := s.Settings()
+	if update.TargetPlatforms != nil {
+		settings.TargetPlatforms = update.TargetPlatforms
+	}
+	if update.SkipPrivate != nil {
+		settings.SkipPrivate = *update.SkipPrivate
+	}
+	if update.SkipOlder != nil {
+		settings.SkipOlder = *update.SkipOlder
+	}
+	if update.FetchLimit != nil {
+		settings.FetchLimit = *update.FetchLimit
+	}
+	if err := s.ValidateSettings(settings); err != nil {
+		return SyncSettings{}, err
+	}
+
+	err := store.SaveSyncSettings(ctx, &dao.SyncSettingsModel{
+		Source:          s.mainSocial,
+		TargetPlatforms: settings.TargetPlatforms,
+		SkipPrivate:     settings.SkipPrivate,
+		SkipOlder:       settings.SkipOlder,
+		FetchLimit:      settings.FetchLimit,
+	})
+	if err != nil {
+		return SyncSettings{}, err
+	}
+
+	s.ApplySettings(settings)
+	log.FromContext(ctx).Info("Sync settings updated", "main_social", s.mainSocial,
+		"targets", settings.TargetPlatforms, "skip_private", settings.SkipPrivate,
+		"skip_older", settings.SkipOlder.String(), "fetch_limit", settings.FetchLimit)
+	return settings, nil
+}
+
+// LoadSettings applies the settings saved by UpdateSettings, if any. Saved
+// settings that no longer validate, e.g. because a target was removed from
+// the config file, are ignored with a warning.
+func (s *SyncService) LoadSettings(ctx context.Context, store dao.SyncSettingsDao) error {
+	saved, err := store.GetSyncSettings(ctx, s.mainSocial)
+	if err != nil {
+		return err
+	}
+	if saved == nil {
+		return nil
+	}
+
+	settings := SyncSettings{
+		TargetPlatforms: saved.TargetPlatforms,
+		SkipPrivate:     saved.SkipPrivate,
+		SkipOlder:       saved.SkipOlder,
+		FetchLimit:      saved.FetchLimit,
+	}
+	if err := s.ValidateSettings(settings); err != nil {
+		log.FromContext(ctx).Warn("Ignoring saved sync settings", "main_social", s.mainSocial, "error", err)
+		return nil
+	}
+	s.ApplySettings(settings)
+	return nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// memorySyncSettingsDao keeps saved sync settings in memory.
+type memorySyncSettingsDao struct {
+	saved map[string]*dao.SyncSettingsModel
+}
+
+func (d *memorySyncSettingsDao) GetSyncSettings(_ context.Context, source string) (*dao.SyncSettingsModel, error) {
+	return d.saved[source], nil
+}
+
+func (d *memorySyncSettingsDao) SaveSyncSettings(_ context.Context, settings *dao.SyncSettingsModel) error {
+	if d.saved == nil {
+		d.saved = make(map[string]*dao.SyncSettingsModel)
+	}
+	d.saved[settings.Source] = settings
+	return nil
+}
+
+func TestSyncService_UpdateSettingsTakesEffectOnNextSync(t *testing.T) {
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+		{ID: "2", Content: "followers only", Visibility: social.VisibilityLevelPrivate, CreatedAt: time.Now()},
+	}}}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	bluesky := &fakeSyncClient{name: "bluesky"}
+	s := newTestSyncService(newMemoryPostDao(), source, mastodon, bluesky)
+	s.socials = []string{"mastodon"}
+	store := &memorySyncSettingsDao{}
+
+	skipPrivate := true
+	fetchLimit := 20
+	settings, err := s.UpdateSettings(context.Background(), store, SyncSettingsUpdate{
+		TargetPlatforms: []string{"bluesky"},
+		SkipPrivate:     &skipPrivate,
+		FetchLimit:      &fetchLimit,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bluesky"}, settings.TargetPlatforms)
+	assert.Equal(t, defaultSkipOlderThan, settings.SkipOlder, "fields left out keep their value")
+
+	saved := store.saved["memos"]
+	require.NotNil(t, saved)
+	assert.Equal(t, []string{"bluesky"}, saved.TargetPlatforms)
+	assert.True(t, saved.SkipPrivate)
+	assert.Equal(t, 20, saved.FetchLimit)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Empty(t, mastodon.postedIDs(), "the old target is dropped")
+	assert.Equal(t, []string{"1"}, bluesky.postedIDs(), "the private post is skipped")
+	assert.Equal(t, []int{20}, source.limits)
+}
+
+func TestSyncService_UpdateSettingsRejectsInvalid(t *testing.T) {
+	s := newTestSyncService(newMemoryPostDao(), &fakeSyncClient{name: "memos"}, &fakeSyncClient{name: "mastodon"})
+	store := &memorySyncSettingsDao{}
+	negative := -time.Minute
+	zero := 0
+
+	for name, update := range map[string]SyncSettingsUpdate{
+		"unknown platform":   {TargetPlatforms: []string{"mastodon", "myspace"}},
+		"source as target":   {TargetPlatforms: []string{"memos"}},
+		"duplicate target":   {TargetPlatforms: []string{"mastodon", "mastodon"}},
+		"negative skip":      {SkipOlder: &negative},
+		"non-positive limit": {FetchLimit: &zero},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.UpdateSettings(context.Background(), store, update)
+			assert.ErrorIs(t, err, ErrInvalidSyncSettings)
+		})
+	}
+
+	assert.Empty(t, store.saved, "rejected settings are not saved")
+	assert.Equal(t, []string{"mastodon"}, s.Settings().TargetPlatforms)
+}
+
+func TestSyncService_LoadSettings(t *testing.T) {
+	s := newTestSyncService(newMemoryPostDao(), &fakeSyncClient{name: "memos"},
+		&fakeSyncClient{name: "mastodon"}, &fakeSyncClient{name: "bluesky"})
+	store := &memorySyncSettingsDao{saved: map[string]*dao.SyncSettingsModel{
+		"memos": {Source: "memos", TargetPlatforms: []string{"bluesky"}, SkipOlder: 0, FetchLimit: 40},
+	}}
+
+	require.NoError(t, s.LoadSettings(context.Background(), store))
+	assert.Equal(t, SyncSettings{TargetPlatforms: []string{"bluesky"}, FetchLimit: 40}, s.Settings())
+
+	// 配置文件中已删除的平台使保存的设置失效，沿用当前设置
+	store.saved["memos"].TargetPlatforms = []string{"threads"}
+	s2 := newTestSyncService(newMemoryPostDao(), &fakeSyncClient{name: "memos"}, &fakeSyncClient{name: "mastodon"})
+	require.NoError(t, s2.LoadSettings(context.Background(), store))
+	assert.Equal(t, []string{"mastodon"}, s2.Settings().TargetPlatforms)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+
+	"butterfly.orx.me/core/log"
+
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// 同步水位：cursor 保存到 sync_state 集合，重启后第一轮同步从上次处理到的
+// 帖子之后继续拉取，而不是重新拉取一整页再按时间窗口过滤。
+
+// SetSyncStateDao makes the listing cursor persistent: the first sync loads
+// the watermark saved in store and every settled sync saves it. Without a
+// store the cursor lives in memory only.
+func (s *SyncService) SetSyncStateDao(store dao.SyncStateDao) {
+	s.stateDao = store
+}
+
+// loadCursor reads the saved watermark into s.cursor once. A source without
+// a watermark is listed from its newest post. A read error is logged and
+// retried on the next sync, which meanwhile lists a full page as before.
+func (s *SyncService) loadCursor(ctx context.Context) {
+	if s.stateDao == nil || s.cursorLoaded {
+		return
+	}
+	logger := log.FromContext(ctx)
+
+	state, err := s.stateDao.GetSyncState(ctx, s.mainSocial)
+	if err != nil {
+		logger.Error("Failed to load sync watermark, listing without it", "main_social", s.mainSocial, "error", err)
+		return
+	}
+	s.cursorLoaded = true
+	if state == nil {
+		logger.Info("No sync watermark yet, listing from the newest post", "main_social", s.mainSocial)
+		return
+	}
+	// 内存中的游标更新（同一进程内已推进过）优先于保存的水位
+	if s.cursor.IsZero() {
+		s.cursor = social.ListCursor{SinceID: state.SinceID, SinceTime: state.SinceTime}
+	}
+}
+
+// saveCursor persists s.cursor. A failed write is only logged: the cursor
+// has already advanced in memory, and the next settled sync writes again.
+func (s *SyncService) saveCursor(ctx context.Context) {
+	if s.stateDao == nil {
+		return
+	}
+	err := s.stateDao.SaveSyncState(ctx, &dao.SyncStateModel{
+		Source:    s.mainSocial,
+		SinceID:   s.cursor.SinceID,
+		SinceTime: s.cursor.SinceTime,
+	})
+	if err != nil {
+		log.FromContext(ctx).Error("Failed to save sync watermark", "main_social", s.mainSocial, "error", err)
+	}
+}
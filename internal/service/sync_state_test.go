@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// memorySyncStateDao is an in-memory dao.SyncStateDao.
+type memorySyncStateDao struct {
+	mu     sync.Mutex
+	states map[string]dao.SyncStateModel
+	saves  int
+	getErr error
+}
+
+func newMemorySyncStateDao() *memorySyncStateDao {
+	return &memorySyncStateDao{states: make(map[string]dao.SyncStateModel)}
+}
+
+func (d *memorySyncStateDao) GetSyncState(_ context.Context, source string) (*dao.SyncStateModel, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.getErr != nil {
+		return nil, d.getErr
+	}
+	state, ok := d.states[source]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func (d *memorySyncStateDao) SaveSyncState(_ context.Context, state *dao.SyncStateModel) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.saves++
+	d.states[state.Source] = *state
+	return nil
+}
+
+func TestSyncService_WatermarkInitiallyEmpty(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "first", CreatedAt: start},
+		{ID: "2", Content: "second", CreatedAt: start.Add(time.Second)},
+	}}}
+	target := &fakeSyncClient{name: "mastodon"}
+	store := newMemorySyncStateDao()
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	s.SetSyncStateDao(store)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	require.Len(t, source.cursors, 1)
+	assert.True(t, source.cursors[0].IsZero(), "without a watermark the newest page is listed")
+	assert.Equal(t, []string{"1", "2"}, target.postedIDs())
+	require.Contains(t, store.states, "memos")
+	assert.Equal(t, "2", store.states["memos"].SinceID)
+	assert.True(t, store.states["memos"].SinceTime.Equal(start.Add(time.Second)))
+}
+
+func TestSyncService_WatermarkAdvancesAndSurvivesRestart(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	posts := []*social.Post{
+		{ID: "1", Content: "first", CreatedAt: start},
+		{ID: "2", Content: "second", CreatedAt: start.Add(time.Second)},
+	}
+	store := newMemorySyncStateDao()
+	postDao := newMemoryPostDao()
+	target := &fakeSyncClient{name: "mastodon"}
+
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: posts}}
+	s := newTestSyncService(postDao, source, target)
+	s.SetSyncStateDao(store)
+	require.NoError(t, s.doSync(context.Background()))
+
+	// 重启：新的 SyncService 从保存的水位继续
+	posts = append(posts, &social.Post{ID: "3", Content: "third", CreatedAt: start.Add(2 * time.Second)})
+	restarted := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: posts}}
+	s = newTestSyncService(postDao, restarted, target)
+	s.SetSyncStateDao(store)
+	require.NoError(t, s.doSync(context.Background()))
+
+	require.Len(t, restarted.cursors, 1)
+	assert.Equal(t, "2", restarted.cursors[0].SinceID, "the saved watermark narrows the listing")
+	assert.True(t, restarted.cursors[0].SinceTime.Equal(start.Add(time.Second)))
+	assert.Equal(t, []string{"1", "2", "3"}, target.postedIDs())
+	assert.Equal(t, "3", store.states["memos"].SinceID)
+
+	// 已加载后不再重复读取
+	store.getErr = errors.New("unexpected read")
+	require.NoError(t, s.doSync(context.Background()))
+	assert.Equal(t, "3", restarted.cursors[1].SinceID)
+}
+
+func TestSyncService_WatermarkHeldOnFailure(t *testing.T) {
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}}
+	target := &fakeSyncClient{name: "mastodon", postErr: errors.New("boom")}
+	store := newMemorySyncStateDao()
+	store.states["memos"] = dao.SyncStateModel{Source: "memos", SinceID: "0", SinceTime: time.Now().Add(-time.Hour)}
+	s := newTestSyncService(newMemoryPostDao(), source, target)
+	s.SetSyncStateDao(store)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	assert.Zero(t, store.saves, "a failed cross-post does not advance the watermark")
+	assert.Equal(t, "0", store.states["memos"].SinceID)
+	require.Len(t, source.cursors, 1)
+	assert.Equal(t, "0", source.cursors[0].SinceID)
+}
+
+func TestSyncService_WatermarkReadErrorListsFullPage(t *testing.T) {
+	source := &sinceSyncClient{fakeSyncClient: &fakeSyncClient{name: "memos"}}
+	store := newMemorySyncStateDao()
+	store.getErr = errors.New("mongo down")
+	s := newTestSyncService(newMemoryPostDao(), source, &fakeSyncClient{name: "mastodon"})
+	s.SetSyncStateDao(store)
+
+	require.NoError(t, s.doSync(context.Background()))
+	require.Len(t, source.cursors, 1)
+	assert.True(t, source.cursors[0].IsZero())
+	assert.False(t, s.cursorLoaded, "the watermark is read again next time")
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+
+	"butterfly.orx.me/core/log"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// orderRepliesAfterParents returns posts with every reply moved after its
+// parent when both are in the batch, so the parent's platform IDs are stored
+// before the reply is cross-posted. Otherwise the source order is kept.
+func orderRepliesAfterParents(posts []*social.Post) []*social.Post {
+	byID := make(map[string]*social.Post, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
+	}
+
+	ordered := make([]*social.Post, 0, len(posts))
+	emitted := make(map[*social.Post]bool, len(posts))
+	var emit func(post *social.Post)
+	emit = func(post *social.Post) {
+		if emitted[post] {
+			return
+		}
+		// Mark first so a reply cycle cannot recurse forever.
+		emitted[post] = true
+		if parent, ok := byID[post.InReplyTo]; ok && post.InReplyTo != "" {
+			emit(parent)
+		}
+		ordered = append(ordered, post)
+	}
+	for _, post := range posts {
+		emit(post)
+	}
+	return ordered
+}
+
+// withReplyTarget returns post with InReplyTo rewritten from the parent's
+// source ID to the parent's ID on targetSocial, looked up from the parent's
+// stored cross-post status. A reply whose parent was never posted to the
+// target is posted standalone. post itself is not modified, since targets
+// are cross-posted concurrently.
+func (s *SyncService) withReplyTarget(ctx context.Context, post *social.Post, targetSocial string) *social.Post {
+	if post.InReplyTo == "" {
+		return post
+	}
+	logger := log.FromContext(ctx)
+
+	out := *post
+	out.InReplyTo = ""
+
+	parent, err := s.postDao.GetBySocialAndSocialID(ctx, s.mainSocial, post.InReplyTo)
+	if err != nil {
+		logger.Warn("Error looking up reply parent, posting standalone",
+			"error", err, "post_id", post.ID, "parent_id", post.InReplyTo, "target_platform", targetSocial)
+		return &out
+	}
+	if parent == nil {
+		logger.Info("Reply parent not synced, posting standalone",
+			"post_id", post.ID, "parent_id", post.InReplyTo, "target_platform", targetSocial)
+		return &out
+	}
+
+	status, ok := parent.CrossPostStatus[targetSocial]
+	if !ok || !status.CrossPosted || status.PlatformID == "" {
+		logger.Info("Reply parent not posted to platform, posting standalone",
+			"post_id", post.ID, "parent_id", post.InReplyTo, "target_platform", targetSocial)
+		return &out
+	}
+
+	out.InReplyTo = status.PlatformID
+	return &out
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+
+	"butterfly.orx.me/core/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/metrics"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// 跳过：目标平台的 visibility_map 写了 skip、帖子可见性不受支持且
+// unsupported_visibility 为 skip，或客户端返回 ErrVisibilityNotSupported、
+// 既没有结果也没有错误时，帖子不发到该平台（见 social.PostSkipped）。跳过记录在
+// CrossPostStatus.SkipReason 中，既不算成功也不算失败；重试也不会成功，所以
+// 之后的同步不再尝试该目标，只能通过 RetryPost 手动重试。
+
+// Reason codes for skipped cross-posts, reported to progress listeners and
+// observers.
+const (
+	skipReasonVisibility = "visibility_skipped"
+	skipReasonNoPost     = "no_post_returned"
+	// skipReasonPrevious: an earlier run recorded the target as skipped
+	skipReasonPrevious = "skipped_previously"
+)
+
+// visibilitySkipped reports whether targetSocial's visibility settings skip
+// post, with the reason to record.
+func (s *SyncService) visibilitySkipped(post *social.Post, targetSocial string) (string, bool) {
+	target, err := s.socialService.GetPlatform(targetSocial)
+	if err != nil || target.Config == nil {
+		return "", false
+	}
+	if _, err := target.Config.ResolveVisibility(post.Visibility); social.IsVisibilitySkip(err) {
+		return err.Error(), true
+	}
+	return "", false
+}
+
+// crossPostSkipped records in span, metrics and the post's status that
+// postID was not sent to targetSocial for reason, and returns the reason
+// code. err is what the skip came from, nil when the client returned no
+// post.
+func (s *SyncService) crossPostSkipped(ctx context.Context, span trace.Span, postID, targetSocial, reason string, err error) string {
+	code, status := skipReasonNoPost, metrics.StatusSkippedNoPost
+	if social.IsVisibilitySkip(err) {
+		code, status = skipReasonVisibility, metrics.StatusSkippedVisibility
+	}
+
+	log.FromContext(ctx).Warn("Target platform did not publish the post, skipping",
+		"post_id", postID, "platform", targetSocial, "reason", reason)
+	s.metrics.IncCrossPosts(targetSocial, status)
+	s.tracer.SetSpanSkipped(span, code, map[string]interface{}{
+		"target_platform": targetSocial,
+	})
+	s.recordCrossPostSkipped(ctx, postID, targetSocial, reason)
+	return code
+}
+
+// recordCrossPostSkipped stores that postID was deliberately not sent to
+// targetSocial.
+func (s *SyncService) recordCrossPostSkipped(ctx context.Context, postID, targetSocial, reason string) {
+	status := dao.CrossPostStatus{SkipReason: reason}
+	if err := s.postDao.UpdateCrossPostStatus(ctx, postID, targetSocial, status); err != nil {
+		log.FromContext(ctx).Error("Error updating cross-post status", "error", err, "post_id", postID, "platform", targetSocial)
+		s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusError)
+		return
+	}
+	s.metrics.IncDatabaseOps(metrics.OperationUpdateStatus, metrics.StatusSuccess)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestSyncService_UnsupportedVisibilityFromClientIsSkipped(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}
+	telegram := &fakeSyncClient{
+		name:    "telegram",
+		postErr: fmt.Errorf("%w by platform telegram: unlisted", social.ErrVisibilityNotSupported),
+	}
+	mastodon := &fakeSyncClient{name: "mastodon"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, telegram, mastodon)
+	ctx := context.Background()
+
+	result, err := s.syncPosts(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Synced, "a skipped target does not fail the post")
+	assert.Equal(t, 0, result.Failed)
+	assert.Equal(t, 1, result.PerPlatform["mastodon"].Success)
+	assert.Equal(t, &PlatformSyncResult{Skipped: 1}, result.PerPlatform["telegram"],
+		"a skipped target is neither a success nor a failure")
+
+	postModel, err := postDao.GetBySocialAndSocialID(ctx, "memos", "1")
+	require.NoError(t, err)
+	status := postModel.CrossPostStatus["telegram"]
+	assert.False(t, status.Success, "a skipped target must not be recorded as posted")
+	assert.False(t, status.CrossPosted)
+	assert.Empty(t, status.PlatformID)
+	assert.Zero(t, status.RetryCount)
+	assert.Contains(t, status.SkipReason, "not supported")
+
+	// The next run leaves the skipped target alone
+	telegram.postErr = nil
+	require.NoError(t, s.doSync(ctx))
+	assert.Empty(t, telegram.postedIDs())
+	assert.Equal(t, []string{"1"}, mastodon.postedIDs())
+}
+
+func TestSyncService_VisibilitySkippedByConfig(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", Visibility: social.VisibilityLevelUnlisted, CreatedAt: time.Now()},
+	}}
+	bluesky := &fakeSyncClient{name: "bluesky"}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, bluesky)
+	platform, err := s.socialService.GetPlatform("bluesky")
+	require.NoError(t, err)
+	platform.Config.Type = social.PlatformBluesky.String()
+	platform.Config.UnsupportedVisibilityPolicy = social.VisibilityPolicySkip
+
+	result, err := s.syncPosts(context.Background(), "")
+	require.NoError(t, err)
+	assert.Empty(t, bluesky.postedIDs())
+	assert.Equal(t, 1, result.Skipped)
+
+	postModel, err := postDao.GetBySocialAndSocialID(context.Background(), "memos", "1")
+	require.NoError(t, err)
+	status := postModel.CrossPostStatus["bluesky"]
+	assert.False(t, status.Success)
+	assert.False(t, status.CrossPosted)
+	assert.Contains(t, status.SkipReason, "unlisted is not supported by platform bluesky")
+}
+
+func TestSyncService_NoPostReturnedIsSkipped(t *testing.T) {
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "hello", CreatedAt: time.Now()},
+	}}
+	mastodon := &fakeSyncClient{name: "mastodon", noPost: true}
+	postDao := newMemoryPostDao()
+	s := newTestSyncService(postDao, source, mastodon)
+	ctx := context.Background()
+
+	result, err := s.syncPosts(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Failed)
+	assert.Equal(t, &PlatformSyncResult{Skipped: 1}, result.PerPlatform["mastodon"])
+
+	postModel, err := postDao.GetBySocialAndSocialID(ctx, "memos", "1")
+	require.NoError(t, err)
+	status := postModel.CrossPostStatus["mastodon"]
+	assert.False(t, status.Success)
+	assert.False(t, status.CrossPosted)
+	assert.Empty(t, status.PlatformID)
+	assert.Equal(t, "platform returned no post", status.SkipReason)
+}
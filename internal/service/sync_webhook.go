@@ -0,0 +1,185 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"butterfly.orx.me/core/log"
+)
+
+const (
+	// WebhookSignatureHeader carries "sha256=<hex HMAC-SHA256 of the body>",
+	// keyed with webhook.secret.
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	// WebhookEventHeader names the event; currently always "sync.completed".
+	WebhookEventHeader = "X-Webhook-Event"
+
+	syncCompletedEvent = "sync.completed"
+
+	defaultOutgoingWebhookAttempts = 3
+	defaultOutgoingWebhookDelay    = time.Second
+	defaultOutgoingWebhookTimeout  = 10 * time.Second
+)
+
+// SyncSummary is what outgoing webhooks receive after each sync run.
+type SyncSummary struct {
+	Source string `json:"source"`
+	DryRun bool   `json:"dry_run"`
+	// PostsFetched counts posts returned by the source; PostsSynced those
+	// cross-posted to at least one target in this run.
+	PostsFetched int                            `json:"posts_fetched"`
+	PostsSynced  int                            `json:"posts_synced"`
+	Platforms    map[string]*PlatformSyncResult `json:"platforms"`
+	// Error is set when the run aborted, e.g. the source could not be read.
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+
+	mu sync.Mutex
+}
+
+// PlatformSyncResult counts cross-post outcomes for one target platform.
+type PlatformSyncResult struct {
+	Success int `json:"success"`
+	Failed  int `json:"failed"`
+	// Skipped counts posts the platform deliberately did not publish, e.g.
+	// because of their visibility.
+	Skipped int `json:"skipped"`
+}
+
+func newSyncSummary(source string, dryRun bool, startedAt time.Time) *SyncSummary {
+	return &SyncSummary{
+		Source:    source,
+		DryRun:    dryRun,
+		Platforms: make(map[string]*PlatformSyncResult),
+		StartedAt: startedAt,
+	}
+}
+
+// recordCrossPost is safe to call from the parallel cross-post goroutines.
+func (s *SyncSummary) recordCrossPost(platform string, outcome crossPostOutcome) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, exists := s.Platforms[platform]
+	if !exists {
+		result = &PlatformSyncResult{}
+		s.Platforms[platform] = result
+	}
+	switch outcome {
+	case crossPostPosted:
+		result.Success++
+	case crossPostSkipped:
+		result.Skipped++
+	default:
+		result.Failed++
+	}
+}
+
+// platformResults returns a copy of the per-platform counts.
+func (s *SyncSummary) platformResults() map[string]*PlatformSyncResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make(map[string]*PlatformSyncResult, len(s.Platforms))
+	for platform, result := range s.Platforms {
+		copied := *result
+		results[platform] = &copied
+	}
+	return results
+}
+
+// OutgoingWebhook posts a signed SyncSummary to each configured URL.
+type OutgoingWebhook struct {
+	urls       []string
+	secret     string
+	httpClient *http.Client
+
+	attempts   int
+	retryDelay time.Duration
+}
+
+func NewOutgoingWebhook(urls []string, secret string, timeout time.Duration) *OutgoingWebhook {
+	if timeout <= 0 {
+		timeout = defaultOutgoingWebhookTimeout
+	}
+	return &OutgoingWebhook{
+		urls:       urls,
+		secret:     secret,
+		httpClient: &http.Client{Timeout: timeout},
+		attempts:   defaultOutgoingWebhookAttempts,
+		retryDelay: defaultOutgoingWebhookDelay,
+	}
+}
+
+// SignWebhookPayload returns the WebhookSignatureHeader value for body.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notify sends summary to every URL, retrying each one with backoff on
+// network errors and non-2xx responses. A failing receiver does not stop the
+// others; all failures are returned joined.
+func (w *OutgoingWebhook) Notify(ctx context.Context, summary *SyncSummary) error {
+	logger := log.FromContext(ctx)
+
+	summary.mu.Lock()
+	body, err := json.Marshal(summary)
+	summary.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("marshal sync summary: %w", err)
+	}
+	signature := SignWebhookPayload(w.secret, body)
+
+	var errs []error
+	for _, url := range w.urls {
+		err := retryWithBackoffIf(ctx, w.attempts, w.retryDelay, isWebhookRetryable, func() error {
+			return w.send(ctx, url, body, signature)
+		})
+		if err != nil {
+			logger.Error("Failed to deliver sync webhook", "url", url, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", url, err))
+			continue
+		}
+		logger.Debug("Delivered sync webhook", "url", url)
+	}
+	return errors.Join(errs...)
+}
+
+func (w *OutgoingWebhook) send(ctx context.Context, url string, body []byte, signature string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, syncCompletedEvent)
+	req.Header.Set(WebhookSignatureHeader, signature)
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// isWebhookRetryable retries every failure except cancellation: unlike a
+// cross-post, re-sending a summary is harmless.
+func isWebhookRetryable(err error) bool {
+	return !errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+type receivedWebhook struct {
+	body      []byte
+	signature string
+	event     string
+}
+
+// webhookReceiver records every delivery and answers with the given status
+// codes in order, then 200.
+func webhookReceiver(t *testing.T, statuses ...int) (*httptest.Server, chan receivedWebhook) {
+	t.Helper()
+	received := make(chan receivedWebhook, 10)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received <- receivedWebhook{
+			body:      body,
+			signature: r.Header.Get(WebhookSignatureHeader),
+			event:     r.Header.Get(WebhookEventHeader),
+		}
+		if n := int(calls.Add(1)); n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestOutgoingWebhook_SignsSummary(t *testing.T) {
+	server, received := webhookReceiver(t)
+	webhook := NewOutgoingWebhook([]string{server.URL}, "shared-secret", time.Second)
+
+	summary := newSyncSummary("memos", false, time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC))
+	summary.PostsFetched = 2
+	summary.PostsSynced = 1
+	summary.recordCrossPost("mastodon", crossPostPosted)
+	summary.recordCrossPost("bluesky", crossPostFailed)
+
+	require.NoError(t, webhook.Notify(context.Background(), summary))
+
+	got := <-received
+	assert.Equal(t, "sync.completed", got.event)
+
+	mac := hmac.New(sha256.New, []byte("shared-secret"))
+	mac.Write(got.body)
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), got.signature)
+
+	var payload SyncSummary
+	require.NoError(t, json.Unmarshal(got.body, &payload))
+	assert.Equal(t, "memos", payload.Source)
+	assert.Equal(t, 2, payload.PostsFetched)
+	assert.Equal(t, 1, payload.PostsSynced)
+	assert.Equal(t, PlatformSyncResult{Success: 1}, *payload.Platforms["mastodon"])
+	assert.Equal(t, PlatformSyncResult{Failed: 1}, *payload.Platforms["bluesky"])
+}
+
+func TestOutgoingWebhook_RetriesNon2xx(t *testing.T) {
+	flaky, flakyReceived := webhookReceiver(t, http.StatusInternalServerError, http.StatusBadRequest)
+	healthy, healthyReceived := webhookReceiver(t)
+	webhook := NewOutgoingWebhook([]string{flaky.URL, healthy.URL}, "shared-secret", time.Second)
+	webhook.retryDelay = time.Millisecond
+
+	require.NoError(t, webhook.Notify(context.Background(), newSyncSummary("memos", false, time.Now())))
+
+	assert.Len(t, flakyReceived, 3, "two failures then a success")
+	assert.Len(t, healthyReceived, 1)
+}
+
+func TestOutgoingWebhook_GivesUpAfterAttempts(t *testing.T) {
+	down, received := webhookReceiver(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	webhook := NewOutgoingWebhook([]string{down.URL}, "shared-secret", time.Second)
+	webhook.retryDelay = time.Millisecond
+
+	err := webhook.Notify(context.Background(), newSyncSummary("memos", false, time.Now()))
+	require.Error(t, err)
+	assert.Len(t, received, defaultOutgoingWebhookAttempts)
+}
+
+func TestSyncService_SendsSummaryAfterSync(t *testing.T) {
+	server, received := webhookReceiver(t)
+
+	source := &fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "1", Content: "one", CreatedAt: time.Now()},
+		{ID: "2", Content: "two", CreatedAt: time.Now()},
+	}}
+	ok := &fakeSyncClient{name: "mastodon"}
+	failing := &fakeSyncClient{name: "bluesky", postErr: errors.New("bluesky is down")}
+	s := newTestSyncService(newMemoryPostDao(), source, ok, failing)
+	s.webhook = NewOutgoingWebhook([]string{server.URL}, "shared-secret", time.Second)
+
+	require.NoError(t, s.doSync(context.Background()))
+
+	var got receivedWebhook
+	select {
+	case got = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not called")
+	}
+	assert.Equal(t, SignWebhookPayload("shared-secret", got.body), got.signature)
+
+	var payload SyncSummary
+	require.NoError(t, json.Unmarshal(got.body, &payload))
+	assert.Equal(t, 2, payload.PostsFetched)
+	assert.Equal(t, 2, payload.PostsSynced)
+	assert.Equal(t, PlatformSyncResult{Success: 2}, *payload.Platforms["mastodon"])
+	assert.Equal(t, PlatformSyncResult{Failed: 2}, *payload.Platforms["bluesky"])
+	assert.Empty(t, payload.Error)
+	assert.False(t, payload.FinishedAt.IsZero())
+}
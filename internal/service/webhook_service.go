@@ -0,0 +1,582 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"butterfly.orx.me/core/log"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+// 入站 webhook：源平台（如 Memos）在内容变化时回调，立即触发一次同步，
+// 不必等下一个轮询周期。请求必须通过 VerifyWebhookSignature 校验。
+
+var (
+	// ErrWebhookDisabled is returned when webhook.enabled is false.
+	ErrWebhookDisabled = errors.New("webhooks are disabled")
+	// ErrWebhookUnauthorized wraps every signature verification failure.
+	ErrWebhookUnauthorized = errors.New("webhook verification failed")
+	// ErrInvalidWebhookPayload is returned for bodies that cannot be handled.
+	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+)
+
+// maxWebhookBodyBytes caps the request body read for verification unless
+// webhook.max_body_bytes is set.
+const maxWebhookBodyBytes = 1 << 20
+
+// defaultWebhookDebounceWindow is used when webhook.debounce_window is unset.
+const defaultWebhookDebounceWindow = 10 * time.Second
+
+// WebhookIdempotencyHeader carries a key identifying one webhook delivery;
+// a retried delivery with the same key gets the first result back.
+const WebhookIdempotencyHeader = "X-Idempotency-Key"
+
+// webhookIdempotencyTTL is how long a handled delivery is remembered.
+const webhookIdempotencyTTL = 10 * time.Minute
+
+// TelegramWebhookEvent is the event of every Telegram webhook delivery.
+const TelegramWebhookEvent = "telegram.update"
+
+// Memos webhook activity types that change content worth syncing.
+const (
+	MemosActivityMemoCreated = "memos.memo.created"
+	MemosActivityMemoUpdated = "memos.memo.updated"
+)
+
+// WebhookResult describes what an incoming webhook triggered.
+type WebhookResult struct {
+	// Accepted is false for events that are verified but ignored.
+	Accepted bool     `json:"accepted"`
+	Event    string   `json:"event,omitempty"`
+	Sources  []string `json:"sources,omitempty"`
+	// MemoID is set when only the memo named in the webhook was synced.
+	MemoID  string `json:"memo_id,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Duplicate is set when the delivery was already handled and this is
+	// the earlier result.
+	Duplicate bool `json:"duplicate,omitempty"`
+}
+
+// MemosWebhookPayload is the body Memos posts to a webhook.
+type MemosWebhookPayload struct {
+	URL          string `json:"url"`
+	ActivityType string `json:"activityType"`
+	Creator      string `json:"creator"`
+	Memo         *struct {
+		Name string `json:"name"`
+	} `json:"memo"`
+}
+
+// GenericWebhookPayload asks for a sync of Source, or of every source the
+// webhook may trigger when Source is empty.
+type GenericWebhookPayload struct {
+	Source string `json:"source"`
+	Event  string `json:"event"`
+}
+
+// telegramWebhookClient is the part of social.TelegramClient that receives
+// webhook deliveries.
+type telegramWebhookClient interface {
+	WebhookSecret() string
+	HandleWebhookUpdate(ctx context.Context, body []byte) error
+}
+
+// WebhookSyncFactory builds the SyncService run for a source platform.
+type WebhookSyncFactory func(mainSocial string, socials []string) (*SyncService, error)
+
+// WebhookService turns verified incoming webhooks into on-demand syncs.
+type WebhookService struct {
+	cfg            *conf.WebhookConfig
+	configs        map[string]*social.PlatformConfig
+	newSyncService WebhookSyncFactory
+	// clients 返回运行中的平台客户端，Telegram webhook 需要把更新交给对应客户端
+	clients func() (map[string]social.SocialClient, error)
+
+	// pending 是各源尚未触发的去抖同步，按源平台名索引
+	pendingMu sync.Mutex
+	pending   map[string]*pendingWebhookSync
+
+	// handled 记录近期处理过的投递（按幂等键），重试时直接返回上次结果；
+	// sweptAt 是上次清理过期记录的时间
+	handledMu sync.Mutex
+	handled   map[string]handledWebhook
+	sweptAt   time.Time
+	now       func() time.Time
+}
+
+// handledWebhook is the result of a delivery, kept until expiresAt. While
+// the delivery is still being handled done is set and closed when it ends.
+type handledWebhook struct {
+	result    WebhookResult
+	expiresAt time.Time
+	done      chan struct{}
+}
+
+// pendingWebhookSync collects the Memos events for one source until its
+// debounce timer fires.
+type pendingWebhookSync struct {
+	ctx     context.Context
+	timer   *time.Timer
+	event   string
+	targets []string
+	// memoIDs are synced one by one unless full is set by an event that
+	// named no memo, in which case the whole source is synced.
+	memoIDs []string
+	full    bool
+}
+
+// NewWebhookService creates a webhook service. configs are the configured
+// platforms; only those with sync_to can be synced by a webhook.
+func NewWebhookService(cfg *conf.WebhookConfig, configs map[string]*social.PlatformConfig, newSyncService WebhookSyncFactory) *WebhookService {
+	return &WebhookService{
+		cfg:            cfg,
+		configs:        configs,
+		newSyncService: newSyncService,
+		pending:        make(map[string]*pendingWebhookSync),
+		handled:        make(map[string]handledWebhook),
+		now:            time.Now,
+	}
+}
+
+// SetSocialClients sets where Telegram webhook deliveries find the running
+// Telegram clients.
+func (s *WebhookService) SetSocialClients(clients func() (map[string]social.SocialClient, error)) {
+	s.clients = clients
+}
+
+// readBody checks that webhooks are enabled and returns the body of r.
+// r.Body is restored so it can be read again.
+func (s *WebhookService) readBody(r *http.Request) ([]byte, error) {
+	if s.cfg == nil || !s.cfg.Enabled {
+		return nil, ErrWebhookDisabled
+	}
+	limit := int64(maxWebhookBodyBytes)
+	if s.cfg.MaxBodyBytes > 0 {
+		limit = s.cfg.MaxBodyBytes
+	}
+	body, err := social.ReadLimited(r.Body, limit)
+	if errors.Is(err, social.ErrBodyTooLarge) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read webhook body: %w", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// verify checks that webhooks are enabled and r is signed, and returns the
+// body. r.Body is restored so it can be read again.
+func (s *WebhookService) verify(r *http.Request) ([]byte, error) {
+	body, err := s.readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyWebhookSignature(s.cfg, r.Header, body); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWebhookUnauthorized, err)
+	}
+	return body, nil
+}
+
+// HandleMemosWebhook syncs the Memos sources when a memo is created or
+// updated. A "source" query parameter limits the sync to one of them. When
+// the payload names the memo only that memo is synced, on the sources whose
+// endpoint matches the payload url if any do. The sync is debounced: it runs
+// once webhook.debounce_window passes without another event for the source.
+// A delivery already handled, by X-Idempotency-Key or else by the source
+// and body, returns the earlier result.
+func (s *WebhookService) HandleMemosWebhook(ctx context.Context, r *http.Request) (*WebhookResult, error) {
+	body, err := s.verify(r)
+	if err != nil {
+		return nil, err
+	}
+	key := r.Header.Get(WebhookIdempotencyHeader)
+	if key == "" {
+		// Memos 重试时请求体不变，而请求体带有 memo 内容与更新时间，足以区分不同事件
+		key = hashWebhookDelivery("memos", r.URL.Query().Get("source"), body)
+	}
+	return s.idempotent(ctx, key, func() (*WebhookResult, error) {
+		return s.handleMemosWebhook(ctx, r, body)
+	})
+}
+
+func (s *WebhookService) handleMemosWebhook(ctx context.Context, r *http.Request, body []byte) (*WebhookResult, error) {
+	var payload MemosWebhookPayload
+	if err := social.DecodeJSON(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+	}
+	if payload.ActivityType != MemosActivityMemoCreated && payload.ActivityType != MemosActivityMemoUpdated {
+		return &WebhookResult{Event: payload.ActivityType, Message: "event ignored"}, nil
+	}
+
+	sources := s.syncSources(social.PlatformMemos)
+	if name := r.URL.Query().Get("source"); name != "" {
+		targets, ok := sources[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not a memos source", ErrInvalidWebhookPayload, name)
+		}
+		sources = map[string][]string{name: targets}
+	}
+	if payload.Memo != nil && payload.Memo.Name != "" {
+		return s.schedule(ctx, payload.ActivityType, s.sourcesAtEndpoint(sources, payload.URL), payload.Memo.Name)
+	}
+	return s.schedule(ctx, payload.ActivityType, sources, "")
+}
+
+// HandleGenericWebhook syncs the source named in the payload, or every
+// source when none is named. A delivery whose X-Idempotency-Key was already
+// handled returns the earlier result. Without the header every delivery
+// syncs, since the same body is also how a caller asks for another sync.
+func (s *WebhookService) HandleGenericWebhook(ctx context.Context, r *http.Request) (*WebhookResult, error) {
+	body, err := s.verify(r)
+	if err != nil {
+		return nil, err
+	}
+	return s.idempotent(ctx, r.Header.Get(WebhookIdempotencyHeader), func() (*WebhookResult, error) {
+		return s.handleGenericWebhook(ctx, body)
+	})
+}
+
+func (s *WebhookService) handleGenericWebhook(ctx context.Context, body []byte) (*WebhookResult, error) {
+	var payload GenericWebhookPayload
+	if len(bytes.TrimSpace(body)) > 0 {
+		if err := social.DecodeJSON(body, &payload); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+		}
+	}
+
+	sources := s.syncSources("")
+	if payload.Source != "" {
+		targets, ok := sources[payload.Source]
+		if !ok {
+			return nil, fmt.Errorf("%w: no sync configured for source %s", ErrInvalidWebhookPayload, payload.Source)
+		}
+		sources = map[string][]string{payload.Source: targets}
+	}
+	return s.sync(ctx, payload.Event, sources, "")
+}
+
+// HandleTelegramWebhook receives an update Telegram delivers to a bot's
+// webhook. Telegram cannot sign the body; instead the secret_token header
+// identifies which Telegram source it is for. The update is handed to that
+// source's client, which buffers its post, and a debounced sync of the
+// source is scheduled. A redelivered update returns the earlier result.
+func (s *WebhookService) HandleTelegramWebhook(ctx context.Context, r *http.Request) (*WebhookResult, error) {
+	body, err := s.readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	name, client, err := s.telegramClient(r.Header.Get(social.TelegramWebhookSecretHeader))
+	if err != nil {
+		return nil, err
+	}
+	return s.idempotent(ctx, hashWebhookDelivery("telegram", name, body), func() (*WebhookResult, error) {
+		return s.handleTelegramWebhook(ctx, name, client, body)
+	})
+}
+
+func (s *WebhookService) handleTelegramWebhook(ctx context.Context, name string, client telegramWebhookClient, body []byte) (*WebhookResult, error) {
+	if err := client.HandleWebhookUpdate(ctx, body); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+	}
+	targets, ok := s.syncSources(social.PlatformTelegram)[name]
+	if !ok {
+		// 没有 sync_to 或不在 allowed_sources 中：帖子留在缓冲区，由定时同步处理
+		return &WebhookResult{Accepted: true, Event: TelegramWebhookEvent, Sources: []string{name}, Message: "update buffered"}, nil
+	}
+	return s.schedule(ctx, TelegramWebhookEvent, map[string][]string{name: targets}, "")
+}
+
+// telegramClient finds the Telegram client whose webhook secret is secret.
+func (s *WebhookService) telegramClient(secret string) (string, telegramWebhookClient, error) {
+	if secret == "" {
+		return "", nil, fmt.Errorf("%w: %w", ErrWebhookUnauthorized, ErrWebhookSignatureMissing)
+	}
+	if s.clients == nil {
+		return "", nil, errors.New("social clients not configured")
+	}
+	clients, err := s.clients()
+	if err != nil {
+		return "", nil, fmt.Errorf("get social clients: %w", err)
+	}
+	for name, client := range clients {
+		tg, ok := client.(telegramWebhookClient)
+		if !ok || tg.WebhookSecret() == "" {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(tg.WebhookSecret()), []byte(secret)) == 1 {
+			return name, tg, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%w: %w", ErrWebhookUnauthorized, ErrWebhookSignatureInvalid)
+}
+
+// syncSources maps the sources a webhook may sync to their sync_to
+// targets: platforms with sync_to, of type platformType unless it is empty,
+// and listed in webhook.allowed_sources when that is set.
+func (s *WebhookService) syncSources(platformType social.Platform) map[string][]string {
+	sources := make(map[string][]string)
+	for key, config := range s.configs {
+		if len(config.SyncTo) == 0 {
+			continue
+		}
+		if platformType != "" && social.ParsePlatform(config.Type) != platformType {
+			continue
+		}
+		name := config.Name
+		if name == "" {
+			name = key
+		}
+		if len(s.cfg.AllowedSources) > 0 && !slices.Contains(s.cfg.AllowedSources, name) {
+			continue
+		}
+		sources[name] = config.SyncTo
+	}
+	return sources
+}
+
+// sourcesAtEndpoint narrows Memos sources to those whose endpoint is url.
+// Memos may report an address other than the configured one, e.g. behind a
+// proxy, so all sources are kept when none matches.
+func (s *WebhookService) sourcesAtEndpoint(sources map[string][]string, url string) map[string][]string {
+	url = strings.TrimRight(url, "/")
+	if url == "" {
+		return sources
+	}
+	matched := make(map[string][]string)
+	for key, config := range s.configs {
+		name := config.Name
+		if name == "" {
+			name = key
+		}
+		targets, ok := sources[name]
+		if ok && config.Memos != nil && strings.TrimRight(config.Memos.Endpoint, "/") == url {
+			matched[name] = targets
+		}
+	}
+	if len(matched) == 0 {
+		return sources
+	}
+	return matched
+}
+
+// sync runs one sync of each source in turn, in name order. A non-empty
+// memoID syncs only that memo instead of listing the source.
+func (s *WebhookService) sync(ctx context.Context, event string, sources map[string][]string, memoID string) (*WebhookResult, error) {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	log.FromContext(ctx).Info("Webhook triggered sync", "event", event, "sources", names, "memo_id", memoID)
+
+	for _, name := range names {
+		syncService, err := s.newSyncService(name, sources[name])
+		if err != nil {
+			return nil, fmt.Errorf("create sync service for %s: %w", name, err)
+		}
+		if memoID != "" {
+			err = syncService.SyncSingleMemo(ctx, memoID)
+		} else {
+			_, err = syncService.Sync(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sync %s: %w", name, err)
+		}
+	}
+	return &WebhookResult{Accepted: true, Event: event, Sources: names, MemoID: memoID, Message: "sync completed"}, nil
+}
+
+// debounceWindow returns webhook.debounce_window, or the default when unset.
+func (s *WebhookService) debounceWindow() time.Duration {
+	if s.cfg.DebounceWindow == 0 {
+		return defaultWebhookDebounceWindow
+	}
+	return s.cfg.DebounceWindow
+}
+
+// schedule debounces a sync of each source: the first event starts a timer
+// and every later one resets it, so a burst of events (Memos may fire one on
+// every save) becomes a single run. It syncs at once when debouncing is
+// disabled.
+func (s *WebhookService) schedule(ctx context.Context, event string, sources map[string][]string, memoID string) (*WebhookResult, error) {
+	window := s.debounceWindow()
+	if window < 0 {
+		return s.sync(ctx, event, sources, memoID)
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	s.pendingMu.Lock()
+	for _, name := range names {
+		p, ok := s.pending[name]
+		if !ok {
+			p = &pendingWebhookSync{}
+			p.timer = time.AfterFunc(window, func() { s.runPending(name, p) })
+			s.pending[name] = p
+		} else {
+			p.timer.Reset(window)
+		}
+		// 保留最近一次请求的日志与追踪信息，但不随请求结束而取消
+		p.ctx = context.WithoutCancel(ctx)
+		p.event = event
+		p.targets = sources[name]
+		if memoID == "" {
+			p.full = true
+		} else if !slices.Contains(p.memoIDs, memoID) {
+			p.memoIDs = append(p.memoIDs, memoID)
+		}
+	}
+	s.pendingMu.Unlock()
+
+	log.FromContext(ctx).Info("Webhook scheduled sync", "event", event, "sources", names, "memo_id", memoID, "window", window)
+	return &WebhookResult{Accepted: true, Event: event, Sources: names, MemoID: memoID, Message: "sync scheduled"}, nil
+}
+
+// runPending runs the debounced sync p of source name when its timer fires.
+// A timer reset just after firing fires again; that run finds p already
+// taken and does nothing.
+func (s *WebhookService) runPending(name string, p *pendingWebhookSync) {
+	s.pendingMu.Lock()
+	if s.pending[name] != p {
+		s.pendingMu.Unlock()
+		return
+	}
+	delete(s.pending, name)
+	s.pendingMu.Unlock()
+
+	ctx := p.ctx
+	logger := log.FromContext(ctx)
+	var memoIDs []string
+	if !p.full {
+		memoIDs = p.memoIDs
+	}
+	logger.Info("Running debounced webhook sync", "event", p.event, "source", name, "memo_ids", memoIDs)
+
+	syncService, err := s.newSyncService(name, p.targets)
+	if err != nil {
+		logger.Error("Failed to create sync service for webhook", "source", name, "error", err)
+		return
+	}
+	if p.full {
+		if _, err := syncService.Sync(ctx); err != nil {
+			logger.Error("Debounced webhook sync failed", "source", name, "error", err)
+		}
+		return
+	}
+	for _, memoID := range memoIDs {
+		if err := syncService.SyncSingleMemo(ctx, memoID); err != nil {
+			logger.Error("Debounced webhook sync failed", "source", name, "memo_id", memoID, "error", err)
+		}
+	}
+}
+
+// hashWebhookDelivery derives an idempotency key from the webhook kind, the
+// source it targets and the body.
+func hashWebhookDelivery(kind, source string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(kind + "\n" + source + "\n"))
+	h.Write(body)
+	return kind + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotent runs handle unless a delivery with key was handled within
+// webhookIdempotencyTTL, in which case that result is returned marked as a
+// duplicate. The key is reserved while handle runs, so a duplicate arriving
+// meanwhile waits for its result. Failed deliveries are not remembered, so
+// a retry runs again. An empty key always runs handle.
+func (s *WebhookService) idempotent(ctx context.Context, key string, handle func() (*WebhookResult, error)) (*WebhookResult, error) {
+	if key == "" {
+		return handle()
+	}
+	logger := log.FromContext(ctx)
+
+	for {
+		now := s.now()
+		s.handledMu.Lock()
+		prior, ok := s.handled[key]
+		if ok && prior.done == nil && !now.Before(prior.expiresAt) {
+			ok = false
+		}
+		if !ok {
+			done := make(chan struct{})
+			s.handled[key] = handledWebhook{done: done}
+			s.handledMu.Unlock()
+			return s.handleReserved(key, done, handle)
+		}
+		s.handledMu.Unlock()
+
+		if prior.done == nil {
+			logger.Info("Duplicate webhook delivery, returning earlier result", "idempotency_key", key)
+			result := prior.result
+			result.Duplicate = true
+			return &result, nil
+		}
+
+		// 第一次投递还在处理（通用 webhook 同步执行，发送方超时后会重试），等它的结果
+		logger.Info("Duplicate webhook delivery while the first is running, waiting", "idempotency_key", key)
+		select {
+		case <-prior.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// handleReserved runs handle for the delivery whose key idempotent
+// reserved, then stores the result or, on failure, drops the reservation,
+// and wakes the duplicates waiting on done.
+func (s *WebhookService) handleReserved(key string, done chan struct{}, handle func() (*WebhookResult, error)) (*WebhookResult, error) {
+	var result *WebhookResult
+	var err error
+	defer func() {
+		s.handledMu.Lock()
+		if err == nil && result != nil {
+			now := s.now()
+			s.sweepHandled(now)
+			s.handled[key] = handledWebhook{result: *result, expiresAt: now.Add(webhookIdempotencyTTL)}
+		} else {
+			delete(s.handled, key)
+		}
+		s.handledMu.Unlock()
+		close(done)
+	}()
+
+	result, err = handle()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// sweepHandled drops expired deliveries, at most once per
+// webhookIdempotencyTTL. Callers hold handledMu.
+func (s *WebhookService) sweepHandled(now time.Time) {
+	if now.Sub(s.sweptAt) < webhookIdempotencyTTL {
+		return
+	}
+	s.sweptAt = now
+	for k, h := range s.handled {
+		if h.done == nil && !now.Before(h.expiresAt) {
+			delete(s.handled, k)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+	"go.orx.me/apps/hyper-sync/internal/dao"
+	"go.orx.me/apps/hyper-sync/internal/social"
+)
+
+func TestWebhookService_VerifyRestoresBody(t *testing.T) {
+	s := NewWebhookService(&conf.WebhookConfig{Enabled: true, Secret: "secret"}, nil, nil)
+	body := `{"source":"memos"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/generic", strings.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", []byte(body)))
+
+	got, err := s.verify(req)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(got))
+
+	again, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(again), "the body can be read again after verification")
+}
+
+func TestWebhookService_VerifyErrors(t *testing.T) {
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/api/webhooks/generic", strings.NewReader(`{}`))
+	}
+
+	_, err := NewWebhookService(nil, nil, nil).verify(newRequest())
+	assert.ErrorIs(t, err, ErrWebhookDisabled)
+
+	_, err = NewWebhookService(&conf.WebhookConfig{Enabled: true}, nil, nil).verify(newRequest())
+	assert.ErrorIs(t, err, ErrWebhookUnauthorized, "no secret configured")
+
+	_, err = NewWebhookService(&conf.WebhookConfig{Enabled: true, Secret: "secret"}, nil, nil).verify(newRequest())
+	assert.ErrorIs(t, err, ErrWebhookUnauthorized)
+	assert.ErrorIs(t, err, ErrWebhookSignatureMissing)
+}
+
+func TestWebhookService_OversizedBody(t *testing.T) {
+	s := NewWebhookService(&conf.WebhookConfig{Enabled: true, Secret: "secret", MaxBodyBytes: 16}, nil, nil)
+	body := `{"source":"memos","event":"push"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/generic", strings.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", []byte(body)))
+
+	_, err := s.verify(req)
+	assert.ErrorIs(t, err, ErrInvalidWebhookPayload)
+	assert.ErrorIs(t, err, social.ErrBodyTooLarge)
+	assert.ErrorContains(t, err, "exceeds 16 bytes")
+}
+
+func TestWebhookService_SourcesAtEndpoint(t *testing.T) {
+	configs := map[string]*social.PlatformConfig{
+		"home": {Type: "memos", SyncTo: []string{"mastodon"}, Memos: &social.MemosConfig{Endpoint: "https://home.example.com/"}},
+		"work": {Type: "memos", SyncTo: []string{"bluesky"}, Memos: &social.MemosConfig{Endpoint: "https://work.example.com"}},
+	}
+	s := NewWebhookService(&conf.WebhookConfig{Enabled: true}, configs, nil)
+	sources := s.syncSources(social.PlatformMemos)
+
+	assert.Equal(t, map[string][]string{"home": {"mastodon"}}, s.sourcesAtEndpoint(sources, "https://home.example.com"))
+	assert.Equal(t, sources, s.sourcesAtEndpoint(sources, "http://10.0.0.1:5230"), "no match keeps every source")
+	assert.Equal(t, sources, s.sourcesAtEndpoint(sources, ""))
+}
+
+// memosSyncClient lists its posts like fakeSyncClient and also fetches one
+// by ID, so webhooks can run both batch and single-memo syncs.
+type memosSyncClient struct {
+	*fakeSyncClient
+}
+
+func (f *memosSyncClient) GetPost(_ context.Context, id string) (*social.Post, error) {
+	for _, p := range f.posts {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+// debounceFixture is a webhook service with a short debounce window over
+// one Memos source whose sync runs are counted.
+type debounceFixture struct {
+	service *WebhookService
+	target  *fakeSyncClient
+
+	mu   sync.Mutex
+	runs int
+	sent int
+}
+
+func newDebounceFixture(t *testing.T, window time.Duration) *debounceFixture {
+	t.Helper()
+	source := &memosSyncClient{&fakeSyncClient{name: "memos", posts: []*social.Post{
+		{ID: "memos/1", Content: "first", CreatedAt: time.Now()},
+		{ID: "memos/2", Content: "second", CreatedAt: time.Now()},
+	}}}
+	f := &debounceFixture{target: &fakeSyncClient{name: "mastodon"}}
+	syncService := newTestSyncService(newMemoryPostDao(), source, f.target)
+	syncService.locker = dao.NewMemoryLocker()
+
+	configs := map[string]*social.PlatformConfig{
+		"memos": {Type: "memos", SyncTo: []string{"mastodon"}},
+	}
+	cfg := &conf.WebhookConfig{Enabled: true, Secret: "secret", DebounceWindow: window}
+	f.service = NewWebhookService(cfg, configs, func(string, []string) (*SyncService, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.runs++
+		return syncService, nil
+	})
+	return f
+}
+
+func (f *debounceFixture) send(t *testing.T, memoID string) {
+	t.Helper()
+	// 每次保存的 updateTime 不同，因此不会被当作重复投递
+	f.sent++
+	body := fmt.Sprintf(`{"activityType":"memos.memo.updated","memo":{"name":%q,"updateTime":"2026-01-02T12:00:%02dZ"}}`, memoID, f.sent)
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/memos", strings.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", []byte(body)))
+
+	result, err := f.service.HandleMemosWebhook(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.Accepted)
+	assert.Equal(t, "sync scheduled", result.Message)
+}
+
+func (f *debounceFixture) runCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.runs
+}
+
+func TestWebhookService_DebounceCollapsesBurst(t *testing.T) {
+	const window = 100 * time.Millisecond
+	f := newDebounceFixture(t, window)
+
+	for _, memoID := range []string{"memos/1", "memos/2", "memos/1", "memos/2", "memos/2"} {
+		f.send(t, memoID)
+	}
+	assert.Zero(t, f.runCount(), "nothing runs inside the window")
+
+	require.Eventually(t, func() bool { return f.runCount() == 1 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(3 * window)
+	assert.Equal(t, 1, f.runCount(), "five events make one scheduled task")
+	assert.ElementsMatch(t, []string{"memos/1", "memos/2"}, f.target.postedIDs(), "each memo named in the burst is synced once")
+}
+
+func TestWebhookService_DebounceSeparatesSpreadEvents(t *testing.T) {
+	const window = 50 * time.Millisecond
+	f := newDebounceFixture(t, window)
+
+	f.send(t, "memos/1")
+	require.Eventually(t, func() bool { return f.runCount() == 1 }, 2*time.Second, 10*time.Millisecond)
+	f.send(t, "memos/2")
+	require.Eventually(t, func() bool { return f.runCount() == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	time.Sleep(3 * window)
+	assert.Equal(t, 2, f.runCount())
+	assert.Equal(t, []string{"memos/1", "memos/2"}, f.target.postedIDs())
+}
+
+func TestWebhookService_DebounceResetsOnEachEvent(t *testing.T) {
+	const window = 150 * time.Millisecond
+	f := newDebounceFixture(t, window)
+
+	// 每个事件都在窗口结束前到达，计时器不断重置
+	for i := 0; i < 4; i++ {
+		f.send(t, "memos/1")
+		time.Sleep(window / 3)
+	}
+	assert.Zero(t, f.runCount(), "the window restarts with every event")
+
+	require.Eventually(t, func() bool { return f.runCount() == 1 }, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestWebhookService_IdempotencyKey(t *testing.T) {
+	f := newDebounceFixture(t, time.Hour)
+	deliver := func(key string) *WebhookResult {
+		body := `{"activityType":"memos.memo.created","memo":{"name":"memos/1"}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/memos", strings.NewReader(body))
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", []byte(body)))
+		req.Header.Set(WebhookIdempotencyHeader, key)
+		result, err := f.service.HandleMemosWebhook(context.Background(), req)
+		require.NoError(t, err)
+		return result
+	}
+
+	first := deliver("delivery-1")
+	assert.False(t, first.Duplicate)
+	require.Contains(t, f.service.pending, "memos")
+	scheduled := f.service.pending["memos"]
+
+	second := deliver("delivery-1")
+	assert.True(t, second.Duplicate)
+	assert.Equal(t, first.Sources, second.Sources)
+	assert.Equal(t, first.MemoID, second.MemoID)
+	assert.Same(t, scheduled, f.service.pending["memos"])
+
+	// 同一请求体换一个键视为新的投递
+	assert.False(t, deliver("delivery-2").Duplicate)
+
+	// 过期后同一个键重新处理
+	f.service.now = func() time.Time { return time.Now().Add(webhookIdempotencyTTL) }
+	assert.False(t, deliver("delivery-1").Duplicate)
+}
+
+func TestWebhookService_IdempotencyDerivedFromBody(t *testing.T) {
+	f := newDebounceFixture(t, -1)
+	body := `{"activityType":"memos.memo.created","memo":{"name":"memos/1","updateTime":"2026-01-02T12:00:00Z"}}`
+	deliver := func(path string) *WebhookResult {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", []byte(body)))
+		result, err := f.service.HandleMemosWebhook(context.Background(), req)
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.False(t, deliver("/api/webhooks/memos").Duplicate)
+	assert.True(t, deliver("/api/webhooks/memos").Duplicate, "a retried Memos delivery has the same body")
+	assert.Equal(t, 1, f.runCount(), "the retry does not sync again")
+
+	assert.False(t, deliver("/api/webhooks/memos?source=memos").Duplicate, "a different source is a different delivery")
+	assert.Equal(t, 2, f.runCount())
+}
+
+func TestWebhookService_IdempotentDuplicateWaitsForRunningDelivery(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		firstErr  error
+		wantCalls int
+		wantDup   bool
+	}{
+		{"first succeeds", nil, 1, true},
+		{"first fails", errors.New("sync failed"), 2, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewWebhookService(&conf.WebhookConfig{Enabled: true}, nil, nil)
+			started, release := make(chan struct{}), make(chan struct{})
+			var mu sync.Mutex
+			calls := 0
+			handle := func() (*WebhookResult, error) {
+				mu.Lock()
+				calls++
+				first := calls == 1
+				mu.Unlock()
+				if first {
+					close(started)
+					<-release
+					if tt.firstErr != nil {
+						return nil, tt.firstErr
+					}
+				}
+				return &WebhookResult{Accepted: true, Event: "sync"}, nil
+			}
+
+			firstDone := make(chan error, 1)
+			go func() {
+				_, err := s.idempotent(context.Background(), "k", handle)
+				firstDone <- err
+			}()
+			<-started
+
+			type outcome struct {
+				result *WebhookResult
+				err    error
+			}
+			second := make(chan outcome, 1)
+			go func() {
+				result, err := s.idempotent(context.Background(), "k", handle)
+				second <- outcome{result, err}
+			}()
+
+			select {
+			case <-second:
+				t.Fatal("a duplicate must wait while the first delivery is running")
+			case <-time.After(50 * time.Millisecond):
+			}
+			close(release)
+
+			assert.Equal(t, tt.firstErr, <-firstDone)
+			got := <-second
+			require.NoError(t, got.err)
+			assert.Equal(t, tt.wantDup, got.result.Duplicate)
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestWebhookService_GenericIdempotency(t *testing.T) {
+	f := newDebounceFixture(t, -1)
+	deliver := func(key string) *WebhookResult {
+		body := `{"source":"memos"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/generic", strings.NewReader(body))
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload("secret", []byte(body)))
+		if key != "" {
+			req.Header.Set(WebhookIdempotencyHeader, key)
+		}
+		result, err := f.service.HandleGenericWebhook(context.Background(), req)
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.False(t, deliver("").Duplicate)
+	assert.False(t, deliver("").Duplicate, "without a key the same body syncs again")
+	assert.Equal(t, 2, f.runCount())
+
+	assert.False(t, deliver("k").Duplicate)
+	assert.True(t, deliver("k").Duplicate)
+	assert.Equal(t, 3, f.runCount())
+}
+
+// telegramWebhookSyncClient is a Telegram source that buffers the text of
+// each webhook update as a post, like social.TelegramClient.
+type telegramWebhookSyncClient struct {
+	*fakeSyncClient
+	secret string
+}
+
+func (f *telegramWebhookSyncClient) WebhookSecret() string { return f.secret }
+
+func (f *telegramWebhookSyncClient) HandleWebhookUpdate(_ context.Context, body []byte) error {
+	var update struct {
+		ID          int `json:"update_id"`
+		ChannelPost struct {
+			ID   int    `json:"message_id"`
+			Text string `json:"text"`
+		} `json:"channel_post"`
+	}
+	if err := json.Unmarshal(body, &update); err != nil {
+		return err
+	}
+	f.posts = append(f.posts, &social.Post{ID: strconv.Itoa(update.ChannelPost.ID), Content: update.ChannelPost.Text, CreatedAt: time.Now()})
+	return nil
+}
+
+func TestWebhookService_Telegram(t *testing.T) {
+	source := &telegramWebhookSyncClient{fakeSyncClient: &fakeSyncClient{name: "tg"}, secret: "tg-secret"}
+	target := &fakeSyncClient{name: "mastodon"}
+	syncService := newTestSyncService(newMemoryPostDao(), source, target)
+	syncService.locker = dao.NewMemoryLocker()
+
+	configs := map[string]*social.PlatformConfig{
+		"tg": {Type: "telegram", SyncTo: []string{"mastodon"}},
+	}
+	var runs int
+	s := NewWebhookService(&conf.WebhookConfig{Enabled: true, DebounceWindow: -1}, configs, func(name string, targets []string) (*SyncService, error) {
+		assert.Equal(t, "tg", name)
+		assert.Equal(t, []string{"mastodon"}, targets)
+		runs++
+		return syncService, nil
+	})
+	s.SetSocialClients(func() (map[string]social.SocialClient, error) {
+		return map[string]social.SocialClient{"tg": source, "mastodon": target}, nil
+	})
+
+	deliver := func(secret, body string) (*WebhookResult, error) {
+		req := httptest.NewRequest(http.MethodPost, "/api/webhooks/telegram", strings.NewReader(body))
+		if secret != "" {
+			req.Header.Set(social.TelegramWebhookSecretHeader, secret)
+		}
+		return s.HandleTelegramWebhook(context.Background(), req)
+	}
+	update := `{"update_id":10,"channel_post":{"message_id":42,"date":1000,"text":"hello","chat":{"id":-100,"type":"channel"}}}`
+
+	result, err := deliver("tg-secret", update)
+	require.NoError(t, err)
+	assert.True(t, result.Accepted)
+	assert.Equal(t, TelegramWebhookEvent, result.Event)
+	assert.Equal(t, []string{"tg"}, result.Sources)
+	assert.Equal(t, []string{"42"}, target.postedIDs())
+
+	result, err = deliver("tg-secret", update)
+	require.NoError(t, err)
+	assert.True(t, result.Duplicate, "Telegram redelivers an update until it is acknowledged")
+	assert.Equal(t, 1, runs)
+
+	_, err = deliver("", update)
+	assert.ErrorIs(t, err, ErrWebhookSignatureMissing)
+	_, err = deliver("wrong", update)
+	assert.ErrorIs(t, err, ErrWebhookUnauthorized)
+	_, err = deliver("tg-secret", `{`)
+	assert.ErrorIs(t, err, ErrInvalidWebhookPayload)
+}
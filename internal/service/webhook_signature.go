@@ -0,0 +1,111 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+)
+
+var (
+	ErrWebhookSignatureMissing = errors.New("webhook signature missing")
+	ErrWebhookSignatureInvalid = errors.New("webhook signature invalid")
+)
+
+// signatureLocation is a header a scheme reads and the prefix its value
+// carries before the signature itself.
+type signatureLocation struct {
+	header string
+	prefix string
+}
+
+// defaultSignatureLocations lists, per scheme, the headers tried in order
+// when webhook.signature_header is not set.
+var defaultSignatureLocations = map[conf.SignatureScheme][]signatureLocation{
+	conf.SignatureHMACSHA256: {
+		{header: WebhookSignatureHeader, prefix: "sha256="},
+		{header: "X-Hub-Signature-256", prefix: "sha256="},
+	},
+	conf.SignatureHMACSHA1: {
+		{header: "X-Hub-Signature", prefix: "sha1="},
+	},
+	conf.SignatureSharedSecret: {
+		{header: "X-Gitlab-Token"},
+	},
+}
+
+// VerifyWebhookSignature checks that an incoming webhook request was signed
+// with cfg.Secret according to cfg.SignatureScheme. All comparisons are
+// constant-time.
+func VerifyWebhookSignature(cfg *conf.WebhookConfig, header http.Header, body []byte) error {
+	if cfg == nil || cfg.Secret == "" {
+		return errors.New("webhook secret not configured")
+	}
+
+	scheme := cfg.SignatureScheme
+	if scheme == "" {
+		scheme = conf.SignatureHMACSHA256
+	}
+	locations, ok := defaultSignatureLocations[scheme]
+	if !ok {
+		return fmt.Errorf("unknown webhook signature scheme %q", scheme)
+	}
+	if cfg.SignatureHeader != "" {
+		// A custom header carries exactly the configured prefix, which may
+		// be empty for sources that send a bare signature.
+		locations = []signatureLocation{{header: cfg.SignatureHeader, prefix: cfg.SignaturePrefix}}
+	} else if cfg.SignaturePrefix != "" {
+		overridden := make([]signatureLocation, len(locations))
+		for i, loc := range locations {
+			overridden[i] = signatureLocation{header: loc.header, prefix: cfg.SignaturePrefix}
+		}
+		locations = overridden
+	}
+
+	for _, loc := range locations {
+		value := header.Get(loc.header)
+		if value == "" {
+			continue
+		}
+		if !strings.HasPrefix(value, loc.prefix) {
+			return ErrWebhookSignatureInvalid
+		}
+		if !signatureMatches(scheme, cfg.Secret, strings.TrimPrefix(value, loc.prefix), body) {
+			return ErrWebhookSignatureInvalid
+		}
+		return nil
+	}
+	return ErrWebhookSignatureMissing
+}
+
+func signatureMatches(scheme conf.SignatureScheme, secret, signature string, body []byte) bool {
+	var newHash func() hash.Hash
+	switch scheme {
+	case conf.SignatureSharedSecret:
+		// Compare digests so the comparison time does not depend on the
+		// secret's length either.
+		want := sha256.Sum256([]byte(secret))
+		got := sha256.Sum256([]byte(signature))
+		return subtle.ConstantTimeCompare(want[:], got[:]) == 1
+	case conf.SignatureHMACSHA1:
+		newHash = sha1.New
+	default:
+		newHash = sha256.New
+	}
+
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), got)
+}
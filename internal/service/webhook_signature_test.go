@@ -0,0 +1,150 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.orx.me/apps/hyper-sync/internal/conf"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	const secret = "webhook-secret"
+	body := []byte(`{"event":"push"}`)
+	tampered := []byte(`{"event":"push","evil":true}`)
+
+	sha1Mac := hmac.New(sha1.New, []byte(secret))
+	sha1Mac.Write(body)
+	sha1Sig := hex.EncodeToString(sha1Mac.Sum(nil))
+
+	// bare hex of SignWebhookPayload, for custom header configs
+	sha256Sig := SignWebhookPayload(secret, body)[len("sha256="):]
+
+	tests := []struct {
+		name    string
+		cfg     conf.WebhookConfig
+		header  http.Header
+		body    []byte
+		wantErr error
+	}{
+		{
+			name:   "hmac-sha256 default header",
+			cfg:    conf.WebhookConfig{Secret: secret},
+			header: http.Header{"X-Webhook-Signature": {SignWebhookPayload(secret, body)}},
+			body:   body,
+		},
+		{
+			name:   "hmac-sha256 github header",
+			cfg:    conf.WebhookConfig{Secret: secret, SignatureScheme: conf.SignatureHMACSHA256},
+			header: http.Header{"X-Hub-Signature-256": {"sha256=" + sha256Sig}},
+			body:   body,
+		},
+		{
+			name:    "hmac-sha256 tampered body",
+			cfg:     conf.WebhookConfig{Secret: secret},
+			header:  http.Header{"X-Webhook-Signature": {SignWebhookPayload(secret, body)}},
+			body:    tampered,
+			wantErr: ErrWebhookSignatureInvalid,
+		},
+		{
+			name:    "hmac-sha256 wrong prefix",
+			cfg:     conf.WebhookConfig{Secret: secret},
+			header:  http.Header{"X-Webhook-Signature": {"sha1=" + sha256Sig}},
+			body:    body,
+			wantErr: ErrWebhookSignatureInvalid,
+		},
+		{
+			name:   "hmac-sha1",
+			cfg:    conf.WebhookConfig{Secret: secret, SignatureScheme: conf.SignatureHMACSHA1},
+			header: http.Header{"X-Hub-Signature": {"sha1=" + sha1Sig}},
+			body:   body,
+		},
+		{
+			name:    "hmac-sha1 tampered body",
+			cfg:     conf.WebhookConfig{Secret: secret, SignatureScheme: conf.SignatureHMACSHA1},
+			header:  http.Header{"X-Hub-Signature": {"sha1=" + sha1Sig}},
+			body:    tampered,
+			wantErr: ErrWebhookSignatureInvalid,
+		},
+		{
+			name:   "shared-secret gitlab token",
+			cfg:    conf.WebhookConfig{Secret: secret, SignatureScheme: conf.SignatureSharedSecret},
+			header: http.Header{"X-Gitlab-Token": {secret}},
+			body:   tampered, // the body is not covered by a shared secret
+		},
+		{
+			name:    "shared-secret wrong token",
+			cfg:     conf.WebhookConfig{Secret: secret, SignatureScheme: conf.SignatureSharedSecret},
+			header:  http.Header{"X-Gitlab-Token": {secret + "x"}},
+			body:    body,
+			wantErr: ErrWebhookSignatureInvalid,
+		},
+		{
+			name: "custom header without prefix",
+			cfg: conf.WebhookConfig{
+				Secret:          secret,
+				SignatureHeader: "X-Signature",
+			},
+			header: http.Header{"X-Signature": {sha256Sig}},
+			body:   body,
+		},
+		{
+			name: "custom header with prefix, tampered body",
+			cfg: conf.WebhookConfig{
+				Secret:          secret,
+				SignatureHeader: "X-Signature",
+				SignaturePrefix: "v1=",
+			},
+			header:  http.Header{"X-Signature": {"v1=" + sha256Sig}},
+			body:    tampered,
+			wantErr: ErrWebhookSignatureInvalid,
+		},
+		{
+			name: "custom header ignores default headers",
+			cfg: conf.WebhookConfig{
+				Secret:          secret,
+				SignatureHeader: "X-Signature",
+			},
+			header:  http.Header{"X-Webhook-Signature": {SignWebhookPayload(secret, body)}},
+			body:    body,
+			wantErr: ErrWebhookSignatureMissing,
+		},
+		{
+			name:    "missing signature",
+			cfg:     conf.WebhookConfig{Secret: secret},
+			header:  http.Header{},
+			body:    body,
+			wantErr: ErrWebhookSignatureMissing,
+		},
+		{
+			name:    "wrong secret",
+			cfg:     conf.WebhookConfig{Secret: "other-secret"},
+			header:  http.Header{"X-Webhook-Signature": {SignWebhookPayload(secret, body)}},
+			body:    body,
+			wantErr: ErrWebhookSignatureInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyWebhookSignature(&tt.cfg, tt.header, tt.body)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestVerifyWebhookSignature_Misconfigured(t *testing.T) {
+	header := http.Header{"X-Webhook-Signature": {SignWebhookPayload("", nil)}}
+
+	assert.Error(t, VerifyWebhookSignature(nil, header, nil))
+	assert.Error(t, VerifyWebhookSignature(&conf.WebhookConfig{}, header, nil), "empty secret must not verify")
+	assert.Error(t, VerifyWebhookSignature(&conf.WebhookConfig{Secret: "s", SignatureScheme: "md5"}, header, nil))
+}
@@ -7,29 +7,93 @@ import (
 	"image"
 	"image/jpeg"
 	"image/png"
+	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"butterfly.orx.me/core/log"
 	"github.com/davhofer/botsky/pkg/botsky"
+	"github.com/davhofer/indigo/api/bsky"
+	lexutil "github.com/davhofer/indigo/lex/util"
+	"go.orx.me/apps/hyper-sync/internal/metrics"
 )
 
 // BlueskyClient 使用 botsky 库的 Bluesky 客户端
 type BlueskyClient struct {
 	name   string
 	client *botsky.Client
+
+	// linkCards 控制是否为以链接为主的帖子附加外部链接卡片
+	linkCards  bool
+	httpClient *http.Client
+
+	// gifMode/videoMode 见 SetMediaModes
+	gifMode   string
+	videoMode string
+	// stripMetadata 为 true 时所有 JPEG/PNG 都重新编码，去掉 EXIF（含 GPS）等元数据
+	stripMetadata bool
+	// lengthPolicy 决定超出字数上限的正文截断还是拆成串，见 SetLengthPolicy
+	lengthPolicy
+
+	// publish 把准备好的帖子交给 botsky，测试中可替换
+	publish func(ctx context.Context, post *blueskyPost) (cid, uri string, err error)
+	// uploadBlob 上传链接卡片缩略图，测试中可替换
+	uploadBlob func(ctx context.Context, data []byte) (*lexutil.LexBlob, error)
+	// deletePost/getOwnPosts 供 DeletePostAndVerify 删除并回查帖子，测试中可替换
+	deletePost  func(ctx context.Context, rkey string) error
+	getOwnPosts func(ctx context.Context, limit int) ([]*botsky.RichPost, error)
+
+	// sessionMu 防止重新认证时与正在进行的请求并发修改 botsky 会话；
+	// 发帖等请求持读锁，EnsureValidToken 持写锁
+	sessionMu       sync.RWMutex
+	authenticate    func(ctx context.Context) error
+	authenticatedAt time.Time
+	now             func() time.Time
+}
+
+// blueskySessionMaxAge is how long a session is used before EnsureValidToken
+// re-authenticates. PDS access JWTs live about two hours; the token refresh
+// scheduler runs every 10 minutes, so this renews well before expiry.
+const blueskySessionMaxAge = 90 * time.Minute
+
+// blueskyAccessTokenTTL is the access JWT lifetime bsky.social issues; botsky
+// does not expose the token's exp claim, so expiry is estimated from it.
+const blueskyAccessTokenTTL = 2 * time.Hour
+
+// blueskyPost 是媒体处理完成后要发布的内容。帖子只能带一种 embed：
+// images 与 external 至多一个非空
+type blueskyPost struct {
+	text   string
+	images []botsky.ImageSource
+	// external 是外部链接卡片（链接卡片或视频链接），由我们自己构造，不经 botsky 抓取
+	external *bsky.EmbedExternal
+	// replyTo 是父帖的 at:// URI；botsky 据此查出父帖并设置 root/parent 引用
+	replyTo string
 }
 
+// blueskyJPEGQuality 是缩放或去除元数据时重新编码 JPEG 的质量
+const blueskyJPEGQuality = 85
+
 // 定义 Bluesky 的文件大小限制（976KB）
 const BlueskyMaxFileSize = 976 * 1024 // 976KB in bytes
 
 // resizeImageIfNeeded 如果图片超过Bluesky限制则调整大小
-func resizeImageIfNeeded(data []byte, maxSize int) ([]byte, error) {
+func resizeImageIfNeeded(data []byte, maxSize int) (_ []byte, err error) {
 	if len(data) <= maxSize {
 		return data, nil // 文件已经在限制内
 	}
 
+	start := time.Now()
+	defer func() {
+		result := metrics.StatusSuccess
+		if err != nil {
+			result = metrics.StatusError
+		}
+		metrics.RecordMediaResize(PlatformBluesky.String(), result, time.Since(start))
+	}()
+
 	// 检测图片格式
 	contentType := detectImageFormat(data)
 	if contentType == "" {
@@ -86,7 +150,7 @@ func resizeImageIfNeeded(data []byte, maxSize int) ([]byte, error) {
 
 	// 编码新图片
 	var buf bytes.Buffer
-	var quality int = 85 // JPEG 质量
+	quality := blueskyJPEGQuality
 
 	switch contentType {
 	case "image/jpeg":
@@ -139,8 +203,9 @@ func detectImageFormat(data []byte) string {
 	return ""
 }
 
-// NewBlueskyClient 创建一个新的Bluesky客户端
-func NewBlueskyClient(host, handle, password string, name string) (*BlueskyClient, error) {
+// NewBlueskyClient 创建一个新的Bluesky客户端。httpClients 用于抓取链接卡片等出站请求，
+// 为 nil 时使用默认的超时与 transport
+func NewBlueskyClient(host, handle, password string, name string, httpClients *HTTPClientFactory) (*BlueskyClient, error) {
 	ctx := context.Background()
 
 	// 使用 botsky 库创建客户端
@@ -155,10 +220,58 @@ func NewBlueskyClient(host, handle, password string, name string) (*BlueskyClien
 		return nil, fmt.Errorf("failed to authenticate with Bluesky: %w", err)
 	}
 
-	return &BlueskyClient{
-		name:   name,
-		client: client,
-	}, nil
+	b := &BlueskyClient{
+		name:            name,
+		client:          client,
+		linkCards:       true,
+		httpClient:      httpClientsOrDefault(httpClients).Client(),
+		gifMode:         BlueskyGIFPassthrough,
+		videoMode:       BlueskyVideoLink,
+		stripMetadata:   true,
+		authenticate:    client.Authenticate,
+		authenticatedAt: time.Now(),
+		now:             time.Now,
+	}
+	b.publish = b.publishViaBotsky
+	b.uploadBlob = b.uploadBlobViaBotsky
+	b.deletePost = b.DeletePost
+	b.getOwnPosts = b.getOwnPostsViaBotsky
+	return b, nil
+}
+
+// EnsureValidToken 在会话接近过期时用 app password 重新认证，
+// 获取新的 access/refresh JWT
+func (b *BlueskyClient) EnsureValidToken(ctx context.Context) error {
+	logger := log.FromContext(ctx).With("method", "BlueskyClient.EnsureValidToken")
+
+	b.sessionMu.Lock()
+	defer b.sessionMu.Unlock()
+
+	age := b.now().Sub(b.authenticatedAt)
+	if age < blueskySessionMaxAge {
+		logger.Debug("bluesky session still fresh", "client", b.name, "age", age)
+		return nil
+	}
+
+	logger.Info("re-authenticating bluesky session", "client", b.name, "age", age)
+	if err := b.authenticate(ctx); err != nil {
+		logger.Error("failed to re-authenticate with bluesky", "client", b.name, "error", err)
+		return fmt.Errorf("failed to re-authenticate with Bluesky: %w", err)
+	}
+	b.authenticatedAt = b.now()
+	return nil
+}
+
+// TokenExpiresAt 估算当前 access JWT 的过期时间（认证时间 + 2 小时）
+func (b *BlueskyClient) TokenExpiresAt(_ context.Context) (time.Time, bool, error) {
+	b.sessionMu.RLock()
+	defer b.sessionMu.RUnlock()
+	return b.authenticatedAt.Add(blueskyAccessTokenTTL), true, nil
+}
+
+// SetLinkCardsEnabled toggles external link card embeds on posts.
+func (c *BlueskyClient) SetLinkCardsEnabled(enabled bool) {
+	c.linkCards = enabled
 }
 
 func (c *BlueskyClient) Name() string {
@@ -179,37 +292,68 @@ func NewBlueskyClientFromEnv() (*BlueskyClient, error) {
 
 	// botsky 使用 app password 而不是普通密码
 	// 如果用户提供的是 app password，直接使用；否则假设是 app password
-	return NewBlueskyClient("", handle, password, PlatformBluesky.String())
+	return NewBlueskyClient("", handle, password, PlatformBluesky.String(), nil)
 }
 
-// Post 发布一条Bluesky帖子
+// Post 发布一条Bluesky帖子，超出字数上限时按 lengthPolicy 截断或拆成串
 func (b *BlueskyClient) Post(ctx context.Context, post *Post) (interface{}, error) {
+	return b.postWithinLimit(ctx, PlatformBluesky, post, b.publishPost)
+}
+
+// publishPost 发布单条帖子
+func (b *BlueskyClient) publishPost(ctx context.Context, post *Post) (interface{}, error) {
 	logger := log.FromContext(ctx)
 
 	// Check if visibility level is supported for Bluesky
-	if post.Visibility.IsValid() {
-		if !IsVisibilityLevelSupported(PlatformBluesky.String(), post.Visibility) {
-			logger.Info("visibility level not supported for bluesky",
-				"visibility", post.Visibility)
-			return nil, fmt.Errorf("visibility %s is not supported by platform %s", post.Visibility.String(), PlatformBluesky.String())
-		}
+	if err := checkVisibility(PlatformBluesky, post.Visibility); err != nil {
+		logger.Info("visibility level not supported for bluesky",
+			"visibility", post.Visibility)
+		return nil, err
+	}
+	if err := ValidateMedia(PlatformBluesky.String(), post.Media); err != nil {
+		return nil, err
 	}
 
 	logger.Info("creating bluesky post",
 		"content_length", len(post.Content),
 		"media_count", len(post.Media))
 
-	// 使用 botsky 的 PostBuilder 创建帖子
-	pb := botsky.NewPostBuilder(post.Content)
+	bp := &blueskyPost{text: post.Content}
+	if post.InReplyTo != "" {
+		parentURI, err := b.postURI(post.InReplyTo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve reply parent %s: %w", post.InReplyTo, err)
+		}
+		bp.replyTo = parentURI
+		logger.Info("posting as reply", "parent_uri", parentURI)
+	}
 
 	// 处理媒体附件
 	if len(post.Media) > 0 {
 		logger.Info("processing media attachments", "count", len(post.Media))
 
-		var images []botsky.ImageSource
-		for i, media := range post.Media {
+		var videoURL string
+		for i := range post.Media {
+			media := &post.Media[i]
+
+			// botsky 只能上传图片：视频按 video_mode 处理，不进入图片缩放流程
+			if media.IsVideo() {
+				switch {
+				case b.videoMode == BlueskyVideoSkip:
+					logger.Info("skipping video attachment", "index", i)
+				case media.GetURL() == "":
+					logger.Warn("video attachment has no URL to link, skipping", "index", i)
+				case videoURL != "":
+					logger.Warn("only one video can be linked, skipping", "index", i)
+				default:
+					videoURL = media.GetURL()
+					logger.Info("linking video attachment", "index", i, "url", videoURL)
+				}
+				continue
+			}
+
 			// 获取媒体数据
-			mediaData, err := media.GetData()
+			mediaData, err := media.GetDataFor(PlatformBluesky.String())
 			if err != nil {
 				logger.Error("failed to get media data",
 					"index", i,
@@ -222,8 +366,13 @@ func (b *BlueskyClient) Post(ctx context.Context, post *Post) (interface{}, erro
 				"original_size", len(mediaData),
 				"max_allowed_size", BlueskyMaxFileSize)
 
-			// 检查并调整图片大小如果超过Bluesky限制
-			processedData, err := resizeImageIfNeeded(mediaData, BlueskyMaxFileSize)
+			// GIF 按 gif_mode 处理；其他图片按需去除元数据，超过Bluesky限制时调整大小
+			var processedData []byte
+			if contentType, _ := media.ContentType(); contentType == "image/gif" {
+				processedData, err = prepareBlueskyGIF(mediaData, b.gifMode, BlueskyMaxFileSize)
+			} else {
+				processedData, err = prepareBlueskyImage(mediaData, BlueskyMaxFileSize, b.stripMetadata)
+			}
 			if err != nil {
 				logger.Error("failed to resize image",
 					"index", i,
@@ -245,7 +394,8 @@ func (b *BlueskyClient) Post(ctx context.Context, post *Post) (interface{}, erro
 			}
 
 			// 创建临时文件来存储媒体数据，因为 botsky 需要文件路径或URL
-			tmpFile, err := os.CreateTemp("", fmt.Sprintf("hypersync_media_%d_*.jpg", i))
+			// botsky 按文件扩展名判断类型，使用检测到的扩展名而不是一律 .jpg
+			tmpFile, err := os.CreateTemp("", fmt.Sprintf("hypersync_media_%d_*%s", i, mediaExtension(normalizeContentType(http.DetectContentType(processedData)))))
 			if err != nil {
 				logger.Error("failed to create temp file",
 					"index", i,
@@ -269,7 +419,7 @@ func (b *BlueskyClient) Post(ctx context.Context, post *Post) (interface{}, erro
 				Uri: tmpFile.Name(),
 				Alt: media.Description,
 			}
-			images = append(images, imageSource)
+			bp.images = append(bp.images, imageSource)
 
 			logger.Info("prepared media for upload",
 				"index", i,
@@ -284,13 +434,22 @@ func (b *BlueskyClient) Post(ctx context.Context, post *Post) (interface{}, erro
 			}(tmpFile.Name())
 		}
 
-		// 添加图像到帖子
-		pb = pb.AddImages(images)
+		// 帖子只能带一种 embed，图片优先于视频链接
+		if videoURL != "" {
+			if len(bp.images) > 0 {
+				logger.Warn("post has images, dropping video link", "url", videoURL)
+			} else {
+				bp.external = b.externalEmbed(ctx, &LinkCard{URL: videoURL, Title: videoURL})
+			}
+		}
+	} else if card := b.resolveLinkCard(ctx, post); card != nil {
+		// 帖子只能带一种 embed，因此只在没有图片时附加链接卡片
+		bp.external = b.externalEmbed(ctx, card)
 	}
 
 	// 发布帖子
 	logger.Info("posting to bluesky via botsky")
-	cid, uri, err := b.client.Post(ctx, pb)
+	cid, uri, err := b.publish(ctx, bp)
 	if err != nil {
 		logger.Error("failed to post via botsky", "error", err)
 		return nil, fmt.Errorf("failed to post to Bluesky: %w", err)
@@ -315,6 +474,91 @@ func (b *BlueskyClient) Post(ctx context.Context, post *Post) (interface{}, erro
 	}, nil
 }
 
+// publishViaBotsky 用 botsky 的 PostBuilder 创建并发布帖子；带外部链接卡片的
+// 帖子由 publishWithEmbed 自行构造记录
+func (b *BlueskyClient) publishViaBotsky(ctx context.Context, post *blueskyPost) (string, string, error) {
+	if post.external != nil && len(post.images) == 0 {
+		return b.publishWithEmbed(ctx, post)
+	}
+
+	pb := botsky.NewPostBuilder(post.text)
+	if post.replyTo != "" {
+		pb = pb.ReplyTo(post.replyTo)
+	}
+	if len(post.images) > 0 {
+		pb = pb.AddImages(post.images)
+	}
+
+	b.sessionMu.RLock()
+	defer b.sessionMu.RUnlock()
+	return b.client.Post(ctx, pb)
+}
+
+// postURI 把 rkey 补全为本账号帖子的 at:// URI；已是 URI 的原样返回
+func (b *BlueskyClient) postURI(id string) (string, error) {
+	if strings.HasPrefix(id, "at://") {
+		return id, nil
+	}
+	if b.client == nil || b.client.Did == "" {
+		return "", fmt.Errorf("client DID not available")
+	}
+	return fmt.Sprintf("at://%s/app.bsky.feed.post/%s", b.client.Did, id), nil
+}
+
+// SourceURL 返回帖子在 bsky.app 上的链接：https://bsky.app/profile/<did>/post/<rkey>
+func (b *BlueskyClient) SourceURL(post *Post) string {
+	id := postOriginalID(post)
+	if id == "" {
+		return ""
+	}
+	uri, err := b.postURI(id)
+	if err != nil {
+		return ""
+	}
+	return blueskyWebURL(uri)
+}
+
+// blueskyWebURL 把 at://did/app.bsky.feed.post/rkey 转换为 bsky.app 链接
+func blueskyWebURL(uri string) string {
+	did, rest, ok := strings.Cut(strings.TrimPrefix(uri, "at://"), "/app.bsky.feed.post/")
+	if !ok || did == "" || rest == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://bsky.app/profile/%s/post/%s", did, rest)
+}
+
+// resolveLinkCard 确定帖子的链接卡片。优先使用 post.LinkCard，否则从内容中
+// 找出唯一的主导链接。抓取失败只记录日志并返回 nil，不阻塞发帖。
+func (b *BlueskyClient) resolveLinkCard(ctx context.Context, post *Post) *LinkCard {
+	logger := log.FromContext(ctx)
+
+	if !b.linkCards {
+		return nil
+	}
+
+	link := post.LinkCard
+	if link == "" {
+		link = dominantURL(post.Content)
+	}
+	if link == "" {
+		return nil
+	}
+
+	card, err := fetchLinkCard(ctx, b.httpClient, link)
+	if err != nil {
+		logger.Warn("failed to fetch link card, posting without it",
+			"url", link,
+			"error", err)
+		return nil
+	}
+
+	logger.Info("attaching link card",
+		"url", card.URL,
+		"title", card.Title,
+		"has_image", card.ImageURL != "")
+	return card
+}
+
 // Delete implements SocialDeleter by delegating to DeletePost.
 func (b *BlueskyClient) Delete(ctx context.Context, platformID string) error {
 	return b.DeletePost(ctx, platformID)
@@ -340,7 +584,9 @@ func (b *BlueskyClient) DeletePost(ctx context.Context, rkey string) error {
 	logger.Info("constructed post URI for deletion", "uri", postUri)
 
 	// 使用 botsky 的删除方法
+	b.sessionMu.RLock()
 	err := b.client.RepoDeletePost(ctx, postUri)
+	b.sessionMu.RUnlock()
 	if err != nil {
 		logger.Error("failed to delete post via botsky", "error", err, "uri", postUri)
 		return fmt.Errorf("failed to delete post: %w", err)
@@ -350,6 +596,72 @@ func (b *BlueskyClient) DeletePost(ctx context.Context, rkey string) error {
 	return nil
 }
 
+// blueskyDeleteVerifyInterval is how often DeletePostAndVerify checks
+// whether a deleted post is still listed.
+const blueskyDeleteVerifyInterval = 500 * time.Millisecond
+
+// blueskyDeleteVerifyLimit is how many recent posts DeletePostAndVerify
+// looks through. An older post cannot be checked and counts as gone.
+const blueskyDeleteVerifyLimit = 100
+
+// DeletePostAndVerify deletes a post like DeletePost, then polls the
+// account's recent posts until it no longer appears, since a deletion can
+// take a while to reach the AppView. It returns an error if the post is
+// still listed after timeout.
+func (b *BlueskyClient) DeletePostAndVerify(ctx context.Context, rkey string, timeout time.Duration) error {
+	logger := log.FromContext(ctx)
+
+	if err := b.deletePost(ctx, rkey); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for attempt := 1; ; attempt++ {
+		posts, err := b.getOwnPosts(ctx, blueskyDeleteVerifyLimit)
+		if err != nil {
+			return fmt.Errorf("failed to verify deletion of %s: %w", rkey, err)
+		}
+		if !blueskyPostListed(posts, rkey) {
+			logger.Info("verified bluesky post deletion", "rkey", rkey, "attempts", attempt)
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("bluesky post %s still listed %s after deletion", rkey, timeout)
+		}
+		wait := min(blueskyDeleteVerifyInterval, remaining)
+		logger.Debug("deleted bluesky post still listed, waiting", "rkey", rkey, "attempt", attempt, "wait", wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// blueskyPostListed reports whether posts contains the post with rkey.
+func blueskyPostListed(posts []*botsky.RichPost, rkey string) bool {
+	for _, post := range posts {
+		if strings.HasSuffix(post.Uri, "/app.bsky.feed.post/"+rkey) {
+			return true
+		}
+	}
+	return false
+}
+
+// getOwnPostsViaBotsky lists the account's recent posts. Unlike ListPosts it
+// reports server errors instead of returning an empty list, which would look
+// like a completed deletion.
+func (b *BlueskyClient) getOwnPostsViaBotsky(ctx context.Context, limit int) ([]*botsky.RichPost, error) {
+	if b.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+	b.sessionMu.RLock()
+	defer b.sessionMu.RUnlock()
+	return b.client.GetPosts(ctx, b.client.Did, limit)
+}
+
 // ListPosts 获取当前用户的最新帖子
 func (b *BlueskyClient) ListPosts(ctx context.Context, limit int) ([]*Post, error) {
 	logger := log.FromContext(ctx)
@@ -367,7 +679,9 @@ func (b *BlueskyClient) ListPosts(ctx context.Context, limit int) ([]*Post, erro
 
 	// 使用 GetPosts 方法获取当前用户的帖子
 	// 如果遇到服务器错误，我们提供优雅的处理
+	b.sessionMu.RLock()
 	richPosts, err := b.client.GetPosts(ctx, b.client.Did, limit)
+	b.sessionMu.RUnlock()
 	if err != nil {
 		logger.Error("failed to get posts via botsky", "error", err)
 
@@ -408,11 +722,14 @@ func (b *BlueskyClient) convertRichPostsToInternalPosts(ctx context.Context, ric
 			createdAt = t
 		}
 
-		// 转换为我们的 Post 结构
+		// 转换为我们的 Post 结构。OriginalID 用 at:// URI，与跨发到 Bluesky 时
+		// 记录的 PlatformID 一致，多源同步据此识别镜像帖
 		post := &Post{
 			ID:             rkey,
 			Content:        richPost.Text,
 			SourcePlatform: PlatformBluesky.String(),
+			OriginalID:     richPost.Uri,
+			SourceURL:      blueskyWebURL(richPost.Uri),
 			CreatedAt:      createdAt,
 		}
 
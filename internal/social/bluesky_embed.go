@@ -0,0 +1,227 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"butterfly.orx.me/core/log"
+	"github.com/davhofer/indigo/api/atproto"
+	"github.com/davhofer/indigo/api/bsky"
+	lexutil "github.com/davhofer/indigo/lex/util"
+)
+
+// 外部链接卡片（app.bsky.embed.external）。botsky 的 AddEmbedLink 会在发帖时不带 ctx
+// 重新抓取页面，抓取失败整条帖子都会失败；这里用已经抓到的 LinkCard 构造 embed、
+// 自行上传缩略图，任何一步失败只丢掉卡片，帖子照常发出。
+
+// linkCardThumbMaxBytes caps the card image download; it is resized to
+// BlueskyMaxFileSize before upload.
+const linkCardThumbMaxBytes = 5 << 20
+
+// blueskyDefaultLangs matches botsky's default post language.
+var blueskyDefaultLangs = []string{"en"}
+
+var (
+	// blueskyHandlePattern 匹配 @handle 提及，handle 为域名形式
+	blueskyHandlePattern = regexp.MustCompile(`(?:^|[^a-zA-Z0-9])(@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*\.[a-zA-Z]{2,10})`)
+	// blueskyHashtagPattern 匹配 #tag，不以数字开头
+	blueskyHashtagPattern = regexp.MustCompile(`(?:^|\s)(#[^\d\s]\S*)`)
+)
+
+// externalEmbed builds the external embed for card, uploading the card image
+// as its thumbnail. It returns nil when the thumbnail cannot be fetched or
+// uploaded, so the post goes out without a card rather than failing.
+func (b *BlueskyClient) externalEmbed(ctx context.Context, card *LinkCard) *bsky.EmbedExternal {
+	logger := log.FromContext(ctx)
+
+	embed := &bsky.EmbedExternal{
+		LexiconTypeID: "app.bsky.embed.external",
+		External: &bsky.EmbedExternal_External{
+			Uri:         card.URL,
+			Title:       card.Title,
+			Description: card.Description,
+		},
+	}
+	if card.ImageURL == "" {
+		return embed
+	}
+
+	thumb, err := b.linkCardThumb(ctx, card)
+	if err != nil {
+		logger.Warn("failed to attach link card thumbnail, posting without the card",
+			"url", card.URL,
+			"image_url", card.ImageURL,
+			"error", err)
+		return nil
+	}
+	embed.External.Thumb = thumb
+	return embed
+}
+
+// linkCardThumb downloads the card image, fits it within Bluesky's blob
+// limit and uploads it.
+func (b *BlueskyClient) linkCardThumb(ctx context.Context, card *LinkCard) (*lexutil.LexBlob, error) {
+	imageURL := card.ImageURL
+	// og:image 可能是相对地址
+	if base, err := url.Parse(card.URL); err == nil {
+		if ref, err := url.Parse(imageURL); err == nil {
+			imageURL = base.ResolveReference(ref).String()
+		}
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, linkCardFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(fetchCtx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("link card thumb: build request: %w", err)
+	}
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("link card thumb: fetch %s: %w", imageURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("link card thumb: fetch %s: status code %d", imageURL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, linkCardThumbMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("link card thumb: read %s: %w", imageURL, err)
+	}
+	if len(data) > linkCardThumbMaxBytes {
+		return nil, fmt.Errorf("link card thumb: %s: %w", imageURL, ErrMediaTooLarge)
+	}
+	if detectImageFormat(data) == "" {
+		return nil, fmt.Errorf("link card thumb: %s is not a JPEG or PNG image", imageURL)
+	}
+
+	data, err = prepareBlueskyImage(data, BlueskyMaxFileSize, b.stripMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("link card thumb: %w", err)
+	}
+	return b.uploadBlob(ctx, data)
+}
+
+// uploadBlobViaBotsky uploads data with botsky's RepoUploadImage, which only
+// reads from a path or URL, through a temp file.
+func (b *BlueskyClient) uploadBlobViaBotsky(ctx context.Context, data []byte) (*lexutil.LexBlob, error) {
+	tmpFile, err := os.CreateTemp("", "hypersync_thumb_*"+mediaExtension(normalizeContentType(http.DetectContentType(data))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file for thumbnail: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(data)
+	tmpFile.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write thumbnail to temp file: %w", err)
+	}
+
+	// RepoUploadImage 的参数类型未导出，借助类型推断取得它的零值再填字段
+	image := zeroArg(b.client.RepoUploadImage)
+	image.Uri = url.URL{Path: tmpFile.Name()}
+
+	b.sessionMu.RLock()
+	defer b.sessionMu.RUnlock()
+	return b.client.RepoUploadImage(ctx, image)
+}
+
+// zeroArg returns the zero value of fn's argument type.
+func zeroArg[T any](fn func(context.Context, T) (*lexutil.LexBlob, error)) T {
+	var zero T
+	return zero
+}
+
+// publishWithEmbed creates the post record itself so it can carry an embed
+// botsky's PostBuilder would refetch. Facets and the reply reference are
+// built the way botsky builds them.
+func (b *BlueskyClient) publishWithEmbed(ctx context.Context, post *blueskyPost) (string, string, error) {
+	b.sessionMu.RLock()
+	defer b.sessionMu.RUnlock()
+
+	record := bsky.FeedPost{
+		LexiconTypeID: "app.bsky.feed.post",
+		Text:          post.text,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+		Langs:         blueskyDefaultLangs,
+		Facets:        blueskyFacets(ctx, post.text, b.client.ResolveHandle),
+		Embed:         &bsky.FeedPost_Embed{EmbedExternal: post.external},
+	}
+
+	if post.replyTo != "" {
+		parent, cid, err := b.client.RepoGetPostAndCid(ctx, post.replyTo)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to get reply parent %s: %w", post.replyTo, err)
+		}
+		root := &atproto.RepoStrongRef{Uri: post.replyTo, Cid: cid}
+		if parent.Reply != nil && parent.Reply.Root != nil {
+			root = &atproto.RepoStrongRef{Uri: parent.Reply.Root.Uri, Cid: parent.Reply.Root.Cid}
+		}
+		record.Reply = &bsky.FeedPost_ReplyRef{
+			Parent: &atproto.RepoStrongRef{Uri: post.replyTo, Cid: cid},
+			Root:   root,
+		}
+	}
+
+	return b.client.RepoCreatePostRecord(ctx, record)
+}
+
+// blueskyFacets returns the rich text facets for links, hashtags and
+// mentions in text. Byte offsets are used as the facet index requires.
+// Mentions whose handle does not resolve are left as plain text.
+func blueskyFacets(ctx context.Context, text string, resolveHandle func(context.Context, string) (string, error)) []*bsky.RichtextFacet {
+	var facets []*bsky.RichtextFacet
+	facet := func(start, end int, feature *bsky.RichtextFacet_Features_Elem) {
+		facets = append(facets, &bsky.RichtextFacet{
+			Index:    &bsky.RichtextFacet_ByteSlice{ByteStart: int64(start), ByteEnd: int64(end)},
+			Features: []*bsky.RichtextFacet_Features_Elem{feature},
+		})
+	}
+
+	for _, m := range blueskyHandlePattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[2], m[3]
+		did, err := resolveHandle(ctx, text[start+1:end])
+		if err != nil {
+			continue
+		}
+		facet(start, end, &bsky.RichtextFacet_Features_Elem{
+			RichtextFacet_Mention: &bsky.RichtextFacet_Mention{
+				LexiconTypeID: "app.bsky.richtext.facet#mention",
+				Did:           did,
+			},
+		})
+	}
+
+	for _, m := range urlPattern.FindAllStringIndex(text, -1) {
+		link := strings.TrimRight(text[m[0]:m[1]], ".,;:!?")
+		facet(m[0], m[0]+len(link), &bsky.RichtextFacet_Features_Elem{
+			RichtextFacet_Link: &bsky.RichtextFacet_Link{
+				LexiconTypeID: "app.bsky.richtext.facet#link",
+				Uri:           link,
+			},
+		})
+	}
+
+	for _, m := range blueskyHashtagPattern.FindAllStringSubmatchIndex(text, -1) {
+		tag := strings.TrimRight(text[m[2]:m[3]], ".,;:!?")
+		if len(tag) < 2 {
+			continue
+		}
+		facet(m[2], m[2]+len(tag), &bsky.RichtextFacet_Features_Elem{
+			RichtextFacet_Tag: &bsky.RichtextFacet_Tag{
+				LexiconTypeID: "app.bsky.richtext.facet#tag",
+				Tag:           tag[1:],
+			},
+		})
+	}
+	return facets
+}
@@ -0,0 +1,120 @@
+package social
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// GIF 处理方式（bluesky.gif_mode）
+const (
+	// BlueskyGIFPassthrough 在大小限制内原样上传 GIF，超出时退回首帧
+	BlueskyGIFPassthrough = "passthrough"
+	// BlueskyGIFFirstFrame 总是只上传首帧（PNG 静态图）
+	BlueskyGIFFirstFrame = "first_frame"
+)
+
+// 视频处理方式（bluesky.video_mode）
+const (
+	// BlueskyVideoLink 把视频 URL 作为外部链接 embed 附加；没有 URL 的视频被跳过
+	BlueskyVideoLink = "link"
+	// BlueskyVideoSkip 直接丢弃视频，只发布文字与图片
+	BlueskyVideoSkip = "skip"
+)
+
+func isValidBlueskyGIFMode(mode string) bool {
+	switch mode {
+	case "", BlueskyGIFPassthrough, BlueskyGIFFirstFrame:
+		return true
+	}
+	return false
+}
+
+func isValidBlueskyVideoMode(mode string) bool {
+	switch mode {
+	case "", BlueskyVideoLink, BlueskyVideoSkip:
+		return true
+	}
+	return false
+}
+
+// SetMediaModes sets how GIFs and videos are posted; an empty mode keeps the
+// default (passthrough GIFs, link videos). botsky can only upload images, so
+// videos never go through the image pipeline.
+func (c *BlueskyClient) SetMediaModes(gifMode, videoMode string) {
+	if gifMode == "" {
+		gifMode = BlueskyGIFPassthrough
+	}
+	if videoMode == "" {
+		videoMode = BlueskyVideoLink
+	}
+	c.gifMode = gifMode
+	c.videoMode = videoMode
+}
+
+// SetStripMetadata 控制上传前是否总是重新编码图片以去除 EXIF 等元数据。
+// 超过大小限制的图片缩放时本就会重新编码；开启后未超限的图片也不再原样上传
+func (c *BlueskyClient) SetStripMetadata(enabled bool) {
+	c.stripMetadata = enabled
+}
+
+// prepareBlueskyImage 返回要上传的 JPEG/PNG 数据：需要时先去除元数据，
+// 再缩放到 maxSize 以内
+func prepareBlueskyImage(data []byte, maxSize int, stripMetadata bool) ([]byte, error) {
+	if stripMetadata && len(data) <= maxSize {
+		stripped, err := stripImageMetadata(data)
+		if err != nil {
+			return nil, err
+		}
+		// 重新编码可能比原图更大，交给下面的缩放兜底
+		data = stripped
+	}
+	return resizeImageIfNeeded(data, maxSize)
+}
+
+// stripImageMetadata 通过解码再编码丢弃 EXIF/XMP 等元数据：image/jpeg 与
+// image/png 的编码器只写出像素数据。无法识别的格式原样返回
+func stripImageMetadata(data []byte) ([]byte, error) {
+	contentType := detectImageFormat(data)
+	if contentType == "" {
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if contentType == "image/png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: blueskyJPEGQuality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// prepareBlueskyGIF 返回要上传的 GIF 数据：passthrough 模式下未超限的 GIF
+// 原样返回（保留动画），否则取首帧编码为 PNG，再按需缩放
+func prepareBlueskyGIF(data []byte, mode string, maxSize int) ([]byte, error) {
+	if mode != BlueskyGIFFirstFrame && len(data) <= maxSize {
+		return data, nil
+	}
+
+	// gif.Decode 只解码第一帧
+	frame, err := gif.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode gif: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, frame); err != nil {
+		return nil, fmt.Errorf("failed to encode gif first frame: %w", err)
+	}
+	return resizeImageIfNeeded(buf.Bytes(), maxSize)
+}
@@ -0,0 +1,389 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/davhofer/botsky/pkg/botsky"
+	lexutil "github.com/davhofer/indigo/lex/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueskyClient_EnsureValidToken(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	authCalls := 0
+	var authErr error
+	client := &BlueskyClient{
+		name: "bluesky",
+		authenticate: func(context.Context) error {
+			authCalls++
+			return authErr
+		},
+		authenticatedAt: now,
+		now:             func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	// A fresh session is left alone.
+	now = now.Add(blueskySessionMaxAge - time.Minute)
+	require.NoError(t, client.EnsureValidToken(ctx))
+	assert.Equal(t, 0, authCalls)
+
+	// Near expiry the session is re-authenticated...
+	now = now.Add(time.Minute)
+	require.NoError(t, client.EnsureValidToken(ctx))
+	assert.Equal(t, 1, authCalls)
+	assert.Equal(t, now, client.authenticatedAt)
+
+	// ...and then fresh again.
+	require.NoError(t, client.EnsureValidToken(ctx))
+	assert.Equal(t, 1, authCalls)
+
+	// A failed re-authentication is reported and retried on the next call.
+	now = now.Add(blueskySessionMaxAge)
+	authErr = errors.New("invalid app password")
+	err := client.EnsureValidToken(ctx)
+	assert.ErrorIs(t, err, authErr)
+	authErr = nil
+	require.NoError(t, client.EnsureValidToken(ctx))
+	assert.Equal(t, 3, authCalls)
+}
+
+// animatedGIF encodes a two-frame 4x4 GIF: red, then blue.
+func animatedGIF(t *testing.T) []byte {
+	t.Helper()
+	palette := color.Palette{color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}}
+	anim := &gif.GIF{}
+	for i := range palette {
+		frame := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+		for p := range frame.Pix {
+			frame.Pix[p] = uint8(i)
+		}
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, anim))
+	return buf.Bytes()
+}
+
+// postToBluesky runs Post with botsky stubbed out and returns what would
+// have been published, reading each image while its temp file still exists.
+func postToBluesky(t *testing.T, client *BlueskyClient, post *Post) (*blueskyPost, [][]byte) {
+	t.Helper()
+	var published *blueskyPost
+	var files [][]byte
+	client.publish = func(_ context.Context, bp *blueskyPost) (string, string, error) {
+		published = bp
+		for _, img := range bp.images {
+			data, err := os.ReadFile(img.Uri)
+			require.NoError(t, err)
+			files = append(files, data)
+		}
+		return "cid", "at://did:plc:me/app.bsky.feed.post/rkey1", nil
+	}
+
+	result, err := client.Post(context.Background(), post)
+	require.NoError(t, err)
+	assert.Equal(t, "rkey1", result.(map[string]interface{})["rkey"])
+	require.NotNil(t, published)
+	return published, files
+}
+
+func TestBlueskyClient_Post_GIFPassthrough(t *testing.T) {
+	client := &BlueskyClient{name: "bluesky"}
+	client.SetMediaModes("", "")
+	data := animatedGIF(t)
+
+	published, files := postToBluesky(t, client, &Post{
+		Content: "animated",
+		Media:   []Media{*NewMedia(data)},
+	})
+
+	require.Len(t, published.images, 1)
+	assert.Equal(t, ".gif", filepath.Ext(published.images[0].Uri))
+	assert.Equal(t, data, files[0], "a GIF within the size limit should be uploaded untouched")
+}
+
+func TestBlueskyClient_Post_GIFFirstFrame(t *testing.T) {
+	client := &BlueskyClient{name: "bluesky"}
+	client.SetMediaModes(BlueskyGIFFirstFrame, "")
+
+	published, files := postToBluesky(t, client, &Post{
+		Content: "animated",
+		Media:   []Media{*NewMedia(animatedGIF(t))},
+	})
+
+	require.Len(t, published.images, 1)
+	assert.Equal(t, ".png", filepath.Ext(published.images[0].Uri))
+	frame, err := png.Decode(bytes.NewReader(files[0]))
+	require.NoError(t, err)
+	r, g, b, _ := frame.At(0, 0).RGBA()
+	assert.Equal(t, [3]uint32{0xffff, 0, 0}, [3]uint32{r, g, b}, "should keep the first (red) frame")
+}
+
+func TestBlueskyClient_Post_VideoLink(t *testing.T) {
+	// Larger than BlueskyMaxFileSize: the image pipeline would reject it.
+	video := append(append([]byte{}, mp4Signature...), make([]byte, BlueskyMaxFileSize)...)
+	var gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+		if r.Method == http.MethodGet {
+			gets++
+			_, _ = w.Write(video)
+		}
+	}))
+	defer server.Close()
+
+	client := &BlueskyClient{name: "bluesky"}
+	client.SetMediaModes("", "")
+	videoURL := server.URL + "/clip.mp4"
+
+	published, _ := postToBluesky(t, client, &Post{
+		Content: "watch this",
+		Media:   []Media{*NewMediaFromURL(videoURL)},
+	})
+
+	assert.Empty(t, published.images)
+	require.NotNil(t, published.external)
+	assert.Equal(t, videoURL, published.external.External.Uri)
+	assert.Nil(t, published.external.External.Thumb)
+	assert.Zero(t, gets, "a linked video should not be downloaded")
+}
+
+func TestBlueskyClient_Post_VideoSkip(t *testing.T) {
+	client := &BlueskyClient{name: "bluesky"}
+	client.SetMediaModes("", BlueskyVideoSkip)
+
+	published, _ := postToBluesky(t, client, &Post{
+		Content: "text survives",
+		Media:   []Media{*NewMedia(mp4Signature)},
+	})
+
+	assert.Equal(t, "text survives", published.text)
+	assert.Empty(t, published.images)
+	assert.Nil(t, published.external)
+}
+
+// linkCardServer serves an article page whose og:image is thumbPath.
+func linkCardServer(t *testing.T, thumbPath string, thumb []byte) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><head>
+<meta property="og:title" content="An article">
+<meta property="og:description" content="About things">
+<meta property="og:image" content="` + thumbPath + `">
+</head></html>`))
+	})
+	if thumb != nil {
+		mux.HandleFunc(thumbPath, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write(thumb)
+		})
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBlueskyClient_Post_LinkCard(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8))))
+	server := linkCardServer(t, "/thumb.png", buf.Bytes())
+
+	var uploaded [][]byte
+	client := &BlueskyClient{name: "bluesky", linkCards: true, httpClient: server.Client()}
+	client.uploadBlob = func(_ context.Context, data []byte) (*lexutil.LexBlob, error) {
+		uploaded = append(uploaded, data)
+		return &lexutil.LexBlob{MimeType: "image/png", Size: int64(len(data))}, nil
+	}
+
+	link := server.URL + "/article"
+	published, _ := postToBluesky(t, client, &Post{Content: "worth reading " + link})
+
+	require.NotNil(t, published.external, "the card we fetched should be embedded")
+	assert.Equal(t, link, published.external.External.Uri)
+	assert.Equal(t, "An article", published.external.External.Title)
+	assert.Equal(t, "About things", published.external.External.Description)
+	require.NotNil(t, published.external.External.Thumb)
+	assert.Len(t, uploaded, 1, "the relative og:image should be fetched and uploaded once")
+}
+
+func TestBlueskyClient_Post_LinkCardThumbFailureDropsCard(t *testing.T) {
+	server := linkCardServer(t, "/missing.png", nil)
+
+	client := &BlueskyClient{name: "bluesky", linkCards: true, httpClient: server.Client()}
+	client.uploadBlob = func(context.Context, []byte) (*lexutil.LexBlob, error) {
+		t.Fatal("nothing should be uploaded")
+		return nil, nil
+	}
+
+	published, _ := postToBluesky(t, client, &Post{Content: "worth reading " + server.URL + "/article"})
+
+	assert.Nil(t, published.external, "a card whose thumbnail fails is dropped")
+	assert.Contains(t, published.text, "worth reading", "the post itself is still published")
+}
+
+func TestBlueskyFacets(t *testing.T) {
+	text := "hi @alice.bsky.social and @nobody.example.com, see https://example.com/a. #golang #2024"
+	resolve := func(_ context.Context, handle string) (string, error) {
+		if handle == "alice.bsky.social" {
+			return "did:plc:alice", nil
+		}
+		return "", errors.New("unable to resolve handle")
+	}
+
+	facets := blueskyFacets(context.Background(), text, resolve)
+
+	require.Len(t, facets, 3)
+	mention := facets[0]
+	assert.Equal(t, "did:plc:alice", mention.Features[0].RichtextFacet_Mention.Did)
+	assert.Equal(t, "@alice.bsky.social", text[mention.Index.ByteStart:mention.Index.ByteEnd])
+
+	link := facets[1]
+	assert.Equal(t, "https://example.com/a", link.Features[0].RichtextFacet_Link.Uri)
+	assert.Equal(t, "https://example.com/a", text[link.Index.ByteStart:link.Index.ByteEnd])
+
+	tag := facets[2]
+	assert.Equal(t, "golang", tag.Features[0].RichtextFacet_Tag.Tag)
+	assert.Equal(t, "#golang", text[tag.Index.ByteStart:tag.Index.ByteEnd])
+}
+
+func TestBlueskyClient_Post_Reply(t *testing.T) {
+	client := &BlueskyClient{name: "bluesky", client: &botsky.Client{Did: "did:plc:me"}}
+
+	first, _ := postToBluesky(t, client, &Post{Content: "first"})
+	assert.Empty(t, first.replyTo)
+
+	// SyncService passes the parent's stored platform ID, which is its URI.
+	second, _ := postToBluesky(t, client, &Post{
+		Content:   "second",
+		InReplyTo: "at://did:plc:me/app.bsky.feed.post/rkey1",
+	})
+	assert.Equal(t, "at://did:plc:me/app.bsky.feed.post/rkey1", second.replyTo)
+
+	// A bare rkey is resolved against the account's DID.
+	third, _ := postToBluesky(t, client, &Post{Content: "third", InReplyTo: "rkey2"})
+	assert.Equal(t, "at://did:plc:me/app.bsky.feed.post/rkey2", third.replyTo)
+}
+
+// jpegWithEXIF encodes a small JPEG and splices an APP1 EXIF segment carrying
+// a fake GPS tag in right after the SOI marker, as cameras do.
+func jpegWithEXIF(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	encoded := buf.Bytes()
+
+	payload := []byte("Exif\x00\x00GPSLatitude=52.37")
+	segment := []byte{0xFF, 0xE1, byte((len(payload) + 2) >> 8), byte(len(payload) + 2)}
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, encoded[:2]...)
+	out = append(out, segment...)
+	out = append(out, encoded[2:]...)
+	_, err := jpeg.Decode(bytes.NewReader(out))
+	require.NoError(t, err, "the spliced JPEG should still decode")
+	return out
+}
+
+func TestPrepareBlueskyImage_StripsEXIF(t *testing.T) {
+	data := jpegWithEXIF(t)
+	require.True(t, bytes.Contains(data, []byte{0xFF, 0xE1}))
+
+	out, err := prepareBlueskyImage(data, BlueskyMaxFileSize, true)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(out, []byte{0xFF, 0xE1}), "output should have no APP1 segment")
+	assert.False(t, bytes.Contains(out, []byte("Exif")))
+	assert.False(t, bytes.Contains(out, []byte("GPS")))
+	_, err = jpeg.Decode(bytes.NewReader(out))
+	assert.NoError(t, err)
+
+	// With stripping disabled, an image under the limit is passed through.
+	out, err = prepareBlueskyImage(data, BlueskyMaxFileSize, false)
+	require.NoError(t, err)
+	assert.Equal(t, data, out)
+}
+
+func TestBlueskyClient_Post_StripsEXIF(t *testing.T) {
+	client := &BlueskyClient{name: "bluesky"}
+	client.SetStripMetadata(true)
+
+	_, files := postToBluesky(t, client, &Post{
+		Content: "photo",
+		Media:   []Media{*NewMedia(jpegWithEXIF(t))},
+	})
+
+	require.Len(t, files, 1)
+	assert.False(t, bytes.Contains(files[0], []byte("Exif")), "uploaded photo should carry no EXIF")
+}
+
+// fakeBlueskyFeed stands in for botsky in DeletePostAndVerify: the deleted
+// post stays listed for the first staleReads reads.
+type fakeBlueskyFeed struct {
+	deleted    []string
+	reads      int
+	staleReads int
+}
+
+func (f *fakeBlueskyFeed) install(client *BlueskyClient) {
+	client.deletePost = func(_ context.Context, rkey string) error {
+		f.deleted = append(f.deleted, rkey)
+		return nil
+	}
+	client.getOwnPosts = func(_ context.Context, _ int) ([]*botsky.RichPost, error) {
+		f.reads++
+		posts := []*botsky.RichPost{{Uri: "at://did:plc:me/app.bsky.feed.post/other"}}
+		if f.reads <= f.staleReads {
+			posts = append(posts, &botsky.RichPost{Uri: "at://did:plc:me/app.bsky.feed.post/rkey1"})
+		}
+		return posts, nil
+	}
+}
+
+func TestBlueskyClient_DeletePostAndVerify(t *testing.T) {
+	client := &BlueskyClient{name: "bluesky"}
+	feed := &fakeBlueskyFeed{staleReads: 1}
+	feed.install(client)
+
+	require.NoError(t, client.DeletePostAndVerify(context.Background(), "rkey1", 5*time.Second))
+	assert.Equal(t, []string{"rkey1"}, feed.deleted)
+	assert.Equal(t, 2, feed.reads, "present once, then gone")
+}
+
+func TestBlueskyClient_DeletePostAndVerify_Timeout(t *testing.T) {
+	client := &BlueskyClient{name: "bluesky"}
+	feed := &fakeBlueskyFeed{staleReads: 1000}
+	feed.install(client)
+
+	err := client.DeletePostAndVerify(context.Background(), "rkey1", 50*time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "still listed")
+	assert.Equal(t, 2, feed.reads, "one read, one after the remaining timeout")
+}
+
+func TestBlueskyClient_DeletePostAndVerify_DeleteFails(t *testing.T) {
+	client := &BlueskyClient{name: "bluesky"}
+	feed := &fakeBlueskyFeed{}
+	feed.install(client)
+	deleteErr := errors.New("record not found")
+	client.deletePost = func(context.Context, string) error { return deleteErr }
+
+	err := client.DeletePostAndVerify(context.Background(), "rkey1", time.Second)
+	assert.ErrorIs(t, err, deleteErr)
+	assert.Zero(t, feed.reads)
+}
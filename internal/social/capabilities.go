@@ -0,0 +1,27 @@
+package social
+
+// PlatformCapabilities describes what a platform type supports, independent
+// of how an instance is configured.
+type PlatformCapabilities struct {
+	// Media reports whether Post uploads or links the post's media.
+	Media bool
+	// ListPosts reports whether the platform can be used as a sync source.
+	ListPosts bool
+}
+
+// SupportedCapabilities lists the capabilities of each platform type.
+// Deleting is not listed: a client supports it when it implements
+// SocialDeleter.
+var SupportedCapabilities = map[Platform]PlatformCapabilities{
+	PlatformMastodon:  {Media: true, ListPosts: true},
+	PlatformBluesky:   {Media: true, ListPosts: true},
+	PlatformThreads:   {Media: true},
+	PlatformMemos:     {ListPosts: true},
+	PlatformTelegram:  {Media: true, ListPosts: true},
+	PlatformNostr:     {Media: true},
+	PlatformDiscord:   {Media: true},
+	PlatformMatrix:    {Media: true},
+	PlatformWordPress: {Media: true},
+	PlatformMicroblog: {Media: true},
+	PlatformRSS:       {ListPosts: true},
+}
@@ -13,15 +13,51 @@ type PlatformConfig struct {
 	SyncFromPlatforms []string `yaml:"sync_from_platforms"` // 允许从哪些平台同步内容
 	SyncCategories    []string `yaml:"sync_categories"`     // 要同步的内容类别
 
-	Mastodon *MastodonConfig `yaml:"mastodon,omitempty"` // Mastodon 特定配置
-	Bluesky  *BlueskyConfig  `yaml:"bluesky,omitempty"`  // Bluesky 特定配置
-	Memos    *MemosConfig    `yaml:"memos,omitempty"`    // Memos 特定配置
-	Threads  *ThreadsConfig  `yaml:"threads,omitempty"`  // Threads 特定配置
-	Telegram *TelegramConfig `yaml:"telegram,omitempty"`
+	Mastodon  *MastodonConfig  `yaml:"mastodon,omitempty"` // Mastodon 特定配置
+	Bluesky   *BlueskyConfig   `yaml:"bluesky,omitempty"`  // Bluesky 特定配置
+	Memos     *MemosConfig     `yaml:"memos,omitempty"`    // Memos 特定配置
+	Threads   *ThreadsConfig   `yaml:"threads,omitempty"`  // Threads 特定配置
+	Telegram  *TelegramConfig  `yaml:"telegram,omitempty"`
+	Nostr     *NostrConfig     `yaml:"nostr,omitempty"`     // Nostr 特定配置
+	Discord   *DiscordConfig   `yaml:"discord,omitempty"`   // Discord 特定配置
+	Matrix    *MatrixConfig    `yaml:"matrix,omitempty"`    // Matrix 特定配置
+	WordPress *WordPressConfig `yaml:"wordpress,omitempty"` // WordPress 特定配置
+	Microblog *MicroblogConfig `yaml:"microblog,omitempty"` // Micro.blog 特定配置
+	RSS       *RSSConfig       `yaml:"rss,omitempty"`       // RSS/Atom 源配置
+
+	// Template 在发布到本平台前改写内容（text/template），为空则原样发布
+	Template *TemplateConfig `yaml:"template,omitempty"`
+	// Normalize 发布到本平台前依次应用的内容清理规则（见 NormalizeRules），如
+	// [strip_hashtags, task_lists, strip_memo_resources]；为空则不清理
+	Normalize []string `yaml:"normalize,omitempty"`
+	// LinkShortener 清理内容时用外部短链服务缩短链接，适合字数上限紧的平台（如 Bluesky）；为空则不缩短
+	LinkShortener *LinkShortenerConfig `yaml:"link_shortener,omitempty"`
+	// Footer 追加在正文末尾的模板（如 "via my blog {{.SourceURL}}"），
+	// 超出平台字数上限时截断正文而保留 footer
+	Footer string `yaml:"footer,omitempty"`
+	// MaxChars 覆盖平台默认字数上限（见 PlatformLimits），如字数上限更高的 Mastodon 实例；0 使用默认值
+	MaxChars int `yaml:"max_chars,omitempty"`
+	// Overflow 正文超出上限时的处理方式：truncate（默认，截断并以 … 结尾）或 thread（拆成多条，依次回复成串）
+	Overflow string `yaml:"overflow,omitempty"`
+	// VisibilityMap 覆盖帖子可见性到本平台的映射，如 {unlisted: private}；
+	// 值为 "skip" 表示该可见性的帖子不发到本平台，未列出的可见性保持默认行为
+	VisibilityMap map[VisibilityLevel]string `yaml:"visibility_map,omitempty"`
+	// UnsupportedVisibilityPolicy 帖子可见性（经 VisibilityMap 映射后）本平台不支持时的处理方式：
+	// error（默认，跨发失败）、skip（跳过，不算失败）或 downgrade（改用最接近的受支持可见性）
+	UnsupportedVisibilityPolicy string `yaml:"unsupported_visibility,omitempty"`
+	// PostRateLimit 本平台作为目标时的发帖速率限制（令牌桶），为空则不限制
+	PostRateLimit *PostRateLimitConfig `yaml:"post_rate_limit,omitempty"`
 
 	// SyncDelay is how long after a post's CreatedAt before cross-posting
 	// begins. Gives the author time to edit or delete before content fans out.
 	SyncDelay time.Duration `yaml:"sync_delay"`
+	// SyncInterval 本平台作为源时的同步轮询间隔，为空则使用 sync.interval
+	SyncInterval time.Duration `yaml:"sync_interval"`
+	// FetchLimit 本平台作为源时每轮拉取的帖子数，为空则使用 sync.batch_size（默认 100）
+	FetchLimit int `yaml:"fetch_limit"`
+	// TokenRefreshThreshold token 剩余有效期不超过该值时刷新，为空则为 7 天；
+	// 目前只对 Threads 生效
+	TokenRefreshThreshold time.Duration `yaml:"token_refresh_threshold"`
 }
 
 type MemosConfig struct {
@@ -33,6 +69,9 @@ type MemosConfig struct {
 type MastodonConfig struct {
 	Instance string `yaml:"instance"` // Mastodon 实例域名
 	Token    string `yaml:"token"`    // 访问令牌
+	// 作为源时是否同步转嘟（boost）与回复他人的嘟文，默认都不同步；自己的串总是同步
+	IncludeReblogs bool `yaml:"include_reblogs"`
+	IncludeReplies bool `yaml:"include_replies"`
 }
 
 // BlueskyConfig 包含 Bluesky 平台的特定配置
@@ -40,6 +79,14 @@ type BlueskyConfig struct {
 	Host     string `yaml:"host"`     // Bluesky 服务器
 	Handle   string `yaml:"handle"`   // 用户名
 	Password string `yaml:"password"` // 密码
+	// DisableLinkCard 关闭以链接为主的帖子的外部链接卡片
+	DisableLinkCard bool `yaml:"disable_link_card"`
+	// GIFMode GIF 的处理方式: "passthrough"（默认）或 "first_frame"
+	GIFMode string `yaml:"gif_mode"`
+	// VideoMode 视频的处理方式: "link"（默认）或 "skip"
+	VideoMode string `yaml:"video_mode"`
+	// StripMetadata 上传前重新编码图片以去除 EXIF（含 GPS）等元数据，未设置时为 true
+	StripMetadata *bool `yaml:"strip_metadata"`
 }
 
 type ThreadsConfig struct {
@@ -53,6 +100,70 @@ type ThreadsConfig struct {
 type TelegramConfig struct {
 	BotToken  string `yaml:"bot_token"`
 	ChannelID string `yaml:"channel_id"`
+	// ParseMode 发送消息时使用的格式: "MarkdownV2"、"HTML" 或留空（纯文本）
+	ParseMode string `yaml:"parse_mode"`
+	// AltTextInCaption 把媒体的 alt text 追加到 caption（Telegram 没有真正的 alt text 字段）
+	AltTextInCaption bool `yaml:"alt_text_in_caption"`
+	// WebhookURL 设置后用 webhook（setWebhook）接收更新而不是长轮询，
+	// 应指向 HyperSync 的 /api/webhooks/telegram
+	WebhookURL string `yaml:"webhook_url"`
+	// WebhookSecret 作为 secret_token 交给 Telegram，用于校验并区分推送来源；使用 webhook 时必填
+	WebhookSecret string `yaml:"webhook_secret"`
+}
+
+// NostrConfig 包含 Nostr 平台的特定配置
+type NostrConfig struct {
+	PrivateKey string   `yaml:"private_key"` // 私钥，nsec 或 64 位十六进制
+	Relays     []string `yaml:"relays"`      // 发布事件的 relay 地址列表（wss://...）
+}
+
+// DiscordConfig 包含 Discord webhook 的配置
+type DiscordConfig struct {
+	WebhookURL string `yaml:"webhook_url"` // Webhook 地址
+	Username   string `yaml:"username"`    // 覆盖 webhook 默认显示名（可选）
+	AvatarURL  string `yaml:"avatar_url"`  // 覆盖 webhook 默认头像（可选）
+}
+
+// MatrixConfig 包含 Matrix 房间发帖的配置
+type MatrixConfig struct {
+	Homeserver  string `yaml:"homeserver"`   // Homeserver 地址，如 https://matrix.org
+	AccessToken string `yaml:"access_token"` // 发帖账号的 access token
+	RoomID      string `yaml:"room_id"`      // 目标房间 ID，如 !abc:matrix.org
+}
+
+// WordPressConfig 包含 WordPress REST API 的配置
+type WordPressConfig struct {
+	SiteURL     string `yaml:"site_url"`     // 站点地址，如 https://blog.example.com
+	Username    string `yaml:"username"`     // 发帖用户名
+	AppPassword string `yaml:"app_password"` // 该用户的应用密码（Application Password）
+}
+
+// MicroblogConfig 包含 Micro.blog（Micropub 协议）的配置
+type MicroblogConfig struct {
+	Endpoint string `yaml:"endpoint"` // Micropub 端点，如 https://micro.blog/micropub
+	Token    string `yaml:"token"`    // App token
+}
+
+// TemplateConfig 定义发布到某个平台前的内容模板
+type TemplateConfig struct {
+	// Content 是 Go text/template，可用字段：.Content、.SourcePlatform、
+	// .SourceURL、.OriginalID、.CreatedAt；可用函数：truncate、trim
+	Content string `yaml:"content"`
+}
+
+// PostRateLimitConfig 定义向某个平台发帖的令牌桶
+type PostRateLimitConfig struct {
+	// Every 平均每隔多久补充一个令牌（即允许发一条），如 30s
+	Every time.Duration `yaml:"every"`
+	// Burst 令牌桶容量，即空闲后可连续发出的条数，为空则为 1
+	Burst int `yaml:"burst,omitempty"`
+	// Mode 令牌不足时的处理方式：wait（默认，在本轮等待令牌）或 skip（本轮跳过，下一轮再发）
+	Mode string `yaml:"mode,omitempty"`
+}
+
+// RSSConfig 包含 RSS/Atom 源的配置
+type RSSConfig struct {
+	FeedURL string `yaml:"feed_url"` // RSS 或 Atom 订阅地址
 }
 
 // ShouldSyncPost 判断是否应该将内容从源平台同步到目标平台
@@ -0,0 +1,113 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubClient is a SocialClient whose Post returns a fixed error (or an ID,
+// or nothing when noPost is set).
+type stubClient struct {
+	name   string
+	err    error
+	noPost bool
+	delay  time.Duration
+	calls  atomic.Int32
+}
+
+func (s *stubClient) Post(_ context.Context, _ *Post) (interface{}, error) {
+	s.calls.Add(1)
+	time.Sleep(s.delay)
+	if s.err != nil || s.noPost {
+		return nil, s.err
+	}
+	return map[string]string{"id": s.name + "-1"}, nil
+}
+
+func (s *stubClient) ListPosts(_ context.Context, _ int) ([]*Post, error) { return nil, nil }
+
+func (s *stubClient) Name() string { return s.name }
+
+func stubPlatform(client *stubClient) *SocialPlatform {
+	return &SocialPlatform{
+		Name:   client.name,
+		Client: client,
+		Config: &PlatformConfig{SyncEnabled: true, SyncFromPlatforms: []string{"*"}},
+	}
+}
+
+func TestCrossPost_AggregatesAllErrors(t *testing.T) {
+	errMastodon := errors.New("mastodon is down")
+	errBluesky := errors.New("bluesky rejected the post")
+
+	const delay = 100 * time.Millisecond
+	mastodon := &stubClient{name: "mastodon", err: errMastodon, delay: delay}
+	bluesky := &stubClient{name: "bluesky", err: errBluesky, delay: delay}
+	threads := &stubClient{name: "threads", delay: delay}
+
+	start := time.Now()
+	results, err := CrossPost(context.Background(), &Post{Content: "hi", SourcePlatform: "memos"},
+		[]*SocialPlatform{stubPlatform(mastodon), stubPlatform(bluesky), stubPlatform(threads)})
+	elapsed := time.Since(start)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errMastodon)
+	assert.ErrorIs(t, err, errBluesky)
+	assert.Contains(t, err.Error(), "failed to post to mastodon")
+	assert.Contains(t, err.Error(), "failed to post to bluesky")
+	assert.Less(t, elapsed, 2*delay, "platforms should be posted to concurrently")
+
+	require.Len(t, results, 3)
+	assert.Equal(t, false, results["mastodon"].(map[string]interface{})["success"])
+	assert.Equal(t, errMastodon.Error(), results["mastodon"].(map[string]interface{})["error"])
+	assert.Equal(t, false, results["bluesky"].(map[string]interface{})["success"])
+	assert.Equal(t, true, results["threads"].(map[string]interface{})["success"])
+}
+
+func TestCrossPost_SkipsSourceAndUnsyncedPlatforms(t *testing.T) {
+	memos := &stubClient{name: "memos"}
+	mastodon := &stubClient{name: "mastodon"}
+	disabled := &stubClient{name: "bluesky"}
+
+	disabledPlatform := stubPlatform(disabled)
+	disabledPlatform.Config.SyncEnabled = false
+
+	results, err := CrossPost(context.Background(), &Post{Content: "hi", SourcePlatform: "memos"},
+		[]*SocialPlatform{stubPlatform(memos), stubPlatform(mastodon), disabledPlatform})
+	require.NoError(t, err)
+
+	assert.Len(t, results, 1)
+	assert.Contains(t, results, "mastodon")
+	assert.Zero(t, memos.calls.Load(), "the source platform must not be posted to")
+	assert.Zero(t, disabled.calls.Load())
+}
+
+func TestCrossPost_RecordsSkippedPlatforms(t *testing.T) {
+	telegram := &stubClient{name: "telegram", err: fmt.Errorf("%w by platform telegram: unlisted", ErrVisibilityNotSupported)}
+	rss := &stubClient{name: "rss", noPost: true}
+	mastodon := &stubClient{name: "mastodon"}
+
+	results, err := CrossPost(context.Background(), &Post{Content: "hi", SourcePlatform: "memos"},
+		[]*SocialPlatform{stubPlatform(telegram), stubPlatform(rss), stubPlatform(mastodon)})
+	require.NoError(t, err, "skipped platforms are not failures")
+
+	require.Len(t, results, 3)
+	skipped := results["telegram"].(map[string]interface{})
+	assert.Equal(t, false, skipped["success"])
+	assert.Equal(t, true, skipped["skipped"])
+	assert.Contains(t, skipped["reason"], "not supported")
+
+	skipped = results["rss"].(map[string]interface{})
+	assert.Equal(t, true, skipped["skipped"])
+	assert.Equal(t, "platform returned no post", skipped["reason"])
+
+	assert.Equal(t, true, results["mastodon"].(map[string]interface{})["success"])
+	assert.Nil(t, results["mastodon"].(map[string]interface{})["skipped"])
+}
@@ -0,0 +1,237 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+
+	"butterfly.orx.me/core/log"
+)
+
+// Discord webhook limits.
+const (
+	discordContentLimit = 2000
+	discordMaxFiles     = 10
+	discordMaxEmbeds    = 10
+)
+
+// DiscordClient implements SocialClient for posting through a Discord
+// webhook. Webhooks are write-only, so it is a sync target only.
+type DiscordClient struct {
+	name       string
+	webhookURL string
+	username   string
+	avatarURL  string
+	httpClient *http.Client
+}
+
+// NewDiscordClient creates a client that posts to webhookURL. username and
+// avatarURL override the webhook's defaults when set. httpClients supplies
+// the timeout, proxy and TLS settings; nil uses the defaults.
+func NewDiscordClient(name, webhookURL, username, avatarURL string, httpClients *HTTPClientFactory) *DiscordClient {
+	return &DiscordClient{
+		name:       name,
+		webhookURL: webhookURL,
+		username:   username,
+		avatarURL:  avatarURL,
+		httpClient: httpClientsOrDefault(httpClients).Client(),
+	}
+}
+
+func (d *DiscordClient) Name() string { return d.name }
+
+type discordEmbed struct {
+	Description string             `json:"description,omitempty"`
+	Image       *discordEmbedImage `json:"image,omitempty"`
+}
+
+type discordEmbedImage struct {
+	URL string `json:"url"`
+}
+
+type discordAttachment struct {
+	ID          int    `json:"id"`
+	Filename    string `json:"filename"`
+	Description string `json:"description,omitempty"`
+}
+
+type discordWebhookPayload struct {
+	Content     string              `json:"content,omitempty"`
+	Username    string              `json:"username,omitempty"`
+	AvatarURL   string              `json:"avatar_url,omitempty"`
+	Embeds      []discordEmbed      `json:"embeds,omitempty"`
+	Attachments []discordAttachment `json:"attachments,omitempty"`
+}
+
+type discordFile struct {
+	name string
+	data []byte
+}
+
+type discordMessage struct {
+	ID string `json:"id"`
+}
+
+// Post sends the post through the webhook. Content longer than Discord's
+// 2000-character limit is split across several messages; media goes with
+// the last one, URL media as image embeds and data media as file uploads.
+// It returns the ID of the first message.
+func (d *DiscordClient) Post(ctx context.Context, post *Post) (interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	if err := checkVisibility(PlatformDiscord, post.Visibility); err != nil {
+		return nil, err
+	}
+	if err := ValidateMedia(PlatformDiscord.String(), post.Media); err != nil {
+		return nil, err
+	}
+
+	var embeds []discordEmbed
+	var files []discordFile
+	var attachments []discordAttachment
+	for i := range post.Media {
+		m := &post.Media[i]
+		if u := m.GetURL(); u != "" {
+			if len(embeds) >= discordMaxEmbeds {
+				logger.Warn("dropping media beyond discord embed limit",
+					"client", d.name,
+					"index", i)
+				continue
+			}
+			embeds = append(embeds, discordEmbed{Description: m.Description, Image: &discordEmbedImage{URL: u}})
+			continue
+		}
+
+		if len(files) >= discordMaxFiles {
+			logger.Warn("dropping media beyond discord attachment limit",
+				"client", d.name,
+				"index", i)
+			continue
+		}
+		data, err := m.GetDataFor(PlatformDiscord.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get media data: %w", err)
+		}
+		filename := fmt.Sprintf("media%d%s", len(files), m.Extension())
+		attachments = append(attachments, discordAttachment{ID: len(files), Filename: filename, Description: m.Description})
+		files = append(files, discordFile{name: filename, data: data})
+	}
+
+	chunks := splitContent(discordContentLimit, post.Content)
+	if len(chunks) == 0 {
+		chunks = []string{""}
+	}
+
+	var firstID string
+	for i, chunk := range chunks {
+		payload := discordWebhookPayload{
+			Content:   chunk,
+			Username:  d.username,
+			AvatarURL: d.avatarURL,
+		}
+		var chunkFiles []discordFile
+		if i == len(chunks)-1 {
+			payload.Embeds = embeds
+			payload.Attachments = attachments
+			chunkFiles = files
+		}
+		if payload.Content == "" && len(payload.Embeds) == 0 && len(chunkFiles) == 0 {
+			return nil, errors.New("discord: post has no content or media")
+		}
+
+		msg, err := d.execute(ctx, payload, chunkFiles)
+		if err != nil {
+			return nil, err
+		}
+		if firstID == "" {
+			firstID = msg.ID
+		}
+	}
+
+	logger.Info("posted to discord",
+		"client", d.name,
+		"message_id", firstID,
+		"messages", len(chunks),
+		"embeds", len(embeds),
+		"files", len(files))
+
+	return map[string]string{"id": firstID}, nil
+}
+
+func (d *DiscordClient) ListPosts(_ context.Context, _ int) ([]*Post, error) {
+	return nil, errors.New("discord: listing posts not supported by webhooks")
+}
+
+// execute sends a single webhook message. With files the payload is sent as
+// multipart/form-data (payload_json + files[n]), otherwise as plain JSON.
+// wait=true makes Discord return the created message so its ID is known.
+func (d *DiscordClient) execute(ctx context.Context, payload discordWebhookPayload, files []discordFile) (*discordMessage, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal discord payload: %w", err)
+	}
+
+	var body io.Reader = bytes.NewReader(jsonData)
+	contentType := "application/json"
+	if len(files) > 0 {
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		if err := w.WriteField("payload_json", string(jsonData)); err != nil {
+			return nil, fmt.Errorf("failed to write discord payload: %w", err)
+		}
+		for i, f := range files {
+			part, err := w.CreateFormFile(fmt.Sprintf("files[%d]", i), f.name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create discord file part: %w", err)
+			}
+			if _, err := part.Write(f.data); err != nil {
+				return nil, fmt.Errorf("failed to write discord file part: %w", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("failed to finish discord multipart body: %w", err)
+		}
+		body = &buf
+		contentType = w.FormDataContentType()
+	}
+
+	endpoint, err := url.Parse(d.webhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid discord webhook URL: %w", err)
+	}
+	q := endpoint.Query()
+	q.Set("wait", "true")
+	endpoint.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discord request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("discord webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := readResponseBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read discord response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &StatusError{Op: "discord webhook", StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	var msg discordMessage
+	if err := DecodeJSON(respBody, &msg); err != nil {
+		return nil, fmt.Errorf("failed to parse discord response: %w", err)
+	}
+	return &msg, nil
+}
@@ -0,0 +1,166 @@
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough of a PNG for http.DetectContentType.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type discordRequest struct {
+	contentType string
+	query       string
+	payload     discordWebhookPayload
+	files       map[string][]byte // form field -> content
+	filenames   map[string]string // form field -> filename
+}
+
+// fakeDiscordWebhook records each webhook execution and answers with a
+// message ID, like Discord does for wait=true.
+type fakeDiscordWebhook struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []discordRequest
+}
+
+func newFakeDiscordWebhook(t *testing.T) *fakeDiscordWebhook {
+	t.Helper()
+	f := &fakeDiscordWebhook{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := discordRequest{
+			contentType: r.Header.Get("Content-Type"),
+			query:       r.URL.RawQuery,
+			files:       map[string][]byte{},
+			filenames:   map[string]string{},
+		}
+
+		if strings.HasPrefix(rec.contentType, "multipart/form-data") {
+			require.NoError(t, r.ParseMultipartForm(10<<20))
+			require.NoError(t, json.Unmarshal([]byte(r.FormValue("payload_json")), &rec.payload))
+			for field, headers := range r.MultipartForm.File {
+				fh := headers[0]
+				file, err := fh.Open()
+				require.NoError(t, err)
+				data, _ := io.ReadAll(file)
+				file.Close()
+				rec.files[field] = data
+				rec.filenames[field] = fh.Filename
+			}
+		} else {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&rec.payload))
+		}
+
+		f.mu.Lock()
+		f.requests = append(f.requests, rec)
+		id := len(f.requests)
+		f.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": "msg-" + strconv.Itoa(id)})
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeDiscordWebhook) sent() []discordRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]discordRequest(nil), f.requests...)
+}
+
+func TestDiscord_PostText(t *testing.T) {
+	hook := newFakeDiscordWebhook(t)
+	client := NewDiscordClient("discord", hook.server.URL+"/api/webhooks/1/token", "HyperSync", "https://example.com/a.png", nil)
+
+	resp, err := client.Post(context.Background(), &Post{Content: "hello discord"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "msg-1"}, resp)
+
+	reqs := hook.sent()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "application/json", reqs[0].contentType)
+	assert.Equal(t, "wait=true", reqs[0].query)
+	assert.Equal(t, discordWebhookPayload{
+		Content:   "hello discord",
+		Username:  "HyperSync",
+		AvatarURL: "https://example.com/a.png",
+	}, reqs[0].payload)
+}
+
+func TestDiscord_PostSplitsLongContent(t *testing.T) {
+	hook := newFakeDiscordWebhook(t)
+	client := NewDiscordClient("discord", hook.server.URL, "", "", nil)
+
+	first := strings.Repeat("a", 1500)
+	second := strings.Repeat("b", 1500)
+	resp, err := client.Post(context.Background(), &Post{
+		Content: first + "\n" + second,
+		Media:   []Media{*NewMediaFromURL("https://cdn.example/x.png")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "msg-1"}, resp, "the first message identifies the post")
+
+	reqs := hook.sent()
+	require.Len(t, reqs, 2)
+	assert.Equal(t, first, reqs[0].payload.Content)
+	assert.Empty(t, reqs[0].payload.Embeds)
+	assert.Equal(t, second, reqs[1].payload.Content)
+	require.Len(t, reqs[1].payload.Embeds, 1, "media goes with the last message")
+	assert.Equal(t, "https://cdn.example/x.png", reqs[1].payload.Embeds[0].Image.URL)
+}
+
+func TestDiscord_PostUploadsMediaData(t *testing.T) {
+	hook := newFakeDiscordWebhook(t)
+	client := NewDiscordClient("discord", hook.server.URL, "", "", nil)
+
+	img := NewMedia(pngHeader)
+	img.Description = "a chart"
+	_, err := client.Post(context.Background(), &Post{
+		Content: "with image",
+		Media:   []Media{*img, *NewMediaFromURL("https://cdn.example/y.jpg")},
+	})
+	require.NoError(t, err)
+
+	reqs := hook.sent()
+	require.Len(t, reqs, 1)
+	req := reqs[0]
+	assert.True(t, strings.HasPrefix(req.contentType, "multipart/form-data"))
+	assert.Equal(t, "with image", req.payload.Content)
+	assert.Equal(t, []discordAttachment{{ID: 0, Filename: "media0.png", Description: "a chart"}}, req.payload.Attachments)
+	require.Len(t, req.payload.Embeds, 1)
+	assert.Equal(t, "https://cdn.example/y.jpg", req.payload.Embeds[0].Image.URL)
+
+	assert.Equal(t, pngHeader, req.files["files[0]"])
+	assert.Equal(t, "media0.png", req.filenames["files[0]"])
+}
+
+func TestDiscord_PostErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"You are being rate limited."}`))
+	}))
+	defer server.Close()
+
+	client := NewDiscordClient("discord", server.URL, "", "", nil)
+	_, err := client.Post(context.Background(), &Post{Content: "hi"})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, HTTPStatusCode(err))
+}
+
+func TestDiscord_ListPostsNotSupported(t *testing.T) {
+	client := NewDiscordClient("discord", "https://discord.example/hook", "", "", nil)
+	_, err := client.ListPosts(context.Background(), 10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package social
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mattn/go-mastodon"
+)
+
+// StatusError is returned when a platform API answers with a non-success
+// HTTP status, so callers can tell rate limits and outages apart from
+// permanent rejections.
+type StatusError struct {
+	Op         string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s failed with status %d: %s", e.Op, e.StatusCode, e.Body)
+}
+
+// HTTPStatusCode returns the HTTP status carried by err or anything it
+// wraps, or 0 if there is none.
+func HTTPStatusCode(err error) int {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	var mastodonErr *mastodon.APIError
+	if errors.As(err, &mastodonErr) {
+		return mastodonErr.StatusCode
+	}
+	return 0
+}
+
+// PlatformError is returned by platform clients when an API call fails with
+// an HTTP status. Platform is the configured platform name and Retryable
+// reports whether the same request may succeed later (rate limits and
+// gateway or availability errors). Err is the underlying StatusError, or a
+// RateLimitError around it for 429.
+type PlatformError struct {
+	Platform   string
+	StatusCode int
+	Body       string
+	Retryable  bool
+	Err        error
+}
+
+func (e *PlatformError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Platform, e.Err)
+	}
+	return fmt.Sprintf("%s: status %d: %s", e.Platform, e.StatusCode, e.Body)
+}
+
+func (e *PlatformError) Unwrap() error {
+	return e.Err
+}
+
+// AsPlatformError returns the PlatformError err is or wraps.
+func AsPlatformError(err error) (*PlatformError, bool) {
+	var platformErr *PlatformError
+	if errors.As(err, &platformErr) {
+		return platformErr, true
+	}
+	return nil, false
+}
+
+// PartialPostError is returned when only part of a post was published, e.g.
+// when a later Telegram album of a multi-album post fails. Result is what
+// did go out, in the form Post returns. Sending the post again would
+// duplicate that part, so callers record Result as the published post and
+// do not retry.
+type PartialPostError struct {
+	Platform string
+	Result   interface{}
+	Err      error
+}
+
+func (e *PartialPostError) Error() string {
+	return fmt.Sprintf("%s: post only partially published: %v", e.Platform, e.Err)
+}
+
+func (e *PartialPostError) Unwrap() error {
+	return e.Err
+}
+
+// AsPartialPostError returns the PartialPostError err is or wraps.
+func AsPartialPostError(err error) (*PartialPostError, bool) {
+	var partialErr *PartialPostError
+	if errors.As(err, &partialErr) {
+		return partialErr, true
+	}
+	return nil, false
+}
+
+// IsRateLimited reports whether err is a rate limit rejection from a
+// platform, i.e. a RateLimitError or an HTTP 429.
+func IsRateLimited(err error) bool {
+	if _, ok := RateLimitRetryAfter(err); ok {
+		return true
+	}
+	return HTTPStatusCode(err) == http.StatusTooManyRequests
+}
+
+// retryableStatus reports whether a request that failed with status may
+// succeed when sent again.
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// newPlatformError builds the PlatformError for a non-success response from
+// platform.
+func newPlatformError(platform, op string, resp *http.Response, body []byte) error {
+	return &PlatformError{
+		Platform:   platform,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		Retryable:  retryableStatus(resp.StatusCode),
+		Err:        newStatusError(op, resp, body),
+	}
+}
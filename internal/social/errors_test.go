@@ -0,0 +1,73 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThreadsClient_PostRateLimitedPlatformError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"too many calls"}}`))
+	}))
+	defer server.Close()
+	orig := threadsGraphURL
+	threadsGraphURL = server.URL
+	t.Cleanup(func() { threadsGraphURL = orig })
+
+	client := &ThreadsClient{name: "threads", UserID: 42, accessToken: "token"}
+	_, err := client.Post(context.Background(), &Post{Content: "hello"})
+	require.Error(t, err)
+
+	platformErr, ok := AsPlatformError(err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, "threads", platformErr.Platform)
+	assert.Equal(t, http.StatusTooManyRequests, platformErr.StatusCode)
+	assert.Contains(t, platformErr.Body, "too many calls")
+	assert.True(t, platformErr.Retryable)
+	assert.True(t, IsRateLimited(err))
+
+	retryAfter, limited := RateLimitRetryAfter(err)
+	assert.True(t, limited)
+	assert.Equal(t, 30*time.Second, retryAfter)
+}
+
+func TestNewPlatformError_NotRetryable(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.WriteHeader(http.StatusBadRequest)
+
+	err := fmt.Errorf("publish: %w", newPlatformError("threads", "publish media container", rec.Result(), []byte("bad")))
+
+	platformErr, ok := AsPlatformError(err)
+	require.True(t, ok)
+	assert.False(t, platformErr.Retryable)
+	assert.False(t, IsRateLimited(err))
+	assert.Equal(t, http.StatusBadRequest, HTTPStatusCode(err))
+
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr), "the StatusError stays reachable")
+}
+
+func TestMemos_PlatformError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewMemos(server.URL, "token", "memos", nil).ListPosts(context.Background(), 10)
+
+	platformErr, ok := AsPlatformError(err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, "memos", platformErr.Platform)
+	assert.True(t, platformErr.Retryable)
+	assert.False(t, IsRateLimited(err))
+}
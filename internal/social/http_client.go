@@ -0,0 +1,177 @@
+package social
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"go.orx.me/apps/hyper-sync/internal/telemetry"
+)
+
+// DefaultHTTPTimeout bounds a single outgoing request when http.timeout is
+// not configured.
+const DefaultHTTPTimeout = 30 * time.Second
+
+// Version is the build version reported in the default User-Agent, set at
+// build time with -ldflags "-X go.orx.me/apps/hyper-sync/internal/social.Version=v1.2.3".
+var Version = "dev"
+
+// projectURL is the contact URL in the default User-Agent.
+const projectURL = "https://github.com/orvice/HyperSync"
+
+// DefaultUserAgent is sent on outgoing requests when http.user_agent is not
+// configured, e.g. "hypersync/v1.2.3 (+https://github.com/orvice/HyperSync)".
+func DefaultUserAgent() string {
+	return "hypersync/" + Version + " (+" + projectURL + ")"
+}
+
+// HTTPClientConfig 出站 HTTP 请求（平台 API 与媒体下载）的公共配置
+type HTTPClientConfig struct {
+	// Timeout 单个请求的超时，默认 30s
+	Timeout time.Duration `yaml:"timeout"`
+	// ProxyURL 出站代理，如 http://127.0.0.1:7890 或 socks5://127.0.0.1:1080；
+	// 为空时沿用 HTTP_PROXY / HTTPS_PROXY / NO_PROXY 环境变量
+	ProxyURL string `yaml:"proxy_url"`
+	// CAFile 额外信任的 CA 证书（PEM），用于自签名证书的自建实例
+	CAFile string `yaml:"ca_file"`
+	// InsecureSkipVerify 跳过 TLS 证书校验，只应用于调试
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+	// UserAgent 出站请求的 User-Agent，默认 hypersync/<版本> (+项目地址)；
+	// 部分实例会限流或屏蔽 Go 默认的 User-Agent
+	UserAgent string `yaml:"user_agent"`
+	// MaxResponseBytes 平台 API 响应体的大小上限，默认 10MB，超出时返回 ErrBodyTooLarge；
+	// 媒体下载使用 sync.max_media_bytes
+	MaxResponseBytes int64 `yaml:"max_response_bytes"`
+	// StrictJSON 解析 API 响应与 webhook 请求体时拒绝未知字段，用于排查接口变化，默认关闭
+	StrictJSON bool `yaml:"strict_json"`
+}
+
+// HTTPClientFactory builds the http.Clients that platform clients and media
+// downloads use, so timeouts, proxy and TLS settings are configured in one
+// place. All clients from one factory share its transport and therefore its
+// connection pool, and send its User-Agent.
+type HTTPClientFactory struct {
+	timeout   time.Duration
+	transport http.RoundTripper
+}
+
+// defaultHTTPClients is used by clients created without a factory.
+var defaultHTTPClients = NewHTTPClientFactoryWithTransport(DefaultHTTPTimeout, nil)
+
+// NewHTTPClientFactory creates a factory from cfg; nil cfg gives the defaults.
+func NewHTTPClientFactory(cfg *HTTPClientConfig) (*HTTPClientFactory, error) {
+	if cfg == nil {
+		return defaultHTTPClients, nil
+	}
+	if cfg.Timeout < 0 {
+		return nil, fmt.Errorf("invalid http timeout %s", cfg.Timeout)
+	}
+	if cfg.MaxResponseBytes < 0 {
+		return nil, fmt.Errorf("invalid http max_response_bytes %d", cfg.MaxResponseBytes)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.ProxyURL != "" {
+		proxy, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid http proxy_url: %w", err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("invalid http proxy_url %q: scheme must be http, https or socks5", cfg.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if cfg.CAFile != "" || cfg.InsecureSkipVerify {
+		tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+		if cfg.CAFile != "" {
+			pem, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read http ca_file: %w", err)
+			}
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				pool = x509.NewCertPool()
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("http ca_file %s contains no PEM certificates", cfg.CAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return newHTTPClientFactory(cfg.Timeout, transport, cfg.UserAgent), nil
+}
+
+// NewHTTPClientFactoryWithTransport creates a factory over a custom
+// transport, e.g. one with its own TLS setup. timeout <= 0 uses
+// DefaultHTTPTimeout and a nil transport http.DefaultTransport. Requests
+// carry DefaultUserAgent.
+func NewHTTPClientFactoryWithTransport(timeout time.Duration, transport http.RoundTripper) *HTTPClientFactory {
+	return newHTTPClientFactory(timeout, transport, "")
+}
+
+func newHTTPClientFactory(timeout time.Duration, transport http.RoundTripper, userAgent string) *HTTPClientFactory {
+	if timeout <= 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
+	}
+	return &HTTPClientFactory{timeout: timeout, transport: &userAgentTransport{next: transport, userAgent: userAgent}}
+}
+
+// userAgentTransport sets User-Agent on requests that do not set their own.
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" {
+		// RoundTrippers must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// httpClientsOrDefault returns f, or the default factory when f is nil.
+func httpClientsOrDefault(f *HTTPClientFactory) *HTTPClientFactory {
+	if f == nil {
+		return defaultHTTPClients
+	}
+	return f
+}
+
+// Timeout returns the per-request timeout of the factory's clients.
+func (f *HTTPClientFactory) Timeout() time.Duration {
+	return f.timeout
+}
+
+// Transport returns the shared transport, for callers that wrap it. It
+// already sets the User-Agent.
+func (f *HTTPClientFactory) Transport() http.RoundTripper {
+	return f.transport
+}
+
+// Client returns a client with the configured timeout and transport.
+func (f *HTTPClientFactory) Client() *http.Client {
+	return &http.Client{Timeout: f.timeout, Transport: f.transport}
+}
+
+// TracedClient returns a client that also wraps every request in a client
+// span for platform (see telemetry.HTTPTransport).
+func (f *HTTPClientFactory) TracedClient(platform string) *http.Client {
+	return &http.Client{Timeout: f.timeout, Transport: telemetry.NewHTTPTransport(f.transport, platform)}
+}
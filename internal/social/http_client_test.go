@@ -0,0 +1,216 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClientFactory_Defaults(t *testing.T) {
+	f, err := NewHTTPClientFactory(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultHTTPTimeout, f.Timeout())
+
+	f, err = NewHTTPClientFactory(&HTTPClientConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultHTTPTimeout, f.Client().Timeout)
+}
+
+func TestNewHTTPClientFactory_Invalid(t *testing.T) {
+	_, err := NewHTTPClientFactory(&HTTPClientConfig{Timeout: -time.Second})
+	assert.Error(t, err)
+
+	_, err = NewHTTPClientFactory(&HTTPClientConfig{ProxyURL: "ftp://proxy.example.com"})
+	assert.Error(t, err)
+
+	_, err = NewHTTPClientFactory(&HTTPClientConfig{CAFile: "/nonexistent/ca.pem"})
+	assert.Error(t, err)
+}
+
+func TestHTTPClientFactory_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	f, err := NewHTTPClientFactory(&HTTPClientConfig{Timeout: 50 * time.Millisecond})
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = f.Client().Get(server.URL)
+	require.Error(t, err)
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr))
+	assert.True(t, netErr.Timeout())
+	assert.Less(t, time.Since(start), time.Second)
+
+	// 平台客户端使用同一超时
+	threads := &ThreadsClient{httpClient: f.TracedClient("threads")}
+	assert.Equal(t, 50*time.Millisecond, threads.client().Timeout)
+	assert.Equal(t, 50*time.Millisecond, NewMemos(server.URL, "token", "memos", f).httpClient.Timeout)
+}
+
+func TestHTTPClientFactory_Proxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 经代理的请求带完整 URL，Host 是目标主机
+		proxied = append(proxied, r.Host+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"memos":[]}`))
+	}))
+	defer proxy.Close()
+
+	f, err := NewHTTPClientFactory(&HTTPClientConfig{ProxyURL: proxy.URL})
+	require.NoError(t, err)
+
+	memos := NewMemos("http://memos.example.com", "token", "memos", f)
+	_, err = memos.ListMemos(context.Background(), &ListMemosRequest{})
+	require.NoError(t, err)
+	require.Len(t, proxied, 1)
+	assert.Equal(t, "memos.example.com/api/v1/memos", proxied[0])
+
+	resp, err := f.Client().Get("http://media.example.com/a.png")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "media.example.com/a.png", proxied[1])
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestHTTPClientFactory_CustomTransport(t *testing.T) {
+	var called bool
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+	})
+	f := NewHTTPClientFactoryWithTransport(0, transport)
+	assert.Equal(t, DefaultHTTPTimeout, f.Timeout())
+
+	resp, err := f.TracedClient("threads").Get("http://threads.example.com/me")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.True(t, called)
+}
+
+func TestHTTPClientFactory_UserAgent(t *testing.T) {
+	var userAgents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"memos":[]}`))
+	}))
+	defer server.Close()
+
+	f, err := NewHTTPClientFactory(nil)
+	require.NoError(t, err)
+	_, err = NewMemos(server.URL, "token", "memos", f).ListMemos(context.Background(), &ListMemosRequest{})
+	require.NoError(t, err)
+	require.Len(t, userAgents, 1)
+	assert.Equal(t, "hypersync/"+Version+" (+https://github.com/orvice/HyperSync)", userAgents[0])
+
+	f, err = NewHTTPClientFactory(&HTTPClientConfig{UserAgent: "my-sync/1.0"})
+	require.NoError(t, err)
+	_, err = NewMemos(server.URL, "token", "memos", f).ListMemos(context.Background(), &ListMemosRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "my-sync/1.0", userAgents[1])
+
+	// A User-Agent set on the request itself wins
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("User-Agent", "custom")
+	resp, err := f.Client().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "custom", userAgents[2])
+}
+
+func TestInitSocialPlatforms_PassesHTTPClients(t *testing.T) {
+	f := NewHTTPClientFactoryWithTransport(90*time.Second, nil)
+	platforms, err := InitSocialPlatforms(map[string]*PlatformConfig{
+		"discord":   {Type: "discord", Enabled: true, Discord: &DiscordConfig{WebhookURL: "https://discord.example/hook"}},
+		"matrix":    {Type: "matrix", Enabled: true, Matrix: &MatrixConfig{Homeserver: "https://matrix.example", AccessToken: "token", RoomID: "!room:example"}},
+		"wordpress": {Type: "wordpress", Enabled: true, WordPress: &WordPressConfig{SiteURL: "https://blog.example", Username: "alice", AppPassword: "pass"}},
+		"microblog": {Type: "microblog", Enabled: true, Microblog: &MicroblogConfig{Endpoint: "https://micro.example/micropub", Token: "token"}},
+		"rss":       {Type: "rss", Enabled: true, RSS: &RSSConfig{FeedURL: "https://blog.example/feed"}},
+	}, nil, nil, nil, "", f)
+	require.NoError(t, err)
+	require.Len(t, platforms, 5)
+
+	for _, p := range platforms {
+		var client *http.Client
+		switch c := p.Client.(type) {
+		case *DiscordClient:
+			client = c.httpClient
+		case *MatrixClient:
+			client = c.httpClient
+		case *WordPressClient:
+			client = c.httpClient
+		case *MicroblogClient:
+			client = c.httpClient
+		case *RSSClient:
+			client = c.httpClient
+		default:
+			t.Fatalf("unexpected client %T", p.Client)
+		}
+		assert.Same(t, f.Transport(), client.Transport, p.Name)
+		assert.Equal(t, 90*time.Second, client.Timeout, p.Name)
+	}
+
+	// 媒体上传的客户端超时不低于 60s
+	assert.Equal(t, wordPressUploadTimeout, NewWordPressClient("blog", "https://blog.example", "alice", "pass", nil).httpClient.Timeout)
+	assert.Equal(t, microblogUploadTimeout, NewMicroblogClient("microblog", "https://micro.example/micropub", "token", nil).httpClient.Timeout)
+}
+
+func TestInitSocialPlatforms_UserAgent(t *testing.T) {
+	var mu sync.Mutex
+	userAgents := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgents[strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")[0]] = r.Header.Get("User-Agent")
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	f, err := NewHTTPClientFactory(&HTTPClientConfig{UserAgent: "my-sync/1.0"})
+	require.NoError(t, err)
+	platforms, err := InitSocialPlatforms(map[string]*PlatformConfig{
+		"discord":   {Type: "discord", Enabled: true, Discord: &DiscordConfig{WebhookURL: server.URL + "/discord"}},
+		"matrix":    {Type: "matrix", Enabled: true, Matrix: &MatrixConfig{Homeserver: server.URL + "/matrix", AccessToken: "token", RoomID: "!room:example"}},
+		"wordpress": {Type: "wordpress", Enabled: true, WordPress: &WordPressConfig{SiteURL: server.URL + "/wordpress", Username: "alice", AppPassword: "pass"}},
+		"microblog": {Type: "microblog", Enabled: true, Microblog: &MicroblogConfig{Endpoint: server.URL + "/microblog", Token: "token"}},
+		"rss":       {Type: "rss", Enabled: true, RSS: &RSSConfig{FeedURL: server.URL + "/rss"}},
+	}, nil, nil, nil, "", f)
+	require.NoError(t, err)
+
+	for _, p := range platforms {
+		// 服务端一律返回 500，这里只关心请求头
+		if p.Name == "rss" {
+			_, _ = p.Client.ListPosts(context.Background(), 10)
+			continue
+		}
+		_, _ = p.Client.Post(context.Background(), &Post{Content: "hi"})
+	}
+
+	assert.Equal(t, map[string]string{
+		"discord":   "my-sync/1.0",
+		"matrix":    "my-sync/1.0",
+		"wordpress": "my-sync/1.0",
+		"microblog": "my-sync/1.0",
+		"rss":       "my-sync/1.0",
+	}, userAgents)
+}
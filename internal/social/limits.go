@@ -0,0 +1,182 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// PlatformLimits is the default maximum post length, in characters, accepted
+// by each platform. Platforms that are missing have no practical limit
+// (Discord splits long content across messages). PlatformConfig.MaxChars
+// overrides the value for one account, e.g. a Mastodon instance with a
+// higher limit.
+var PlatformLimits = map[Platform]int{
+	PlatformMastodon: 500,
+	PlatformBluesky:  300,
+	PlatformThreads:  500,
+	PlatformTelegram: 4096,
+}
+
+// Overflow strategies for content longer than the platform limit.
+const (
+	// OverflowTruncate 截断正文并以 … 结尾（默认）
+	OverflowTruncate = "truncate"
+	// OverflowThread 把正文拆成多条，后一条回复前一条，组成串
+	OverflowThread = "thread"
+)
+
+func isValidOverflow(strategy string) bool {
+	switch strategy {
+	case "", OverflowTruncate, OverflowThread:
+		return true
+	}
+	return false
+}
+
+// TruncateForPlatform shortens content to the platform's default limit,
+// cutting only between grapheme clusters so emoji sequences and combining
+// marks are never split. With appendEllipsis the result ends with "…" and
+// still fits the limit.
+func TruncateForPlatform(platform, content string, appendEllipsis bool) string {
+	return truncateContent(PlatformLimits[Platform(platform)], content, appendEllipsis)
+}
+
+// graphemeCount returns the number of user-perceived characters (grapheme
+// clusters) in s, which is how platforms count towards their limits: a ZWJ
+// family emoji or a letter with a combining accent is one character.
+func graphemeCount(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+// truncateGraphemes returns the first n grapheme clusters of s.
+func truncateGraphemes(s string, n int) string {
+	end, state := 0, -1
+	rest := s
+	for i := 0; i < n && rest != ""; i++ {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		end += len(cluster)
+	}
+	return s[:end]
+}
+
+// truncateContent shortens s to at most limit characters, counted as
+// grapheme clusters. limit <= 0 means no limit.
+func truncateContent(limit int, s string, appendEllipsis bool) string {
+	if limit <= 0 || graphemeCount(s) <= limit {
+		return s
+	}
+	if !appendEllipsis {
+		return truncateGraphemes(s, limit)
+	}
+	return truncateGraphemes(s, limit-1) + "…"
+}
+
+// splitContent splits s into chunks of at most limit characters, preferring
+// to break at a newline, then at a space, and never inside a grapheme
+// cluster.
+func splitContent(limit int, s string) []string {
+	var chunks []string
+	s = strings.TrimSpace(s)
+	for graphemeCount(s) > limit {
+		head := truncateGraphemes(s, limit)
+		split := strings.LastIndexByte(head, '\n')
+		if split <= 0 {
+			split = strings.LastIndexByte(head, ' ')
+		}
+		if split <= 0 {
+			split = len(head)
+		}
+
+		chunks = append(chunks, strings.TrimRight(s[:split], " \n"))
+		s = strings.TrimLeft(s[split:], " \n")
+	}
+	if s != "" {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
+// LengthLimiter is implemented by clients that fit long content into their
+// platform's limit. InitSocialPlatforms configures it from
+// PlatformConfig.MaxChars and PlatformConfig.Overflow.
+type LengthLimiter interface {
+	SetLengthPolicy(maxChars int, overflow string)
+}
+
+// lengthPolicy is embedded by clients to implement LengthLimiter.
+type lengthPolicy struct {
+	maxChars int
+	overflow string
+}
+
+// SetLengthPolicy overrides the platform's default limit (0 keeps it) and
+// chooses between OverflowTruncate and OverflowThread for longer content.
+func (p *lengthPolicy) SetLengthPolicy(maxChars int, overflow string) {
+	p.maxChars = maxChars
+	p.overflow = overflow
+}
+
+// effectiveLimit is ContentLimit with maxChars, when set, replacing the
+// platform's default limit. Special limits such as Telegram captions are
+// kept.
+func effectiveLimit(platformType string, maxChars int, post *Post) int {
+	limit := ContentLimit(platformType, post)
+	if maxChars > 0 && limit == PlatformLimits[Platform(platformType)] {
+		return maxChars
+	}
+	return limit
+}
+
+// postWithinLimit publishes post through publish, first fitting its content
+// into the limit. With OverflowThread the content is split and each further
+// part is published as a reply to the previous one; media, poll and the
+// original reply target stay on the first part. The first part's result is
+// returned, since that is the post the thread is stored and edited as. If a
+// further part fails the thread stops there and a PartialPostError carrying
+// the first part's result is returned.
+func (p *lengthPolicy) postWithinLimit(ctx context.Context, platform Platform, post *Post, publish func(context.Context, *Post) (interface{}, error)) (interface{}, error) {
+	limit := effectiveLimit(platform.String(), p.maxChars, post)
+	if limit <= 0 || graphemeCount(post.Content) <= limit {
+		return publish(ctx, post)
+	}
+
+	if p.overflow != OverflowThread {
+		out := *post
+		out.Content = truncateContent(limit, post.Content, true)
+		return publish(ctx, &out)
+	}
+
+	parts := splitContent(limit, post.Content)
+	head := *post
+	head.Content = parts[0]
+	result, err := publish(ctx, &head)
+	if err != nil {
+		return nil, err
+	}
+
+	parentID := ExtractPlatformID(result)
+	for i, part := range parts[1:] {
+		reply, err := publish(ctx, &Post{
+			Content:        part,
+			Visibility:     post.Visibility,
+			SourcePlatform: post.SourcePlatform,
+			OriginalID:     post.OriginalID,
+			InReplyTo:      parentID,
+			CreatedAt:      post.CreatedAt,
+		})
+		if err != nil {
+			// 首条已发布，按部分发布返回：同步记为已发布、不再重试整条串
+			return nil, &PartialPostError{
+				Platform: platform.String(),
+				Result:   result,
+				Err:      fmt.Errorf("thread part %d of %d: %w", i+2, len(parts), err),
+			}
+		}
+		parentID = ExtractPlatformID(reply)
+	}
+	return result, nil
+}
@@ -0,0 +1,216 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	zwjFamily  = "\U0001F468\u200d\U0001F469\u200d\U0001F467" // 👨‍👩‍👧: 5 code points joined by ZWJ
+	flagJP     = "\U0001F1EF\U0001F1F5"                       // 2 regional indicators
+	thumbsUp   = "\U0001F44D\U0001F3FD"                       // emoji + skin tone modifier
+	eWithAcute = "e\u0301"                                    // e + combining acute accent
+)
+
+func TestGraphemeCount(t *testing.T) {
+	assert.Equal(t, 0, graphemeCount(""))
+	assert.Equal(t, 5, graphemeCount("hello"))
+	assert.Equal(t, 2, graphemeCount("你好"))
+	assert.Equal(t, 1, graphemeCount(zwjFamily))
+	assert.Equal(t, 1, graphemeCount(flagJP))
+	assert.Equal(t, 2, graphemeCount(flagJP+flagJP))
+	assert.Equal(t, 1, graphemeCount(thumbsUp))
+	assert.Equal(t, 5, graphemeCount("h"+eWithAcute+"llo"))
+	assert.Equal(t, 7, utf8.RuneCountInString("ab"+zwjFamily), "runes overcount")
+	assert.Equal(t, 3, graphemeCount("ab"+zwjFamily))
+}
+
+func TestTruncateGraphemes(t *testing.T) {
+	assert.Equal(t, "", truncateGraphemes("hello", 0))
+	assert.Equal(t, "hel", truncateGraphemes("hello", 3))
+	assert.Equal(t, "hello", truncateGraphemes("hello", 10))
+	assert.Equal(t, zwjFamily, truncateGraphemes(zwjFamily+"x", 1))
+	assert.Equal(t, "a"+zwjFamily, truncateGraphemes("a"+zwjFamily+zwjFamily, 2))
+	assert.Equal(t, "h"+eWithAcute, truncateGraphemes("h"+eWithAcute+"llo", 2))
+}
+
+func TestTruncateContent_GraphemeBoundaries(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		in    string
+		want  string
+	}{
+		{"fits", 10, "hello", "hello"},
+		{"no limit", 0, "hello", "hello"},
+		{"plain", 4, "hello", "hel…"},
+		{"zwj sequence fits", 3, "ab" + zwjFamily, "ab" + zwjFamily},
+		{"zwj sequence", 4, "ab" + zwjFamily + "cd", "ab" + zwjFamily + "…"},
+		{"zwj sequence at cut", 3, "ab" + zwjFamily + "cd", "ab…"},
+		{"flag pair", 4, "ab" + flagJP + flagJP + "c", "ab" + flagJP + "…"},
+		{"skin tone", 3, "a" + thumbsUp + "bc", "a" + thumbsUp + "…"},
+		{"combining mark", 4, "ab" + eWithAcute + "cd", "ab" + eWithAcute + "…"},
+		{"single character limit", 1, "hello", "…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateContent(tt.limit, tt.in, true)
+			assert.Equal(t, tt.want, got)
+			if tt.limit > 0 {
+				assert.LessOrEqual(t, graphemeCount(got), tt.limit)
+			}
+		})
+	}
+
+	// 不加省略号时同样不拆开字素簇
+	assert.Equal(t, "ab"+zwjFamily, truncateContent(3, "ab"+zwjFamily+"c", false))
+	assert.Equal(t, "ab"+flagJP, truncateContent(3, "ab"+flagJP+flagJP, false))
+}
+
+func TestTruncateForPlatform(t *testing.T) {
+	long := strings.Repeat("a", 600)
+	assert.Equal(t, 300, utf8.RuneCountInString(TruncateForPlatform("bluesky", long, true)))
+	assert.True(t, strings.HasSuffix(TruncateForPlatform("bluesky", long, true), "…"))
+	assert.Equal(t, strings.Repeat("a", 500), TruncateForPlatform("mastodon", long, false))
+	assert.Equal(t, long, TruncateForPlatform("discord", long, true), "platforms without a limit are left alone")
+}
+
+func TestSplitContent(t *testing.T) {
+	assert.Nil(t, splitContent(10, ""))
+	assert.Equal(t, []string{"short"}, splitContent(10, "short"))
+	assert.Equal(t, []string{"hello", "world foo"}, splitContent(10, "hello world foo"))
+	assert.Equal(t, []string{"line one", "line two"}, splitContent(10, "line one\nline two"))
+	assert.Equal(t, []string{"abcdefghij", "klm"}, splitContent(10, "abcdefghijklm"))
+
+	// Multi-byte runes count as one character and are never cut in half.
+	chunks := splitContent(10, strings.Repeat("你", 25))
+	require.Len(t, chunks, 3)
+	assert.Equal(t, strings.Repeat("你", 10), chunks[0])
+	assert.Equal(t, strings.Repeat("你", 5), chunks[2])
+
+	// ZWJ 序列算一个字符，且不会被拆到两段
+	assert.Equal(t, []string{"ab" + zwjFamily, zwjFamily}, splitContent(3, "ab"+zwjFamily+zwjFamily))
+}
+
+// fakePublisher records what postWithinLimit publishes and answers with
+// Telegram-style {"id": N} results.
+type fakePublisher struct {
+	posts  []*Post
+	failAt int
+}
+
+func (f *fakePublisher) publish(_ context.Context, post *Post) (interface{}, error) {
+	if f.failAt > 0 && len(f.posts)+1 == f.failAt {
+		return nil, errors.New("rate limited")
+	}
+	f.posts = append(f.posts, post)
+	return map[string]string{"id": strconv.Itoa(100 + len(f.posts))}, nil
+}
+
+func TestPostWithinLimit_Truncate(t *testing.T) {
+	var policy lengthPolicy
+	pub := &fakePublisher{}
+	long := strings.Repeat("word ", 100)
+
+	result, err := policy.postWithinLimit(context.Background(), PlatformBluesky, &Post{Content: long}, pub.publish)
+	require.NoError(t, err)
+	assert.Equal(t, "101", ExtractPlatformID(result))
+	require.Len(t, pub.posts, 1)
+	assert.Equal(t, 300, utf8.RuneCountInString(pub.posts[0].Content))
+	assert.True(t, strings.HasSuffix(pub.posts[0].Content, "…"))
+
+	// max_chars 覆盖默认上限
+	pub = &fakePublisher{}
+	policy.SetLengthPolicy(1000, OverflowTruncate)
+	_, err = policy.postWithinLimit(context.Background(), PlatformMastodon, &Post{Content: long}, pub.publish)
+	require.NoError(t, err)
+	assert.Equal(t, long, pub.posts[0].Content)
+}
+
+func TestPostWithinLimit_Thread(t *testing.T) {
+	var policy lengthPolicy
+	policy.SetLengthPolicy(20, OverflowThread)
+	pub := &fakePublisher{}
+	post := &Post{
+		Content:    "first part here. second part here. third part",
+		Visibility: VisibilityLevelPublic,
+		Media:      []Media{*NewMediaFromURL("https://example.com/a.png")},
+		InReplyTo:  "7",
+	}
+
+	result, err := policy.postWithinLimit(context.Background(), PlatformMastodon, post, pub.publish)
+	require.NoError(t, err)
+	assert.Equal(t, "101", ExtractPlatformID(result), "the head of the thread is returned")
+
+	require.Len(t, pub.posts, 3)
+	assert.Equal(t, "first part here.", pub.posts[0].Content)
+	assert.Equal(t, "second part here.", pub.posts[1].Content)
+	assert.Equal(t, "third part", pub.posts[2].Content)
+
+	// 媒体和原回复目标只在第一条上，后续每条回复前一条
+	assert.Len(t, pub.posts[0].Media, 1)
+	assert.Equal(t, "7", pub.posts[0].InReplyTo)
+	assert.Empty(t, pub.posts[1].Media)
+	assert.Equal(t, "101", pub.posts[1].InReplyTo)
+	assert.Equal(t, "102", pub.posts[2].InReplyTo)
+	assert.Equal(t, VisibilityLevelPublic, pub.posts[2].Visibility)
+	assert.Equal(t, "first part here. second part here. third part", post.Content, "the original post must not be modified")
+}
+
+func TestPostWithinLimit_ThreadContinuationFails(t *testing.T) {
+	var policy lengthPolicy
+	policy.SetLengthPolicy(20, OverflowThread)
+	pub := &fakePublisher{failAt: 2}
+
+	_, err := policy.postWithinLimit(context.Background(), PlatformMastodon,
+		&Post{Content: "first part here. second part here. third part"}, pub.publish)
+	partial, ok := AsPartialPostError(err)
+	require.True(t, ok, "the head is published, so the post must not be retried")
+	assert.Equal(t, "101", ExtractPlatformID(partial.Result))
+	assert.Contains(t, err.Error(), "thread part 2 of 3")
+	assert.Len(t, pub.posts, 1)
+
+	// 第一条失败时照常返回错误
+	pub = &fakePublisher{failAt: 1}
+	_, err = policy.postWithinLimit(context.Background(), PlatformMastodon,
+		&Post{Content: "first part here. second part here."}, pub.publish)
+	assert.Error(t, err)
+	_, ok = AsPartialPostError(err)
+	assert.False(t, ok)
+}
+
+func TestPostWithinLimit_TelegramCaption(t *testing.T) {
+	var policy lengthPolicy
+	policy.SetLengthPolicy(8000, OverflowTruncate)
+	pub := &fakePublisher{}
+	long := strings.Repeat("a", 5000)
+
+	_, err := policy.postWithinLimit(context.Background(), PlatformTelegram, &Post{Content: long}, pub.publish)
+	require.NoError(t, err)
+	assert.Equal(t, long, pub.posts[0].Content, "max_chars replaces the message limit")
+
+	_, err = policy.postWithinLimit(context.Background(), PlatformTelegram, &Post{
+		Content: long,
+		Media:   []Media{*NewMediaFromURL("https://example.com/a.png")},
+	}, pub.publish)
+	require.NoError(t, err)
+	assert.Equal(t, telegramCaptionLimit, utf8.RuneCountInString(pub.posts[1].Content), "captions keep their own limit")
+}
+
+func TestFooterTransformer_MaxChars(t *testing.T) {
+	tr, err := NewFooterTransformer("mastodon", "mastodon", "via blog", nil)
+	require.NoError(t, err)
+	tr.SetMaxChars(1000)
+
+	long := strings.Repeat("a", 800)
+	out, err := tr.Transform(&Post{Content: long})
+	require.NoError(t, err)
+	assert.Equal(t, long+"\n\nvia blog", out.Content)
+}
@@ -0,0 +1,125 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// linkCardMaxBody caps how much of a page is read when looking for
+// OpenGraph tags; they live in <head>, so the first chunk is enough.
+const linkCardMaxBody = 512 * 1024
+
+// linkCardFetchTimeout bounds the page fetch so a slow site never holds up
+// posting — a card is a nice-to-have, the post itself is not.
+const linkCardFetchTimeout = 10 * time.Second
+
+// LinkCard is the preview metadata extracted from a linked page.
+type LinkCard struct {
+	URL         string
+	Title       string
+	Description string
+	ImageURL    string
+}
+
+var (
+	urlPattern     = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+	metaTagPattern = regexp.MustCompile(`(?is)<meta\s+[^>]*>`)
+	metaAttrRegexp = regexp.MustCompile(`(?is)(property|name|content)\s*=\s*("[^"]*"|'[^']*')`)
+	titlePattern   = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// dominantURL returns the link a post is "about": the content must contain
+// exactly one URL and, once that URL is removed, at most a short comment.
+// Returns "" when no single URL dominates.
+func dominantURL(content string) string {
+	urls := urlPattern.FindAllString(content, -1)
+	if len(urls) != 1 {
+		return ""
+	}
+	link := strings.TrimRight(urls[0], ".,;:!?")
+
+	comment := strings.TrimSpace(strings.Replace(content, urls[0], "", 1))
+	if graphemeCount(comment) > 200 {
+		return ""
+	}
+	return link
+}
+
+// fetchLinkCard downloads link and extracts its OpenGraph title, description
+// and image. A page without OG tags falls back to <title>, then to the URL.
+func fetchLinkCard(ctx context.Context, client *http.Client, link string) (*LinkCard, error) {
+	ctx, cancel := context.WithTimeout(ctx, linkCardFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("link card: build request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("link card: fetch %s: %w", link, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("link card: fetch %s: status code %d", link, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, linkCardMaxBody))
+	if err != nil {
+		return nil, fmt.Errorf("link card: read %s: %w", link, err)
+	}
+
+	card := parseLinkCard(string(body))
+	card.URL = link
+	if card.Title == "" {
+		card.Title = link
+	}
+	return card, nil
+}
+
+// parseLinkCard pulls OpenGraph metadata out of an HTML document.
+func parseLinkCard(doc string) *LinkCard {
+	card := &LinkCard{}
+	for _, tag := range metaTagPattern.FindAllString(doc, -1) {
+		var key, content string
+		for _, m := range metaAttrRegexp.FindAllStringSubmatch(tag, -1) {
+			value := html.UnescapeString(strings.Trim(m[2], `"'`))
+			switch strings.ToLower(m[1]) {
+			case "property", "name":
+				key = strings.ToLower(value)
+			case "content":
+				content = strings.TrimSpace(value)
+			}
+		}
+		switch key {
+		case "og:title":
+			card.Title = content
+		case "og:description":
+			card.Description = content
+		case "og:image", "og:image:url":
+			if card.ImageURL == "" {
+				card.ImageURL = content
+			}
+		case "description":
+			if card.Description == "" {
+				card.Description = content
+			}
+		}
+	}
+
+	if card.Title == "" {
+		if m := titlePattern.FindStringSubmatch(doc); m != nil {
+			card.Title = strings.TrimSpace(html.UnescapeString(m[1]))
+		}
+	}
+	return card
+}
@@ -0,0 +1,73 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDominantURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"bare link", "https://example.com/post", "https://example.com/post"},
+		{"link with comment", "Worth a read: https://example.com/a?b=1.", "https://example.com/a?b=1"},
+		{"no link", "just some text", ""},
+		{"two links", "https://a.example https://b.example", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, dominantURL(tt.content))
+		})
+	}
+}
+
+func TestFetchLinkCard_OpenGraph(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><head>
+<title>Fallback title</title>
+<meta property="og:title" content="Hello &amp; welcome">
+<meta property="og:description" content="A description">
+<meta content="https://cdn.example.com/img.jpg" property="og:image">
+</head></html>`))
+	}))
+	defer server.Close()
+
+	card, err := fetchLinkCard(context.Background(), server.Client(), server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, server.URL, card.URL)
+	assert.Equal(t, "Hello & welcome", card.Title)
+	assert.Equal(t, "A description", card.Description)
+	assert.Equal(t, "https://cdn.example.com/img.jpg", card.ImageURL)
+}
+
+func TestFetchLinkCard_FallsBackToURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<html><body>no metadata</body></html>`))
+	}))
+	defer server.Close()
+
+	card, err := fetchLinkCard(context.Background(), server.Client(), server.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, server.URL, card.Title)
+	assert.Empty(t, card.ImageURL)
+}
+
+func TestFetchLinkCard_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := fetchLinkCard(context.Background(), server.Client(), server.URL)
+	assert.Error(t, err)
+}
@@ -0,0 +1,175 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"butterfly.orx.me/core/log"
+)
+
+// LinkShortener turns a URL into a shorter one that redirects to it.
+type LinkShortener interface {
+	Shorten(ctx context.Context, link string) (string, error)
+}
+
+// LinkShortenerConfig 外部短链服务：向 endpoint POST {"url": "<原链接>"}，
+// 响应 JSON 的 short_url 字段为短链
+type LinkShortenerConfig struct {
+	Endpoint string `yaml:"endpoint"`
+	// Token 非空时以 Authorization: Bearer <token> 发送
+	Token string `yaml:"token"`
+}
+
+// PassthroughShortener returns links unchanged. It is used for platforms
+// without a link shortener.
+type PassthroughShortener struct{}
+
+func (PassthroughShortener) Shorten(_ context.Context, link string) (string, error) {
+	return link, nil
+}
+
+// HTTPShortener shortens links through an external service.
+type HTTPShortener struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+}
+
+// NewHTTPShortener creates a shortener posting to endpoint.
+func NewHTTPShortener(endpoint, token string, client *http.Client) *HTTPShortener {
+	return &HTTPShortener{endpoint: endpoint, token: token, httpClient: client}
+}
+
+type shortenRequest struct {
+	URL string `json:"url"`
+}
+
+type shortenResponse struct {
+	ShortURL string `json:"short_url"`
+}
+
+// Shorten posts link to the shorten endpoint and returns its short_url.
+func (s *HTTPShortener) Shorten(ctx context.Context, link string) (string, error) {
+	body, err := json.Marshal(shortenRequest{URL: link})
+	if err != nil {
+		return "", fmt.Errorf("link shortener: encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("link shortener: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("link shortener: shorten %s: %w", link, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("link shortener: shorten %s: status code %d: %s", link, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result shortenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("link shortener: decode response: %w", err)
+	}
+	if result.ShortURL == "" {
+		return "", errors.New("link shortener: response has no short_url")
+	}
+	return result.ShortURL, nil
+}
+
+// maxCachedLinks bounds CachingShortener; the cache starts over once full.
+const maxCachedLinks = 4096
+
+// CachingShortener remembers the links next has shortened, so a link that
+// shows up in several posts or is sent to several platforms is shortened
+// once. Failures are not cached.
+type CachingShortener struct {
+	next LinkShortener
+
+	mu    sync.Mutex
+	links map[string]string
+}
+
+// NewCachingShortener wraps next with a cache.
+func NewCachingShortener(next LinkShortener) *CachingShortener {
+	return &CachingShortener{next: next, links: make(map[string]string)}
+}
+
+func (c *CachingShortener) Shorten(ctx context.Context, link string) (string, error) {
+	c.mu.Lock()
+	short, ok := c.links[link]
+	c.mu.Unlock()
+	if ok {
+		return short, nil
+	}
+
+	short, err := c.next.Shorten(ctx, link)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	if len(c.links) >= maxCachedLinks {
+		c.links = make(map[string]string)
+	}
+	c.links[link] = short
+	c.mu.Unlock()
+	return short, nil
+}
+
+// NewLinkShortener builds the shortener described by cfg, falling back to a
+// passthrough when none is configured. External shorteners are cached.
+func NewLinkShortener(cfg *LinkShortenerConfig, httpClients *HTTPClientFactory) (LinkShortener, error) {
+	if cfg == nil {
+		return PassthroughShortener{}, nil
+	}
+	if cfg.Endpoint == "" {
+		return nil, errors.New("missing link_shortener endpoint")
+	}
+	client := httpClientsOrDefault(httpClients).Client()
+	return NewCachingShortener(NewHTTPShortener(cfg.Endpoint, cfg.Token, client)), nil
+}
+
+// ShortenLinks replaces every URL in content with its shortened form. A link
+// the shortener fails on, or cannot make shorter, is kept as it is, so
+// shortening never fails a post.
+func ShortenLinks(ctx context.Context, content string, shortener LinkShortener) string {
+	if shortener == nil {
+		return content
+	}
+	shortened := make(map[string]string)
+	return urlPattern.ReplaceAllStringFunc(content, func(match string) string {
+		// Trailing punctuation belongs to the sentence, not the link
+		link := strings.TrimRight(match, ".,;:!?")
+		suffix := match[len(link):]
+
+		short, ok := shortened[link]
+		if !ok {
+			var err error
+			short, err = shortener.Shorten(ctx, link)
+			if err != nil {
+				log.FromContext(ctx).Warn("Failed to shorten link, keeping it", "link", link, "error", err)
+				short = link
+			} else if len(short) >= len(link) {
+				short = link
+			}
+			shortened[link] = short
+		}
+		return short + suffix
+	})
+}
@@ -0,0 +1,127 @@
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockShortener maps links to "https://s.io/<n>" and counts its calls.
+type mockShortener struct {
+	mu    sync.Mutex
+	calls []string
+	err   error
+}
+
+func (m *mockShortener) Shorten(_ context.Context, link string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, link)
+	if m.err != nil {
+		return "", m.err
+	}
+	return "https://s.io/" + strings.Repeat("x", len(m.calls)), nil
+}
+
+func TestShortenLinks(t *testing.T) {
+	shortener := &mockShortener{}
+	content := "Read https://example.com/articles/2026/long-title-here. Also https://example.com/articles/2026/long-title-here and https://a.io"
+
+	got := ShortenLinks(context.Background(), content, shortener)
+
+	assert.Equal(t, "Read https://s.io/x. Also https://s.io/x and https://a.io", got,
+		"trailing punctuation stays, and links the shortener cannot shorten are kept")
+	assert.Equal(t, []string{"https://example.com/articles/2026/long-title-here", "https://a.io"}, shortener.calls)
+}
+
+func TestShortenLinks_KeepsLinkOnError(t *testing.T) {
+	shortener := &mockShortener{err: errors.New("service down")}
+	content := "see https://example.com/articles/2026/long-title-here"
+
+	assert.Equal(t, content, ShortenLinks(context.Background(), content, shortener))
+}
+
+func TestCachingShortener_CacheHit(t *testing.T) {
+	mock := &mockShortener{}
+	shortener := NewCachingShortener(mock)
+	ctx := context.Background()
+	const link = "https://example.com/articles/2026/long-title-here"
+
+	first, err := shortener.Shorten(ctx, link)
+	require.NoError(t, err)
+	second, err := shortener.Shorten(ctx, link)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Len(t, mock.calls, 1, "a cached link must not be shortened again")
+
+	// Across posts and platforms sharing the shortener
+	ShortenLinks(ctx, "again: "+link, shortener)
+	assert.Len(t, mock.calls, 1)
+}
+
+func TestCachingShortener_DoesNotCacheFailures(t *testing.T) {
+	mock := &mockShortener{err: errors.New("service down")}
+	shortener := NewCachingShortener(mock)
+	ctx := context.Background()
+
+	_, err := shortener.Shorten(ctx, "https://example.com/a")
+	require.Error(t, err)
+
+	mock.err = nil
+	short, err := shortener.Shorten(ctx, "https://example.com/a")
+	require.NoError(t, err)
+	assert.Equal(t, "https://s.io/xx", short)
+}
+
+func TestHTTPShortener(t *testing.T) {
+	var got shortenRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"short_url": "https://s.io/abc"}`))
+	}))
+	defer server.Close()
+
+	shortener := NewHTTPShortener(server.URL, "secret", server.Client())
+	short, err := shortener.Shorten(context.Background(), "https://example.com/long")
+	require.NoError(t, err)
+	assert.Equal(t, "https://s.io/abc", short)
+	assert.Equal(t, "https://example.com/long", got.URL)
+}
+
+func TestHTTPShortener_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/empty" {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPShortener(server.URL, "", server.Client()).Shorten(context.Background(), "https://example.com/long")
+	assert.ErrorContains(t, err, "status code 429: quota exceeded")
+
+	_, err = NewHTTPShortener(server.URL+"/empty", "", server.Client()).Shorten(context.Background(), "https://example.com/long")
+	assert.ErrorContains(t, err, "no short_url")
+}
+
+func TestSocialPlatform_NormalizeContentShortensLinks(t *testing.T) {
+	platform := &SocialPlatform{
+		Config:    &PlatformConfig{Normalize: []string{NormalizeStripHashtags}},
+		Shortener: &mockShortener{},
+	}
+	got := platform.NormalizeContent(context.Background(), "New post https://example.com/articles/2026/long-title-here #blog")
+	assert.Equal(t, "New post https://s.io/x", got)
+}
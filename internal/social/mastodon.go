@@ -1,15 +1,33 @@
 package social
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"butterfly.orx.me/core/log"
 	"github.com/mattn/go-mastodon"
 )
 
 type MastodonClient struct {
 	name   string
 	Client *mastodon.Client
+	// accountURL 是当前账号主页（https://instance/@user），ListPosts 时记录，
+	// 用于拼接状态链接
+	mu         sync.Mutex
+	accountURL string
+
+	// includeReblogs / includeReplies 作为源时是否同步转嘟与回复（默认都不同步）
+	includeReblogs bool
+	includeReplies bool
+
+	// lengthPolicy 决定超出字数上限的正文截断还是拆成串，见 SetLengthPolicy
+	lengthPolicy
 }
 
 func NewMastodonClient(instanceURL, accessToken, name string) *MastodonClient {
@@ -20,6 +38,7 @@ func NewMastodonClient(instanceURL, accessToken, name string) *MastodonClient {
 
 	// Create the client
 	c := mastodon.NewClient(config)
+	c.Transport = &rateLimitTransport{base: http.DefaultTransport}
 
 	return &MastodonClient{
 		Client: c,
@@ -31,21 +50,67 @@ func (c *MastodonClient) Name() string {
 	return c.name
 }
 
-// Post publishes a new status to Mastodon
+// SetIncludeReblogs makes ListPosts return boosts of other accounts'
+// statuses, which it skips by default.
+func (c *MastodonClient) SetIncludeReblogs(include bool) {
+	c.includeReblogs = include
+}
+
+// SetIncludeReplies makes ListPosts return replies to other accounts, which
+// it skips by default. Replies to the account's own statuses (threads) are
+// always returned.
+func (c *MastodonClient) SetIncludeReplies(include bool) {
+	c.includeReplies = include
+}
+
+// EnsureValidToken checks that the access token is still accepted. Mastodon
+// tokens do not expire and cannot be refreshed, but a user can revoke them;
+// this surfaces that on the token refresh schedule instead of at the next
+// cross-post.
+func (c *MastodonClient) EnsureValidToken(ctx context.Context) error {
+	if _, err := c.Client.GetAccountCurrentUser(ctx); err != nil {
+		return fmt.Errorf("mastodon access token rejected: %w", err)
+	}
+	return nil
+}
+
+// TokenExpiresAt reports that Mastodon tokens never expire.
+func (c *MastodonClient) TokenExpiresAt(_ context.Context) (time.Time, bool, error) {
+	return time.Time{}, false, nil
+}
+
+// Post publishes a new status to Mastodon. Content over the limit is
+// truncated or continued in replies, depending on the length policy.
 func (c *MastodonClient) Post(ctx context.Context, post *Post) (interface{}, error) {
+	return c.postWithinLimit(ctx, PlatformMastodon, post, c.publishStatus)
+}
+
+// publishStatus publishes post as a single status.
+func (c *MastodonClient) publishStatus(ctx context.Context, post *Post) (interface{}, error) {
 	// Check if visibility level is supported for Mastodon
-	if post.Visibility.IsValid() {
-		if !IsVisibilityLevelSupported(PlatformMastodon.String(), post.Visibility) {
-			return nil, fmt.Errorf("visibility %s is not supported by platform %s", post.Visibility.String(), PlatformMastodon.String())
-		}
+	if err := checkVisibility(PlatformMastodon, post.Visibility); err != nil {
+		return nil, err
+	}
+	if err := ValidateMedia(PlatformMastodon.String(), post.Media); err != nil {
+		return nil, err
 	}
 
 	// Convert enum to platform-specific string
 	platformVisibility := GetPlatformVisibilityString(PlatformMastodon.String(), post.Visibility)
 
+	status, poll, err := mastodonPoll(post)
+	if err != nil {
+		return nil, err
+	}
+	if poll != nil && len(post.Media) > 0 {
+		return nil, fmt.Errorf("mastodon does not allow a poll and media on the same status")
+	}
+
 	toot := &mastodon.Toot{
-		Status:     post.Content,
-		Visibility: platformVisibility,
+		Status:      status,
+		InReplyToID: mastodon.ID(post.InReplyTo),
+		Visibility:  platformVisibility,
+		Poll:        poll,
 	}
 
 	// Upload media attachments if any
@@ -53,12 +118,15 @@ func (c *MastodonClient) Post(ctx context.Context, post *Post) (interface{}, err
 		mediaIDs := make([]mastodon.ID, 0, len(post.Media))
 		for _, media := range post.Media {
 			// Get media data, which might be fetched from a URL
-			mediaData, err := media.GetData()
+			mediaData, err := media.GetDataFor(PlatformMastodon.String())
 			if err != nil {
 				return nil, fmt.Errorf("failed to get media data: %w", err)
 			}
 
-			attachment, err := c.Client.UploadMediaFromBytes(ctx, mediaData)
+			attachment, err := c.Client.UploadMediaFromMedia(ctx, &mastodon.Media{
+				File:        bytes.NewReader(mediaData),
+				Description: media.Description,
+			})
 			if err != nil {
 				return nil, err
 			}
@@ -71,8 +139,31 @@ func (c *MastodonClient) Post(ctx context.Context, post *Post) (interface{}, err
 		}
 	}
 
-	status, err := c.Client.PostStatus(ctx, toot)
-	return status, err
+	posted, err := c.Client.PostStatus(ctx, toot)
+	if err != nil {
+		return nil, err
+	}
+	return posted, nil
+}
+
+// mastodonPoll returns the status text and poll for post: post.Poll if set,
+// otherwise a trailing "[ ] option" block parsed out of the content.
+func mastodonPoll(post *Post) (string, *mastodon.TootPoll, error) {
+	status, poll := post.Content, post.Poll
+	if poll == nil {
+		status, poll = ExtractPollBlock(post.Content)
+	}
+	if poll == nil {
+		return post.Content, nil, nil
+	}
+	if err := poll.Validate(); err != nil {
+		return "", nil, fmt.Errorf("invalid poll: %w", err)
+	}
+	return status, &mastodon.TootPoll{
+		Options:          poll.Options,
+		ExpiresInSeconds: int64(poll.expiresIn().Seconds()),
+		Multiple:         poll.Multiple,
+	}, nil
 }
 
 // Update edits an existing status on Mastodon.
@@ -96,13 +187,85 @@ func (c *MastodonClient) Delete(ctx context.Context, platformID string) error {
 	return c.Client.DeleteStatus(ctx, mastodon.ID(platformID))
 }
 
+// mastodonStatusToPost converts a status to a Post, keeping its visibility
+// and its media attachments with their alt text.
+func mastodonStatusToPost(ctx context.Context, status *mastodon.Status) *Post {
+	visibility, err := ParsePlatformVisibility(PlatformMastodon.String(), status.Visibility)
+	if err != nil {
+		// 未知的可见性（如部分分支的 "limited"）按 private 处理，避免扩大受众
+		log.FromContext(ctx).Warn("unknown mastodon visibility, treating as private",
+			"status_id", status.ID,
+			"visibility", status.Visibility)
+		visibility = VisibilityLevelPrivate
+	}
+
+	post := &Post{
+		ID:             string(status.ID),
+		Content:        status.Content,
+		Visibility:     visibility,
+		SourcePlatform: PlatformMastodon.String(),
+		SourceURL:      status.URL,
+		CreatedAt:      status.CreatedAt,
+	}
+	for _, attachment := range status.MediaAttachments {
+		// 远端实例的媒体尚未缓存时 url 为空，只有 remote_url
+		url := attachment.URL
+		if url == "" {
+			url = attachment.RemoteURL
+		}
+		if url == "" {
+			continue
+		}
+		media := NewMediaFromURL(url)
+		media.Description = attachment.Description
+		post.Media = append(post.Media, *media)
+	}
+	return post
+}
+
+// mastodonID returns an ID go-mastodon leaves untyped, such as
+// in_reply_to_id, as a string; it is empty when the field is null.
+func mastodonID(v interface{}) string {
+	switch id := v.(type) {
+	case string:
+		return id
+	case mastodon.ID:
+		return string(id)
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	}
+	return ""
+}
+
+// SourceURL returns the status permalink (https://instance/@user/<id>). The
+// account URL is learned by ListPosts, so it is empty before the first list.
+func (c *MastodonClient) SourceURL(post *Post) string {
+	c.mu.Lock()
+	accountURL := c.accountURL
+	c.mu.Unlock()
+	id := postOriginalID(post)
+	if accountURL == "" || id == "" {
+		return ""
+	}
+	return strings.TrimSuffix(accountURL, "/") + "/" + id
+}
+
 // ListPosts retrieves the most recent posts for the authenticated user
 func (c *MastodonClient) ListPosts(ctx context.Context, limit int) ([]*Post, error) {
+	return c.ListPostsSince(ctx, limit, ListCursor{})
+}
+
+// ListPostsSince lists the authenticated user's statuses newer than
+// since.SinceID, using the since_id pagination parameter.
+func (c *MastodonClient) ListPostsSince(ctx context.Context, limit int, since ListCursor) ([]*Post, error) {
 	// Get the account information for the authenticated user
 	account, err := c.Client.GetAccountCurrentUser(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current user account: %w", err)
 	}
+	c.mu.Lock()
+	c.accountURL = account.URL
+	c.mu.Unlock()
 
 	// Set default limit if not specified
 	if limit <= 0 {
@@ -111,7 +274,8 @@ func (c *MastodonClient) ListPosts(ctx context.Context, limit int) ([]*Post, err
 
 	// Get statuses for the authenticated user
 	pg := mastodon.Pagination{
-		Limit: int64(limit),
+		Limit:   int64(limit),
+		SinceID: mastodon.ID(since.SinceID),
 	}
 	statuses, err := c.Client.GetAccountStatuses(ctx, account.ID, &pg)
 	if err != nil {
@@ -121,27 +285,18 @@ func (c *MastodonClient) ListPosts(ctx context.Context, limit int) ([]*Post, err
 	// Convert Mastodon statuses to our Post type
 	posts := make([]*Post, 0, len(statuses))
 	for _, status := range statuses {
-		// Convert string visibility to enum
-		visibility, err := ParseVisibilityLevel(status.Visibility)
-		if err != nil {
-			// Use default visibility if parsing fails
-			visibility = VisibilityLevelPublic
+		if status.Reblog != nil && !c.includeReblogs {
+			continue
 		}
-
-		post := &Post{
-			ID:             string(status.ID),
-			Content:        status.Content,
-			Visibility:     visibility,
-			SourcePlatform: PlatformMastodon.String(),
-			CreatedAt:      status.CreatedAt,
+		replyTo := mastodonID(status.InReplyToID)
+		selfReply := replyTo != "" && mastodonID(status.InReplyToAccountID) == string(account.ID)
+		if replyTo != "" && !selfReply && !c.includeReplies {
+			continue
 		}
-		// Add media attachments if available
-		if len(status.MediaAttachments) > 0 {
-			// We don't have the original media data, just note that media exists
-			post.Media = []Media{}
-			for _, media := range status.MediaAttachments {
-				post.Media = append(post.Media, *NewMediaFromURL(media.URL))
-			}
+		post := mastodonStatusToPost(ctx, status)
+		if selfReply {
+			// 自己的串：保留父帖 ID，交给同步流程映射为各目标上的回复
+			post.InReplyTo = replyTo
 		}
 		posts = append(posts, post)
 	}
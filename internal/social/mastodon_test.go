@@ -0,0 +1,179 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-mastodon"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMastodonClient_PostSendsAltText(t *testing.T) {
+	var descriptions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/media"):
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			descriptions = append(descriptions, r.FormValue("description"))
+			_, _ = w.Write([]byte(`{"id":"m1","type":"image"}`))
+		case strings.HasSuffix(r.URL.Path, "/statuses"):
+			_, _ = w.Write([]byte(`{"id":"s1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	image := NewMedia(pngSignature)
+	image.Description = "a cat on a keyboard"
+	client := NewMastodonClient(server.URL, "token", "mastodon")
+	_, err := client.Post(context.Background(), &Post{Content: "hello", Media: []Media{*image}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"a cat on a keyboard"}, descriptions)
+}
+
+func TestMastodonClient_ListPostsSince(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/verify_credentials"):
+			_, _ = w.Write([]byte(`{"id":"42","url":"https://example.social/@me"}`))
+		case strings.HasSuffix(r.URL.Path, "/accounts/42/statuses"):
+			queries = append(queries, r.URL.Query())
+			_, _ = w.Write([]byte(`[{"id":"110","content":"new","visibility":"public"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewMastodonClient(server.URL, "token", "mastodon")
+	_, err := client.ListPosts(context.Background(), 40)
+	require.NoError(t, err)
+	posts, err := client.ListPostsSince(context.Background(), 40, ListCursor{SinceID: "100"})
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, "110", posts[0].ID)
+
+	require.Len(t, queries, 2)
+	assert.Empty(t, queries[0].Get("since_id"))
+	assert.Equal(t, "100", queries[1].Get("since_id"))
+	assert.Equal(t, "40", queries[1].Get("limit"))
+}
+
+func TestMastodonStatusToPost(t *testing.T) {
+	created := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	status := &mastodon.Status{
+		ID:         "111",
+		URL:        "https://example.social/@me/111",
+		Content:    "<p>two photos</p>",
+		Visibility: "unlisted",
+		CreatedAt:  created,
+		MediaAttachments: []mastodon.Attachment{
+			{ID: "a1", Type: "image", URL: "https://files.example.social/a1.jpg", Description: "a cat on a keyboard"},
+			{ID: "a2", Type: "video", RemoteURL: "https://remote.example/a2.mp4"},
+		},
+	}
+
+	post := mastodonStatusToPost(context.Background(), status)
+	assert.Equal(t, "111", post.ID)
+	assert.Equal(t, "<p>two photos</p>", post.Content)
+	assert.Equal(t, VisibilityLevelUnlisted, post.Visibility)
+	assert.Equal(t, "https://example.social/@me/111", post.SourceURL)
+	assert.Equal(t, created, post.CreatedAt)
+
+	require.Len(t, post.Media, 2)
+	assert.Equal(t, "https://files.example.social/a1.jpg", post.Media[0].GetURL())
+	assert.Equal(t, "a cat on a keyboard", post.Media[0].Description)
+	assert.Equal(t, "https://remote.example/a2.mp4", post.Media[1].GetURL(), "uncached remote media falls back to remote_url")
+	assert.Empty(t, post.Media[1].Description)
+}
+
+func TestMastodonClient_ListPostsVisibility(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/verify_credentials"):
+			_, _ = w.Write([]byte(`{"id":"42","url":"https://example.social/@me"}`))
+		case strings.HasSuffix(r.URL.Path, "/accounts/42/statuses"):
+			_, _ = w.Write([]byte(`[
+				{"id":"1","visibility":"public"},
+				{"id":"2","visibility":"unlisted"},
+				{"id":"3","visibility":"private"},
+				{"id":"4","visibility":"direct"},
+				{"id":"5","visibility":"limited"},
+				{"id":"6","visibility":"public","media_attachments":[{"id":"m","type":"image","url":"https://files.example.social/m.png","description":"alt"}]}
+			]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	posts, err := NewMastodonClient(server.URL, "token", "mastodon").ListPosts(context.Background(), 20)
+	require.NoError(t, err)
+	require.Len(t, posts, 6)
+
+	want := []VisibilityLevel{
+		VisibilityLevelPublic, VisibilityLevelUnlisted, VisibilityLevelPrivate,
+		VisibilityLevelDirect, VisibilityLevelPrivate, VisibilityLevelPublic,
+	}
+	for i, post := range posts {
+		assert.Equal(t, want[i], post.Visibility, "status %s", post.ID)
+	}
+	assert.Empty(t, posts[0].Media)
+	require.Len(t, posts[5].Media, 1)
+	assert.Equal(t, "alt", posts[5].Media[0].Description)
+}
+
+func TestMastodonClient_ListPostsSkipsBoostsAndReplies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/verify_credentials"):
+			_, _ = w.Write([]byte(`{"id":"42","url":"https://example.social/@me"}`))
+		case strings.HasSuffix(r.URL.Path, "/accounts/42/statuses"):
+			_, _ = w.Write([]byte(`[
+				{"id":"4","visibility":"public","in_reply_to_id":"1","in_reply_to_account_id":"42"},
+				{"id":"3","visibility":"public","in_reply_to_id":"99","in_reply_to_account_id":"7"},
+				{"id":"2","visibility":"public","reblog":{"id":"98","visibility":"public","content":"someone else"}},
+				{"id":"1","visibility":"public"}
+			]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ids := func(posts []*Post) []string {
+		var out []string
+		for _, post := range posts {
+			out = append(out, post.ID)
+		}
+		return out
+	}
+
+	client := NewMastodonClient(server.URL, "token", "mastodon")
+	posts, err := client.ListPosts(context.Background(), 20)
+	require.NoError(t, err)
+	// 自己的串保留，并带上父帖 ID
+	assert.Equal(t, []string{"4", "1"}, ids(posts))
+	assert.Equal(t, "1", posts[0].InReplyTo)
+	assert.Empty(t, posts[1].InReplyTo)
+
+	client.SetIncludeReblogs(true)
+	client.SetIncludeReplies(true)
+	posts, err = client.ListPosts(context.Background(), 20)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"4", "3", "2", "1"}, ids(posts))
+	assert.Empty(t, posts[1].InReplyTo, "only self-replies are threaded")
+}
@@ -0,0 +1,231 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"butterfly.orx.me/core/log"
+)
+
+// MatrixClient implements SocialClient for posting to a Matrix room through
+// the client-server API. It only sends messages, so it is a sync target only.
+type MatrixClient struct {
+	name        string
+	homeserver  string
+	accessToken string
+	roomID      string
+	httpClient  *http.Client
+}
+
+// NewMatrixClient creates a client that posts to roomID on homeserver as the
+// user owning accessToken. httpClients supplies the timeout, proxy and TLS
+// settings; nil uses the defaults.
+func NewMatrixClient(name, homeserver, accessToken, roomID string, httpClients *HTTPClientFactory) *MatrixClient {
+	return &MatrixClient{
+		name:        name,
+		homeserver:  strings.TrimSuffix(homeserver, "/"),
+		accessToken: accessToken,
+		roomID:      roomID,
+		httpClient:  httpClientsOrDefault(httpClients).Client(),
+	}
+}
+
+func (c *MatrixClient) Name() string { return c.name }
+
+// matrixMessage is the content of an m.room.message event.
+type matrixMessage struct {
+	MsgType       string             `json:"msgtype"`
+	Body          string             `json:"body"`
+	Format        string             `json:"format,omitempty"`
+	FormattedBody string             `json:"formatted_body,omitempty"`
+	URL           string             `json:"url,omitempty"`
+	Info          *matrixMessageInfo `json:"info,omitempty"`
+}
+
+type matrixMessageInfo struct {
+	MimeType string `json:"mimetype,omitempty"`
+	Size     int    `json:"size,omitempty"`
+}
+
+// Post sends the text as an m.text message, then each media item as an
+// m.image (or m.video) message referencing the file uploaded to the content
+// repository. It returns the ID of the first event.
+func (c *MatrixClient) Post(ctx context.Context, post *Post) (interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	if err := checkVisibility(PlatformMatrix, post.Visibility); err != nil {
+		return nil, err
+	}
+	if err := ValidateMedia(PlatformMatrix.String(), post.Media); err != nil {
+		return nil, err
+	}
+
+	// 先上传全部媒体，避免文字已发出而图片上传失败
+	images := make([]matrixMessage, 0, len(post.Media))
+	for i := range post.Media {
+		msg, err := c.uploadMedia(ctx, &post.Media[i], i)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, *msg)
+	}
+
+	var messages []matrixMessage
+	if post.Content != "" {
+		text := matrixMessage{MsgType: "m.text", Body: post.Content}
+		if formatted, ok := matrixFormattedBody(post.Content); ok {
+			text.Format = "org.matrix.custom.html"
+			text.FormattedBody = formatted
+		}
+		messages = append(messages, text)
+	}
+	messages = append(messages, images...)
+	if len(messages) == 0 {
+		return nil, errors.New("matrix: post has no content or media")
+	}
+
+	var firstID string
+	for _, msg := range messages {
+		eventID, err := c.sendMessage(ctx, msg)
+		if err != nil {
+			return nil, err
+		}
+		if firstID == "" {
+			firstID = eventID
+		}
+	}
+
+	logger.Info("posted to matrix",
+		"client", c.name,
+		"room_id", c.roomID,
+		"event_id", firstID,
+		"media", len(images))
+
+	return map[string]string{"id": firstID}, nil
+}
+
+func (c *MatrixClient) ListPosts(_ context.Context, _ int) ([]*Post, error) {
+	return nil, errors.New("matrix: listing posts not supported")
+}
+
+// uploadMedia uploads m to the content repository and returns the message
+// that shows it.
+func (c *MatrixClient) uploadMedia(ctx context.Context, m *Media, index int) (*matrixMessage, error) {
+	data, err := m.GetDataFor(PlatformMatrix.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media data: %w", err)
+	}
+	contentType, err := m.ContentType()
+	if err != nil {
+		contentType = "application/octet-stream"
+	}
+	filename := fmt.Sprintf("media%d%s", index, m.Extension())
+
+	endpoint := fmt.Sprintf("%s/_matrix/media/v3/upload?filename=%s", c.homeserver, url.QueryEscape(filename))
+	respBody, err := c.do(ctx, http.MethodPost, endpoint, contentType, bytes.NewReader(data), "matrix upload")
+	if err != nil {
+		return nil, err
+	}
+	var uploaded struct {
+		ContentURI string `json:"content_uri"`
+	}
+	if err := DecodeJSON(respBody, &uploaded); err != nil {
+		return nil, fmt.Errorf("failed to parse matrix upload response: %w", err)
+	}
+
+	msgType := "m.image"
+	if m.IsVideo() {
+		msgType = "m.video"
+	}
+	body := m.Description
+	if body == "" {
+		body = filename
+	}
+	return &matrixMessage{
+		MsgType: msgType,
+		Body:    body,
+		URL:     uploaded.ContentURI,
+		Info:    &matrixMessageInfo{MimeType: contentType, Size: len(data)},
+	}, nil
+}
+
+// sendMessage sends one m.room.message event and returns its event ID. Each
+// call uses a new transaction ID, since the homeserver treats a repeated one
+// as a retry of the same event.
+func (c *MatrixClient) sendMessage(ctx context.Context, msg matrixMessage) (string, error) {
+	jsonData, err := json.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal matrix message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		c.homeserver, url.PathEscape(c.roomID), url.PathEscape(uuid.NewString()))
+	respBody, err := c.do(ctx, http.MethodPut, endpoint, "application/json", bytes.NewReader(jsonData), "matrix send")
+	if err != nil {
+		return "", err
+	}
+	var sent struct {
+		EventID string `json:"event_id"`
+	}
+	if err := DecodeJSON(respBody, &sent); err != nil {
+		return "", fmt.Errorf("failed to parse matrix send response: %w", err)
+	}
+	return sent.EventID, nil
+}
+
+// do sends an authenticated request and returns the body of a 2xx response.
+func (c *MatrixClient) do(ctx context.Context, method, endpoint, contentType string, body io.Reader, op string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", op, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := readResponseBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", op, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newPlatformError(c.name, op, resp, respBody)
+	}
+	return respBody, nil
+}
+
+var (
+	matrixLinkRe = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://[^\s)]+)\)`)
+	matrixBoldRe = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	matrixCodeRe = regexp.MustCompile("`([^`\n]+)`")
+)
+
+// matrixFormattedBody renders the Markdown links, bold text and inline code
+// in content as HTML. ok is false when content has no such markup, so the
+// message is sent as plain text only.
+func matrixFormattedBody(content string) (formatted string, ok bool) {
+	if !matrixLinkRe.MatchString(content) && !matrixBoldRe.MatchString(content) && !matrixCodeRe.MatchString(content) {
+		return "", false
+	}
+	formatted = html.EscapeString(content)
+	formatted = matrixLinkRe.ReplaceAllString(formatted, `<a href="$2">$1</a>`)
+	formatted = matrixBoldRe.ReplaceAllString(formatted, `<strong>$1</strong>`)
+	formatted = matrixCodeRe.ReplaceAllString(formatted, `<code>$1</code>`)
+	formatted = strings.ReplaceAll(formatted, "\n", "<br>")
+	return formatted, true
+}
@@ -0,0 +1,143 @@
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type matrixSend struct {
+	roomID  string
+	txnID   string
+	message matrixMessage
+}
+
+// fakeMatrixHomeserver records uploads and sent events and answers with
+// sequential content URIs and event IDs.
+type fakeMatrixHomeserver struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	auth    []string
+	uploads [][]byte
+	sends   []matrixSend
+}
+
+func newFakeMatrixHomeserver(t *testing.T) *fakeMatrixHomeserver {
+	t.Helper()
+	f := &fakeMatrixHomeserver{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.auth = append(f.auth, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+
+		const sendPrefix = "/_matrix/client/v3/rooms/"
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/_matrix/media/v3/upload":
+			f.uploads = append(f.uploads, body)
+			_, _ = w.Write([]byte(`{"content_uri":"mxc://example.org/media` + strconv.Itoa(len(f.uploads)) + `"}`))
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, sendPrefix):
+			// rooms/{roomId}/send/m.room.message/{txnId}
+			parts := strings.Split(strings.TrimPrefix(r.URL.Path, sendPrefix), "/")
+			require.Len(t, parts, 4)
+			require.Equal(t, "send", parts[1])
+			require.Equal(t, "m.room.message", parts[2])
+			var msg matrixMessage
+			require.NoError(t, json.Unmarshal(body, &msg))
+			f.sends = append(f.sends, matrixSend{roomID: parts[0], txnID: parts[3], message: msg})
+			_, _ = w.Write([]byte(`{"event_id":"$event` + strconv.Itoa(len(f.sends)) + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func TestMatrixClient_PostText(t *testing.T) {
+	hs := newFakeMatrixHomeserver(t)
+	client := NewMatrixClient("matrix", hs.server.URL+"/", "secret-token", "!room:example.org", nil)
+
+	result, err := client.Post(context.Background(), &Post{Content: "hello world"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "$event1"}, result)
+
+	_, err = client.Post(context.Background(), &Post{Content: "see [docs](https://example.com/a?b=1&c=2) and **this**"})
+	require.NoError(t, err)
+
+	require.Len(t, hs.sends, 2)
+	assert.Equal(t, "!room:example.org", hs.sends[0].roomID)
+	assert.Equal(t, matrixMessage{MsgType: "m.text", Body: "hello world"}, hs.sends[0].message, "plain text has no formatted_body")
+
+	formatted := hs.sends[1].message
+	assert.Equal(t, "m.text", formatted.MsgType)
+	assert.Equal(t, "see [docs](https://example.com/a?b=1&c=2) and **this**", formatted.Body)
+	assert.Equal(t, "org.matrix.custom.html", formatted.Format)
+	assert.Equal(t, `see <a href="https://example.com/a?b=1&amp;c=2">docs</a> and <strong>this</strong>`, formatted.FormattedBody)
+
+	assert.NotEmpty(t, hs.sends[0].txnID)
+	assert.NotEqual(t, hs.sends[0].txnID, hs.sends[1].txnID, "every event needs its own transaction ID")
+	for _, auth := range hs.auth {
+		assert.Equal(t, "Bearer secret-token", auth)
+	}
+}
+
+func TestMatrixClient_PostImage(t *testing.T) {
+	hs := newFakeMatrixHomeserver(t)
+	client := NewMatrixClient("matrix", hs.server.URL, "secret-token", "!room:example.org", nil)
+
+	image := NewMedia(pngHeader)
+	image.Description = "a cat"
+	result, err := client.Post(context.Background(), &Post{Content: "look", Media: []Media{*image, *NewMedia(pngHeader)}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "$event1"}, result, "the text event comes first")
+
+	require.Len(t, hs.uploads, 2)
+	assert.Equal(t, pngHeader, hs.uploads[0])
+
+	require.Len(t, hs.sends, 3)
+	assert.Equal(t, "m.text", hs.sends[0].message.MsgType)
+	first := hs.sends[1].message
+	assert.Equal(t, "m.image", first.MsgType)
+	assert.Equal(t, "a cat", first.Body)
+	assert.Equal(t, "mxc://example.org/media1", first.URL)
+	require.NotNil(t, first.Info)
+	assert.Equal(t, "image/png", first.Info.MimeType)
+	assert.Equal(t, len(pngHeader), first.Info.Size)
+	assert.Equal(t, "media1.png", hs.sends[2].message.Body, "the filename stands in for a missing description")
+	assert.Equal(t, "mxc://example.org/media2", hs.sends[2].message.URL)
+
+	txnIDs := map[string]bool{}
+	for _, send := range hs.sends {
+		txnIDs[send.txnID] = true
+	}
+	assert.Len(t, txnIDs, 3)
+}
+
+func TestMatrixClient_PostError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errcode":"M_FORBIDDEN"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewMatrixClient("matrix", server.URL, "token", "!room:example.org", nil).Post(context.Background(), &Post{Content: "hi"})
+
+	platformErr, ok := AsPlatformError(err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, http.StatusForbidden, platformErr.StatusCode)
+	assert.False(t, platformErr.Retryable)
+}
@@ -0,0 +1,106 @@
+package social
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MediaCacheEntry is media fetched by URL together with the validators the
+// server sent, used to revalidate it with a conditional request.
+type MediaCacheEntry struct {
+	Data         []byte
+	ContentType  string
+	ETag         string
+	LastModified string
+}
+
+// MediaFetchCache stores media fetched by URL. Implementations must be safe
+// for concurrent use.
+type MediaFetchCache interface {
+	Get(url string) (*MediaCacheEntry, bool)
+	Put(url string, entry *MediaCacheEntry)
+}
+
+// MediaCache, when set, lets Media.GetData revalidate media it has fetched
+// before with If-None-Match / If-Modified-Since and reuse the cached bytes
+// on a 304. It is set from sync.media_cache_bytes at startup; nil disables
+// caching.
+var MediaCache MediaFetchCache
+
+// MediaLRUCache is an in-memory MediaFetchCache holding at most maxBytes of
+// media data, evicting the least recently used entries first.
+type MediaLRUCache struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type mediaLRUItem struct {
+	url   string
+	entry *MediaCacheEntry
+}
+
+// NewMediaLRUCache creates a cache capped at maxBytes of media data.
+func NewMediaLRUCache(maxBytes int64) *MediaLRUCache {
+	return &MediaLRUCache{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the entry for url and marks it as recently used.
+func (c *MediaLRUCache) Get(url string) (*MediaCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[url]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*mediaLRUItem).entry, true
+}
+
+// Put stores entry for url, replacing any previous entry, and evicts the
+// least recently used entries until the cache fits. Entries larger than the
+// whole cache are not stored.
+func (c *MediaLRUCache) Put(url string, entry *MediaCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[url]; ok {
+		c.remove(elem)
+	}
+	n := int64(len(entry.Data))
+	if n > c.maxBytes {
+		return
+	}
+	for c.size+n > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+	c.entries[url] = c.order.PushFront(&mediaLRUItem{url: url, entry: entry})
+	c.size += n
+}
+
+// Len returns the number of cached entries.
+func (c *MediaLRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Size returns the bytes of media data held.
+func (c *MediaLRUCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+func (c *MediaLRUCache) remove(elem *list.Element) {
+	item := c.order.Remove(elem).(*mediaLRUItem)
+	delete(c.entries, item.url)
+	c.size -= int64(len(item.entry.Data))
+}
@@ -0,0 +1,126 @@
+package social
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withMediaCache(t *testing.T, cache MediaFetchCache) {
+	t.Helper()
+	prev := MediaCache
+	MediaCache = cache
+	t.Cleanup(func() { MediaCache = prev })
+}
+
+func TestMediaGetData_NotModifiedUsesCache(t *testing.T) {
+	withMaxMediaBytes(t, 1024)
+	withMediaCache(t, NewMediaLRUCache(1024))
+	png := append([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}, bytes.Repeat([]byte{1}, 100)...)
+
+	var gets, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			return
+		}
+		gets.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			assert.Equal(t, "Wed, 01 Jan 2025 00:00:00 GMT", r.Header.Get("If-Modified-Since"))
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Wed, 01 Jan 2025 00:00:00 GMT")
+		_, _ = w.Write(png)
+	}))
+	defer server.Close()
+
+	data, err := NewMediaFromURL(server.URL + "/a.png").GetData()
+	require.NoError(t, err)
+	assert.Equal(t, png, data)
+
+	// A later sync cycle builds a new Media for the same URL
+	media := NewMediaFromURL(server.URL + "/a.png")
+	data, err = media.GetData()
+	require.NoError(t, err)
+	assert.Equal(t, png, data, "a 304 must return the cached bytes")
+	contentType, err := media.ContentType()
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, int32(2), gets.Load())
+	assert.Equal(t, int32(1), notModified.Load())
+}
+
+func TestMediaGetData_ChangedMediaReplacesCache(t *testing.T) {
+	withMaxMediaBytes(t, 1024)
+	cache := NewMediaLRUCache(1024)
+	withMediaCache(t, cache)
+
+	var current atomic.Value
+	current.Store("v1")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := current.Load().(string)
+		if r.Header.Get("If-None-Match") == `"`+version+`"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"`+version+`"`)
+		_, _ = w.Write([]byte(version))
+	}))
+	defer server.Close()
+
+	_, err := NewMediaFromURL(server.URL + "/a.png").GetData()
+	require.NoError(t, err)
+
+	current.Store("v2")
+	data, err := NewMediaFromURL(server.URL + "/a.png").GetData()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2"), data)
+
+	entry, ok := cache.Get(server.URL + "/a.png")
+	require.True(t, ok)
+	assert.Equal(t, `"v2"`, entry.ETag)
+}
+
+func TestMediaLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMediaLRUCache(10)
+	cache.Put("a", &MediaCacheEntry{Data: []byte("aaaa")})
+	cache.Put("b", &MediaCacheEntry{Data: []byte("bbbb")})
+
+	// Touch a so b is the least recently used
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+
+	cache.Put("c", &MediaCacheEntry{Data: []byte("cccc")})
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "b should have been evicted")
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+	_, ok = cache.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, int64(8), cache.Size())
+}
+
+func TestMediaLRUCache_SkipsOversizedEntries(t *testing.T) {
+	cache := NewMediaLRUCache(10)
+	cache.Put("a", &MediaCacheEntry{Data: []byte("aaaa")})
+	cache.Put("huge", &MediaCacheEntry{Data: make([]byte, 11)})
+
+	_, ok := cache.Get("huge")
+	assert.False(t, ok)
+	_, ok = cache.Get("a")
+	assert.True(t, ok, "an entry too large to cache must not evict others")
+
+	// Replacing an entry updates the size
+	cache.Put("a", &MediaCacheEntry{Data: []byte("aa")})
+	assert.Equal(t, int64(2), cache.Size())
+}
@@ -0,0 +1,161 @@
+package social
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+
+	"go.orx.me/apps/hyper-sync/internal/metrics"
+)
+
+// DefaultMaxMediaBytes is the default cap on media fetched by URL.
+const DefaultMaxMediaBytes int64 = 25 << 20
+
+// MaxMediaBytes caps the size of media Media.GetData downloads; it is set
+// from sync.max_media_bytes at startup.
+var MaxMediaBytes = DefaultMaxMediaBytes
+
+// ErrMediaTooLarge is returned by Media.GetData when remote media exceeds
+// MaxMediaBytes.
+var ErrMediaTooLarge = errors.New("media too large")
+
+// MediaHTTPClient downloads media for Media.GetData; it is replaced with a
+// client from the configured HTTPClientFactory at startup.
+var MediaHTTPClient = defaultHTTPClients.Client()
+
+// mediaFetches shares one download between concurrent fetches of a URL.
+var mediaFetches singleflight.Group
+
+// mediaDataMu guards the data Media.GetDataFor stores on a Media, which
+// concurrent cross-posts of one post share.
+var mediaDataMu sync.Mutex
+
+// fetchedMedia is the result of a shared fetch.
+type fetchedMedia struct {
+	data        []byte
+	contentType string
+}
+
+// fetchMediaShared is fetchMedia with concurrent calls for the same url
+// sharing a single request. The download is counted once, for the platform
+// of the call that made it.
+func fetchMediaShared(url, platform string) (fetchedMedia, error) {
+	v, err, _ := mediaFetches.Do(url, func() (interface{}, error) {
+		data, contentType, downloaded, err := fetchMedia(url)
+		if err != nil {
+			return fetchedMedia{}, err
+		}
+		if platform == "" {
+			platform = "unknown"
+		}
+		if downloaded {
+			metrics.RecordMediaDownload(platform, len(data))
+		}
+		return fetchedMedia{data: data, contentType: contentType}, nil
+	})
+	return v.(fetchedMedia), err
+}
+
+// mediaHeader is what a HEAD or GET response says about the media.
+type mediaHeader struct {
+	// size is -1 when the server did not send Content-Length.
+	size        int64
+	contentType string
+}
+
+// headMedia asks the server about url without downloading it. ok is false
+// when the server does not answer HEAD usefully.
+func headMedia(url string) (mediaHeader, bool) {
+	resp, err := MediaHTTPClient.Head(url)
+	if err != nil {
+		return mediaHeader{}, false
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return mediaHeader{}, false
+	}
+	return mediaHeader{
+		size:        resp.ContentLength,
+		contentType: normalizeContentType(resp.Header.Get("Content-Type")),
+	}, true
+}
+
+// fetchMedia downloads url, refusing anything over MaxMediaBytes. A HEAD
+// request rejects media that announce their size up front; the GET body is
+// read through a limit so chunked or lying responses are cut off too. The
+// returned content type is the GET response's, or "" if it was generic.
+//
+// With a MediaCache, media fetched before is revalidated with a conditional
+// GET instead, and its cached bytes are returned on a 304; downloaded is
+// false then.
+func fetchMedia(url string) (data []byte, contentType string, downloaded bool, err error) {
+	limit := MaxMediaBytes
+
+	var cached *MediaCacheEntry
+	if MediaCache != nil {
+		cached, _ = MediaCache.Get(url)
+	}
+
+	// Not every server answers HEAD; the GET enforces the limit on its own.
+	// Cached media already fit, so they skip straight to revalidation.
+	if cached == nil {
+		if head, ok := headMedia(url); ok && head.size > limit {
+			return nil, "", false, fmt.Errorf("%w: %s is %d bytes, limit %d", ErrMediaTooLarge, url, head.size, limit)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to fetch media from URL %s: %w", url, err)
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := MediaHTTPClient.Do(req)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to fetch media from URL %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if cached != nil && resp.StatusCode == http.StatusNotModified {
+		return cached.Data, cached.ContentType, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", false, fmt.Errorf("failed to fetch media from URL %s: status code %d", url, resp.StatusCode)
+	}
+	if resp.ContentLength > limit {
+		return nil, "", false, fmt.Errorf("%w: %s is %d bytes, limit %d", ErrMediaTooLarge, url, resp.ContentLength, limit)
+	}
+
+	// Read one byte past the limit to tell "exactly at the limit" from "over".
+	data, err = io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", false, fmt.Errorf("failed to read media data from URL %s: %w", url, err)
+	}
+	if int64(len(data)) > limit {
+		return nil, "", false, fmt.Errorf("%w: %s exceeds limit %d", ErrMediaTooLarge, url, limit)
+	}
+	contentType = normalizeContentType(resp.Header.Get("Content-Type"))
+
+	// Only media the server can validate are worth keeping
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if MediaCache != nil && (etag != "" || lastModified != "") {
+		MediaCache.Put(url, &MediaCacheEntry{
+			Data:         data,
+			ContentType:  contentType,
+			ETag:         etag,
+			LastModified: lastModified,
+		})
+	}
+	return data, contentType, true, nil
+}
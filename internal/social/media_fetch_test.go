@@ -0,0 +1,177 @@
+package social
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withMaxMediaBytes(t *testing.T, n int64) {
+	t.Helper()
+	prev := MaxMediaBytes
+	MaxMediaBytes = n
+	t.Cleanup(func() { MaxMediaBytes = prev })
+}
+
+func TestMediaGetData_SmallImage(t *testing.T) {
+	withMaxMediaBytes(t, 1024)
+	png := append([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}, bytes.Repeat([]byte{0}, 100)...)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Length", strconv.Itoa(len(png)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(png)
+		}
+	}))
+	defer server.Close()
+
+	media := NewMediaFromURL(server.URL + "/small.png")
+	data, err := media.GetData()
+	require.NoError(t, err)
+	assert.Equal(t, png, data)
+}
+
+func TestMediaGetData_OversizedContentLength(t *testing.T) {
+	withMaxMediaBytes(t, 1024)
+	var gets atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1048576")
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+			_, _ = w.Write(make([]byte, 1<<20))
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewMediaFromURL(server.URL + "/huge.jpg").GetData()
+	assert.ErrorIs(t, err, ErrMediaTooLarge)
+	assert.Zero(t, gets.Load(), "oversized media must be rejected by the HEAD request")
+}
+
+func TestMediaGetData_LyingServerIsCutOff(t *testing.T) {
+	withMaxMediaBytes(t, 1024)
+
+	// HEAD claims a small file and the GET streams chunked data with no
+	// Content-Length, well past the limit.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.Header().Set("Content-Length", "10")
+			return
+		}
+		flusher := w.(http.Flusher)
+		chunk := make([]byte, 512)
+		for i := 0; i < 64; i++ {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}))
+	defer server.Close()
+
+	_, err := NewMediaFromURL(server.URL + "/lies.jpg").GetData()
+	assert.ErrorIs(t, err, ErrMediaTooLarge)
+}
+
+func TestMediaGetData_ExactlyAtLimit(t *testing.T) {
+	withMaxMediaBytes(t, 1024)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			// Some servers reject HEAD; the GET still enforces the limit.
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = w.Write(make([]byte, 1024))
+	}))
+	defer server.Close()
+
+	data, err := NewMediaFromURL(server.URL + "/exact.jpg").GetData()
+	require.NoError(t, err)
+	assert.Len(t, data, 1024)
+}
+
+func TestMediaGetData_LoaderCalledOnce(t *testing.T) {
+	var calls int
+	media := NewMediaFromLoader(func() ([]byte, error) {
+		calls++
+		return []byte("stored-bytes"), nil
+	})
+	assert.Zero(t, calls, "loader must not run before the data is needed")
+
+	for range 2 {
+		data, err := media.GetData()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("stored-bytes"), data)
+	}
+	assert.Equal(t, 1, calls)
+}
+
+func TestMediaGetData_LoaderError(t *testing.T) {
+	errGone := errors.New("gone")
+	media := NewMediaFromLoader(func() ([]byte, error) { return nil, errGone })
+
+	_, err := media.GetData()
+	assert.ErrorIs(t, err, errGone)
+}
+
+func TestMediaGetData_ConcurrentFetchesShareOneRequest(t *testing.T) {
+	withMaxMediaBytes(t, 1024)
+	png := append([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}, bytes.Repeat([]byte{2}, 100)...)
+
+	var heads, gets atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads.Add(1)
+		} else {
+			gets.Add(1)
+		}
+		// Keep the fetch in flight until every goroutine has asked for it
+		time.Sleep(100 * time.Millisecond)
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(png)
+	}))
+	defer server.Close()
+
+	const goroutines = 20
+	url := server.URL + "/shared.png"
+	// Half share one Media, as platforms posting the same post do; the
+	// others fetch the same URL through their own Media.
+	shared := NewMediaFromURL(url)
+	start := make(chan struct{})
+	results := make([][]byte, goroutines)
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		media := shared
+		if i%2 == 1 {
+			media = NewMediaFromURL(url)
+		}
+		wg.Add(1)
+		go func(i int, media *Media) {
+			defer wg.Done()
+			<-start
+			data, err := media.GetData()
+			assert.NoError(t, err)
+			results[i] = data
+		}(i, media)
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), gets.Load(), "concurrent fetches of one URL must share a single GET")
+	assert.Equal(t, int32(1), heads.Load())
+	for i, data := range results {
+		assert.Equal(t, png, data, "goroutine %d", i)
+	}
+}
@@ -0,0 +1,96 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go.orx.me/apps/hyper-sync/internal/metrics"
+)
+
+func TestMediaMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+
+	// 随机噪点让 JPEG 难以压缩，保证超过下面的大小限制
+	img := image.NewRGBA(image.Rect(0, 0, 300, 300))
+	rng := rand.New(rand.NewSource(1))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 300; x++ {
+			img.Set(x, y, color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, nil))
+	photo := buf.Bytes()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(photo)
+	}))
+	defer server.Close()
+
+	data, err := NewMediaFromURL(server.URL + "/photo.jpg").GetDataFor(PlatformBluesky.String())
+	require.NoError(t, err)
+	require.Equal(t, photo, data)
+
+	resized, err := resizeImageIfNeeded(data, len(data)/2)
+	require.NoError(t, err)
+	assert.Less(t, len(resized), len(data))
+	// 不超限时不计入 resize 指标
+	_, err = resizeImageIfNeeded(resized, len(resized))
+	require.NoError(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	downloaded, ok := mediaMetric(rm, "hypersync_media_downloaded_bytes_total").(metricdata.Sum[int64])
+	require.True(t, ok, "downloaded_bytes_total should be an int64 sum")
+	bytesByPlatform := make(map[string]int64)
+	for _, dp := range downloaded.DataPoints {
+		bytesByPlatform[mediaAttr(dp.Attributes, metrics.AttrPlatform)] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"bluesky": int64(len(photo))}, bytesByPlatform)
+
+	resizedTotal, ok := mediaMetric(rm, "hypersync_media_resized_total").(metricdata.Sum[int64])
+	require.True(t, ok, "resized_total should be an int64 sum")
+	resizes := make(map[string]int64)
+	for _, dp := range resizedTotal.DataPoints {
+		resizes[mediaAttr(dp.Attributes, metrics.AttrPlatform)+"/"+mediaAttr(dp.Attributes, metrics.AttrResult)] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"bluesky/success": 1}, resizes)
+
+	duration, ok := mediaMetric(rm, "hypersync_media_resize_duration_seconds").(metricdata.Histogram[float64])
+	require.True(t, ok, "resize_duration_seconds should be a float64 histogram")
+	require.Len(t, duration.DataPoints, 1)
+	assert.Equal(t, uint64(1), duration.DataPoints[0].Count)
+}
+
+func mediaMetric(rm metricdata.ResourceMetrics, name string) metricdata.Aggregation {
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	return nil
+}
+
+func mediaAttr(set attribute.Set, key string) string {
+	v, _ := set.Value(attribute.Key(key))
+	return v.AsString()
+}
@@ -0,0 +1,95 @@
+package social
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// mediaExtensions maps the media types platforms care about to the file
+// extension they expect; mime.ExtensionsByType does not know video types on
+// systems without /etc/mime.types.
+var mediaExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"video/mp4":       ".mp4",
+	"video/quicktime": ".mov",
+	"video/webm":      ".webm",
+}
+
+// ContentType returns the media's MIME type without parameters. For URL
+// media the server's Content-Type wins (from a HEAD request when the data
+// has not been downloaded yet); otherwise, or when the server only says
+// application/octet-stream, the type is sniffed from the bytes. The result
+// is cached.
+func (m *Media) ContentType() (string, error) {
+	if m.contentType != "" {
+		return m.contentType, nil
+	}
+
+	if m.data == nil && m.url != "" {
+		if head, ok := headMedia(m.url); ok && head.contentType != "" {
+			m.contentType = head.contentType
+			return m.contentType, nil
+		}
+	}
+
+	data, err := m.GetData()
+	if err != nil {
+		return "", err
+	}
+	// GetData records the GET response's Content-Type, if it had a useful one.
+	if m.contentType == "" {
+		m.contentType = normalizeContentType(http.DetectContentType(data))
+	}
+	return m.contentType, nil
+}
+
+// IsImage reports whether the media is an image. Media whose type cannot be
+// determined is neither an image nor a video.
+func (m *Media) IsImage() bool {
+	contentType, err := m.ContentType()
+	return err == nil && strings.HasPrefix(contentType, "image/")
+}
+
+// IsVideo reports whether the media is a video.
+func (m *Media) IsVideo() bool {
+	contentType, err := m.ContentType()
+	return err == nil && strings.HasPrefix(contentType, "video/")
+}
+
+// Extension returns a file extension (with the dot) matching the media's
+// content type, or ".bin" when it is unknown.
+func (m *Media) Extension() string {
+	contentType, err := m.ContentType()
+	if err != nil {
+		return ".bin"
+	}
+	return mediaExtension(contentType)
+}
+
+func mediaExtension(contentType string) string {
+	if ext, ok := mediaExtensions[contentType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(contentType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+// normalizeContentType strips parameters and lower-cases a Content-Type
+// header. Generic binary types carry no information and become "".
+func normalizeContentType(header string) string {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return ""
+	}
+	switch mediaType {
+	case "application/octet-stream", "binary/octet-stream":
+		return ""
+	}
+	return mediaType
+}
@@ -0,0 +1,98 @@
+package social
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	pngSignature  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegSignature = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	gifSignature  = []byte("GIF89a\x01\x00\x01\x00")
+	// a 24-byte ftyp box with the mp42 brand
+	mp4Signature = []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom")
+)
+
+func TestMedia_ContentType_Sniffed(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		ext     string
+		isImage bool
+		isVideo bool
+	}{
+		{name: "png", data: pngSignature, want: "image/png", ext: ".png", isImage: true},
+		{name: "jpeg", data: jpegSignature, want: "image/jpeg", ext: ".jpg", isImage: true},
+		{name: "gif", data: gifSignature, want: "image/gif", ext: ".gif", isImage: true},
+		{name: "mp4", data: mp4Signature, want: "video/mp4", ext: ".mp4", isVideo: true},
+		{name: "text", data: []byte("img"), want: "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMedia(tt.data)
+			got, err := m.ContentType()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.isImage, m.IsImage())
+			assert.Equal(t, tt.isVideo, m.IsVideo())
+			if tt.ext != "" {
+				assert.Equal(t, tt.ext, m.Extension())
+			}
+		})
+	}
+}
+
+func TestMedia_ContentType_ServerHeaderWins(t *testing.T) {
+	var heads, gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+		if r.Method == http.MethodHead {
+			heads++
+			return
+		}
+		gets++
+		_, _ = w.Write(jpegSignature)
+	}))
+	defer server.Close()
+
+	// The extension and even the bytes say JPEG; the server says MP4.
+	m := NewMediaFromURL(server.URL + "/photo.jpg")
+	assert.True(t, m.IsVideo())
+	assert.False(t, m.IsImage())
+	assert.Equal(t, ".mp4", m.Extension())
+	assert.Equal(t, 1, heads, "content type should be cached after the first lookup")
+	assert.Zero(t, gets, "classifying should not download the media")
+}
+
+func TestMedia_ContentType_OctetStreamFallsBackToSniffing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(pngSignature)
+	}))
+	defer server.Close()
+
+	m := NewMediaFromURL(server.URL + "/download")
+	got, err := m.ContentType()
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", got)
+}
+
+func TestMedia_ContentType_StripsParameters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "Image/WebP; charset=binary")
+		_, _ = w.Write([]byte("RIFF"))
+	}))
+	defer server.Close()
+
+	m := NewMediaFromURL(server.URL + "/a")
+	got, err := m.ContentType()
+	require.NoError(t, err)
+	assert.Equal(t, "image/webp", got)
+	assert.Equal(t, ".webp", m.Extension())
+}
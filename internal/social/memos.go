@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -31,14 +32,19 @@ type Memos struct {
 	name     string
 	Endpoint string
 	Token    string
+	// httpClient 为空时使用默认的超时与 transport
+	httpClient *http.Client
 }
 
-func NewMemos(endpoint, token, name string) *Memos {
+// NewMemos creates a Memos client. httpClients supplies the timeout, proxy
+// and TLS settings; nil uses the defaults.
+func NewMemos(endpoint, token, name string, httpClients *HTTPClientFactory) *Memos {
 	endpoint = strings.TrimSuffix(endpoint, "/")
 	return &Memos{
-		Endpoint: endpoint,
-		Token:    token,
-		name:     name,
+		Endpoint:   endpoint,
+		Token:      token,
+		name:       name,
+		httpClient: httpClientsOrDefault(httpClients).Client(),
 	}
 }
 
@@ -125,6 +131,7 @@ type Memo struct {
 	Pinned      bool         `json:"pinned"`
 	Attachments []Attachment `json:"attachments,omitempty"`
 	Relations   []string     `json:"relations,omitempty"`
+	Parent      string       `json:"parent,omitempty"` // 评论所属的备忘录，如 "memos/abc"
 	Reactions   []string     `json:"reactions,omitempty"`
 	Property    *Property    `json:"property,omitempty"`
 	Snippet     string       `json:"snippet,omitempty"`
@@ -192,7 +199,10 @@ func (m *Memos) makeRequest(ctx context.Context, method, path string, params url
 	}
 
 	// 发送请求
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := m.httpClient
+	if client == nil {
+		client = defaultHTTPClients.Client()
+	}
 	logger.Debug("sending HTTP request", "client", m.name)
 	resp, err := client.Do(httpReq)
 	if err != nil {
@@ -206,7 +216,7 @@ func (m *Memos) makeRequest(ctx context.Context, method, path string, params url
 	defer resp.Body.Close()
 
 	// 读取响应
-	responseBody, err := io.ReadAll(resp.Body)
+	responseBody, err := readResponseBody(resp.Body)
 	if err != nil {
 		logger.Error("failed to read response body",
 			"client", m.name,
@@ -232,7 +242,7 @@ func (m *Memos) makeRequest(ctx context.Context, method, path string, params url
 			"path", path,
 			"status_code", resp.StatusCode,
 			"response_body", string(responseBody))
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(responseBody))
+		return nil, newPlatformError(m.name, "API request", resp, responseBody)
 	}
 
 	logger.Info("API request completed successfully",
@@ -268,7 +278,7 @@ func (m *Memos) makeRequestAndParse(ctx context.Context, method, path string, pa
 			"client", m.name,
 			"response_size", len(respData))
 
-		if err := json.Unmarshal(respData, responseBody); err != nil {
+		if err := DecodeJSON(respData, responseBody); err != nil {
 			logger.Error("failed to parse response JSON",
 				"client", m.name,
 				"method", method,
@@ -332,12 +342,79 @@ func (m *Memos) ListMemos(ctx context.Context, req *ListMemosRequest) (*ListMemo
 	return &response, nil
 }
 
+// ListAllMemos 跟随 NextPageToken 逐页获取备忘录，直到没有下一页或达到 maxPages
+// (maxPages <= 0 表示不限制)。如果服务端重复返回同一个 token，则报错以避免死循环。
+func (m *Memos) ListAllMemos(ctx context.Context, req *ListMemosRequest, maxPages int) ([]Memo, error) {
+	var all []Memo
+	err := m.walkMemoPages(ctx, req, maxPages, func(page []Memo) bool {
+		all = append(all, page...)
+		return true
+	})
+	return all, err
+}
+
+// IterateMemos 返回一个逐条遍历所有备忘录的迭代器，按需翻页:
+//
+//	for memo := range memos.IterateMemos(ctx, req) { ... }
+//
+// 迭代器无法返回错误，请求失败时记录日志并结束遍历；需要感知错误时使用 ListAllMemos。
+func (m *Memos) IterateMemos(ctx context.Context, req *ListMemosRequest) iter.Seq[Memo] {
+	return func(yield func(Memo) bool) {
+		err := m.walkMemoPages(ctx, req, 0, func(page []Memo) bool {
+			for _, memo := range page {
+				if !yield(memo) {
+					return false
+				}
+			}
+			return true
+		})
+		if err != nil {
+			log.FromContext(ctx).Error("failed to iterate memos",
+				"client", m.name,
+				"error", err)
+		}
+	}
+}
+
+// walkMemoPages 逐页调用 ListMemos，把每一页交给 fn；fn 返回 false 时停止。
+func (m *Memos) walkMemoPages(ctx context.Context, req *ListMemosRequest, maxPages int, fn func([]Memo) bool) error {
+	// 复制请求，避免修改调用方的 PageToken
+	pageReq := ListMemosRequest{}
+	if req != nil {
+		pageReq = *req
+	}
+
+	seen := make(map[string]bool)
+	for page := 0; maxPages <= 0 || page < maxPages; page++ {
+		resp, err := m.ListMemos(ctx, &pageReq)
+		if err != nil {
+			return fmt.Errorf("list memos page %d: %w", page+1, err)
+		}
+		if !fn(resp.Memos) {
+			return nil
+		}
+
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		if seen[resp.NextPageToken] {
+			return fmt.Errorf("list memos: server returned page token %q twice", resp.NextPageToken)
+		}
+		seen[resp.NextPageToken] = true
+		pageReq.PageToken = resp.NextPageToken
+	}
+	return nil
+}
+
 func (m *Memos) Name() string {
 	return m.name
 }
 
 // Post implements SocialClient interface - posts content to Memos
 func (m *Memos) Post(ctx context.Context, post *Post) (interface{}, error) {
+	if err := checkVisibility(PlatformMemos, post.Visibility); err != nil {
+		return nil, err
+	}
 	visibility := GetPlatformVisibilityString(PlatformMemos.String(), post.Visibility)
 
 	memo, err := m.CreateMemo(ctx, &CreateMemoRequest{
@@ -374,92 +451,125 @@ func (m *Memos) Delete(ctx context.Context, platformID string) error {
 
 // ListPosts implements SocialClient interface - converts Memos to social Posts
 func (m *Memos) ListPosts(ctx context.Context, limit int) ([]*Post, error) {
+	return m.ListPostsSince(ctx, limit, ListCursor{})
+}
+
+// ListPostsSince 只列出创建时间不早于 since.SinceTime 的 memo（memo 名称无序，
+// 忽略 SinceID）。边界上的 memo 会再次返回，由调用方去重。
+func (m *Memos) ListPostsSince(ctx context.Context, limit int, since ListCursor) ([]*Post, error) {
 	req := &ListMemosRequest{
 		PageSize: limit,
 		OrderBy:  "display_time desc",
 	}
+	if !since.SinceTime.IsZero() {
+		req.Filter = fmt.Sprintf("created_ts >= %d", since.SinceTime.Unix())
+	}
 
 	resp, err := m.ListMemos(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	var posts []*Post
+	posts := make([]*Post, 0, len(resp.Memos))
 	for _, memo := range resp.Memos {
-		var medias = make([]Media, 0)
-
-		// 处理新版 API 的 Attachments
-		if memo.Attachments != nil {
-			for _, attachment := range memo.Attachments {
-				// 根据附件类型创建不同的 Media 对象
-				if attachment.ExternalLink != "" {
-					// 如果有外部链接，使用外部链接创建 Media
-					media := NewMediaFromURL(attachment.ExternalLink)
-					media.Description = attachment.Filename
-					medias = append(medias, *media)
-				} else if attachment.Content != "" {
-					// 如果有内容数据，使用内容创建 Media
-					media := NewMedia([]byte(attachment.Content))
-					media.Description = attachment.Filename
-					medias = append(medias, *media)
-				} else if attachment.Name != "" {
-					// 如果有附件名称，构建资源 URL
-					resourceURL := fmt.Sprintf("%s/file/%s/%s", m.Endpoint, attachment.Name, attachment.Filename)
-					media := NewMediaFromURL(resourceURL)
-					media.Description = attachment.Filename
-					medias = append(medias, *media)
-				}
+		posts = append(posts, m.memoToPost(memo))
+	}
+
+	return posts, nil
+}
+
+// GetPost 按 ID（memo 名称 memos/<id> 或裸 id）获取单个 memo
+func (m *Memos) GetPost(ctx context.Context, id string) (*Post, error) {
+	memo, err := m.GetMemo(ctx, strings.TrimPrefix(id, "memos/"))
+	if err != nil {
+		return nil, err
+	}
+	return m.memoToPost(*memo), nil
+}
+
+// memoToPost 把 memo 转换为 Post
+func (m *Memos) memoToPost(memo Memo) *Post {
+	var medias = make([]Media, 0)
+
+	// 处理新版 API 的 Attachments
+	if memo.Attachments != nil {
+		for _, attachment := range memo.Attachments {
+			// 根据附件类型创建不同的 Media 对象
+			if attachment.ExternalLink != "" {
+				// 如果有外部链接，使用外部链接创建 Media
+				media := NewMediaFromURL(attachment.ExternalLink)
+				media.Description = attachment.Filename
+				medias = append(medias, *media)
+			} else if attachment.Content != "" {
+				// 如果有内容数据，使用内容创建 Media
+				media := NewMedia([]byte(attachment.Content))
+				media.Description = attachment.Filename
+				medias = append(medias, *media)
+			} else if attachment.Name != "" {
+				// 如果有附件名称，构建资源 URL
+				resourceURL := fmt.Sprintf("%s/file/%s/%s", m.Endpoint, attachment.Name, attachment.Filename)
+				media := NewMediaFromURL(resourceURL)
+				media.Description = attachment.Filename
+				medias = append(medias, *media)
 			}
-		} else if memo.Resources != nil {
-			// 向后兼容：处理旧版 API 的 Resources
-			for _, resource := range memo.Resources {
-				// 根据资源类型创建不同的 Media 对象
-				if resource.ExternalLink != "" {
-					// 如果有外部链接，使用外部链接创建 Media
-					media := NewMediaFromURL(resource.ExternalLink)
-					media.Description = resource.Filename
-					medias = append(medias, *media)
-				} else if resource.Content != "" {
-					// 如果有内容数据，使用内容创建 Media
-					media := NewMedia([]byte(resource.Content))
-					media.Description = resource.Filename
-					medias = append(medias, *media)
-				} else if resource.Name != "" {
-					// 如果有资源名称，构建资源 URL
-					resourceURL := fmt.Sprintf("%s/file/%s/%s", m.Endpoint, resource.Name, resource.Filename)
-					media := NewMediaFromURL(resourceURL)
-					media.Description = resource.Filename
-					medias = append(medias, *media)
-				}
+		}
+	} else if memo.Resources != nil {
+		// 向后兼容：处理旧版 API 的 Resources
+		for _, resource := range memo.Resources {
+			// 根据资源类型创建不同的 Media 对象
+			if resource.ExternalLink != "" {
+				// 如果有外部链接，使用外部链接创建 Media
+				media := NewMediaFromURL(resource.ExternalLink)
+				media.Description = resource.Filename
+				medias = append(medias, *media)
+			} else if resource.Content != "" {
+				// 如果有内容数据，使用内容创建 Media
+				media := NewMedia([]byte(resource.Content))
+				media.Description = resource.Filename
+				medias = append(medias, *media)
+			} else if resource.Name != "" {
+				// 如果有资源名称，构建资源 URL
+				resourceURL := fmt.Sprintf("%s/file/%s/%s", m.Endpoint, resource.Name, resource.Filename)
+				media := NewMediaFromURL(resourceURL)
+				media.Description = resource.Filename
+				medias = append(medias, *media)
 			}
 		}
+	}
 
-		// Convert string visibility to enum
-		visibility, err := ParsePlatformVisibility(PlatformMemos.String(), memo.Visibility)
-		if err != nil {
-			// Use default visibility if parsing fails
-			visibility = VisibilityLevelPublic
-		}
+	// Convert string visibility to enum
+	visibility, err := ParsePlatformVisibility(PlatformMemos.String(), memo.Visibility)
+	if err != nil {
+		// Use default visibility if parsing fails
+		visibility = VisibilityLevelPublic
+	}
 
-		// 使用 memo.Name 作为 OriginalID，向后兼容旧的 UID 字段
-		originalID := memo.Name
-		if memo.UID != "" {
-			originalID = memo.UID
-		}
+	// 使用 memo.Name 作为 OriginalID，向后兼容旧的 UID 字段
+	originalID := memo.Name
+	if memo.UID != "" {
+		originalID = memo.UID
+	}
 
-		post := &Post{
-			ID:             memo.Name,
-			Content:        memo.Content,
-			Visibility:     visibility,
-			Media:          medias,
-			SourcePlatform: m.name,
-			OriginalID:     originalID,
-			CreatedAt:      memo.CreateTime,
-		}
-		posts = append(posts, post)
+	return &Post{
+		ID:             memo.Name,
+		Content:        memo.Content,
+		Visibility:     visibility,
+		Media:          medias,
+		SourcePlatform: m.name,
+		OriginalID:     originalID,
+		SourceURL:      m.SourceURL(&Post{OriginalID: originalID}),
+		InReplyTo:      memo.Parent,
+		CreatedAt:      memo.CreateTime,
 	}
+}
 
-	return posts, nil
+// SourceURL 返回 memo 的公开链接：<endpoint>/m/<uid>
+func (m *Memos) SourceURL(post *Post) string {
+	id := strings.TrimPrefix(postOriginalID(post), "memos/")
+	if id == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/m/%s", strings.TrimSuffix(m.Endpoint, "/"), id)
 }
 
 // GetMemo 获取单个备忘录
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 	"time"
@@ -19,7 +20,7 @@ func TestMemos_ListMemos_Localhost(t *testing.T) {
 		t.Skip("MEMOS_ENDPOINT environment variable not set, skipping localhost test")
 	}
 
-	memos := NewMemos(endpoint, token, "memos")
+	memos := NewMemos(endpoint, token, "memos", nil)
 
 	response, err := memos.ListPosts(context.Background(), 10)
 	if err != nil {
@@ -253,7 +254,7 @@ func TestMemos_ListMemos(t *testing.T) {
 			defer server.Close()
 
 			// 创建Memos实例
-			memos := NewMemos(server.URL, tt.token, "memos")
+			memos := NewMemos(server.URL, tt.token, "memos", nil)
 
 			// 发送请求并检查响应
 			response, err := memos.ListMemos(context.Background(), tt.request)
@@ -348,7 +349,7 @@ func TestMemos_ListMemos_InvalidJSON(t *testing.T) {
 	}))
 	defer server.Close()
 
-	memos := NewMemos(server.URL, "test-token", "memos")
+	memos := NewMemos(server.URL, "test-token", "memos", nil)
 
 	_, err := memos.ListMemos(context.Background(), nil)
 	if err == nil {
@@ -357,7 +358,7 @@ func TestMemos_ListMemos_InvalidJSON(t *testing.T) {
 }
 
 func TestMemos_ListMemos_NetworkError(t *testing.T) {
-	memos := NewMemos("http://invalid-host:9999", "test-token", "memos")
+	memos := NewMemos("http://invalid-host:9999", "test-token", "memos", nil)
 
 	_, err := memos.ListMemos(context.Background(), nil)
 	if err == nil {
@@ -370,7 +371,7 @@ func TestNewMemos(t *testing.T) {
 	token := "test-token"
 	name := "test-memos"
 
-	memos := NewMemos(endpoint, token, name)
+	memos := NewMemos(endpoint, token, name, nil)
 
 	// Check if trailing slash is removed
 	if memos.Endpoint != "https://example.com" {
@@ -404,7 +405,7 @@ func TestListMemosDefaultOrderBy(t *testing.T) {
 	}))
 	defer server.Close()
 
-	memos := NewMemos(server.URL, "test-token", "memos")
+	memos := NewMemos(server.URL, "test-token", "memos", nil)
 
 	// Test with nil request - should set default orderBy
 	_, err := memos.ListMemos(context.Background(), nil)
@@ -442,7 +443,7 @@ func TestListMemosCustomOrderBy(t *testing.T) {
 	}))
 	defer server.Close()
 
-	memos := NewMemos(server.URL, "test-token", "memos")
+	memos := NewMemos(server.URL, "test-token", "memos", nil)
 
 	// Test with custom orderBy
 	req := &ListMemosRequest{
@@ -500,7 +501,7 @@ func TestMemosURLConstruction(t *testing.T) {
 			}))
 			defer server.Close()
 
-			memos := NewMemos(server.URL, "test-token", "memos")
+			memos := NewMemos(server.URL, "test-token", "memos", nil)
 
 			_, err := memos.ListMemos(context.Background(), &ListMemosRequest{PageSize: 5})
 			if err != nil {
@@ -509,3 +510,155 @@ func TestMemosURLConstruction(t *testing.T) {
 		})
 	}
 }
+
+// newPagedMemosServer serves two pages of memos linked by a page token.
+func newPagedMemosServer(t *testing.T, secondToken string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			json.NewEncoder(w).Encode(ListMemosResponse{
+				Memos:         []Memo{{Name: "memos/1"}, {Name: "memos/2"}},
+				NextPageToken: "page-2",
+			})
+		case "page-2":
+			json.NewEncoder(w).Encode(ListMemosResponse{
+				Memos:         []Memo{{Name: "memos/3"}},
+				NextPageToken: secondToken,
+			})
+		default:
+			t.Errorf("unexpected pageToken %q", r.URL.Query().Get("pageToken"))
+		}
+	}))
+}
+
+func TestMemos_ListAllMemos(t *testing.T) {
+	server := newPagedMemosServer(t, "")
+	defer server.Close()
+
+	memos := NewMemos(server.URL, "test-token", "memos", nil)
+	req := &ListMemosRequest{PageSize: 2}
+
+	all, err := memos.ListAllMemos(context.Background(), req, 0)
+	if err != nil {
+		t.Fatalf("ListAllMemos failed: %v", err)
+	}
+	if len(all) != 3 || all[2].Name != "memos/3" {
+		t.Errorf("Expected 3 memos across two pages, got %+v", all)
+	}
+	if req.PageToken != "" {
+		t.Errorf("Expected caller's request to be left untouched, got PageToken %q", req.PageToken)
+	}
+
+	limited, err := memos.ListAllMemos(context.Background(), req, 1)
+	if err != nil {
+		t.Fatalf("ListAllMemos failed: %v", err)
+	}
+	if len(limited) != 2 {
+		t.Errorf("Expected maxPages=1 to return only the first page, got %d memos", len(limited))
+	}
+}
+
+func TestMemos_ListAllMemos_RepeatedToken(t *testing.T) {
+	// 第二页又返回 "page-2"，应该报错而不是无限循环
+	server := newPagedMemosServer(t, "page-2")
+	defer server.Close()
+
+	memos := NewMemos(server.URL, "test-token", "memos", nil)
+
+	all, err := memos.ListAllMemos(context.Background(), &ListMemosRequest{PageSize: 2}, 0)
+	if err == nil {
+		t.Fatal("Expected error for repeated page token, got none")
+	}
+	if len(all) != 3 {
+		t.Errorf("Expected memos fetched before the error to be returned, got %d", len(all))
+	}
+}
+
+func TestMemos_IterateMemos(t *testing.T) {
+	server := newPagedMemosServer(t, "")
+	defer server.Close()
+
+	memos := NewMemos(server.URL, "test-token", "memos", nil)
+
+	var names []string
+	for memo := range memos.IterateMemos(context.Background(), &ListMemosRequest{PageSize: 2}) {
+		names = append(names, memo.Name)
+	}
+	if len(names) != 3 {
+		t.Errorf("Expected to iterate 3 memos, got %v", names)
+	}
+
+	// 提前 break 时不应该继续翻页
+	count := 0
+	for range memos.IterateMemos(context.Background(), nil) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("Expected break to stop iteration, got %d", count)
+	}
+}
+
+// TestMemos_ListPostsSince tests that the cursor narrows the query by create time
+func TestMemos_ListPostsSince(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ListMemosResponse{Memos: []Memo{}})
+	}))
+	defer server.Close()
+
+	memos := NewMemos(server.URL, "test-token", "memos", nil)
+	since := time.Unix(1700000000, 0)
+
+	if _, err := memos.ListPosts(context.Background(), 30); err != nil {
+		t.Fatalf("ListPosts failed: %v", err)
+	}
+	if _, err := memos.ListPostsSince(context.Background(), 30, ListCursor{SinceID: "memos/1", SinceTime: since}); err != nil {
+		t.Fatalf("ListPostsSince failed: %v", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(queries))
+	}
+	if got := queries[0].Get("filter"); got != "" {
+		t.Errorf("Expected no filter without a cursor, got '%s'", got)
+	}
+	if got := queries[1].Get("filter"); got != "created_ts >= 1700000000" {
+		t.Errorf("Expected create time filter, got '%s'", got)
+	}
+	for _, q := range queries {
+		if got := q.Get("pageSize"); got != "30" {
+			t.Errorf("Expected pageSize=30, got %s", got)
+		}
+	}
+}
+
+func TestMemos_GetPost(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Memo{Name: "memos/abc", Content: "hello", Visibility: "PUBLIC"})
+	}))
+	defer server.Close()
+
+	memos := NewMemos(server.URL, "test-token", "memos", nil)
+
+	for _, id := range []string{"memos/abc", "abc"} {
+		post, err := memos.GetPost(context.Background(), id)
+		if err != nil {
+			t.Fatalf("GetPost(%s) failed: %v", id, err)
+		}
+		if post.ID != "memos/abc" || post.Content != "hello" || post.SourcePlatform != "memos" {
+			t.Errorf("Unexpected post for %s: %+v", id, post)
+		}
+	}
+	for _, path := range paths {
+		if path != "/api/v1/memos/abc" {
+			t.Errorf("Expected path /api/v1/memos/abc, got %s", path)
+		}
+	}
+}
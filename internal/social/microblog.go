@@ -0,0 +1,225 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"butterfly.orx.me/core/log"
+)
+
+// microblogUploadTimeout is the minimum client timeout, since media uploads
+// can take longer than an ordinary API call.
+const microblogUploadTimeout = 60 * time.Second
+
+// MicroblogClient implements SocialClient for publishing to Micro.blog (or
+// any Micropub server) through the Micropub protocol. It is a sync target
+// only.
+type MicroblogClient struct {
+	name       string
+	endpoint   string
+	token      string
+	httpClient *http.Client
+
+	// mediaEndpoint 来自 q=config 查询，首次上传媒体时获取并缓存
+	mediaMu       sync.Mutex
+	mediaEndpoint string
+}
+
+// NewMicroblogClient creates a client for the Micropub endpoint, e.g.
+// https://micro.blog/micropub. httpClients supplies the timeout, proxy and
+// TLS settings; nil uses the defaults.
+func NewMicroblogClient(name, endpoint, token string, httpClients *HTTPClientFactory) *MicroblogClient {
+	httpClients = httpClientsOrDefault(httpClients)
+	return &MicroblogClient{
+		name:     name,
+		endpoint: endpoint,
+		token:    token,
+		httpClient: &http.Client{
+			// 媒体上传可能较慢，超时不短于 microblogUploadTimeout
+			Timeout:   max(httpClients.Timeout(), microblogUploadTimeout),
+			Transport: httpClients.Transport(),
+		},
+	}
+}
+
+func (m *MicroblogClient) Name() string { return m.name }
+
+// Post creates an h-entry with the post content. Media is uploaded to the
+// media endpoint first and referenced by URL through photo[]. It returns the
+// URL from the Location header as both ID and URL.
+func (m *MicroblogClient) Post(ctx context.Context, post *Post) (interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	if err := checkVisibility(PlatformMicroblog, post.Visibility); err != nil {
+		return nil, err
+	}
+	if err := ValidateMedia(PlatformMicroblog.String(), post.Media); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(post.Content) == "" && len(post.Media) == 0 {
+		return nil, errors.New("microblog: post has no content or media")
+	}
+
+	form := url.Values{}
+	form.Set("h", "entry")
+	form.Set("content", post.Content)
+	hasAlt := false
+	for i := range post.Media {
+		photoURL, err := m.uploadMedia(ctx, &post.Media[i], i)
+		if err != nil {
+			return nil, err
+		}
+		form.Add("photo[]", photoURL)
+		if post.Media[i].Description != "" {
+			hasAlt = true
+		}
+	}
+	// mp-photo-alt[] 按顺序对应 photo[]，只要有一张带 alt 就全部发送
+	if hasAlt {
+		for _, media := range post.Media {
+			form.Add("mp-photo-alt[]", media.Description)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create micropub request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	location, err := m.doLocation(req, "micropub create")
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("posted to micro.blog",
+		"client", m.name,
+		"url", location,
+		"media", len(post.Media))
+
+	return map[string]string{"id": location, "url": location}, nil
+}
+
+func (m *MicroblogClient) ListPosts(_ context.Context, _ int) ([]*Post, error) {
+	return nil, errors.New("microblog: listing posts not supported")
+}
+
+// uploadMedia sends m to the media endpoint as the multipart "file" field and
+// returns the URL of the uploaded file.
+func (m *MicroblogClient) uploadMedia(ctx context.Context, media *Media, index int) (string, error) {
+	endpoint, err := m.getMediaEndpoint(ctx)
+	if err != nil {
+		return "", err
+	}
+	data, err := media.GetDataFor(PlatformMicroblog.String())
+	if err != nil {
+		return "", fmt.Errorf("failed to get media data: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fmt.Sprintf("media%d%s", index, media.Extension()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", fmt.Errorf("failed to write media data: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create micropub media request: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return m.doLocation(req, "micropub upload media")
+}
+
+// getMediaEndpoint discovers the media endpoint with a q=config query and
+// caches it.
+func (m *MicroblogClient) getMediaEndpoint(ctx context.Context) (string, error) {
+	m.mediaMu.Lock()
+	defer m.mediaMu.Unlock()
+	if m.mediaEndpoint != "" {
+		return m.mediaEndpoint, nil
+	}
+
+	endpoint, err := url.Parse(m.endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid micropub endpoint: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("q", "config")
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create micropub config request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	respBody, _, err := m.do(req, "micropub config")
+	if err != nil {
+		return "", err
+	}
+	var config struct {
+		MediaEndpoint string `json:"media-endpoint"`
+	}
+	if err := DecodeJSON(respBody, &config); err != nil {
+		return "", fmt.Errorf("failed to parse micropub config: %w", err)
+	}
+	if config.MediaEndpoint == "" {
+		return "", errors.New("microblog: server has no media endpoint")
+	}
+
+	// 相对地址按 Micropub 端点解析
+	mediaEndpoint, err := endpoint.Parse(config.MediaEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid micropub media endpoint: %w", err)
+	}
+	m.mediaEndpoint = mediaEndpoint.String()
+	return m.mediaEndpoint, nil
+}
+
+// doLocation sends req and returns the Location header of the response,
+// which Micropub uses for the URL of a created post or uploaded file.
+func (m *MicroblogClient) doLocation(req *http.Request, op string) (string, error) {
+	_, header, err := m.do(req, op)
+	if err != nil {
+		return "", err
+	}
+	location := header.Get("Location")
+	if location == "" {
+		return "", fmt.Errorf("%s: response has no Location header", op)
+	}
+	return location, nil
+}
+
+// do sends req with the Bearer token and returns the body and headers of a
+// 2xx response.
+func (m *MicroblogClient) do(req *http.Request, op string) ([]byte, http.Header, error) {
+	req.Header.Set("Authorization", "Bearer "+m.token)
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s request failed: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := readResponseBody(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s response: %w", op, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, newPlatformError(m.name, op, resp, respBody)
+	}
+	return respBody, resp.Header, nil
+}
@@ -0,0 +1,123 @@
+package social
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMicropub serves a Micropub endpoint at /micropub and a media endpoint
+// at /media, recording created entries and uploaded files.
+type fakeMicropub struct {
+	server *httptest.Server
+
+	mu         sync.Mutex
+	auth       []string
+	configHits int
+	entries    []url.Values
+	uploads    [][]byte
+	filenames  []string
+}
+
+func newFakeMicropub(t *testing.T) *fakeMicropub {
+	t.Helper()
+	f := &fakeMicropub{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.auth = append(f.auth, r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/micropub" && r.URL.Query().Get("q") == "config":
+			f.configHits++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"media-endpoint":"/media"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/micropub":
+			require.NoError(t, r.ParseForm())
+			f.entries = append(f.entries, r.PostForm)
+			w.Header().Set("Location", "https://example.micro.blog/2024/01/01/hello.html")
+			w.WriteHeader(http.StatusAccepted)
+		case r.Method == http.MethodPost && r.URL.Path == "/media":
+			file, header, err := r.FormFile("file")
+			require.NoError(t, err)
+			data, err := io.ReadAll(file)
+			require.NoError(t, err)
+			f.uploads = append(f.uploads, data)
+			f.filenames = append(f.filenames, header.Filename)
+			w.Header().Set("Location", "https://cdn.micro.blog/photo"+strconv.Itoa(len(f.uploads))+".png")
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func TestMicroblogClient_Post(t *testing.T) {
+	mp := newFakeMicropub(t)
+	client := NewMicroblogClient("microblog", mp.server.URL+"/micropub", "app-token", nil)
+
+	result, err := client.Post(context.Background(), &Post{Content: "hello & welcome"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"id":  "https://example.micro.blog/2024/01/01/hello.html",
+		"url": "https://example.micro.blog/2024/01/01/hello.html",
+	}, result)
+
+	require.Len(t, mp.entries, 1)
+	assert.Equal(t, "entry", mp.entries[0].Get("h"))
+	assert.Equal(t, "hello & welcome", mp.entries[0].Get("content"))
+	assert.Empty(t, mp.entries[0]["photo[]"])
+	assert.Zero(t, mp.configHits, "no media means no config query")
+	assert.Equal(t, []string{"Bearer app-token"}, mp.auth)
+}
+
+func TestMicroblogClient_PostPhotos(t *testing.T) {
+	mp := newFakeMicropub(t)
+	client := NewMicroblogClient("microblog", mp.server.URL+"/micropub", "app-token", nil)
+
+	first := NewMedia(pngHeader)
+	first.Description = "a cat"
+	_, err := client.Post(context.Background(), &Post{Content: "photos", Media: []Media{*first, *NewMedia(pngHeader)}})
+	require.NoError(t, err)
+
+	require.Len(t, mp.uploads, 2)
+	assert.Equal(t, pngHeader, mp.uploads[0])
+	assert.Equal(t, []string{"media0.png", "media1.png"}, mp.filenames)
+
+	require.Len(t, mp.entries, 1)
+	assert.Equal(t, []string{"https://cdn.micro.blog/photo1.png", "https://cdn.micro.blog/photo2.png"}, mp.entries[0]["photo[]"])
+	assert.Equal(t, []string{"a cat", ""}, mp.entries[0]["mp-photo-alt[]"])
+
+	_, err = client.Post(context.Background(), &Post{Content: "again", Media: []Media{*NewMedia(pngHeader)}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, mp.configHits, "the media endpoint is cached")
+	assert.Nil(t, mp.entries[1]["mp-photo-alt[]"])
+	for _, auth := range mp.auth {
+		assert.Equal(t, "Bearer app-token", auth)
+	}
+}
+
+func TestMicroblogClient_PostError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"unauthorized"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewMicroblogClient("microblog", server.URL, "bad", nil).Post(context.Background(), &Post{Content: "hi"})
+
+	platformErr, ok := AsPlatformError(err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, http.StatusUnauthorized, platformErr.StatusCode)
+	assert.Equal(t, "microblog", platformErr.Platform)
+}
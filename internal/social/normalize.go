@@ -0,0 +1,124 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NormalizeRule rewrites post content, e.g. to drop syntax that only makes
+// sense on the source platform.
+type NormalizeRule func(content string) string
+
+// Names of the built-in normalization rules, used in PlatformConfig.Normalize.
+const (
+	// NormalizeStripHashtags 去掉 #tag 形式的标签（Memos 的 #a/b 嵌套标签同样去掉），不写则保留标签
+	NormalizeStripHashtags = "strip_hashtags"
+	// NormalizeTaskLists 把任务列表 "- [ ] 待办" / "- [x] 已完成" 转成普通列表 "- 待办" / "- ✓ 已完成"
+	NormalizeTaskLists = "task_lists"
+	// NormalizeStripMemoResources 去掉 Memos 的资源占位符，如 ![[resources/1]] 与指向 /file/ 的内联图片；
+	// 附件会作为媒体单独上传
+	NormalizeStripMemoResources = "strip_memo_resources"
+)
+
+// NormalizeRules holds the rules PlatformConfig.Normalize can name. Add to it
+// before InitSocialPlatforms to make further rules configurable.
+var NormalizeRules = map[string]NormalizeRule{
+	NormalizeStripHashtags:      stripHashtags,
+	NormalizeTaskLists:          convertTaskLists,
+	NormalizeStripMemoResources: stripMemoResources,
+}
+
+var (
+	// A tag starts a line or follows whitespace, so "# Heading" and URL
+	// fragments are left alone.
+	hashtagPattern  = regexp.MustCompile(`(^|\s)#[\p{L}\p{N}_/-]+`)
+	taskListPattern = regexp.MustCompile(`(?m)^(\s*)[-*+] \[([ xX])\] `)
+	// Memos embeds (![[resources/1]], ![[memos/abc]]) and inline images of
+	// its own uploads, whose relative paths are dead anywhere else.
+	memoResourcePattern = regexp.MustCompile(`!\[\[[^\]\n]*\]\]|!\[[^\]\n]*\]\((?:/file/|/o/r/)[^)\s]*\)`)
+	extraSpacePattern   = regexp.MustCompile(`(\S)[ \t]{2,}`)
+	blankLinesPattern   = regexp.MustCompile(`\n{3,}`)
+)
+
+// NormalizeContent applies the named rules to content in order. Unknown
+// names are ignored; validateNormalizeRules rejects them at startup.
+func NormalizeContent(content string, rules []string) string {
+	if len(rules) == 0 {
+		return content
+	}
+	for _, name := range rules {
+		if rule, ok := NormalizeRules[name]; ok {
+			content = rule(content)
+		}
+	}
+	return strings.TrimSpace(content)
+}
+
+func validateNormalizeRules(rules []string) error {
+	for _, name := range rules {
+		if _, ok := NormalizeRules[name]; !ok {
+			return fmt.Errorf("unknown normalize rule %q", name)
+		}
+	}
+	return nil
+}
+
+// NormalizeContent applies this platform's Normalize rules to content.
+func (c *PlatformConfig) NormalizeContent(content string) string {
+	return NormalizeContent(content, c.Normalize)
+}
+
+// NormalizeContent applies the platform's Normalize rules to content, then
+// shortens its links when the platform has a Shortener.
+func (p *SocialPlatform) NormalizeContent(ctx context.Context, content string) string {
+	if p.Config != nil {
+		content = p.Config.NormalizeContent(content)
+	}
+	if p.Shortener != nil {
+		content = ShortenLinks(ctx, content, p.Shortener)
+	}
+	return content
+}
+
+func stripHashtags(content string) string {
+	return removeFromLines(content, hashtagPattern, "$1")
+}
+
+func stripMemoResources(content string) string {
+	return removeFromLines(content, memoResourcePattern, "")
+}
+
+// removeFromLines replaces pattern in every line of content, then tidies
+// what the removal leaves behind: doubled and edge spaces are dropped, and
+// lines left empty are removed. Indentation is kept, and untouched lines,
+// including blank ones, are left as they are.
+func removeFromLines(content string, pattern *regexp.Regexp, repl string) string {
+	lines := strings.Split(content, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		cleaned := pattern.ReplaceAllString(line, repl)
+		if cleaned == line {
+			out = append(out, line)
+			continue
+		}
+		cleaned = strings.TrimSpace(extraSpacePattern.ReplaceAllString(cleaned, "$1 "))
+		if cleaned == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		out = append(out, indent+cleaned)
+	}
+	return blankLinesPattern.ReplaceAllString(strings.Join(out, "\n"), "\n\n")
+}
+
+func convertTaskLists(content string) string {
+	return taskListPattern.ReplaceAllStringFunc(content, func(item string) string {
+		m := taskListPattern.FindStringSubmatch(item)
+		if m[2] == " " {
+			return m[1] + "- "
+		}
+		return m[1] + "- ✓ "
+	})
+}
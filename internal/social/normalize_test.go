@@ -0,0 +1,41 @@
+package social
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeContent_StripHashtags(t *testing.T) {
+	content := "Weekend hike #outdoor #life/travel done\n\n# Notes\nsee https://example.com/page#section\n\n#daily #log"
+
+	got := NormalizeContent(content, []string{NormalizeStripHashtags})
+	assert.Equal(t, "Weekend hike done\n\n# Notes\nsee https://example.com/page#section", got)
+
+	assert.Equal(t, content, NormalizeContent(content, nil), "tags are kept without the rule")
+}
+
+func TestNormalizeContent_TaskLists(t *testing.T) {
+	content := "Today:\n- [ ] buy milk\n- [x] ship release\n  * [X] nested done\nnot a [ ] task"
+
+	got := NormalizeContent(content, []string{NormalizeTaskLists})
+	assert.Equal(t, "Today:\n- buy milk\n- ✓ ship release\n  - ✓ nested done\nnot a [ ] task", got)
+}
+
+func TestNormalizeContent_StripMemoResources(t *testing.T) {
+	content := "Photos from today\n![[resources/101]]\n![cat](/file/resources/7/cat.png) so cute\n![remote](https://example.com/a.png)"
+
+	got := NormalizeContent(content, []string{NormalizeStripMemoResources})
+	assert.Equal(t, "Photos from today\nso cute\n![remote](https://example.com/a.png)", got)
+}
+
+func TestNormalizeContent_RulesApplyInOrder(t *testing.T) {
+	config := &PlatformConfig{Normalize: []string{NormalizeTaskLists, NormalizeStripHashtags}}
+	assert.Equal(t, "- ✓ release v2", config.NormalizeContent("- [x] release v2 #work"))
+}
+
+func TestValidateNormalizeRules(t *testing.T) {
+	require.NoError(t, validateNormalizeRules([]string{NormalizeStripHashtags, NormalizeTaskLists, NormalizeStripMemoResources}))
+	assert.ErrorContains(t, validateNormalizeRules([]string{"strip_emoji"}), `unknown normalize rule "strip_emoji"`)
+}
@@ -0,0 +1,323 @@
+package social
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"butterfly.orx.me/core/log"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/gorilla/websocket"
+)
+
+// nostrPublishTimeout bounds how long a single relay gets to acknowledge an
+// event before it counts as failed.
+const nostrPublishTimeout = 15 * time.Second
+
+// nostrKindTextNote is the NIP-01 event kind for a short text note.
+const nostrKindTextNote = 1
+
+// NostrEvent is a signed NIP-01 event.
+type NostrEvent struct {
+	ID        string     `json:"id"`
+	PubKey    string     `json:"pubkey"`
+	CreatedAt int64      `json:"created_at"`
+	Kind      int        `json:"kind"`
+	Tags      [][]string `json:"tags"`
+	Content   string     `json:"content"`
+	Sig       string     `json:"sig"`
+}
+
+// NostrClient implements SocialClient for publishing text notes to Nostr
+// relays. It is a sync target only; ListPosts is not supported.
+type NostrClient struct {
+	name   string
+	key    *btcec.PrivateKey
+	pubkey string
+	relays []string
+	dialer *websocket.Dialer
+	now    func() time.Time
+}
+
+// NewNostrClient creates a client that signs with privateKey (nsec bech32 or
+// 64-char hex) and publishes to every relay in relays.
+func NewNostrClient(name, privateKey string, relays []string) (*NostrClient, error) {
+	seckey, err := decodeNostrKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	var scalar btcec.ModNScalar
+	if overflow := scalar.SetByteSlice(seckey); overflow || scalar.IsZero() {
+		return nil, errors.New("nostr: private key out of range")
+	}
+	key, _ := btcec.PrivKeyFromBytes(seckey)
+	if len(relays) == 0 {
+		return nil, errors.New("nostr: at least one relay is required")
+	}
+
+	return &NostrClient{
+		name:   name,
+		key:    key,
+		pubkey: hex.EncodeToString(schnorr.SerializePubKey(key.PubKey())),
+		relays: relays,
+		dialer: &websocket.Dialer{HandshakeTimeout: nostrPublishTimeout},
+		now:    time.Now,
+	}, nil
+}
+
+func (n *NostrClient) Name() string { return n.name }
+
+// Post signs a kind-1 note and publishes it to all relays. Media with a URL
+// is appended to the content and described with NIP-92 imeta tags; data-only
+// media has nowhere to be hosted and is dropped. The post succeeds if at
+// least one relay accepts the event.
+func (n *NostrClient) Post(ctx context.Context, post *Post) (interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	if err := checkVisibility(PlatformNostr, post.Visibility); err != nil {
+		return nil, err
+	}
+	if err := ValidateMedia(PlatformNostr.String(), post.Media); err != nil {
+		return nil, err
+	}
+
+	content := post.Content
+	tags := [][]string{}
+	for i := range post.Media {
+		url := post.Media[i].GetURL()
+		if url == "" {
+			logger.Warn("dropping media without URL, nostr has no upload target",
+				"client", n.name,
+				"index", i)
+			continue
+		}
+		content = strings.TrimRight(content, "\n") + "\n" + url
+		tag := []string{"imeta", "url " + url}
+		if post.Media[i].Description != "" {
+			tag = append(tag, "alt "+post.Media[i].Description)
+		}
+		tags = append(tags, tag)
+	}
+
+	event, err := n.signEvent(nostrKindTextNote, tags, strings.TrimSpace(content))
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("publishing to nostr",
+		"client", n.name,
+		"event_id", event.ID,
+		"relays", len(n.relays))
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		accepted []string
+		errs     []error
+	)
+	for _, relay := range n.relays {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := n.publish(ctx, relay, event)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				logger.Warn("nostr relay did not accept event",
+					"client", n.name,
+					"relay", relay,
+					"error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", relay, err))
+				return
+			}
+			accepted = append(accepted, relay)
+		}()
+	}
+	wg.Wait()
+
+	if len(accepted) == 0 {
+		return nil, fmt.Errorf("nostr: no relay accepted event %s: %w", event.ID, errors.Join(errs...))
+	}
+
+	logger.Info("published to nostr",
+		"client", n.name,
+		"event_id", event.ID,
+		"accepted", len(accepted),
+		"failed", len(errs))
+
+	return map[string]string{"id": event.ID}, nil
+}
+
+func (n *NostrClient) ListPosts(_ context.Context, _ int) ([]*Post, error) {
+	return nil, errors.New("nostr: listing posts not supported")
+}
+
+// signEvent builds and signs a NIP-01 event.
+func (n *NostrClient) signEvent(kind int, tags [][]string, content string) (*NostrEvent, error) {
+	event := &NostrEvent{
+		PubKey:    n.pubkey,
+		CreatedAt: n.now().Unix(),
+		Kind:      kind,
+		Tags:      tags,
+		Content:   content,
+	}
+	id := sha256.Sum256(nostrSerialize(event))
+	event.ID = hex.EncodeToString(id[:])
+
+	// BIP-340 建议用新鲜随机数作为 aux，防御侧信道
+	var aux [32]byte
+	if _, err := rand.Read(aux[:]); err != nil {
+		return nil, fmt.Errorf("nostr: read randomness: %w", err)
+	}
+	sig, err := schnorr.Sign(n.key, id[:], schnorr.CustomNonce(aux))
+	if err != nil {
+		return nil, fmt.Errorf("nostr: sign event: %w", err)
+	}
+	event.Sig = hex.EncodeToString(sig.Serialize())
+	return event, nil
+}
+
+// publish sends event to one relay and waits for its NIP-20 OK reply.
+func (n *NostrClient) publish(ctx context.Context, relay string, event *NostrEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, nostrPublishTimeout)
+	defer cancel()
+
+	conn, _, err := n.dialer.DialContext(ctx, relay, nil)
+	if err != nil {
+		return fmt.Errorf("dial: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetWriteDeadline(deadline)
+	_ = conn.SetReadDeadline(deadline)
+
+	if err := conn.WriteJSON([]interface{}{"EVENT", event}); err != nil {
+		return fmt.Errorf("send event: %w", err)
+	}
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read reply: %w", err)
+		}
+
+		var msg []json.RawMessage
+		if err := json.Unmarshal(data, &msg); err != nil || len(msg) == 0 {
+			continue
+		}
+		var label string
+		if err := json.Unmarshal(msg[0], &label); err != nil || label != "OK" || len(msg) < 3 {
+			continue
+		}
+
+		var id string
+		var ok bool
+		var reason string
+		_ = json.Unmarshal(msg[1], &id)
+		if id != event.ID {
+			continue
+		}
+		_ = json.Unmarshal(msg[2], &ok)
+		if len(msg) > 3 {
+			_ = json.Unmarshal(msg[3], &reason)
+		}
+		if !ok {
+			return fmt.Errorf("rejected: %s", reason)
+		}
+		return nil
+	}
+}
+
+// nostrSerialize returns the NIP-01 commitment
+// [0,<pubkey>,<created_at>,<kind>,<tags>,<content>] whose SHA-256 is the
+// event id. Strings are escaped exactly as NIP-01 prescribes, which differs
+// from encoding/json (it escapes <, > and & and uses \u forms).
+func nostrSerialize(e *NostrEvent) []byte {
+	var b strings.Builder
+	b.WriteString(`[0,`)
+	nostrWriteString(&b, e.PubKey)
+	b.WriteByte(',')
+	b.WriteString(strconv.FormatInt(e.CreatedAt, 10))
+	b.WriteByte(',')
+	b.WriteString(strconv.Itoa(e.Kind))
+	b.WriteString(`,[`)
+	for i, tag := range e.Tags {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('[')
+		for j, v := range tag {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			nostrWriteString(&b, v)
+		}
+		b.WriteByte(']')
+	}
+	b.WriteString(`],`)
+	nostrWriteString(&b, e.Content)
+	b.WriteByte(']')
+	return []byte(b.String())
+}
+
+func nostrWriteString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\n':
+			b.WriteString(`\n`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		default:
+			// NIP-01 只转义上面七个字符，其他字符（包括其余控制字符）原样写入
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+}
+
+// decodeNostrKey accepts a NIP-19 nsec or a 64-char hex private key.
+func decodeNostrKey(key string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if strings.HasPrefix(key, "nsec1") {
+		hrp, words, err := bech32.Decode(key)
+		if err != nil {
+			return nil, fmt.Errorf("nostr: decode nsec: %w", err)
+		}
+		data, err := bech32.ConvertBits(words, 5, 8, false)
+		if err != nil {
+			return nil, fmt.Errorf("nostr: decode nsec: %w", err)
+		}
+		if hrp != "nsec" || len(data) != 32 {
+			return nil, errors.New("nostr: invalid nsec key")
+		}
+		return data, nil
+	}
+
+	data, err := hex.DecodeString(key)
+	if err != nil || len(data) != 32 {
+		return nil, errors.New("nostr: private key must be nsec or 64 hex characters")
+	}
+	return data, nil
+}
@@ -0,0 +1,245 @@
+package social
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testNostrKey is the secret key from BIP-340 test vector 1.
+const testNostrKey = "b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef"
+
+// fakeNostrRelay accepts websocket connections, records every EVENT it
+// receives and replies with an OK message.
+type fakeNostrRelay struct {
+	server *httptest.Server
+	accept bool
+
+	mu     sync.Mutex
+	events []NostrEvent
+}
+
+func newFakeNostrRelay(t *testing.T, accept bool) *fakeNostrRelay {
+	t.Helper()
+	r := &fakeNostrRelay{accept: accept}
+	upgrader := websocket.Upgrader{}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			var msg []json.RawMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			var label string
+			if len(msg) != 2 || json.Unmarshal(msg[0], &label) != nil || label != "EVENT" {
+				continue
+			}
+			var ev NostrEvent
+			if err := json.Unmarshal(msg[1], &ev); err != nil {
+				continue
+			}
+			r.mu.Lock()
+			r.events = append(r.events, ev)
+			r.mu.Unlock()
+
+			reason := ""
+			if !r.accept {
+				reason = "blocked: not on allowlist"
+			}
+			_ = conn.WriteJSON([]interface{}{"OK", ev.ID, r.accept, reason})
+		}
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *fakeNostrRelay) url() string {
+	return "ws" + strings.TrimPrefix(r.server.URL, "http")
+}
+
+func (r *fakeNostrRelay) received() []NostrEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]NostrEvent(nil), r.events...)
+}
+
+// verifyNostrSig checks a hex BIP-340 signature over id.
+func verifyNostrSig(t *testing.T, pubkeyHex string, id []byte, sigHex string) bool {
+	t.Helper()
+	pubBytes, err := hex.DecodeString(pubkeyHex)
+	require.NoError(t, err)
+	pub, err := schnorr.ParsePubKey(pubBytes)
+	require.NoError(t, err)
+	sigBytes, err := hex.DecodeString(sigHex)
+	require.NoError(t, err)
+	sig, err := schnorr.ParseSignature(sigBytes)
+	require.NoError(t, err)
+	return sig.Verify(id, pub)
+}
+
+func TestNewNostrClient_Key(t *testing.T) {
+	client, err := NewNostrClient("nostr", testNostrKey, []string{"wss://relay.example"})
+	require.NoError(t, err)
+	// Public key from BIP-340 test vector 1.
+	assert.Equal(t, "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659", client.pubkey)
+
+	_, err = NewNostrClient("nostr", strings.Repeat("0", 64), []string{"wss://relay.example"})
+	assert.ErrorContains(t, err, "out of range")
+	_, err = NewNostrClient("nostr", strings.Repeat("f", 64), []string{"wss://relay.example"})
+	assert.ErrorContains(t, err, "out of range", "keys >= the curve order are rejected, not reduced")
+}
+
+func TestNostr_SignEvent(t *testing.T) {
+	client, err := NewNostrClient("nostr", testNostrKey, []string{"wss://relay.example"})
+	require.NoError(t, err)
+
+	event, err := client.signEvent(nostrKindTextNote, nil, "hello")
+	require.NoError(t, err)
+
+	id := sha256.Sum256(nostrSerialize(event))
+	assert.Equal(t, hex.EncodeToString(id[:]), event.ID)
+	assert.True(t, verifyNostrSig(t, event.PubKey, id[:], event.Sig))
+
+	tampered := sha256.Sum256([]byte("other"))
+	assert.False(t, verifyNostrSig(t, event.PubKey, tampered[:], event.Sig))
+}
+
+func TestDecodeNostrKey(t *testing.T) {
+	// NIP-19 example key.
+	want := "67dea2ed018072d675f5415ecfaed7d2597555e202d85b3d65ea4e58d2d92ffa"
+
+	key, err := decodeNostrKey("nsec1vl029mgpspedva04g90vltkh6fvh240zqtv9k0t9af8935ke9laqsnlfe5")
+	require.NoError(t, err)
+	assert.Equal(t, want, hex.EncodeToString(key))
+
+	key, err = decodeNostrKey(want)
+	require.NoError(t, err)
+	assert.Equal(t, want, hex.EncodeToString(key))
+
+	_, err = decodeNostrKey("nsec1vl029mgpspedva04g90vltkh6fvh240zqtv9k0t9af8935ke9laqsnlfe6")
+	assert.Error(t, err, "bad checksum")
+	_, err = decodeNostrKey("abcd")
+	assert.Error(t, err, "short hex")
+}
+
+func TestNostrSerialize_Escaping(t *testing.T) {
+	ev := &NostrEvent{
+		PubKey:    "ab",
+		CreatedAt: 1700000000,
+		Kind:      1,
+		Tags:      [][]string{{"imeta", "url https://a.example/x.png"}},
+		Content:   "line\n\"quote\" <b> & \\ \t\r\b\f \x01\x1f é",
+	}
+	assert.Equal(t,
+		"[0,\"ab\",1700000000,1,[[\"imeta\",\"url https://a.example/x.png\"]],\"line\\n\\\"quote\\\" <b> & \\\\ \\t\\r\\b\\f \x01\x1f é\"]",
+		string(nostrSerialize(ev)),
+		"only \\n \\\" \\\\ \\r \\t \\b \\f are escaped; other control characters are written as is")
+}
+
+func TestNostr_PostPublishesSignedEvent(t *testing.T) {
+	relayA := newFakeNostrRelay(t, true)
+	relayB := newFakeNostrRelay(t, true)
+
+	client, err := NewNostrClient("nostr", testNostrKey, []string{relayA.url(), relayB.url()})
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	client.now = func() time.Time { return now }
+
+	post := &Post{
+		Content: "hello nostr",
+		Media: []Media{
+			*NewMediaFromURL("https://cdn.example/a.png"),
+			*NewMedia([]byte("raw bytes")),
+		},
+	}
+	post.Media[0].Description = "a cat"
+
+	resp, err := client.Post(context.Background(), post)
+	require.NoError(t, err)
+
+	for _, relay := range []*fakeNostrRelay{relayA, relayB} {
+		events := relay.received()
+		require.Len(t, events, 1)
+		ev := events[0]
+
+		assert.Equal(t, map[string]string{"id": ev.ID}, resp)
+		assert.Equal(t, "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659", ev.PubKey)
+		assert.Equal(t, nostrKindTextNote, ev.Kind)
+		assert.Equal(t, now.Unix(), ev.CreatedAt)
+		assert.Equal(t, "hello nostr\nhttps://cdn.example/a.png", ev.Content)
+		assert.Equal(t, [][]string{{"imeta", "url https://cdn.example/a.png", "alt a cat"}}, ev.Tags)
+
+		id := sha256.Sum256(nostrSerialize(&ev))
+		assert.Equal(t, hex.EncodeToString(id[:]), ev.ID)
+
+		assert.True(t, verifyNostrSig(t, ev.PubKey, id[:], ev.Sig), "event signature must verify")
+	}
+}
+
+func TestNostr_PostSucceedsIfAnyRelayAccepts(t *testing.T) {
+	ok := newFakeNostrRelay(t, true)
+	rejecting := newFakeNostrRelay(t, false)
+
+	client, err := NewNostrClient("nostr", testNostrKey, []string{rejecting.url(), ok.url()})
+	require.NoError(t, err)
+
+	_, err = client.Post(context.Background(), &Post{Content: "hi"})
+	require.NoError(t, err)
+}
+
+func TestNostr_PostFailsWhenAllRelaysReject(t *testing.T) {
+	rejecting := newFakeNostrRelay(t, false)
+
+	client, err := NewNostrClient("nostr", testNostrKey, []string{rejecting.url()})
+	require.NoError(t, err)
+
+	_, err = client.Post(context.Background(), &Post{Content: "hi"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not on allowlist")
+}
+
+func TestNostr_UnsupportedVisibility(t *testing.T) {
+	relay := newFakeNostrRelay(t, true)
+	client, err := NewNostrClient("nostr", testNostrKey, []string{relay.url()})
+	require.NoError(t, err)
+
+	_, err = client.Post(context.Background(), &Post{Content: "hi", Visibility: VisibilityLevelPrivate})
+	assert.Error(t, err)
+	assert.Empty(t, relay.received())
+}
+
+func TestInitSocialPlatforms_NostrConfig(t *testing.T) {
+	_, err := InitSocialPlatforms(map[string]*PlatformConfig{
+		"nostr": {Type: "nostr", Enabled: true},
+	}, nil, nil, nil, "", nil)
+	assert.ErrorContains(t, err, "missing Nostr config")
+
+	_, err = InitSocialPlatforms(map[string]*PlatformConfig{
+		"nostr": {Type: "nostr", Enabled: true, Nostr: &NostrConfig{PrivateKey: testNostrKey}},
+	}, nil, nil, nil, "", nil)
+	assert.ErrorContains(t, err, "missing Nostr credentials")
+
+	platforms, err := InitSocialPlatforms(map[string]*PlatformConfig{
+		"nostr": {Type: "nostr", Enabled: true, Nostr: &NostrConfig{PrivateKey: testNostrKey, Relays: []string{"wss://relay.example"}}},
+	}, nil, nil, nil, "", nil)
+	require.NoError(t, err)
+	require.Len(t, platforms, 1)
+	assert.IsType(t, &NostrClient{}, platforms[0].Client)
+}
@@ -0,0 +1,81 @@
+package social
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Poll limits. Mastodon accepts 2–4 options by default and polls lasting
+// from 5 minutes to about a month.
+const (
+	MinPollOptions       = 2
+	MaxPollOptions       = 4
+	MaxPollOptionLength  = 50
+	MinPollExpiry        = 5 * time.Minute
+	MaxPollExpiry        = 30 * 24 * time.Hour
+	DefaultPollExpiresIn = 24 * time.Hour
+)
+
+// pollOptionPrefix marks a poll option line in post content, e.g. "[ ] Yes".
+// Markdown task items ("- [ ] ...") are deliberately not matched.
+const pollOptionPrefix = "[ ] "
+
+// PollSpec describes a poll to attach to a post.
+type PollSpec struct {
+	Options []string
+	// ExpiresIn is how long the poll stays open; 0 means DefaultPollExpiresIn.
+	ExpiresIn time.Duration
+	// Multiple allows choosing more than one option.
+	Multiple bool
+}
+
+// Validate checks the option count and length and the expiry.
+func (p *PollSpec) Validate() error {
+	if len(p.Options) < MinPollOptions || len(p.Options) > MaxPollOptions {
+		return fmt.Errorf("poll needs %d to %d options, got %d", MinPollOptions, MaxPollOptions, len(p.Options))
+	}
+	for _, option := range p.Options {
+		if strings.TrimSpace(option) == "" {
+			return fmt.Errorf("poll options must not be empty")
+		}
+		if n := graphemeCount(option); n > MaxPollOptionLength {
+			return fmt.Errorf("poll option %q is %d characters, at most %d allowed", option, n, MaxPollOptionLength)
+		}
+	}
+	if p.ExpiresIn != 0 && (p.ExpiresIn < MinPollExpiry || p.ExpiresIn > MaxPollExpiry) {
+		return fmt.Errorf("poll expiry %s must be between %s and %s", p.ExpiresIn, MinPollExpiry, MaxPollExpiry)
+	}
+	return nil
+}
+
+// expiresIn returns ExpiresIn, or DefaultPollExpiresIn when unset.
+func (p *PollSpec) expiresIn() time.Duration {
+	if p.ExpiresIn == 0 {
+		return DefaultPollExpiresIn
+	}
+	return p.ExpiresIn
+}
+
+// ExtractPollBlock looks for a poll written at the end of content as two or
+// more "[ ] option" lines. It returns the content without those lines and
+// the poll, or content unchanged and nil when there is no poll block.
+func ExtractPollBlock(content string) (string, *PollSpec) {
+	lines := strings.Split(strings.TrimRight(content, " \t\r\n"), "\n")
+
+	start := len(lines)
+	for start > 0 && strings.HasPrefix(strings.TrimSpace(lines[start-1])+" ", pollOptionPrefix) {
+		start--
+	}
+	if len(lines)-start < MinPollOptions {
+		return content, nil
+	}
+
+	options := make([]string, 0, len(lines)-start)
+	for _, line := range lines[start:] {
+		option := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line)+" ", pollOptionPrefix))
+		options = append(options, option)
+	}
+	text := strings.TrimRight(strings.Join(lines[:start], "\n"), " \t\r\n")
+	return text, &PollSpec{Options: options}
+}
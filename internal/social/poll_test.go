@@ -0,0 +1,127 @@
+package social
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractPollBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantText string
+		wantOpts []string
+	}{
+		{
+			name:     "trailing block",
+			content:  "Lunch?\n\n[ ] Ramen\n[ ] Pizza\n[ ] Salad\n",
+			wantText: "Lunch?",
+			wantOpts: []string{"Ramen", "Pizza", "Salad"},
+		},
+		{
+			name:     "one option is not a poll",
+			content:  "Lunch?\n[ ] Ramen",
+			wantText: "Lunch?\n[ ] Ramen",
+		},
+		{
+			name:     "markdown tasks are not a poll",
+			content:  "Todo\n- [ ] milk\n- [ ] eggs",
+			wantText: "Todo\n- [ ] milk\n- [ ] eggs",
+		},
+		{
+			name:     "block not at the end",
+			content:  "[ ] a\n[ ] b\nthoughts?",
+			wantText: "[ ] a\n[ ] b\nthoughts?",
+		},
+		{
+			name:     "no text",
+			content:  "[ ] yes\n[ ] no",
+			wantText: "",
+			wantOpts: []string{"yes", "no"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, poll := ExtractPollBlock(tt.content)
+			assert.Equal(t, tt.wantText, text)
+			if tt.wantOpts == nil {
+				assert.Nil(t, poll)
+				return
+			}
+			require.NotNil(t, poll)
+			assert.Equal(t, tt.wantOpts, poll.Options)
+		})
+	}
+}
+
+func TestPollSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		poll    PollSpec
+		wantErr string
+	}{
+		{"two options", PollSpec{Options: []string{"a", "b"}}, ""},
+		{"four options", PollSpec{Options: []string{"a", "b", "c", "d"}, ExpiresIn: time.Hour}, ""},
+		{"one option", PollSpec{Options: []string{"a"}}, "2 to 4 options, got 1"},
+		{"five options", PollSpec{Options: []string{"a", "b", "c", "d", "e"}}, "2 to 4 options, got 5"},
+		{"empty option", PollSpec{Options: []string{"a", " "}}, "must not be empty"},
+		{"long option", PollSpec{Options: []string{"a", strings.Repeat("x", 51)}}, "at most 50"},
+		{"expiry too short", PollSpec{Options: []string{"a", "b"}, ExpiresIn: time.Minute}, "must be between"},
+		{"expiry too long", PollSpec{Options: []string{"a", "b"}, ExpiresIn: 60 * 24 * time.Hour}, "must be between"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.poll.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestMastodonPoll_MapsExplicitPoll(t *testing.T) {
+	status, poll, err := mastodonPoll(&Post{
+		Content: "Which one?",
+		Poll:    &PollSpec{Options: []string{"a", "b"}, ExpiresIn: 2 * time.Hour, Multiple: true},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Which one?", status)
+	require.NotNil(t, poll)
+	assert.Equal(t, []string{"a", "b"}, poll.Options)
+	assert.Equal(t, int64(7200), poll.ExpiresInSeconds)
+	assert.True(t, poll.Multiple)
+}
+
+func TestMastodonPoll_MapsContentBlock(t *testing.T) {
+	status, poll, err := mastodonPoll(&Post{Content: "Lunch?\n[ ] Ramen\n[ ] Pizza"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Lunch?", status)
+	require.NotNil(t, poll)
+	assert.Equal(t, []string{"Ramen", "Pizza"}, poll.Options)
+	assert.Equal(t, int64(DefaultPollExpiresIn.Seconds()), poll.ExpiresInSeconds)
+	assert.False(t, poll.Multiple)
+}
+
+func TestMastodonPoll_NoPoll(t *testing.T) {
+	status, poll, err := mastodonPoll(&Post{Content: "just text"})
+	require.NoError(t, err)
+	assert.Equal(t, "just text", status)
+	assert.Nil(t, poll)
+}
+
+func TestMastodonPoll_TooManyOptions(t *testing.T) {
+	_, _, err := mastodonPoll(&Post{Content: "?\n[ ] a\n[ ] b\n[ ] c\n[ ] d\n[ ] e"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 to 4 options, got 5")
+}
@@ -0,0 +1,43 @@
+package social
+
+import (
+	"fmt"
+
+	"golang.org/x/time/rate"
+)
+
+// What a cross-post does when its target's post_rate_limit has no token
+// left, set with PostRateLimitConfig.Mode.
+const (
+	// PostRateLimitWait 在本轮等待令牌后再发（默认）
+	PostRateLimitWait = "wait"
+	// PostRateLimitSkip 本轮跳过，帖子留到下一轮再发
+	PostRateLimitSkip = "skip"
+)
+
+// Validate reports whether the config describes a usable token bucket.
+func (c *PostRateLimitConfig) Validate() error {
+	if c.Every <= 0 {
+		return fmt.Errorf("every must be positive, got %s", c.Every)
+	}
+	if c.Burst < 0 {
+		return fmt.Errorf("burst must not be negative, got %d", c.Burst)
+	}
+	switch c.Mode {
+	case "", PostRateLimitWait, PostRateLimitSkip:
+		return nil
+	}
+	return fmt.Errorf("unknown mode %q", c.Mode)
+}
+
+// Skips reports whether posts without a token are left for the next sync
+// cycle instead of waiting.
+func (c *PostRateLimitConfig) Skips() bool {
+	return c.Mode == PostRateLimitSkip
+}
+
+// NewLimiter returns a limiter allowing one post every c.Every with bursts
+// of c.Burst (at least 1). The bucket starts full.
+func (c *PostRateLimitConfig) NewLimiter() *rate.Limiter {
+	return rate.NewLimiter(rate.Every(c.Every), max(c.Burst, 1))
+}
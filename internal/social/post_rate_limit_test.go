@@ -0,0 +1,23 @@
+package social
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostRateLimitConfig_Validate(t *testing.T) {
+	assert.NoError(t, (&PostRateLimitConfig{Every: time.Second}).Validate())
+	assert.NoError(t, (&PostRateLimitConfig{Every: time.Second, Burst: 3, Mode: PostRateLimitSkip}).Validate())
+	assert.ErrorContains(t, (&PostRateLimitConfig{}).Validate(), "every must be positive")
+	assert.ErrorContains(t, (&PostRateLimitConfig{Every: time.Second, Burst: -1}).Validate(), "burst")
+	assert.ErrorContains(t, (&PostRateLimitConfig{Every: time.Second, Mode: "drop"}).Validate(), `unknown mode "drop"`)
+}
+
+func TestPostRateLimitConfig_NewLimiter(t *testing.T) {
+	limiter := (&PostRateLimitConfig{Every: time.Hour}).NewLimiter()
+	assert.Equal(t, 1, limiter.Burst(), "an unset burst allows one post at a time")
+	assert.True(t, limiter.Allow())
+	assert.False(t, limiter.Allow())
+}
@@ -0,0 +1,92 @@
+package social
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitError is returned when a platform rejects a request with 429 Too
+// Many Requests. RetryAfter is how long the platform asked us to wait, or 0
+// when it did not say.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited, retry after %s: %v", e.RetryAfter, e.Err)
+	}
+	return fmt.Sprintf("rate limited: %v", e.Err)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitRetryAfter reports whether err is or wraps a RateLimitError and
+// how long the platform asked callers to wait.
+func RateLimitRetryAfter(err error) (time.Duration, bool) {
+	var rateErr *RateLimitError
+	if errors.As(err, &rateErr) {
+		return rateErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// newStatusError builds the error for a non-success response, wrapping it in
+// a RateLimitError when the status is 429.
+func newStatusError(op string, resp *http.Response, body []byte) error {
+	err := &StatusError{Op: op, StatusCode: resp.StatusCode, Body: string(body)}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return err
+	}
+	return &RateLimitError{RetryAfter: parseRetryAfter(resp.Header, time.Now()), Err: err}
+}
+
+// parseRetryAfter reads how long to wait from a 429 response: Retry-After as
+// seconds or an HTTP date, else X-RateLimit-Reset (Mastodon) as an RFC 3339
+// time or Unix seconds. It returns 0 when neither header is usable.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil && t.After(now) {
+			return t.Sub(now)
+		}
+	}
+	if v := h.Get("X-RateLimit-Reset"); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil && t.After(now) {
+			return t.Sub(now)
+		}
+		if unix, err := strconv.ParseInt(v, 10, 64); err == nil {
+			if t := time.Unix(unix, 0); t.After(now) {
+				return t.Sub(now)
+			}
+		}
+	}
+	return 0
+}
+
+// rateLimitTransport fails requests answered with 429 with a RateLimitError
+// carrying the wait from the response headers, for clients like go-mastodon
+// that would otherwise retry 429s themselves for up to an hour and drop the
+// headers from their errors.
+type rateLimitTransport struct {
+	base http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	// 返回错误而不是响应，go-mastodon 的 429 重试循环在第一次就退出
+	defer resp.Body.Close()
+	body, _ := readResponseBody(resp.Body)
+	return nil, newStatusError(req.Method+" "+req.URL.Path, resp, body)
+}
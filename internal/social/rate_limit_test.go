@@ -0,0 +1,83 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 7, 3, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+	}{
+		{"seconds", map[string]string{"Retry-After": "120"}, 2 * time.Minute},
+		{"http date", map[string]string{"Retry-After": now.Add(90 * time.Second).Format(http.TimeFormat)}, 90 * time.Second},
+		{"mastodon reset", map[string]string{"X-RateLimit-Reset": now.Add(5 * time.Minute).Format(time.RFC3339)}, 5 * time.Minute},
+		{"unix reset", map[string]string{"X-RateLimit-Reset": "1783080060"}, time.Minute},
+		{"retry-after wins", map[string]string{"Retry-After": "30", "X-RateLimit-Reset": "1783080060"}, 30 * time.Second},
+		{"past date", map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)}, 0},
+		{"garbage", map[string]string{"Retry-After": "soon"}, 0},
+		{"none", nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			assert.Equal(t, tt.want, parseRetryAfter(h, now))
+		})
+	}
+}
+
+func TestNewStatusError_RateLimited(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Retry-After", "120")
+	rec.WriteHeader(http.StatusTooManyRequests)
+
+	err := newStatusError("publish media container", rec.Result(), []byte(`{"error":"too many calls"}`))
+
+	retryAfter, limited := RateLimitRetryAfter(err)
+	assert.True(t, limited)
+	assert.Equal(t, 2*time.Minute, retryAfter)
+	assert.Equal(t, http.StatusTooManyRequests, HTTPStatusCode(err))
+}
+
+func TestNewStatusError_OtherStatus(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Retry-After", "120")
+	rec.WriteHeader(http.StatusServiceUnavailable)
+
+	err := newStatusError("publish media container", rec.Result(), nil)
+
+	_, limited := RateLimitRetryAfter(err)
+	assert.False(t, limited)
+	assert.Equal(t, http.StatusServiceUnavailable, HTTPStatusCode(err))
+}
+
+func TestMastodonClient_PostRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "90")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":"Too many requests"}`))
+	}))
+	defer server.Close()
+
+	client := NewMastodonClient(server.URL, "token", "mastodon")
+	_, err := client.Post(context.Background(), &Post{Content: "hello"})
+	require.Error(t, err)
+
+	retryAfter, limited := RateLimitRetryAfter(err)
+	assert.True(t, limited)
+	assert.Equal(t, 90*time.Second, retryAfter)
+	assert.Equal(t, http.StatusTooManyRequests, HTTPStatusCode(err))
+}
@@ -0,0 +1,60 @@
+package social
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxResponseBytes is the default cap on API response bodies.
+const DefaultMaxResponseBytes int64 = 10 << 20
+
+// MaxResponseBytes caps the API response bodies clients read into memory,
+// so a misbehaving server cannot exhaust it; it is set from
+// http.max_response_bytes at startup. Media downloads use MaxMediaBytes.
+var MaxResponseBytes = DefaultMaxResponseBytes
+
+// StrictJSON makes DecodeJSON reject fields the target struct does not
+// know; it is set from http.strict_json at startup. Useful for spotting
+// API changes, but most APIs add fields freely, so it is off by default.
+var StrictJSON bool
+
+// ErrBodyTooLarge is returned when a body exceeds its size cap.
+var ErrBodyTooLarge = errors.New("body too large")
+
+// ReadLimited reads all of r, failing with an error wrapping
+// ErrBodyTooLarge once more than limit bytes arrive.
+func ReadLimited(r io.Reader, limit int64) ([]byte, error) {
+	// Read one byte past the limit to tell "exactly at the limit" from "over".
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, limit)
+	}
+	return data, nil
+}
+
+// readResponseBody reads an API response body up to MaxResponseBytes.
+func readResponseBody(r io.Reader) ([]byte, error) {
+	return ReadLimited(r, MaxResponseBytes)
+}
+
+// DecodeJSON unmarshals data into v, rejecting unknown fields when
+// StrictJSON is set.
+func DecodeJSON(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if StrictJSON {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after JSON value")
+	}
+	return nil
+}
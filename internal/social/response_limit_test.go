@@ -0,0 +1,79 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withMaxResponseBytes(t *testing.T, n int64) {
+	t.Helper()
+	prev := MaxResponseBytes
+	MaxResponseBytes = n
+	t.Cleanup(func() { MaxResponseBytes = prev })
+}
+
+// oversizedServer answers every request with a JSON body of n bytes.
+func oversizedServer(t *testing.T, n int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"` + strings.Repeat("x", n-9) + `"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReadLimited(t *testing.T) {
+	data, err := ReadLimited(strings.NewReader("12345"), 5)
+	require.NoError(t, err)
+	assert.Equal(t, "12345", string(data), "a body exactly at the limit is allowed")
+
+	_, err = ReadLimited(strings.NewReader("123456"), 5)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+	assert.ErrorContains(t, err, "exceeds 5 bytes")
+}
+
+func TestMemos_OversizedResponse(t *testing.T) {
+	withMaxResponseBytes(t, 1024)
+	server := oversizedServer(t, 2048)
+
+	_, err := NewMemos(server.URL, "token", "memos", nil).ListMemos(context.Background(), &ListMemosRequest{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+	assert.ErrorContains(t, err, "exceeds 1024 bytes")
+}
+
+func TestThreads_OversizedResponse(t *testing.T) {
+	withMaxResponseBytes(t, 1024)
+	server := oversizedServer(t, 2048)
+	orig := threadsGraphURL
+	threadsGraphURL = server.URL
+	t.Cleanup(func() { threadsGraphURL = orig })
+
+	client := &ThreadsClient{name: "threads", UserID: 42, accessToken: "token"}
+	_, err := client.Post(context.Background(), &Post{Content: "hi"})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrBodyTooLarge)
+}
+
+func TestDecodeJSON_StrictJSON(t *testing.T) {
+	var v struct {
+		ID string `json:"id"`
+	}
+	data := []byte(`{"id":"1","extra":true}`)
+	require.NoError(t, DecodeJSON(data, &v))
+	assert.Equal(t, "1", v.ID)
+
+	prev := StrictJSON
+	StrictJSON = true
+	t.Cleanup(func() { StrictJSON = prev })
+	assert.ErrorContains(t, DecodeJSON(data, &v), `unknown field "extra"`)
+
+	assert.Error(t, DecodeJSON([]byte(`{"id":"1"} {"id":"2"}`), &v), "trailing data is rejected like json.Unmarshal does")
+}
@@ -0,0 +1,272 @@
+package social
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"butterfly.orx.me/core/log"
+)
+
+// rssMaxFeedSize caps how much of a feed document is read.
+const rssMaxFeedSize = 10 << 20
+
+// RSSClient implements SocialClient for reading entries from an RSS 2.0 or
+// Atom feed. It is a source only; Post is not supported.
+type RSSClient struct {
+	name       string
+	feedURL    string
+	httpClient *http.Client
+}
+
+// NewRSSClient creates a client that reads the feed at feedURL. httpClients
+// supplies the timeout, proxy and TLS settings; nil uses the defaults.
+func NewRSSClient(name, feedURL string, httpClients *HTTPClientFactory) *RSSClient {
+	return &RSSClient{
+		name:       name,
+		feedURL:    feedURL,
+		httpClient: httpClientsOrDefault(httpClients).Client(),
+	}
+}
+
+func (r *RSSClient) Name() string { return r.name }
+
+func (r *RSSClient) Post(_ context.Context, _ *Post) (interface{}, error) {
+	return nil, errors.New("rss: posting not supported, feeds are read-only")
+}
+
+// ListPosts fetches the feed and returns up to limit entries, newest first.
+func (r *RSSClient) ListPosts(ctx context.Context, limit int) ([]*Post, error) {
+	logger := log.FromContext(ctx)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feed request: %w", err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, rssMaxFeedSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Op: "fetch feed", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	posts, err := parseFeed(body)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range posts {
+		p.SourcePlatform = PlatformRSS.String()
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].CreatedAt.After(posts[j].CreatedAt)
+	})
+	if limit > 0 && len(posts) > limit {
+		posts = posts[:limit]
+	}
+
+	logger.Info("fetched feed",
+		"client", r.name,
+		"url", r.feedURL,
+		"count", len(posts))
+
+	return posts, nil
+}
+
+// RSS 2.0 document.
+type rssDocument struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Enclosures  []struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+}
+
+// Atom document.
+type atomDocument struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Summary   string     `xml:"summary"`
+	Content   string     `xml:"content"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Links     []atomLink `xml:"link"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// parseFeed detects the feed format from the root element and maps every
+// entry to a Post.
+func parseFeed(data []byte) ([]*Post, error) {
+	var root struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	switch root.XMLName.Local {
+	case "rss":
+		var doc rssDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse RSS feed: %w", err)
+		}
+		posts := make([]*Post, 0, len(doc.Channel.Items))
+		for _, item := range doc.Channel.Items {
+			posts = append(posts, item.toPost())
+		}
+		return posts, nil
+
+	case "feed":
+		var doc atomDocument
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse Atom feed: %w", err)
+		}
+		posts := make([]*Post, 0, len(doc.Entries))
+		for _, entry := range doc.Entries {
+			posts = append(posts, entry.toPost())
+		}
+		return posts, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported feed format %q", root.XMLName.Local)
+	}
+}
+
+func (item rssItem) toPost() *Post {
+	id := strings.TrimSpace(item.GUID)
+	link := strings.TrimSpace(item.Link)
+	if id == "" {
+		id = link
+	}
+
+	post := &Post{
+		ID:         id,
+		OriginalID: id,
+		Content:    feedContent(item.Title, item.Description, link),
+		Visibility: VisibilityLevelPublic,
+		SourceURL:  link,
+		CreatedAt:  parseFeedTime(item.PubDate),
+	}
+	for _, enc := range item.Enclosures {
+		if enc.URL != "" {
+			post.Media = append(post.Media, *NewMediaFromURL(enc.URL))
+		}
+	}
+	return post
+}
+
+func (entry atomEntry) toPost() *Post {
+	var link string
+	var media []Media
+	for _, l := range entry.Links {
+		switch l.Rel {
+		case "", "alternate":
+			if link == "" {
+				link = strings.TrimSpace(l.Href)
+			}
+		case "enclosure":
+			if l.Href != "" {
+				media = append(media, *NewMediaFromURL(l.Href))
+			}
+		}
+	}
+
+	id := strings.TrimSpace(entry.ID)
+	if id == "" {
+		id = link
+	}
+	published := entry.Published
+	if published == "" {
+		published = entry.Updated
+	}
+	summary := entry.Summary
+	if summary == "" {
+		summary = entry.Content
+	}
+
+	return &Post{
+		ID:         id,
+		OriginalID: id,
+		Content:    feedContent(entry.Title, summary, link),
+		Visibility: VisibilityLevelPublic,
+		Media:      media,
+		SourceURL:  link,
+		CreatedAt:  parseFeedTime(published),
+	}
+}
+
+// feedContent renders an entry as "title\n\nlink", falling back to the
+// summary when the entry has no title.
+func feedContent(title, summary, link string) string {
+	text := strings.TrimSpace(title)
+	if text == "" {
+		text = strings.TrimSpace(summary)
+	}
+	if link == "" {
+		return text
+	}
+	if text == "" {
+		return link
+	}
+	return text + "\n\n" + link
+}
+
+// feedTimeLayouts are the date formats seen in the wild: RFC 822 variants
+// for RSS, RFC 3339 for Atom.
+var feedTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	time.RFC3339Nano,
+}
+
+// parseFeedTime parses a feed date, returning the zero time if no known
+// layout matches.
+func parseFeedTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
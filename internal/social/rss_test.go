@@ -0,0 +1,134 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Example Blog</title>
+    <link>https://blog.example.com/</link>
+    <item>
+      <title>Older post</title>
+      <link>https://blog.example.com/older</link>
+      <guid isPermaLink="false">post-1</guid>
+      <pubDate>Mon, 02 Jan 2006 15:04:05 +0000</pubDate>
+      <description>An older entry</description>
+    </item>
+    <item>
+      <title>Newer post</title>
+      <link>https://blog.example.com/newer</link>
+      <guid>https://blog.example.com/newer</guid>
+      <pubDate>Tue, 3 Jan 2006 10:00:00 GMT</pubDate>
+      <enclosure url="https://blog.example.com/cover.jpg" type="image/jpeg" length="1234"/>
+    </item>
+    <item>
+      <link>https://blog.example.com/untitled</link>
+      <description>Just a thought</description>
+      <pubDate>Wed, 04 Jan 2006 08:00:00 +0800</pubDate>
+    </item>
+  </channel>
+</rss>`
+
+const sampleAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example Atom</title>
+  <entry>
+    <id>tag:blog.example.com,2024:1</id>
+    <title>Hello Atom</title>
+    <link rel="alternate" href="https://blog.example.com/hello"/>
+    <link rel="enclosure" type="image/png" href="https://blog.example.com/hello.png"/>
+    <published>2024-05-01T12:00:00Z</published>
+    <updated>2024-05-02T12:00:00Z</updated>
+    <summary>Summary text</summary>
+  </entry>
+  <entry>
+    <id>tag:blog.example.com,2024:2</id>
+    <link href="https://blog.example.com/notitle"/>
+    <updated>2024-05-03T08:30:00+02:00</updated>
+    <content type="html">Body text</content>
+  </entry>
+</feed>`
+
+func TestParseFeed_RSS(t *testing.T) {
+	posts, err := parseFeed([]byte(sampleRSS))
+	require.NoError(t, err)
+	require.Len(t, posts, 3)
+
+	assert.Equal(t, "post-1", posts[0].ID)
+	assert.Equal(t, "Older post\n\nhttps://blog.example.com/older", posts[0].Content)
+	assert.Equal(t, time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC), posts[0].CreatedAt.UTC())
+	assert.Empty(t, posts[0].Media)
+
+	assert.Equal(t, "https://blog.example.com/newer", posts[1].ID)
+	assert.Equal(t, time.Date(2006, 1, 3, 10, 0, 0, 0, time.UTC), posts[1].CreatedAt.UTC())
+	require.Len(t, posts[1].Media, 1)
+	assert.Equal(t, "https://blog.example.com/cover.jpg", posts[1].Media[0].GetURL())
+
+	// No guid falls back to the link, no title falls back to the description.
+	assert.Equal(t, "https://blog.example.com/untitled", posts[2].ID)
+	assert.Equal(t, "Just a thought\n\nhttps://blog.example.com/untitled", posts[2].Content)
+	assert.Equal(t, time.Date(2006, 1, 4, 0, 0, 0, 0, time.UTC), posts[2].CreatedAt.UTC())
+}
+
+func TestParseFeed_Atom(t *testing.T) {
+	posts, err := parseFeed([]byte(sampleAtom))
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+
+	assert.Equal(t, "tag:blog.example.com,2024:1", posts[0].ID)
+	assert.Equal(t, "Hello Atom\n\nhttps://blog.example.com/hello", posts[0].Content)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), posts[0].CreatedAt.UTC(), "published wins over updated")
+	require.Len(t, posts[0].Media, 1)
+	assert.Equal(t, "https://blog.example.com/hello.png", posts[0].Media[0].GetURL())
+
+	assert.Equal(t, "Body text\n\nhttps://blog.example.com/notitle", posts[1].Content)
+	assert.Equal(t, time.Date(2024, 5, 3, 6, 30, 0, 0, time.UTC), posts[1].CreatedAt.UTC())
+}
+
+func TestParseFeed_Unsupported(t *testing.T) {
+	_, err := parseFeed([]byte(`<html><body>not a feed</body></html>`))
+	assert.Error(t, err)
+}
+
+func TestRSS_ListPosts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		_, _ = w.Write([]byte(sampleRSS))
+	}))
+	defer server.Close()
+
+	client := NewRSSClient("blog", server.URL, nil)
+	posts, err := client.ListPosts(context.Background(), 2)
+	require.NoError(t, err)
+	require.Len(t, posts, 2)
+
+	// Newest first, limited.
+	assert.Equal(t, "https://blog.example.com/untitled", posts[0].ID)
+	assert.Equal(t, "https://blog.example.com/newer", posts[1].ID)
+	assert.Equal(t, PlatformRSS.String(), posts[0].SourcePlatform)
+}
+
+func TestRSS_ListPostsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewRSSClient("blog", server.URL, nil).ListPosts(context.Background(), 10)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, HTTPStatusCode(err))
+}
+
+func TestRSS_PostNotSupported(t *testing.T) {
+	_, err := NewRSSClient("blog", "https://blog.example.com/feed", nil).Post(context.Background(), &Post{Content: "hi"})
+	assert.Error(t, err)
+}
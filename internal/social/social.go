@@ -2,11 +2,12 @@ package social
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"sync"
 	"time"
 
+	"butterfly.orx.me/core/log"
 	"github.com/mattn/go-mastodon"
 
 	"go.orx.me/apps/hyper-sync/internal/media"
@@ -22,6 +23,12 @@ const (
 	PlatformThreads   Platform = "threads"
 	PlatformMemos     Platform = "memos"
 	PlatformTelegram  Platform = "telegram"
+	PlatformNostr     Platform = "nostr"
+	PlatformDiscord   Platform = "discord"
+	PlatformMatrix    Platform = "matrix"
+	PlatformWordPress Platform = "wordpress"
+	PlatformMicroblog Platform = "microblog"
+	PlatformRSS       Platform = "rss"
 )
 
 // String returns the string representation of the platform
@@ -32,7 +39,7 @@ func (p Platform) String() string {
 // IsValid checks if the platform is a valid one
 func (p Platform) IsValid() bool {
 	switch p {
-	case PlatformMastodon, PlatformBluesky, PlatformThreads, PlatformMemos, PlatformTelegram:
+	case PlatformMastodon, PlatformBluesky, PlatformThreads, PlatformMemos, PlatformTelegram, PlatformNostr, PlatformDiscord, PlatformMatrix, PlatformWordPress, PlatformMicroblog, PlatformRSS:
 		return true
 	default:
 		return false
@@ -75,6 +82,17 @@ func (v VisibilityLevel) IsValid() bool {
 	return v >= VisibilityLevelPublic && v <= VisibilityLevelDirect
 }
 
+// UnmarshalText parses a visibility name such as "unlisted", so levels can
+// be used as configuration keys (see PlatformConfig.VisibilityMap).
+func (v *VisibilityLevel) UnmarshalText(text []byte) error {
+	level, err := ParseVisibilityLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*v = level
+	return nil
+}
+
 // Legacy string constants for backward compatibility
 const (
 	VisibilityPublic   = "public"
@@ -90,38 +108,62 @@ const (
 
 // SupportedVisibilityLevels defines which visibility levels are supported by each platform (using enum)
 var SupportedVisibilityLevels = map[Platform][]VisibilityLevel{
-	PlatformMastodon: {VisibilityLevelPublic, VisibilityLevelUnlisted, VisibilityLevelPrivate, VisibilityLevelDirect},
-	PlatformBluesky:  {VisibilityLevelPublic, VisibilityLevelPrivate},
-	PlatformThreads:  {VisibilityLevelPublic, VisibilityLevelPrivate},
-	PlatformMemos:    {VisibilityLevelPublic, VisibilityLevelUnlisted, VisibilityLevelPrivate},
-	PlatformTelegram: {VisibilityLevelPublic},
+	PlatformMastodon:  {VisibilityLevelPublic, VisibilityLevelUnlisted, VisibilityLevelPrivate, VisibilityLevelDirect},
+	PlatformBluesky:   {VisibilityLevelPublic, VisibilityLevelPrivate},
+	PlatformThreads:   {VisibilityLevelPublic, VisibilityLevelPrivate},
+	PlatformMemos:     {VisibilityLevelPublic, VisibilityLevelUnlisted, VisibilityLevelPrivate},
+	PlatformTelegram:  {VisibilityLevelPublic},
+	PlatformNostr:     {VisibilityLevelPublic},
+	PlatformDiscord:   {VisibilityLevelPublic},
+	PlatformMatrix:    {VisibilityLevelPublic},
+	PlatformWordPress: {VisibilityLevelPublic, VisibilityLevelUnlisted, VisibilityLevelPrivate},
+	PlatformMicroblog: {VisibilityLevelPublic},
+	PlatformRSS:       {VisibilityLevelPublic},
 }
 
 // DefaultVisibilityLevel defines the default visibility for each platform (using enum)
 var DefaultVisibilityLevel = map[Platform]VisibilityLevel{
-	PlatformMastodon: VisibilityLevelPublic,
-	PlatformBluesky:  VisibilityLevelPublic,
-	PlatformThreads:  VisibilityLevelPublic,
-	PlatformMemos:    VisibilityLevelPublic,
-	PlatformTelegram: VisibilityLevelPublic,
+	PlatformMastodon:  VisibilityLevelPublic,
+	PlatformBluesky:   VisibilityLevelPublic,
+	PlatformThreads:   VisibilityLevelPublic,
+	PlatformMemos:     VisibilityLevelPublic,
+	PlatformTelegram:  VisibilityLevelPublic,
+	PlatformNostr:     VisibilityLevelPublic,
+	PlatformDiscord:   VisibilityLevelPublic,
+	PlatformMatrix:    VisibilityLevelPublic,
+	PlatformWordPress: VisibilityLevelPublic,
+	PlatformMicroblog: VisibilityLevelPublic,
+	PlatformRSS:       VisibilityLevelPublic,
 }
 
 // Legacy SupportedVisibilityLevelsString for backward compatibility
 var SupportedVisibilityLevelsString = map[string][]string{
-	"mastodon": {VisibilityPublic, VisibilityUnlisted, VisibilityPrivate, VisibilityDirect},
-	"bluesky":  {VisibilityPublic, VisibilityPrivate},
-	"threads":  {VisibilityPublic, VisibilityPrivate},
-	"memos":    {VisibilityPublic, VisibilityUnlisted, VisibilityPrivate},
-	"telegram": {VisibilityPublic},
+	"mastodon":  {VisibilityPublic, VisibilityUnlisted, VisibilityPrivate, VisibilityDirect},
+	"bluesky":   {VisibilityPublic, VisibilityPrivate},
+	"threads":   {VisibilityPublic, VisibilityPrivate},
+	"memos":     {VisibilityPublic, VisibilityUnlisted, VisibilityPrivate},
+	"telegram":  {VisibilityPublic},
+	"nostr":     {VisibilityPublic},
+	"discord":   {VisibilityPublic},
+	"matrix":    {VisibilityPublic},
+	"wordpress": {VisibilityPublic, VisibilityUnlisted, VisibilityPrivate},
+	"microblog": {VisibilityPublic},
+	"rss":       {VisibilityPublic},
 }
 
 // DefaultVisibility defines the default visibility for each platform (string)
 var DefaultVisibility = map[string]string{
-	"mastodon": VisibilityPublic,
-	"bluesky":  VisibilityPublic,
-	"threads":  VisibilityPublic,
-	"memos":    VisibilityPublic,
-	"telegram": VisibilityPublic,
+	"mastodon":  VisibilityPublic,
+	"bluesky":   VisibilityPublic,
+	"threads":   VisibilityPublic,
+	"memos":     VisibilityPublic,
+	"telegram":  VisibilityPublic,
+	"nostr":     VisibilityPublic,
+	"discord":   VisibilityPublic,
+	"matrix":    VisibilityPublic,
+	"wordpress": VisibilityPublic,
+	"microblog": VisibilityPublic,
+	"rss":       VisibilityPublic,
 }
 
 // ParseVisibilityLevel converts a string visibility value to VisibilityLevel enum
@@ -362,6 +404,48 @@ type SocialUpdater interface {
 	Update(ctx context.Context, platformID string, post *Post) error
 }
 
+// SourceURLResolver is an optional interface for platforms that can build
+// the canonical permalink of one of their posts, identified by OriginalID
+// (falling back to ID). It returns "" when no public URL can be built.
+type SourceURLResolver interface {
+	SourceURL(post *Post) string
+}
+
+// ListCursor marks the newest post seen by a previous ListPosts call.
+// SinceID is the platform ID of that post, SinceTime its CreatedAt; a
+// platform uses whichever it can filter on. The zero value lists from the
+// newest post as ListPosts does.
+type ListCursor struct {
+	SinceID   string
+	SinceTime time.Time
+}
+
+// IsZero reports whether the cursor is unset.
+func (c ListCursor) IsZero() bool {
+	return c.SinceID == "" && c.SinceTime.IsZero()
+}
+
+// SinceLister is an optional interface for platforms that can list only the
+// posts newer than a cursor, so repeated syncs do not refetch a full page.
+// Posts at the cursor itself may be returned again.
+type SinceLister interface {
+	ListPostsSince(ctx context.Context, limit int, since ListCursor) ([]*Post, error)
+}
+
+// PostGetter is an optional interface for platforms that can fetch a single
+// post by ID, so a webhook about one post does not require a full listing.
+type PostGetter interface {
+	GetPost(ctx context.Context, id string) (*Post, error)
+}
+
+// postOriginalID returns the platform-side ID of post.
+func postOriginalID(post *Post) string {
+	if post.OriginalID != "" {
+		return post.OriginalID
+	}
+	return post.ID
+}
+
 // PostRequeuer is an optional interface for buffer-based clients (e.g.
 // Telegram) where ListPosts is destructive. The sync service calls Requeue
 // to return posts that could not be processed yet (e.g. sync_delay) so
@@ -370,6 +454,43 @@ type PostRequeuer interface {
 	Requeue(posts []*Post)
 }
 
+// QueueClearer is an optional interface for buffer-based clients whose
+// pending, not yet synced posts can be discarded. ClearQueue returns how
+// many posts were dropped; ingestion keeps running.
+type QueueClearer interface {
+	ClearQueue() int
+}
+
+// TokenRefresher is an optional interface for clients whose credentials
+// expire or can be revoked. SchedulerService calls EnsureValidToken on every
+// token refresh tick; it should only do network work when the token is close
+// to expiry.
+type TokenRefresher interface {
+	EnsureValidToken(ctx context.Context) error
+}
+
+// TokenRefreshRetrier is an optional interface for TokenRefreshers that keep
+// using a still-valid token when a refresh fails. RefreshRetryIn reports how
+// soon SchedulerService should try again, ahead of its regular interval; ok
+// is false when no retry is pending.
+type TokenRefreshRetrier interface {
+	RefreshRetryIn() (retryIn time.Duration, ok bool)
+}
+
+// TokenRefreshThresholder is an optional interface for TokenRefreshers that
+// refresh once the token is within a configurable window of its expiry.
+// InitSocialPlatforms sets it from token_refresh_threshold.
+type TokenRefreshThresholder interface {
+	SetRefreshThreshold(threshold time.Duration)
+	RefreshThreshold() time.Duration
+}
+
+// TokenExpiryReporter is an optional interface for clients that know when
+// their current token expires. ok is false for a token that never expires.
+type TokenExpiryReporter interface {
+	TokenExpiresAt(ctx context.Context) (expiresAt time.Time, ok bool, err error)
+}
+
 // SocialDeleter is an optional interface for platforms that support deleting posts.
 type SocialDeleter interface {
 	Delete(ctx context.Context, platformID string) error
@@ -394,6 +515,20 @@ func ExtractPlatformID(result interface{}) string {
 	return ""
 }
 
+// PostSkipped reports whether the outcome of SocialClient.Post (or of
+// SocialPlatform.Transform) means the post was deliberately not published
+// rather than posted or failed: a visibility skip (see IsVisibilitySkip), or
+// a nil result without an error. reason says why.
+func PostSkipped(result interface{}, err error) (reason string, skipped bool) {
+	switch {
+	case IsVisibilitySkip(err):
+		return err.Error(), true
+	case err == nil && result == nil:
+		return "platform returned no post", true
+	}
+	return "", false
+}
+
 type Post struct {
 	ID             string
 	Content        string
@@ -401,6 +536,19 @@ type Post struct {
 	Media          []Media
 	SourcePlatform string
 	OriginalID     string
+	// LinkCard, when set, is rendered as a link preview on platforms that
+	// support one instead of guessing the link from Content.
+	LinkCard string
+	// SourceURL is the public permalink of the post on its source platform,
+	// if the source has one. Exposed to content templates.
+	SourceURL string
+	// InReplyTo is the ID of the post this one replies to. Sources set the
+	// parent's ID on the source platform; SyncService rewrites it to the
+	// parent's ID on each target (for Bluesky, its at:// URI) before posting.
+	InReplyTo string
+	// Poll, when set, is attached as a poll on platforms that support one
+	// (Mastodon); others ignore it and post the text only.
+	Poll *PollSpec
 
 	CreatedAt time.Time
 }
@@ -408,7 +556,10 @@ type Post struct {
 type Media struct {
 	data        []byte
 	url         string
+	contentType string
 	Description string
+	// load fetches data on first use, e.g. from blob storage
+	load func() ([]byte, error)
 }
 
 // NewMedia creates a new Media object from byte data
@@ -421,41 +572,56 @@ func NewMediaFromURL(url string) *Media {
 	return &Media{url: url}
 }
 
+// NewMediaFromLoader creates a Media whose data is read by load the first
+// time it is needed, so stored media is only fetched when posted.
+func NewMediaFromLoader(load func() ([]byte, error)) *Media {
+	return &Media{load: load}
+}
+
 // GetData returns the media data, fetching from URL if necessary
 func (m *Media) GetData() ([]byte, error) {
+	return m.GetDataFor("")
+}
+
+// GetDataFor is GetData for a post to platform, which labels the download
+// metrics when the data is fetched from the URL. It is safe to call from
+// several goroutines, as when one post is cross-posted to several platforms
+// at once; see fetchMediaShared.
+func (m *Media) GetDataFor(platform string) ([]byte, error) {
 	// If we already have the data, return it
-	if m.data != nil {
-		return m.data, nil
+	mediaDataMu.Lock()
+	data := m.data
+	mediaDataMu.Unlock()
+	if data != nil {
+		return data, nil
 	}
 
-	// If we have a URL, fetch the data
-	if m.url != "" {
-		// Create HTTP client with timeout
-		client := &http.Client{
-			Timeout: 30 * time.Second,
-		}
-
-		// Make the request
-		resp, err := client.Get(m.url)
+	if m.load != nil {
+		data, err := m.load()
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch media from URL %s: %w", m.url, err)
-		}
-		defer resp.Body.Close()
-
-		// Check status code
-		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("failed to fetch media from URL %s: status code %d", m.url, resp.StatusCode)
+			return nil, fmt.Errorf("failed to load media: %w", err)
 		}
+		mediaDataMu.Lock()
+		m.data = data
+		mediaDataMu.Unlock()
+		return data, nil
+	}
 
-		// Read the body
-		data, err := io.ReadAll(resp.Body)
+	// If we have a URL, fetch the data
+	if m.url != "" {
+		fetched, err := fetchMediaShared(m.url, platform)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read media data from URL %s: %w", m.url, err)
+			return nil, err
 		}
 
 		// Cache the data for future calls
-		m.data = data
-		return data, nil
+		mediaDataMu.Lock()
+		m.data = fetched.data
+		if m.contentType == "" {
+			m.contentType = fetched.contentType
+		}
+		mediaDataMu.Unlock()
+		return fetched.data, nil
 	}
 
 	// No data and no URL
@@ -467,6 +633,71 @@ func (m *Media) GetURL() string {
 	return m.url
 }
 
+// ErrInvalidMedia is returned by ValidateMedia when media breaks a
+// platform's limits.
+var ErrInvalidMedia = errors.New("invalid media")
+
+// MediaLimits describes what media a platform accepts on one post. Zero
+// values mean no limit.
+type MediaLimits struct {
+	MaxCount  int // images and videos together
+	MaxImages int
+	// Size limits are only checked for media whose data is in memory; URL
+	// media are sized by the platform when it fetches them.
+	MaxImageBytes int64
+	MaxVideoBytes int64
+}
+
+// platformMediaLimits is the per-platform media rules checked before
+// posting. Telegram splits large sets into several albums and Nostr only
+// links media, so neither limits the count.
+var platformMediaLimits = map[Platform]MediaLimits{
+	PlatformMastodon: {MaxCount: 4, MaxImageBytes: 16 << 20, MaxVideoBytes: 99 << 20},
+	PlatformBluesky:  {MaxImages: 4},
+	PlatformThreads:  {MaxCount: 20},
+	PlatformTelegram: {MaxImageBytes: 10 << 20, MaxVideoBytes: 50 << 20},
+	PlatformDiscord:  {MaxCount: 10},
+}
+
+// ValidateMedia checks media against the limits of platform (a platform
+// type such as "bluesky") and returns an error wrapping ErrInvalidMedia
+// that says what to change, e.g. "bluesky allows at most 4 images, got 6".
+// Media whose type cannot be determined is left for the client to handle.
+func ValidateMedia(platform string, media []Media) error {
+	limits, ok := platformMediaLimits[Platform(platform)]
+	if !ok || len(media) == 0 {
+		return nil
+	}
+	if limits.MaxCount > 0 && len(media) > limits.MaxCount {
+		return fmt.Errorf("%w: %s allows at most %d attachments, got %d", ErrInvalidMedia, platform, limits.MaxCount, len(media))
+	}
+	if limits.MaxImages == 0 && limits.MaxImageBytes == 0 && limits.MaxVideoBytes == 0 {
+		return nil
+	}
+
+	var images int
+	for i := range media {
+		m := &media[i]
+		switch {
+		case m.IsImage():
+			images++
+			if limits.MaxImageBytes > 0 && int64(len(m.data)) > limits.MaxImageBytes {
+				return fmt.Errorf("%w: %s allows images up to %d MB, attachment %d is %.1f MB",
+					ErrInvalidMedia, platform, limits.MaxImageBytes>>20, i+1, float64(len(m.data))/(1<<20))
+			}
+		case m.IsVideo():
+			if limits.MaxVideoBytes > 0 && int64(len(m.data)) > limits.MaxVideoBytes {
+				return fmt.Errorf("%w: %s allows videos up to %d MB, attachment %d is %.1f MB",
+					ErrInvalidMedia, platform, limits.MaxVideoBytes>>20, i+1, float64(len(m.data))/(1<<20))
+			}
+		}
+	}
+	if limits.MaxImages > 0 && images > limits.MaxImages {
+		return fmt.Errorf("%w: %s allows at most %d images, got %d", ErrInvalidMedia, platform, limits.MaxImages, images)
+	}
+	return nil
+}
+
 // ShouldSyncPost determines if a post should be synced from source to target platform
 // based on the provided configuration
 func ShouldSyncPost(sourcePlatform string, targetPlatformConfig map[string]interface{}) bool {
@@ -497,18 +728,61 @@ type SocialPlatform struct {
 	Name   string
 	Client SocialClient
 	Config *PlatformConfig
+	// Transformer rewrites posts before they are sent to Client. Nil means
+	// posts are sent unchanged.
+	Transformer ContentTransformer
+	// Shortener shortens links while content is normalized for this
+	// platform. Nil means links are kept as they are.
+	Shortener LinkShortener
 }
 
-// CrossPost posts content to multiple social platforms based on configuration
+// Transform applies the platform's content transformer to post, then its
+// visibility_map and unsupported visibility policy. A post whose visibility
+// the platform skips returns an error wrapping ErrVisibilitySkipped.
+func (p *SocialPlatform) Transform(post *Post) (*Post, error) {
+	out := post
+	if p.Transformer != nil {
+		var err error
+		if out, err = p.Transformer.Transform(post); err != nil {
+			return nil, err
+		}
+	}
+	if p.Config == nil {
+		return out, nil
+	}
+
+	level, err := p.Config.ResolveVisibility(out.Visibility)
+	if err != nil {
+		return nil, err
+	}
+	if level != out.Visibility {
+		mapped := *out
+		mapped.Visibility = level
+		out = &mapped
+	}
+	return out, nil
+}
+
+// CrossPost posts content to multiple social platforms based on configuration.
+// Platforms are posted to concurrently; the returned error joins every
+// platform failure, and results holds an entry for each attempted platform.
+// Platforms that skip the post (see PostSkipped) are marked "skipped" with a
+// "reason" and are neither successes nor failures.
 func CrossPost(ctx context.Context, post *Post, platforms []*SocialPlatform) (map[string]interface{}, error) {
 	results := make(map[string]interface{})
-	var firstError error
+	// One slot per platform keeps the joined error in configuration order.
+	errs := make([]error, len(platforms))
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
 
 	// Remember the source platform
 	sourcePlatform := post.SourcePlatform
 
 	// Post to each enabled platform that should receive this content
-	for _, platform := range platforms {
+	for i, platform := range platforms {
 		// Skip the source platform (don't repost to where it came from)
 		if platform.Name == sourcePlatform {
 			continue
@@ -519,34 +793,52 @@ func CrossPost(ctx context.Context, post *Post, platforms []*SocialPlatform) (ma
 			continue
 		}
 
-		// Post to this platform
-		resp, err := platform.Client.Post(ctx, post)
-
-		// Store the result
-		if err != nil {
-			results[platform.Name] = map[string]interface{}{
-				"success": false,
-				"error":   err.Error(),
-			}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
 
-			// Remember the first error
-			if firstError == nil {
-				firstError = fmt.Errorf("failed to post to %s: %w", platform.Name, err)
+			// Rewrite the content for this platform, then post to it
+			var resp interface{}
+			out, err := platform.Transform(post)
+			if err == nil {
+				resp, err = platform.Client.Post(ctx, out)
 			}
-		} else {
-			results[platform.Name] = map[string]interface{}{
-				"success":  true,
-				"response": resp,
+			reason, skipped := PostSkipped(resp, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			// Store the result
+			if skipped {
+				results[platform.Name] = map[string]interface{}{
+					"success": false,
+					"skipped": true,
+					"reason":  reason,
+				}
+			} else if err != nil {
+				results[platform.Name] = map[string]interface{}{
+					"success": false,
+					"error":   err.Error(),
+				}
+				errs[i] = fmt.Errorf("failed to post to %s: %w", platform.Name, err)
+			} else {
+				results[platform.Name] = map[string]interface{}{
+					"success":  true,
+					"response": resp,
+				}
 			}
-		}
+		}()
 	}
+	wg.Wait()
 
-	return results, firstError
+	return results, errors.Join(errs...)
 }
 
 // InitSocialPlatforms initializes social clients from configuration
-func InitSocialPlatforms(configs map[string]*PlatformConfig, tokenManager TokenManager, cursorDao SyncCursorDao, objectStorage media.ObjectStorage, cdnDomain string) ([]*SocialPlatform, error) {
+func InitSocialPlatforms(configs map[string]*PlatformConfig, tokenManager TokenManager, cursorDao SyncCursorDao, objectStorage media.ObjectStorage, cdnDomain string, httpClients *HTTPClientFactory) ([]*SocialPlatform, error) {
 	var platforms []*SocialPlatform
+	// Platforms using the same shortener share it, and so its cache
+	shorteners := make(map[LinkShortenerConfig]LinkShortener)
 
 	for name, config := range configs {
 		// Skip disabled platforms
@@ -562,6 +854,54 @@ func InitSocialPlatforms(configs map[string]*PlatformConfig, tokenManager TokenM
 		var client SocialClient
 		var err error
 
+		// 先校验通用配置再创建客户端：客户端可能已经启动轮询或注册 webhook，
+		// 之后的校验失败会把它们留在后台
+		if !isValidOverflow(config.Overflow) {
+			return nil, fmt.Errorf("invalid overflow %q for %s", config.Overflow, name)
+		}
+		if config.MaxChars < 0 {
+			return nil, fmt.Errorf("invalid max_chars %d for %s", config.MaxChars, name)
+		}
+		if err := validateVisibilityMap(config.Type, config.VisibilityMap); err != nil {
+			return nil, fmt.Errorf("invalid visibility_map for %s: %w", name, err)
+		}
+		if !isValidVisibilityPolicy(config.UnsupportedVisibilityPolicy) {
+			return nil, fmt.Errorf("invalid unsupported_visibility %q for %s", config.UnsupportedVisibilityPolicy, name)
+		}
+		if err := validateNormalizeRules(config.Normalize); err != nil {
+			return nil, fmt.Errorf("invalid normalize for %s: %w", name, err)
+		}
+		if config.PostRateLimit != nil {
+			if err := config.PostRateLimit.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid post_rate_limit for %s: %w", name, err)
+			}
+		}
+		var shortener LinkShortener
+		if config.LinkShortener != nil {
+			if shortener = shorteners[*config.LinkShortener]; shortener == nil {
+				if shortener, err = NewLinkShortener(config.LinkShortener, httpClients); err != nil {
+					return nil, fmt.Errorf("invalid link_shortener for %s: %w", name, err)
+				}
+				shorteners[*config.LinkShortener] = shortener
+			}
+		}
+		if config.TokenRefreshThreshold < 0 {
+			return nil, fmt.Errorf("invalid token_refresh_threshold %s for %s", config.TokenRefreshThreshold, name)
+		}
+
+		transformer, err := NewContentTransformer(name, config.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid content template for %s: %w", name, err)
+		}
+		if config.Footer != "" {
+			footer, err := NewFooterTransformer(name, config.Type, config.Footer, transformer)
+			if err != nil {
+				return nil, fmt.Errorf("invalid footer template for %s: %w", name, err)
+			}
+			footer.SetMaxChars(config.MaxChars)
+			transformer = footer
+		}
+
 		// Initialize the appropriate client based on type
 		switch config.Type {
 		case PlatformMemos.String():
@@ -571,7 +911,7 @@ func InitSocialPlatforms(configs map[string]*PlatformConfig, tokenManager TokenM
 			if config.Memos.Endpoint == "" || config.Memos.Token == "" {
 				return nil, fmt.Errorf("missing Memos credentials for %s", name)
 			}
-			client = NewMemos(config.Memos.Endpoint, config.Memos.Token, config.Name)
+			client = NewMemos(config.Memos.Endpoint, config.Memos.Token, config.Name, httpClients)
 
 		case PlatformMastodon.String():
 			if config.Mastodon == nil {
@@ -582,7 +922,10 @@ func InitSocialPlatforms(configs map[string]*PlatformConfig, tokenManager TokenM
 				return nil, fmt.Errorf("missing Mastodon credentials for %s", name)
 			}
 
-			client = NewMastodonClient(config.Mastodon.Instance, config.Mastodon.Token, config.Name)
+			mastodonClient := NewMastodonClient(config.Mastodon.Instance, config.Mastodon.Token, config.Name)
+			mastodonClient.SetIncludeReblogs(config.Mastodon.IncludeReblogs)
+			mastodonClient.SetIncludeReplies(config.Mastodon.IncludeReplies)
+			client = mastodonClient
 
 		case PlatformBluesky.String():
 			if config.Bluesky == nil {
@@ -592,46 +935,141 @@ func InitSocialPlatforms(configs map[string]*PlatformConfig, tokenManager TokenM
 			if config.Bluesky.Host == "" || config.Bluesky.Handle == "" || config.Bluesky.Password == "" {
 				return nil, fmt.Errorf("missing Bluesky credentials for %s", name)
 			}
+			if !isValidBlueskyGIFMode(config.Bluesky.GIFMode) {
+				return nil, fmt.Errorf("invalid Bluesky gif_mode %q for %s", config.Bluesky.GIFMode, name)
+			}
+			if !isValidBlueskyVideoMode(config.Bluesky.VideoMode) {
+				return nil, fmt.Errorf("invalid Bluesky video_mode %q for %s", config.Bluesky.VideoMode, name)
+			}
 
-			client, err = NewBlueskyClient(config.Bluesky.Host, config.Bluesky.Handle, config.Bluesky.Password, config.Name)
+			bsky, err := NewBlueskyClient(config.Bluesky.Host, config.Bluesky.Handle, config.Bluesky.Password, config.Name, httpClients)
 			if err != nil {
 				return nil, fmt.Errorf("failed to initialize Bluesky client for %s: %w", name, err)
 			}
+			bsky.SetLinkCardsEnabled(!config.Bluesky.DisableLinkCard)
+			bsky.SetMediaModes(config.Bluesky.GIFMode, config.Bluesky.VideoMode)
+			bsky.SetStripMetadata(config.Bluesky.StripMetadata == nil || *config.Bluesky.StripMetadata)
+			client = bsky
 
 		case PlatformThreads.String():
 			if config.Threads == nil {
 				return nil, fmt.Errorf("missing Threads config for %s", name)
 			}
 			client, err = NewThreadsClientWithDao(config.Name, config.Threads.ClientID, config.Threads.ClientSecret, config.Threads.AccessToken,
-				config.Threads.UserID, tokenManager)
+				config.Threads.UserID, tokenManager, httpClients)
 			if err != nil {
 				return nil, fmt.Errorf("failed to initialize Threads client for %s: %w", name, err)
 			}
 
-	case PlatformTelegram.String():
-		if config.Telegram == nil {
-			return nil, fmt.Errorf("missing Telegram config for %s", name)
-		}
-		if config.Telegram.BotToken == "" || config.Telegram.ChannelID == "" {
-			return nil, fmt.Errorf("missing Telegram credentials for %s", name)
-		}
-		if config.SyncDelay == 0 {
-			config.SyncDelay = 3 * time.Minute
-		}
-		client, err = NewTelegramClient(config.Telegram.BotToken, config.Telegram.ChannelID, config.Name, "", cursorDao, objectStorage, cdnDomain)
+		case PlatformTelegram.String():
+			if config.Telegram == nil {
+				return nil, fmt.Errorf("missing Telegram config for %s", name)
+			}
+			if config.Telegram.BotToken == "" || config.Telegram.ChannelID == "" {
+				return nil, fmt.Errorf("missing Telegram credentials for %s", name)
+			}
+			if config.SyncDelay == 0 {
+				config.SyncDelay = 3 * time.Minute
+			}
+			if !isValidTelegramParseMode(config.Telegram.ParseMode) {
+				return nil, fmt.Errorf("invalid Telegram parse_mode %q for %s", config.Telegram.ParseMode, name)
+			}
+			var tg *TelegramClient
+			if config.Telegram.WebhookURL != "" {
+				if config.Telegram.WebhookSecret == "" {
+					return nil, fmt.Errorf("missing Telegram webhook_secret for %s", name)
+				}
+				tg, err = NewTelegramWebhookClient(config.Telegram.BotToken, config.Telegram.ChannelID, config.Name, "", config.Telegram.WebhookSecret, cursorDao, objectStorage, cdnDomain, httpClients)
+			} else {
+				tg, err = NewTelegramClient(config.Telegram.BotToken, config.Telegram.ChannelID, config.Name, "", cursorDao, objectStorage, cdnDomain, httpClients)
+			}
 			if err != nil {
 				return nil, fmt.Errorf("failed to initialize Telegram client for %s: %w", name, err)
 			}
+			if config.Telegram.WebhookURL != "" {
+				// 注册失败不阻止启动：之前注册的 webhook 仍然有效
+				ctx, cancel := context.WithTimeout(context.Background(), telegramSetWebhookTimeout)
+				if err := tg.SetWebhook(ctx, config.Telegram.WebhookURL); err != nil {
+					log.FromContext(ctx).Error("failed to set Telegram webhook", "client", name, "error", err)
+				}
+				cancel()
+			}
+			tg.SetParseMode(config.Telegram.ParseMode)
+			tg.SetAltTextInCaption(config.Telegram.AltTextInCaption)
+			client = tg
+
+		case PlatformNostr.String():
+			if config.Nostr == nil {
+				return nil, fmt.Errorf("missing Nostr config for %s", name)
+			}
+			if config.Nostr.PrivateKey == "" || len(config.Nostr.Relays) == 0 {
+				return nil, fmt.Errorf("missing Nostr credentials for %s", name)
+			}
+			client, err = NewNostrClient(config.Name, config.Nostr.PrivateKey, config.Nostr.Relays)
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize Nostr client for %s: %w", name, err)
+			}
+
+		case PlatformDiscord.String():
+			if config.Discord == nil {
+				return nil, fmt.Errorf("missing Discord config for %s", name)
+			}
+			if config.Discord.WebhookURL == "" {
+				return nil, fmt.Errorf("missing Discord webhook_url for %s", name)
+			}
+			client = NewDiscordClient(config.Name, config.Discord.WebhookURL, config.Discord.Username, config.Discord.AvatarURL, httpClients)
+
+		case PlatformMatrix.String():
+			if config.Matrix == nil {
+				return nil, fmt.Errorf("missing Matrix config for %s", name)
+			}
+			if config.Matrix.Homeserver == "" || config.Matrix.AccessToken == "" || config.Matrix.RoomID == "" {
+				return nil, fmt.Errorf("missing Matrix homeserver, access_token or room_id for %s", name)
+			}
+			client = NewMatrixClient(config.Name, config.Matrix.Homeserver, config.Matrix.AccessToken, config.Matrix.RoomID, httpClients)
+
+		case PlatformWordPress.String():
+			if config.WordPress == nil {
+				return nil, fmt.Errorf("missing WordPress config for %s", name)
+			}
+			if config.WordPress.SiteURL == "" || config.WordPress.Username == "" || config.WordPress.AppPassword == "" {
+				return nil, fmt.Errorf("missing WordPress site_url, username or app_password for %s", name)
+			}
+			client = NewWordPressClient(config.Name, config.WordPress.SiteURL, config.WordPress.Username, config.WordPress.AppPassword, httpClients)
+
+		case PlatformMicroblog.String():
+			if config.Microblog == nil {
+				return nil, fmt.Errorf("missing Micro.blog config for %s", name)
+			}
+			if config.Microblog.Endpoint == "" || config.Microblog.Token == "" {
+				return nil, fmt.Errorf("missing Micro.blog endpoint or token for %s", name)
+			}
+			client = NewMicroblogClient(config.Name, config.Microblog.Endpoint, config.Microblog.Token, httpClients)
+
+		case PlatformRSS.String():
+			if config.RSS == nil || config.RSS.FeedURL == "" {
+				return nil, fmt.Errorf("missing RSS feed_url for %s", name)
+			}
+			client = NewRSSClient(config.Name, config.RSS.FeedURL, httpClients)
 
 		default:
 			return nil, fmt.Errorf("unsupported platform type %s for %s", config.Type, name)
 		}
 
+		if limiter, ok := client.(LengthLimiter); ok {
+			limiter.SetLengthPolicy(config.MaxChars, config.Overflow)
+		}
+		if thresholder, ok := client.(TokenRefreshThresholder); ok {
+			thresholder.SetRefreshThreshold(config.TokenRefreshThreshold)
+		}
+
 		// Add the platform to the list
 		platforms = append(platforms, &SocialPlatform{
-			Name:   name,
-			Client: client,
-			Config: config,
+			Name:        name,
+			Client:      client,
+			Config:      config,
+			Transformer: transformer,
+			Shortener:   shortener,
 		})
 	}
 
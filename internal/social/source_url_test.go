@@ -0,0 +1,68 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/davhofer/botsky/pkg/botsky"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemos_SourceURL(t *testing.T) {
+	m := NewMemos("https://memos.example.com/", "token", "memos", nil)
+
+	assert.Equal(t, "https://memos.example.com/m/abc", m.SourceURL(&Post{ID: "memos/1", OriginalID: "abc"}))
+	assert.Equal(t, "https://memos.example.com/m/1", m.SourceURL(&Post{ID: "memos/1"}), "falls back to the memo name")
+	assert.Empty(t, m.SourceURL(&Post{}))
+}
+
+func TestMastodon_SourceURL(t *testing.T) {
+	c := &MastodonClient{name: "mastodon"}
+	assert.Empty(t, c.SourceURL(&Post{ID: "109"}), "the account URL is unknown before ListPosts")
+
+	c.accountURL = "https://mastodon.example.com/@me"
+	assert.Equal(t, "https://mastodon.example.com/@me/109", c.SourceURL(&Post{ID: "109"}))
+	assert.Equal(t, "https://mastodon.example.com/@me/110", c.SourceURL(&Post{ID: "db-id", OriginalID: "110"}))
+}
+
+func TestBluesky_SourceURL(t *testing.T) {
+	c := &BlueskyClient{name: "bluesky", client: &botsky.Client{Did: "did:plc:me"}}
+
+	assert.Equal(t, "https://bsky.app/profile/did:plc:me/post/3kabc", c.SourceURL(&Post{ID: "3kabc"}))
+	assert.Equal(t, "https://bsky.app/profile/did:plc:other/post/3kxyz",
+		c.SourceURL(&Post{OriginalID: "at://did:plc:other/app.bsky.feed.post/3kxyz"}))
+	assert.Empty(t, blueskyWebURL("at://did:plc:me/app.bsky.feed.like/3kabc"))
+
+	noSession := &BlueskyClient{name: "bluesky"}
+	assert.Empty(t, noSession.SourceURL(&Post{ID: "3kabc"}), "an rkey needs the session DID")
+}
+
+func TestTelegram_SourceURL(t *testing.T) {
+	public := &TelegramClient{name: "telegram", chatID: "@mychannel"}
+	assert.Equal(t, "https://t.me/mychannel/42", public.SourceURL(&Post{ID: "42"}))
+
+	private := &TelegramClient{name: "telegram", chatID: "-1001234567890"}
+	assert.Empty(t, private.SourceURL(&Post{ID: "42"}), "numeric channel IDs have no public permalink")
+}
+
+func TestThreads_Permalink(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/17890", r.URL.Path)
+		assert.Equal(t, "permalink", r.URL.Query().Get("fields"))
+		assert.Equal(t, "token", r.URL.Query().Get("access_token"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"17890","permalink":"https://www.threads.net/@me/post/C3abc"}`))
+	}))
+	defer server.Close()
+	orig := threadsGraphURL
+	threadsGraphURL = server.URL
+	defer func() { threadsGraphURL = orig }()
+
+	client := &ThreadsClient{name: "threads", accessToken: "token"}
+	link, err := client.Permalink(context.Background(), "17890")
+	require.NoError(t, err)
+	assert.Equal(t, "https://www.threads.net/@me/post/C3abc", link)
+}
@@ -3,8 +3,10 @@ package social
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"html"
 	"log/slog"
 	"net/http"
 	"path/filepath"
@@ -22,6 +24,7 @@ import (
 
 	"go.orx.me/apps/hyper-sync/internal/media"
 	"go.orx.me/apps/hyper-sync/internal/metrics"
+	"go.orx.me/apps/hyper-sync/internal/telemetry"
 )
 
 // SyncCursorDao persists polling offsets for pull-based content sources.
@@ -43,19 +46,65 @@ type pendingMediaGroup struct {
 	timer *time.Timer
 }
 
-// TelegramClient implements SocialClient for Telegram channel ingestion.
+// Telegram parse modes accepted by TelegramConfig.ParseMode. An empty mode
+// sends plain text.
+const (
+	telegramParseModeMarkdownV2 = "MarkdownV2"
+	telegramParseModeHTML       = "HTML"
+)
+
+// telegramMarkdownV2Reserved lists the characters MarkdownV2 requires to be
+// escaped anywhere outside of an entity.
+const telegramMarkdownV2Reserved = "_*[]()~`>#+-=|{}.!\\"
+
+// telegramMediaGroupLimit is the maximum number of items Telegram accepts in
+// a single sendMediaGroup album.
+const telegramMediaGroupLimit = 10
+
+// telegramAllowedUpdates are the update types requested from Telegram, both
+// by the getUpdates loop and by setWebhook.
+var telegramAllowedUpdates = []string{"channel_post", "edited_channel_post", "message", "edited_message"}
+
+// TelegramWebhookSecretHeader carries the secret_token given to setWebhook on
+// every update Telegram delivers to the webhook.
+const TelegramWebhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// telegramSetWebhookTimeout bounds registering the webhook at startup, so
+// an unresponsive Telegram API cannot hold up the service.
+const telegramSetWebhookTimeout = 30 * time.Second
+
+// telegramPollTimeout matches go-telegram/bot's default: getUpdates long
+// polls for a little under it, and the HTTP client times out at it.
+const telegramPollTimeout = time.Minute
+
+// TelegramClient implements SocialClient for Telegram channel ingestion and
+// publishing.
 //
 // It runs a go-telegram/bot long-polling loop in the background for the
 // lifetime of the client; ListPosts just drains the posts that loop has
 // buffered so far. The polling offset is persisted via cursor after every
-// processed update so a restart resumes where it left off.
+// processed update so a restart resumes where it left off. A client created
+// with NewTelegramWebhookClient does not poll; updates delivered to the
+// webhook are passed to HandleWebhookUpdate instead.
 type TelegramClient struct {
 	bot           *tgbot.Bot
 	name          string
+	chatID        string
+	parseMode     string
 	cursor        SyncCursorDao
 	objectStorage media.ObjectStorage
 	cdnDomain     string
 	metrics       *metrics.TelegramMetrics
+	httpClient    *http.Client
+
+	// altTextInCaption 把图片/视频的 alt text 追加到 caption（Telegram 没有真正的 alt text）
+	altTextInCaption bool
+
+	// webhookSecret 作为 setWebhook 的 secret_token，Telegram 每次推送时带在请求头里
+	webhookSecret string
+
+	// lengthPolicy 决定超出字数上限的正文截断还是拆成串，见 SetLengthPolicy
+	lengthPolicy
 
 	cancel context.CancelFunc
 
@@ -64,17 +113,43 @@ type TelegramClient struct {
 	pendingGroups map[string]*pendingMediaGroup
 }
 
-func NewTelegramClient(botToken, channelID, name, apiBase string, cursor SyncCursorDao, objectStorage media.ObjectStorage, cdnDomain string) (*TelegramClient, error) {
+// NewTelegramClient creates a TelegramClient that long-polls for updates.
+// httpClients supplies the proxy and TLS settings for Bot API calls; nil
+// uses the defaults.
+func NewTelegramClient(botToken, channelID, name, apiBase string, cursor SyncCursorDao, objectStorage media.ObjectStorage, cdnDomain string, httpClients *HTTPClientFactory) (*TelegramClient, error) {
+	return newTelegramClient(botToken, channelID, name, apiBase, cursor, objectStorage, cdnDomain, httpClients, true)
+}
+
+// NewTelegramWebhookClient creates a TelegramClient that receives updates
+// through a webhook instead of long polling. Register the webhook with
+// SetWebhook; webhookSecret is sent to Telegram as its secret_token.
+func NewTelegramWebhookClient(botToken, channelID, name, apiBase, webhookSecret string, cursor SyncCursorDao, objectStorage media.ObjectStorage, cdnDomain string, httpClients *HTTPClientFactory) (*TelegramClient, error) {
+	t, err := newTelegramClient(botToken, channelID, name, apiBase, cursor, objectStorage, cdnDomain, httpClients, false)
+	if err != nil {
+		return nil, err
+	}
+	t.webhookSecret = webhookSecret
+	return t, nil
+}
+
+func newTelegramClient(botToken, channelID, name, apiBase string, cursor SyncCursorDao, objectStorage media.ObjectStorage, cdnDomain string, httpClients *HTTPClientFactory, poll bool) (*TelegramClient, error) {
 	logger := slog.Default()
 
 	t := &TelegramClient{
 		name:          name,
+		chatID:        channelID,
 		cursor:        cursor,
 		objectStorage: objectStorage,
 		cdnDomain:     cdnDomain,
 		metrics:       metrics.NewTelegramMetrics(name),
 		pendingGroups: make(map[string]*pendingMediaGroup),
 	}
+	// getUpdates 长轮询最长持续 telegramPollTimeout，客户端超时不能比它短
+	httpClients = httpClientsOrDefault(httpClients)
+	t.httpClient = &http.Client{
+		Timeout:   max(httpClients.Timeout(), telegramPollTimeout),
+		Transport: newTelegramTransport(botToken, httpClients.Transport()),
+	}
 
 	var offset int64
 	if cursor != nil {
@@ -92,7 +167,8 @@ func NewTelegramClient(botToken, channelID, name, apiBase string, cursor SyncCur
 		tgbot.WithSkipGetMe(),
 		tgbot.WithNotAsyncHandlers(),
 		tgbot.WithDefaultHandler(t.handleUpdate),
-		tgbot.WithAllowedUpdates(tgbot.AllowedUpdates{"channel_post"}),
+		tgbot.WithAllowedUpdates(tgbot.AllowedUpdates(telegramAllowedUpdates)),
+		tgbot.WithHTTPClient(telegramPollTimeout, t.httpClient),
 	}
 	if offset > 0 {
 		// The bot library's getUpdates loop always requests lastUpdateID+1,
@@ -111,16 +187,76 @@ func NewTelegramClient(botToken, channelID, name, apiBase string, cursor SyncCur
 
 	ctx, cancel := context.WithCancel(context.Background())
 	t.cancel = cancel
-	go b.Start(ctx)
+	if poll {
+		go b.Start(ctx)
+	}
 
 	logger.Info("telegram client started",
 		"client", name,
 		"api_base", apiBase,
+		"polling", poll,
 		"has_object_storage", objectStorage != nil)
 
 	return t, nil
 }
 
+// SetWebhook registers url with Telegram as the bot's webhook, with the
+// client's webhook secret as secret_token. Telegram stops answering
+// getUpdates while a webhook is set.
+func (t *TelegramClient) SetWebhook(ctx context.Context, url string) error {
+	if _, err := t.bot.SetWebhook(ctx, &tgbot.SetWebhookParams{
+		URL:            url,
+		AllowedUpdates: telegramAllowedUpdates,
+		SecretToken:    t.webhookSecret,
+	}); err != nil {
+		return t.apiError("set webhook", err)
+	}
+	log.FromContext(ctx).Info("telegram webhook set",
+		"client", t.name,
+		"url", url)
+	return nil
+}
+
+// DeleteWebhook removes the bot's webhook, e.g. before switching back to
+// long polling. Updates not yet delivered are kept for getUpdates.
+func (t *TelegramClient) DeleteWebhook(ctx context.Context) error {
+	if _, err := t.bot.DeleteWebhook(ctx, &tgbot.DeleteWebhookParams{}); err != nil {
+		return t.apiError("delete webhook", err)
+	}
+	log.FromContext(ctx).Info("telegram webhook deleted", "client", t.name)
+	return nil
+}
+
+// WebhookSecret returns the secret_token Telegram sends with webhook
+// updates; it is empty for a polling client.
+func (t *TelegramClient) WebhookSecret() string { return t.webhookSecret }
+
+// HandleWebhookUpdate decodes an Update delivered to the webhook and
+// processes it like one received by polling, buffering the post it carries
+// for the next ListPosts.
+func (t *TelegramClient) HandleWebhookUpdate(ctx context.Context, body []byte) error {
+	var update models.Update
+	if err := json.Unmarshal(body, &update); err != nil {
+		return fmt.Errorf("telegram: decode update: %w", err)
+	}
+	t.handleUpdate(ctx, t.bot, &update)
+	return nil
+}
+
+// newTelegramTransport traces Bot API calls. The bot token is part of every
+// request path, so it is masked in the recorded path; the getUpdates long
+// poll is not traced since it would add a span every poll.
+func newTelegramTransport(botToken string, base http.RoundTripper) *telemetry.HTTPTransport {
+	transport := telemetry.NewHTTPTransport(base, PlatformTelegram.String())
+	transport.Path = func(req *http.Request) string {
+		return strings.ReplaceAll(req.URL.Path, botToken, "<token>")
+	}
+	transport.Skip = func(req *http.Request) bool {
+		return strings.HasSuffix(req.URL.Path, "/getUpdates")
+	}
+	return transport
+}
+
 // Requeue prepends posts back to the front of the buffer so they will be
 // returned by the next ListPosts call. This is used by the sync service to
 // return posts that were too recent to sync (sync_delay).
@@ -134,6 +270,18 @@ func (t *TelegramClient) Requeue(posts []*Post) {
 	t.metrics.SetBufferSize(len(t.buffer))
 }
 
+// ClearQueue discards every buffered post that has not been handed to the
+// sync service yet. Media groups still being assembled are kept, and the
+// polling loop keeps running.
+func (t *TelegramClient) ClearQueue() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	n := len(t.buffer)
+	t.buffer = nil
+	t.metrics.SetBufferSize(0)
+	return n
+}
+
 // Close stops the background polling loop.
 func (t *TelegramClient) Close() {
 	if t.cancel != nil {
@@ -143,8 +291,347 @@ func (t *TelegramClient) Close() {
 
 func (t *TelegramClient) Name() string { return t.name }
 
-func (t *TelegramClient) Post(_ context.Context, _ *Post) (interface{}, error) {
-	return nil, fmt.Errorf("telegram: posting not implemented")
+// SourceURL returns the t.me permalink of a message, which only public
+// channels configured by "@username" have.
+func (t *TelegramClient) SourceURL(post *Post) string {
+	username, ok := strings.CutPrefix(t.chatID, "@")
+	id := postOriginalID(post)
+	if !ok || username == "" || id == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://t.me/%s/%s", username, id)
+}
+
+// SetParseMode sets the parse_mode sent with outgoing messages and captions.
+// Markdown in the content is converted to the mode's entities and the rest
+// is escaped, so plain text from other platforms is delivered verbatim
+// instead of being mangled or rejected by Telegram.
+func (t *TelegramClient) SetParseMode(mode string) {
+	t.parseMode = mode
+}
+
+// SetAltTextInCaption appends each attachment's alt text to its caption.
+// Telegram has no alt text field, so this is the only way to carry it.
+func (t *TelegramClient) SetAltTextInCaption(enabled bool) {
+	t.altTextInCaption = enabled
+}
+
+// caption returns the formatted caption for an attachment: content (which
+// may be empty) followed by the attachment's alt text when enabled.
+func (t *TelegramClient) caption(content string, media *Media) string {
+	caption := t.formatText(content)
+	if t.altTextInCaption && media.Description != "" {
+		// alt text 是纯文本，不解析其中的 Markdown
+		alt := t.escapeText("Alt: " + media.Description)
+		if caption == "" {
+			caption = alt
+		} else {
+			caption += "\n\n" + alt
+		}
+	}
+	return caption
+}
+
+// formatText converts the Markdown in text to the configured parse mode's
+// entities, escaping the literal text around it.
+func (t *TelegramClient) formatText(text string) string {
+	switch t.parseMode {
+	case telegramParseModeMarkdownV2:
+		return telegramMarkdownV2Markup.render(text)
+	case telegramParseModeHTML:
+		return telegramHTMLMarkup.render(text)
+	default:
+		return text
+	}
+}
+
+// escapeText escapes text for the configured parse mode without
+// interpreting any Markdown in it.
+func (t *TelegramClient) escapeText(text string) string {
+	switch t.parseMode {
+	case telegramParseModeMarkdownV2:
+		return escapeMarkdownV2(text)
+	case telegramParseModeHTML:
+		return html.EscapeString(text)
+	default:
+		return text
+	}
+}
+
+func isValidTelegramParseMode(mode string) bool {
+	switch mode {
+	case "", telegramParseModeMarkdownV2, telegramParseModeHTML:
+		return true
+	}
+	return false
+}
+
+// escapeMarkdownV2 backslash-escapes every MarkdownV2 reserved character.
+func escapeMarkdownV2(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if strings.ContainsRune(telegramMarkdownV2Reserved, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Post publishes a post to the configured channel. Content over the limit is
+// truncated or continued in replies, depending on the length policy.
+func (t *TelegramClient) Post(ctx context.Context, post *Post) (interface{}, error) {
+	return t.postWithinLimit(ctx, PlatformTelegram, post, t.publishPost)
+}
+
+// publishPost sends post as one message. Text-only posts go out via
+// sendMessage, a single attachment via sendPhoto, and several attachments as
+// one or more sendMediaGroup albums.
+func (t *TelegramClient) publishPost(ctx context.Context, post *Post) (interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	if err := checkVisibility(PlatformTelegram, post.Visibility); err != nil {
+		return nil, err
+	}
+	if err := ValidateMedia(PlatformTelegram.String(), post.Media); err != nil {
+		return nil, err
+	}
+
+	logger.Info("posting to telegram",
+		"client", t.name,
+		"chat_id", t.chatID,
+		"content_len", len(post.Content),
+		"media_count", len(post.Media))
+
+	var (
+		messageID int
+		err       error
+	)
+	switch {
+	case len(post.Media) > 1:
+		messageID, err = t.sendMediaGroup(ctx, post)
+	case len(post.Media) == 1 && post.Media[0].IsVideo():
+		messageID, err = t.sendVideo(ctx, post)
+	case len(post.Media) == 1:
+		messageID, err = t.sendPhoto(ctx, post)
+	default:
+		messageID, err = t.sendMessage(ctx, post)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("posted to telegram",
+		"client", t.name,
+		"message_id", messageID)
+
+	return map[string]string{"id": strconv.Itoa(messageID)}, nil
+}
+
+// apiError turns a Bot API rejection into a PlatformError with the HTTP
+// status Telegram answered with, and a 429 into a RateLimitError honouring
+// retry_after. Other failures, e.g. network errors, are only wrapped.
+func (t *TelegramClient) apiError(op string, err error) error {
+	var tooMany *tgbot.TooManyRequestsError
+	status := 0
+	switch {
+	case errors.As(err, &tooMany):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, tgbot.ErrorBadRequest):
+		status = http.StatusBadRequest
+	case errors.Is(err, tgbot.ErrorUnauthorized):
+		status = http.StatusUnauthorized
+	case errors.Is(err, tgbot.ErrorForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, tgbot.ErrorNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, tgbot.ErrorConflict):
+		status = http.StatusConflict
+	default:
+		return fmt.Errorf("telegram: %s: %w", op, err)
+	}
+
+	var cause error = &StatusError{Op: "telegram " + op, StatusCode: status, Body: err.Error()}
+	if tooMany != nil {
+		cause = &RateLimitError{RetryAfter: time.Duration(tooMany.RetryAfter) * time.Second, Err: cause}
+	}
+	return &PlatformError{
+		Platform:   t.name,
+		StatusCode: status,
+		Body:       err.Error(),
+		Retryable:  retryableStatus(status),
+		Err:        cause,
+	}
+}
+
+// replyParameters threads a message under post.InReplyTo, a message ID in
+// the channel. A deleted parent does not make the send fail.
+func replyParameters(post *Post) *models.ReplyParameters {
+	id, err := strconv.Atoi(post.InReplyTo)
+	if err != nil {
+		return nil
+	}
+	return &models.ReplyParameters{MessageID: id, AllowSendingWithoutReply: true}
+}
+
+func (t *TelegramClient) sendMessage(ctx context.Context, post *Post) (int, error) {
+	msg, err := t.bot.SendMessage(ctx, &tgbot.SendMessageParams{
+		ChatID:          t.chatID,
+		Text:            t.formatText(post.Content),
+		ParseMode:       models.ParseMode(t.parseMode),
+		ReplyParameters: replyParameters(post),
+	})
+	if err != nil {
+		return 0, t.apiError("send message", err)
+	}
+	return msg.ID, nil
+}
+
+func (t *TelegramClient) sendPhoto(ctx context.Context, post *Post) (int, error) {
+	data, err := post.Media[0].GetDataFor(PlatformTelegram.String())
+	if err != nil {
+		return 0, fmt.Errorf("telegram: get media data: %w", err)
+	}
+
+	msg, err := t.bot.SendPhoto(ctx, &tgbot.SendPhotoParams{
+		ChatID:          t.chatID,
+		Photo:           &models.InputFileUpload{Filename: "photo_0" + post.Media[0].Extension(), Data: bytes.NewReader(data)},
+		Caption:         t.caption(post.Content, &post.Media[0]),
+		ParseMode:       models.ParseMode(t.parseMode),
+		ReplyParameters: replyParameters(post),
+	})
+	if err != nil {
+		return 0, t.apiError("send photo", err)
+	}
+	return msg.ID, nil
+}
+
+func (t *TelegramClient) sendVideo(ctx context.Context, post *Post) (int, error) {
+	data, err := post.Media[0].GetDataFor(PlatformTelegram.String())
+	if err != nil {
+		return 0, fmt.Errorf("telegram: get media data: %w", err)
+	}
+
+	msg, err := t.bot.SendVideo(ctx, &tgbot.SendVideoParams{
+		ChatID:          t.chatID,
+		Video:           &models.InputFileUpload{Filename: "video_0" + post.Media[0].Extension(), Data: bytes.NewReader(data)},
+		Caption:         t.caption(post.Content, &post.Media[0]),
+		ParseMode:       models.ParseMode(t.parseMode),
+		ReplyParameters: replyParameters(post),
+	})
+	if err != nil {
+		return 0, t.apiError("send video", err)
+	}
+	return msg.ID, nil
+}
+
+// sendMediaGroup posts the media as albums of at most telegramMediaGroupLimit
+// photos or videos, each file attached to the multipart request and referenced from the
+// media array via attach://. The caption rides on the first item of the first
+// album only, so it appears once. Returns the first message's ID.
+//
+// Once the first album is out, a failure is returned as a PartialPostError
+// carrying that ID: retrying would send the earlier albums again.
+func (t *TelegramClient) sendMediaGroup(ctx context.Context, post *Post) (int, error) {
+	logger := log.FromContext(ctx)
+
+	var sentIDs []int
+	fail := func(err error) (int, error) {
+		if len(sentIDs) == 0 {
+			return 0, err
+		}
+		logger.Error("telegram media group partially sent",
+			"client", t.name,
+			"sent_message_ids", sentIDs,
+			"error", err)
+		return 0, &PartialPostError{
+			Platform: t.name,
+			Result:   map[string]string{"id": strconv.Itoa(sentIDs[0])},
+			Err:      err,
+		}
+	}
+
+	start := 0
+	for _, size := range mediaGroupSizes(len(post.Media)) {
+		end := start + size
+
+		group := make([]models.InputMedia, 0, size)
+		for i := start; i < end; i++ {
+			media := &post.Media[i]
+			data, err := media.GetDataFor(PlatformTelegram.String())
+			if err != nil {
+				return fail(fmt.Errorf("telegram: get media data for attachment %d: %w", i, err))
+			}
+
+			// 正文只放在第一项；alt text 开启时每项带自己的 alt text
+			var content string
+			if i == 0 {
+				content = post.Content
+			}
+			caption := t.caption(content, media)
+			var parseMode string
+			if caption != "" {
+				parseMode = t.parseMode
+			}
+			if media.IsVideo() {
+				group = append(group, &models.InputMediaVideo{
+					Media:           fmt.Sprintf("attach://video_%d%s", i, media.Extension()),
+					MediaAttachment: bytes.NewReader(data),
+					Caption:         caption,
+					ParseMode:       models.ParseMode(parseMode),
+				})
+				continue
+			}
+			group = append(group, &models.InputMediaPhoto{
+				Media:           fmt.Sprintf("attach://photo_%d%s", i, media.Extension()),
+				MediaAttachment: bytes.NewReader(data),
+				Caption:         caption,
+				ParseMode:       models.ParseMode(parseMode),
+			})
+		}
+
+		logger.Debug("sending media group",
+			"client", t.name,
+			"batch_start", start,
+			"batch_size", len(group))
+
+		params := &tgbot.SendMediaGroupParams{
+			ChatID: t.chatID,
+			Media:  group,
+		}
+		if start == 0 {
+			params.ReplyParameters = replyParameters(post)
+		}
+		msgs, err := t.bot.SendMediaGroup(ctx, params)
+		if err != nil {
+			return fail(t.apiError("send media group", err))
+		}
+		for _, msg := range msgs {
+			sentIDs = append(sentIDs, msg.ID)
+		}
+		start = end
+	}
+
+	if len(sentIDs) == 0 {
+		return 0, nil
+	}
+	return sentIDs[0], nil
+}
+
+// mediaGroupSizes splits n attachments into as few albums as
+// telegramMediaGroupLimit allows, sized as evenly as possible: Telegram
+// rejects an album of a single item, which plain chunking leaves for e.g. 11.
+func mediaGroupSizes(n int) []int {
+	albums := (n + telegramMediaGroupLimit - 1) / telegramMediaGroupLimit
+	sizes := make([]int, albums)
+	for i := range sizes {
+		sizes[i] = n / albums
+		if i < n%albums {
+			sizes[i]++
+		}
+	}
+	return sizes
 }
 
 // Compile-time check that TelegramClient satisfies SocialClient.
@@ -182,8 +669,8 @@ func (t *TelegramClient) handleUpdate(ctx context.Context, _ *tgbot.Bot, update
 		"update_id", update.ID)
 	t.metrics.IncUpdates()
 
-	if msg := update.ChannelPost; msg != nil {
-		logger.Info("received channel post",
+	if msg, kind, edited := telegramUpdateMessage(update); msg != nil {
+		logger.Info("received "+kind,
 			"client", t.name,
 			"message_id", msg.ID,
 			"chat_id", msg.Chat.ID,
@@ -195,8 +682,25 @@ func (t *TelegramClient) handleUpdate(ctx context.Context, _ *tgbot.Bot, update
 			"has_caption", msg.Caption != "",
 			"has_photo", len(msg.Photo) > 0,
 			"has_video", msg.Video != nil,
+			"has_document", msg.Document != nil,
 			"media_group_id", msg.MediaGroupID)
-		t.ingest(ctx, msg)
+
+		switch {
+		case msg.Chat.Type != models.ChatTypeChannel && !t.isSourceChat(msg.Chat):
+			// 频道外的消息（如私聊 bot）只接受配置的 chat_id，避免任何人都能触发同步
+			logger.Debug("ignored message from another chat",
+				"client", t.name,
+				"message_id", msg.ID,
+				"chat_id", msg.Chat.ID)
+		case edited && msg.MediaGroupID != "":
+			// 相册已按 media_group_id 合并为一个帖子，单条的编辑无法对应回去
+			logger.Info("ignored edit of a media group message",
+				"client", t.name,
+				"message_id", msg.ID,
+				"media_group_id", msg.MediaGroupID)
+		default:
+			t.ingest(ctx, msg)
+		}
 	}
 
 	if t.cursor != nil {
@@ -209,6 +713,32 @@ func (t *TelegramClient) handleUpdate(ctx context.Context, _ *tgbot.Bot, update
 	}
 }
 
+// telegramUpdateMessage returns the message an update carries, a name for
+// its kind and whether it is an edit. An edit is ingested like a new message
+// with the same ID, so the sync service sees it as changed content.
+func telegramUpdateMessage(update *models.Update) (*models.Message, string, bool) {
+	switch {
+	case update.ChannelPost != nil:
+		return update.ChannelPost, "channel post", false
+	case update.EditedChannelPost != nil:
+		return update.EditedChannelPost, "edited channel post", true
+	case update.Message != nil:
+		return update.Message, "message", false
+	case update.EditedMessage != nil:
+		return update.EditedMessage, "edited message", true
+	}
+	return nil, "", false
+}
+
+// isSourceChat reports whether chat is the configured chat_id, given either
+// as a numeric ID or as an @username.
+func (t *TelegramClient) isSourceChat(chat models.Chat) bool {
+	if username, ok := strings.CutPrefix(t.chatID, "@"); ok {
+		return chat.Username != "" && strings.EqualFold(username, chat.Username)
+	}
+	return t.chatID == strconv.FormatInt(chat.ID, 10)
+}
+
 // ingest merges media-group parts and appends completed posts to buffer.
 func (t *TelegramClient) ingest(ctx context.Context, msg *models.Message) {
 	logger := log.FromContext(ctx)
@@ -300,7 +830,8 @@ func (t *TelegramClient) flushGroupLocked(groupID string) {
 }
 
 // convert builds a Post from a Telegram message. Returns nil if the message
-// carries no content HyperSync understands (e.g. sticker, poll, document).
+// carries no content HyperSync understands (e.g. sticker, poll, a non-media
+// document).
 func (t *TelegramClient) convert(ctx context.Context, msg *models.Message) *Post {
 	logger := log.FromContext(ctx)
 
@@ -326,6 +857,12 @@ func (t *TelegramClient) convert(ctx context.Context, msg *models.Message) *Post
 		"content_len", len(content),
 		"media_count", len(media))
 
+	// Only public channels (with a username) have a permalink.
+	var sourceURL string
+	if msg.Chat.Username != "" {
+		sourceURL = fmt.Sprintf("https://t.me/%s/%d", msg.Chat.Username, msg.ID)
+	}
+
 	return &Post{
 		ID:             id,
 		Content:        content,
@@ -333,65 +870,89 @@ func (t *TelegramClient) convert(ctx context.Context, msg *models.Message) *Post
 		Media:          media,
 		SourcePlatform: t.name,
 		OriginalID:     id,
+		SourceURL:      sourceURL,
 		CreatedAt:      time.Unix(int64(msg.Date), 0).UTC(),
 	}
 }
 
-// mediaURLs downloads a message's photo (largest size only) and/or video, if
-// present, and uploads each to object storage, returning their permanent
-// CDN URLs. Telegram's own temporary file-serving URLs are never returned.
+// mediaURLs downloads a message's photo (largest size only), video and/or
+// image or video document, if present, and uploads each to object storage,
+// returning their permanent CDN URLs. Telegram's own temporary file-serving
+// URLs are never returned.
 func (t *TelegramClient) mediaURLs(ctx context.Context, msg *models.Message) []string {
-	logger := log.FromContext(ctx)
 	var urls []string
 
 	if len(msg.Photo) > 0 {
 		largest := msg.Photo[len(msg.Photo)-1]
-		logger.Debug("downloading photo",
-			"client", t.name,
-			"message_id", msg.ID,
-			"file_id", largest.FileID)
-		start := time.Now()
-		if url, err := t.downloadAndStoreFile(ctx, largest.FileID); err == nil {
-			t.metrics.RecordMediaUpload("photo", metrics.StatusSuccess, time.Since(start))
-			logger.Info("photo uploaded to CDN",
-				"client", t.name,
-				"message_id", msg.ID,
-				"url", url)
+		if url, ok := t.storeMedia(ctx, msg, "photo", largest.FileID); ok {
 			urls = append(urls, url)
-		} else {
-			t.metrics.RecordMediaUpload("photo", metrics.StatusError, time.Since(start))
-			logger.Error("failed to get photo file",
-				"client", t.name,
-				"message_id", msg.ID,
-				"error", err)
 		}
 	}
 
 	if msg.Video != nil {
-		logger.Debug("downloading video",
-			"client", t.name,
-			"message_id", msg.ID,
-			"file_id", msg.Video.FileID)
-		start := time.Now()
-		if url, err := t.downloadAndStoreFile(ctx, msg.Video.FileID); err == nil {
-			t.metrics.RecordMediaUpload("video", metrics.StatusSuccess, time.Since(start))
-			logger.Info("video uploaded to CDN",
-				"client", t.name,
-				"message_id", msg.ID,
-				"url", url)
+		if url, ok := t.storeMedia(ctx, msg, "video", msg.Video.FileID); ok {
+			urls = append(urls, url)
+		}
+	}
+
+	// 以文件形式发送的图片/视频（不压缩）作为 document 到达；其他文件类型忽略
+	if doc := msg.Document; doc != nil && isTelegramMediaDocument(doc) {
+		if url, ok := t.storeMedia(ctx, msg, "document", doc.FileID); ok {
 			urls = append(urls, url)
-		} else {
-			t.metrics.RecordMediaUpload("video", metrics.StatusError, time.Since(start))
-			logger.Error("failed to get video file",
-				"client", t.name,
-				"message_id", msg.ID,
-				"error", err)
 		}
 	}
 
 	return urls
 }
 
+// storeMedia downloads one file of msg to object storage, recording the
+// upload metric under kind. Failures are logged and reported as !ok.
+func (t *TelegramClient) storeMedia(ctx context.Context, msg *models.Message, kind, fileID string) (string, bool) {
+	logger := log.FromContext(ctx)
+
+	logger.Debug("downloading "+kind,
+		"client", t.name,
+		"message_id", msg.ID,
+		"file_id", fileID)
+	start := time.Now()
+	url, err := t.downloadAndStoreFile(ctx, fileID)
+	if err != nil {
+		t.metrics.RecordMediaUpload(kind, metrics.StatusError, time.Since(start))
+		logger.Error("failed to get "+kind+" file",
+			"client", t.name,
+			"message_id", msg.ID,
+			"error", err)
+		return "", false
+	}
+	t.metrics.RecordMediaUpload(kind, metrics.StatusSuccess, time.Since(start))
+	logger.Info(kind+" uploaded to CDN",
+		"client", t.name,
+		"message_id", msg.ID,
+		"url", url)
+	return url, true
+}
+
+// isTelegramMediaDocument reports whether a document is an image or video
+// that can be cross-posted as media.
+func isTelegramMediaDocument(doc *models.Document) bool {
+	return strings.HasPrefix(doc.MimeType, "image/") || strings.HasPrefix(doc.MimeType, "video/")
+}
+
+// getFile resolves a Telegram file ID via the getFile API and returns the
+// download URL (<api>/file/bot<token>/<file_path>) along with the file path.
+// The URL embeds the bot token and expires after about an hour, so it is only
+// used to fetch the bytes and must never end up on a Post.
+func (t *TelegramClient) getFile(ctx context.Context, fileID string) (string, string, error) {
+	file, err := t.bot.GetFile(ctx, &tgbot.GetFileParams{FileID: fileID})
+	if err != nil {
+		return "", "", fmt.Errorf("telegram: get file: %w", err)
+	}
+	if file.FilePath == "" {
+		return "", "", fmt.Errorf("telegram: get file %s: empty file_path", fileID)
+	}
+	return t.bot.FileDownloadLink(file), file.FilePath, nil
+}
+
 // downloadAndStoreFile fetches a Telegram file by ID via the Bot API,
 // downloads its bytes over Telegram's temporary file-serving URL, uploads
 // them to object storage, and returns the permanent CDN URL.
@@ -402,33 +963,36 @@ func (t *TelegramClient) downloadAndStoreFile(ctx context.Context, fileID string
 		return "", fmt.Errorf("telegram: no object storage configured")
 	}
 
-	file, err := t.bot.GetFile(ctx, &tgbot.GetFileParams{FileID: fileID})
+	tempURL, filePath, err := t.getFile(ctx, fileID)
 	if err != nil {
-		return "", fmt.Errorf("telegram: get file: %w", err)
+		return "", err
 	}
-	tempURL := t.bot.FileDownloadLink(file)
 
 	logger.Debug("downloading file from Telegram",
 		"client", t.name,
 		"file_id", fileID,
-		"file_path", file.FilePath)
+		"file_path", filePath)
 
-	resp, err := http.Get(tempURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tempURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("telegram: create download request: %w", err)
+	}
+	resp, err := t.httpClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("telegram: download file: %w", err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("telegram: download file: status code %d", resp.StatusCode)
+		return "", newPlatformError(t.name, "telegram download file", resp, nil)
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	data, err := ReadLimited(resp.Body, MaxMediaBytes)
 	if err != nil {
 		return "", fmt.Errorf("telegram: read file: %w", err)
 	}
 
 	contentType := http.DetectContentType(data)
-	key := fmt.Sprintf("telegram/%s/%s-%s", time.Now().Format("2006/01/02"), uuid.New().String(), filepath.Base(file.FilePath))
+	key := fmt.Sprintf("telegram/%s/%s-%s", time.Now().Format("2006/01/02"), uuid.New().String(), filepath.Base(filePath))
 
 	logger.Debug("uploading file to object storage",
 		"client", t.name,
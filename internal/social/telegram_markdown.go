@@ -0,0 +1,165 @@
+package social
+
+import (
+	"html"
+	"strings"
+)
+
+// telegramMarkup renders Markdown spans for one Telegram parse mode.
+type telegramMarkup struct {
+	// text escapes literal text.
+	text func(string) string
+	// code renders an inline code span.
+	code func(string) string
+	// wrap renders formatted, already rendered inner text.
+	wrap func(style, inner string) string
+	// pre renders a fenced code block with an optional language.
+	pre func(lang, body string) string
+	// link renders a link around already rendered inner text.
+	link func(inner, url string) string
+}
+
+var (
+	telegramMarkdownV2Markup = telegramMarkup{
+		text: escapeMarkdownV2,
+		code: func(body string) string { return "`" + escapeMarkdownV2Code(body) + "`" },
+		wrap: func(style, inner string) string {
+			marker := map[string]string{"bold": "*", "italic": "_", "strike": "~"}[style]
+			return marker + inner + marker
+		},
+		pre: func(lang, body string) string {
+			return "```" + lang + "\n" + escapeMarkdownV2Code(body) + "```"
+		},
+		link: func(inner, url string) string {
+			// 链接地址里只需转义 ) 和 \
+			return "[" + inner + "](" + strings.NewReplacer(`\`, `\\`, ")", `\)`).Replace(url) + ")"
+		},
+	}
+
+	telegramHTMLMarkup = telegramMarkup{
+		text: html.EscapeString,
+		code: func(body string) string { return "<code>" + html.EscapeString(body) + "</code>" },
+		wrap: func(style, inner string) string {
+			tag := map[string]string{"bold": "b", "italic": "i", "strike": "s"}[style]
+			return "<" + tag + ">" + inner + "</" + tag + ">"
+		},
+		pre: func(lang, body string) string {
+			if lang == "" {
+				return "<pre>" + html.EscapeString(body) + "</pre>"
+			}
+			return `<pre><code class="language-` + html.EscapeString(lang) + `">` + html.EscapeString(body) + "</code></pre>"
+		},
+		link: func(inner, url string) string {
+			return `<a href="` + html.EscapeString(url) + `">` + inner + "</a>"
+		},
+	}
+)
+
+// telegramInlineStyles maps the Markdown emphasis delimiters to their style,
+// longest first so "**" is not read as two "*".
+var telegramInlineStyles = []struct{ delim, style string }{
+	{"**", "bold"},
+	{"__", "bold"},
+	{"~~", "strike"},
+	{"*", "italic"},
+	{"_", "italic"},
+}
+
+// escapeMarkdownV2Code escapes the characters MarkdownV2 reserves inside
+// code spans and blocks.
+func escapeMarkdownV2Code(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(s)
+}
+
+// render converts the Markdown in s (bold, italic, strikethrough, inline
+// code, fenced code blocks and [text](url) links) to the parse mode's
+// entities and escapes everything else as literal text. Unmatched markers
+// are literal, so unformatted text comes out fully escaped.
+func (m telegramMarkup) render(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	lit := 0
+	for i := 0; i < len(s); {
+		out, n := m.span(s, i)
+		if n == 0 {
+			i++
+			continue
+		}
+		b.WriteString(m.text(s[lit:i]))
+		b.WriteString(out)
+		i += n
+		lit = i
+	}
+	b.WriteString(m.text(s[lit:]))
+	return b.String()
+}
+
+// span renders the Markdown span starting at s[i] and returns it with the
+// number of source bytes it covers, or 0 if none starts there.
+func (m telegramMarkup) span(s string, i int) (string, int) {
+	rest := s[i:]
+	switch {
+	case strings.HasPrefix(rest, "```"):
+		end := strings.Index(rest[3:], "```")
+		if end < 0 {
+			return "", 0
+		}
+		body := rest[3 : 3+end]
+		// 首行只有一个词时视为语言标记
+		var lang string
+		if nl := strings.IndexByte(body, '\n'); nl >= 0 && !strings.ContainsAny(body[:nl], " \t") {
+			lang, body = body[:nl], body[nl+1:]
+		}
+		return m.pre(lang, body), 3 + end + 3
+
+	case rest[0] == '`':
+		end := strings.IndexAny(rest[1:], "`\n")
+		if end <= 0 || rest[1+end] != '`' {
+			return "", 0
+		}
+		return m.code(rest[1 : 1+end]), end + 2
+
+	case rest[0] == '[':
+		closeText := strings.Index(rest, "](")
+		if closeText <= 1 || strings.ContainsAny(rest[1:closeText], "[]\n") {
+			return "", 0
+		}
+		closeURL := strings.IndexByte(rest[closeText:], ')')
+		if closeURL < 0 {
+			return "", 0
+		}
+		url := rest[closeText+2 : closeText+closeURL]
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") || strings.ContainsAny(url, " \t\n") {
+			return "", 0
+		}
+		return m.link(m.render(rest[1:closeText]), url), closeText + closeURL + 1
+	}
+
+	for _, st := range telegramInlineStyles {
+		if !strings.HasPrefix(rest, st.delim) {
+			continue
+		}
+		// 单个 * 或 _ 需要在词边界上，避免 snake_case、2*3*4 被当成斜体
+		single := len(st.delim) == 1
+		if single && i > 0 && isWordByte(s[i-1]) {
+			return "", 0
+		}
+		d := len(st.delim)
+		end := strings.Index(rest[d:], st.delim)
+		if end <= 0 {
+			return "", 0
+		}
+		inner := rest[d : d+end]
+		after := d + end + d
+		if strings.ContainsRune(inner, '\n') || strings.TrimSpace(inner) != inner ||
+			(single && after < len(rest) && isWordByte(rest[after])) {
+			return "", 0
+		}
+		return m.wrap(st.style, m.render(inner)), after
+	}
+	return "", 0
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
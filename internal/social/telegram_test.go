@@ -28,6 +28,18 @@ type fakeTelegramServer struct {
 	batches        [][]map[string]any
 	files          map[string]string // file_id -> file_path
 	requestOffsets []string
+	sent           []sentRequest
+	nextMessageID  int
+	// failSendsFrom, when positive, is the 1-based send* call from which
+	// every send fails with 400.
+	failSendsFrom int
+}
+
+// sentRequest records a send* call made against the fake server.
+type sentRequest struct {
+	method string
+	fields map[string]string
+	files  []string // multipart file part names
 }
 
 func newFakeTelegramServer(t *testing.T) *fakeTelegramServer {
@@ -67,6 +79,62 @@ func (f *fakeTelegramServer) handle(w http.ResponseWriter, r *http.Request) {
 			"result": map[string]any{"file_id": fileID, "file_path": path},
 		})
 
+	case strings.HasSuffix(r.URL.Path, "/sendMessage"),
+		strings.HasSuffix(r.URL.Path, "/sendPhoto"),
+		strings.HasSuffix(r.URL.Path, "/sendVideo"),
+		strings.HasSuffix(r.URL.Path, "/sendMediaGroup"):
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		req := sentRequest{method: method, fields: make(map[string]string)}
+		if err := r.ParseMultipartForm(1 << 20); err == nil {
+			for k, v := range r.MultipartForm.Value {
+				req.fields[k] = v[0]
+			}
+			for k := range r.MultipartForm.File {
+				req.files = append(req.files, k)
+			}
+		}
+
+		count := 1
+		if method == "sendMediaGroup" {
+			var items []map[string]any
+			_ = json.Unmarshal([]byte(req.fields["media"]), &items)
+			count = len(items)
+		}
+
+		f.mu.Lock()
+		if f.failSendsFrom > 0 && len(f.sent)+1 >= f.failSendsFrom {
+			f.mu.Unlock()
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]any{"ok": false, "error_code": 400, "description": "Bad Request: too many attachments"})
+			return
+		}
+		f.sent = append(f.sent, req)
+		msgs := make([]map[string]any, 0, count)
+		for range count {
+			f.nextMessageID++
+			msgs = append(msgs, map[string]any{"message_id": 100 + f.nextMessageID, "date": 0})
+		}
+		f.mu.Unlock()
+
+		if method == "sendMediaGroup" {
+			writeJSON(w, map[string]any{"ok": true, "result": msgs})
+		} else {
+			writeJSON(w, map[string]any{"ok": true, "result": msgs[0]})
+		}
+
+	case strings.HasSuffix(r.URL.Path, "/setWebhook"),
+		strings.HasSuffix(r.URL.Path, "/deleteWebhook"):
+		req := sentRequest{method: r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], fields: make(map[string]string)}
+		if err := r.ParseMultipartForm(1 << 20); err == nil {
+			for k, v := range r.MultipartForm.Value {
+				req.fields[k] = v[0]
+			}
+		}
+		f.mu.Lock()
+		f.sent = append(f.sent, req)
+		f.mu.Unlock()
+		writeJSON(w, map[string]any{"ok": true, "result": true})
+
 	case strings.Contains(r.URL.Path, "/file/bot"):
 		w.Header().Set("Content-Type", "image/jpeg")
 		_, _ = w.Write([]byte("fake-file-bytes"))
@@ -93,6 +161,12 @@ func (f *fakeTelegramServer) setFile(fileID, filePath string) {
 	f.files[fileID] = filePath
 }
 
+func (f *fakeTelegramServer) sentRequests() []sentRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]sentRequest(nil), f.sent...)
+}
+
 func (f *fakeTelegramServer) offsets() []string {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -142,7 +216,7 @@ func TestTelegram_ListPosts_SingleTextMessage(t *testing.T) {
 		},
 	})
 
-	client, err := NewTelegramClient("test-token", "-1001234567890", "my-telegram", server.URL, nil, nil, "")
+	client, err := NewTelegramClient("test-token", "-1001234567890", "my-telegram", server.URL, nil, nil, "", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -181,7 +255,7 @@ func TestTelegram_ListPosts_PhotoWithCaption(t *testing.T) {
 	})
 
 	storage := media.NewMemoryObjectStorage()
-	client, err := NewTelegramClient("test-token", "-1001234567890", "my-telegram", server.URL, nil, storage, "https://cdn.example.com")
+	client, err := NewTelegramClient("test-token", "-1001234567890", "my-telegram", server.URL, nil, storage, "https://cdn.example.com", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -204,6 +278,23 @@ func TestTelegram_ListPosts_PhotoWithCaption(t *testing.T) {
 	assert.True(t, storage.Has(key), "uploaded file should be present in object storage")
 }
 
+func TestTelegram_GetFile(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	server.setFile("large_id", "photos/file_123.jpg")
+
+	client, err := NewTelegramClient("test-token", "-1001234567890", "my-telegram", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	url, path, err := client.getFile(context.Background(), "large_id")
+	require.NoError(t, err)
+	assert.Equal(t, "photos/file_123.jpg", path)
+	assert.Equal(t, server.URL+"/file/bottest-token/photos/file_123.jpg", url)
+
+	_, _, err = client.getFile(context.Background(), "unknown_id")
+	assert.Error(t, err, "a file without file_path cannot be downloaded")
+}
+
 func TestTelegram_ListPosts_SkippedMessageTypes(t *testing.T) {
 	server := newFakeTelegramServer(t)
 	server.pushBatch([]map[string]any{
@@ -245,7 +336,7 @@ func TestTelegram_ListPosts_SkippedMessageTypes(t *testing.T) {
 		},
 	})
 
-	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, nil, "")
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, nil, "", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -301,7 +392,7 @@ func TestTelegram_ListPosts_OffsetAdvancement(t *testing.T) {
 	})
 
 	cursor := newMemoryCursor()
-	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, cursor, nil, "")
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, cursor, nil, "", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -324,7 +415,7 @@ func TestTelegram_ListPosts_EmptyBatch(t *testing.T) {
 	cursor := newMemoryCursor()
 	cursor.offsets["tg"] = 50 // pre-existing offset
 
-	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, cursor, nil, "")
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, cursor, nil, "", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -360,7 +451,7 @@ func TestTelegram_ListPosts_EntityStripping(t *testing.T) {
 		},
 	})
 
-	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, nil, "")
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, nil, "", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -404,7 +495,7 @@ func TestTelegram_ListPosts_MediaGroupMerge(t *testing.T) {
 	})
 
 	storage := media.NewMemoryObjectStorage()
-	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, storage, "https://cdn.example.com")
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, storage, "https://cdn.example.com", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -471,7 +562,7 @@ func TestTelegram_ListPosts_InterleavedMediaGroups(t *testing.T) {
 	})
 
 	storage := media.NewMemoryObjectStorage()
-	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, storage, "https://cdn.example.com")
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, storage, "https://cdn.example.com", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -521,7 +612,7 @@ func TestTelegram_ListPosts_Video(t *testing.T) {
 	})
 
 	storage := media.NewMemoryObjectStorage()
-	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, storage, "https://cdn.example.com")
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, storage, "https://cdn.example.com", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -538,6 +629,128 @@ func TestTelegram_ListPosts_Video(t *testing.T) {
 	assert.NotContains(t, url, "api.telegram.org")
 }
 
+func TestTelegram_ListPosts_VideoDocument(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	server.setFile("doc_video", "documents/raw.mp4")
+	server.setFile("doc_pdf", "documents/notes.pdf")
+	server.pushBatch([]map[string]any{
+		{
+			"update_id": 650,
+			"channel_post": map[string]any{
+				"message_id": 85,
+				"date":       1000,
+				"caption":    "Uncompressed clip",
+				"chat":       map[string]any{"id": -100, "type": "channel"},
+				"video":      map[string]any{"file_id": "video_id", "file_unique_id": "v1", "width": 1280, "height": 720, "duration": 12},
+				"document":   map[string]any{"file_id": "doc_video", "file_unique_id": "d1", "file_name": "raw.mp4", "mime_type": "video/mp4"},
+			},
+		},
+		{
+			"update_id": 651,
+			"channel_post": map[string]any{
+				"message_id": 86,
+				"date":       1001,
+				"caption":    "Meeting notes",
+				"chat":       map[string]any{"id": -100, "type": "channel"},
+				"document":   map[string]any{"file_id": "doc_pdf", "file_unique_id": "d2", "file_name": "notes.pdf", "mime_type": "application/pdf"},
+			},
+		},
+	})
+	server.setFile("video_id", "videos/clip.mp4")
+
+	storage := media.NewMemoryObjectStorage()
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, storage, "https://cdn.example.com", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	posts := waitForPosts(t, client, 2, 3*time.Second)
+	require.Len(t, posts, 2)
+
+	require.Len(t, posts[0].Media, 2, "the video and the video document are both stored")
+	assert.Contains(t, posts[0].Media[0].GetURL(), "clip.mp4")
+	assert.Contains(t, posts[0].Media[1].GetURL(), "raw.mp4")
+
+	assert.Equal(t, "Meeting notes", posts[1].Content)
+	assert.Empty(t, posts[1].Media, "non-media documents are not stored")
+}
+
+func TestTelegram_ListPosts_EditedChannelPost(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	server.pushBatch([]map[string]any{
+		{
+			"update_id": 660,
+			"channel_post": map[string]any{
+				"message_id": 87, "date": 1000, "text": "typo",
+				"chat": map[string]any{"id": -100, "type": "channel"},
+			},
+		},
+		{
+			"update_id": 661,
+			"edited_channel_post": map[string]any{
+				"message_id": 87, "date": 1000, "edit_date": 1060, "text": "fixed",
+				"chat": map[string]any{"id": -100, "type": "channel"},
+			},
+		},
+		{
+			"update_id": 662,
+			"edited_channel_post": map[string]any{
+				"message_id": 88, "date": 1000, "edit_date": 1060, "caption": "album caption",
+				"media_group_id": "album1",
+				"chat":           map[string]any{"id": -100, "type": "channel"},
+			},
+		},
+	})
+
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	posts := waitForPosts(t, client, 2, 3*time.Second)
+	require.Len(t, posts, 2)
+	assert.Equal(t, "typo", posts[0].Content)
+	assert.Equal(t, "87", posts[1].ID, "an edit keeps the message ID")
+	assert.Equal(t, "fixed", posts[1].Content)
+	assert.Equal(t, time.Unix(1000, 0).UTC(), posts[1].CreatedAt)
+	assertNoMorePosts(t, client, 900*time.Millisecond)
+}
+
+func TestTelegram_ListPosts_MessagesFromConfiguredChatOnly(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	server.pushBatch([]map[string]any{
+		{
+			"update_id": 670,
+			"message": map[string]any{
+				"message_id": 1, "date": 1000, "text": "from the group",
+				"chat": map[string]any{"id": -200, "type": "supergroup", "username": "MyGroup"},
+			},
+		},
+		{
+			"update_id": 671,
+			"message": map[string]any{
+				"message_id": 2, "date": 1000, "text": "hi bot",
+				"chat": map[string]any{"id": 12345, "type": "private"},
+			},
+		},
+		{
+			"update_id": 672,
+			"edited_message": map[string]any{
+				"message_id": 1, "date": 1000, "edit_date": 1060, "text": "from the group, edited",
+				"chat": map[string]any{"id": -200, "type": "supergroup", "username": "MyGroup"},
+			},
+		},
+	})
+
+	client, err := NewTelegramClient("test-token", "@mygroup", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	posts := waitForPosts(t, client, 2, 3*time.Second)
+	require.Len(t, posts, 2)
+	assert.Equal(t, "from the group", posts[0].Content)
+	assert.Equal(t, "from the group, edited", posts[1].Content)
+	assertNoMorePosts(t, client, 300*time.Millisecond)
+}
+
 func TestTelegram_ListPosts_PhotoWithoutCaption(t *testing.T) {
 	msgDate := time.Date(2026, 7, 14, 10, 0, 0, 0, time.UTC)
 	server := newFakeTelegramServer(t)
@@ -557,7 +770,7 @@ func TestTelegram_ListPosts_PhotoWithoutCaption(t *testing.T) {
 	})
 
 	storage := media.NewMemoryObjectStorage()
-	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, storage, "https://cdn.example.com")
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, storage, "https://cdn.example.com", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -599,7 +812,7 @@ func TestTelegram_ListPosts_DownloadFailureSkipsMessage(t *testing.T) {
 		},
 	})
 
-	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, nil, "https://cdn.example.com")
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, nil, "https://cdn.example.com", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
@@ -611,16 +824,321 @@ func TestTelegram_ListPosts_DownloadFailureSkipsMessage(t *testing.T) {
 	assert.Equal(t, "Still works", posts[1].Content)
 }
 
-func TestTelegram_Post_NotImplemented(t *testing.T) {
+func TestTelegram_Post_TextOnly(t *testing.T) {
 	server := newFakeTelegramServer(t)
-	client, err := NewTelegramClient("test-token", "chan", "tg", server.URL, nil, nil, "")
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
 	require.NoError(t, err)
 	defer client.Close()
 
 	result, err := client.Post(context.Background(), &Post{Content: "hello"})
-	assert.Nil(t, result)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "101"}, result)
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "sendMessage", sent[0].method)
+	assert.Equal(t, "@chan", sent[0].fields["chat_id"])
+	assert.Equal(t, "hello", sent[0].fields["text"])
+}
+
+func TestTelegram_Post_SinglePhoto(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Post(context.Background(), &Post{
+		Content: "one photo",
+		Media:   []Media{*NewMedia([]byte("img"))},
+	})
+	require.NoError(t, err)
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "sendPhoto", sent[0].method)
+	assert.Equal(t, "one photo", sent[0].fields["caption"])
+	assert.Equal(t, []string{"photo"}, sent[0].files)
+}
+
+func TestTelegram_Post_SingleVideo(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Post(context.Background(), &Post{
+		Content: "one video",
+		Media:   []Media{*NewMedia(mp4Signature)},
+	})
+	require.NoError(t, err)
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "sendVideo", sent[0].method)
+	assert.Equal(t, "one video", sent[0].fields["caption"])
+	assert.Equal(t, []string{"video"}, sent[0].files)
+}
+
+func TestTelegram_Post_MixedMediaGroup(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Post(context.Background(), &Post{
+		Content: "mixed",
+		Media:   []Media{*NewMedia(pngSignature), *NewMedia(mp4Signature)},
+	})
+	require.NoError(t, err)
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 1)
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(sent[0].fields["media"]), &items))
+	require.Len(t, items, 2)
+	assert.Equal(t, "photo", items[0]["type"])
+	assert.Equal(t, "attach://photo_0.png", items[0]["media"])
+	assert.Equal(t, "video", items[1]["type"])
+	assert.Equal(t, "attach://video_1.mp4", items[1]["media"])
+}
+
+func TestTelegram_Post_MediaGroupBatches(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	post := &Post{Content: "album"}
+	for range 12 {
+		post.Media = append(post.Media, *NewMedia([]byte("img")))
+	}
+
+	result, err := client.Post(context.Background(), post)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "101"}, result, "should return the first message of the first album")
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 2)
+
+	var first, second []map[string]any
+	assert.Equal(t, "sendMediaGroup", sent[0].method)
+	require.NoError(t, json.Unmarshal([]byte(sent[0].fields["media"]), &first))
+	require.NoError(t, json.Unmarshal([]byte(sent[1].fields["media"]), &second))
+	require.Len(t, first, 6, "12 attachments are split evenly")
+	require.Len(t, second, 6)
+	assert.Len(t, sent[0].files, 6)
+	assert.Len(t, sent[1].files, 6)
+
+	assert.Equal(t, "album", first[0]["caption"])
+	for _, item := range append(first[1:], second...) {
+		assert.Empty(t, item["caption"], "caption should only be set on the first item")
+	}
+}
+
+func TestMediaGroupSizes(t *testing.T) {
+	assert.Equal(t, []int{2}, mediaGroupSizes(2))
+	assert.Equal(t, []int{10}, mediaGroupSizes(10))
+	assert.Equal(t, []int{6, 5}, mediaGroupSizes(11), "no album of a single item")
+	assert.Equal(t, []int{7, 7, 7}, mediaGroupSizes(21))
+}
+
+func TestTelegram_Post_MediaGroupPartialFailure(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	server.failSendsFrom = 2
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	post := &Post{Content: "album"}
+	for range 12 {
+		post.Media = append(post.Media, *NewMedia([]byte("img")))
+	}
+
+	_, err = client.Post(context.Background(), post)
+	partial, ok := AsPartialPostError(err)
+	require.True(t, ok, "a failure after the first album must be reported as partial, got %v", err)
+	assert.Equal(t, map[string]string{"id": "101"}, partial.Result, "should carry the first album's message")
+	assert.Equal(t, http.StatusBadRequest, HTTPStatusCode(err))
+	require.Len(t, server.sentRequests(), 1)
+}
+
+func TestTelegram_Post_MediaGroupFirstAlbumFailure(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	server.failSendsFrom = 1
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Post(context.Background(), &Post{
+		Content: "album",
+		Media:   []Media{*NewMedia([]byte("img")), *NewMedia([]byte("img"))},
+	})
+	require.Error(t, err)
+	_, partial := AsPartialPostError(err)
+	assert.False(t, partial, "nothing was sent, so the post can be retried as a whole")
+}
+
+func TestTelegram_Post_AltTextInCaption(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+	client.SetAltTextInCaption(true)
+
+	photo := NewMedia([]byte("img"))
+	photo.Description = "a cat on a keyboard"
+	_, err = client.Post(context.Background(), &Post{Content: "one photo", Media: []Media{*photo}})
+	require.NoError(t, err)
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "one photo\n\nAlt: a cat on a keyboard", sent[0].fields["caption"])
+}
+
+func TestTelegram_Post_AltTextInMediaGroup(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+	client.SetAltTextInCaption(true)
+
+	first := NewMedia(pngSignature)
+	first.Description = "first"
+	second := NewMedia(pngSignature)
+	second.Description = "second"
+	third := NewMedia(pngSignature)
+	_, err = client.Post(context.Background(), &Post{Content: "album", Media: []Media{*first, *second, *third}})
+	require.NoError(t, err)
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 1)
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(sent[0].fields["media"]), &items))
+	require.Len(t, items, 3)
+	assert.Equal(t, "album\n\nAlt: first", items[0]["caption"])
+	assert.Equal(t, "Alt: second", items[1]["caption"])
+	assert.Empty(t, items[2]["caption"])
+}
+
+func TestTelegram_Post_AltTextOmittedByDefault(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	photo := NewMedia([]byte("img"))
+	photo.Description = "a cat on a keyboard"
+	_, err = client.Post(context.Background(), &Post{Content: "one photo", Media: []Media{*photo}})
+	require.NoError(t, err)
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "one photo", sent[0].fields["caption"])
+}
+
+func TestTelegram_Post_ParseMode(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+	client.SetParseMode("MarkdownV2")
+
+	_, err = client.Post(context.Background(), &Post{Content: "v1.2 is out!"})
+	require.NoError(t, err)
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "MarkdownV2", sent[0].fields["parse_mode"])
+	assert.Equal(t, `v1\.2 is out\!`, sent[0].fields["text"])
+}
+
+func TestEscapeMarkdownV2(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"snake_case", `snake\_case`},
+		{"2 * 3", `2 \* 3`},
+		{"[link]", `\[link\]`},
+		{"end.", `end\.`},
+		{"plain text", "plain text"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, escapeMarkdownV2(tt.in), tt.in)
+	}
+}
+
+func TestTelegram_Post_ParseModeConvertsMarkdown(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+	client.SetParseMode("HTML")
+	client.SetAltTextInCaption(true)
+
+	photo := NewMedia([]byte("img"))
+	photo.Description = "**not** bold"
+	_, err = client.Post(context.Background(), &Post{Content: "**new** <release>", Media: []Media{*photo}})
+	require.NoError(t, err)
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "<b>new</b> &lt;release&gt;\n\nAlt: **not** bold", sent[0].fields["caption"],
+		"alt text is escaped, not parsed")
+}
+
+func TestTelegramMarkup_MarkdownV2(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain v1.2!", `plain v1\.2\!`},
+		{"**bold** and *italic* and _also_", `*bold* and _italic_ and _also_`},
+		{"~~gone~~", `~gone~`},
+		{"**a.b**", `*a\.b*`},
+		{"see [the docs](https://example.com/a_b) now.", `see [the docs](https://example.com/a_b) now\.`},
+		{"[**v2** out](https://example.com)", `[*v2* out](https://example.com)`},
+		{"run `go test ./...` first", "run `go test ./...` first"},
+		{"```go\nfmt.Println(`a`)\n```", "```go\nfmt.Println(\\`a\\`)\n```"},
+		{"snake_case_name", `snake\_case\_name`},
+		{"2*3*4", `2\*3\*4`},
+		{"**unclosed", `\*\*unclosed`},
+		{"[no url](ftp://x)", `\[no url\]\(ftp://x\)`},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, telegramMarkdownV2Markup.render(tt.in), tt.in)
+	}
+}
+
+func TestTelegramMarkup_HTML(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"a < b & c", "a &lt; b &amp; c"},
+		{"**bold** *it* ~~s~~", "<b>bold</b> <i>it</i> <s>s</s>"},
+		{"[x & y](https://example.com/?a=1&b=2)", `<a href="https://example.com/?a=1&amp;b=2">x &amp; y</a>`},
+		{"`<tag>`", "<code>&lt;tag&gt;</code>"},
+		{"```\n<b>\n```", "<pre>&lt;b&gt;\n</pre>"},
+		{"```go\nx := 1\n```", `<pre><code class="language-go">x := 1` + "\n" + `</code></pre>`},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, telegramHTMLMarkup.render(tt.in), tt.in)
+	}
+}
+
+func TestTelegram_Post_UnsupportedVisibility(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Post(context.Background(), &Post{Content: "secret", Visibility: VisibilityLevelPrivate})
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "not implemented")
+	assert.Empty(t, server.sentRequests())
 }
 
 func TestInitSocialPlatforms_Telegram(t *testing.T) {
@@ -635,7 +1153,7 @@ func TestInitSocialPlatforms_Telegram(t *testing.T) {
 		},
 	}
 
-	platforms, err := InitSocialPlatforms(configs, nil, nil, nil, "")
+	platforms, err := InitSocialPlatforms(configs, nil, nil, nil, "", nil)
 	require.NoError(t, err)
 	require.Len(t, platforms, 1)
 
@@ -662,7 +1180,7 @@ func TestInitSocialPlatforms_Telegram_DefaultSyncDelay(t *testing.T) {
 		},
 	}
 
-	platforms, err := InitSocialPlatforms(configs, nil, nil, nil, "")
+	platforms, err := InitSocialPlatforms(configs, nil, nil, nil, "", nil)
 	require.NoError(t, err)
 	require.Len(t, platforms, 1)
 
@@ -686,7 +1204,7 @@ func TestInitSocialPlatforms_Telegram_CustomSyncDelay(t *testing.T) {
 		},
 	}
 
-	platforms, err := InitSocialPlatforms(configs, nil, nil, nil, "")
+	platforms, err := InitSocialPlatforms(configs, nil, nil, nil, "", nil)
 	require.NoError(t, err)
 	require.Len(t, platforms, 1)
 
@@ -705,7 +1223,7 @@ func TestInitSocialPlatforms_Telegram_MissingConfig(t *testing.T) {
 		},
 	}
 
-	_, err := InitSocialPlatforms(configs, nil, nil, nil, "")
+	_, err := InitSocialPlatforms(configs, nil, nil, nil, "", nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "missing Telegram config")
 }
@@ -722,7 +1240,159 @@ func TestInitSocialPlatforms_Telegram_MissingCredentials(t *testing.T) {
 		},
 	}
 
-	_, err := InitSocialPlatforms(configs, nil, nil, nil, "")
+	_, err := InitSocialPlatforms(configs, nil, nil, nil, "", nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "missing Telegram credentials")
 }
+
+func TestInitSocialPlatforms_Telegram_InvalidParseMode(t *testing.T) {
+	configs := map[string]*PlatformConfig{
+		"bad-tg": {
+			Type:    "telegram",
+			Enabled: true,
+			Telegram: &TelegramConfig{
+				BotToken:  "123:ABC",
+				ChannelID: "-1001234567890",
+				ParseMode: "Markdown",
+			},
+		},
+	}
+
+	_, err := InitSocialPlatforms(configs, nil, nil, nil, "", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid Telegram parse_mode")
+}
+
+func TestTelegram_ClearQueue(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	var batch []map[string]any
+	for i := 0; i < 3; i++ {
+		batch = append(batch, map[string]any{
+			"update_id": 100 + i,
+			"channel_post": map[string]any{
+				"message_id": 10 + i,
+				"date":       time.Now().Unix(),
+				"text":       "pending",
+				"chat":       map[string]any{"id": -100, "type": "channel"},
+			},
+		})
+	}
+	server.pushBatch(batch)
+
+	client, err := NewTelegramClient("test-token", "-100", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.Eventually(t, func() bool {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return len(client.buffer) == 3
+	}, 3*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 3, client.ClearQueue())
+	assertNoMorePosts(t, client, 50*time.Millisecond)
+
+	// Polling keeps running after the queue is cleared.
+	server.pushBatch([]map[string]any{{
+		"update_id": 200,
+		"channel_post": map[string]any{
+			"message_id": 20,
+			"date":       time.Now().Unix(),
+			"text":       "after clear",
+			"chat":       map[string]any{"id": -100, "type": "channel"},
+		},
+	}})
+	posts := waitForPosts(t, client, 1, 3*time.Second)
+	require.Len(t, posts, 1)
+	assert.Equal(t, "after clear", posts[0].Content)
+}
+
+func TestTelegram_WebhookUpdate(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramWebhookClient("test-token", "-100", "tg", server.URL, "hook-secret", nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, "hook-secret", client.WebhookSecret())
+
+	update := `{"update_id":900,"channel_post":{"message_id":91,"date":1000,"text":"via webhook","chat":{"id":-100,"type":"channel","username":"mychannel"}}}`
+	require.NoError(t, client.HandleWebhookUpdate(context.Background(), []byte(update)))
+
+	posts, err := client.ListPosts(context.Background(), 0)
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.Equal(t, "91", posts[0].ID)
+	assert.Equal(t, "via webhook", posts[0].Content)
+	assert.Equal(t, "tg", posts[0].SourcePlatform)
+	assert.Equal(t, "https://t.me/mychannel/91", posts[0].SourceURL)
+	assert.Equal(t, time.Unix(1000, 0).UTC(), posts[0].CreatedAt)
+
+	assert.Error(t, client.HandleWebhookUpdate(context.Background(), []byte(`{"update_id":`)))
+	assert.Empty(t, server.offsets(), "a webhook client does not poll getUpdates")
+}
+
+func TestTelegram_SetWebhook(t *testing.T) {
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramWebhookClient("test-token", "-100", "tg", server.URL, "hook-secret", nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	require.NoError(t, client.SetWebhook(context.Background(), "https://sync.example.com/api/webhooks/telegram"))
+	require.NoError(t, client.DeleteWebhook(context.Background()))
+
+	sent := server.sentRequests()
+	require.Len(t, sent, 2)
+	assert.Equal(t, "setWebhook", sent[0].method)
+	assert.Equal(t, "https://sync.example.com/api/webhooks/telegram", sent[0].fields["url"])
+	assert.Equal(t, "hook-secret", sent[0].fields["secret_token"])
+	assert.Contains(t, sent[0].fields["allowed_updates"], "channel_post")
+	assert.Equal(t, "deleteWebhook", sent[1].method)
+}
+
+func TestInitSocialPlatforms_Telegram_WebhookWithoutSecret(t *testing.T) {
+	configs := map[string]*PlatformConfig{
+		"tg": {
+			Type:    "telegram",
+			Enabled: true,
+			Telegram: &TelegramConfig{
+				BotToken:   "test-token",
+				ChannelID:  "-100",
+				WebhookURL: "https://sync.example.com/api/webhooks/telegram",
+			},
+		},
+	}
+
+	_, err := InitSocialPlatforms(configs, nil, nil, nil, "", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook_secret")
+}
+
+func TestInitSocialPlatforms_Telegram_ValidatesBeforeCreatingClient(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	httpClients := NewHTTPClientFactoryWithTransport(time.Second, roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+		return nil, assert.AnError
+	}))
+	configs := map[string]*PlatformConfig{
+		"tg": {
+			Type:     "telegram",
+			Enabled:  true,
+			Overflow: "bogus",
+			Telegram: &TelegramConfig{
+				BotToken:      "test-token",
+				ChannelID:     "-100",
+				WebhookURL:    "https://sync.example.com/api/webhooks/telegram",
+				WebhookSecret: "hook-secret",
+			},
+		},
+	}
+
+	_, err := InitSocialPlatforms(configs, nil, nil, nil, "", httpClients)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid overflow")
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Empty(t, requests, "no client is created for an invalid config")
+}
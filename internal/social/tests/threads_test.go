@@ -69,6 +69,7 @@ func TestTokenResponse_ShouldRefreshToken(t *testing.T) {
 	tests := []struct {
 		name      string
 		expiresIn int64
+		threshold time.Duration
 		expected  bool
 	}{
 		{
@@ -91,6 +92,18 @@ func TestTokenResponse_ShouldRefreshToken(t *testing.T) {
 			expiresIn: 30 * 24 * 3600, // 30 days
 			expected:  false,
 		},
+		{
+			name:      "1 day threshold - token expires in 12 hours - should refresh",
+			expiresIn: 12 * 3600,
+			threshold: 24 * time.Hour,
+			expected:  true,
+		},
+		{
+			name:      "1 day threshold - token expires in 6 days - should not refresh yet",
+			expiresIn: 6 * 24 * 3600,
+			threshold: 24 * time.Hour,
+			expected:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -101,7 +114,7 @@ func TestTokenResponse_ShouldRefreshToken(t *testing.T) {
 				ExpiresIn:   tt.expiresIn,
 			}
 
-			result := tokenResp.ShouldRefreshToken()
+			result := tokenResp.ShouldRefreshToken(tt.threshold)
 			if result != tt.expected {
 				t.Errorf("ShouldRefreshToken() = %v, expected %v", result, tt.expected)
 			}
@@ -2,29 +2,85 @@ package social
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"butterfly.orx.me/core/log"
 )
 
-// ThreadsConfig represents Threads configuration
+// threadsGraphURL is the Threads Graph API base; tests point it at a fake server.
+var threadsGraphURL = "https://graph.threads.net/v1.0"
+
+// Threads 的 token 刷新接口偶尔返回 5xx，刷新失败时先原地重试几次
+const (
+	threadsRefreshAttempts   = 3
+	threadsRefreshRetryDelay = 2 * time.Second
+	// threadsRefreshRetryIn 重试用尽但 token 仍未过期时，多久后再试一次刷新
+	threadsRefreshRetryIn = 5 * time.Minute
+)
+
+// DefaultTokenRefreshThreshold 默认在 token 过期前 7 天内刷新，适合 Threads 的 60 天长期 token
+const DefaultTokenRefreshThreshold = 7 * 24 * time.Hour
+
+// client returns the HTTP client for Graph API calls. Every call is wrapped
+// in a client span; a client built without NewThreadsClientWithDao uses the
+// default timeout and transport.
+func (c *ThreadsClient) client() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return defaultHTTPClients.TracedClient(PlatformThreads.String())
+}
 
+// get sends a GET to the Graph API with ctx.
+func (c *ThreadsClient) get(ctx context.Context, requestURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.client().Do(req)
+}
+
+// postForm posts params form-encoded to the Graph API with ctx.
+func (c *ThreadsClient) postForm(ctx context.Context, requestURL string, params url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return c.client().Do(req)
+}
+
+// ThreadsConfig represents Threads configuration
 type ThreadsClient struct {
 	name         string
 	ClientID     string
 	ClientSecret string
 	UserID       int64
 	tokenManager TokenManager
+	// httpClient 发送所有 Graph API 请求，带超时、代理设置与 tracing
+	httpClient *http.Client
+
+	// refreshAttempts / refreshRetryDelay 为 0 时使用 threadsRefreshAttempts / threadsRefreshRetryDelay
+	refreshAttempts   int
+	refreshRetryDelay time.Duration
+	// refreshThreshold token 剩余有效期不超过该值时刷新，0 使用 DefaultTokenRefreshThreshold
+	refreshThreshold time.Duration
 
 	mu          sync.RWMutex
 	accessToken string
+	// refreshRetryAt 上次刷新失败但 token 仍可用时，下一次刷新的时间
+	refreshRetryAt time.Time
+
+	// lengthPolicy 决定超出字数上限的正文截断还是拆成串，见 SetLengthPolicy
+	lengthPolicy
 }
 
 // getAccessToken 以并发安全的方式读取 access token
@@ -46,6 +102,28 @@ func (c *ThreadsClient) SetTokenManager(manager TokenManager) {
 	c.tokenManager = manager
 }
 
+// SetRefreshThreshold sets how close to expiry EnsureValidToken refreshes
+// the token. Zero or negative restores DefaultTokenRefreshThreshold.
+func (c *ThreadsClient) SetRefreshThreshold(threshold time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshThreshold = max(threshold, 0)
+}
+
+// RefreshThreshold returns the effective refresh threshold.
+func (c *ThreadsClient) RefreshThreshold() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return refreshThresholdOrDefault(c.refreshThreshold)
+}
+
+func refreshThresholdOrDefault(threshold time.Duration) time.Duration {
+	if threshold <= 0 {
+		return DefaultTokenRefreshThreshold
+	}
+	return threshold
+}
+
 // TokenResponse 表示 Threads API token 响应
 type TokenResponse struct {
 	AccessToken string `json:"access_token"`
@@ -56,8 +134,9 @@ type TokenResponse struct {
 // NewThreadsClientWithDao creates a new ThreadsClient with dao support
 // 当 dao 里不存在 access token 时，将传入的 accessToken 写入 dao
 // 当 dao 里有 access token 时，使用 dao 里的，方便第一次初始化
+// httpClients 为 nil 时使用默认的超时与 transport
 func NewThreadsClientWithDao(name string,
-	clientID, clientSecret, accessToken string, userID int64, tokenManager TokenManager) (*ThreadsClient, error) {
+	clientID, clientSecret, accessToken string, userID int64, tokenManager TokenManager, httpClients *HTTPClientFactory) (*ThreadsClient, error) {
 
 	logger := log.FromContext(context.Background())
 
@@ -70,6 +149,7 @@ func NewThreadsClientWithDao(name string,
 		ClientSecret: clientSecret,
 		UserID:       userID,
 		tokenManager: tokenManager,
+		httpClient:   httpClientsOrDefault(httpClients).TracedClient(PlatformThreads.String()),
 	}
 
 	ctx := context.Background()
@@ -104,6 +184,82 @@ func NewThreadsClientWithDao(name string,
 	return client, nil
 }
 
+// TokenExpiresAt 返回数据库中长期 token 的过期时间；没有记录过期时间时 ok 为 false
+func (c *ThreadsClient) TokenExpiresAt(ctx context.Context) (time.Time, bool, error) {
+	if c.tokenManager == nil {
+		return time.Time{}, false, fmt.Errorf("token manager is not set")
+	}
+	tokenInfo, err := c.tokenManager.GetTokenInfo(ctx, c.name)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get token info: %w", err)
+	}
+	if tokenInfo == nil || tokenInfo.ExpiresAt == nil {
+		return time.Time{}, false, nil
+	}
+	return *tokenInfo.ExpiresAt, true, nil
+}
+
+// RefreshRetryIn implements TokenRefreshRetrier: after a failed refresh of a
+// token that is still valid, it reports how soon to try again.
+func (c *ThreadsClient) RefreshRetryIn() (time.Duration, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.refreshRetryAt.IsZero() {
+		return 0, false
+	}
+	return max(time.Until(c.refreshRetryAt), 0), true
+}
+
+func (c *ThreadsClient) setRefreshRetryAt(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshRetryAt = t
+}
+
+// refreshTokenWithRetry calls RefreshLongLivedToken up to refreshAttempts
+// times, doubling the wait after each transient failure. Errors that won't
+// go away on their own, such as a revoked token, are returned at once.
+func (c *ThreadsClient) refreshTokenWithRetry(ctx context.Context) (*TokenResponse, error) {
+	attempts, delay := c.refreshAttempts, c.refreshRetryDelay
+	if attempts < 1 {
+		attempts = threadsRefreshAttempts
+	}
+	if delay <= 0 {
+		delay = threadsRefreshRetryDelay
+	}
+
+	for attempt := 1; ; attempt++ {
+		tokenResp, err := c.RefreshLongLivedToken(ctx)
+		if err == nil || attempt >= attempts || !isTransientRefreshError(err) {
+			return tokenResp, err
+		}
+		log.FromContext(ctx).Warn("token refresh failed, retrying",
+			"client", c.name,
+			"attempt", attempt,
+			"retry_in", delay,
+			"error", err)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// isTransientRefreshError reports whether a failed token refresh may succeed
+// when sent again: a 5xx or rate limit from Threads, or a network error.
+func isTransientRefreshError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if platformErr, ok := AsPlatformError(err); ok {
+		return platformErr.Retryable || platformErr.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
 // EnsureValidToken 确保 token 有效，如果快过期则自动刷新
 func (c *ThreadsClient) EnsureValidToken(ctx context.Context) error {
 	logger := log.FromContext(ctx).With("method", "ThreadsClient.EnsureValidToken")
@@ -135,7 +291,7 @@ func (c *ThreadsClient) EnsureValidToken(ctx context.Context) error {
 	if tokenInfo.ExpiresAt == nil {
 		logger.Info("no expiration time found for token, forcing refresh to obtain expiry information", "client", c.name)
 
-		tokenResp, err := c.RefreshLongLivedToken()
+		tokenResp, err := c.refreshTokenWithRetry(ctx)
 		if err != nil {
 			logger.Error("forced token refresh failed", "client", c.name, "error", err)
 			return fmt.Errorf("forced token refresh failed: %w", err)
@@ -148,6 +304,7 @@ func (c *ThreadsClient) EnsureValidToken(ctx context.Context) error {
 			return fmt.Errorf("failed to save force-refreshed token: %w", err)
 		}
 
+		c.setRefreshRetryAt(time.Time{})
 		logger.Info("token successfully force-refreshed with expiry information",
 			"client", c.name,
 			"new_expiry", tokenResp.GetTokenExpirationTime().Format(time.RFC3339))
@@ -155,8 +312,8 @@ func (c *ThreadsClient) EnsureValidToken(ctx context.Context) error {
 		return nil
 	}
 
-	// 检查 token 是否在 7 天内过期
-	const refreshThreshold = 7 * 24 * time.Hour
+	// 检查 token 是否在刷新阈值（默认 7 天）内过期
+	refreshThreshold := c.RefreshThreshold()
 	timeUntilExpiry := time.Until(*tokenInfo.ExpiresAt)
 
 	if timeUntilExpiry <= refreshThreshold {
@@ -166,16 +323,20 @@ func (c *ThreadsClient) EnsureValidToken(ctx context.Context) error {
 			"time_until_expiry", timeUntilExpiry,
 			"expires_at", tokenInfo.ExpiresAt.Format(time.RFC3339))
 
-		tokenResp, err := c.RefreshLongLivedToken()
+		tokenResp, err := c.refreshTokenWithRetry(ctx)
 		if err != nil {
-			// 刷新失败，但如果 token 还没完全过期，仍可使用
+			// 重试用尽，但如果 token 还没完全过期，仍可使用，并提前安排下一次刷新
 			if timeUntilExpiry > 0 {
+				retryIn := min(threadsRefreshRetryIn, timeUntilExpiry)
+				c.setRefreshRetryAt(time.Now().Add(retryIn))
 				logger.Warn("token refresh failed but token is still valid",
 					"client", c.name,
 					"time_until_expiry", timeUntilExpiry,
+					"retry_in", retryIn,
 					"error", err)
 				return nil
 			}
+			c.setRefreshRetryAt(time.Time{})
 			logger.Error("token expired and refresh failed", "client", c.name, "error", err)
 			return fmt.Errorf("token expired and refresh failed: %w", err)
 		}
@@ -187,6 +348,7 @@ func (c *ThreadsClient) EnsureValidToken(ctx context.Context) error {
 			return fmt.Errorf("failed to save refreshed token: %w", err)
 		}
 
+		c.setRefreshRetryAt(time.Time{})
 		logger.Info("token successfully refreshed",
 			"client", c.name,
 			"new_expiry", tokenResp.GetTokenExpirationTime().Format(time.RFC3339))
@@ -240,8 +402,8 @@ func (c *ThreadsClient) SaveTokenToDao(ctx context.Context, tokenResp *TokenResp
 
 // ExchangeForLongLivedToken 将短期访问令牌交换为长期访问令牌
 // 长期令牌有效期为60天，可以刷新
-func (c *ThreadsClient) ExchangeForLongLivedToken(shortLivedToken string) (*TokenResponse, error) {
-	logger := log.FromContext(context.Background())
+func (c *ThreadsClient) ExchangeForLongLivedToken(ctx context.Context, shortLivedToken string) (*TokenResponse, error) {
+	logger := log.FromContext(ctx)
 
 	if c.ClientSecret == "" {
 		logger.Error("client secret is required for token exchange", "client", c.name)
@@ -251,7 +413,7 @@ func (c *ThreadsClient) ExchangeForLongLivedToken(shortLivedToken string) (*Toke
 	logger.Info("exchanging short-lived token for long-lived token", "client", c.name)
 
 	// 构建请求URL
-	baseURL := "https://graph.threads.net/v1.0/access_token"
+	baseURL := threadsGraphURL + "/access_token"
 	params := url.Values{}
 	params.Add("grant_type", "th_exchange_token")
 	params.Add("client_secret", c.ClientSecret)
@@ -260,7 +422,7 @@ func (c *ThreadsClient) ExchangeForLongLivedToken(shortLivedToken string) (*Toke
 	requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
 	// 发送GET请求
-	resp, err := http.Get(requestURL)
+	resp, err := c.get(ctx, requestURL)
 	if err != nil {
 		logger.Error("failed to send token exchange request", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to exchange token: %w", err)
@@ -268,7 +430,7 @@ func (c *ThreadsClient) ExchangeForLongLivedToken(shortLivedToken string) (*Toke
 	defer resp.Body.Close()
 
 	// 读取响应
-	body, err := io.ReadAll(resp.Body)
+	body, err := readResponseBody(resp.Body)
 	if err != nil {
 		logger.Error("failed to read token exchange response body", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
@@ -280,12 +442,12 @@ func (c *ThreadsClient) ExchangeForLongLivedToken(shortLivedToken string) (*Toke
 			"client", c.name,
 			"status_code", resp.StatusCode,
 			"response", string(body))
-		return nil, fmt.Errorf("token exchange failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newPlatformError(c.name, "token exchange", resp, body)
 	}
 
 	// 解析JSON响应
 	var tokenResp TokenResponse
-	if err := json.Unmarshal(body, &tokenResp); err != nil {
+	if err := DecodeJSON(body, &tokenResp); err != nil {
 		logger.Error("failed to parse token exchange response", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to parse token response: %w", err)
 	}
@@ -303,8 +465,8 @@ func (c *ThreadsClient) ExchangeForLongLivedToken(shortLivedToken string) (*Toke
 // RefreshLongLivedToken 刷新长期访问令牌
 // 长期令牌必须至少24小时旧但尚未过期才能刷新
 // 刷新后的令牌有效期为60天
-func (c *ThreadsClient) RefreshLongLivedToken() (*TokenResponse, error) {
-	logger := log.FromContext(context.Background())
+func (c *ThreadsClient) RefreshLongLivedToken(ctx context.Context) (*TokenResponse, error) {
+	logger := log.FromContext(ctx)
 
 	currentToken := c.getAccessToken()
 	if currentToken == "" {
@@ -315,7 +477,7 @@ func (c *ThreadsClient) RefreshLongLivedToken() (*TokenResponse, error) {
 	logger.Info("refreshing long-lived token", "client", c.name)
 
 	// 构建请求URL
-	baseURL := "https://graph.threads.net/v1.0/refresh_access_token"
+	baseURL := threadsGraphURL + "/refresh_access_token"
 	params := url.Values{}
 	params.Add("grant_type", "th_refresh_token")
 	params.Add("access_token", currentToken)
@@ -323,7 +485,7 @@ func (c *ThreadsClient) RefreshLongLivedToken() (*TokenResponse, error) {
 	requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
 	// 发送GET请求
-	resp, err := http.Get(requestURL)
+	resp, err := c.get(ctx, requestURL)
 	if err != nil {
 		logger.Error("failed to send token refresh request", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to refresh token: %w", err)
@@ -331,7 +493,7 @@ func (c *ThreadsClient) RefreshLongLivedToken() (*TokenResponse, error) {
 	defer resp.Body.Close()
 
 	// 读取响应
-	body, err := io.ReadAll(resp.Body)
+	body, err := readResponseBody(resp.Body)
 	if err != nil {
 		logger.Error("failed to read token refresh response body", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
@@ -343,12 +505,12 @@ func (c *ThreadsClient) RefreshLongLivedToken() (*TokenResponse, error) {
 			"client", c.name,
 			"status_code", resp.StatusCode,
 			"response", string(body))
-		return nil, fmt.Errorf("token refresh failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newPlatformError(c.name, "token refresh", resp, body)
 	}
 
 	// 解析JSON响应
 	var tokenResp TokenResponse
-	if err := json.Unmarshal(body, &tokenResp); err != nil {
+	if err := DecodeJSON(body, &tokenResp); err != nil {
 		logger.Error("failed to parse token refresh response", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to parse token response: %w", err)
 	}
@@ -369,17 +531,17 @@ func (tr *TokenResponse) GetTokenExpirationTime() time.Time {
 }
 
 // IsTokenExpiringSoon 检查令牌是否在指定时间内过期
-// 建议在令牌过期前7天开始尝试刷新
+// threshold 为 0 时使用 DefaultTokenRefreshThreshold（过期前 7 天）
 func (tr *TokenResponse) IsTokenExpiringSoon(threshold time.Duration) bool {
 	expirationTime := tr.GetTokenExpirationTime()
-	return time.Until(expirationTime) <= threshold
+	return time.Until(expirationTime) <= refreshThresholdOrDefault(threshold)
 }
 
 // ShouldRefreshToken 检查是否应该刷新令牌
-// 长期令牌必须至少24小时旧才能刷新，建议在过期前7天刷新
-func (tr *TokenResponse) ShouldRefreshToken() bool {
-	// 检查是否在7天内过期
-	return tr.IsTokenExpiringSoon(7 * 24 * time.Hour)
+// 长期令牌必须至少24小时旧才能刷新；threshold 通常取 ThreadsClient.RefreshThreshold()，
+// 为 0 时在过期前 7 天刷新
+func (tr *TokenResponse) ShouldRefreshToken(threshold time.Duration) bool {
+	return tr.IsTokenExpiringSoon(threshold)
 }
 
 // PostRequest represents a post creation request
@@ -391,6 +553,8 @@ type PostRequest struct {
 	LinkAttachment string   `json:"link_attachment,omitempty"`  // For text posts only
 	IsCarouselItem bool     `json:"is_carousel_item,omitempty"` // For carousel items
 	Children       []string `json:"children,omitempty"`         // For carousel containers
+	AltText        string   `json:"alt_text,omitempty"`         // For images and videos
+	ReplyToID      string   `json:"reply_to_id,omitempty"`      // Media ID of the post this one replies to
 }
 
 // MediaContainerResponse represents the response when creating a media container
@@ -432,7 +596,7 @@ func (c *ThreadsClient) CreateMediaContainer(ctx context.Context, userID string,
 		"is_carousel_item", req.IsCarouselItem)
 
 	// 构建请求URL
-	baseURL := fmt.Sprintf("https://graph.threads.net/v1.0/%s/threads", userID)
+	baseURL := fmt.Sprintf("%s/%s/threads", threadsGraphURL, userID)
 
 	// 构建请求参数
 	params := url.Values{}
@@ -455,6 +619,14 @@ func (c *ThreadsClient) CreateMediaContainer(ctx context.Context, userID string,
 		params.Add("link_attachment", req.LinkAttachment)
 	}
 
+	if req.AltText != "" {
+		params.Add("alt_text", req.AltText)
+	}
+
+	if req.ReplyToID != "" {
+		params.Add("reply_to_id", req.ReplyToID)
+	}
+
 	if req.IsCarouselItem {
 		params.Add("is_carousel_item", "true")
 	}
@@ -475,7 +647,7 @@ func (c *ThreadsClient) CreateMediaContainer(ctx context.Context, userID string,
 	}
 
 	// 发送POST请求
-	resp, err := http.PostForm(baseURL, params)
+	resp, err := c.postForm(ctx, baseURL, params)
 	if err != nil {
 		logger.Error("failed to send create media container request", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to create media container: %w", err)
@@ -483,7 +655,7 @@ func (c *ThreadsClient) CreateMediaContainer(ctx context.Context, userID string,
 	defer resp.Body.Close()
 
 	// 读取响应
-	body, err := io.ReadAll(resp.Body)
+	body, err := readResponseBody(resp.Body)
 	if err != nil {
 		logger.Error("failed to read create media container response", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
@@ -495,12 +667,12 @@ func (c *ThreadsClient) CreateMediaContainer(ctx context.Context, userID string,
 			"client", c.name,
 			"status_code", resp.StatusCode,
 			"response", string(body))
-		return nil, fmt.Errorf("create media container failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newPlatformError(c.name, "create media container", resp, body)
 	}
 
 	// 解析JSON响应
 	var containerResp MediaContainerResponse
-	if err := json.Unmarshal(body, &containerResp); err != nil {
+	if err := DecodeJSON(body, &containerResp); err != nil {
 		logger.Error("failed to parse media container response", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to parse container response: %w", err)
 	}
@@ -537,7 +709,7 @@ func (c *ThreadsClient) PublishMediaContainer(ctx context.Context, userID, conta
 		"container_id", containerID)
 
 	// 构建请求URL
-	baseURL := fmt.Sprintf("https://graph.threads.net/v1.0/%s/threads_publish", userID)
+	baseURL := fmt.Sprintf("%s/%s/threads_publish", threadsGraphURL, userID)
 
 	// 构建请求参数
 	params := url.Values{}
@@ -545,7 +717,7 @@ func (c *ThreadsClient) PublishMediaContainer(ctx context.Context, userID, conta
 	params.Add("creation_id", containerID)
 
 	// 发送POST请求
-	resp, err := http.PostForm(baseURL, params)
+	resp, err := c.postForm(ctx, baseURL, params)
 	if err != nil {
 		logger.Error("failed to send publish media container request", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to publish media container: %w", err)
@@ -553,7 +725,7 @@ func (c *ThreadsClient) PublishMediaContainer(ctx context.Context, userID, conta
 	defer resp.Body.Close()
 
 	// 读取响应
-	body, err := io.ReadAll(resp.Body)
+	body, err := readResponseBody(resp.Body)
 	if err != nil {
 		logger.Error("failed to read publish response", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to read response body: %w", err)
@@ -565,12 +737,12 @@ func (c *ThreadsClient) PublishMediaContainer(ctx context.Context, userID, conta
 			"client", c.name,
 			"status_code", resp.StatusCode,
 			"response", string(body))
-		return nil, fmt.Errorf("publish media container failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, newPlatformError(c.name, "publish media container", resp, body)
 	}
 
 	// 解析JSON响应
 	var publishResp PublishResponse
-	if err := json.Unmarshal(body, &publishResp); err != nil {
+	if err := DecodeJSON(body, &publishResp); err != nil {
 		logger.Error("failed to parse publish response", "client", c.name, "error", err)
 		return nil, fmt.Errorf("failed to parse publish response: %w", err)
 	}
@@ -612,8 +784,21 @@ func (c *ThreadsClient) PostText(ctx context.Context, userID, text string, linkA
 	return c.PublishMediaContainer(ctx, userID, container.ID)
 }
 
+// PostReply creates and publishes a text post as a reply to replyToID
+func (c *ThreadsClient) PostReply(ctx context.Context, userID, replyToID, text string) (*PublishResponse, error) {
+	container, err := c.CreateMediaContainer(ctx, userID, &PostRequest{
+		MediaType: "TEXT",
+		Text:      text,
+		ReplyToID: replyToID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reply container: %w", err)
+	}
+	return c.PublishMediaContainer(ctx, userID, container.ID)
+}
+
 // PostImage creates and publishes an image post
-func (c *ThreadsClient) PostImage(ctx context.Context, userID, imageURL, text string) (*PublishResponse, error) {
+func (c *ThreadsClient) PostImage(ctx context.Context, userID, imageURL, text, altText string) (*PublishResponse, error) {
 	logger := log.FromContext(ctx)
 
 	logger.Debug("starting image post",
@@ -626,6 +811,7 @@ func (c *ThreadsClient) PostImage(ctx context.Context, userID, imageURL, text st
 		MediaType: "IMAGE",
 		ImageURL:  imageURL,
 		Text:      text,
+		AltText:   altText,
 	}
 
 	// Step 1: Create media container
@@ -640,7 +826,7 @@ func (c *ThreadsClient) PostImage(ctx context.Context, userID, imageURL, text st
 }
 
 // PostVideo creates and publishes a video post
-func (c *ThreadsClient) PostVideo(ctx context.Context, userID, videoURL, text string) (*PublishResponse, error) {
+func (c *ThreadsClient) PostVideo(ctx context.Context, userID, videoURL, text, altText string) (*PublishResponse, error) {
 	logger := log.FromContext(ctx)
 
 	logger.Debug("starting video post",
@@ -653,6 +839,7 @@ func (c *ThreadsClient) PostVideo(ctx context.Context, userID, videoURL, text st
 		MediaType: "VIDEO",
 		VideoURL:  videoURL,
 		Text:      text,
+		AltText:   altText,
 	}
 
 	// Step 1: Create media container
@@ -671,6 +858,7 @@ type CarouselItem struct {
 	MediaType string `json:"media_type"` // IMAGE or VIDEO
 	ImageURL  string `json:"image_url,omitempty"`
 	VideoURL  string `json:"video_url,omitempty"`
+	AltText   string `json:"alt_text,omitempty"`
 }
 
 // PostCarousel creates and publishes a carousel post
@@ -705,6 +893,7 @@ func (c *ThreadsClient) PostCarousel(ctx context.Context, userID string, items [
 			MediaType:      item.MediaType,
 			ImageURL:       item.ImageURL,
 			VideoURL:       item.VideoURL,
+			AltText:        item.AltText,
 			IsCarouselItem: true,
 		}
 
@@ -750,16 +939,24 @@ func (c *ThreadsClient) Name() string {
 	return c.name
 }
 
-// Post implements the SocialClient interface for posting content
+// Post implements the SocialClient interface for posting content. Content
+// over the limit is truncated or continued in replies, depending on the
+// length policy.
 func (c *ThreadsClient) Post(ctx context.Context, post *Post) (interface{}, error) {
+	return c.postWithinLimit(ctx, PlatformThreads, post, c.publishPost)
+}
+
+// publishPost publishes post as a single Threads post.
+func (c *ThreadsClient) publishPost(ctx context.Context, post *Post) (interface{}, error) {
 	logger := log.FromContext(ctx)
 	userID := strconv.FormatInt(c.UserID, 10)
 
 	// Check if visibility level is supported for Threads
-	if post.Visibility.IsValid() {
-		if !IsVisibilityLevelSupported(PlatformThreads.String(), post.Visibility) {
-			return nil, fmt.Errorf("visibility %s is not supported by platform %s", post.Visibility.String(), PlatformThreads.String())
-		}
+	if err := checkVisibility(PlatformThreads, post.Visibility); err != nil {
+		return nil, err
+	}
+	if err := ValidateMedia(PlatformThreads.String(), post.Media); err != nil {
+		return nil, err
 	}
 
 	// Determine post type based on media content
@@ -775,7 +972,13 @@ func (c *ThreadsClient) Post(ctx context.Context, post *Post) (interface{}, erro
 	case mediaCount == 0:
 		// Text-only post
 		logger.Debug("posting text-only content", "client", c.name)
-		result, err := c.PostText(ctx, userID, post.Content)
+		var result *PublishResponse
+		var err error
+		if post.InReplyTo != "" {
+			result, err = c.PostReply(ctx, userID, post.InReplyTo, post.Content)
+		} else {
+			result, err = c.PostText(ctx, userID, post.Content)
+		}
 		if err != nil {
 			logger.Error("failed to post text content", "client", c.name, "error", err)
 			return nil, err
@@ -785,7 +988,7 @@ func (c *ThreadsClient) Post(ctx context.Context, post *Post) (interface{}, erro
 
 	case mediaCount == 1:
 		// Single media post
-		media := post.Media[0]
+		media := &post.Media[0]
 		mediaURL := media.GetURL()
 
 		if mediaURL == "" {
@@ -797,8 +1000,17 @@ func (c *ThreadsClient) Post(ctx context.Context, post *Post) (interface{}, erro
 			"client", c.name,
 			"media_url", mediaURL)
 
-		// For now, assume it's an image. TODO: Add media type detection based on URL or content type
-		result, err := c.PostImage(ctx, userID, mediaURL, post.Content)
+		if media.IsVideo() {
+			result, err := c.PostVideo(ctx, userID, mediaURL, post.Content, media.Description)
+			if err != nil {
+				logger.Error("failed to post video content", "client", c.name, "error", err)
+				return nil, err
+			}
+			logger.Info("video post successful", "client", c.name, "post_id", result.ID)
+			return result, nil
+		}
+
+		result, err := c.PostImage(ctx, userID, mediaURL, post.Content, media.Description)
 		if err != nil {
 			logger.Error("failed to post image content", "client", c.name, "error", err)
 			return nil, err
@@ -807,21 +1019,14 @@ func (c *ThreadsClient) Post(ctx context.Context, post *Post) (interface{}, erro
 		return result, nil
 
 	case mediaCount > 1:
-		// Carousel post
-		if mediaCount > 20 {
-			logger.Error("too many media items for carousel",
-				"client", c.name,
-				"media_count", mediaCount,
-				"max_allowed", 20)
-			return nil, fmt.Errorf("too many media items for carousel: %d (max 20)", mediaCount)
-		}
-
+		// Carousel post（最多 20 项，已由 ValidateMedia 检查）
 		logger.Debug("posting carousel content",
 			"client", c.name,
 			"media_count", mediaCount)
 
 		var carouselItems []CarouselItem
-		for i, media := range post.Media {
+		for i := range post.Media {
+			media := &post.Media[i]
 			mediaURL := media.GetURL()
 			if mediaURL == "" {
 				logger.Error("media URL is required for carousel items",
@@ -830,10 +1035,18 @@ func (c *ThreadsClient) Post(ctx context.Context, post *Post) (interface{}, erro
 				return nil, fmt.Errorf("media URL is required for carousel items")
 			}
 
-			// For now, assume all are images. TODO: Add proper media type detection
+			if media.IsVideo() {
+				carouselItems = append(carouselItems, CarouselItem{
+					MediaType: "VIDEO",
+					VideoURL:  mediaURL,
+					AltText:   media.Description,
+				})
+				continue
+			}
 			carouselItems = append(carouselItems, CarouselItem{
 				MediaType: "IMAGE",
 				ImageURL:  mediaURL,
+				AltText:   media.Description,
 			})
 		}
 
@@ -851,6 +1064,46 @@ func (c *ThreadsClient) Post(ctx context.Context, post *Post) (interface{}, erro
 	}
 }
 
+// Permalink 查询已发布帖子的公开链接。Threads 链接使用短码而非媒体 ID，
+// 只能通过 Graph API 的 permalink 字段获取，无法本地拼接。
+func (c *ThreadsClient) Permalink(ctx context.Context, mediaID string) (string, error) {
+	currentToken := c.getAccessToken()
+	if currentToken == "" {
+		return "", fmt.Errorf("access token is required")
+	}
+
+	params := url.Values{}
+	params.Add("fields", "permalink")
+	params.Add("access_token", currentToken)
+	reqURL := fmt.Sprintf("%s/%s?%s", threadsGraphURL, url.PathEscape(mediaID), params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create permalink request: %w", err)
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get permalink: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := readResponseBody(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", newPlatformError(c.name, "get permalink", resp, body)
+	}
+
+	var media struct {
+		Permalink string `json:"permalink"`
+	}
+	if err := DecodeJSON(body, &media); err != nil {
+		return "", fmt.Errorf("failed to parse permalink response: %w", err)
+	}
+	return media.Permalink, nil
+}
+
 // ListPosts implements the SocialClient interface for retrieving posts
 func (c *ThreadsClient) ListPosts(ctx context.Context, limit int) ([]*Post, error) {
 	logger := log.FromContext(ctx)
@@ -0,0 +1,296 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeThreadsGraph records container creations and answers every Graph API
+// call with an ID. It also serves PNGs under /media/ so the client can detect
+// the media type without leaving the test.
+type fakeThreadsGraph struct {
+	url string
+
+	mu         sync.Mutex
+	containers []url.Values
+}
+
+func newFakeThreadsGraph(t *testing.T) *fakeThreadsGraph {
+	t.Helper()
+	f := &fakeThreadsGraph{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/media/") {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(pngSignature)
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, "/threads") {
+			f.containers = append(f.containers, r.PostForm)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	t.Cleanup(server.Close)
+	f.url = server.URL
+
+	orig := threadsGraphURL
+	threadsGraphURL = server.URL
+	t.Cleanup(func() { threadsGraphURL = orig })
+	return f
+}
+
+func TestThreads_Post_AltText(t *testing.T) {
+	graph := newFakeThreadsGraph(t)
+	client := &ThreadsClient{name: "threads", UserID: 42, accessToken: "token"}
+
+	image := NewMediaFromURL(graph.url + "/media/a.png")
+	image.Description = "a cat"
+	_, err := client.Post(context.Background(), &Post{Content: "hi", Media: []Media{*image}})
+	require.NoError(t, err)
+
+	require.Len(t, graph.containers, 1)
+	assert.Equal(t, "IMAGE", graph.containers[0].Get("media_type"))
+	assert.Equal(t, "a cat", graph.containers[0].Get("alt_text"))
+}
+
+func TestThreads_Post_CarouselAltText(t *testing.T) {
+	graph := newFakeThreadsGraph(t)
+	client := &ThreadsClient{name: "threads", UserID: 42, accessToken: "token"}
+
+	first := NewMediaFromURL(graph.url + "/media/a.png")
+	first.Description = "first"
+	second := NewMediaFromURL(graph.url + "/media/b.png")
+	_, err := client.Post(context.Background(), &Post{Content: "hi", Media: []Media{*first, *second}})
+	require.NoError(t, err)
+
+	// two items, then the carousel container itself
+	require.Len(t, graph.containers, 3)
+	assert.Equal(t, "first", graph.containers[0].Get("alt_text"))
+	assert.False(t, graph.containers[1].Has("alt_text"))
+	assert.Equal(t, "CAROUSEL", graph.containers[2].Get("media_type"))
+}
+
+func TestThreads_RequestsHonorContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	orig := threadsGraphURL
+	threadsGraphURL = server.URL
+	defer func() { threadsGraphURL = orig }()
+
+	client := &ThreadsClient{name: "threads", UserID: 42, ClientSecret: "secret", accessToken: "token"}
+	calls := map[string]func(ctx context.Context) error{
+		"exchange": func(ctx context.Context) error {
+			_, err := client.ExchangeForLongLivedToken(ctx, "short")
+			return err
+		},
+		"refresh": func(ctx context.Context) error {
+			_, err := client.RefreshLongLivedToken(ctx)
+			return err
+		},
+		"create container": func(ctx context.Context) error {
+			_, err := client.CreateMediaContainer(ctx, "42", &PostRequest{MediaType: "TEXT", Text: "hi"})
+			return err
+		},
+		"publish container": func(ctx context.Context) error {
+			_, err := client.PublishMediaContainer(ctx, "42", "1")
+			return err
+		},
+	}
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := call(ctx)
+			require.Error(t, err)
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Less(t, time.Since(start), 2*time.Second, "a hung Threads API must not block the caller")
+		})
+	}
+}
+
+func TestThreads_TokenRequestParams(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		assert.Equal(t, http.MethodGet, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"long","token_type":"bearer","expires_in":5184000}`))
+	}))
+	defer server.Close()
+	orig := threadsGraphURL
+	threadsGraphURL = server.URL
+	defer func() { threadsGraphURL = orig }()
+
+	client := &ThreadsClient{name: "threads", ClientSecret: "secret", accessToken: "token"}
+	_, err := client.ExchangeForLongLivedToken(context.Background(), "short")
+	require.NoError(t, err)
+	_, err = client.RefreshLongLivedToken(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	assert.Equal(t, url.Values{
+		"grant_type":    {"th_exchange_token"},
+		"client_secret": {"secret"},
+		"access_token":  {"short"},
+	}, queries[0])
+	assert.Equal(t, url.Values{
+		"grant_type":   {"th_refresh_token"},
+		"access_token": {"long"},
+	}, queries[1])
+}
+
+// memoryTokenManager is an in-memory TokenManager.
+type memoryTokenManager struct {
+	mu     sync.Mutex
+	tokens map[string]*TokenInfo
+}
+
+func (m *memoryTokenManager) GetAccessToken(_ context.Context, platform string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if info := m.tokens[platform]; info != nil {
+		return info.AccessToken, nil
+	}
+	return "", nil
+}
+
+func (m *memoryTokenManager) GetTokenInfo(_ context.Context, platform string) (*TokenInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tokens[platform], nil
+}
+
+func (m *memoryTokenManager) SaveAccessToken(_ context.Context, platform, accessToken string, expiresAt *time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[platform] = &TokenInfo{AccessToken: accessToken, ExpiresAt: expiresAt}
+	return nil
+}
+
+// newThreadsRefreshServer answers token refreshes with statuses in order,
+// repeating the last one; 200 returns a new token.
+func newThreadsRefreshServer(t *testing.T, statuses ...int) *atomic.Int32 {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/refresh_access_token", r.URL.Path)
+		n := int(calls.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"error":{"message":"temporarily unavailable"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"refreshed","token_type":"bearer","expires_in":5184000}`))
+	}))
+	t.Cleanup(server.Close)
+	orig := threadsGraphURL
+	threadsGraphURL = server.URL
+	t.Cleanup(func() { threadsGraphURL = orig })
+	return &calls
+}
+
+func newRefreshingThreadsClient(expiresIn time.Duration) (*ThreadsClient, *memoryTokenManager) {
+	expiresAt := time.Now().Add(expiresIn)
+	tokens := &memoryTokenManager{tokens: map[string]*TokenInfo{
+		"threads": {AccessToken: "current", ExpiresAt: &expiresAt},
+	}}
+	client := &ThreadsClient{name: "threads", tokenManager: tokens, refreshRetryDelay: time.Millisecond}
+	return client, tokens
+}
+
+func TestThreads_EnsureValidToken_RetriesTransientFailure(t *testing.T) {
+	calls := newThreadsRefreshServer(t, http.StatusServiceUnavailable, http.StatusInternalServerError, http.StatusOK)
+	client, tokens := newRefreshingThreadsClient(3 * 24 * time.Hour)
+
+	require.NoError(t, client.EnsureValidToken(context.Background()))
+	assert.EqualValues(t, 3, calls.Load())
+	assert.Equal(t, "refreshed", client.getAccessToken())
+	assert.Equal(t, "refreshed", tokens.tokens["threads"].AccessToken)
+	_, pending := client.RefreshRetryIn()
+	assert.False(t, pending)
+}
+
+func TestThreads_EnsureValidToken_KeepsValidTokenWhenRefreshFails(t *testing.T) {
+	calls := newThreadsRefreshServer(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK)
+	client, tokens := newRefreshingThreadsClient(3 * 24 * time.Hour)
+
+	require.NoError(t, client.EnsureValidToken(context.Background()), "a still-valid token is kept")
+	assert.EqualValues(t, 3, calls.Load())
+	assert.Equal(t, "current", client.getAccessToken())
+	assert.Equal(t, "current", tokens.tokens["threads"].AccessToken)
+
+	retryIn, pending := client.RefreshRetryIn()
+	assert.True(t, pending)
+	assert.InDelta(t, threadsRefreshRetryIn, retryIn, float64(time.Second))
+
+	// 之后的重试成功，清除待重试状态
+	require.NoError(t, client.EnsureValidToken(context.Background()))
+	_, pending = client.RefreshRetryIn()
+	assert.False(t, pending)
+}
+
+func TestThreads_EnsureValidToken_ExpiredTokenFails(t *testing.T) {
+	calls := newThreadsRefreshServer(t, http.StatusBadRequest)
+	client, _ := newRefreshingThreadsClient(-time.Hour)
+
+	assert.Error(t, client.EnsureValidToken(context.Background()))
+	assert.EqualValues(t, 1, calls.Load(), "a rejected token is not retried")
+	_, pending := client.RefreshRetryIn()
+	assert.False(t, pending)
+}
+
+func TestThreads_EnsureValidToken_RefreshThreshold(t *testing.T) {
+	calls := newThreadsRefreshServer(t, http.StatusOK)
+
+	// 1 天阈值：剩余 3 天不刷新，剩余 12 小时刷新
+	client, _ := newRefreshingThreadsClient(3 * 24 * time.Hour)
+	client.SetRefreshThreshold(24 * time.Hour)
+	assert.Equal(t, 24*time.Hour, client.RefreshThreshold())
+	require.NoError(t, client.EnsureValidToken(context.Background()))
+	assert.EqualValues(t, 0, calls.Load())
+	assert.Equal(t, "current", client.getAccessToken())
+
+	client, tokens := newRefreshingThreadsClient(12 * time.Hour)
+	client.SetRefreshThreshold(24 * time.Hour)
+	require.NoError(t, client.EnsureValidToken(context.Background()))
+	assert.EqualValues(t, 1, calls.Load())
+	assert.Equal(t, "refreshed", tokens.tokens["threads"].AccessToken)
+}
+
+func TestThreads_EnsureValidToken_DefaultThresholdSkipsLongLivedToken(t *testing.T) {
+	calls := newThreadsRefreshServer(t, http.StatusOK)
+	client, tokens := newRefreshingThreadsClient(30 * 24 * time.Hour)
+	assert.Equal(t, DefaultTokenRefreshThreshold, client.RefreshThreshold())
+
+	require.NoError(t, client.EnsureValidToken(context.Background()))
+	assert.EqualValues(t, 0, calls.Load())
+	assert.Equal(t, "current", tokens.tokens["threads"].AccessToken)
+
+	// 负值恢复默认阈值
+	client.SetRefreshThreshold(-time.Hour)
+	assert.Equal(t, DefaultTokenRefreshThreshold, client.RefreshThreshold())
+}
@@ -0,0 +1,96 @@
+package social
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"go.orx.me/apps/hyper-sync/internal/telemetry"
+)
+
+func installSpanRecorder(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	orig := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(orig) })
+	return exporter
+}
+
+// httpSpans returns the spans recorded by the platform HTTP transport.
+func httpSpans(exporter *tracetest.InMemoryExporter) tracetest.SpanStubs {
+	var spans tracetest.SpanStubs
+	for _, span := range exporter.GetSpans() {
+		if span.InstrumentationScope.Name == telemetry.HTTPTracerName {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func spanAttr(span tracetest.SpanStub, key string) attribute.Value {
+	for _, kv := range span.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTelegram_Post_TracesSendMessage(t *testing.T) {
+	exporter := installSpanRecorder(t)
+	server := newFakeTelegramServer(t)
+	client, err := NewTelegramClient("test-token", "@chan", "tg", server.URL, nil, nil, "", nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "cross_post")
+	_, err = client.Post(ctx, &Post{Content: "hello"})
+	parent.End()
+	require.NoError(t, err)
+
+	spans := httpSpans(exporter)
+	require.Len(t, spans, 1, "getUpdates polling is not traced")
+	span := spans[0]
+	assert.Equal(t, "telegram POST", span.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	assert.Equal(t, "telegram", spanAttr(span, telemetry.AttrPlatform).AsString())
+	assert.Equal(t, http.MethodPost, spanAttr(span, telemetry.AttrHTTPMethod).AsString())
+	assert.Equal(t, "/bot<token>/sendMessage", spanAttr(span, telemetry.AttrURLPath).AsString())
+	assert.Equal(t, int64(http.StatusOK), spanAttr(span, telemetry.AttrHTTPStatusCode).AsInt64())
+	assert.Equal(t, codes.Ok, span.Status.Code)
+}
+
+func TestThreads_TracesErrorResponse(t *testing.T) {
+	exporter := installSpanRecorder(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad"}}`))
+	}))
+	defer server.Close()
+	orig := threadsGraphURL
+	threadsGraphURL = server.URL
+	defer func() { threadsGraphURL = orig }()
+
+	client := &ThreadsClient{name: "threads", UserID: 42, accessToken: "token"}
+	_, err := client.CreateMediaContainer(context.Background(), "42", &PostRequest{MediaType: "TEXT", Text: "hi"})
+	require.Error(t, err)
+
+	spans := httpSpans(exporter)
+	require.Len(t, spans, 1)
+	assert.Equal(t, "threads POST", spans[0].Name)
+	assert.Equal(t, "/42/threads", spanAttr(spans[0], telemetry.AttrURLPath).AsString())
+	assert.Equal(t, int64(http.StatusBadRequest), spanAttr(spans[0], telemetry.AttrHTTPStatusCode).AsInt64())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.False(t, strings.Contains(spanAttr(spans[0], telemetry.AttrURLPath).AsString(), "token"))
+}
@@ -0,0 +1,174 @@
+package social
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ContentTransformer rewrites a post for one target platform before it is
+// handed to that platform's client. Implementations must not modify the
+// post they are given, since the same post fans out to several platforms.
+type ContentTransformer interface {
+	Transform(post *Post) (*Post, error)
+}
+
+// PassthroughTransformer returns posts unchanged. It is used for platforms
+// without a template.
+type PassthroughTransformer struct{}
+
+func (PassthroughTransformer) Transform(post *Post) (*Post, error) {
+	return post, nil
+}
+
+// TemplateData is what a content template is executed against.
+type TemplateData struct {
+	Content        string
+	SourcePlatform string
+	SourceURL      string
+	OriginalID     string
+	CreatedAt      time.Time
+}
+
+// templateFuncs are available to content templates in addition to the
+// text/template builtins.
+var templateFuncs = template.FuncMap{
+	"truncate": truncateText,
+	"trim":     strings.TrimSpace,
+}
+
+// truncateText shortens s to at most n characters, ending with "…" when cut.
+// It never cuts inside a grapheme cluster such as an emoji sequence.
+func truncateText(n int, s string) string {
+	return truncateContent(n, s, true)
+}
+
+// TemplateTransformer renders a post's content through a text/template.
+type TemplateTransformer struct {
+	tmpl *template.Template
+}
+
+// NewTemplateTransformer parses text as a content template, e.g.
+// "{{.Content}}\n\n🔗 {{.SourceURL}}".
+func NewTemplateTransformer(name, text string) (*TemplateTransformer, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse content template: %w", err)
+	}
+	return &TemplateTransformer{tmpl: tmpl}, nil
+}
+
+// Transform returns a copy of post whose Content is the rendered template.
+func (t *TemplateTransformer) Transform(post *Post) (*Post, error) {
+	var b strings.Builder
+	err := t.tmpl.Execute(&b, TemplateData{
+		Content:        post.Content,
+		SourcePlatform: post.SourcePlatform,
+		SourceURL:      post.SourceURL,
+		OriginalID:     post.OriginalID,
+		CreatedAt:      post.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render content template: %w", err)
+	}
+
+	out := *post
+	out.Content = strings.TrimSpace(b.String())
+	return &out, nil
+}
+
+// NewContentTransformer builds the transformer described by cfg, falling
+// back to a passthrough when no template is configured.
+func NewContentTransformer(name string, cfg *TemplateConfig) (ContentTransformer, error) {
+	if cfg == nil || cfg.Content == "" {
+		return PassthroughTransformer{}, nil
+	}
+	return NewTemplateTransformer(name, cfg.Content)
+}
+
+// telegramCaptionLimit applies instead of the message limit when a Telegram
+// post carries media, since the content becomes a caption.
+const telegramCaptionLimit = 1024
+
+// ContentLimit returns the maximum content length, in characters, that the
+// platform type accepts for post, or 0 when there is no limit.
+func ContentLimit(platformType string, post *Post) int {
+	if Platform(platformType) == PlatformTelegram && len(post.Media) > 0 {
+		return telegramCaptionLimit
+	}
+	return PlatformLimits[Platform(platformType)]
+}
+
+// FooterTransformer appends a rendered footer (e.g. "via my blog
+// {{.SourceURL}}") to the content produced by next. When the result would
+// exceed the platform's content limit, the body is trimmed so the footer
+// always survives.
+type FooterTransformer struct {
+	next         ContentTransformer
+	tmpl         *template.Template
+	platformType string
+	maxChars     int
+}
+
+// NewFooterTransformer parses text as a footer template for a platform of
+// the given type. next runs first; nil means the content is used as-is.
+func NewFooterTransformer(name, platformType, text string, next ContentTransformer) (*FooterTransformer, error) {
+	tmpl, err := template.New(name + "-footer").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse footer template: %w", err)
+	}
+	if next == nil {
+		next = PassthroughTransformer{}
+	}
+	return &FooterTransformer{next: next, tmpl: tmpl, platformType: platformType}, nil
+}
+
+// SetMaxChars overrides the platform's default content limit, mirroring
+// PlatformConfig.MaxChars. 0 keeps the default.
+func (t *FooterTransformer) SetMaxChars(n int) {
+	t.maxChars = n
+}
+
+// Transform returns a copy of post with the footer appended.
+func (t *FooterTransformer) Transform(post *Post) (*Post, error) {
+	out, err := t.next.Transform(post)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	err = t.tmpl.Execute(&b, TemplateData{
+		Content:        out.Content,
+		SourcePlatform: post.SourcePlatform,
+		SourceURL:      post.SourceURL,
+		OriginalID:     post.OriginalID,
+		CreatedAt:      post.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render footer template: %w", err)
+	}
+	footer := strings.TrimSpace(b.String())
+	if footer == "" {
+		return out, nil
+	}
+
+	const sep = "\n\n"
+	body := out.Content
+	if limit := effectiveLimit(t.platformType, t.maxChars, out); limit > 0 {
+		room := limit - graphemeCount(footer) - graphemeCount(sep)
+		if room <= 0 {
+			body = ""
+		} else if graphemeCount(body) > room {
+			body = truncateText(room, body)
+		}
+	}
+
+	result := *out
+	if body == "" {
+		result.Content = footer
+	} else {
+		result.Content = body + sep + footer
+	}
+	return &result, nil
+}
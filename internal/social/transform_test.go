@@ -0,0 +1,163 @@
+package social
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateTransformer(t *testing.T) {
+	tr, err := NewTemplateTransformer("telegram", "{{.Content}}\n\n🔗 {{.SourceURL}}")
+	require.NoError(t, err)
+
+	post := &Post{Content: "hello", SourceURL: "https://memos.example.com/m/abc", SourcePlatform: "memos"}
+	out, err := tr.Transform(post)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello\n\n🔗 https://memos.example.com/m/abc", out.Content)
+	assert.Equal(t, "hello", post.Content, "the original post must not be modified")
+	assert.Equal(t, "memos", out.SourcePlatform)
+}
+
+func TestTemplateTransformer_Fields(t *testing.T) {
+	tr, err := NewTemplateTransformer("test",
+		`{{truncate 8 .Content}} via {{.SourcePlatform}}#{{.OriginalID}} on {{.CreatedAt.Format "2006-01-02"}}`)
+	require.NoError(t, err)
+
+	out, err := tr.Transform(&Post{
+		Content:        "a rather long post",
+		SourcePlatform: "memos",
+		OriginalID:     "42",
+		CreatedAt:      time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "a rathe… via memos#42 on 2026-03-04", out.Content)
+}
+
+func TestTemplateTransformer_Errors(t *testing.T) {
+	_, err := NewTemplateTransformer("bad", "{{.Content")
+	assert.Error(t, err)
+
+	tr, err := NewTemplateTransformer("unknown field", "{{.Nope}}")
+	require.NoError(t, err)
+	_, err = tr.Transform(&Post{Content: "hi"})
+	assert.Error(t, err)
+}
+
+func TestNewContentTransformer_DefaultsToPassthrough(t *testing.T) {
+	for _, cfg := range []*TemplateConfig{nil, {}} {
+		tr, err := NewContentTransformer("mastodon", cfg)
+		require.NoError(t, err)
+		assert.IsType(t, PassthroughTransformer{}, tr)
+
+		post := &Post{Content: "unchanged"}
+		out, err := tr.Transform(post)
+		require.NoError(t, err)
+		assert.Same(t, post, out)
+	}
+}
+
+func TestFooterTransformer(t *testing.T) {
+	tr, err := NewFooterTransformer("mastodon", "mastodon", "via my blog ({{.SourceURL}})", nil)
+	require.NoError(t, err)
+
+	post := &Post{Content: "hello", SourceURL: "https://memos.example.com/m/abc"}
+	out, err := tr.Transform(post)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello\n\nvia my blog (https://memos.example.com/m/abc)", out.Content)
+	assert.Equal(t, "hello", post.Content, "the original post must not be modified")
+}
+
+func TestFooterTransformer_TrimsBodyToLimit(t *testing.T) {
+	tr, err := NewFooterTransformer("bluesky", "bluesky", "🔗 {{.SourceURL}}", nil)
+	require.NoError(t, err)
+
+	footer := "🔗 https://memos.example.com/m/abc"
+	out, err := tr.Transform(&Post{Content: strings.Repeat("很长", 200), SourceURL: "https://memos.example.com/m/abc"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 300, utf8.RuneCountInString(out.Content))
+	assert.True(t, strings.HasSuffix(out.Content, "…\n\n"+footer), "the body should be trimmed, not the footer: %q", out.Content)
+}
+
+func TestFooterTransformer_TelegramCaptionLimit(t *testing.T) {
+	tr, err := NewFooterTransformer("telegram", "telegram", "via blog", nil)
+	require.NoError(t, err)
+
+	long := strings.Repeat("a", 2000)
+	out, err := tr.Transform(&Post{Content: long})
+	require.NoError(t, err)
+	assert.Equal(t, long+"\n\nvia blog", out.Content, "text messages allow 4096 characters")
+
+	out, err = tr.Transform(&Post{Content: long, Media: []Media{*NewMediaFromURL("https://example.com/a.png")}})
+	require.NoError(t, err)
+	assert.Equal(t, 1024, utf8.RuneCountInString(out.Content), "captions allow 1024 characters")
+	assert.True(t, strings.HasSuffix(out.Content, "via blog"))
+}
+
+func TestFooterTransformer_RunsAfterTemplate(t *testing.T) {
+	tmpl, err := NewTemplateTransformer("mastodon", "[{{.Content}}]")
+	require.NoError(t, err)
+	tr, err := NewFooterTransformer("mastodon", "mastodon", "{{if .SourceURL}}{{.SourceURL}}{{end}}", tmpl)
+	require.NoError(t, err)
+
+	out, err := tr.Transform(&Post{Content: "hi", SourceURL: "https://example.com/1"})
+	require.NoError(t, err)
+	assert.Equal(t, "[hi]\n\nhttps://example.com/1", out.Content)
+
+	out, err = tr.Transform(&Post{Content: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "[hi]", out.Content, "an empty footer adds nothing")
+
+	_, err = NewFooterTransformer("bad", "mastodon", "{{.SourceURL", nil)
+	assert.Error(t, err)
+}
+
+func TestCrossPost_AppliesPlatformTemplate(t *testing.T) {
+	telegram := &recordingClient{name: "telegram"}
+	mastodon := &recordingClient{name: "mastodon"}
+
+	tr, err := NewTemplateTransformer("telegram", "{{.Content}}\n\n🔗 {{.SourceURL}}")
+	require.NoError(t, err)
+
+	telegramPlatform := &SocialPlatform{
+		Name:        "telegram",
+		Client:      telegram,
+		Config:      &PlatformConfig{SyncEnabled: true, SyncFromPlatforms: []string{"*"}},
+		Transformer: tr,
+	}
+	mastodonPlatform := &SocialPlatform{
+		Name:   "mastodon",
+		Client: mastodon,
+		Config: &PlatformConfig{SyncEnabled: true, SyncFromPlatforms: []string{"*"}},
+	}
+
+	_, err = CrossPost(context.Background(),
+		&Post{Content: "hello", SourcePlatform: "memos", SourceURL: "https://memos.example.com/m/1"},
+		[]*SocialPlatform{telegramPlatform, mastodonPlatform})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"hello\n\n🔗 https://memos.example.com/m/1"}, telegram.contents)
+	assert.Equal(t, []string{"hello"}, mastodon.contents)
+}
+
+// recordingClient records the content of every post it receives.
+type recordingClient struct {
+	name     string
+	contents []string
+}
+
+func (r *recordingClient) Post(_ context.Context, post *Post) (interface{}, error) {
+	r.contents = append(r.contents, post.Content)
+	return nil, nil
+}
+
+func (r *recordingClient) ListPosts(_ context.Context, _ int) ([]*Post, error) { return nil, nil }
+
+func (r *recordingClient) Name() string { return r.name }
@@ -0,0 +1,82 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func repeatMedia(data []byte, n int) []Media {
+	media := make([]Media, n)
+	for i := range media {
+		media[i] = *NewMedia(data)
+	}
+	return media
+}
+
+// oversized returns data of the given size that sniffs as the type of sig.
+func oversized(sig []byte, size int) []byte {
+	data := make([]byte, size)
+	copy(data, sig)
+	return data
+}
+
+func TestValidateMedia(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform Platform
+		media    []Media
+		wantErr  string
+	}{
+		{"no media", PlatformBluesky, nil, ""},
+		{"unknown platform", Platform("myspace"), repeatMedia(pngSignature, 50), ""},
+
+		{"bluesky 4 images", PlatformBluesky, repeatMedia(pngSignature, 4), ""},
+		{"bluesky 6 images", PlatformBluesky, repeatMedia(pngSignature, 6), "bluesky allows at most 4 images, got 6"},
+		{"bluesky videos are not images", PlatformBluesky,
+			append(repeatMedia(pngSignature, 4), repeatMedia(mp4Signature, 2)...), ""},
+
+		{"mastodon 4 attachments", PlatformMastodon, repeatMedia(jpegSignature, 4), ""},
+		{"mastodon 5 attachments", PlatformMastodon, repeatMedia(jpegSignature, 5), "mastodon allows at most 4 attachments, got 5"},
+		{"mastodon image too large", PlatformMastodon, []Media{*NewMedia(oversized(pngSignature, 17<<20))},
+			"mastodon allows images up to 16 MB, attachment 1 is 17.0 MB"},
+		{"mastodon large video", PlatformMastodon, []Media{*NewMedia(oversized(mp4Signature, 17<<20))}, ""},
+
+		{"threads 20 items", PlatformThreads, repeatMedia(pngSignature, 20), ""},
+		{"threads 21 items", PlatformThreads, repeatMedia(pngSignature, 21), "threads allows at most 20 attachments, got 21"},
+
+		{"telegram splits albums", PlatformTelegram, repeatMedia(pngSignature, 25), ""},
+		{"telegram photo too large", PlatformTelegram,
+			[]Media{*NewMedia(pngSignature), *NewMedia(oversized(jpegSignature, 11<<20))},
+			"telegram allows images up to 10 MB, attachment 2 is 11.0 MB"},
+
+		{"discord 10 files", PlatformDiscord, repeatMedia(gifSignature, 10), ""},
+		{"discord 11 files", PlatformDiscord, repeatMedia(gifSignature, 11), "discord allows at most 10 attachments, got 11"},
+
+		{"nostr has no limits", PlatformNostr, repeatMedia(pngSignature, 30), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMedia(tt.platform.String(), tt.media)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidMedia))
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestBlueskyClient_Post_RejectsTooManyImages(t *testing.T) {
+	client := &BlueskyClient{name: "bluesky"}
+	_, err := client.Post(context.Background(), &Post{Content: "album", Media: repeatMedia(pngSignature, 5)})
+
+	require.ErrorIs(t, err, ErrInvalidMedia)
+	assert.Contains(t, err.Error(), "at most 4 images, got 5")
+}
@@ -1,6 +1,7 @@
 package social
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -403,3 +404,174 @@ func TestValidateAndNormalizeVisibilityLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestPlatformConfigVisibilityMapRemaps(t *testing.T) {
+	config := &PlatformConfig{
+		Type:          "memos",
+		VisibilityMap: map[VisibilityLevel]string{VisibilityLevelUnlisted: MemosVisibilityPrivate},
+	}
+
+	got, err := config.GetPlatformVisibilityString(VisibilityLevelUnlisted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != MemosVisibilityPrivate {
+		t.Errorf("expected %s but got %s", MemosVisibilityPrivate, got)
+	}
+	// Levels without an override keep the default mapping
+	if got, _ := config.GetPlatformVisibilityString(VisibilityLevelPublic); got != MemosVisibilityPublic {
+		t.Errorf("expected %s but got %s", MemosVisibilityPublic, got)
+	}
+
+	// Bluesky has no unlisted; remapping it to private makes it postable
+	bluesky := &PlatformConfig{
+		Type:          "bluesky",
+		VisibilityMap: map[VisibilityLevel]string{VisibilityLevelUnlisted: VisibilityPrivate},
+	}
+	if err := ValidateVisibilityLevel("bluesky", VisibilityLevelUnlisted); err == nil {
+		t.Fatal("expected unlisted to be unsupported by bluesky without an override")
+	}
+	if err := bluesky.ValidateVisibilityLevel(VisibilityLevelUnlisted); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	platform := &SocialPlatform{Name: "bsky", Config: bluesky}
+	post := &Post{Content: "hi", Visibility: VisibilityLevelUnlisted}
+	out, err := platform.Transform(post)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Visibility != VisibilityLevelPrivate {
+		t.Errorf("expected transformed visibility private but got %s", out.Visibility)
+	}
+	if post.Visibility != VisibilityLevelUnlisted {
+		t.Error("Transform modified the original post")
+	}
+}
+
+func TestPlatformConfigVisibilityMapSkips(t *testing.T) {
+	config := &PlatformConfig{
+		Type:          "bluesky",
+		VisibilityMap: map[VisibilityLevel]string{VisibilityLevelUnlisted: VisibilitySkip},
+	}
+
+	if _, err := config.GetPlatformVisibilityString(VisibilityLevelUnlisted); !errors.Is(err, ErrVisibilitySkipped) {
+		t.Errorf("expected ErrVisibilitySkipped but got %v", err)
+	}
+	if err := config.ValidateVisibilityLevel(VisibilityLevelUnlisted); !errors.Is(err, ErrVisibilitySkipped) {
+		t.Errorf("expected ErrVisibilitySkipped but got %v", err)
+	}
+	if err := config.ValidateVisibilityLevel(VisibilityLevelPublic); err != nil {
+		t.Errorf("unexpected error for public: %v", err)
+	}
+
+	platform := &SocialPlatform{Name: "bsky", Config: config}
+	if _, err := platform.Transform(&Post{Content: "hi", Visibility: VisibilityLevelUnlisted}); !errors.Is(err, ErrVisibilitySkipped) {
+		t.Errorf("expected Transform to return ErrVisibilitySkipped but got %v", err)
+	}
+}
+
+func TestValidateVisibilityMap(t *testing.T) {
+	tests := []struct {
+		name        string
+		platform    string
+		overrides   map[VisibilityLevel]string
+		expectError bool
+	}{
+		{"skip", "bluesky", map[VisibilityLevel]string{VisibilityLevelUnlisted: VisibilitySkip}, false},
+		{"memos native value", "memos", map[VisibilityLevel]string{VisibilityLevelPrivate: MemosVisibilityProtected}, false},
+		{"unsupported target", "bluesky", map[VisibilityLevel]string{VisibilityLevelPrivate: VisibilityDirect}, true},
+		{"unknown value", "mastodon", map[VisibilityLevel]string{VisibilityLevelPublic: "friends"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVisibilityMap(tt.platform, tt.overrides)
+			if tt.expectError && err == nil {
+				t.Errorf("expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestVisibilityLevelUnmarshalText(t *testing.T) {
+	var level VisibilityLevel
+	if err := level.UnmarshalText([]byte("unlisted")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if level != VisibilityLevelUnlisted {
+		t.Errorf("expected unlisted but got %s", level)
+	}
+	if err := level.UnmarshalText([]byte("friends")); err == nil {
+		t.Error("expected error for unknown visibility")
+	}
+}
+
+func TestUnsupportedVisibilityPolicyOnBlueskyDirectPost(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		expected VisibilityLevel
+		err      error
+	}{
+		{name: "default is error", policy: "", err: ErrVisibilityNotSupported},
+		{name: "error", policy: VisibilityPolicyError, err: ErrVisibilityNotSupported},
+		{name: "skip", policy: VisibilityPolicySkip, err: ErrVisibilitySkipped},
+		{name: "downgrade", policy: VisibilityPolicyDowngrade, expected: VisibilityLevelPrivate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			platform := &SocialPlatform{
+				Name:   "bsky",
+				Config: &PlatformConfig{Type: "bluesky", UnsupportedVisibilityPolicy: tt.policy},
+			}
+			post := &Post{Content: "hi", Visibility: VisibilityLevelDirect}
+
+			out, err := platform.Transform(post)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v but got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if out.Visibility != tt.expected {
+				t.Errorf("expected %s but got %s", tt.expected, out.Visibility)
+			}
+			if post.Visibility != VisibilityLevelDirect {
+				t.Error("Transform modified the original post")
+			}
+		})
+	}
+}
+
+func TestApplyVisibilityPolicyDowngrade(t *testing.T) {
+	tests := []struct {
+		platform string
+		level    VisibilityLevel
+		expected VisibilityLevel
+	}{
+		{"bluesky", VisibilityLevelUnlisted, VisibilityLevelPublic},
+		{"memos", VisibilityLevelDirect, VisibilityLevelPrivate},
+		{"telegram", VisibilityLevelPrivate, VisibilityLevelPublic},
+		{"mastodon", VisibilityLevelDirect, VisibilityLevelDirect},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform+" "+tt.level.String(), func(t *testing.T) {
+			got, err := ApplyVisibilityPolicy(tt.platform, tt.level, VisibilityPolicyDowngrade)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s but got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package social
+
+import (
+	"errors"
+	"fmt"
+)
+
+// VisibilitySkip as a PlatformConfig.VisibilityMap value means posts at that
+// visibility are not sent to the platform at all.
+const VisibilitySkip = "skip"
+
+// Policies for a visibility the platform does not support, set with
+// PlatformConfig.UnsupportedVisibilityPolicy.
+const (
+	// VisibilityPolicyError 发布失败，返回 ErrVisibilityNotSupported（默认）
+	VisibilityPolicyError = "error"
+	// VisibilityPolicySkip 不发到该平台，记为跳过而不是失败
+	VisibilityPolicySkip = "skip"
+	// VisibilityPolicyDowngrade 改用最接近的受支持可见性，优先更公开的一级，如 direct→private、unlisted→public
+	VisibilityPolicyDowngrade = "downgrade"
+)
+
+var (
+	// ErrVisibilitySkipped is returned when a platform's visibility_map or
+	// unsupported visibility policy says not to post at a post's visibility.
+	// It is a deliberate skip, not a failure.
+	ErrVisibilitySkipped = errors.New("visibility skipped")
+	// ErrVisibilityNotSupported is returned when a post's visibility is not
+	// supported by the platform it is sent to.
+	ErrVisibilityNotSupported = errors.New("visibility not supported")
+)
+
+// IsVisibilitySkip reports whether err keeps a post off a platform because
+// of its visibility, either by configuration or because the platform does
+// not support it. Retrying such a post cannot succeed.
+func IsVisibilitySkip(err error) bool {
+	return errors.Is(err, ErrVisibilitySkipped) || errors.Is(err, ErrVisibilityNotSupported)
+}
+
+func isValidVisibilityPolicy(policy string) bool {
+	switch policy {
+	case "", VisibilityPolicyError, VisibilityPolicySkip, VisibilityPolicyDowngrade:
+		return true
+	}
+	return false
+}
+
+// checkVisibility returns an error wrapping ErrVisibilityNotSupported when
+// level is set and platform does not support it. Clients call it before
+// publishing, as SocialPlatform.Transform only applies the policy for posts
+// that go through it.
+func checkVisibility(platform Platform, level VisibilityLevel) error {
+	if level.IsValid() && !IsVisibilityLevelSupported(platform.String(), level) {
+		return fmt.Errorf("%w by platform %s: %s", ErrVisibilityNotSupported, platform.String(), level.String())
+	}
+	return nil
+}
+
+// ApplyVisibilityPolicy returns the level a post at level is published at on
+// platform (a platform type such as "bluesky") when the platform does not
+// support level. Supported levels are returned unchanged. policy is one of
+// the VisibilityPolicy constants; "" is VisibilityPolicyError.
+func ApplyVisibilityPolicy(platform string, level VisibilityLevel, policy string) (VisibilityLevel, error) {
+	if !level.IsValid() || IsVisibilityLevelSupported(platform, level) {
+		return level, nil
+	}
+
+	switch policy {
+	case VisibilityPolicySkip:
+		return level, fmt.Errorf("%w: %s is not supported by platform %s", ErrVisibilitySkipped, level.String(), platform)
+	case VisibilityPolicyDowngrade:
+		if downgraded, ok := downgradeVisibility(platform, level); ok {
+			return downgraded, nil
+		}
+	}
+	return level, fmt.Errorf("%w by platform %s: %s", ErrVisibilityNotSupported, platform, level.String())
+}
+
+// downgradeVisibility returns the supported level nearest to level,
+// preferring the more public side.
+func downgradeVisibility(platform string, level VisibilityLevel) (VisibilityLevel, bool) {
+	for l := level - 1; l >= VisibilityLevelPublic; l-- {
+		if IsVisibilityLevelSupported(platform, l) {
+			return l, true
+		}
+	}
+	for l := level + 1; l <= VisibilityLevelDirect; l++ {
+		if IsVisibilityLevelSupported(platform, l) {
+			return l, true
+		}
+	}
+	return level, false
+}
+
+// ResolveVisibility returns the level a post at level is published at on
+// platform (a platform type such as "bluesky"), after applying overrides.
+// Override values are platform visibility strings, so Memos accepts
+// "PROTECTED" as well as "unlisted". Levels without an override are
+// returned unchanged. A "skip" override returns an error wrapping
+// ErrVisibilitySkipped.
+func ResolveVisibility(platform string, level VisibilityLevel, overrides map[VisibilityLevel]string) (VisibilityLevel, error) {
+	value, ok := overrides[level]
+	if !ok {
+		return level, nil
+	}
+	if value == VisibilitySkip {
+		return level, fmt.Errorf("%w: visibility_map skips %s posts on %s", ErrVisibilitySkipped, level.String(), platform)
+	}
+	mapped, err := ParsePlatformVisibility(platform, value)
+	if err != nil {
+		return level, fmt.Errorf("invalid visibility_map value for %s: %w", level.String(), err)
+	}
+	return mapped, nil
+}
+
+// validateVisibilityMap checks that every override maps to "skip" or to a
+// visibility the platform supports.
+func validateVisibilityMap(platform string, overrides map[VisibilityLevel]string) error {
+	for level, value := range overrides {
+		if !level.IsValid() {
+			return fmt.Errorf("invalid visibility_map key %d", level)
+		}
+		mapped, err := ResolveVisibility(platform, level, overrides)
+		if errors.Is(err, ErrVisibilitySkipped) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := ValidateVisibilityLevel(platform, mapped); err != nil {
+			return fmt.Errorf("invalid visibility_map value %q for %s: %w", value, level.String(), err)
+		}
+	}
+	return nil
+}
+
+// ResolveVisibility returns the level a post at level is published at on
+// this platform: its VisibilityMap is applied first, then its
+// UnsupportedVisibilityPolicy if the result is not supported. Skipped
+// levels return an error wrapping ErrVisibilitySkipped.
+func (c *PlatformConfig) ResolveVisibility(level VisibilityLevel) (VisibilityLevel, error) {
+	mapped, err := ResolveVisibility(c.Type, level, c.VisibilityMap)
+	if err != nil {
+		return level, err
+	}
+	return ApplyVisibilityPolicy(c.Type, mapped, c.UnsupportedVisibilityPolicy)
+}
+
+// ValidateVisibilityLevel is ValidateVisibilityLevel for this platform after
+// its VisibilityMap and UnsupportedVisibilityPolicy are applied. Skipped
+// levels return an error wrapping ErrVisibilitySkipped.
+func (c *PlatformConfig) ValidateVisibilityLevel(level VisibilityLevel) error {
+	mapped, err := c.ResolveVisibility(level)
+	if err != nil {
+		return err
+	}
+	return ValidateVisibilityLevel(c.Type, mapped)
+}
+
+// GetPlatformVisibilityString is GetPlatformVisibilityString for this
+// platform after its VisibilityMap and UnsupportedVisibilityPolicy are
+// applied. Skipped levels return an error wrapping ErrVisibilitySkipped.
+func (c *PlatformConfig) GetPlatformVisibilityString(level VisibilityLevel) (string, error) {
+	mapped, err := c.ResolveVisibility(level)
+	if err != nil {
+		return "", err
+	}
+	return GetPlatformVisibilityString(c.Type, mapped), nil
+}
@@ -0,0 +1,210 @@
+package social
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"butterfly.orx.me/core/log"
+)
+
+// wordPressTitleLimit caps the title derived from a post's first line.
+const wordPressTitleLimit = 100
+
+// wordPressUploadTimeout is the minimum client timeout, since media uploads
+// can take longer than an ordinary API call.
+const wordPressUploadTimeout = 60 * time.Second
+
+// wordPressStatus maps visibility levels to WordPress post statuses. An
+// unlisted post is kept as a draft, since WordPress has no unlisted status.
+var wordPressStatus = map[VisibilityLevel]string{
+	VisibilityLevelPublic:   "publish",
+	VisibilityLevelUnlisted: "draft",
+	VisibilityLevelPrivate:  "private",
+}
+
+// WordPressClient implements SocialClient for publishing long-form posts to
+// a WordPress site through the REST API, authenticated with an application
+// password. It is a sync target only.
+type WordPressClient struct {
+	name        string
+	siteURL     string
+	username    string
+	appPassword string
+	httpClient  *http.Client
+}
+
+// NewWordPressClient creates a client for the site at siteURL. httpClients
+// supplies the timeout, proxy and TLS settings; nil uses the defaults.
+func NewWordPressClient(name, siteURL, username, appPassword string, httpClients *HTTPClientFactory) *WordPressClient {
+	httpClients = httpClientsOrDefault(httpClients)
+	return &WordPressClient{
+		name:        name,
+		siteURL:     strings.TrimSuffix(siteURL, "/"),
+		username:    username,
+		appPassword: appPassword,
+		httpClient: &http.Client{
+			// 媒体上传可能较慢，超时不短于 wordPressUploadTimeout
+			Timeout:   max(httpClients.Timeout(), wordPressUploadTimeout),
+			Transport: httpClients.Transport(),
+		},
+	}
+}
+
+func (w *WordPressClient) Name() string { return w.name }
+
+type wordPressPostRequest struct {
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+	Status        string `json:"status"`
+	FeaturedMedia int    `json:"featured_media,omitempty"`
+}
+
+type wordPressPostResponse struct {
+	ID   int    `json:"id"`
+	Link string `json:"link"`
+}
+
+type wordPressMediaResponse struct {
+	ID        int    `json:"id"`
+	SourceURL string `json:"source_url"`
+}
+
+// Post creates a WordPress post titled with the first line of the content.
+// Media is uploaded to the media library; the first item becomes the
+// featured image and the rest are appended to the content. It returns the
+// post ID and link.
+func (w *WordPressClient) Post(ctx context.Context, post *Post) (interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	visibility := post.Visibility
+	if !visibility.IsValid() {
+		visibility = VisibilityLevelPublic
+	}
+	if err := checkVisibility(PlatformWordPress, visibility); err != nil {
+		return nil, err
+	}
+	status := wordPressStatus[visibility]
+	if err := ValidateMedia(PlatformWordPress.String(), post.Media); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(post.Content) == "" && len(post.Media) == 0 {
+		return nil, errors.New("wordpress: post has no content or media")
+	}
+
+	req := wordPressPostRequest{
+		Title:   wordPressTitle(post.Content),
+		Content: post.Content,
+		Status:  status,
+	}
+	for i := range post.Media {
+		uploaded, err := w.uploadMedia(ctx, &post.Media[i], i)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			req.FeaturedMedia = uploaded.ID
+			continue
+		}
+		req.Content += fmt.Sprintf("\n\n<img src=\"%s\" alt=\"%s\" />",
+			html.EscapeString(uploaded.SourceURL), html.EscapeString(post.Media[i].Description))
+	}
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal wordpress post: %w", err)
+	}
+	respBody, err := w.do(ctx, "/wp-json/wp/v2/posts", "application/json", "", bytes.NewReader(jsonData), "wordpress create post")
+	if err != nil {
+		return nil, err
+	}
+	var created wordPressPostResponse
+	if err := DecodeJSON(respBody, &created); err != nil {
+		return nil, fmt.Errorf("failed to parse wordpress post response: %w", err)
+	}
+
+	logger.Info("posted to wordpress",
+		"client", w.name,
+		"post_id", created.ID,
+		"status", status,
+		"media", len(post.Media))
+
+	return map[string]string{"id": strconv.Itoa(created.ID), "url": created.Link}, nil
+}
+
+func (w *WordPressClient) ListPosts(_ context.Context, _ int) ([]*Post, error) {
+	return nil, errors.New("wordpress: listing posts not supported")
+}
+
+// uploadMedia adds m to the media library.
+func (w *WordPressClient) uploadMedia(ctx context.Context, m *Media, index int) (*wordPressMediaResponse, error) {
+	data, err := m.GetDataFor(PlatformWordPress.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media data: %w", err)
+	}
+	contentType, err := m.ContentType()
+	if err != nil {
+		contentType = "application/octet-stream"
+	}
+	filename := fmt.Sprintf("media%d%s", index, m.Extension())
+
+	respBody, err := w.do(ctx, "/wp-json/wp/v2/media", contentType, filename, bytes.NewReader(data), "wordpress upload media")
+	if err != nil {
+		return nil, err
+	}
+	var uploaded wordPressMediaResponse
+	if err := DecodeJSON(respBody, &uploaded); err != nil {
+		return nil, fmt.Errorf("failed to parse wordpress media response: %w", err)
+	}
+	return &uploaded, nil
+}
+
+// do POSTs body to path with Basic auth and returns the body of a 2xx
+// response. A filename sends body as a file upload.
+func (w *WordPressClient) do(ctx context.Context, path, contentType, filename string, body io.Reader, op string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.siteURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", op, err)
+	}
+	req.SetBasicAuth(w.username, w.appPassword)
+	req.Header.Set("Content-Type", contentType)
+	if filename != "" {
+		req.Header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := readResponseBody(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", op, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newPlatformError(w.name, op, resp, respBody)
+	}
+	return respBody, nil
+}
+
+// wordPressTitle derives a title from the first non-empty line of content,
+// without Markdown heading marks, cut to wordPressTitleLimit characters.
+func wordPressTitle(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		if line == "" {
+			continue
+		}
+		return truncateContent(wordPressTitleLimit, line, true)
+	}
+	return ""
+}
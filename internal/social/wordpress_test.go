@@ -0,0 +1,148 @@
+package social
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wordPressUpload struct {
+	contentType        string
+	contentDisposition string
+	data               []byte
+}
+
+// fakeWordPress records media uploads and created posts, answering like the
+// WordPress REST API.
+type fakeWordPress struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	users   []string
+	uploads []wordPressUpload
+	posts   []wordPressPostRequest
+}
+
+func newFakeWordPress(t *testing.T) *fakeWordPress {
+	t.Helper()
+	f := &fakeWordPress{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || pass != "app pass word" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":"rest_not_logged_in"}`))
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.users = append(f.users, user)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/wp-json/wp/v2/media":
+			f.uploads = append(f.uploads, wordPressUpload{
+				contentType:        r.Header.Get("Content-Type"),
+				contentDisposition: r.Header.Get("Content-Disposition"),
+				data:               body,
+			})
+			id := 100 + len(f.uploads)
+			_, _ = w.Write([]byte(`{"id":` + strconv.Itoa(id) + `,"source_url":"https://blog.example.com/uploads/` + strconv.Itoa(id) + `.png"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/wp-json/wp/v2/posts":
+			var req wordPressPostRequest
+			require.NoError(t, json.Unmarshal(body, &req))
+			f.posts = append(f.posts, req)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":42,"link":"https://blog.example.com/?p=42"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func TestWordPressClient_Post(t *testing.T) {
+	wp := newFakeWordPress(t)
+	client := NewWordPressClient("blog", wp.server.URL+"/", "alice", "app pass word", nil)
+
+	result, err := client.Post(context.Background(), &Post{Content: "# My long memo\n\nFirst paragraph.\n\nSecond paragraph."})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"id": "42", "url": "https://blog.example.com/?p=42"}, result)
+
+	require.Len(t, wp.posts, 1)
+	assert.Equal(t, "My long memo", wp.posts[0].Title)
+	assert.Equal(t, "# My long memo\n\nFirst paragraph.\n\nSecond paragraph.", wp.posts[0].Content)
+	assert.Equal(t, "publish", wp.posts[0].Status)
+	assert.Zero(t, wp.posts[0].FeaturedMedia)
+	assert.Empty(t, wp.uploads)
+	assert.Equal(t, []string{"alice"}, wp.users)
+}
+
+func TestWordPressClient_PostVisibility(t *testing.T) {
+	wp := newFakeWordPress(t)
+	client := NewWordPressClient("blog", wp.server.URL, "alice", "app pass word", nil)
+
+	for _, visibility := range []VisibilityLevel{VisibilityLevelPrivate, VisibilityLevelUnlisted} {
+		_, err := client.Post(context.Background(), &Post{Content: "hello", Visibility: visibility})
+		require.NoError(t, err)
+	}
+	_, err := client.Post(context.Background(), &Post{Content: "hello", Visibility: VisibilityLevelDirect})
+	assert.Error(t, err)
+
+	require.Len(t, wp.posts, 2)
+	assert.Equal(t, "private", wp.posts[0].Status)
+	assert.Equal(t, "draft", wp.posts[1].Status)
+}
+
+func TestWordPressClient_PostMedia(t *testing.T) {
+	wp := newFakeWordPress(t)
+	client := NewWordPressClient("blog", wp.server.URL, "alice", "app pass word", nil)
+
+	second := NewMedia(pngHeader)
+	second.Description = `a "cat"`
+	_, err := client.Post(context.Background(), &Post{Content: "Photos", Media: []Media{*NewMedia(pngHeader), *second}})
+	require.NoError(t, err)
+
+	require.Len(t, wp.uploads, 2)
+	assert.Equal(t, "image/png", wp.uploads[0].contentType)
+	assert.Equal(t, `attachment; filename="media0.png"`, wp.uploads[0].contentDisposition)
+	assert.Equal(t, pngHeader, wp.uploads[0].data)
+
+	require.Len(t, wp.posts, 1)
+	assert.Equal(t, 101, wp.posts[0].FeaturedMedia, "the first upload is the featured image")
+	assert.True(t, strings.HasSuffix(wp.posts[0].Content,
+		`<img src="https://blog.example.com/uploads/102.png" alt="a &#34;cat&#34;" />`), wp.posts[0].Content)
+}
+
+func TestWordPressClient_PostUnauthorized(t *testing.T) {
+	wp := newFakeWordPress(t)
+	client := NewWordPressClient("blog", wp.server.URL, "alice", "wrong", nil)
+
+	_, err := client.Post(context.Background(), &Post{Content: "hello"})
+
+	platformErr, ok := AsPlatformError(err)
+	require.True(t, ok, "got %v", err)
+	assert.Equal(t, http.StatusUnauthorized, platformErr.StatusCode)
+	assert.Equal(t, "blog", platformErr.Platform)
+}
+
+func TestWordPressTitle(t *testing.T) {
+	assert.Equal(t, "Title", wordPressTitle("\n\n## Title  \nbody"))
+	assert.Equal(t, "", wordPressTitle(""))
+	long := strings.Repeat("长", wordPressTitleLimit+5)
+	title := wordPressTitle(long)
+	assert.Equal(t, wordPressTitleLimit, len([]rune(title)))
+	assert.True(t, strings.HasSuffix(title, "…"))
+}
@@ -0,0 +1,84 @@
+package telemetry
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HTTPTracerName is the tracer name for outgoing platform API calls
+const HTTPTracerName = "hypersync-http"
+
+// HTTP span attribute keys
+const (
+	AttrPlatform       = "hypersync.platform"
+	AttrHTTPMethod     = "http.request.method"
+	AttrURLPath        = "url.path"
+	AttrHTTPStatusCode = "http.response.status_code"
+)
+
+// HTTPTransport is an http.RoundTripper that wraps each request in a client
+// span, as a child of the span in the request context.
+type HTTPTransport struct {
+	// Base 实际发送请求的 RoundTripper，为空时使用 http.DefaultTransport
+	Base http.RoundTripper
+	// Platform 记录在 span 上的平台名
+	Platform string
+	// Path 返回记录到 span 的 url.path，用于去掉路径里的凭据（如 Telegram bot token）；
+	// 为空时使用 req.URL.Path
+	Path func(*http.Request) string
+	// Skip 返回 true 的请求不创建 span，如长轮询
+	Skip func(*http.Request) bool
+}
+
+// NewHTTPTransport creates an HTTPTransport for platform over base.
+func NewHTTPTransport(base http.RoundTripper, platform string) *HTTPTransport {
+	return &HTTPTransport{Base: base, Platform: platform}
+}
+
+// RoundTrip implements http.RoundTripper. Transport errors and non-2xx
+// responses mark the span as an error.
+func (t *HTTPTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Skip != nil && t.Skip(req) {
+		return base.RoundTrip(req)
+	}
+
+	path := req.URL.Path
+	if t.Path != nil {
+		path = t.Path(req)
+	}
+
+	// 每次请求时取全局 tracer，以便使用之后安装的 TracerProvider
+	ctx, span := otel.Tracer(HTTPTracerName).Start(req.Context(), fmt.Sprintf("%s %s", t.Platform, req.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String(AttrPlatform, t.Platform),
+			attribute.String(AttrHTTPMethod, req.Method),
+			attribute.String(AttrURLPath, path),
+		),
+	)
+	defer span.End()
+
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int(AttrHTTPStatusCode, resp.StatusCode))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		span.SetStatus(codes.Error, resp.Status)
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	return resp, nil
+}
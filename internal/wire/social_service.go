@@ -1,8 +1,11 @@
 package wire
 
 import (
+	"context"
 	"sync"
 
+	"butterfly.orx.me/core/log"
+
 	"go.orx.me/apps/hyper-sync/internal/conf"
 	"go.orx.me/apps/hyper-sync/internal/dao"
 	"go.orx.me/apps/hyper-sync/internal/media"
@@ -18,6 +21,9 @@ var (
 	schedulerServiceOnce     sync.Once
 	schedulerServiceInstance *service.SchedulerService
 	schedulerServiceInitErr  error
+
+	syncServicesMu sync.Mutex
+	syncServices   = make(map[string]*service.SyncService)
 )
 
 // GetSocialService returns a process-wide singleton SocialService. This
@@ -28,7 +34,11 @@ func GetSocialService() (*service.SocialService, error) {
 	socialServiceOnce.Do(func() {
 		client := dao.NewMongoClient()
 		socialConfigDao := dao.NewSocialConfigDao(client)
-		threadsConfigAdapter := dao.NewThreadsConfigAdapter(socialConfigDao)
+		var threadsConfigAdapter *dao.ThreadsConfigAdapter
+		threadsConfigAdapter, socialServiceInitErr = dao.NewThreadsConfigAdapter(socialConfigDao)
+		if socialServiceInitErr != nil {
+			return
+		}
 		syncCursorDao := dao.NewSyncCursorDao(client)
 
 		var objectStorage media.ObjectStorage
@@ -69,13 +79,60 @@ func GetSchedulerService() (*service.SchedulerService, error) {
 		}
 		client := dao.NewMongoClient()
 		socialConfigDao := dao.NewSocialConfigDao(client)
-		threadsConfigAdapter := dao.NewThreadsConfigAdapter(socialConfigDao)
-		locker := dao.NewLocker(dao.NewRedisClient())
+		threadsConfigAdapter, err := dao.NewThreadsConfigAdapter(socialConfigDao)
+		if err != nil {
+			schedulerServiceInitErr = err
+			return
+		}
+		locker := dao.NewLocker()
 		schedulerServiceInstance = service.NewSchedulerService(socialSvc, locker, threadsConfigAdapter)
 	})
 	return schedulerServiceInstance, schedulerServiceInitErr
 }
 
+// NewSyncService builds a SyncService for mainSocial -> socials backed by the
+// singleton SocialService, with the settings saved through the config API
+// applied. Every instance for the same mainSocial shares one distributed
+// lock, so an on-demand sync never overlaps the scheduled one.
+func NewSyncService(mainSocial string, socials []string) (*service.SyncService, error) {
+	socialSvc, err := GetSocialService()
+	if err != nil {
+		return nil, err
+	}
+	postDao := dao.NewPostDao(dao.NewMongoClient())
+	locker := dao.NewLocker()
+	syncService, err := service.NewSyncService(postDao, socialSvc, locker, mainSocial, socials)
+	if err != nil {
+		return nil, err
+	}
+	// 读取已保存的设置失败时沿用配置文件，不阻止同步启动
+	ctx := context.Background()
+	if err := syncService.LoadSettings(ctx, dao.NewSyncSettingsDao(dao.NewMongoClient())); err != nil {
+		log.FromContext(ctx).Error("Failed to load saved sync settings", "main_social", mainSocial, "error", err)
+	}
+	return syncService, nil
+}
+
+// GetSyncService returns the process-wide SyncService for mainSocial,
+// creating it with NewSyncService and a persistent sync watermark on first
+// use. The scheduled job, the manual sync and retry endpoints and the config
+// API share it, so a settings update or a paused target reaches every path.
+func GetSyncService(mainSocial string, socials []string) (*service.SyncService, error) {
+	syncServicesMu.Lock()
+	defer syncServicesMu.Unlock()
+	if syncService, ok := syncServices[mainSocial]; ok {
+		return syncService, nil
+	}
+
+	syncService, err := NewSyncService(mainSocial, socials)
+	if err != nil {
+		return nil, err
+	}
+	syncService.SetSyncStateDao(dao.NewSyncStateDao(dao.NewMongoClient()))
+	syncServices[mainSocial] = syncService
+	return syncService, nil
+}
+
 // CDNDomain helper used by callers that need the CDN domain separately.
 func CDNDomain() string {
 	if conf.Conf.Storage != nil && conf.Conf.Storage.S3 != nil {
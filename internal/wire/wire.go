@@ -10,22 +10,6 @@ import (
 	"go.orx.me/apps/hyper-sync/internal/social"
 )
 
-func NewSyncService(mainSocial string, socials []string) (*service.SyncService, error) {
-	panic(wire.Build(
-		dao.NewMongoClient,
-		dao.NewPostDao,
-		dao.NewSocialConfigDao,
-		dao.NewSyncCursorDao,
-		dao.NewThreadsConfigAdapter,
-		dao.NewLocker,
-		dao.NewRedisClient,
-		dao.NewObjectStorage,
-		service.NewSocialService,
-		service.NewSyncService,
-		wire.Bind(new(social.TokenManager), new(*dao.ThreadsConfigAdapter)),
-	))
-}
-
 func NewSchedulerService() (*service.SchedulerService, error) {
 	panic(wire.Build(
 		dao.NewMongoClient,
@@ -33,7 +17,6 @@ func NewSchedulerService() (*service.SchedulerService, error) {
 		dao.NewSyncCursorDao,
 		dao.NewThreadsConfigAdapter,
 		dao.NewLocker,
-		dao.NewRedisClient,
 		dao.NewObjectStorage,
 		service.NewSocialService,
 		service.NewSchedulerService,
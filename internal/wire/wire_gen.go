@@ -13,33 +13,13 @@ import (
 
 // Injectors from wire.go:
 
-func NewSyncService(mainSocial string, socials []string) (*service.SyncService, error) {
+func NewSchedulerService() (*service.SchedulerService, error) {
 	client := dao.NewMongoClient()
-	postDao := dao.NewPostDao(client)
 	socialConfigDao := dao.NewSocialConfigDao(client)
-	threadsConfigAdapter := dao.NewThreadsConfigAdapter(socialConfigDao)
-	syncCursorDao := dao.NewSyncCursorDao(client)
-	objectStorage, err := dao.NewObjectStorage()
-	if err != nil {
-		return nil, err
-	}
-	socialService, err := service.NewSocialService(threadsConfigAdapter, syncCursorDao, objectStorage)
-	if err != nil {
-		return nil, err
-	}
-	redisClient := dao.NewRedisClient()
-	redislockClient := dao.NewLocker(redisClient)
-	syncService, err := service.NewSyncService(postDao, socialService, redislockClient, mainSocial, socials)
+	threadsConfigAdapter, err := dao.NewThreadsConfigAdapter(socialConfigDao)
 	if err != nil {
 		return nil, err
 	}
-	return syncService, nil
-}
-
-func NewSchedulerService() (*service.SchedulerService, error) {
-	client := dao.NewMongoClient()
-	socialConfigDao := dao.NewSocialConfigDao(client)
-	threadsConfigAdapter := dao.NewThreadsConfigAdapter(socialConfigDao)
 	syncCursorDao := dao.NewSyncCursorDao(client)
 	objectStorage, err := dao.NewObjectStorage()
 	if err != nil {
@@ -49,16 +29,18 @@ func NewSchedulerService() (*service.SchedulerService, error) {
 	if err != nil {
 		return nil, err
 	}
-	redisClient := dao.NewRedisClient()
-	redislockClient := dao.NewLocker(redisClient)
-	schedulerService := service.NewSchedulerService(socialService, redislockClient, threadsConfigAdapter)
+	locker := dao.NewLocker()
+	schedulerService := service.NewSchedulerService(socialService, locker, threadsConfigAdapter)
 	return schedulerService, nil
 }
 
 func NewSocialServiceOnly() (*service.SocialService, error) {
 	client := dao.NewMongoClient()
 	socialConfigDao := dao.NewSocialConfigDao(client)
-	threadsConfigAdapter := dao.NewThreadsConfigAdapter(socialConfigDao)
+	threadsConfigAdapter, err := dao.NewThreadsConfigAdapter(socialConfigDao)
+	if err != nil {
+		return nil, err
+	}
 	syncCursorDao := dao.NewSyncCursorDao(client)
 	objectStorage, err := dao.NewObjectStorage()
 	if err != nil {
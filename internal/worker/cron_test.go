@@ -0,0 +1,120 @@
+package worker_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.orx.me/apps/hyper-sync/internal/worker"
+)
+
+func TestParseCron_EveryFiveMinutes(t *testing.T) {
+	schedule, err := worker.ParseCron("*/5 * * * *")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+
+	from := time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+	want := []time.Time{
+		time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC),
+		time.Date(2026, 3, 14, 9, 35, 0, 0, time.UTC),
+		time.Date(2026, 3, 14, 9, 40, 0, 0, time.UTC),
+	}
+	for _, w := range want {
+		next := schedule.Next(from)
+		if !next.Equal(w) {
+			t.Fatalf("Next(%s) = %s, want %s", from, next, w)
+		}
+		from = next
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// 2026-01-01 is a Thursday.
+	from := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 9 * * *", time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)},
+		{"30 8-10/2 * * *", time.Date(2026, 1, 2, 8, 30, 0, 0, time.UTC)},
+		{"0 0 * * mon", time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 mar *", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"15,45 12 * * *", time.Date(2026, 1, 1, 12, 15, 0, 0, time.UTC)},
+		// Both day fields restricted: either one matching is enough.
+		{"0 0 15 * fri", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := worker.ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron: %v", err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Fatalf("Next = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronSchedule_Next_NeverMatches(t *testing.T) {
+	schedule, err := worker.ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+	if next := schedule.Next(time.Now()); !next.IsZero() {
+		t.Fatalf("Next = %s, want zero time", next)
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"* * * *", "expected 5 fields"},
+		{"60 * * * *", "minute: 60 out of range"},
+		{"* 24 * * *", "hour: 24 out of range"},
+		{"* * 0 * *", "day of month: 0 out of range"},
+		{"* * * foo *", `month: invalid value "foo"`},
+		{"*/0 * * * *", "minute: invalid step"},
+		{"* 10-2 * * *", "backwards"},
+		{"@reboot", "expected 5 fields"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := worker.ParseCron(tt.expr)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error %q does not mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCron_AlreadyCancelled_NeverRunsFn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	schedule, err := worker.ParseCron("* * * * *")
+	if err != nil {
+		t.Fatalf("ParseCron: %v", err)
+	}
+
+	ran := false
+	worker.RunCron(ctx, schedule, func(context.Context) { ran = true })
+
+	if ran {
+		t.Fatal("fn must not run when shutdown has already started")
+	}
+}
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrShutdownTimeout is returned by Group.Shutdown when workers are still
+// running after the timeout.
+var ErrShutdownTimeout = errors.New("timed out waiting for workers to stop")
+
+// Group owns the context of a set of background workers and tracks them so
+// they can be stopped and drained together on shutdown.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewGroup creates a group whose workers stop when parent is cancelled or
+// Shutdown is called.
+func NewGroup(parent context.Context) *Group {
+	ctx, cancel := context.WithCancel(parent)
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Context is cancelled once shutdown starts.
+func (g *Group) Context() context.Context {
+	return g.ctx
+}
+
+// Go runs fn in a tracked goroutine with the group's context. fn must return
+// once the context is cancelled.
+func (g *Group) Go(fn func(context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn(g.ctx)
+	}()
+}
+
+// Loop runs fn immediately and then on every interval tick until shutdown,
+// see RunLoop.
+func (g *Group) Loop(interval time.Duration, fn func(context.Context)) {
+	g.Go(func(ctx context.Context) {
+		RunLoop(ctx, interval, fn)
+	})
+}
+
+// LoopAfter waits delay and then loops like Loop, see RunLoopAfter.
+func (g *Group) LoopAfter(delay, interval time.Duration, fn func(context.Context)) {
+	g.Go(func(ctx context.Context) {
+		RunLoopAfter(ctx, delay, interval, fn)
+	})
+}
+
+// Wait blocks until every worker has returned or ctx is done.
+func (g *Group) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ErrShutdownTimeout
+	}
+}
+
+// Shutdown cancels the group's context so no new iterations start, then
+// waits up to timeout for in-flight iterations to finish.
+func (g *Group) Shutdown(timeout time.Duration) error {
+	g.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return g.Wait(ctx)
+}
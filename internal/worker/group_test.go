@@ -0,0 +1,61 @@
+package worker_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.orx.me/apps/hyper-sync/internal/worker"
+)
+
+func TestGroup_ShutdownWaitsForInFlightIteration(t *testing.T) {
+	g := worker.NewGroup(context.Background())
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	g.Loop(time.Hour, func(context.Context) {
+		close(started)
+		// A sync that does not watch ctx still gets to finish.
+		time.Sleep(100 * time.Millisecond)
+		finished.Store(true)
+	})
+	<-started
+
+	begin := time.Now()
+	if err := g.Shutdown(2 * time.Second); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if !finished.Load() {
+		t.Fatal("Shutdown returned before the in-flight iteration finished")
+	}
+	if elapsed := time.Since(begin); elapsed >= 2*time.Second {
+		t.Fatalf("Shutdown took %v, want well under the timeout", elapsed)
+	}
+	if g.Context().Err() == nil {
+		t.Fatal("group context must be cancelled after Shutdown")
+	}
+}
+
+func TestGroup_ShutdownTimesOut(t *testing.T) {
+	g := worker.NewGroup(context.Background())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	g.Go(func(context.Context) {
+		close(started)
+		<-release
+	})
+	<-started
+
+	begin := time.Now()
+	err := g.Shutdown(50 * time.Millisecond)
+	if !errors.Is(err, worker.ErrShutdownTimeout) {
+		t.Fatalf("Shutdown error = %v, want ErrShutdownTimeout", err)
+	}
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Fatalf("Shutdown took %v, want it to give up after the timeout", elapsed)
+	}
+}
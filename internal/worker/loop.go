@@ -4,6 +4,7 @@ package worker
 
 import (
 	"context"
+	"math/rand/v2"
 	"time"
 )
 
@@ -27,3 +28,28 @@ func RunLoop(ctx context.Context, interval time.Duration, fn func(context.Contex
 		}
 	}
 }
+
+// RunLoopAfter waits delay, then behaves like RunLoop. It returns without
+// running fn if ctx is cancelled during the wait.
+func RunLoopAfter(ctx context.Context, delay, interval time.Duration, fn func(context.Context)) {
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+	}
+	RunLoop(ctx, interval, fn)
+}
+
+// Jitter returns a random delay in [0, interval) so loops started together
+// spread out instead of all firing at once.
+func Jitter(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
+	}
+	return rand.N(interval)
+}
@@ -61,3 +61,54 @@ func TestRunLoop_RunsImmediatelyAndStopsOnCancel(t *testing.T) {
 		t.Fatal("RunLoop did not return after ctx was cancelled")
 	}
 }
+
+func TestRunLoopAfter_DelaysFirstRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const delay = 50 * time.Millisecond
+	begin := time.Now()
+	calls := make(chan time.Time, 1)
+	go worker.RunLoopAfter(ctx, delay, time.Hour, func(context.Context) { calls <- time.Now() })
+
+	select {
+	case at := <-calls:
+		if elapsed := at.Sub(begin); elapsed < delay {
+			t.Fatalf("first run after %v, want at least %v", elapsed, delay)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("fn was not run after the initial delay")
+	}
+}
+
+func TestRunLoopAfter_CancelledDuringDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	ran := false
+	go func() {
+		worker.RunLoopAfter(ctx, time.Hour, time.Hour, func(context.Context) { ran = true })
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunLoopAfter did not return when cancelled during the delay")
+	}
+	if ran {
+		t.Fatal("fn must not run when cancelled during the delay")
+	}
+}
+
+func TestJitter_WithinInterval(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if d := worker.Jitter(30 * time.Second); d < 0 || d >= 30*time.Second {
+			t.Fatalf("Jitter(30s) = %v, want [0, 30s)", d)
+		}
+	}
+	if d := worker.Jitter(0); d != 0 {
+		t.Fatalf("Jitter(0) = %v, want 0", d)
+	}
+}
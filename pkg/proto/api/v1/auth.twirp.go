@@ -35,6 +35,16 @@ type AuthService interface {
 	Login(context.Context, *LoginRequest) (*LoginResponse, error)
 
 	ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error)
+
+	RefreshToken(context.Context, *RefreshTokenRequest) (*RefreshTokenResponse, error)
+
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+
+	LoginWithGithub(context.Context, *LoginWithGithubRequest) (*LoginResponse, error)
+
+	RequestPasswordReset(context.Context, *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error)
+
+	ConfirmPasswordReset(context.Context, *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error)
 }
 
 // ===========================
@@ -43,7 +53,7 @@ type AuthService interface {
 
 type authServiceProtobufClient struct {
 	client      HTTPClient
-	urls        [2]string
+	urls        [7]string
 	interceptor twirp.Interceptor
 	opts        twirp.ClientOptions
 }
@@ -71,9 +81,14 @@ func NewAuthServiceProtobufClient(baseURL string, client HTTPClient, opts ...twi
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
 	serviceURL := sanitizeBaseURL(baseURL)
 	serviceURL += baseServicePath(pathPrefix, "api.v1", "AuthService")
-	urls := [2]string{
+	urls := [7]string{
 		serviceURL + "Login",
 		serviceURL + "ChangePassword",
+		serviceURL + "RefreshToken",
+		serviceURL + "Logout",
+		serviceURL + "LoginWithGithub",
+		serviceURL + "RequestPasswordReset",
+		serviceURL + "ConfirmPasswordReset",
 	}
 
 	return &authServiceProtobufClient{
@@ -176,13 +191,243 @@ func (c *authServiceProtobufClient) callChangePassword(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *authServiceProtobufClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "RefreshToken")
+	caller := c.callRefreshToken
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*RefreshTokenRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*RefreshTokenRequest) when calling interceptor")
+					}
+					return c.callRefreshToken(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*RefreshTokenResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*RefreshTokenResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceProtobufClient) callRefreshToken(ctx context.Context, in *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	out := new(RefreshTokenResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[2], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceProtobufClient) Logout(ctx context.Context, in *LogoutRequest) (*LogoutResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "Logout")
+	caller := c.callLogout
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*LogoutRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*LogoutRequest) when calling interceptor")
+					}
+					return c.callLogout(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*LogoutResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*LogoutResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceProtobufClient) callLogout(ctx context.Context, in *LogoutRequest) (*LogoutResponse, error) {
+	out := new(LogoutResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[3], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceProtobufClient) LoginWithGithub(ctx context.Context, in *LoginWithGithubRequest) (*LoginResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "LoginWithGithub")
+	caller := c.callLoginWithGithub
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *LoginWithGithubRequest) (*LoginResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*LoginWithGithubRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*LoginWithGithubRequest) when calling interceptor")
+					}
+					return c.callLoginWithGithub(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*LoginResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*LoginResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceProtobufClient) callLoginWithGithub(ctx context.Context, in *LoginWithGithubRequest) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[4], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceProtobufClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "RequestPasswordReset")
+	caller := c.callRequestPasswordReset
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*RequestPasswordResetRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*RequestPasswordResetRequest) when calling interceptor")
+					}
+					return c.callRequestPasswordReset(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*RequestPasswordResetResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*RequestPasswordResetResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceProtobufClient) callRequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+	out := new(RequestPasswordResetResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[5], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceProtobufClient) ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "ConfirmPasswordReset")
+	caller := c.callConfirmPasswordReset
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ConfirmPasswordResetRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ConfirmPasswordResetRequest) when calling interceptor")
+					}
+					return c.callConfirmPasswordReset(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ConfirmPasswordResetResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ConfirmPasswordResetResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceProtobufClient) callConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
+	out := new(ConfirmPasswordResetResponse)
+	ctx, err := doProtobufRequest(ctx, c.client, c.opts.Hooks, c.urls[6], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
 // =======================
 // AuthService JSON Client
 // =======================
 
 type authServiceJSONClient struct {
 	client      HTTPClient
-	urls        [2]string
+	urls        [7]string
 	interceptor twirp.Interceptor
 	opts        twirp.ClientOptions
 }
@@ -210,9 +455,14 @@ func NewAuthServiceJSONClient(baseURL string, client HTTPClient, opts ...twirp.C
 	// Build method URLs: <baseURL>[<prefix>]/<package>.<Service>/<Method>
 	serviceURL := sanitizeBaseURL(baseURL)
 	serviceURL += baseServicePath(pathPrefix, "api.v1", "AuthService")
-	urls := [2]string{
+	urls := [7]string{
 		serviceURL + "Login",
 		serviceURL + "ChangePassword",
+		serviceURL + "RefreshToken",
+		serviceURL + "Logout",
+		serviceURL + "LoginWithGithub",
+		serviceURL + "RequestPasswordReset",
+		serviceURL + "ConfirmPasswordReset",
 	}
 
 	return &authServiceJSONClient{
@@ -264,168 +514,1313 @@ func (c *authServiceJSONClient) callLogin(ctx context.Context, in *LoginRequest)
 		return nil, err
 	}
 
-	callClientResponseReceived(ctx, c.opts.Hooks)
-
-	return out, nil
-}
-
-func (c *authServiceJSONClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest) (*ChangePasswordResponse, error) {
-	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
-	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
-	ctx = ctxsetters.WithMethodName(ctx, "ChangePassword")
-	caller := c.callChangePassword
-	if c.interceptor != nil {
-		caller = func(ctx context.Context, req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
-			resp, err := c.interceptor(
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceJSONClient) ChangePassword(ctx context.Context, in *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "ChangePassword")
+	caller := c.callChangePassword
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ChangePasswordRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ChangePasswordRequest) when calling interceptor")
+					}
+					return c.callChangePassword(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ChangePasswordResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ChangePasswordResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceJSONClient) callChangePassword(ctx context.Context, in *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+	out := new(ChangePasswordResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceJSONClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "RefreshToken")
+	caller := c.callRefreshToken
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*RefreshTokenRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*RefreshTokenRequest) when calling interceptor")
+					}
+					return c.callRefreshToken(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*RefreshTokenResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*RefreshTokenResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceJSONClient) callRefreshToken(ctx context.Context, in *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+	out := new(RefreshTokenResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[2], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceJSONClient) Logout(ctx context.Context, in *LogoutRequest) (*LogoutResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "Logout")
+	caller := c.callLogout
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*LogoutRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*LogoutRequest) when calling interceptor")
+					}
+					return c.callLogout(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*LogoutResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*LogoutResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceJSONClient) callLogout(ctx context.Context, in *LogoutRequest) (*LogoutResponse, error) {
+	out := new(LogoutResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[3], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceJSONClient) LoginWithGithub(ctx context.Context, in *LoginWithGithubRequest) (*LoginResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "LoginWithGithub")
+	caller := c.callLoginWithGithub
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *LoginWithGithubRequest) (*LoginResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*LoginWithGithubRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*LoginWithGithubRequest) when calling interceptor")
+					}
+					return c.callLoginWithGithub(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*LoginResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*LoginResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceJSONClient) callLoginWithGithub(ctx context.Context, in *LoginWithGithubRequest) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[4], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceJSONClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "RequestPasswordReset")
+	caller := c.callRequestPasswordReset
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*RequestPasswordResetRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*RequestPasswordResetRequest) when calling interceptor")
+					}
+					return c.callRequestPasswordReset(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*RequestPasswordResetResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*RequestPasswordResetResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceJSONClient) callRequestPasswordReset(ctx context.Context, in *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
+	out := new(RequestPasswordResetResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[5], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+func (c *authServiceJSONClient) ConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithMethodName(ctx, "ConfirmPasswordReset")
+	caller := c.callConfirmPasswordReset
+	if c.interceptor != nil {
+		caller = func(ctx context.Context, req *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
+			resp, err := c.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ConfirmPasswordResetRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ConfirmPasswordResetRequest) when calling interceptor")
+					}
+					return c.callConfirmPasswordReset(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ConfirmPasswordResetResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ConfirmPasswordResetResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+	return caller(ctx, in)
+}
+
+func (c *authServiceJSONClient) callConfirmPasswordReset(ctx context.Context, in *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
+	out := new(ConfirmPasswordResetResponse)
+	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[6], in, out)
+	if err != nil {
+		twerr, ok := err.(twirp.Error)
+		if !ok {
+			twerr = twirp.InternalErrorWith(err)
+		}
+		callClientError(ctx, c.opts.Hooks, twerr)
+		return nil, err
+	}
+
+	callClientResponseReceived(ctx, c.opts.Hooks)
+
+	return out, nil
+}
+
+// ==========================
+// AuthService Server Handler
+// ==========================
+
+type authServiceServer struct {
+	AuthService
+	interceptor      twirp.Interceptor
+	hooks            *twirp.ServerHooks
+	pathPrefix       string // prefix for routing
+	jsonSkipDefaults bool   // do not include unpopulated fields (default values) in the response
+	jsonCamelCase    bool   // JSON fields are serialized as lowerCamelCase rather than keeping the original proto names
+}
+
+// NewAuthServiceServer builds a TwirpServer that can be used as an http.Handler to handle
+// HTTP requests that are routed to the right method in the provided svc implementation.
+// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
+func NewAuthServiceServer(svc AuthService, opts ...interface{}) TwirpServer {
+	serverOpts := newServerOpts(opts)
+
+	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
+	jsonSkipDefaults := false
+	_ = serverOpts.ReadOpt("jsonSkipDefaults", &jsonSkipDefaults)
+	jsonCamelCase := false
+	_ = serverOpts.ReadOpt("jsonCamelCase", &jsonCamelCase)
+	var pathPrefix string
+	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
+		pathPrefix = "/twirp" // default prefix
+	}
+
+	return &authServiceServer{
+		AuthService:      svc,
+		hooks:            serverOpts.Hooks,
+		interceptor:      twirp.ChainInterceptors(serverOpts.Interceptors...),
+		pathPrefix:       pathPrefix,
+		jsonSkipDefaults: jsonSkipDefaults,
+		jsonCamelCase:    jsonCamelCase,
+	}
+}
+
+// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
+// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
+func (s *authServiceServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
+	writeError(ctx, resp, err, s.hooks)
+}
+
+// handleRequestBodyError is used to handle error when the twirp server cannot read request
+func (s *authServiceServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
+	if context.Canceled == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
+		return
+	}
+	if context.DeadlineExceeded == ctx.Err() {
+		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
+		return
+	}
+	s.writeError(ctx, resp, twirp.WrapError(malformedRequestError(msg), err))
+}
+
+// AuthServicePathPrefix is a convenience constant that may identify URL paths.
+// Should be used with caution, it only matches routes generated by Twirp Go clients,
+// with the default "/twirp" prefix and default CamelCase service and method names.
+// More info: https://twitchtv.github.io/twirp/docs/routing.html
+const AuthServicePathPrefix = "/twirp/api.v1.AuthService/"
+
+func (s *authServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
+	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
+	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+
+	var err error
+	ctx, err = callRequestReceived(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	if req.Method != "POST" {
+		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
+		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
+	prefix, pkgService, method := parseTwirpPath(req.URL.Path)
+	if pkgService != "api.v1.AuthService" {
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+	if prefix != s.pathPrefix {
+		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
+		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+
+	switch method {
+	case "Login":
+		s.serveLogin(ctx, resp, req)
+		return
+	case "ChangePassword":
+		s.serveChangePassword(ctx, resp, req)
+		return
+	case "RefreshToken":
+		s.serveRefreshToken(ctx, resp, req)
+		return
+	case "Logout":
+		s.serveLogout(ctx, resp, req)
+		return
+	case "LoginWithGithub":
+		s.serveLoginWithGithub(ctx, resp, req)
+		return
+	case "RequestPasswordReset":
+		s.serveRequestPasswordReset(ctx, resp, req)
+		return
+	case "ConfirmPasswordReset":
+		s.serveConfirmPasswordReset(ctx, resp, req)
+		return
+	default:
+		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
+		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+		return
+	}
+}
+
+func (s *authServiceServer) serveLogin(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveLoginJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveLoginProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *authServiceServer) serveLoginJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "Login")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(LoginRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.AuthService.Login
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*LoginRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*LoginRequest) when calling interceptor")
+					}
+					return s.AuthService.Login(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*LoginResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*LoginResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *LoginResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LoginResponse and nil error while calling Login. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *authServiceServer) serveLoginProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "Login")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(LoginRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.AuthService.Login
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*LoginRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*LoginRequest) when calling interceptor")
+					}
+					return s.AuthService.Login(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*LoginResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*LoginResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *LoginResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LoginResponse and nil error while calling Login. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *authServiceServer) serveChangePassword(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveChangePasswordJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveChangePasswordProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *authServiceServer) serveChangePasswordJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ChangePassword")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(ChangePasswordRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.AuthService.ChangePassword
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ChangePasswordRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ChangePasswordRequest) when calling interceptor")
+					}
+					return s.AuthService.ChangePassword(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ChangePasswordResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ChangePasswordResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *ChangePasswordResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ChangePasswordResponse and nil error while calling ChangePassword. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *authServiceServer) serveChangePasswordProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ChangePassword")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(ChangePasswordRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.AuthService.ChangePassword
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*ChangePasswordRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*ChangePasswordRequest) when calling interceptor")
+					}
+					return s.AuthService.ChangePassword(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*ChangePasswordResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*ChangePasswordResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *ChangePasswordResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ChangePasswordResponse and nil error while calling ChangePassword. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *authServiceServer) serveRefreshToken(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveRefreshTokenJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveRefreshTokenProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *authServiceServer) serveRefreshTokenJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RefreshToken")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(RefreshTokenRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.AuthService.RefreshToken
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*RefreshTokenRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*RefreshTokenRequest) when calling interceptor")
+					}
+					return s.AuthService.RefreshToken(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*RefreshTokenResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*RefreshTokenResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *RefreshTokenResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RefreshTokenResponse and nil error while calling RefreshToken. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *authServiceServer) serveRefreshTokenProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RefreshToken")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(RefreshTokenRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.AuthService.RefreshToken
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *RefreshTokenRequest) (*RefreshTokenResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*RefreshTokenRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*RefreshTokenRequest) when calling interceptor")
+					}
+					return s.AuthService.RefreshToken(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*RefreshTokenResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*RefreshTokenResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *RefreshTokenResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RefreshTokenResponse and nil error while calling RefreshToken. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *authServiceServer) serveLogout(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveLogoutJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveLogoutProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *authServiceServer) serveLogoutJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "Logout")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(LogoutRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.AuthService.Logout
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*LogoutRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*LogoutRequest) when calling interceptor")
+					}
+					return s.AuthService.Logout(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*LogoutResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*LogoutResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *LogoutResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LogoutResponse and nil error while calling Logout. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *authServiceServer) serveLogoutProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "Logout")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
+		return
+	}
+	reqContent := new(LogoutRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
+		return
+	}
+
+	handler := s.AuthService.Logout
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*LogoutRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*LogoutRequest) when calling interceptor")
+					}
+					return s.AuthService.Logout(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*LogoutResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*LogoutResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
+
+	// Call service method
+	var respContent *LogoutResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LogoutResponse and nil error while calling Logout. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *authServiceServer) serveLoginWithGithub(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveLoginWithGithubJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveLoginWithGithubProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *authServiceServer) serveLoginWithGithubJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "LoginWithGithub")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	d := json.NewDecoder(req.Body)
+	rawReqBody := json.RawMessage{}
+	if err := d.Decode(&rawReqBody); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+	reqContent := new(LoginWithGithubRequest)
+	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
+		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
+		return
+	}
+
+	handler := s.AuthService.LoginWithGithub
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *LoginWithGithubRequest) (*LoginResponse, error) {
+			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*ChangePasswordRequest)
+					typedReq, ok := req.(*LoginWithGithubRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*ChangePasswordRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*LoginWithGithubRequest) when calling interceptor")
 					}
-					return c.callChangePassword(ctx, typedReq)
+					return s.AuthService.LoginWithGithub(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*ChangePasswordResponse)
+				typedResp, ok := resp.(*LoginResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*ChangePasswordResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*LoginResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
 			return nil, err
 		}
 	}
-	return caller(ctx, in)
-}
 
-func (c *authServiceJSONClient) callChangePassword(ctx context.Context, in *ChangePasswordRequest) (*ChangePasswordResponse, error) {
-	out := new(ChangePasswordResponse)
-	ctx, err := doJSONRequest(ctx, c.client, c.opts.Hooks, c.urls[1], in, out)
+	// Call service method
+	var respContent *LoginResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
+
 	if err != nil {
-		twerr, ok := err.(twirp.Error)
-		if !ok {
-			twerr = twirp.InternalErrorWith(err)
-		}
-		callClientError(ctx, c.opts.Hooks, twerr)
-		return nil, err
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LoginResponse and nil error while calling LoginWithGithub. nil responses are not supported"))
+		return
 	}
 
-	callClientResponseReceived(ctx, c.opts.Hooks)
-
-	return out, nil
-}
-
-// ==========================
-// AuthService Server Handler
-// ==========================
-
-type authServiceServer struct {
-	AuthService
-	interceptor      twirp.Interceptor
-	hooks            *twirp.ServerHooks
-	pathPrefix       string // prefix for routing
-	jsonSkipDefaults bool   // do not include unpopulated fields (default values) in the response
-	jsonCamelCase    bool   // JSON fields are serialized as lowerCamelCase rather than keeping the original proto names
-}
-
-// NewAuthServiceServer builds a TwirpServer that can be used as an http.Handler to handle
-// HTTP requests that are routed to the right method in the provided svc implementation.
-// The opts are twirp.ServerOption modifiers, for example twirp.WithServerHooks(hooks).
-func NewAuthServiceServer(svc AuthService, opts ...interface{}) TwirpServer {
-	serverOpts := newServerOpts(opts)
+	ctx = callResponsePrepared(ctx, s.hooks)
 
-	// Using ReadOpt allows backwards and forwards compatibility with new options in the future
-	jsonSkipDefaults := false
-	_ = serverOpts.ReadOpt("jsonSkipDefaults", &jsonSkipDefaults)
-	jsonCamelCase := false
-	_ = serverOpts.ReadOpt("jsonCamelCase", &jsonCamelCase)
-	var pathPrefix string
-	if ok := serverOpts.ReadOpt("pathPrefix", &pathPrefix); !ok {
-		pathPrefix = "/twirp" // default prefix
+	marshaler := &protojson.MarshalOptions{UseProtoNames: !s.jsonCamelCase, EmitUnpopulated: !s.jsonSkipDefaults}
+	respBytes, err := marshaler.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal json response"))
+		return
 	}
 
-	return &authServiceServer{
-		AuthService:      svc,
-		hooks:            serverOpts.Hooks,
-		interceptor:      twirp.ChainInterceptors(serverOpts.Interceptors...),
-		pathPrefix:       pathPrefix,
-		jsonSkipDefaults: jsonSkipDefaults,
-		jsonCamelCase:    jsonCamelCase,
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
 	}
+	callResponseSent(ctx, s.hooks)
 }
 
-// writeError writes an HTTP response with a valid Twirp error format, and triggers hooks.
-// If err is not a twirp.Error, it will get wrapped with twirp.InternalErrorWith(err)
-func (s *authServiceServer) writeError(ctx context.Context, resp http.ResponseWriter, err error) {
-	writeError(ctx, resp, err, s.hooks)
-}
+func (s *authServiceServer) serveLoginWithGithubProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "LoginWithGithub")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
 
-// handleRequestBodyError is used to handle error when the twirp server cannot read request
-func (s *authServiceServer) handleRequestBodyError(ctx context.Context, resp http.ResponseWriter, msg string, err error) {
-	if context.Canceled == ctx.Err() {
-		s.writeError(ctx, resp, twirp.NewError(twirp.Canceled, "failed to read request: context canceled"))
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
 		return
 	}
-	if context.DeadlineExceeded == ctx.Err() {
-		s.writeError(ctx, resp, twirp.NewError(twirp.DeadlineExceeded, "failed to read request: deadline exceeded"))
+	reqContent := new(LoginWithGithubRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
 		return
 	}
-	s.writeError(ctx, resp, twirp.WrapError(malformedRequestError(msg), err))
-}
 
-// AuthServicePathPrefix is a convenience constant that may identify URL paths.
-// Should be used with caution, it only matches routes generated by Twirp Go clients,
-// with the default "/twirp" prefix and default CamelCase service and method names.
-// More info: https://twitchtv.github.io/twirp/docs/routing.html
-const AuthServicePathPrefix = "/twirp/api.v1.AuthService/"
+	handler := s.AuthService.LoginWithGithub
+	if s.interceptor != nil {
+		handler = func(ctx context.Context, req *LoginWithGithubRequest) (*LoginResponse, error) {
+			resp, err := s.interceptor(
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					typedReq, ok := req.(*LoginWithGithubRequest)
+					if !ok {
+						return nil, twirp.InternalError("failed type assertion req.(*LoginWithGithubRequest) when calling interceptor")
+					}
+					return s.AuthService.LoginWithGithub(ctx, typedReq)
+				},
+			)(ctx, req)
+			if resp != nil {
+				typedResp, ok := resp.(*LoginResponse)
+				if !ok {
+					return nil, twirp.InternalError("failed type assertion resp.(*LoginResponse) when calling interceptor")
+				}
+				return typedResp, err
+			}
+			return nil, err
+		}
+	}
 
-func (s *authServiceServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
-	ctx := req.Context()
-	ctx = ctxsetters.WithPackageName(ctx, "api.v1")
-	ctx = ctxsetters.WithServiceName(ctx, "AuthService")
-	ctx = ctxsetters.WithResponseWriter(ctx, resp)
+	// Call service method
+	var respContent *LoginResponse
+	func() {
+		defer ensurePanicResponses(ctx, resp, s.hooks)
+		respContent, err = handler(ctx, reqContent)
+	}()
 
-	var err error
-	ctx, err = callRequestReceived(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
 		return
 	}
-
-	if req.Method != "POST" {
-		msg := fmt.Sprintf("unsupported method %q (only POST is allowed)", req.Method)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LoginResponse and nil error while calling LoginWithGithub. nil responses are not supported"))
 		return
 	}
 
-	// Verify path format: [<prefix>]/<package>.<Service>/<Method>
-	prefix, pkgService, method := parseTwirpPath(req.URL.Path)
-	if pkgService != "api.v1.AuthService" {
-		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
-		return
-	}
-	if prefix != s.pathPrefix {
-		msg := fmt.Sprintf("invalid path prefix %q, expected %q, on path %q", prefix, s.pathPrefix, req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		s.writeError(ctx, resp, wrapInternal(err, "failed to marshal proto response"))
 		return
 	}
 
-	switch method {
-	case "Login":
-		s.serveLogin(ctx, resp, req)
-		return
-	case "ChangePassword":
-		s.serveChangePassword(ctx, resp, req)
-		return
-	default:
-		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
-		s.writeError(ctx, resp, badRouteError(msg, req.Method, req.URL.Path))
-		return
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.Header().Set("Content-Length", strconv.Itoa(len(respBytes)))
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		ctx = callError(ctx, s.hooks, twerr)
 	}
+	callResponseSent(ctx, s.hooks)
 }
 
-func (s *authServiceServer) serveLogin(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *authServiceServer) serveRequestPasswordReset(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	header := req.Header.Get("Content-Type")
 	i := strings.Index(header, ";")
 	if i == -1 {
@@ -433,9 +1828,9 @@ func (s *authServiceServer) serveLogin(ctx context.Context, resp http.ResponseWr
 	}
 	switch strings.TrimSpace(strings.ToLower(header[:i])) {
 	case "application/json":
-		s.serveLoginJSON(ctx, resp, req)
+		s.serveRequestPasswordResetJSON(ctx, resp, req)
 	case "application/protobuf":
-		s.serveLoginProtobuf(ctx, resp, req)
+		s.serveRequestPasswordResetProtobuf(ctx, resp, req)
 	default:
 		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
 		twerr := badRouteError(msg, req.Method, req.URL.Path)
@@ -443,9 +1838,9 @@ func (s *authServiceServer) serveLogin(ctx context.Context, resp http.ResponseWr
 	}
 }
 
-func (s *authServiceServer) serveLoginJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *authServiceServer) serveRequestPasswordResetJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "Login")
+	ctx = ctxsetters.WithMethodName(ctx, "RequestPasswordReset")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -458,29 +1853,29 @@ func (s *authServiceServer) serveLoginJSON(ctx context.Context, resp http.Respon
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
-	reqContent := new(LoginRequest)
+	reqContent := new(RequestPasswordResetRequest)
 	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
 	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
 
-	handler := s.AuthService.Login
+	handler := s.AuthService.RequestPasswordReset
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+		handler = func(ctx context.Context, req *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*LoginRequest)
+					typedReq, ok := req.(*RequestPasswordResetRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*LoginRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*RequestPasswordResetRequest) when calling interceptor")
 					}
-					return s.AuthService.Login(ctx, typedReq)
+					return s.AuthService.RequestPasswordReset(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*LoginResponse)
+				typedResp, ok := resp.(*RequestPasswordResetResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*LoginResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*RequestPasswordResetResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -489,7 +1884,7 @@ func (s *authServiceServer) serveLoginJSON(ctx context.Context, resp http.Respon
 	}
 
 	// Call service method
-	var respContent *LoginResponse
+	var respContent *RequestPasswordResetResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -500,7 +1895,7 @@ func (s *authServiceServer) serveLoginJSON(ctx context.Context, resp http.Respon
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *LoginResponse and nil error while calling Login. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RequestPasswordResetResponse and nil error while calling RequestPasswordReset. nil responses are not supported"))
 		return
 	}
 
@@ -526,9 +1921,9 @@ func (s *authServiceServer) serveLoginJSON(ctx context.Context, resp http.Respon
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *authServiceServer) serveLoginProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *authServiceServer) serveRequestPasswordResetProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "Login")
+	ctx = ctxsetters.WithMethodName(ctx, "RequestPasswordReset")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -540,28 +1935,28 @@ func (s *authServiceServer) serveLoginProtobuf(ctx context.Context, resp http.Re
 		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
 		return
 	}
-	reqContent := new(LoginRequest)
+	reqContent := new(RequestPasswordResetRequest)
 	if err = proto.Unmarshal(buf, reqContent); err != nil {
 		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
 		return
 	}
 
-	handler := s.AuthService.Login
+	handler := s.AuthService.RequestPasswordReset
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+		handler = func(ctx context.Context, req *RequestPasswordResetRequest) (*RequestPasswordResetResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*LoginRequest)
+					typedReq, ok := req.(*RequestPasswordResetRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*LoginRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*RequestPasswordResetRequest) when calling interceptor")
 					}
-					return s.AuthService.Login(ctx, typedReq)
+					return s.AuthService.RequestPasswordReset(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*LoginResponse)
+				typedResp, ok := resp.(*RequestPasswordResetResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*LoginResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*RequestPasswordResetResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -570,7 +1965,7 @@ func (s *authServiceServer) serveLoginProtobuf(ctx context.Context, resp http.Re
 	}
 
 	// Call service method
-	var respContent *LoginResponse
+	var respContent *RequestPasswordResetResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -581,7 +1976,7 @@ func (s *authServiceServer) serveLoginProtobuf(ctx context.Context, resp http.Re
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *LoginResponse and nil error while calling Login. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RequestPasswordResetResponse and nil error while calling RequestPasswordReset. nil responses are not supported"))
 		return
 	}
 
@@ -605,7 +2000,7 @@ func (s *authServiceServer) serveLoginProtobuf(ctx context.Context, resp http.Re
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *authServiceServer) serveChangePassword(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *authServiceServer) serveConfirmPasswordReset(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	header := req.Header.Get("Content-Type")
 	i := strings.Index(header, ";")
 	if i == -1 {
@@ -613,9 +2008,9 @@ func (s *authServiceServer) serveChangePassword(ctx context.Context, resp http.R
 	}
 	switch strings.TrimSpace(strings.ToLower(header[:i])) {
 	case "application/json":
-		s.serveChangePasswordJSON(ctx, resp, req)
+		s.serveConfirmPasswordResetJSON(ctx, resp, req)
 	case "application/protobuf":
-		s.serveChangePasswordProtobuf(ctx, resp, req)
+		s.serveConfirmPasswordResetProtobuf(ctx, resp, req)
 	default:
 		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
 		twerr := badRouteError(msg, req.Method, req.URL.Path)
@@ -623,9 +2018,9 @@ func (s *authServiceServer) serveChangePassword(ctx context.Context, resp http.R
 	}
 }
 
-func (s *authServiceServer) serveChangePasswordJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *authServiceServer) serveConfirmPasswordResetJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "ChangePassword")
+	ctx = ctxsetters.WithMethodName(ctx, "ConfirmPasswordReset")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -638,29 +2033,29 @@ func (s *authServiceServer) serveChangePasswordJSON(ctx context.Context, resp ht
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
-	reqContent := new(ChangePasswordRequest)
+	reqContent := new(ConfirmPasswordResetRequest)
 	unmarshaler := protojson.UnmarshalOptions{DiscardUnknown: true}
 	if err = unmarshaler.Unmarshal(rawReqBody, reqContent); err != nil {
 		s.handleRequestBodyError(ctx, resp, "the json request could not be decoded", err)
 		return
 	}
 
-	handler := s.AuthService.ChangePassword
+	handler := s.AuthService.ConfirmPasswordReset
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+		handler = func(ctx context.Context, req *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*ChangePasswordRequest)
+					typedReq, ok := req.(*ConfirmPasswordResetRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*ChangePasswordRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*ConfirmPasswordResetRequest) when calling interceptor")
 					}
-					return s.AuthService.ChangePassword(ctx, typedReq)
+					return s.AuthService.ConfirmPasswordReset(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*ChangePasswordResponse)
+				typedResp, ok := resp.(*ConfirmPasswordResetResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*ChangePasswordResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*ConfirmPasswordResetResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -669,7 +2064,7 @@ func (s *authServiceServer) serveChangePasswordJSON(ctx context.Context, resp ht
 	}
 
 	// Call service method
-	var respContent *ChangePasswordResponse
+	var respContent *ConfirmPasswordResetResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -680,7 +2075,7 @@ func (s *authServiceServer) serveChangePasswordJSON(ctx context.Context, resp ht
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *ChangePasswordResponse and nil error while calling ChangePassword. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ConfirmPasswordResetResponse and nil error while calling ConfirmPasswordReset. nil responses are not supported"))
 		return
 	}
 
@@ -706,9 +2101,9 @@ func (s *authServiceServer) serveChangePasswordJSON(ctx context.Context, resp ht
 	callResponseSent(ctx, s.hooks)
 }
 
-func (s *authServiceServer) serveChangePasswordProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+func (s *authServiceServer) serveConfirmPasswordResetProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
 	var err error
-	ctx = ctxsetters.WithMethodName(ctx, "ChangePassword")
+	ctx = ctxsetters.WithMethodName(ctx, "ConfirmPasswordReset")
 	ctx, err = callRequestRouted(ctx, s.hooks)
 	if err != nil {
 		s.writeError(ctx, resp, err)
@@ -720,28 +2115,28 @@ func (s *authServiceServer) serveChangePasswordProtobuf(ctx context.Context, res
 		s.handleRequestBodyError(ctx, resp, "failed to read request body", err)
 		return
 	}
-	reqContent := new(ChangePasswordRequest)
+	reqContent := new(ConfirmPasswordResetRequest)
 	if err = proto.Unmarshal(buf, reqContent); err != nil {
 		s.writeError(ctx, resp, malformedRequestError("the protobuf request could not be decoded"))
 		return
 	}
 
-	handler := s.AuthService.ChangePassword
+	handler := s.AuthService.ConfirmPasswordReset
 	if s.interceptor != nil {
-		handler = func(ctx context.Context, req *ChangePasswordRequest) (*ChangePasswordResponse, error) {
+		handler = func(ctx context.Context, req *ConfirmPasswordResetRequest) (*ConfirmPasswordResetResponse, error) {
 			resp, err := s.interceptor(
 				func(ctx context.Context, req interface{}) (interface{}, error) {
-					typedReq, ok := req.(*ChangePasswordRequest)
+					typedReq, ok := req.(*ConfirmPasswordResetRequest)
 					if !ok {
-						return nil, twirp.InternalError("failed type assertion req.(*ChangePasswordRequest) when calling interceptor")
+						return nil, twirp.InternalError("failed type assertion req.(*ConfirmPasswordResetRequest) when calling interceptor")
 					}
-					return s.AuthService.ChangePassword(ctx, typedReq)
+					return s.AuthService.ConfirmPasswordReset(ctx, typedReq)
 				},
 			)(ctx, req)
 			if resp != nil {
-				typedResp, ok := resp.(*ChangePasswordResponse)
+				typedResp, ok := resp.(*ConfirmPasswordResetResponse)
 				if !ok {
-					return nil, twirp.InternalError("failed type assertion resp.(*ChangePasswordResponse) when calling interceptor")
+					return nil, twirp.InternalError("failed type assertion resp.(*ConfirmPasswordResetResponse) when calling interceptor")
 				}
 				return typedResp, err
 			}
@@ -750,7 +2145,7 @@ func (s *authServiceServer) serveChangePasswordProtobuf(ctx context.Context, res
 	}
 
 	// Call service method
-	var respContent *ChangePasswordResponse
+	var respContent *ConfirmPasswordResetResponse
 	func() {
 		defer ensurePanicResponses(ctx, resp, s.hooks)
 		respContent, err = handler(ctx, reqContent)
@@ -761,7 +2156,7 @@ func (s *authServiceServer) serveChangePasswordProtobuf(ctx context.Context, res
 		return
 	}
 	if respContent == nil {
-		s.writeError(ctx, resp, twirp.InternalError("received a nil *ChangePasswordResponse and nil error while calling ChangePassword. nil responses are not supported"))
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ConfirmPasswordResetResponse and nil error while calling ConfirmPasswordReset. nil responses are not supported"))
 		return
 	}
 
@@ -1366,24 +2761,39 @@ func callClientError(ctx context.Context, h *twirp.ClientHooks, err twirp.Error)
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 300 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0x4f, 0x4b, 0x03, 0x31,
-	0x10, 0xc5, 0xad, 0xd2, 0x62, 0xa7, 0xf5, 0x5f, 0x68, 0xa5, 0x14, 0x2a, 0xba, 0x27, 0x15, 0x4d,
-	0xa8, 0x82, 0xf7, 0xaa, 0x78, 0xf2, 0xa0, 0xeb, 0xcd, 0xcb, 0x12, 0xd7, 0x61, 0x77, 0x29, 0x4d,
-	0x62, 0x92, 0xdd, 0x6d, 0xbf, 0x89, 0x1f, 0x57, 0xdc, 0x6c, 0x8a, 0x2e, 0x7a, 0x7c, 0xf3, 0x92,
-	0xdf, 0xbc, 0x99, 0x81, 0x03, 0xae, 0x32, 0x56, 0x4c, 0x19, 0xcf, 0x6d, 0x4a, 0x95, 0x96, 0x56,
-	0x92, 0x0e, 0x57, 0x19, 0x2d, 0xa6, 0xc1, 0x03, 0xf4, 0x1f, 0x65, 0x92, 0x89, 0x10, 0x3f, 0x72,
-	0x34, 0x96, 0x8c, 0x61, 0x3b, 0x37, 0xa8, 0x05, 0x5f, 0xe0, 0xa8, 0x75, 0xdc, 0x3a, 0xed, 0x86,
-	0x6b, 0xfd, 0xed, 0x29, 0x6e, 0x4c, 0x29, 0xf5, 0xfb, 0x68, 0xd3, 0x79, 0x5e, 0x07, 0xf7, 0xb0,
-	0x53, 0x73, 0x8c, 0x92, 0xc2, 0x20, 0x19, 0x40, 0xdb, 0xca, 0x39, 0x8a, 0x9a, 0xe2, 0x04, 0x99,
-	0x00, 0xe0, 0x52, 0x65, 0x1a, 0x4d, 0xc4, 0x6d, 0x05, 0xd9, 0x0a, 0xbb, 0x75, 0x65, 0x66, 0x03,
-	0x84, 0xe1, 0x5d, 0xca, 0x45, 0x82, 0x4f, 0x35, 0xd7, 0xc7, 0x3a, 0x83, 0xfd, 0x38, 0xd7, 0x1a,
-	0x85, 0x8d, 0xd6, 0x11, 0x1c, 0x78, 0xaf, 0xae, 0xfb, 0x1f, 0xe4, 0x04, 0xfa, 0x02, 0xcb, 0xa8,
-	0x91, 0xb4, 0x27, 0xb0, 0xf4, 0x4f, 0x82, 0x11, 0x1c, 0x36, 0xdb, 0xb8, 0xd4, 0x57, 0x9f, 0x2d,
-	0xe8, 0xcd, 0x72, 0x9b, 0xbe, 0xa0, 0x2e, 0xb2, 0x18, 0xc9, 0x0d, 0xb4, 0xab, 0xb1, 0xc8, 0x80,
-	0xba, 0x85, 0xd1, 0x9f, 0xdb, 0x1a, 0x0f, 0x1b, 0x55, 0x47, 0x09, 0x36, 0xc8, 0x33, 0xec, 0xfe,
-	0xee, 0x40, 0x26, 0xfe, 0xe9, 0x9f, 0x03, 0x8e, 0x8f, 0xfe, 0xb3, 0x3d, 0xf2, 0xf6, 0xe2, 0xf5,
-	0x3c, 0x91, 0x54, 0xea, 0x25, 0x5d, 0x20, 0xe3, 0x4a, 0x19, 0x96, 0xae, 0x14, 0xea, 0x4b, 0xb3,
-	0x12, 0x31, 0x53, 0xf3, 0x84, 0x55, 0x87, 0x65, 0xee, 0xd4, 0x6f, 0x9d, 0x4a, 0x5d, 0x7f, 0x05,
-	0x00, 0x00, 0xff, 0xff, 0x30, 0x15, 0xa2, 0xac, 0xfb, 0x01, 0x00, 0x00,
+	// 533 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x54, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0x26, 0x34, 0x89, 0xe8, 0x34, 0x6d, 0xc3, 0x92, 0x46, 0x91, 0x93, 0x46, 0xe0, 0xf6, 0x00,
+	0x28, 0xd8, 0x2a, 0x45, 0x48, 0x70, 0x2b, 0x15, 0x20, 0x01, 0x07, 0x08, 0x08, 0x24, 0x2e, 0x91,
+	0xeb, 0x4e, 0x63, 0xab, 0x64, 0x77, 0xd9, 0x5d, 0x27, 0xed, 0x93, 0x70, 0xe3, 0x31, 0x78, 0x3e,
+	0xc4, 0x7a, 0xd7, 0x71, 0x1c, 0x27, 0x82, 0x1b, 0x37, 0xcf, 0xdf, 0x37, 0x33, 0xdf, 0x7c, 0x6b,
+	0xb8, 0x1d, 0xf0, 0xd8, 0x9f, 0x1e, 0xf9, 0x41, 0xa2, 0x22, 0x8f, 0x0b, 0xa6, 0x18, 0xa9, 0x07,
+	0x3c, 0xf6, 0xa6, 0x47, 0xee, 0x2b, 0x68, 0xbc, 0x63, 0xe3, 0x98, 0x0e, 0xf1, 0x7b, 0x82, 0x52,
+	0x11, 0x07, 0x6e, 0x25, 0x12, 0x05, 0x0d, 0x26, 0xd8, 0xa9, 0xdc, 0xad, 0xdc, 0xdf, 0x1c, 0x66,
+	0xf6, 0x9f, 0x18, 0x0f, 0xa4, 0x9c, 0x31, 0x71, 0xde, 0xb9, 0x99, 0xc6, 0xac, 0xed, 0xfe, 0xa8,
+	0xc0, 0xb6, 0x01, 0x92, 0x9c, 0x51, 0x89, 0xa4, 0x05, 0x35, 0xc5, 0x2e, 0x91, 0x1a, 0x98, 0xd4,
+	0x20, 0xfb, 0x00, 0x78, 0xc5, 0x63, 0x81, 0x72, 0x14, 0x28, 0x8d, 0xb2, 0x31, 0xdc, 0x34, 0x9e,
+	0x13, 0x45, 0x0e, 0x60, 0x5b, 0xe0, 0x85, 0x40, 0x19, 0x8d, 0xd2, 0xe2, 0x0d, 0x5d, 0xdc, 0x30,
+	0xce, 0x4f, 0x1a, 0x63, 0x00, 0xc4, 0x26, 0xe5, 0xb0, 0xaa, 0x1a, 0xab, 0x69, 0x22, 0x2f, 0x2d,
+	0xa4, 0x8b, 0xb0, 0x77, 0x1a, 0x05, 0x74, 0x8c, 0xef, 0xcd, 0xac, 0x76, 0xd5, 0x07, 0xd0, 0x0c,
+	0x13, 0x21, 0x90, 0xaa, 0x51, 0xb6, 0x56, 0x3a, 0xeb, 0xae, 0xf1, 0xdb, 0x0a, 0x72, 0x0f, 0x1a,
+	0x14, 0x67, 0xa3, 0xc2, 0xf6, 0x5b, 0x14, 0x67, 0x36, 0xc5, 0xed, 0x40, 0xbb, 0xd8, 0x26, 0x25,
+	0xc2, 0x7d, 0x0e, 0x77, 0x86, 0xb9, 0xf1, 0x6d, 0xfb, 0xa5, 0x55, 0x2b, 0xcb, 0xab, 0xba, 0x3f,
+	0x2b, 0xd0, 0x5a, 0x2c, 0xfe, 0xcf, 0xd8, 0x7d, 0xa2, 0xcf, 0xce, 0x12, 0xf5, 0x4f, 0x6b, 0x35,
+	0x61, 0xc7, 0x56, 0x19, 0x92, 0x06, 0xd0, 0xd6, 0xf2, 0xf9, 0x12, 0xab, 0xe8, 0x75, 0xac, 0xa2,
+	0xe4, 0xcc, 0x02, 0x12, 0xa8, 0x86, 0xec, 0xdc, 0xaa, 0x51, 0x7f, 0xbb, 0xc7, 0xd0, 0x35, 0xe1,
+	0x1c, 0xdb, 0x98, 0xcd, 0xd0, 0x82, 0x1a, 0x4e, 0x82, 0xf8, 0x9b, 0x25, 0x47, 0x1b, 0x6e, 0x1f,
+	0x7a, 0xe5, 0x45, 0x66, 0x84, 0xcf, 0xd0, 0x3d, 0x65, 0xf4, 0x22, 0x16, 0x93, 0x55, 0xa0, 0x25,
+	0x8c, 0xff, 0x85, 0x32, 0xfa, 0xd0, 0x2b, 0xc7, 0x4d, 0xfb, 0x3e, 0xfe, 0x55, 0x85, 0xad, 0x93,
+	0x44, 0x45, 0x1f, 0x51, 0x4c, 0xe3, 0x10, 0xc9, 0x53, 0xa8, 0x69, 0x2a, 0x48, 0xcb, 0x4b, 0x1f,
+	0xa9, 0x97, 0x7f, 0xa1, 0xce, 0x5e, 0xc1, 0x6b, 0xa6, 0xbf, 0x41, 0x3e, 0xc0, 0xce, 0xa2, 0x02,
+	0xc9, 0xbe, 0x4d, 0x2d, 0x7d, 0x00, 0x4e, 0x7f, 0x55, 0x38, 0x83, 0x7c, 0x0b, 0x8d, 0xbc, 0xfa,
+	0x48, 0xd7, 0x56, 0x94, 0x08, 0xda, 0xe9, 0x95, 0x07, 0x33, 0xb0, 0x67, 0x50, 0x4f, 0x8f, 0x4e,
+	0xf2, 0x2b, 0xcc, 0xa5, 0xe3, 0xb4, 0x8b, 0xee, 0xac, 0xf4, 0x0d, 0xec, 0x16, 0xd4, 0x41, 0xfa,
+	0x0b, 0x34, 0x2c, 0xc9, 0x66, 0x35, 0x4d, 0x21, 0xb4, 0x4c, 0xce, 0xc2, 0x39, 0xc8, 0xc1, 0x7c,
+	0xfc, 0x95, 0xca, 0x72, 0x0e, 0xd7, 0x27, 0xe5, 0x9b, 0x94, 0xdd, 0x7c, 0xde, 0x64, 0x8d, 0xd2,
+	0x9c, 0xc3, 0xf5, 0x49, 0xb6, 0xc9, 0x8b, 0xc1, 0xd7, 0x87, 0x63, 0xe6, 0x31, 0x71, 0xe5, 0x4d,
+	0xd0, 0x0f, 0x38, 0x97, 0x7e, 0x74, 0xcd, 0x51, 0x3c, 0x92, 0xd7, 0x34, 0xf4, 0xf9, 0xe5, 0xd8,
+	0xd7, 0xbf, 0x7a, 0x3f, 0xfd, 0xf9, 0x9f, 0xd5, 0xb5, 0x75, 0xfc, 0x7b, 0x00, 0xe2, 0x07, 0x4c,
+	0xa9, 0x0d, 0x06, 0x00, 0x00,
 }